@@ -0,0 +1,240 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// ganttDateTokens maps dateFormat tokens to Go time layout elements, longest
+// first so that "YYYY" is matched before "YY".
+var ganttDateTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"YY", "06"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"DD", "02"},
+	{"D", "2"},
+	{"HH", "15"},
+	{"mm", "04"},
+	{"ss", "05"},
+	{"SSS", "000"},
+}
+
+// GanttDateLayout converts a Gantt dateFormat into a Go time layout. It
+// reports false for formats using tokens it does not understand, such as
+// Unix timestamps, whose dates cannot be parsed with time.Parse.
+func GanttDateLayout(format string) (string, bool) {
+	if format == "" {
+		format = "YYYY-MM-DD"
+	}
+
+	var layout strings.Builder
+	for rest := format; rest != ""; {
+		matched := false
+		for _, t := range ganttDateTokens {
+			if strings.HasPrefix(rest, t.token) {
+				layout.WriteString(t.layout)
+				rest = rest[len(t.token):]
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if c := rest[0]; (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return "", false
+		}
+		layout.WriteByte(rest[0])
+		rest = rest[1:]
+	}
+	return layout.String(), true
+}
+
+// ganttDurationRegex matches task durations such as "5d", "1.5h" or "2w".
+var ganttDurationRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)(ms|s|m|h|d|w)$`)
+
+// ganttDurationUnits maps duration suffixes to their length.
+var ganttDurationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// GanttSpan is the time covered by a task, section or chart. Start and End are
+// zero when it could not be worked out.
+type GanttSpan struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the span, reporting false when it is unknown.
+func (s GanttSpan) Duration() (time.Duration, bool) {
+	if s.Start.IsZero() || s.End.IsZero() {
+		return 0, false
+	}
+	return s.End.Sub(s.Start), true
+}
+
+// GanttSummary is an overview of a Gantt chart, for progress tables rendered
+// next to it.
+type GanttSummary struct {
+	Sections   []GanttSectionSummary
+	Milestones []GanttMilestone
+	GanttSpan  // Span of every task in the chart
+}
+
+// GanttSectionSummary counts the tasks of one section by status.
+type GanttSectionSummary struct {
+	Name      string
+	Tasks     int // Number of tasks, milestones included
+	Done      int
+	Active    int
+	Critical  int
+	Pos       ast.Position
+	GanttSpan // Span of the section's tasks
+}
+
+// GanttMilestone is a task marked as a milestone.
+type GanttMilestone struct {
+	Name    string
+	ID      string
+	Section string
+	Date    time.Time // Start of the milestone, or zero when unknown
+	Pos     ast.Position
+}
+
+// SummariseGantt returns the task counts, milestones and spans of diagram.
+// Spans are worked out from explicit dates, durations, "after" dependencies
+// and tasks following on from the previous one, in calendar time: excluded
+// days are not skipped. A section's span, or the chart's, is unknown when any
+// of its tasks' dates cannot be worked out, such as when the dateFormat is
+// not understood or the first task has no start date.
+func SummariseGantt(diagram *ast.GanttDiagram) GanttSummary {
+	spans := ganttTaskSpans(diagram)
+	summary := GanttSummary{GanttSpan: coveringSpan(spans)}
+
+	i := 0
+	for _, section := range diagram.Sections {
+		sectionSummary := GanttSectionSummary{
+			Name:      section.Name,
+			Tasks:     len(section.Tasks),
+			Pos:       section.Pos,
+			GanttSpan: coveringSpan(spans[i : i+len(section.Tasks)]),
+		}
+		for _, task := range section.Tasks {
+			switch task.Status {
+			case "done":
+				sectionSummary.Done++
+			case "active":
+				sectionSummary.Active++
+			case "crit":
+				sectionSummary.Critical++
+			case "milestone":
+				summary.Milestones = append(summary.Milestones, GanttMilestone{
+					Name:    task.Name,
+					ID:      task.ID,
+					Section: section.Name,
+					Date:    spans[i].Start,
+					Pos:     task.Pos,
+				})
+			}
+			i++
+		}
+		summary.Sections = append(summary.Sections, sectionSummary)
+	}
+	return summary
+}
+
+// ganttTaskSpans returns the span of every task of diagram, in source order.
+func ganttTaskSpans(diagram *ast.GanttDiagram) []GanttSpan {
+	var spans []GanttSpan
+	layout, ok := GanttDateLayout(diagram.DateFormat)
+	ends := make(map[string]time.Time)
+	var previous time.Time
+
+	for _, section := range diagram.Sections {
+		for _, task := range section.Tasks {
+			var span GanttSpan
+			if ok {
+				span = ganttTaskSpan(task, layout, previous, ends)
+			}
+			spans = append(spans, span)
+			previous = span.End
+			if task.ID != "" {
+				ends[task.ID] = span.End
+			}
+		}
+	}
+	return spans
+}
+
+// ganttTaskSpan works out when task starts and ends. previous is the end of
+// the task before it and ends maps task IDs to their ends, either zero when
+// unknown.
+func ganttTaskSpan(task ast.GanttTask, layout string, previous time.Time, ends map[string]time.Time) GanttSpan {
+	var start time.Time
+	switch {
+	case task.StartDate == "":
+		start = previous
+	case len(task.Dependencies) > 0:
+		for _, dep := range task.Dependencies {
+			end := ends[dep]
+			if end.IsZero() {
+				return GanttSpan{}
+			}
+			if end.After(start) {
+				start = end
+			}
+		}
+	default:
+		date, err := time.Parse(layout, task.StartDate)
+		if err != nil {
+			return GanttSpan{}
+		}
+		start = date
+	}
+	if start.IsZero() {
+		return GanttSpan{}
+	}
+
+	if end, err := time.Parse(layout, task.EndDate); err == nil {
+		return GanttSpan{Start: start, End: end}
+	}
+	if matches := ganttDurationRegex.FindStringSubmatch(strings.TrimSpace(task.EndDate)); matches != nil {
+		amount, _ := strconv.ParseFloat(matches[1], 64)
+		length := time.Duration(amount * float64(ganttDurationUnits[matches[2]]))
+		return GanttSpan{Start: start, End: start.Add(length)}
+	}
+	return GanttSpan{Start: start}
+}
+
+// coveringSpan returns the span from the earliest start to the latest end of
+// spans, or an unknown span if any of them is unknown or there are none.
+func coveringSpan(spans []GanttSpan) GanttSpan {
+	var covering GanttSpan
+	for _, span := range spans {
+		if _, ok := span.Duration(); !ok {
+			return GanttSpan{}
+		}
+		if covering.Start.IsZero() || span.Start.Before(covering.Start) {
+			covering.Start = span.Start
+		}
+		if span.End.After(covering.End) {
+			covering.End = span.End
+		}
+	}
+	return covering
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import "github.com/sammcj/mermaid-check/ast"
+
+// JourneySummary holds the average scores of a user journey, for dashboards
+// built from journey diagrams.
+type JourneySummary struct {
+	Sections []JourneyScore // One per section, in source order
+	Actors   []JourneyScore // One per actor, in order of first appearance
+	Lowest   []ast.Task     // Tasks with the lowest score, in source order
+}
+
+// JourneyScore is the average score of the tasks in a section or involving an
+// actor.
+type JourneyScore struct {
+	Name    string  // Section or actor name
+	Tasks   int     // Number of tasks averaged
+	Average float64 // Mean task score, or 0 when there are no tasks
+}
+
+// JourneyScores returns the average score of every section and actor of
+// diagram, and the tasks sharing the lowest score.
+func JourneyScores(diagram *ast.JourneyDiagram) JourneySummary {
+	var summary JourneySummary
+	actorIndex := make(map[string]int)
+	actorTotals := make([]int, 0, len(diagram.Actors))
+
+	for _, section := range diagram.Sections {
+		total := 0
+		for _, task := range section.Tasks {
+			total += task.Score
+			summary.Lowest = lowestTasks(summary.Lowest, task)
+			for _, actor := range task.Actors {
+				i, ok := actorIndex[actor]
+				if !ok {
+					i = len(summary.Actors)
+					actorIndex[actor] = i
+					summary.Actors = append(summary.Actors, JourneyScore{Name: actor})
+					actorTotals = append(actorTotals, 0)
+				}
+				summary.Actors[i].Tasks++
+				actorTotals[i] += task.Score
+			}
+		}
+		summary.Sections = append(summary.Sections, JourneyScore{
+			Name:    section.Name,
+			Tasks:   len(section.Tasks),
+			Average: average(total, len(section.Tasks)),
+		})
+	}
+
+	for i := range summary.Actors {
+		summary.Actors[i].Average = average(actorTotals[i], summary.Actors[i].Tasks)
+	}
+	return summary
+}
+
+// lowestTasks adds task to lowest, the tasks sharing the lowest score so far,
+// replacing them if task scores lower.
+func lowestTasks(lowest []ast.Task, task ast.Task) []ast.Task {
+	switch {
+	case len(lowest) == 0 || task.Score < lowest[0].Score:
+		return []ast.Task{task}
+	case task.Score == lowest[0].Score:
+		return append(lowest, task)
+	default:
+		return lowest
+	}
+}
+
+func average(total, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
@@ -0,0 +1,273 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// SymbolKind is the kind of thing a Symbol stands for.
+type SymbolKind string
+
+// Symbol kinds reported by Outline.
+const (
+	SymbolSubgraph  SymbolKind = "subgraph"
+	SymbolNode      SymbolKind = "node"
+	SymbolSection   SymbolKind = "section"
+	SymbolTask      SymbolKind = "task"
+	SymbolBoundary  SymbolKind = "boundary"
+	SymbolElement   SymbolKind = "element"
+	SymbolClass     SymbolKind = "class"
+	SymbolMember    SymbolKind = "member"
+	SymbolState     SymbolKind = "state"
+	SymbolEntity    SymbolKind = "entity"
+	SymbolAttribute SymbolKind = "attribute"
+)
+
+// Symbol is an entry in a diagram's outline, such as a subgraph with the
+// nodes drawn in it.
+type Symbol struct {
+	Name     string       // ID, or the name or title where there is no ID
+	Detail   string       // Label, type or description shown alongside Name, if any
+	Kind     SymbolKind   // What the symbol stands for
+	Pos      ast.Position // Where the symbol is declared, or first mentioned
+	Children []Symbol     // Symbols nested inside this one, in source order
+}
+
+// Outline returns the symbols of diagram as a tree, for editor outlines and
+// tables of contents: subgraphs and their nodes in flowcharts, sections and
+// their tasks in Gantt charts and user journeys, boundaries and their elements
+// in C4 diagrams, classes and their members, composite states and the states
+// inside them, and entities and their attributes. It returns nil for other
+// diagram types.
+func Outline(diagram ast.Diagram) []Symbol {
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		return flowchartOutline(d)
+	case *ast.GanttDiagram:
+		return ganttOutline(d)
+	case *ast.JourneyDiagram:
+		return journeyOutline(d)
+	case *ast.C4Diagram:
+		return append(c4Elements(d.Elements), c4Boundaries(d.Boundaries)...)
+	case *ast.ClassDiagram:
+		return classOutline(d)
+	case *ast.StateDiagram:
+		return stateOutline(d.Statements)
+	case *ast.ERDiagram:
+		return erOutline(d)
+	}
+	return nil
+}
+
+// flowchartOutline places every node in the innermost subgraph Mermaid draws
+// it in (see SubgraphOf), at its first mention.
+func flowchartOutline(flowchart *ast.Flowchart) []Symbol {
+	labels := make(map[string]string)
+	collectLabels(flowchart.Statements, labels)
+
+	membership := SubgraphOf(flowchart)
+	nodes := make(map[string][]Symbol) // Nodes by innermost subgraph, "" for none
+	seen := make(map[string]bool)
+	for _, mention := range Mentions(flowchart) {
+		if seen[mention.Node] {
+			continue
+		}
+		seen[mention.Node] = true
+		path := membership[mention.Node]
+		parent := ""
+		if len(path) > 0 {
+			parent = path[len(path)-1]
+		}
+		nodes[parent] = append(nodes[parent], Symbol{
+			Name:   mention.Node,
+			Detail: labels[mention.Node],
+			Kind:   SymbolNode,
+			Pos:    mention.Pos,
+		})
+	}
+	return subgraphSymbols(flowchart.Statements, nodes[""], nodes)
+}
+
+// subgraphSymbols returns the subgraphs declared in statements, each with its
+// nodes and nested subgraphs, merged with nodes in source order.
+func subgraphSymbols(statements []ast.Statement, nodes []Symbol, nodesBySubgraph map[string][]Symbol) []Symbol {
+	symbols := slices.Clone(nodes)
+	for _, stmt := range statements {
+		subgraph, ok := stmt.(*ast.Subgraph)
+		if !ok {
+			continue
+		}
+		key := subgraphKey(subgraph)
+		detail := ""
+		if subgraph.Title != key {
+			detail = subgraph.Title
+		}
+		symbols = append(symbols, Symbol{
+			Name:     key,
+			Detail:   detail,
+			Kind:     SymbolSubgraph,
+			Pos:      subgraph.Pos,
+			Children: subgraphSymbols(subgraph.Statements, nodesBySubgraph[key], nodesBySubgraph),
+		})
+	}
+	return sortSymbols(symbols)
+}
+
+// collectLabels records the label of every node defined in statements.
+func collectLabels(statements []ast.Statement, labels map[string]string) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			if s.Label != "" {
+				labels[s.ID] = s.Label
+			}
+		case *ast.Subgraph:
+			collectLabels(s.Statements, labels)
+		}
+	}
+}
+
+func ganttOutline(gantt *ast.GanttDiagram) []Symbol {
+	var symbols []Symbol
+	for _, section := range gantt.Sections {
+		var tasks []Symbol
+		for _, task := range section.Tasks {
+			tasks = append(tasks, Symbol{Name: task.Name, Detail: task.ID, Kind: SymbolTask, Pos: task.Pos})
+		}
+		symbols = append(symbols, Symbol{Name: section.Name, Kind: SymbolSection, Pos: section.Pos, Children: tasks})
+	}
+	return symbols
+}
+
+func journeyOutline(journey *ast.JourneyDiagram) []Symbol {
+	var symbols []Symbol
+	for _, section := range journey.Sections {
+		var tasks []Symbol
+		for _, task := range section.Tasks {
+			tasks = append(tasks, Symbol{Name: task.Name, Kind: SymbolTask, Pos: task.Pos})
+		}
+		symbols = append(symbols, Symbol{Name: section.Name, Kind: SymbolSection, Pos: section.Pos, Children: tasks})
+	}
+	return symbols
+}
+
+func c4Elements(elements []ast.C4Element) []Symbol {
+	var symbols []Symbol
+	for _, element := range elements {
+		symbols = append(symbols, Symbol{Name: element.ID, Detail: element.Label, Kind: SymbolElement, Pos: element.Pos})
+	}
+	return symbols
+}
+
+func c4Boundaries(boundaries []ast.C4Boundary) []Symbol {
+	var symbols []Symbol
+	for _, boundary := range boundaries {
+		children := append(c4Elements(boundary.Elements), c4Boundaries(boundary.Boundaries)...)
+		symbols = append(symbols, Symbol{
+			Name:     boundary.ID,
+			Detail:   boundary.Label,
+			Kind:     SymbolBoundary,
+			Pos:      boundary.Pos,
+			Children: sortSymbols(children),
+		})
+	}
+	return symbols
+}
+
+// classOutline lists the declared classes with their members, and classes
+// only named by a relationship at the first relationship naming them.
+func classOutline(diagram *ast.ClassDiagram) []Symbol {
+	var symbols []Symbol
+	index := make(map[string]int)
+	add := func(name string, pos ast.Position) int {
+		if i, ok := index[name]; ok {
+			return i
+		}
+		index[name] = len(symbols)
+		symbols = append(symbols, Symbol{Name: name, Kind: SymbolClass, Pos: pos})
+		return len(symbols) - 1
+	}
+
+	for _, stmt := range diagram.Statements {
+		switch s := stmt.(type) {
+		case *ast.Class:
+			i := add(s.Name, s.Pos)
+			symbols[i].Detail = s.Stereotype
+			for _, member := range s.Members {
+				symbols[i].Children = append(symbols[i].Children, Symbol{
+					Name:   member.Name,
+					Detail: member.Type,
+					Kind:   SymbolMember,
+					Pos:    member.Pos,
+				})
+			}
+		case *ast.Relationship:
+			add(s.From, s.Pos)
+			add(s.To, s.Pos)
+		}
+	}
+	return symbols
+}
+
+// stateOutline lists the states declared in statements, with the states
+// inside composite ones, and states only named by transitions at the first
+// transition naming them.
+func stateOutline(statements []ast.StateStmt) []Symbol {
+	var symbols []Symbol
+	index := make(map[string]int)
+	add := func(id string, pos ast.Position) int {
+		if i, ok := index[id]; ok {
+			return i
+		}
+		index[id] = len(symbols)
+		symbols = append(symbols, Symbol{Name: id, Kind: SymbolState, Pos: pos})
+		return len(symbols) - 1
+	}
+
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.State:
+			i := add(s.ID, s.Pos)
+			if s.Description != "" {
+				symbols[i].Detail = s.Description
+			}
+			if s.IsComposite {
+				symbols[i].Children = append(symbols[i].Children, stateOutline(s.Nested)...)
+			}
+		case *ast.Transition:
+			for _, id := range []string{s.From, s.To} {
+				if id != "[*]" {
+					add(id, s.Pos)
+				}
+			}
+		case *ast.StartState:
+			add(s.To, s.Pos)
+		case *ast.EndState:
+			add(s.From, s.Pos)
+		}
+	}
+	return symbols
+}
+
+func erOutline(diagram *ast.ERDiagram) []Symbol {
+	var symbols []Symbol
+	for _, entity := range diagram.Entities {
+		var attributes []Symbol
+		for _, attribute := range entity.Attributes {
+			attributes = append(attributes, Symbol{Name: attribute.Name, Detail: attribute.Type, Kind: SymbolAttribute, Pos: attribute.Pos})
+		}
+		symbols = append(symbols, Symbol{Name: entity.Name, Detail: entity.Alias, Kind: SymbolEntity, Pos: entity.Pos, Children: attributes})
+	}
+	return symbols
+}
+
+// sortSymbols orders symbols by position, keeping the order of symbols on the
+// same line.
+func sortSymbols(symbols []Symbol) []Symbol {
+	slices.SortStableFunc(symbols, func(a, b Symbol) int {
+		return cmp.Or(cmp.Compare(a.Pos.Line, b.Pos.Line), cmp.Compare(a.Pos.Column, b.Pos.Column))
+	})
+	return symbols
+}
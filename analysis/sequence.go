@@ -0,0 +1,156 @@
+package analysis
+
+import "github.com/sammcj/mermaid-check/ast"
+
+// SequenceBlock is a block of a sequence diagram, or one branch of it, that
+// encloses a message.
+type SequenceBlock struct {
+	Kind  string       // "loop", "alt", "else", "opt", "par", "and", "critical", "option" or "break"
+	Label string       // Condition or description, if any
+	Pos   ast.Position // Position of the block statement
+}
+
+// Conditional reports whether the block is a branch of alt or opt, which may
+// not run at all.
+func (b SequenceBlock) Conditional() bool {
+	return b.Kind == "alt" || b.Kind == "else" || b.Kind == "opt"
+}
+
+// TracedMessage is a message in the trace of a participant.
+type TracedMessage struct {
+	Index    int             // Position of the message among all messages of the diagram, from 0
+	Message  *ast.Message    // The message itself
+	Sent     bool            // Whether the participant sends the message
+	Received bool            // Whether the participant receives it (both, for a message to itself)
+	Blocks   []SequenceBlock // Blocks enclosing the message, outermost first
+}
+
+// Conditional reports whether the message lies in an alt or opt branch.
+func (m TracedMessage) Conditional() bool {
+	for _, block := range m.Blocks {
+		if block.Conditional() {
+			return true
+		}
+	}
+	return false
+}
+
+// ParticipantTrace is the messages a participant sends or receives, in the
+// order they are exchanged.
+type ParticipantTrace struct {
+	Participant string       // Participant ID
+	Pos         ast.Position // Where the participant is declared, or first mentioned
+	Messages    []TracedMessage
+}
+
+// ConditionalOnly reports whether the participant has messages and all of
+// them lie in alt or opt branches, so its lifeline may see no interaction at
+// all when the diagram's scenario runs.
+func (t ParticipantTrace) ConditionalOnly() bool {
+	for _, message := range t.Messages {
+		if !message.Conditional() {
+			return false
+		}
+	}
+	return len(t.Messages) > 0
+}
+
+// SequenceTrace returns the message trace of every participant of diagram, in
+// the order participants are declared or first mentioned, for reviewing which
+// calls each service in an API interaction makes and receives.
+func SequenceTrace(diagram *ast.SequenceDiagram) []ParticipantTrace {
+	t := &sequenceTracer{index: make(map[string]int)}
+	t.statements(diagram.Statements, nil)
+	return t.traces
+}
+
+type sequenceTracer struct {
+	traces   []ParticipantTrace
+	index    map[string]int // Position of each participant in traces
+	messages int            // Messages seen so far
+}
+
+// participant returns the trace of the participant id, adding it at pos if it
+// is new.
+func (t *sequenceTracer) participant(id string, pos ast.Position) *ParticipantTrace {
+	i, ok := t.index[id]
+	if !ok {
+		i = len(t.traces)
+		t.index[id] = i
+		t.traces = append(t.traces, ParticipantTrace{Participant: id, Pos: pos})
+	}
+	return &t.traces[i]
+}
+
+func (t *sequenceTracer) statements(statements []ast.SeqStmt, blocks []SequenceBlock) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			t.participant(s.ID, s.Pos)
+		case *ast.Box:
+			for _, participant := range s.Participants {
+				t.participant(participant.ID, participant.Pos)
+			}
+		case *ast.Note:
+			for _, id := range s.Participants {
+				t.participant(id, s.Pos)
+			}
+		case *ast.Activation:
+			t.participant(s.Participant, s.Pos)
+		case *ast.Message:
+			t.message(s, blocks)
+		case *ast.Loop:
+			t.block(blocks, SequenceBlock{"loop", s.Label, s.Pos}, s.Statements)
+		case *ast.Opt:
+			t.block(blocks, SequenceBlock{"opt", s.Label, s.Pos}, s.Statements)
+		case *ast.Break:
+			t.block(blocks, SequenceBlock{"break", s.Label, s.Pos}, s.Statements)
+		case *ast.Alt:
+			for i, condition := range s.Conditions {
+				kind := "else"
+				if i == 0 {
+					kind = "alt"
+				}
+				t.block(blocks, SequenceBlock{kind, condition.Label, s.Pos}, condition.Statements)
+			}
+		case *ast.Par:
+			for i, branch := range s.Branches {
+				kind := "and"
+				if i == 0 {
+					kind = "par"
+				}
+				t.block(blocks, SequenceBlock{kind, branch.Label, s.Pos}, branch.Statements)
+			}
+		case *ast.Critical:
+			t.block(blocks, SequenceBlock{"critical", s.Label, s.Pos}, s.Statements)
+			for _, option := range s.Options {
+				t.block(blocks, SequenceBlock{"option", option.Label, s.Pos}, option.Statements)
+			}
+		}
+	}
+}
+
+// block traces statements inside block, itself inside blocks.
+func (t *sequenceTracer) block(blocks []SequenceBlock, block SequenceBlock, statements []ast.SeqStmt) {
+	t.statements(statements, append(blocks[:len(blocks):len(blocks)], block))
+}
+
+func (t *sequenceTracer) message(m *ast.Message, blocks []SequenceBlock) {
+	traced := TracedMessage{Index: t.messages, Message: m, Blocks: blocks}
+	t.messages++
+
+	from := t.participant(m.From, m.Pos)
+	if m.From == m.To {
+		traced.Sent, traced.Received = true, true
+		from.Messages = append(from.Messages, traced)
+		return
+	}
+	sent := traced
+	sent.Sent = true
+	from.Messages = append(from.Messages, sent)
+
+	received := traced
+	received.Received = true
+	to := t.participant(m.To, m.Pos)
+	to.Messages = append(to.Messages, received)
+}
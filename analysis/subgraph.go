@@ -0,0 +1,117 @@
+// Package analysis derives structural facts from parsed diagrams that the AST
+// does not record directly, for rules and tools built on mermaid-check.
+package analysis
+
+import "github.com/sammcj/mermaid-check/ast"
+
+// NodeMention is one place a flowchart node is mentioned, by a node definition
+// or as a link endpoint.
+type NodeMention struct {
+	Node      string       // Node ID
+	Subgraphs []string     // Subgraphs enclosing the mention, outermost first
+	Pos       ast.Position // Position of the statement mentioning the node
+}
+
+// Mentions returns every mention of a node in flowchart, in source order.
+// Subgraphs without an ID are identified by their title, and link endpoints
+// naming a subgraph are not node mentions.
+func Mentions(flowchart *ast.Flowchart) []NodeMention {
+	subgraphs := make(map[string]bool)
+	collectSubgraphs(flowchart.Statements, subgraphs)
+
+	var mentions []NodeMention
+	collectMentions(flowchart.Statements, nil, subgraphs, &mentions)
+	return mentions
+}
+
+// SubgraphOf returns, for every node of flowchart, the IDs of the subgraphs it
+// belongs to, outermost first, or nil for a node outside every subgraph. A
+// node mentioned in several subgraphs belongs to the first of them to end, as
+// Mermaid draws it: an inner subgraph before the one enclosing it, and an
+// earlier subgraph before a later sibling.
+func SubgraphOf(flowchart *ast.Flowchart) map[string][]string {
+	ends := make(map[string]int)
+	numberEnds(flowchart.Statements, ends)
+	membership := make(map[string][]string)
+	for _, mention := range Mentions(flowchart) {
+		current, seen := membership[mention.Node]
+		if !seen || claims(mention.Subgraphs, current, ends) {
+			membership[mention.Node] = mention.Subgraphs
+		}
+	}
+	return membership
+}
+
+// Nested reports whether one of the subgraph paths a and b lies within the
+// other, as returned by Mentions and SubgraphOf. A node mentioned in nested
+// subgraphs is drawn where both mentions expect it.
+func Nested(a, b []string) bool {
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// claims reports whether a node mentioned inside the subgraphs in path belongs
+// there rather than inside current, the subgraphs of an earlier mention: when
+// path is inside a subgraph and current isn't, or path's innermost subgraph
+// ends first. ends numbers the subgraphs in the order they end.
+func claims(path, current []string, ends map[string]int) bool {
+	if len(path) == 0 {
+		return false
+	}
+	if len(current) == 0 {
+		return true
+	}
+	return ends[path[len(path)-1]] < ends[current[len(current)-1]]
+}
+
+// subgraphKey identifies a subgraph by its ID, or its title if it has none.
+func subgraphKey(subgraph *ast.Subgraph) string {
+	if subgraph.ID != "" {
+		return subgraph.ID
+	}
+	return subgraph.Title
+}
+
+func collectSubgraphs(statements []ast.Statement, subgraphs map[string]bool) {
+	for _, stmt := range statements {
+		if s, ok := stmt.(*ast.Subgraph); ok {
+			subgraphs[subgraphKey(s)] = true
+			collectSubgraphs(s.Statements, subgraphs)
+		}
+	}
+}
+
+func collectMentions(statements []ast.Statement, path []string, subgraphs map[string]bool, mentions *[]NodeMention) {
+	mention := func(id string, pos ast.Position) {
+		if id != "" && !subgraphs[id] {
+			*mentions = append(*mentions, NodeMention{Node: id, Subgraphs: path, Pos: pos})
+		}
+	}
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			mention(s.ID, s.Pos)
+		case *ast.Link:
+			mention(s.From, s.Pos)
+			mention(s.To, s.Pos)
+		case *ast.Subgraph:
+			inner := append(path[:len(path):len(path)], subgraphKey(s))
+			collectMentions(s.Statements, inner, subgraphs, mentions)
+		}
+	}
+}
+
+// numberEnds numbers the subgraphs in statements in the order they end, an
+// inner subgraph before the subgraph enclosing it.
+func numberEnds(statements []ast.Statement, ends map[string]int) {
+	for _, stmt := range statements {
+		if s, ok := stmt.(*ast.Subgraph); ok {
+			numberEnds(s.Statements, ends)
+			ends[subgraphKey(s)] = len(ends)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package analysis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func parseGantt(t *testing.T, source string) *ast.GanttDiagram {
+	t.Helper()
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return diagram.(*ast.GanttDiagram)
+}
+
+func date(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestSummariseGantt(t *testing.T) {
+	gantt := parseGantt(t, "gantt\n"+
+		"    dateFormat YYYY-MM-DD\n"+
+		"    section Design\n"+
+		"    Research   :done, research, 2024-01-01, 5d\n"+
+		"    Mock-ups   :active, mockups, after research, 1w\n"+
+		"    Sign-off   :milestone, signoff, after mockups, 0d\n"+
+		"    section Build\n"+
+		"    Backend    :crit, api, after signoff, 2024-01-20\n"+
+		"    Frontend   :3d\n")
+
+	summary := analysis.SummariseGantt(gantt)
+
+	if len(summary.Sections) != 2 {
+		t.Fatalf("got %d sections, want 2", len(summary.Sections))
+	}
+	design, build := summary.Sections[0], summary.Sections[1]
+	if design.Tasks != 3 || design.Done != 1 || design.Active != 1 || design.Critical != 0 {
+		t.Errorf("Design counts = %+v", design)
+	}
+	if build.Tasks != 2 || build.Critical != 1 {
+		t.Errorf("Build counts = %+v", build)
+	}
+
+	if d, ok := design.Duration(); !ok || d != 12*24*time.Hour {
+		t.Errorf("Design duration = %v, %v, want 288h", d, ok)
+	}
+	if !build.Start.Equal(date(t, "2024-01-13")) || !build.End.Equal(date(t, "2024-01-23")) {
+		t.Errorf("Build span = %v to %v", build.Start, build.End)
+	}
+	if d, ok := summary.Duration(); !ok || d != 22*24*time.Hour {
+		t.Errorf("chart duration = %v, %v, want 528h", d, ok)
+	}
+
+	if len(summary.Milestones) != 1 {
+		t.Fatalf("got %d milestones, want 1", len(summary.Milestones))
+	}
+	milestone := summary.Milestones[0]
+	if milestone.ID != "signoff" || milestone.Section != "Design" || !milestone.Date.Equal(date(t, "2024-01-13")) {
+		t.Errorf("milestone = %+v", milestone)
+	}
+}
+
+func TestSummariseGanttUnknownDates(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "unknown dependency",
+			source: "gantt\n    section A\n    Task :t1, after missing, 5d\n",
+		},
+		{
+			name:   "first task without start",
+			source: "gantt\n    section A\n    Task :5d\n",
+		},
+		{
+			name:   "unsupported dateFormat",
+			source: "gantt\n    dateFormat X\n    section A\n    Task :t1, 1704067200, 5d\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := analysis.SummariseGantt(parseGantt(t, tt.source))
+			if _, ok := summary.Duration(); ok {
+				t.Errorf("chart duration known, want unknown")
+			}
+			if summary.Sections[0].Tasks != 1 {
+				t.Errorf("got %d tasks, want 1", summary.Sections[0].Tasks)
+			}
+		})
+	}
+}
+
+func TestGanttDateLayout(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+		ok     bool
+	}{
+		{"", "2006-01-02", true},
+		{"DD/MM/YYYY HH:mm", "02/01/2006 15:04", true},
+		{"X", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := analysis.GanttDateLayout(tt.format)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("GanttDateLayout(%q) = %q, %v, want %q, %v", tt.format, got, ok, tt.want, tt.ok)
+		}
+	}
+}
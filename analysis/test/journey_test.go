@@ -0,0 +1,51 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestJourneyScores(t *testing.T) {
+	diagram, err := parser.Parse("journey\n" +
+		"    title Shopping\n" +
+		"    section Browse\n" +
+		"      Search: 4: Shopper\n" +
+		"      Compare: 2: Shopper, Friend\n" +
+		"    section Checkout\n" +
+		"      Pay: 2: Shopper\n" +
+		"      Ship: 5: Warehouse\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	summary := analysis.JourneyScores(diagram.(*ast.JourneyDiagram))
+
+	wantSections := []analysis.JourneyScore{
+		{Name: "Browse", Tasks: 2, Average: 3},
+		{Name: "Checkout", Tasks: 2, Average: 3.5},
+	}
+	if !reflect.DeepEqual(summary.Sections, wantSections) {
+		t.Errorf("Sections = %+v, want %+v", summary.Sections, wantSections)
+	}
+
+	wantActors := []analysis.JourneyScore{
+		{Name: "Shopper", Tasks: 3, Average: 8.0 / 3},
+		{Name: "Friend", Tasks: 1, Average: 2},
+		{Name: "Warehouse", Tasks: 1, Average: 5},
+	}
+	if !reflect.DeepEqual(summary.Actors, wantActors) {
+		t.Errorf("Actors = %+v, want %+v", summary.Actors, wantActors)
+	}
+
+	var lowest []string
+	for _, task := range summary.Lowest {
+		lowest = append(lowest, task.Name)
+	}
+	if want := []string{"Compare", "Pay"}; !reflect.DeepEqual(lowest, want) {
+		t.Errorf("Lowest = %v, want %v", lowest, want)
+	}
+}
@@ -0,0 +1,143 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+// outlineEntry flattens a symbol for comparison, ignoring positions.
+type outlineEntry struct {
+	Depth  int
+	Kind   analysis.SymbolKind
+	Name   string
+	Detail string
+	Line   int
+}
+
+func flatten(symbols []analysis.Symbol, depth int) []outlineEntry {
+	var entries []outlineEntry
+	for _, symbol := range symbols {
+		entries = append(entries, outlineEntry{depth, symbol.Kind, symbol.Name, symbol.Detail, symbol.Pos.Line})
+		entries = append(entries, flatten(symbol.Children, depth+1)...)
+	}
+	return entries
+}
+
+func TestOutline(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []outlineEntry
+	}{
+		{
+			name: "flowchart",
+			source: "flowchart TD\n" +
+				"    start[Start] --> api\n" +
+				"    subgraph backend[Backend]\n" +
+				"        api[API] --> db\n" +
+				"        subgraph storage\n" +
+				"            db[(Database)]\n" +
+				"        end\n" +
+				"    end\n" +
+				"    web --> backend\n",
+			want: []outlineEntry{
+				{0, analysis.SymbolNode, "start", "Start", 2},
+				{0, analysis.SymbolSubgraph, "backend", "Backend", 3},
+				{1, analysis.SymbolNode, "api", "API", 2},
+				{1, analysis.SymbolSubgraph, "storage", "", 5},
+				{2, analysis.SymbolNode, "db", "Database", 4},
+				{0, analysis.SymbolNode, "web", "", 9},
+			},
+		},
+		{
+			name: "gantt",
+			source: "gantt\n" +
+				"    section Design\n" +
+				"    Research :r1, 2024-01-01, 5d\n" +
+				"    section Build\n" +
+				"    Code :3d\n",
+			want: []outlineEntry{
+				{0, analysis.SymbolSection, "Design", "", 2},
+				{1, analysis.SymbolTask, "Research", "r1", 3},
+				{0, analysis.SymbolSection, "Build", "", 4},
+				{1, analysis.SymbolTask, "Code", "", 5},
+			},
+		},
+		{
+			name: "c4",
+			source: "C4Container\n" +
+				"    Person(user, \"User\")\n" +
+				"    System_Boundary(shop, \"Shop\") {\n" +
+				"        Container(web, \"Web App\", \"Go\")\n" +
+				"    }\n",
+			want: []outlineEntry{
+				{0, analysis.SymbolElement, "user", "User", 2},
+				{0, analysis.SymbolBoundary, "shop", "Shop", 3},
+				{1, analysis.SymbolElement, "web", "Web App", 4},
+			},
+		},
+		{
+			name: "state",
+			source: "stateDiagram-v2\n" +
+				"    [*] --> Idle\n" +
+				"    state \"Waiting\" as Idle\n" +
+				"    Idle --> Active\n" +
+				"    Active --> [*]\n",
+			want: []outlineEntry{
+				{0, analysis.SymbolState, "Idle", "Waiting", 2},
+				{0, analysis.SymbolState, "Active", "", 4},
+			},
+		},
+		{
+			name:   "pie",
+			source: "pie\n    \"A\" : 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := flatten(analysis.Outline(diagram), 0); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Outline() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineNested(t *testing.T) {
+	class := &ast.ClassDiagram{Statements: []ast.ClassStmt{
+		&ast.Class{Name: "Animal", Stereotype: "abstract", Pos: ast.Position{Line: 2, Column: 1}, Members: []ast.ClassMember{
+			{Name: "name", Type: "String", Pos: ast.Position{Line: 3, Column: 1}},
+		}},
+		&ast.Relationship{From: "Animal", To: "Dog", Pos: ast.Position{Line: 5, Column: 1}},
+	}}
+	want := []outlineEntry{
+		{0, analysis.SymbolClass, "Animal", "abstract", 2},
+		{1, analysis.SymbolMember, "name", "String", 3},
+		{0, analysis.SymbolClass, "Dog", "", 5},
+	}
+	if got := flatten(analysis.Outline(class), 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("class Outline() =\n%v\nwant\n%v", got, want)
+	}
+
+	state := &ast.StateDiagram{Statements: []ast.StateStmt{
+		&ast.State{ID: "Active", IsComposite: true, Pos: ast.Position{Line: 2, Column: 1}, Nested: []ast.StateStmt{
+			&ast.Transition{From: "Running", To: "Paused", Pos: ast.Position{Line: 3, Column: 1}},
+		}},
+	}}
+	want = []outlineEntry{
+		{0, analysis.SymbolState, "Active", "", 2},
+		{1, analysis.SymbolState, "Running", "", 3},
+		{1, analysis.SymbolState, "Paused", "", 3},
+	}
+	if got := flatten(analysis.Outline(state), 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("state Outline() =\n%v\nwant\n%v", got, want)
+	}
+}
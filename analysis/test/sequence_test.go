@@ -0,0 +1,102 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+// tracedEntry flattens a traced message for comparison.
+type tracedEntry struct {
+	Index          int
+	Text           string
+	Sent, Received bool
+	Blocks         string
+}
+
+func TestSequenceTrace(t *testing.T) {
+	diagram, err := parser.Parse(`sequenceDiagram
+    participant Client
+    participant API
+    Client->>API: GET /orders
+    API->>API: Check cache
+    alt cache miss
+        API->>DB: SELECT orders
+        DB-->>API: rows
+    else cache hit
+        loop each order
+            API->>Audit: record
+        end
+    end
+    API-->>Client: 200 OK`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	traces := analysis.SequenceTrace(diagram.(*ast.SequenceDiagram))
+	got := make(map[string][]tracedEntry)
+	var order []string
+	for _, trace := range traces {
+		order = append(order, trace.Participant)
+		for _, m := range trace.Messages {
+			blocks := ""
+			for _, block := range m.Blocks {
+				blocks += block.Kind + "(" + block.Label + ")"
+			}
+			got[trace.Participant] = append(got[trace.Participant], tracedEntry{m.Index, m.Message.Text, m.Sent, m.Received, blocks})
+		}
+	}
+
+	if want := []string{"Client", "API", "DB", "Audit"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("participants = %v, want %v", order, want)
+	}
+	want := map[string][]tracedEntry{
+		"Client": {
+			{0, "GET /orders", true, false, ""},
+			{5, "200 OK", false, true, ""},
+		},
+		"API": {
+			{0, "GET /orders", false, true, ""},
+			{1, "Check cache", true, true, ""},
+			{2, "SELECT orders", true, false, "alt(cache miss)"},
+			{3, "rows", false, true, "alt(cache miss)"},
+			{4, "record", true, false, "else(cache hit)loop(each order)"},
+			{5, "200 OK", true, false, ""},
+		},
+		"DB": {
+			{2, "SELECT orders", false, true, "alt(cache miss)"},
+			{3, "rows", true, false, "alt(cache miss)"},
+		},
+		"Audit": {
+			{4, "record", false, true, "else(cache hit)loop(each order)"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("traces = %+v, want %+v", got, want)
+	}
+
+	conditional := map[string]bool{}
+	for _, trace := range traces {
+		conditional[trace.Participant] = trace.ConditionalOnly()
+	}
+	if want := map[string]bool{"Client": false, "API": false, "DB": true, "Audit": true}; !reflect.DeepEqual(conditional, want) {
+		t.Errorf("ConditionalOnly = %v, want %v", conditional, want)
+	}
+}
+
+func TestSequenceTraceUnusedParticipant(t *testing.T) {
+	diagram, err := parser.Parse("sequenceDiagram\n    participant A\n    participant B\n    opt maybe\n        A->>A: Think\n    end")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	traces := analysis.SequenceTrace(diagram.(*ast.SequenceDiagram))
+	if len(traces) != 2 || traces[1].Participant != "B" || traces[1].Pos.Line != 3 {
+		t.Fatalf("traces = %+v, want A and B declared on lines 2 and 3", traces)
+	}
+	if !traces[0].ConditionalOnly() || traces[1].ConditionalOnly() {
+		t.Errorf("ConditionalOnly = %v, %v, want true for A only", traces[0].ConditionalOnly(), traces[1].ConditionalOnly())
+	}
+}
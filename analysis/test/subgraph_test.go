@@ -0,0 +1,84 @@
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func parseFlowchart(t *testing.T, source string) *ast.Flowchart {
+	t.Helper()
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return diagram.(*ast.Flowchart)
+}
+
+func TestSubgraphOf(t *testing.T) {
+	flowchart := parseFlowchart(t, "flowchart TD\n"+
+		"    start --> api\n"+
+		"    subgraph backend[Backend]\n"+
+		"        api --> db\n"+
+		"        subgraph storage\n"+
+		"            db\n"+
+		"        end\n"+
+		"    end\n"+
+		"    subgraph \"Clients\"\n"+
+		"        web\n"+
+		"    end\n"+
+		"    web --> backend\n")
+
+	want := map[string][]string{
+		"start": nil,
+		"api":   {"backend"},
+		"db":    {"backend", "storage"},
+		"web":   {"Clients"},
+	}
+	if got := analysis.SubgraphOf(flowchart); !reflect.DeepEqual(got, want) {
+		t.Errorf("SubgraphOf() = %v, want %v", got, want)
+	}
+}
+
+func TestSubgraphOf_FirstSubgraphToEnd(t *testing.T) {
+	flowchart := parseFlowchart(t, "flowchart TD\n"+
+		"    subgraph a\n        x\n    end\n"+
+		"    subgraph b\n        x\n    end\n")
+	if got := analysis.SubgraphOf(flowchart)["x"]; !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("SubgraphOf()[x] = %v, want [a]", got)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	flowchart := parseFlowchart(t, "flowchart TD\n    subgraph a\n        x --> y\n    end\n    y --> a\n")
+	var got []string
+	for _, mention := range analysis.Mentions(flowchart) {
+		got = append(got, mention.Node)
+		if mention.Node == "x" && (mention.Pos.Line != 3 || !reflect.DeepEqual(mention.Subgraphs, []string{"a"})) {
+			t.Errorf("mention of x = %+v, want line 3 in subgraph a", mention)
+		}
+	}
+	if want := []string{"x", "y", "y"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Mentions() nodes = %v, want %v", got, want)
+	}
+}
+
+func TestNested(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want bool
+	}{
+		{nil, []string{"a"}, true},
+		{[]string{"a"}, []string{"a", "b"}, true},
+		{[]string{"a", "b"}, []string{"a", "c"}, false},
+		{[]string{"a"}, []string{"b"}, false},
+	}
+	for _, tt := range tests {
+		if got := analysis.Nested(tt.a, tt.b); got != tt.want {
+			t.Errorf("Nested(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
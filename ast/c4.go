@@ -3,14 +3,16 @@ package ast
 // C4Diagram represents any C4 diagram (Context, Container, Component, Dynamic, Deployment).
 // All C4 diagram types share the same AST structure with common elements.
 type C4Diagram struct {
-	DiagramType   string             // "c4Context", "c4Container", "c4Component", "c4Dynamic", "c4Deployment"
-	Title         string             // Optional title
-	Elements      []C4Element        // All elements (Person, System, Container, Component, Node)
-	Boundaries    []C4Boundary       // Boundary elements (can be nested)
-	Relationships []C4Relationship   // All relationships (Rel, BiRel, etc.)
-	Styles        []C4Style          // Style overrides
-	Source        string             // Original source
-	Pos           Position           // Position in source
+	DiagramType   string           // "c4Context", "c4Container", "c4Component", "c4Dynamic", "c4Deployment"
+	Title         string           // Optional title
+	Elements      []C4Element      // All elements (Person, System, Container, Component, Node)
+	Boundaries    []C4Boundary     // Boundary elements (can be nested)
+	Relationships []C4Relationship // All relationships (Rel, BiRel, etc.)
+	Styles        []C4Style        // Style overrides
+	Layouts       []C4Layout       // Layout directives (UpdateLayoutConfig, LAYOUT_* macros)
+	Source        string           // Original source
+	Pos           Position         // Position in source
+	Annotations
 }
 
 // GetType implements the Diagram interface.
@@ -38,9 +40,9 @@ type C4Element struct {
 	Sprite      string   // Optional sprite
 	Tags        string   // Optional tags
 	Link        string   // Optional link
-	External    bool     // True for _Ext variants (Person_Ext, System_Ext)
-	Database    bool     // True for Db variants (ContainerDb, ComponentDb)
-	Queue       bool     // True for Queue variants (ContainerQueue, ComponentQueue)
+	External    bool     // True for _Ext variants (Person_Ext, System_Ext, Container_Ext, etc.)
+	Database    bool     // True for Db variants (SystemDb, ContainerDb, ComponentDb)
+	Queue       bool     // True for Queue variants (SystemQueue, ContainerQueue, ComponentQueue)
 	Pos         Position // Position in source
 }
 
@@ -57,7 +59,8 @@ type C4Boundary struct {
 
 // C4Relationship represents a relationship between elements.
 type C4Relationship struct {
-	RelType     string   // "Rel", "Rel_Back", "Rel_Neighbor", "Rel_Down", "Rel_Up", "Rel_Left", "Rel_Right", "BiRel"
+	RelType     string   // "Rel", "RelIndex", "Rel_Back", "Rel_Neighbor", "Rel_Down", "Rel_D", "Rel_Up", "Rel_U", "Rel_Left", "Rel_L", "Rel_Right", "Rel_R", "BiRel"
+	Index       string   // Explicit sequence index (RelIndex only)
 	From        string   // Source element ID
 	To          string   // Target element ID
 	Label       string   // Relationship label
@@ -86,3 +89,12 @@ type C4Style struct {
 	OffsetY     string   // Y offset (for relationships)
 	Pos         Position // Position in source
 }
+
+// C4Layout represents a layout directive: UpdateLayoutConfig or one of the
+// argument-less LAYOUT_* style macros.
+type C4Layout struct {
+	Directive     string   // "UpdateLayoutConfig", "LAYOUT_TOP_DOWN", "LAYOUT_WITH_LEGEND", etc.
+	ShapeInRow    string   // Shapes per row (UpdateLayoutConfig only)
+	BoundaryInRow string   // Boundaries per row (UpdateLayoutConfig only)
+	Pos           Position // Position in source
+}
@@ -6,6 +6,8 @@ type ClassDiagram struct {
 	Statements []ClassStmt // All statements in the diagram
 	Source     string      // Original source
 	Pos        Position    // Position in source
+	Annotations
+	Skips
 }
 
 // ClassStmt is the interface for all class diagram statements.
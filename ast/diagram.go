@@ -7,6 +7,8 @@ type Diagram interface {
 	GetType() string
 	// GetPosition returns the position in the source where this diagram starts.
 	GetPosition() Position
+	// GetMetadata returns metadata from `%% @key: value` comment annotations (nil if none).
+	GetMetadata() map[string]string
 }
 
 // Position represents a location in the source text.
@@ -14,3 +16,21 @@ type Position struct {
 	Line   int // Line number (1-indexed)
 	Column int // Column number (1-indexed)
 }
+
+// SkippedLine is a line a parser didn't recognise and left out of the AST,
+// so nothing on it was validated.
+type SkippedLine struct {
+	Line    int    // Line number (1-indexed, relative to the diagram)
+	Content string // The line, without surrounding whitespace
+	Reason  string // Why it was skipped
+}
+
+// Skips records the lines a parser skipped. It is embedded in the diagram
+// types whose parsers skip lines they don't recognise rather than failing:
+// flowcharts, class diagrams and state diagrams.
+type Skips struct {
+	SkippedLines []SkippedLine // Skipped lines, in source order
+}
+
+// GetSkippedLines returns the lines the parser skipped (nil if none).
+func (s *Skips) GetSkippedLines() []SkippedLine { return s.SkippedLines }
@@ -8,6 +8,7 @@ type ERDiagram struct {
 	Relationships []ERRelationship // Relationships between entities
 	Source        string           // Original source
 	Pos           Position         // Position in source
+	Annotations
 }
 
 // EREntity represents an entity in an ER diagram.
@@ -4,10 +4,12 @@ package ast
 // Flowchart represents a complete Mermaid flowchart or graph diagram.
 type Flowchart struct {
 	Type       string      // "flowchart" or "graph"
-	Direction  string      // TB, TD, BT, RL, LR
+	Direction  string      // TB, TD, BT, RL, LR, or empty for the default (TB)
 	Statements []Statement // All statements in the diagram
 	Source     string      // Original source
 	Pos        Position    // Position in source
+	Annotations
+	Skips
 }
 
 // GetType returns the diagram type.
@@ -27,10 +29,11 @@ type Statement interface {
 
 // NodeDef represents a node definition.
 type NodeDef struct {
-	ID    string   // Node identifier
-	Shape string   // Shape type (bracket style)
-	Label string   // Node label/text
-	Pos   Position
+	ID           string // Node identifier
+	Shape        string // Shape type (bracket style)
+	Label        string // Node label/text, as written
+	DisplayLabel string // Label as displayed, without its quotes and with entities decoded (see DecodeText)
+	Pos          Position
 }
 
 func (n *NodeDef) statement() {}
@@ -42,9 +45,10 @@ func (n *NodeDef) GetPosition() Position { return n.Pos }
 type Link struct {
 	From      string   // Source node ID
 	To        string   // Target node ID
-	Arrow     string   // Arrow type (-->, -.>, ==>, etc.)
+	Arrow     string   // Arrow type (-->, -.->, ==>, ~~~ for an invisible link, etc.)
 	Label     string   // Link label (optional)
 	BiDir     bool     // Bidirectional arrow
+	Length    int      // Ranks the link spans: 1 for -->, 2 for --->, and so on
 	Pos       Position
 }
 
@@ -57,6 +61,7 @@ func (l *Link) GetPosition() Position { return l.Pos }
 type Subgraph struct {
 	ID         string      // Subgraph id (empty for the quoted-title form, which has none)
 	Title      string      // Subgraph title
+	Direction  string      // Layout direction from a `direction` statement (empty if not set)
 	Statements []Statement // Nested statements
 	Pos        Position
 }
@@ -78,15 +83,39 @@ func (c *ClassDef) statement() {}
 // GetPosition returns the position of this class definition in the source.
 func (c *ClassDef) GetPosition() Position { return c.Pos }
 
+// Style represents a style statement applying CSS properties to a single node.
+// A statement styling several nodes, as in `style A,B fill:#f9f`, is parsed as
+// one Style per node, positioned at the node's ID.
+type Style struct {
+	NodeID string            // Node ID to style
+	Styles map[string]string // CSS properties
+	Pos    Position
+}
+
+func (s *Style) statement() {}
+
+// GetPosition returns the position of this style statement in the source.
+func (s *Style) GetPosition() Position { return s.Pos }
+
 // ClassAssignment represents assigning classes to nodes.
 type ClassAssignment struct {
-	NodeIDs   []string // Node IDs to apply class to
-	ClassName string   // Class name to apply
-	Pos       Position
+	NodeIDs       []string   // Node IDs to apply class to
+	NodePositions []Position // Position of each of NodeIDs in the source (nil if unknown)
+	ClassName     string     // Class name to apply
+	Pos           Position
 }
 
 func (c *ClassAssignment) statement() {}
 
+// NodePosition returns the position of NodeIDs[i], or the statement's position
+// when it isn't recorded.
+func (c *ClassAssignment) NodePosition(i int) Position {
+	if i < len(c.NodePositions) {
+		return c.NodePositions[i]
+	}
+	return c.Pos
+}
+
 // GetPosition returns the position of this class assignment in the source.
 func (c *ClassAssignment) GetPosition() Position { return c.Pos }
 
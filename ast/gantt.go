@@ -7,10 +7,12 @@ type GanttDiagram struct {
 	DateFormat  string         // Date format (default YYYY-MM-DD)
 	AxisFormat  string         // Optional axis format for display
 	Excludes    string         // Excluded days (weekends, holidays, etc.)
+	ExcludesPos Position       // Position of the excludes line
 	TodayMarker string         // "on", "off", or colour value
 	Sections    []GanttSection // Sections with tasks
 	Source      string         // Original source
 	Pos         Position       // Position in source
+	Annotations
 }
 
 // GanttSection represents a section within a Gantt chart.
@@ -7,6 +7,7 @@ type GenericDiagram struct {
 	Source      string   // Raw diagram source
 	Lines       []string // Split lines for line-based validation
 	Pos         Position // Position in source
+	Annotations
 }
 
 // GetType returns the diagram type.
@@ -9,6 +9,7 @@ type GitGraphDiagram struct {
 	Operations      []GitOperation // All git operations (commits, branches, merges, etc.)
 	Source          string         // Original source
 	Pos             Position       // Position in source
+	Annotations
 }
 
 // GitOperation represents a single git operation.
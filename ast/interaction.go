@@ -0,0 +1,22 @@
+package ast
+
+// Interaction represents a `click` statement (flowcharts and class diagrams) or a
+// class diagram `link`/`callback` statement that attaches a URL or JavaScript
+// callback to a node or class.
+type Interaction struct {
+	NodeID   string // Node or class the interaction is attached to
+	Kind     string // "href" or "callback"
+	URL      string // Link URL (href only)
+	Target   string // Link target such as _blank or _self (href only, optional)
+	Callback string // JavaScript function name (callback only)
+	Args     string // Raw arguments from `call fn(args)` (callback only, optional)
+	Tooltip  string // Tooltip text (optional)
+	Pos      Position
+}
+
+func (i *Interaction) statement() {}
+
+func (i *Interaction) classStmt() {}
+
+// GetPosition returns the position of this interaction in the source.
+func (i *Interaction) GetPosition() Position { return i.Pos }
@@ -5,8 +5,10 @@ type JourneyDiagram struct {
 	Type     string    // Always "journey"
 	Title    string    // Optional title
 	Sections []Section // Journey sections
+	Actors   []string  // Every actor named by a task, deduplicated in order of first appearance
 	Source   string    // Original source
 	Pos      Position  // Position in source
+	Annotations
 }
 
 // Section represents a section within a user journey diagram.
@@ -0,0 +1,13 @@
+package ast
+
+// Annotations holds metadata collected from structured comment annotations such
+// as `%% @owner: platform-team`. It is embedded in every diagram type.
+type Annotations struct {
+	Metadata map[string]string // Annotation values keyed by name (without the leading @)
+}
+
+// GetMetadata returns the diagram's annotation metadata (nil if it has none).
+func (a *Annotations) GetMetadata() map[string]string { return a.Metadata }
+
+// SetMetadata replaces the diagram's annotation metadata.
+func (a *Annotations) SetMetadata(metadata map[string]string) { a.Metadata = metadata }
@@ -6,13 +6,16 @@ type MindmapDiagram struct {
 	Root   *MindmapNode // Root node (required)
 	Source string       // Original source
 	Pos    Position     // Position in source
+	Annotations
 }
 
 // MindmapNode represents a node in a mindmap diagram.
 type MindmapNode struct {
 	Text     string         // Node text content
 	Shape    string         // Node shape: "()", "(())", "[]", "{{}}", "))((" or "" for default
-	Icon     string         // Optional icon (e.g., "fa fa-book")
+	Icon     string         // Optional icon (e.g., "fa fa-book"); the last ::icon() wins
+	Icons    []string       // Every ::icon() payload given for the node, in order
+	Classes  []string       // CSS classes from a ::: line (e.g., ":::urgent large")
 	Level    int            // Indentation level (0 for root)
 	Children []*MindmapNode // Child nodes
 	Pos      Position       // Position in source
@@ -2,18 +2,22 @@ package ast
 
 // PieDiagram represents a pie chart diagram AST.
 type PieDiagram struct {
-	Type        string      // Always "pie"
-	Title       string      // Optional title
-	ShowData    bool        // Whether to show data values
-	DataEntries []PieEntry  // Data entries
-	Source      string      // Original source
-	Pos         Position    // Position in source
+	Type        string            // Always "pie"
+	Title       string            // Optional title
+	ShowData    bool              // Whether to show data values
+	Config      map[string]string // Pie settings from an init directive: "pie" keys (e.g. textPosition) and pie* theme variables (e.g. pieOuterStrokeWidth)
+	ConfigPos   Position          // Position of the init directive that set Config
+	DataEntries []PieEntry        // Data entries
+	Source      string            // Original source
+	Pos         Position          // Position in source
+	Annotations
 }
 
 // PieEntry represents a single data entry in a pie chart.
 type PieEntry struct {
 	Label string   // Entry label (must be quoted in source)
 	Value float64  // Numeric value (must be positive)
+	Raw   string   // Value as written in the source
 	Pos   Position // Position in source
 }
 
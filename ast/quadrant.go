@@ -8,8 +8,10 @@ type QuadrantDiagram struct {
 	YAxis          QuadrantAxis    // Y-axis configuration
 	QuadrantLabels [4]string       // Labels for quadrants 1-4 (indexed 0-3)
 	Points         []QuadrantPoint // Data points
+	ClassDefs      []ClassDef      // Point style classes (classDef)
 	Source         string          // Original source
 	Pos            Position        // Position in source
+	Annotations
 }
 
 // QuadrantAxis represents an axis definition in a quadrant chart.
@@ -24,6 +26,9 @@ type QuadrantPoint struct {
 	X    float64  // X coordinate (0.0-1.0)
 	Y    float64  // Y coordinate (0.0-1.0)
 	Pos  Position // Position in source
+
+	Class  string            // Class applied with Name:::class (optional)
+	Styles map[string]string // Inline styles, e.g. radius, color, stroke-color (optional)
 }
 
 // GetType returns the diagram type.
@@ -6,6 +6,7 @@ type SankeyDiagram struct {
 	Links  []SankeyLink // Flow links between nodes
 	Source string       // Original source
 	Pos    Position     // Position in source
+	Annotations
 }
 
 // SankeyLink represents a flow link between two nodes.
@@ -0,0 +1,151 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// SchemaVersion is the version of the AST's layout, as recorded in Document.
+//
+// Within a schema version the AST only grows: existing exported types, fields
+// and their meaning stay as they are, while new fields, new diagram types and
+// new statement types may be added. Code switching on a statement interface
+// (Statement, SeqStmt, ClassStmt, StateStmt and so on) should therefore have a
+// default case that skips types it doesn't know, and code building AST values
+// should use keyed struct literals. Removing or renaming a field, or changing
+// its type or meaning, needs a new schema version and an upgrade from the old
+// one (see DecodeDocument).
+const SchemaVersion = 1
+
+// Document is the JSON form of a diagram's AST, tagged with the schema version
+// it was written with so consumers can tell which layout to expect.
+//
+// Each struct becomes an object whose keys are its exported field names with
+// the first word lower-cased (Line, ParentID and EdgeType become line,
+// parentID and edgeType). Fields of embedded structs are inlined. Structs held
+// by pointer or behind an interface, including the diagram itself, also have a
+// "kind" key naming their Go type, such as "Flowchart", "Link" or "Message",
+// which tells the statements of a diagram apart.
+type Document struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Diagram       map[string]any `json:"diagram"`
+}
+
+// NewDocument returns the Document of diagram at the current SchemaVersion.
+func NewDocument(diagram Diagram) Document {
+	encoded, _ := encodeValue(reflect.ValueOf(diagram), true).(map[string]any)
+	return Document{SchemaVersion: SchemaVersion, Diagram: encoded}
+}
+
+// MarshalJSON encodes the Document of diagram as JSON.
+func MarshalJSON(diagram Diagram) ([]byte, error) {
+	return json.Marshal(NewDocument(diagram))
+}
+
+// upgrades converts a document's diagram from the schema version it is keyed
+// by to the next one. Each schema version after the first adds an entry, so
+// documents written by older releases keep decoding.
+var upgrades = map[int]func(diagram map[string]any) (map[string]any, error){}
+
+// DecodeDocument decodes a Document written with this or an earlier schema
+// version, upgrading it to SchemaVersion. It reports documents without a
+// schema version and those written by a newer release.
+func DecodeDocument(data []byte) (Document, error) {
+	var document Document
+	if err := json.Unmarshal(data, &document); err != nil {
+		return Document{}, fmt.Errorf("decoding AST document: %w", err)
+	}
+	switch {
+	case document.SchemaVersion < 1:
+		return Document{}, fmt.Errorf("decoding AST document: missing schemaVersion")
+	case document.SchemaVersion > SchemaVersion:
+		return Document{}, fmt.Errorf("decoding AST document: schema version %d is newer than the supported version %d", document.SchemaVersion, SchemaVersion)
+	}
+	for document.SchemaVersion < SchemaVersion {
+		upgrade, ok := upgrades[document.SchemaVersion]
+		if !ok {
+			return Document{}, fmt.Errorf("decoding AST document: no upgrade from schema version %d", document.SchemaVersion)
+		}
+		diagram, err := upgrade(document.Diagram)
+		if err != nil {
+			return Document{}, fmt.Errorf("decoding AST document: upgrading from schema version %d: %w", document.SchemaVersion, err)
+		}
+		document.Diagram = diagram
+		document.SchemaVersion++
+	}
+	return document, nil
+}
+
+// encodeValue converts v to the JSON-ready form described on Document. kind
+// adds the "kind" key to a struct.
+func encodeValue(v reflect.Value, kind bool) any {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(v.Elem(), true)
+	case reflect.Struct:
+		object := make(map[string]any)
+		if kind {
+			object["kind"] = v.Type().Name()
+		}
+		encodeFields(v, object)
+		return object
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = encodeValue(v.Index(i), false)
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		object := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			object[fmt.Sprint(iter.Key().Interface())] = encodeValue(iter.Value(), false)
+		}
+		return object
+	}
+	return v.Interface()
+}
+
+// encodeFields adds the exported fields of the struct v to object, inlining
+// embedded structs.
+func encodeFields(v reflect.Value, object map[string]any) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			encodeFields(v.Field(i), object)
+			continue
+		}
+		object[fieldKey(field.Name)] = encodeValue(v.Field(i), false)
+	}
+}
+
+// fieldKey returns the JSON key of a field: its name with the leading word
+// lower-cased, treating a run of capitals as one word (ID becomes id, and
+// URLPath urlPath).
+func fieldKey(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		upper-- // The last capital starts the next word
+	}
+	return strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+}
@@ -0,0 +1,96 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Line", "line"},
+		{"ID", "id"},
+		{"ParentID", "parentID"},
+		{"URLPath", "urlPath"},
+		{"BiDir", "biDir"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldKey(tt.name); got != tt.want {
+				t.Errorf("fieldKey(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	flowchart := &Flowchart{
+		Type:      "flowchart",
+		Direction: "LR",
+		Statements: []Statement{
+			&Link{From: "A", To: "B", Arrow: "-->", Length: 1, Pos: Position{Line: 2, Column: 5}},
+		},
+		Pos:         Position{Line: 1, Column: 1},
+		Annotations: Annotations{Metadata: map[string]string{"owner": "platform"}},
+	}
+
+	data, err := MarshalJSON(flowchart)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	for _, want := range []string{
+		`"schemaVersion":1`,
+		`"kind":"Flowchart"`,
+		`"direction":"LR"`,
+		`"metadata":{"owner":"platform"}`,
+		`"kind":"Link"`,
+		`"pos":{"column":5,"line":2}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("MarshalJSON() = %s, missing %s", data, want)
+		}
+	}
+
+	document, err := DecodeDocument(data)
+	if err != nil {
+		t.Fatalf("DecodeDocument() error = %v", err)
+	}
+	statements, _ := document.Diagram["statements"].([]any)
+	if len(statements) != 1 {
+		t.Fatalf("decoded statements = %v, want one", document.Diagram["statements"])
+	}
+	if link, _ := statements[0].(map[string]any); link["from"] != "A" || link["kind"] != "Link" {
+		t.Errorf("decoded link = %v", link)
+	}
+}
+
+func TestDecodeDocument_Versions(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"current", `{"schemaVersion":1,"diagram":{"kind":"PieChart"}}`, ""},
+		{"missing version", `{"diagram":{}}`, "missing schemaVersion"},
+		{"newer version", `{"schemaVersion":99,"diagram":{}}`, "newer than the supported version 1"},
+		{"not JSON", `flowchart`, "decoding AST document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeDocument([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("DecodeDocument() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodeDocument() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ type SequenceDiagram struct {
 	Statements []SeqStmt   // All statements in the diagram
 	Source     string      // Original source
 	Pos        Position    // Position in source
+	Annotations
 }
 
 // GetType returns the diagram type.
@@ -36,14 +37,24 @@ func (p *Participant) seqStmt() {}
 // GetPosition returns the position of this participant in the source.
 func (p *Participant) GetPosition() Position { return p.Pos }
 
+// MessageArrows are the arrows a Message can have: solid and dotted lines,
+// arrowheads, crosses, open (async) arrowheads and bidirectional arrows.
+// Messages written right to left, with <<- or <<--, are stored with the
+// equivalent arrow from this list.
+var MessageArrows = []string{"->", "-->", "->>", "-->>", "-x", "--x", "-)", "--)", "<<->>", "<<-->>"}
+
 // Message represents a message between participants.
 type Message struct {
 	From   string   // Source participant ID
 	To     string   // Target participant ID
-	Arrow  string   // Arrow type: "->", "-->", "->>", "-->>", "-x", "--x", "-)", "--)", "<<->>", "<<-->>"
-	Text   string   // Message text (optional)
+	Arrow  string   // Arrow type, one of MessageArrows
+	Text   string   // Message text (optional), as written
+	DisplayText string // Text as displayed, with entities decoded (see DecodeText)
 	Activate   bool // Activate target on this message
 	Deactivate bool // Deactivate source on this message
+	// Reversed is set for a message written right to left, as in B<<-A; From
+	// is still the sender and Arrow the equivalent left-to-right arrow
+	Reversed bool
 	Pos    Position
 }
 
@@ -180,9 +191,13 @@ func (b *Box) seqStmt() {}
 // GetPosition returns the position of this box in the source.
 func (b *Box) GetPosition() Position { return b.Pos }
 
-// Autonumber represents the autonumber directive.
+// Autonumber represents the autonumber directive. It may appear more than
+// once, for example to turn numbering off and back on mid-diagram.
 type Autonumber struct {
-	Enabled bool     // Enable/disable autonumbering
+	Enabled bool     // Enable/disable autonumbering ("autonumber off" disables)
+	Start   int      // First number (default 1)
+	Step    int      // Increment between numbers (default 1)
+	Args    []string // Arguments as written, kept for validation
 	Pos     Position
 }
 
@@ -6,6 +6,8 @@ type StateDiagram struct {
 	Statements []StateStmt // All statements in the diagram
 	Source     string      // Original source
 	Pos        Position    // Position in source
+	Annotations
+	Skips
 }
 
 // StateStmt is the interface for all state diagram statements.
@@ -30,10 +32,14 @@ func (s *State) GetPosition() Position { return s.Pos }
 
 // Transition represents a transition between states.
 type Transition struct {
-	From      string   // Source state ID
-	To        string   // Target state ID
-	Label     string   // Transition label/condition
-	Pos       Position
+	From     string // Source state ID
+	To       string // Target state ID
+	Label    string // Transition label/condition
+	HasLabel bool   // true when the transition has a ":" label separator, even if the label is empty
+	Event    string // Event part of an "event [guard] / action" label
+	Guard    string // Guard condition, without brackets
+	Action   string // Action after the "/"
+	Pos      Position
 }
 
 func (t *Transition) stateStmt() {}
@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// entityCodePattern matches Mermaid's entity codes, written like HTML entities
+// with # in place of &: #quot; for a double quote and #35; for a hash.
+var entityCodePattern = regexp.MustCompile(`#(\w+);`)
+
+// DecodeText returns label or message text as Mermaid displays it: without the
+// double quotes around a quoted label, with escaped quotes (\") unescaped, and
+// with HTML entities (&quot;, &#35;) and Mermaid entity codes (#quot;, #35;)
+// decoded. Unknown entities are left as they are.
+func DecodeText(raw string) string {
+	text := raw
+	if len(text) >= 2 && strings.HasPrefix(text, `"`) && strings.HasSuffix(text, `"`) {
+		text = text[1 : len(text)-1]
+	}
+	text = strings.ReplaceAll(text, `\"`, `"`)
+	text = html.UnescapeString(text)
+	return entityCodePattern.ReplaceAllStringFunc(text, func(code string) string {
+		decoded, ok := DecodeEntity(code[1 : len(code)-1])
+		if !ok {
+			return code
+		}
+		return decoded
+	})
+}
+
+// DecodeEntity returns the character named by an entity code's name, such as
+// "quot" or "35", and whether the name is known.
+func DecodeEntity(name string) (string, bool) {
+	if n, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return string(rune(n)), true
+	}
+	entity := "&" + name + ";"
+	decoded := html.UnescapeString(entity)
+	return decoded, decoded != entity
+}
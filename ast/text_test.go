@@ -0,0 +1,49 @@
+package ast
+
+import "testing"
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"Start", "Start"},
+		{`"Start (here)"`, "Start (here)"},
+		{`"say #quot;hi#quot;"`, `say "hi"`},
+		{`"say \"hi\""`, `say "hi"`},
+		{"#35;1 &amp; &#35;2", "#1 & #2"},
+		{"#9829; love", "♥ love"},
+		{"#qout; stays", "#qout; stays"},
+		{`"`, `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := DecodeText(tt.raw); got != tt.want {
+				t.Errorf("DecodeText(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEntity(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"quot", `"`, true},
+		{"35", "#", true},
+		{"amp", "&", true},
+		{"qout", "&qout;", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DecodeEntity(tt.name)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("DecodeEntity(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ type TimelineDiagram struct {
 	Sections []TimelineSection // Sections containing periods
 	Source   string            // Original source
 	Pos      Position          // Position in source
+	Annotations
 }
 
 // TimelineSection represents a section in a timeline diagram.
@@ -10,6 +10,7 @@ type XYChartDiagram struct {
 	Series      []XYChartSeries  // Data series (bar, line)
 	Source      string           // Original source
 	Pos         Position         // Position in source
+	Annotations
 }
 
 // XYChartAxis represents an axis configuration in an XY chart.
@@ -25,6 +26,7 @@ type XYChartAxis struct {
 // XYChartSeries represents a data series in an XY chart.
 type XYChartSeries struct {
 	Type   string    // "bar" or "line"
+	Name   string    // Optional series title (e.g. bar "Revenue" [...])
 	Values []float64 // Data values
 	Pos    Position  // Position in source
 }
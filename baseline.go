@@ -0,0 +1,195 @@
+package mermaid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// BaselineFinding is a validation error recorded in a baseline.
+type BaselineFinding struct {
+	File        string `json:"file"`        // File path relative to the baseline, with forward slashes
+	Rule        string `json:"rule"`        // Name of the rule that reported the error
+	Fingerprint string `json:"fingerprint"` // See ErrorFingerprint
+}
+
+// Baseline records known validation errors so that only new ones are
+// reported, letting existing documents be fixed gradually.
+type Baseline struct {
+	Findings []BaselineFinding `json:"findings"`
+
+	// dir is the directory file paths are relative to.
+	dir string
+}
+
+// ParseErrorRule is the rule name baselines record parse errors under.
+const ParseErrorRule = "parse-error"
+
+// lineReferencePattern matches line numbers quoted in error messages, such as
+// "first defined at line 3" or the "line 3:" prefix of parse errors, which
+// change when unrelated lines are added. Only the "line N" wording is
+// matched, so identifiers such as L2 are left alone.
+var lineReferencePattern = regexp.MustCompile(`\b(lines?) \d+`)
+
+// parseErrorLinePattern extracts the line number from parse errors of the
+// form "line N: message".
+var parseErrorLinePattern = regexp.MustCompile(`^line (\d+): `)
+
+// ErrorFingerprint identifies err independently of where it appears, so that a
+// baselined error is still recognised after unrelated lines are added or
+// removed. It combines the diagram type, rule, message (with line numbers
+// removed) and the text of the line the error is reported on.
+func ErrorFingerprint(diagram ast.Diagram, err validator.ValidationError) string {
+	var source string
+	if sourced, ok := diagram.(interface{ GetSource() string }); ok {
+		source = sourced.GetSource()
+	}
+	return fingerprint(diagram.GetType(), err.Rule, err.Message, source, err.Line)
+}
+
+// ParseErrorFingerprint identifies a parse error of source in the same way as
+// ErrorFingerprint, from the type detected for source, ParseErrorRule, the
+// message and the text of the line it is reported on.
+func ParseErrorFingerprint(source string, err error) string {
+	line := 0
+	if matches := parseErrorLinePattern.FindStringSubmatch(err.Error()); matches != nil {
+		line, _ = strconv.Atoi(matches[1])
+	}
+	return fingerprint(parser.DetectType(source), ParseErrorRule, err.Error(), source, line)
+}
+
+// fingerprint hashes the parts of an error's fingerprint, removing line
+// numbers from message and taking the text of line from source.
+func fingerprint(diagramType, rule, message, source string, line int) string {
+	var lineText string
+	if lines := strings.Split(source, "\n"); line >= 1 && line <= len(lines) {
+		lineText = strings.TrimSpace(lines[line-1])
+	}
+	message = lineReferencePattern.ReplaceAllString(message, "$1")
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{diagramType, rule, message, lineText}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewBaseline records every validation and parse error in results, with file
+// paths made relative to dir (normally the directory the baseline is saved in).
+func NewBaseline(results []Result, dir string) *Baseline {
+	baseline := &Baseline{Findings: []BaselineFinding{}, dir: dir}
+	for _, result := range results {
+		file := baseline.relative(result.File)
+		if result.ParseError != nil {
+			baseline.Findings = append(baseline.Findings, BaselineFinding{
+				File:        file,
+				Rule:        ParseErrorRule,
+				Fingerprint: ParseErrorFingerprint(result.Source, result.ParseError),
+			})
+		}
+		for _, err := range result.Errors {
+			baseline.Findings = append(baseline.Findings, BaselineFinding{
+				File:        file,
+				Rule:        err.Rule,
+				Fingerprint: ErrorFingerprint(result.Diagram, err),
+			})
+		}
+	}
+	return baseline
+}
+
+// LoadBaseline reads a baseline written by Save. File paths in it are
+// relative to the directory containing path.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided baseline path is intentional
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	baseline.dir = filepath.Dir(path)
+
+	return &baseline, nil
+}
+
+// Save writes the baseline to path as JSON.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// ForFile returns the baselined findings for the file at path, for filtering
+// the errors of each diagram in that file.
+func (b *Baseline) ForFile(path string) *FileBaseline {
+	file := b.relative(path)
+	known := make(map[string]int)
+	for _, finding := range b.Findings {
+		if finding.File == file {
+			known[finding.Rule+"\x00"+finding.Fingerprint]++
+		}
+	}
+	return &FileBaseline{known: known}
+}
+
+// relative returns path relative to the baseline's directory, falling back to
+// the path as given when it cannot be made relative.
+func (b *Baseline) relative(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if dir, err := filepath.Abs(b.dir); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// FileBaseline filters the errors of one file against a Baseline. Each
+// baselined finding excuses a single error, so a file with more copies of an
+// error than were recorded still reports the extra ones.
+type FileBaseline struct {
+	known map[string]int
+
+	// Suppressed counts the errors filtered out so far.
+	Suppressed int
+}
+
+// Filter returns the errors of diagram that are not in the baseline.
+func (f *FileBaseline) Filter(diagram ast.Diagram, errors []validator.ValidationError) []validator.ValidationError {
+	var remaining []validator.ValidationError
+	for _, err := range errors {
+		key := err.Rule + "\x00" + ErrorFingerprint(diagram, err)
+		if f.known[key] > 0 {
+			f.known[key]--
+			f.Suppressed++
+			continue
+		}
+		remaining = append(remaining, err)
+	}
+	return remaining
+}
+
+// FilterParseError returns nil if the parse error err of source is in the
+// baseline, and err otherwise.
+func (f *FileBaseline) FilterParseError(source string, err error) error {
+	key := ParseErrorRule + "\x00" + ParseErrorFingerprint(source, err)
+	if f.known[key] > 0 {
+		f.known[key]--
+		f.Suppressed++
+		return nil
+	}
+	return err
+}
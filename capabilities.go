@@ -0,0 +1,185 @@
+package mermaid
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// Version is the version of mermaid-check.
+const Version = "0.1.0"
+
+// CapabilityManifest describes what this version of mermaid-check supports,
+// for CI templates and editor plugins that adapt to the installed version. It
+// encodes to JSON with stable field names.
+type CapabilityManifest struct {
+	Version      string              `json:"version"`
+	DiagramTypes []DiagramCapability `json:"diagramTypes"`
+	Rules        []RuleCapability    `json:"rules"`
+}
+
+// DiagramCapability is a diagram type mermaid-check parses.
+type DiagramCapability struct {
+	// Type is the diagram kind, as in validator.DiagramKinds, or the header
+	// of a type added with parser.Register.
+	Type string `json:"type"`
+	// Headers are the first words of the diagrams of this type.
+	Headers []string `json:"headers"`
+	// Features lists the statements the parser accepts, with examples.
+	Features []string `json:"features,omitempty"`
+}
+
+// RuleCapability is a validation rule.
+type RuleCapability struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"` // See validator.RuleVersion
+	// DiagramTypes are the kinds of diagram the rule checks, or empty for
+	// every kind.
+	DiagramTypes []string `json:"diagramTypes,omitempty"`
+	// Default and Strict report whether the rule is in the default and strict
+	// rule sets. A rule in neither runs only when configured.
+	Default bool `json:"default"`
+	Strict  bool `json:"strict"`
+	// Options are the options of a configurable rule.
+	Options []validator.Option `json:"options,omitempty"`
+}
+
+// diagramHeaders are the headers of each diagram kind.
+var diagramHeaders = map[string][]string{
+	"flowchart": {"flowchart", "flowchart-elk", "graph"},
+	"sequence":  {"sequenceDiagram"},
+	"class":     {"classDiagram", "classDiagram-v2"},
+	"state":     {"stateDiagram", "stateDiagram-v2"},
+	"er":        {"erDiagram"},
+	"gantt":     {"gantt"},
+	"pie":       {"pie"},
+	"journey":   {"journey"},
+	"c4":        {"C4Context", "C4Container", "C4Component", "C4Dynamic", "C4Deployment"},
+	"gitgraph":  {"gitGraph"},
+	"mindmap":   {"mindmap"},
+	"timeline":  {"timeline"},
+	"quadrant":  {"quadrantChart"},
+	"xychart":   {"xychart-beta"},
+	"sankey":    {"sankey-beta"},
+}
+
+// constructTypes maps the diagram kinds whose expectedConstructs are keyed
+// differently to their key.
+var constructTypes = map[string]string{
+	"gitgraph": "gitGraph",
+	"quadrant": "quadrantChart",
+	"xychart":  "xyChart",
+}
+
+// ruleSets are the default and strict rules of each diagram kind.
+func ruleSets() map[string][2][]any {
+	return map[string][2][]any{
+		"flowchart": {anyRules(validator.DefaultRules()), anyRules(validator.StrictRules())},
+		"sequence":  {anyRules(validator.SequenceDefaultRules()), anyRules(validator.SequenceStrictRules())},
+		"class":     {anyRules(validator.ClassDefaultRules()), anyRules(validator.ClassStrictRules())},
+		"state":     {anyRules(validator.StateDefaultRules()), anyRules(validator.StateStrictRules())},
+		"er":        {anyRules(validator.ERDefaultRules()), anyRules(validator.ERStrictRules())},
+		"gantt":     {anyRules(validator.GanttDefaultRules()), anyRules(validator.GanttStrictRules())},
+		"pie":       {anyRules(validator.PieDefaultRules()), anyRules(validator.PieStrictRules())},
+		"journey":   {anyRules(validator.JourneyDefaultRules()), anyRules(validator.JourneyStrictRules())},
+		"c4":        {anyRules(validator.DefaultC4Rules()), anyRules(validator.StrictC4Rules())},
+		"gitgraph":  {anyRules(validator.GitGraphDefaultRules()), anyRules(validator.GitGraphStrictRules())},
+		"mindmap":   {anyRules(validator.MindmapDefaultRules()), anyRules(validator.MindmapStrictRules())},
+		"timeline":  {anyRules(validator.TimelineDefaultRules()), anyRules(validator.TimelineStrictRules())},
+		"quadrant":  {anyRules(validator.QuadrantDefaultRules()), anyRules(validator.QuadrantStrictRules())},
+		"xychart":   {anyRules(validator.XYChartDefaultRules()), anyRules(validator.XYChartStrictRules())},
+		"sankey":    {anyRules(validator.SankeyDefaultRules()), anyRules(validator.SankeyStrictRules())},
+	}
+}
+
+func anyRules[R any](rules []R) []any {
+	all := make([]any, len(rules))
+	for i, rule := range rules {
+		all[i] = rule
+	}
+	return all
+}
+
+// diagramRuleCapabilities are the rules that apply across diagram kinds (see
+// diagramRules), with the kinds they check when not every kind, and whether
+// they are in the default and strict rule sets.
+var diagramRuleCapabilities = []struct {
+	rule                any
+	kinds               []string
+	inDefault, inStrict bool
+}{
+	{&validator.ConfusableCharacters{}, nil, false, true},
+	{&validator.SkippedLines{}, []string{"flowchart", "class", "state"}, false, true},
+	{&validator.SelfLoops{}, []string{"flowchart", "state", "class", "c4"}, false, true},
+	{&validator.KnownIcons{}, []string{"c4"}, false, true},
+	{&validator.MixedIndentation{}, nil, false, true},
+	{&validator.RequiredAnnotations{}, nil, false, false},
+	{&validator.SpellCheck{}, []string{"flowchart", "sequence"}, false, false},
+	{&validator.Terminology{}, nil, false, false},
+	{&validator.NamingConventions{}, []string{"flowchart", "sequence", "class", "state"}, false, false},
+	{&validator.IndentStyle{}, nil, false, false},
+	{&validator.ParticipantsDeclaredFirst{}, []string{"sequence"}, false, false},
+	{&validator.ERSQLTypes{}, []string{"er"}, false, false},
+	{&validator.RendererCompatibility{}, nil, false, false},
+	{&validator.AllowedDirections{}, []string{"flowchart"}, false, false},
+	{&validator.MaxParticipants{}, []string{"sequence"}, false, false},
+}
+
+// Capabilities returns the diagram types this version of mermaid-check
+// parses, the statements each accepts, and its validation rules, sorted by
+// name.
+func Capabilities() CapabilityManifest {
+	manifest := CapabilityManifest{Version: Version}
+	for _, kind := range validator.DiagramKinds {
+		key := kind
+		if constructKey, ok := constructTypes[kind]; ok {
+			key = constructKey
+		}
+		manifest.DiagramTypes = append(manifest.DiagramTypes, DiagramCapability{
+			Type:     kind,
+			Headers:  diagramHeaders[kind],
+			Features: expectedConstructs[key],
+		})
+	}
+	for _, header := range parser.Registered() {
+		manifest.DiagramTypes = append(manifest.DiagramTypes, DiagramCapability{Type: header, Headers: []string{header}})
+	}
+
+	rules := make(map[string]*RuleCapability)
+	add := func(rule any, kind string, inDefault, inStrict bool) {
+		name := validator.RuleName(rule)
+		capability, ok := rules[name]
+		if !ok {
+			capability = &RuleCapability{Name: name, Version: validator.RuleVersion(rule)}
+			if configurable, ok := rule.(validator.ConfigurableRule); ok {
+				capability.Options = configurable.Options()
+			}
+			rules[name] = capability
+		}
+		if kind != "" && !slices.Contains(capability.DiagramTypes, kind) {
+			capability.DiagramTypes = append(capability.DiagramTypes, kind)
+		}
+		capability.Default = capability.Default || inDefault
+		capability.Strict = capability.Strict || inStrict
+	}
+	sets := ruleSets()
+	for _, kind := range validator.DiagramKinds {
+		for _, rule := range sets[kind][0] {
+			add(rule, kind, true, false)
+		}
+		for _, rule := range sets[kind][1] {
+			add(rule, kind, false, true)
+		}
+	}
+	for _, r := range diagramRuleCapabilities {
+		add(r.rule, "", r.inDefault, r.inStrict)
+		rules[validator.RuleName(r.rule)].DiagramTypes = r.kinds
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(rules)) {
+		manifest.Rules = append(manifest.Rules, *rules[name])
+	}
+	return manifest
+}
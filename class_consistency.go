@@ -0,0 +1,204 @@
+package mermaid
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// classConsistencyRule names the errors reported by CheckClassConsistency.
+const classConsistencyRule = "class-consistency"
+
+// classDefinition is a class with members defined in one diagram of a file.
+type classDefinition struct {
+	result  *Result
+	line    int      // Line in the file of the first statement defining the class
+	members []string // Member signatures, sorted
+}
+
+// CheckClassConsistency checks class diagrams that describe the same model in
+// several diagrams of one file, such as the views of a design document. It
+// reports a class defined with members in more than one diagram when the
+// member sets differ, and a relationship that refers to a class defined only
+// in another diagram of the file, since that diagram's reader cannot see the
+// definition. Results from different files are checked separately.
+func CheckClassConsistency(results []Result) []ReferenceError {
+	var errors []ReferenceError
+	byFile := make(map[string][]*Result)
+	var files []string
+	for i := range results {
+		r := &results[i]
+		if _, ok := r.Diagram.(*ast.ClassDiagram); !ok {
+			continue
+		}
+		if _, seen := byFile[r.File]; !seen {
+			files = append(files, r.File)
+		}
+		byFile[r.File] = append(byFile[r.File], r)
+	}
+
+	for _, file := range files {
+		diagrams := byFile[file]
+		if len(diagrams) < 2 {
+			continue
+		}
+		errors = append(errors, checkClassDiagrams(diagrams)...)
+	}
+	return errors
+}
+
+// checkClassDiagrams checks the class diagrams of one file against each other.
+func checkClassDiagrams(diagrams []*Result) []ReferenceError {
+	var errors []ReferenceError
+	first := make(map[string]classDefinition)      // First definition with members of each class
+	declared := make(map[string][]classDefinition) // Every explicit declaration of each class
+	for _, r := range diagrams {
+		definitions := classDefinitions(r)
+		for _, name := range sortedClassNames(definitions) {
+			definition := definitions[name]
+			declared[name] = append(declared[name], definition)
+			if len(definition.members) == 0 {
+				continue
+			}
+			earlier, exists := first[name]
+			if !exists {
+				first[name] = definition
+				continue
+			}
+			if !slices.Equal(earlier.members, definition.members) {
+				errors = append(errors, ReferenceError{
+					File:       r.File,
+					BlockIndex: r.BlockIndex,
+					Line:       definition.line,
+					Message: fmt.Sprintf("class '%s' has different members from its definition in diagram %d at line %d%s",
+						name, earlier.result.BlockIndex+1, earlier.line, memberDifference(earlier.members, definition.members)),
+					Rule: classConsistencyRule,
+				})
+			}
+		}
+	}
+
+	for _, r := range diagrams {
+		diagram := r.Diagram.(*ast.ClassDiagram)
+		reported := make(map[string]bool)
+		for _, stmt := range diagram.Statements {
+			rel, ok := stmt.(*ast.Relationship)
+			if !ok {
+				continue
+			}
+			for _, name := range []string{rel.From, rel.To} {
+				elsewhere := definedElsewhere(declared[name], r)
+				if elsewhere == nil || reported[name] {
+					continue
+				}
+				reported[name] = true
+				errors = append(errors, ReferenceError{
+					File:       r.File,
+					BlockIndex: r.BlockIndex,
+					Line:       r.LineOffset + rel.Pos.Line - 1,
+					Message: fmt.Sprintf("relationship refers to class '%s', which is only defined in diagram %d at line %d",
+						name, elsewhere.result.BlockIndex+1, elsewhere.line),
+					Rule: classConsistencyRule,
+				})
+			}
+		}
+	}
+	return errors
+}
+
+// classDefinitions returns the classes declared in a class diagram, merging
+// repeated declarations of a class.
+func classDefinitions(r *Result) map[string]classDefinition {
+	diagram := r.Diagram.(*ast.ClassDiagram)
+	definitions := make(map[string]classDefinition)
+	for _, stmt := range diagram.Statements {
+		class, ok := stmt.(*ast.Class)
+		if !ok {
+			continue
+		}
+		definition, exists := definitions[class.Name]
+		if !exists {
+			definition = classDefinition{result: r, line: r.LineOffset + class.Pos.Line - 1}
+		}
+		for _, member := range class.Members {
+			signature := memberSignature(member)
+			if !slices.Contains(definition.members, signature) {
+				definition.members = append(definition.members, signature)
+			}
+		}
+		slices.Sort(definition.members)
+		definitions[class.Name] = definition
+	}
+	return definitions
+}
+
+// definedElsewhere returns a declaration of a class from another diagram than
+// r, or nil if r declares the class itself or no diagram does.
+func definedElsewhere(declarations []classDefinition, r *Result) *classDefinition {
+	var elsewhere *classDefinition
+	for i := range declarations {
+		if declarations[i].result == r {
+			return nil
+		}
+		if elsewhere == nil {
+			elsewhere = &declarations[i]
+		}
+	}
+	return elsewhere
+}
+
+// memberSignature renders a class member for comparison, as it would be written.
+func memberSignature(member ast.ClassMember) string {
+	var b strings.Builder
+	b.WriteString(member.Visibility)
+	b.WriteString(member.Name)
+	if member.IsMethod || len(member.Parameters) > 0 {
+		b.WriteString("(" + strings.Join(member.Parameters, ", ") + ")")
+	}
+	if member.Type != "" {
+		b.WriteString(" " + member.Type)
+	}
+	if member.IsStatic {
+		b.WriteString("$")
+	}
+	if member.IsAbstract {
+		b.WriteString("*")
+	}
+	return b.String()
+}
+
+// memberDifference describes the members in want missing from got and those
+// in got missing from want.
+func memberDifference(want, got []string) string {
+	var missing, extra []string
+	for _, member := range want {
+		if !slices.Contains(got, member) {
+			missing = append(missing, member)
+		}
+	}
+	for _, member := range got {
+		if !slices.Contains(want, member) {
+			extra = append(extra, member)
+		}
+	}
+	var parts []string
+	if len(missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(missing, ", "))
+	}
+	if len(extra) > 0 {
+		parts = append(parts, "adds "+strings.Join(extra, ", "))
+	}
+	return ": " + strings.Join(parts, "; ")
+}
+
+// sortedClassNames returns the class names in definitions in lexical order.
+func sortedClassNames(definitions map[string]classDefinition) []string {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
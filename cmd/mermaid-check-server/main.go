@@ -0,0 +1,39 @@
+// Command mermaid-check-server runs the mermaid-check gRPC validation service
+// (see proto/mermaidcheck/v1/mermaidcheck.proto).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+
+	mermaidcheckv1 "github.com/sammcj/mermaid-check/proto/mermaidcheck/v1"
+	"github.com/sammcj/mermaid-check/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+func main() {
+	var (
+		listen = flag.String("listen", "localhost:50051", "address to listen on")
+		root   = flag.String("root", "", "directory StreamValidateDirectory may read (disabled when empty)")
+	)
+	flag.Parse()
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	grpcServer := grpc.NewServer()
+	mermaidcheckv1.RegisterValidationServiceServer(grpcServer, server.New(*root))
+	reflection.Register(grpcServer)
+
+	fmt.Printf("mermaid-check-server listening on %s\n", listener.Addr())
+	if err := grpcServer.Serve(listener); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build js && wasm
+
+// Command mermaid-check-wasm exposes the validator to JavaScript when compiled
+// to WebAssembly, so documentation editors can validate diagrams client-side:
+//
+//	GOOS=js GOARCH=wasm go build -o mermaid-check.wasm ./cmd/mermaid-check-wasm
+//
+// Once loaded with Go's wasm_exec.js it defines a global mermaidCheck object
+// whose validate(source, options) function returns the JSON report produced by
+// mermaid.ValidateJSON. Options may be a JSON string or a plain object.
+package main
+
+import (
+	"syscall/js"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+func main() {
+	js.Global().Set("mermaidCheck", js.ValueOf(map[string]any{
+		"validate": js.FuncOf(validate),
+	}))
+
+	// Keep the exported functions available to JavaScript
+	select {}
+}
+
+// validate is the JavaScript binding for mermaid.ValidateJSON.
+func validate(_ js.Value, args []js.Value) any {
+	var source, options string
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		source = args[0].String()
+	}
+	if len(args) > 1 {
+		switch args[1].Type() {
+		case js.TypeString:
+			options = args[1].String()
+		case js.TypeObject:
+			options = js.Global().Get("JSON").Call("stringify", args[1]).String()
+		}
+	}
+	return mermaid.ValidateJSON(source, options)
+}
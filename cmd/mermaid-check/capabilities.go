@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+// runCapabilities handles the `capabilities` subcommand, which lists the
+// diagram types and rules this version supports, as JSON with --json.
+func runCapabilities(args []string) int {
+	fs := flag.NewFlagSet("capabilities", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the manifest as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprint(os.Stderr, "Usage: mermaid-check capabilities [--json]\n")
+		return 1
+	}
+
+	manifest := mermaid.Capabilities()
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(manifest); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("mermaid-check %s\n\nDiagram types:\n", manifest.Version)
+	for _, diagramType := range manifest.DiagramTypes {
+		fmt.Printf("  %-10s %s\n", diagramType.Type, strings.Join(diagramType.Headers, ", "))
+	}
+	fmt.Println("\nRules:")
+	for _, rule := range manifest.Rules {
+		set := "configured"
+		switch {
+		case rule.Default:
+			set = "default"
+		case rule.Strict:
+			set = "strict"
+		}
+		types := "all"
+		if len(rule.DiagramTypes) > 0 {
+			types = strings.Join(rule.DiagramTypes, ", ")
+		}
+		fmt.Printf("  %-36s v%d  %-10s %s\n", rule.Name, rule.Version, set, types)
+	}
+	return 0
+}
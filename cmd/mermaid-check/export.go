@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/export"
+)
+
+const exportUsage = `Usage: mermaid-check export sql [--dialect postgres|mysql|sqlite] <file>...
+       mermaid-check export graphml|gexf [--output DIR] <file>...
+`
+
+// runExport handles the `export` subcommand. The `sql` format prints CREATE
+// TABLE statements for every ER diagram in the given files; the `graphml` and
+// `gexf` formats convert flowcharts and state diagrams to graph documents.
+func runExport(args []string, opts mermaid.ValidateOptions) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, exportUsage)
+		return 1
+	}
+	switch args[0] {
+	case "sql":
+		return runExportSQL(args[1:], opts)
+	case "graphml", "gexf":
+		return runExportGraph(args[0], args[1:], opts)
+	}
+	fmt.Fprint(os.Stderr, exportUsage)
+	return 1
+}
+
+// runExportSQL prints CREATE TABLE statements for every ER diagram in the
+// given files.
+func runExportSQL(args []string, opts mermaid.ValidateOptions) int {
+
+	fs := flag.NewFlagSet("export sql", flag.ContinueOnError)
+	dialectName := fs.String("dialect", string(export.Postgres), "SQL dialect: postgres, mysql or sqlite")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	dialect, err := export.ParseDialect(*dialectName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprint(os.Stderr, exportUsage)
+		return 1
+	}
+
+	exitCode := 0
+	exported := 0
+	for _, path := range fs.Args() {
+		results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{FenceLanguages: opts.FenceLanguages})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), path, err)
+			exitCode = 1
+			continue
+		}
+		for _, result := range results {
+			diagram, ok := result.Diagram.(*ast.ERDiagram)
+			if !ok {
+				continue
+			}
+			ddl, err := export.SQL(diagram, dialect)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s (L%d-L%d):\n%v\n", red("✗"), path, result.LineOffset, result.EndLine, err)
+				exitCode = 1
+				continue
+			}
+			if exported > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("-- %s (L%d-L%d)\n%s", path, result.LineOffset, result.EndLine, ddl)
+			exported++
+		}
+	}
+	if exported == 0 && exitCode == 0 {
+		fmt.Fprintf(os.Stderr, "No ER diagrams found\n")
+		return 1
+	}
+	return exitCode
+}
+
+// runExportGraph converts every flowchart and state diagram in the given files
+// to a GraphML or GEXF document. A single diagram is printed; several need
+// --output, and are written to DIR as <file>-L<line>.<format>.
+func runExportGraph(format string, args []string, opts mermaid.ValidateOptions) int {
+	fs := flag.NewFlagSet("export "+format, flag.ContinueOnError)
+	outputDir := fs.String("output", "", "directory to write one document per diagram to")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprint(os.Stderr, exportUsage)
+		return 1
+	}
+	convert := export.GraphML
+	if format == "gexf" {
+		convert = export.GEXF
+	}
+
+	type document struct {
+		path    string
+		result  mermaid.Result
+		content string
+	}
+	var documents []document
+	exitCode := 0
+	for _, path := range fs.Args() {
+		results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{FenceLanguages: opts.FenceLanguages})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), path, err)
+			exitCode = 1
+			continue
+		}
+		for _, result := range results {
+			switch result.Diagram.(type) {
+			case *ast.Flowchart, *ast.StateDiagram:
+			default:
+				continue
+			}
+			content, err := convert(result.Diagram)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s (L%d-L%d):\n%v\n", red("✗"), path, result.LineOffset, result.EndLine, err)
+				exitCode = 1
+				continue
+			}
+			documents = append(documents, document{path: path, result: result, content: content})
+		}
+	}
+
+	switch {
+	case len(documents) == 0 && exitCode == 0:
+		fmt.Fprintf(os.Stderr, "No flowcharts or state diagrams found\n")
+		return 1
+	case *outputDir == "" && len(documents) > 1:
+		fmt.Fprintf(os.Stderr, "Error: found %d diagrams; use --output DIR to write one %s document per diagram\n", len(documents), format)
+		return 1
+	case *outputDir == "":
+		for _, doc := range documents {
+			fmt.Print(doc.content)
+		}
+		return exitCode
+	}
+
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	for _, doc := range documents {
+		base := strings.TrimSuffix(filepath.Base(doc.path), filepath.Ext(doc.path))
+		name := filepath.Join(*outputDir, fmt.Sprintf("%s-L%d.%s", base, doc.result.LineOffset, format))
+		if err := os.WriteFile(name, []byte(doc.content), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), name, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%s %s\n", green("✓"), name)
+	}
+	return exitCode
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// maxFixPasses bounds the fix and revalidate passes made on each file, in case
+// fixes keep producing new findings.
+const maxFixPasses = 10
+
+// fixChoice is the answer to an interactive fix prompt.
+type fixChoice int
+
+const (
+	fixAccept fixChoice = iota
+	fixSkip
+	fixAcceptAll
+	fixQuit
+)
+
+// fixPrompter asks whether to apply each fix, remembering an "all" or "quit"
+// answer for the rest of the run.
+type fixPrompter struct {
+	interactive bool
+	in          *bufio.Reader
+	answer      fixChoice // fixAcceptAll or fixQuit once given
+	answered    bool
+}
+
+// runFix handles the `fix` subcommand.
+func runFix(args []string, opts mermaid.ValidateOptions) int {
+	fs := flag.NewFlagSet("fix", flag.ContinueOnError)
+	interactive := fs.Bool("interactive", false, "show each fix and ask whether to apply it")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: mermaid-check [flags] fix [--interactive] <file>...\n")
+		return 1
+	}
+
+	prompter := &fixPrompter{interactive: *interactive, in: bufio.NewReader(os.Stdin)}
+	exitCode := 0
+	for _, path := range fs.Args() {
+		applied, err := fixFile(path, opts, prompter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), path, err)
+			exitCode = 1
+			continue
+		}
+		if applied > 0 {
+			fmt.Printf("%s %s: applied %d fix(es)\n", green("✓"), path, applied)
+		}
+		if prompter.answered && prompter.answer == fixQuit {
+			break
+		}
+	}
+	return exitCode
+}
+
+// fixFile applies the fixes offered for the diagrams in the file at path,
+// asking about each one when the prompter is interactive, and writes the file
+// back if anything changed. Fixes are applied a pass at a time and the file
+// revalidated, so that fixes uncovered by earlier ones are offered too;
+// declined fixes are not offered again.
+func fixFile(path string, opts mermaid.ValidateOptions, prompter *fixPrompter) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided file path is intentional
+	if err != nil {
+		return 0, err
+	}
+	crlf := strings.Contains(string(data), "\r\n")
+	content := inpututil.NormaliseNewlines(string(data))
+
+	declined := make(map[string]bool)
+	total := 0
+	for range maxFixPasses {
+		results, err := mermaid.ValidateSource(path, content, opts)
+		if err != nil {
+			return total, err
+		}
+
+		lines := strings.Split(content, "\n")
+		var accepted []validator.ValidationError
+		for _, result := range results {
+			for _, finding := range result.Errors {
+				if finding.Fix == nil {
+					continue
+				}
+				fileFix := *finding.Fix
+				// Put back the comment markers of a diagram in source code comments
+				if i := fileFix.Line - 1; !fileFix.Delete && i >= 0 && i < len(result.LinePrefixes) {
+					fileFix.Replacement = result.LinePrefixes[i] + fileFix.Replacement
+				}
+				fileFix.Line += result.LineOffset - 1
+				if fileFix.Line < 1 || fileFix.Line > len(lines) {
+					continue
+				}
+				key := fmt.Sprintf("%s\x00%s\x00%s", finding.Rule, finding.Message, lines[fileFix.Line-1])
+				if declined[key] {
+					continue
+				}
+
+				switch prompter.ask(path, finding, fileFix, lines[fileFix.Line-1]) {
+				case fixAccept, fixAcceptAll:
+					finding.Fix = &fileFix
+					accepted = append(accepted, finding)
+				case fixSkip:
+					declined[key] = true
+				case fixQuit:
+					fixed, applied := validator.ApplyFixes(content, accepted)
+					return total + applied, writeFixed(path, info.Mode(), fixed, crlf, total+applied)
+				}
+			}
+		}
+
+		fixed, applied := validator.ApplyFixes(content, accepted)
+		if applied == 0 {
+			break
+		}
+		content = fixed
+		total += applied
+	}
+	return total, writeFixed(path, info.Mode(), content, crlf, total)
+}
+
+// writeFixed writes content back to path when any fixes have been applied,
+// restoring CRLF line endings if the file had them.
+func writeFixed(path string, mode os.FileMode, content string, crlf bool, applied int) error {
+	if applied == 0 {
+		return nil
+	}
+	if crlf {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	return os.WriteFile(path, []byte(content), mode.Perm())
+}
+
+// ask decides whether to apply fix, which replaces or removes line of the
+// file at path to resolve finding. Outside interactive mode every fix is
+// accepted.
+func (p *fixPrompter) ask(path string, finding validator.ValidationError, fix validator.Fix, line string) fixChoice {
+	if !p.interactive {
+		return fixAccept
+	}
+	if p.answered {
+		return p.answer
+	}
+
+	fmt.Printf("\n%s %s\n", cyan(fmt.Sprintf("%s:%d", path, fix.Line)), withSuggestion(finding.Message, finding.Suggestion))
+	if finding.Rule != "" {
+		fmt.Printf("  %s\n", dim(finding.Rule))
+	}
+	fmt.Printf("  %s\n", red("- "+visibleWhitespace(line)))
+	if !fix.Delete {
+		fmt.Printf("  %s\n", green("+ "+visibleWhitespace(fix.Replacement)))
+	}
+
+	for {
+		fmt.Print(bold("Apply this fix? [y]es, [n]o, [a]ll remaining, [q]uit: "))
+		answer, err := p.in.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return fixAccept
+		case "n", "no":
+			return fixSkip
+		case "a", "all":
+			p.answer, p.answered = fixAcceptAll, true
+			return fixAcceptAll
+		case "q", "quit":
+			p.answer, p.answered = fixQuit, true
+			return fixQuit
+		}
+		if err == io.EOF {
+			fmt.Println()
+			p.answer, p.answered = fixQuit, true
+			return fixQuit
+		}
+	}
+}
+
+// visibleWhitespace shows tabs and trailing spaces in a diff line, where
+// whitespace-only fixes would otherwise look like no change at all.
+func visibleWhitespace(line string) string {
+	line = strings.ReplaceAll(line, "\t", "→   ")
+	trimmed := strings.TrimRight(line, " ")
+	return trimmed + strings.Repeat("·", len(line)-len(trimmed))
+}
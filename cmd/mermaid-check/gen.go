@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/generate"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+const genUsage = `Usage:
+  mermaid-check gen er --from-sql <file|->
+  mermaid-check gen sequence --from-openapi <file|-> --operation <id>
+  mermaid-check gen flowchart|C4Container --from-k8s <file|directory|->
+  mermaid-check gen flowchart --from-terraform-graph <file|->
+`
+
+// genSource is a source a diagram type can be generated from, selected with
+// its flag.
+type genSource struct {
+	flag, usage string
+	manifests   bool // Whether the path may be a directory of YAML files
+	generate    func(data []byte) (string, error)
+}
+
+// runGen handles the `gen` subcommand, which prints a diagram generated from
+// another source: an ER diagram from the CREATE TABLE statements in an SQL
+// file, a sequence diagram from an OpenAPI operation, a flowchart or C4
+// container diagram from Kubernetes manifests, or a flowchart from Terraform's
+// dependency graph. A file named "-" is read from standard input, and a
+// directory of manifests is read whole. The diagram is validated before it is
+// printed.
+func runGen(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, genUsage)
+		return 1
+	}
+
+	var operation string
+	kubernetes := func(diagram func([]byte) (string, error)) genSource {
+		return genSource{flag: "from-k8s", usage: "Kubernetes manifest file or directory, or - for standard input", manifests: true, generate: diagram}
+	}
+	sources := map[string][]genSource{
+		"er": {{flag: "from-sql", usage: "SQL file of CREATE TABLE statements, or - for standard input",
+			generate: func(data []byte) (string, error) {
+				diagram, err := generate.ERFromSQL(string(data))
+				if err != nil {
+					return "", err
+				}
+				return generate.FormatER(diagram), nil
+			}}},
+		"sequence": {{flag: "from-openapi", usage: "OpenAPI document in YAML or JSON, or - for standard input",
+			generate: func(data []byte) (string, error) {
+				diagram, err := generate.SequenceFromOpenAPI(data, operation)
+				if err != nil {
+					return "", err
+				}
+				return generate.FormatSequence(diagram), nil
+			}}},
+		"flowchart": {
+			kubernetes(func(data []byte) (string, error) {
+				diagram, err := generate.KubernetesFlowchart(data)
+				if err != nil {
+					return "", err
+				}
+				return generate.FormatFlowchart(diagram), nil
+			}),
+			{flag: "from-terraform-graph", usage: "output of terraform graph, as DOT or Graphviz JSON, or - for standard input",
+				generate: func(data []byte) (string, error) {
+					diagram, err := generate.TerraformFlowchart(data)
+					if err != nil {
+						return "", err
+					}
+					return generate.FormatFlowchart(diagram), nil
+				}},
+		},
+		"c4container": {kubernetes(func(data []byte) (string, error) {
+			diagram, err := generate.KubernetesC4(data)
+			if err != nil {
+				return "", err
+			}
+			return generate.FormatC4(diagram), nil
+		})},
+	}
+	candidates, ok := sources[strings.ToLower(args[0])]
+	if !ok {
+		fmt.Fprint(os.Stderr, genUsage)
+		return 1
+	}
+
+	fs := flag.NewFlagSet("gen "+args[0], flag.ContinueOnError)
+	paths := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		fs.StringVar(&paths[i], candidate.flag, "", candidate.usage)
+	}
+	if strings.EqualFold(args[0], "sequence") {
+		fs.StringVar(&operation, "operation", "", "operationId, or method and path such as 'GET /pets/{id}'")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+	var selected *genSource
+	var source string
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		if selected != nil {
+			fmt.Fprintf(os.Stderr, "Error: --%s and --%s can't be used together\n", selected.flag, candidates[i].flag)
+			return 1
+		}
+		selected, source = &candidates[i], path
+	}
+	if selected == nil || fs.NArg() != 0 {
+		fmt.Fprint(os.Stderr, genUsage)
+		return 1
+	}
+
+	var data []byte
+	var err error
+	if info, statErr := os.Stat(source); selected.manifests && statErr == nil && info.IsDir() {
+		data, err = readManifestDir(source)
+	} else if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source) //nolint:gosec // User-provided file path is intentional
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), source, err)
+		return 1
+	}
+
+	output, err := selected.generate(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), source, err)
+		return 1
+	}
+	if err := validateGenerated(output); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: generated diagram is invalid: %v\n%s", red("✗"), source, err, output)
+		return 1
+	}
+	fmt.Print(output)
+	return 0
+}
+
+// readManifestDir reads the .yaml and .yml files under dir as one stream of
+// YAML documents.
+func readManifestDir(dir string) ([]byte, error) {
+	var data []byte
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		content, err := os.ReadFile(path) //nolint:gosec // Walking a user-provided directory is intentional
+		if err != nil {
+			return err
+		}
+		data = append(data, "\n---\n"...)
+		data = append(data, content...)
+		return nil
+	})
+	return data, err
+}
+
+// validateGenerated parses and validates generated diagram source, so that
+// gen never prints a diagram the linter would reject.
+func validateGenerated(source string) error {
+	diagram, err := mermaid.Parse(source)
+	if err != nil {
+		return err
+	}
+	for _, e := range mermaid.Validate(diagram, false) {
+		if e.Severity == validator.SeverityError {
+			return fmt.Errorf("line %d: %s", e.Line, e.Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// hookMarker identifies a pre-commit hook written by `hook install`, so it can
+// be replaced without --force.
+const hookMarker = "# Installed by mermaid-check hook install"
+
+// preCommitHook is the git pre-commit hook written by `hook install`.
+const preCommitHook = `#!/bin/sh
+` + hookMarker + `
+exec mermaid-check --staged
+`
+
+// preCommitConfig is the .pre-commit-config.yaml entry printed by
+// `hook install --pre-commit-config`.
+const preCommitConfig = `repos:
+  - repo: https://github.com/sammcj/mermaid-check
+    rev: v` + version + `
+    hooks:
+      - id: mermaid-check
+`
+
+// readFile reads the files given on the command line. --staged replaces it
+// with stagedReader, so that what is validated is what will be committed.
+var readFile = os.ReadFile
+
+// runHook handles the `hook` subcommand.
+func runHook(args []string) int {
+	if len(args) == 0 || args[0] != "install" {
+		fmt.Fprintf(os.Stderr, "Usage: mermaid-check hook install [--force] [--pre-commit-config]\n")
+		return 1
+	}
+
+	fs := flag.NewFlagSet("hook install", flag.ContinueOnError)
+	force := fs.Bool("force", false, "replace an existing pre-commit hook")
+	printConfig := fs.Bool("pre-commit-config", false, "print a pre-commit framework configuration instead of installing a hook")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	if *printConfig {
+		fmt.Print(preCommitConfig)
+		return 0
+	}
+
+	path, err := installHook(*force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Installed pre-commit hook in %s\n", path)
+	return 0
+}
+
+// installHook writes the pre-commit hook into the current repository's hooks
+// directory, returning its path. An existing hook is only replaced if it was
+// written by installHook or force is set.
+func installHook(force bool) (string, error) {
+	hooksDir, err := git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(hooksDir, "pre-commit")
+
+	existing, err := os.ReadFile(path) //nolint:gosec // Path comes from git
+	switch {
+	case err == nil && !force && !bytes.Contains(existing, []byte(hookMarker)):
+		return "", fmt.Errorf("%s already exists; use --force to replace it", path)
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return "", err
+	}
+
+	if err := os.MkdirAll(hooksDir, 0o750); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(preCommitHook), 0o755); err != nil { //nolint:gosec // Hooks must be executable
+		return "", err
+	}
+	return path, nil
+}
+
+// stagedFiles returns the Markdown and Mermaid files with staged changes
+// (added, copied, modified or renamed) in the current repository, with their
+// staged content keyed by the returned path. Unstaged edits to those files
+// are not part of the commit, so they are not validated.
+func stagedFiles() ([]string, map[string][]byte, error) {
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil, err
+	}
+	output, err := git("diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []string
+	contents := make(map[string][]byte)
+	for name := range strings.SplitSeq(output, "\x00") {
+		if name == "" || inpututil.DetectFileType(name) == inpututil.FileTypeUnknown {
+			continue
+		}
+		// The index entry, not the working tree copy
+		content, err := gitOutput("cat-file", "blob", ":"+name)
+		if err != nil {
+			return nil, nil, err
+		}
+		path := filepath.Join(root, filepath.FromSlash(name))
+		// Report paths as the user would type them from the current directory
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			path = rel
+		}
+		files = append(files, path)
+		contents[path] = content
+	}
+	return files, contents, nil
+}
+
+// stagedReader returns a readFile that reads the staged content of the files
+// in contents, and other files from disk.
+func stagedReader(contents map[string][]byte) func(string) ([]byte, error) {
+	return func(path string) ([]byte, error) {
+		if content, ok := contents[path]; ok {
+			return content, nil
+		}
+		return os.ReadFile(path) //nolint:gosec // User-provided file path is intentional
+	}
+}
+
+// git runs a git command and returns its trimmed output.
+func git(args ...string) (string, error) {
+	output, err := gitOutput(args...)
+	return strings.TrimSpace(string(output)), err
+}
+
+// gitOutput runs a git command and returns its output unchanged.
+func gitOutput(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return output, nil
+}
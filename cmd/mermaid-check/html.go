@@ -0,0 +1,344 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// excerptContext is the number of lines shown either side of a finding in an
+// HTML report excerpt.
+const excerptContext = 2
+
+// maxChartBars caps the bars in each HTML report chart; the rest are summed
+// into an "other" bar.
+const maxChartBars = 10
+
+// htmlReport is the data rendered by htmlReportTemplate.
+type htmlReport struct {
+	Version  string
+	Files    []htmlFile
+	Diagrams int
+	Failing  int
+	Findings int
+	Charts   []htmlChart
+}
+
+// htmlFile is a validated file and its diagrams.
+type htmlFile struct {
+	Path     string
+	Diagrams []htmlDiagram
+	Findings int
+}
+
+// htmlDiagram is a diagram with its metadata, findings and source.
+type htmlDiagram struct {
+	Index     int
+	Type      string
+	StartLine int
+	EndLine   int
+	Metadata  [][2]string
+	Findings  []htmlFinding
+	Source    []htmlLine
+}
+
+// htmlFinding is a parse or validation error with an excerpt of the source
+// lines around it.
+type htmlFinding struct {
+	Line     int
+	Severity string
+	Rule     string
+	Message  string
+	Excerpt  []htmlLine
+}
+
+// htmlLine is a numbered source line, highlighted when a finding is on it.
+type htmlLine struct {
+	Number    int
+	Text      string
+	Highlight bool
+}
+
+// htmlChart is a horizontal bar chart in the report summary.
+type htmlChart struct {
+	Title string
+	Bars  []htmlBar
+}
+
+// htmlBar is one bar of an htmlChart, with Percent relative to the largest.
+type htmlBar struct {
+	Label   string
+	Count   int
+	Percent int
+	Class   string
+}
+
+// runHTMLReport validates paths (files or directories) and writes a single
+// self-contained HTML report of the results to stdout, returning 1 when any
+// diagram has findings.
+func runHTMLReport(paths []string, opts mermaid.ValidateOptions) int {
+	var results []mermaid.Result
+	for _, path := range paths {
+		pathResults, err := mermaid.ValidatePath(path, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		results = append(results, pathResults...)
+	}
+
+	report := buildHTMLReport(results)
+	if err := writeHTMLReport(os.Stdout, report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if report.Findings > 0 {
+		return 1
+	}
+	return 0
+}
+
+// buildHTMLReport groups results by file, reading each file again for the
+// source excerpts, and tallies the summary charts.
+func buildHTMLReport(results []mermaid.Result) htmlReport {
+	report := htmlReport{Version: version, Diagrams: len(results)}
+	sources := make(map[string][]string)
+	byType := make(map[string]int)
+	byRule := make(map[string]int)
+
+	for _, result := range results {
+		if len(report.Files) == 0 || report.Files[len(report.Files)-1].Path != result.File {
+			report.Files = append(report.Files, htmlFile{Path: result.File})
+		}
+		file := &report.Files[len(report.Files)-1]
+
+		lines, ok := sources[result.File]
+		if !ok {
+			if data, err := os.ReadFile(result.File); err == nil {
+				lines = strings.Split(inpututil.NormaliseNewlines(string(data)), "\n")
+			}
+			sources[result.File] = lines
+		}
+
+		diagram := htmlDiagram{
+			Index:     result.BlockIndex + 1,
+			Type:      diagramTypeDisplayName(result.DiagramType),
+			StartLine: result.LineOffset,
+			EndLine:   result.EndLine,
+			Source:    sourceLines(lines, result.LineOffset, result.EndLine),
+		}
+		for _, key := range sortedKeys(result.Metadata) {
+			diagram.Metadata = append(diagram.Metadata, [2]string{key, result.Metadata[key]})
+		}
+
+		if result.ParseError != nil {
+			// Explain reports every syntax error, not only the first
+			explanation := mermaid.Explain(joinLines(diagram.Source))
+			for _, err := range explanation.Errors {
+				finding := htmlFinding{Severity: "error", Rule: "syntax", Message: withSuggestion(err.Message, err.Suggestion)}
+				if err.Line > 0 {
+					finding.Line = result.LineOffset + err.Line - 1
+				}
+				diagram.Findings = append(diagram.Findings, finding)
+			}
+			if len(diagram.Findings) == 0 {
+				diagram.Findings = append(diagram.Findings, htmlFinding{Severity: "error", Rule: "syntax", Message: withSuggestion(result.ParseError.Error(), result.Suggestion)})
+			}
+		}
+		for _, err := range result.Errors {
+			diagram.Findings = append(diagram.Findings, htmlFinding{
+				Line:     result.LineOffset + err.Line - 1,
+				Severity: err.Severity.String(),
+				Rule:     err.Rule,
+				Message:  withSuggestion(err.Message, err.Suggestion),
+			})
+		}
+		for i := range diagram.Findings {
+			finding := &diagram.Findings[i]
+			if finding.Line > 0 {
+				finding.Excerpt = sourceLines(lines, max(finding.Line-excerptContext, result.LineOffset), min(finding.Line+excerptContext, result.EndLine))
+				for j := range finding.Excerpt {
+					finding.Excerpt[j].Highlight = finding.Excerpt[j].Number == finding.Line
+				}
+			}
+			byRule[cmp.Or(finding.Rule, "other")]++
+		}
+		for i := range diagram.Source {
+			diagram.Source[i].Highlight = slices.ContainsFunc(diagram.Findings, func(f htmlFinding) bool { return f.Line == diagram.Source[i].Number })
+		}
+
+		byType[diagram.Type]++
+		if len(diagram.Findings) > 0 {
+			report.Failing++
+			report.Findings += len(diagram.Findings)
+			file.Findings += len(diagram.Findings)
+		}
+		file.Diagrams = append(file.Diagrams, diagram)
+	}
+
+	report.Charts = []htmlChart{
+		barChart("Diagrams by status", map[string]int{"valid": report.Diagrams - report.Failing, "with findings": report.Failing}),
+		barChart("Diagrams by type", byType),
+	}
+	if len(byRule) > 0 {
+		report.Charts = append(report.Charts, barChart("Findings by rule", byRule))
+	}
+	return report
+}
+
+// sourceLines returns lines start to end (1-indexed, inclusive) of a file,
+// clamped to the lines it has.
+func sourceLines(lines []string, start, end int) []htmlLine {
+	start = max(start, 1)
+	end = min(end, len(lines))
+	var numbered []htmlLine
+	for n := start; n <= end; n++ {
+		numbered = append(numbered, htmlLine{Number: n, Text: lines[n-1]})
+	}
+	return numbered
+}
+
+// joinLines joins the text of numbered lines back into source.
+func joinLines(lines []htmlLine) string {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// barChart turns counts into a chart with the largest bars first, summing any
+// beyond maxChartBars into an "other" bar. Empty counts are left out.
+func barChart(title string, counts map[string]int) htmlChart {
+	var bars []htmlBar
+	for label, count := range counts {
+		if count > 0 {
+			bars = append(bars, htmlBar{Label: label, Count: count})
+		}
+	}
+	slices.SortFunc(bars, func(a, b htmlBar) int {
+		return cmp.Or(b.Count-a.Count, strings.Compare(a.Label, b.Label))
+	})
+	if len(bars) > maxChartBars {
+		other := htmlBar{Label: "other"}
+		for _, bar := range bars[maxChartBars-1:] {
+			other.Count += bar.Count
+		}
+		bars = append(bars[:maxChartBars-1], other)
+	}
+
+	largest := 0
+	for _, bar := range bars {
+		largest = max(largest, bar.Count)
+	}
+	for i := range bars {
+		bars[i].Percent = bars[i].Count * 100 / largest
+		if bars[i].Label == "with findings" {
+			bars[i].Class = "bad"
+		}
+	}
+	return htmlChart{Title: title, Bars: bars}
+}
+
+// writeHTMLReport renders report as a standalone HTML page.
+func writeHTMLReport(w io.Writer, report htmlReport) error {
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// htmlReportTemplate is a standalone page with inline styles and no scripts,
+// so that it can be archived as a CI artefact and opened anywhere.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="mermaid-check {{.Version}}">
+<title>Mermaid diagram check</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #1f2328; line-height: 1.4; }
+h1 { margin-top: 0; }
+.summary { font-size: 1.1rem; }
+.charts { display: flex; flex-wrap: wrap; gap: 2rem; margin: 1.5rem 0; }
+.chart { min-width: 18rem; flex: 1; }
+.chart h3 { font-size: 1rem; margin: 0 0 .5rem; }
+.bar-row { display: grid; grid-template-columns: 10rem 1fr 3rem; gap: .5rem; align-items: center; margin: .2rem 0; font-size: .9rem; }
+.bar { background: #2da44e; height: .9rem; border-radius: 2px; }
+.bar.bad { background: #cf222e; }
+.count { text-align: right; font-variant-numeric: tabular-nums; }
+.file { border: 1px solid #d0d7de; border-radius: 6px; margin: 1rem 0; padding: 0 1rem 1rem; }
+.file h2 { font-size: 1.1rem; font-family: ui-monospace, monospace; }
+.diagram { border-top: 1px solid #d0d7de; padding-top: .5rem; margin-top: .5rem; }
+.status { font-weight: bold; }
+.ok { color: #1a7f37; }
+.fail { color: #cf222e; }
+.meta { font-size: .85rem; color: #57606a; }
+.meta td { padding: 0 1rem 0 0; }
+.finding { margin: .5rem 0; }
+.severity-error { color: #cf222e; }
+.severity-warning { color: #9a6700; }
+.severity-info { color: #0969da; }
+.rule { font-family: ui-monospace, monospace; font-size: .85rem; color: #57606a; }
+pre { background: #f6f8fa; padding: .5rem; overflow-x: auto; margin: .25rem 0; font-size: .85rem; }
+.ln { display: inline-block; width: 3.5rem; color: #8c959f; user-select: none; }
+.hl { background: #ffebe9; }
+</style>
+</head>
+<body>
+<h1>Mermaid diagram check</h1>
+<p class="summary">{{if .Findings}}<span class="status fail">Found {{.Findings}} problem(s) in {{.Failing}} of {{.Diagrams}} diagram(s) across {{len .Files}} file(s).</span>{{else}}<span class="status ok">All {{.Diagrams}} diagram(s) in {{len .Files}} file(s) are valid.</span>{{end}}</p>
+<div class="charts">
+{{- range .Charts}}
+<div class="chart">
+<h3>{{.Title}}</h3>
+{{- range .Bars}}
+<div class="bar-row"><span>{{.Label}}</span><div class="bar{{if .Class}} {{.Class}}{{end}}" style="width: {{.Percent}}%"></div><span class="count">{{.Count}}</span></div>
+{{- end}}
+</div>
+{{- end}}
+</div>
+{{- range .Files}}
+<section class="file">
+<h2>{{.Path}}</h2>
+{{- range .Diagrams}}
+<div class="diagram">
+<p><span class="status {{if .Findings}}fail{{else}}ok{{end}}">{{if .Findings}}✗{{else}}✓{{end}}</span> #{{.Index}} {{.Type}} <span class="meta">lines {{.StartLine}}-{{.EndLine}}</span></p>
+{{- if .Metadata}}
+<table class="meta">{{range .Metadata}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>{{end}}</table>
+{{- end}}
+{{- range .Findings}}
+<div class="finding">
+<div><span class="severity-{{.Severity}}">{{.Severity}}</span>{{if .Line}} line {{.Line}}{{end}} <span class="rule">{{.Rule}}</span>: {{.Message}}</div>
+{{- if .Excerpt}}
+<pre>{{range .Excerpt}}<span{{if .Highlight}} class="hl"{{end}}><span class="ln">{{.Number}}</span>{{.Text}}
+</span>{{end}}</pre>
+{{- end}}
+</div>
+{{- end}}
+<details><summary>Source</summary>
+<pre>{{range .Source}}<span{{if .Highlight}} class="hl"{{end}}><span class="ln">{{.Number}}</span>{{.Text}}
+</span>{{end}}</pre>
+</details>
+</div>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
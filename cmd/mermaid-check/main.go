@@ -2,20 +2,36 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	mermaid "github.com/sammcj/mermaid-check"
 	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/config"
+	"github.com/sammcj/mermaid-check/export"
 	"github.com/sammcj/mermaid-check/extractor"
 	"github.com/sammcj/mermaid-check/internal/inpututil"
+	"github.com/sammcj/mermaid-check/mcpserver"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
 )
 
-const version = "0.1.0"
+const version = mermaid.Version
+
+// defaultBaselineFile is where `baseline create` writes when --baseline is not given.
+const defaultBaselineFile = ".mermaid-check-baseline.json"
+
+// nearDuplicateThreshold is the similarity at which --detect-duplicates reports two diagrams.
+const nearDuplicateThreshold = 0.8
 
 var (
 	// Colour definitions for clean, modern output
@@ -31,11 +47,25 @@ var (
 func main() {
 	// Define flags
 	var (
-		strict       = flag.Bool("strict", false, "use strict validation rules")
-		formatFlag   = flag.String("format", "", "force input format (mermaid or markdown)")
-		errorOnEmpty = flag.Bool("error-on-empty", false, "treat files with no Mermaid diagrams as errors")
-		showHelp     = flag.Bool("help", false, "show help message")
-		showVersion  = flag.Bool("version", false, "show version")
+		strict             = flag.Bool("strict", false, "use strict validation rules")
+		formatFlag         = flag.String("format", "", "force input format (mermaid or markdown)")
+		errorOnEmpty       = flag.Bool("error-on-empty", false, "treat files with no Mermaid diagrams as errors")
+		requireAnnotations = flag.String("require-annotations", "", "comma-separated annotation keys every diagram must define (e.g. owner,id)")
+		checkReferences    = flag.Bool("check-references", false, "check links between diagrams (#id hrefs) across all given files")
+		checkClasses       = flag.Bool("check-class-consistency", false, "check class diagrams in the same file against each other")
+		checkSequences     = flag.Bool("check-sequence-consistency", false, "check sequence diagram participants against the nearest flowchart or C4 diagram in the same file")
+		detectDuplicates   = flag.Bool("detect-duplicates", false, "report identical or near-identical diagrams across all given files")
+		maxErrors          = flag.Int("max-errors-per-diagram", 0, "list at most this many errors for each diagram (0 for no limit)")
+		profile            = flag.Bool("profile", false, "report parse and validation times per diagram and per rule")
+		configPath         = flag.String("config", "", "configuration file (default: nearest "+config.FileName+")")
+		baselinePath       = flag.String("baseline", "", "only report errors not recorded in this baseline file")
+		staged             = flag.Bool("staged", false, "validate the Markdown and Mermaid files staged in git")
+		output             = flag.String("output", "text", "output format (text or html)")
+		fenceLanguages     = flag.String("fence-languages", "", "comma-separated code fence languages treated as Mermaid (default: mermaid,mmd,mermaidjs,{mermaid})")
+		renderer           = flag.String("renderer", "", "report constructs this renderer can't display ("+strings.Join(validator.RendererNames(), ", ")+")")
+		sourceComments     = flag.Bool("source-comments", false, "also validate diagrams in Go, JavaScript, TypeScript and Python comments found in directories")
+		showHelp           = flag.Bool("help", false, "show help message")
+		showVersion        = flag.Bool("version", false, "show version")
 	)
 
 	flag.Parse()
@@ -50,29 +80,173 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Determine input source
+	opts := mermaid.ValidateOptions{
+		Strict:                   *strict,
+		RequiredAnnotations:      splitList(*requireAnnotations),
+		CheckReferences:          *checkReferences,
+		CheckClassConsistency:    *checkClasses,
+		CheckSequenceConsistency: *checkSequences,
+		FenceLanguages:           splitList(*fenceLanguages),
+		SourceComments:           *sourceComments,
+		Renderer:                 *renderer,
+	}
+	if err := applyConfig(*configPath, &opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if opts.Renderer != "" {
+		if _, err := validator.ForRenderer(opts.Renderer); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	args := flag.Args()
-	var exitCode int
+	if len(args) > 0 {
+		if run, ok := commands(opts, *baselinePath)[args[0]]; ok {
+			os.Exit(run(args[1:]))
+		}
+	}
+
+	if err := loadBaseline(*baselinePath, &opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+		os.Exit(1)
+	}
+	if !slices.Contains([]string{"text", "html"}, *output) {
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format %q\n", *output)
+		os.Exit(1)
+	}
+
+	os.Exit(runValidate(args, opts, validateFlags{
+		format:           *formatFlag,
+		errorOnEmpty:     *errorOnEmpty,
+		detectDuplicates: *detectDuplicates,
+		maxErrors:        *maxErrors,
+		profile:          *profile,
+		staged:           *staged,
+		output:           *output,
+	}))
+}
+
+// commands returns the subcommands by name. Each takes the arguments after
+// its name and returns the exit code.
+func commands(opts mermaid.ValidateOptions, baselinePath string) map[string]func([]string) int {
+	// withBaseline loads --baseline before running a command that validates
+	withBaseline := func(run func([]string, mermaid.ValidateOptions) int) func([]string) int {
+		return func(args []string) int {
+			if err := loadBaseline(baselinePath, &opts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+				return 1
+			}
+			return run(args, opts)
+		}
+	}
+	return map[string]func([]string) int{
+		"hook":         runHook,
+		"new":          runNew,
+		"mcp":          runMCP,
+		"baseline":     func(args []string) int { return runBaseline(args, baselinePath, opts) },
+		"report":       withBaseline(runReport),
+		"fix":          withBaseline(runFix),
+		"export":       withBaseline(runExport),
+		"gen":          runGen,
+		"render":       withBaseline(runRender),
+		"capabilities": runCapabilities,
+	}
+}
+
+// runMCP serves the MCP tools over stdio.
+func runMCP([]string) int {
+	if err := mcpserver.New(version).Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runBaseline runs `baseline create`, writing to output, or to
+// defaultBaselineFile when output is empty.
+func runBaseline(args []string, output string, opts mermaid.ValidateOptions) int {
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Fprintf(os.Stderr, "Usage: mermaid-check [flags] baseline create <file or directory>...\n")
+		return 1
+	}
+	if output == "" {
+		output = defaultBaselineFile
+	}
+	return createBaseline(args[1:], output, opts)
+}
+
+// loadBaseline sets opts.Baseline from the baseline file at path, if any.
+func loadBaseline(path string, opts *mermaid.ValidateOptions) error {
+	if path == "" {
+		return nil
+	}
+	baseline, err := mermaid.LoadBaseline(path)
+	if err != nil {
+		return err
+	}
+	opts.Baseline = baseline
+	return nil
+}
+
+// validateFlags are the command-line flags that control a validation run.
+type validateFlags struct {
+	format           string
+	errorOnEmpty     bool
+	detectDuplicates bool
+	maxErrors        int
+	profile          bool
+	staged           bool
+	output           string
+}
+
+// runValidate validates the files in args, or stdin when there are none, and
+// returns the exit code.
+func runValidate(args []string, opts mermaid.ValidateOptions, flags validateFlags) int {
+	if flags.staged {
+		files, contents, err := stagedFiles()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing staged files: %v\n", err)
+			return 1
+		}
+		if len(files) == 0 {
+			return 0
+		}
+		args = append(args, files...)
+		readFile = stagedReader(contents)
+	}
+
+	if flags.output == "html" {
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --output html needs files or directories to validate\n")
+			return 1
+		}
+		return runHTMLReport(args, opts)
+	}
 
 	if len(args) == 0 {
-		// Read from stdin
-		exitCode = processStdin(*formatFlag, *strict, *errorOnEmpty)
-	} else {
-		// Process files
-		exitCode = processFiles(args, *strict, *errorOnEmpty)
+		return processStdin(flags.format, opts, flags.errorOnEmpty, flags.maxErrors)
 	}
 
-	os.Exit(exitCode)
+	exitCode, diagrams := processFiles(args, opts, flags.errorOnEmpty, flags.maxErrors, flags.profile)
+	if flags.detectDuplicates {
+		printDuplicates(diagrams)
+	}
+	if flags.profile {
+		printProfile(diagrams)
+	}
+	return exitCode
 }
 
-func processStdin(format string, strict bool, errorOnEmpty bool) int {
+func processStdin(format string, opts mermaid.ValidateOptions, errorOnEmpty bool, maxErrors int) int {
 	data, err := io.ReadAll(os.Stdin)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
 		return 1
 	}
 
-	content := string(data)
+	content := inpututil.NormaliseNewlines(string(data))
 
 	// Determine format
 	isMarkdown := format == "markdown"
@@ -83,12 +257,22 @@ func processStdin(format string, strict bool, errorOnEmpty bool) int {
 
 	var hasErrors bool
 
-	if isMarkdown {
+	// Raw Mermaid holding several diagrams is validated block by block, like markdown
+	var blocks []extractor.DiagramBlock
+	if !isMarkdown {
+		if split := extractor.SplitMermaid(content); len(split) > 1 {
+			blocks = split
+		}
+	}
+
+	if isMarkdown || blocks != nil {
 		// Extract and validate Mermaid blocks from markdown
-		blocks, err := extractor.ExtractFromMarkdown(content)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error extracting Mermaid blocks: %v\n", err)
-			return 1
+		if isMarkdown {
+			blocks, err = extractor.ExtractFromMarkdownLanguages(content, opts.FenceLanguages...)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error extracting Mermaid blocks: %v\n", err)
+				return 1
+			}
 		}
 
 		if len(blocks) == 0 {
@@ -108,7 +292,7 @@ func processStdin(format string, strict bool, errorOnEmpty bool) int {
 			displayName := diagramTypeDisplayName(block.DiagramType)
 			fmt.Printf("\n--- Diagram %d - %s (%s, line %d) ---\n", i+1, displayName, block.DiagramType, block.LineOffset)
 			stats[block.DiagramType]++
-			if processBlock(&block, strict) {
+			if processBlock(&block, opts, maxErrors) {
 				hasErrors = true
 			}
 		}
@@ -131,7 +315,7 @@ func processStdin(format string, strict bool, errorOnEmpty bool) int {
 		diagramType := diagram.GetType()
 		displayName := diagramTypeDisplayName(diagramType)
 		fmt.Printf("Diagram type: %s (%s)\n", displayName, diagramType)
-		if validateDiagram(diagram, strict, "") {
+		if validateDiagram(diagram, opts, "", maxErrors) {
 			hasErrors = true
 		}
 	}
@@ -157,7 +341,8 @@ type blockResult struct {
 	diagramType string
 	lineRange   string
 	isValid     bool
-	errors      []string
+	parseErrors []string
+	errors      []validator.ValidationError
 	blockNum    int
 }
 
@@ -172,9 +357,45 @@ const (
 	resultUnsupportedType
 )
 
-func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
+// createBaseline records the current validation errors in paths (files or
+// directories) to output, so later runs with --baseline only report new ones.
+func createBaseline(paths []string, output string, opts mermaid.ValidateOptions) int {
+	if len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: mermaid-check [flags] baseline create <file or directory>...\n")
+		return 1
+	}
+
+	// Reference errors are reported separately and never baselined
+	opts.CheckReferences = false
+
+	var results []mermaid.Result
+	for _, path := range paths {
+		pathResults, err := mermaid.ValidatePath(path, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		results = append(results, pathResults...)
+	}
+
+	baseline := mermaid.NewBaseline(results, filepath.Dir(output))
+	if err := baseline.Save(output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Recorded %d finding(s) in %s\n", len(baseline.Findings), output)
+	return 0
+}
+
+// processFiles validates and reports every diagram in paths, returning the
+// exit code and a result for each diagram, with its metrics when profile is
+// set. The results come from the same pass, so --detect-duplicates and
+// --profile describe exactly the content that was validated.
+func processFiles(paths []string, opts mermaid.ValidateOptions, errorOnEmpty bool, maxErrors int, profile bool) (int, []mermaid.Result) {
 	var hasErrors bool
+	var suppressed int
 	results := make([]fileResult, 0, len(paths))
+	var diagrams []mermaid.Result
 
 	// Collect all results first
 	for _, path := range paths {
@@ -184,7 +405,7 @@ func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
 		}
 
 		// Read file content
-		data, err := os.ReadFile(path)
+		data, err := readFile(path)
 		if err != nil {
 			result.resultType = resultFileError
 			result.errorMsg = err.Error()
@@ -193,24 +414,53 @@ func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
 			continue
 		}
 
-		content := string(data)
+		content := inpututil.NormaliseNewlines(string(data))
 		fileType := inpututil.DetectFileType(path)
+		disabled := opts.DisabledRules(path)
+		var known *mermaid.FileBaseline
+		if opts.Baseline != nil {
+			known = opts.Baseline.ForFile(path)
+		}
 
 		// Check if .mmd file contains markdown code fences
 		if fileType == inpututil.FileTypeMermaid && containsMarkdownFences(content) {
 			fileType = inpututil.FileTypeMarkdown
 		}
 
+		// A .mmd file holding several diagrams is reported block by block, like markdown
+		var blocks []extractor.DiagramBlock
+		var single extractor.DiagramBlock
+		if fileType == inpututil.FileTypeMermaid {
+			if split := extractor.SplitMermaid(content); len(split) > 1 {
+				blocks = split
+				fileType = inpututil.FileTypeMarkdown
+			} else {
+				single = split[0]
+			}
+		}
+
+		// Diagrams in source code comments are reported block by block, like markdown
+		syntax, isSource := extractor.CommentSyntaxFor(path)
+		if fileType == inpututil.FileTypeUnknown && isSource {
+			fileType = inpututil.FileTypeMarkdown
+		}
+
 		switch fileType {
 		case inpututil.FileTypeMarkdown:
 			// Extract blocks from markdown to preserve line information
-			blocks, err := extractor.ExtractFromMarkdown(content)
-			if err != nil {
-				result.resultType = resultParseError
-				result.errorMsg = err.Error()
-				results = append(results, result)
-				hasErrors = true
-				continue
+			if blocks == nil {
+				if isSource {
+					blocks, err = extractor.ExtractFromComments(content, syntax, opts.FenceLanguages...)
+				} else {
+					blocks, err = extractor.ExtractFromMarkdownLanguages(content, opts.FenceLanguages...)
+				}
+				if err != nil {
+					result.resultType = resultParseError
+					result.errorMsg = err.Error()
+					results = append(results, result)
+					hasErrors = true
+					continue
+				}
 			}
 
 			if len(blocks) == 0 {
@@ -237,23 +487,37 @@ func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
 					blockNum:    i + 1,
 				}
 
-				diagram, err := mermaid.Parse(block.Source)
+				checked := checkDiagram(path, i, block, opts, profile)
+				diagram, err := checked.Diagram, checked.ParseError
+				if err != nil && known != nil && known.FilterParseError(block.Source, err) == nil {
+					checked.ParseError = nil
+					diagrams = append(diagrams, checked)
+					blockRes.isValid = true
+					result.blocks = append(result.blocks, blockRes)
+					continue
+				}
 				if err != nil {
+					diagrams = append(diagrams, checked)
 					blockRes.isValid = false
-					blockRes.errors = []string{fmt.Sprintf("parse error: %v", err)}
+					for _, msg := range parseErrorMessages(block.Source) {
+						blockRes.parseErrors = append(blockRes.parseErrors, msg)
+					}
 					result.blocks = append(result.blocks, blockRes)
 					hasValidationErrors = true
 					continue
 				}
 
-				validationErrors := mermaid.Validate(diagram, strict)
+				validationErrors := validator.WithoutRules(checked.Errors, disabled...)
+				if known != nil {
+					validationErrors = known.Filter(diagram, validationErrors)
+				}
+				checked.Errors = validationErrors
+				diagrams = append(diagrams, checked)
 				if len(validationErrors) == 0 {
 					blockRes.isValid = true
 				} else {
 					blockRes.isValid = false
-					for _, ve := range validationErrors {
-						blockRes.errors = append(blockRes.errors, ve.Error())
-					}
+					blockRes.errors = validationErrors
 					hasValidationErrors = true
 				}
 
@@ -284,10 +548,24 @@ func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
 			}
 
 			// Parse as raw Mermaid
-			diagram, err := mermaid.Parse(content)
+			checked := checkDiagram(path, 0, single, opts, profile)
+			diagram, err := checked.Diagram, checked.ParseError
+			if err != nil && known != nil && known.FilterParseError(content, err) == nil {
+				checked.ParseError = nil
+				diagrams = append(diagrams, checked)
+				diagramType := parser.DetectType(content)
+				result.resultType = resultSuccess
+				result.stats = map[string]int{diagramType: 1}
+				result.diagramCount = 1
+				result.blocks = append(result.blocks, blockResult{diagramType: diagramType, isValid: true, blockNum: 1})
+				suppressed += known.Suppressed
+				results = append(results, result)
+				continue
+			}
 			if err != nil {
+				diagrams = append(diagrams, checked)
 				result.resultType = resultParseError
-				result.errorMsg = err.Error()
+				result.errorMsg = strings.Join(parseErrorMessages(content), "; ")
 				results = append(results, result)
 				hasErrors = true
 				continue
@@ -302,15 +580,18 @@ func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
 				blockNum:    1,
 			}
 
-			validationErrors := mermaid.Validate(diagram, strict)
+			validationErrors := validator.WithoutRules(checked.Errors, disabled...)
+			if known != nil {
+				validationErrors = known.Filter(diagram, validationErrors)
+			}
+			checked.Errors = validationErrors
+			diagrams = append(diagrams, checked)
 			if len(validationErrors) == 0 {
 				blockRes.isValid = true
 				result.resultType = resultSuccess
 			} else {
 				blockRes.isValid = false
-				for _, ve := range validationErrors {
-					blockRes.errors = append(blockRes.errors, ve.Error())
-				}
+				blockRes.errors = validationErrors
 				result.resultType = resultValidationError
 				hasErrors = true
 			}
@@ -324,19 +605,136 @@ func processFiles(paths []string, strict bool, errorOnEmpty bool) int {
 			continue
 		}
 
+		if known != nil {
+			suppressed += known.Suppressed
+		}
 		results = append(results, result)
 	}
 
 	// Output results grouped by type
-	printGroupedResults(results, errorOnEmpty)
+	printGroupedResults(results, errorOnEmpty, maxErrors)
+	if suppressed > 0 {
+		fmt.Printf("\n%s\n", dim(fmt.Sprintf("%d known error(s) hidden by the baseline", suppressed)))
+	}
+
+	if opts.CheckReferences && printReferenceErrors(diagrams) {
+		hasErrors = true
+	}
+	if opts.CheckClassConsistency && printClassConsistencyErrors(diagrams) {
+		hasErrors = true
+	}
+	if opts.CheckSequenceConsistency && printSequenceConsistencyErrors(diagrams) {
+		hasErrors = true
+	}
 
 	if hasErrors {
-		return 1
+		return 1, diagrams
+	}
+	return 0, diagrams
+}
+
+// checkDiagram parses and validates block, diagram index of the file at path,
+// with metrics when profile is set. The result's errors are not yet filtered
+// by path rules or the baseline.
+func checkDiagram(path string, index int, block extractor.DiagramBlock, opts mermaid.ValidateOptions, profile bool) mermaid.Result {
+	result := mermaid.Result{
+		File:        path,
+		BlockIndex:  index,
+		DiagramType: block.DiagramType,
+		LineOffset:  block.LineOffset,
+		EndLine:     block.EndLine,
+		Source:      block.Source,
+		Metadata:    parser.ExtractMetadata(block.Source),
+	}
+	diagram, metrics, err := mermaid.ParseWithMetrics(block.Source)
+	if profile {
+		result.Metrics = &metrics
+	}
+	if err != nil {
+		result.ParseError = err
+		return result
+	}
+	result.Diagram = diagram
+	result.DiagramType = diagram.GetType()
+	if profile {
+		var validation mermaid.Metrics
+		result.Errors, validation = mermaid.ProfileWith(diagram, opts)
+		result.Metrics.Validate, result.Metrics.Rules = validation.Validate, validation.Rules
+	} else {
+		result.Errors = validate(diagram, opts)
+	}
+	return result
+}
+
+// printReferenceErrors checks cross-diagram references across results and
+// prints any problems, returning true if there were any.
+func printReferenceErrors(results []mermaid.Result) bool {
+	refErrors := mermaid.CheckReferences(results)
+	if len(refErrors) == 0 {
+		return false
+	}
+
+	fmt.Printf("\n%s\n", bold(red("Cross-diagram references:")))
+	for _, e := range refErrors {
+		fmt.Printf("  %s %s\n", red("✗"), e.Error())
+	}
+	return true
+}
+
+// printClassConsistencyErrors checks the class diagrams within each file of
+// results against each other and prints any conflicts, returning true if there
+// were any.
+func printClassConsistencyErrors(results []mermaid.Result) bool {
+	classErrors := mermaid.CheckClassConsistency(results)
+	if len(classErrors) == 0 {
+		return false
+	}
+
+	fmt.Printf("\n%s\n", bold(red("Class diagram consistency:")))
+	for _, e := range classErrors {
+		fmt.Printf("  %s %s\n", red("✗"), e.Error())
+	}
+	return true
+}
+
+// printSequenceConsistencyErrors checks the sequence diagrams within each file
+// of results against the architecture diagrams they describe and prints any
+// mismatches, returning true if there were any.
+func printSequenceConsistencyErrors(results []mermaid.Result) bool {
+	sequenceErrors := mermaid.CheckSequenceConsistency(results)
+	if len(sequenceErrors) == 0 {
+		return false
+	}
+
+	fmt.Printf("\n%s\n", bold(red("Sequence diagram consistency:")))
+	for _, e := range sequenceErrors {
+		fmt.Printf("  %s %s\n", red("✗"), e.Error())
+	}
+	return true
+}
+
+// printDuplicates reports groups of identical or near-identical diagrams.
+// Duplicates are informational and don't affect the exit code.
+func printDuplicates(results []mermaid.Result) {
+	groups := mermaid.FindDuplicates(results, nearDuplicateThreshold)
+	if len(groups) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", orange("Duplicate diagrams:"))
+	for _, group := range groups {
+		summary := "identical"
+		if !group.Identical {
+			summary = fmt.Sprintf("%.0f%% similar", group.Similarity*100)
+		}
+		fmt.Printf("  %s %s %s\n", orange("⚠"), diagramTypeDisplayName(group.Results[0].DiagramType), dim("("+summary+")"))
+		for _, r := range group.Results {
+			fmt.Printf("    %s (L%d-L%d)\n", r.File, r.LineOffset, r.EndLine)
+		}
 	}
-	return 0
 }
 
-func printGroupedResults(results []fileResult, errorOnEmpty bool) {
+func printGroupedResults(results []fileResult, errorOnEmpty bool, maxErrors int) {
 	// Group results by type
 	noDiagramsInfo := make([]fileResult, 0)  // informational (markdown with no diagrams)
 	noDiagramsError := make([]fileResult, 0) // errors (empty .mmd files)
@@ -444,10 +842,7 @@ func printGroupedResults(results []fileResult, errorOnEmpty bool) {
 				if block.isValid {
 					fmt.Printf("%s%s %s\n", prefix, green("✓"), dim("Valid"))
 				} else {
-					fmt.Printf("%s%s %s:\n", prefix, red("✗"), red(fmt.Sprintf("%d validation error(s)", len(block.errors))))
-					for _, errMsg := range block.errors {
-						fmt.Printf("%s  %s\n", prefix, yellow(errMsg))
-					}
+					printDiagramErrors(prefix, block.parseErrors, block.errors, maxErrors)
 				}
 			}
 
@@ -462,30 +857,307 @@ func printGroupedResults(results []fileResult, errorOnEmpty bool) {
 	}
 }
 
-func processBlock(block *extractor.DiagramBlock, strict bool) bool {
+func processBlock(block *extractor.DiagramBlock, opts mermaid.ValidateOptions, maxErrors int) bool {
 	diagram, err := mermaid.Parse(block.Source)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Parse error: %v\n", err)
+		for _, msg := range parseErrorMessages(block.Source) {
+			fmt.Fprintf(os.Stderr, "Parse error: %s\n", msg)
+		}
 		return true
 	}
 
-	return validateDiagram(diagram, strict, "")
+	return validateDiagram(diagram, opts, "", maxErrors)
+}
+
+// parseErrorMessages re-parses source in recovery mode so that every syntax
+// error in a diagram is reported, not just the first.
+func parseErrorMessages(source string) []string {
+	_, syntaxErrors := mermaid.ParseRecovering(source)
+	messages := make([]string, 0, len(syntaxErrors))
+	for _, se := range syntaxErrors {
+		messages = append(messages, withSuggestion(se.Error(), se.Suggestion))
+	}
+	return messages
+}
+
+// withSuggestion appends a "did you mean" hint to an error message.
+func withSuggestion(message, suggestion string) string {
+	if suggestion == "" {
+		return message
+	}
+	return message + " (hint: " + suggestion + ")"
+}
+
+// validate runs the rules selected by opts against diagram.
+func validate(diagram ast.Diagram, opts mermaid.ValidateOptions) []validator.ValidationError {
+	return mermaid.ValidateWith(diagram, opts)
+}
+
+// applyConfig loads the configuration file at path, or the nearest
+// .mermaid-check.yaml when path is empty, and merges it into opts. Settings
+// given on the command line take precedence.
+func applyConfig(path string, opts *mermaid.ValidateOptions) error {
+	if path == "" {
+		found, err := config.Find(".")
+		if err != nil || found == "" {
+			return err
+		}
+		path = found
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	flagStrict := opts.Strict
+	applyGeneralConfig(cfg, opts)
+	sections := []func(*config.Config, *mermaid.ValidateOptions) error{
+		applySpellCheckConfig,
+		applyLabelLengthConfig,
+		applyNamingConfig,
+		applySelfLoopsConfig,
+		applyRuleConfig,
+		func(cfg *config.Config, opts *mermaid.ValidateOptions) error {
+			return applyTypeConfig(cfg, opts, flagStrict)
+		},
+	}
+	for _, apply := range sections {
+		if err := apply(cfg, opts); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// applyGeneralConfig merges the settings that need no checking into opts.
+func applyGeneralConfig(cfg *config.Config, opts *mermaid.ValidateOptions) {
+	opts.Strict = opts.Strict || cfg.Strict
+	if len(opts.RequiredAnnotations) == 0 {
+		opts.RequiredAnnotations = cfg.RequiredAnnotations
+	}
+	if len(opts.FenceLanguages) == 0 {
+		opts.FenceLanguages = cfg.FenceLanguages
+	}
+	opts.SourceComments = opts.SourceComments || cfg.SourceComments
+	opts.ParticipantsDeclaredFirst = opts.ParticipantsDeclaredFirst || cfg.ParticipantsDeclaredFirst
+	if opts.Renderer == "" {
+		opts.Renderer = cfg.Renderer
+	}
+
+	for _, term := range cfg.Terminology {
+		opts.Terminology = append(opts.Terminology, validator.Term{Avoid: term.Avoid, Prefer: term.Prefer})
+	}
+
+	for _, override := range cfg.Overrides {
+		rules := mermaid.PathRules{Disable: override.Disable}
+		for _, pattern := range override.Paths {
+			rules.Paths = append(rules.Paths, cfg.Resolve(pattern))
+		}
+		opts.PathRules = append(opts.PathRules, rules)
+	}
+}
+
+// applySpellCheckConfig enables the spell-check rule with the configured
+// words and dictionaries.
+func applySpellCheckConfig(cfg *config.Config, opts *mermaid.ValidateOptions) error {
+	if !cfg.SpellCheck.Enabled {
+		return nil
+	}
+	checker := validator.NewWordlistChecker(cfg.SpellCheck.Words...)
+	for _, dictionary := range cfg.SpellCheck.Dictionaries {
+		if err := checker.AddFile(cfg.Resolve(dictionary)); err != nil {
+			return fmt.Errorf("spell-check dictionary: %w", err)
+		}
+	}
+	opts.SpellChecker = checker
+	return nil
+}
+
+// applyLabelLengthConfig sets the label length limits per diagram type.
+func applyLabelLengthConfig(cfg *config.Config, opts *mermaid.ValidateOptions) error {
+	for diagramType, limit := range cfg.LabelLength {
+		switch diagramType {
+		case "flowchart", "sequence":
+		default:
+			return fmt.Errorf("label length limits are not supported for diagram type %q", diagramType)
+		}
+		if opts.LabelLengths == nil {
+			opts.LabelLengths = make(map[string]int)
+		}
+		opts.LabelLengths[diagramType] = limit
+	}
+	return nil
+}
+
+// applyNamingConfig sets the naming conventions per diagram type.
+func applyNamingConfig(cfg *config.Config, opts *mermaid.ValidateOptions) error {
+	for diagramType, convention := range cfg.Naming {
+		switch diagramType {
+		case "flowchart", "sequence", "class", "state":
+		default:
+			return fmt.Errorf("naming conventions are not supported for diagram type %q", diagramType)
+		}
+		pattern, err := validator.NamingPattern(convention)
+		if err != nil {
+			return fmt.Errorf("naming convention for %s: %w", diagramType, err)
+		}
+		if opts.NamingConventions == nil {
+			opts.NamingConventions = make(map[string]*regexp.Regexp)
+		}
+		opts.NamingConventions[diagramType] = pattern
+	}
+	return nil
+}
+
+// applySelfLoopsConfig configures the self-loops rule per diagram type.
+func applySelfLoopsConfig(cfg *config.Config, opts *mermaid.ValidateOptions) error {
+	for diagramType, level := range cfg.SelfLoops {
+		switch diagramType {
+		case "flowchart", "state", "class", "c4":
+		default:
+			return fmt.Errorf("self-loop checks are not supported for diagram type %q", diagramType)
+		}
+		if opts.SelfLoops == nil {
+			opts.SelfLoops = &validator.SelfLoops{Severities: make(map[string]validator.Severity)}
+		}
+		if level == "off" {
+			opts.SelfLoops.Disabled = append(opts.SelfLoops.Disabled, diagramType)
+			continue
+		}
+		severity, err := validator.ParseSeverity(level)
+		if err != nil {
+			return fmt.Errorf("self-loops for %s: %w", diagramType, err)
+		}
+		opts.SelfLoops.Severities[diagramType] = severity
+	}
+	return nil
+}
+
+// applyRuleConfig configures the icon catalogue, SQL dialect, indentation
+// and rule options.
+func applyRuleConfig(cfg *config.Config, opts *mermaid.ValidateOptions) error {
+	if cfg.Icons != nil {
+		icons := append(slices.Clone(validator.DefaultIcons), cfg.Icons.Known...)
+		if cfg.Icons.Replace {
+			icons = append([]string{}, cfg.Icons.Known...)
+		}
+		opts.Icons = &validator.KnownIcons{Icons: icons}
+	}
+
+	if cfg.SQLDialect != "" {
+		dialect, err := export.ParseDialect(cfg.SQLDialect)
+		if err != nil {
+			return err
+		}
+		opts.SQLDialect = dialect
+	}
+
+	if cfg.Indentation != "" {
+		style, err := validator.ParseIndentStyle(cfg.Indentation)
+		if err != nil {
+			return err
+		}
+		opts.Indentation = style
+	}
+
+	configured, err := cfg.ConfiguredRules()
+	if err != nil {
+		return err
+	}
+	opts.ConfiguredRules = append(opts.ConfiguredRules, configured...)
+	return nil
 }
 
-func validateDiagram(diagram ast.Diagram, strict bool, prefix string) bool {
-	errors := mermaid.Validate(diagram, strict)
+// applyTypeConfig sets the options per diagram kind. flagStrict is whether
+// --strict was given, which applies to every kind.
+func applyTypeConfig(cfg *config.Config, opts *mermaid.ValidateOptions, flagStrict bool) error {
+	for kind, typeConfig := range cfg.Types {
+		typeOptions := mermaid.TypeOptions{RequiredAnnotations: typeConfig.RequiredAnnotations}
+		if !flagStrict {
+			typeOptions.Strict = typeConfig.Strict
+		}
+		var err error
+		if typeOptions.ConfiguredRules, err = typeConfig.ConfiguredRules(); err != nil {
+			return fmt.Errorf("%s: %w", kind, err)
+		}
+		if opts.Types == nil {
+			opts.Types = make(map[string]mermaid.TypeOptions)
+		}
+		opts.Types[kind] = typeOptions
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func validateDiagram(diagram ast.Diagram, opts mermaid.ValidateOptions, prefix string, maxErrors int) bool {
+	errors := validate(diagram, opts)
 
 	if len(errors) == 0 {
 		fmt.Printf("%s%s %s\n", prefix, green("✓"), dim("Valid"))
 		return false
 	}
 
-	fmt.Printf("%s%s %s:\n", prefix, red("✗"), red(fmt.Sprintf("%d validation error(s)", len(errors))))
+	printDiagramErrors(prefix, nil, errors, maxErrors)
+	return true
+}
+
+// printDiagramErrors lists a diagram's parse errors and then its validation
+// errors grouped by rule, with the rules in the order of their first error.
+// At most maxErrors errors are listed when it is positive.
+func printDiagramErrors(prefix string, parseErrors []string, errors []validator.ValidationError, maxErrors int) {
+	total := len(parseErrors) + len(errors)
+	fmt.Printf("%s%s %s:\n", prefix, red("✗"), red(fmt.Sprintf("%d validation error(s)", total)))
+
+	listed := 0
+	full := func() bool { return maxErrors > 0 && listed >= maxErrors }
+	for _, msg := range parseErrors {
+		if full() {
+			break
+		}
+		fmt.Printf("%s  %s\n", prefix, yellow("parse error: "+msg))
+		listed++
+	}
+
+	var rules []string
+	byRule := make(map[string][]validator.ValidationError)
 	for _, err := range errors {
-		fmt.Printf("%s  %s\n", prefix, yellow(fmt.Sprintf("%v", err)))
+		if _, ok := byRule[err.Rule]; !ok {
+			rules = append(rules, err.Rule)
+		}
+		byRule[err.Rule] = append(byRule[err.Rule], err)
+	}
+	for _, rule := range rules {
+		if full() {
+			break
+		}
+		name := rule
+		if name == "" {
+			name = "other"
+		}
+		fmt.Printf("%s  %s %s\n", prefix, bold(name), dim(fmt.Sprintf("(%d)", len(byRule[rule]))))
+		for _, err := range byRule[rule] {
+			if full() {
+				break
+			}
+			fmt.Printf("%s    %s\n", prefix, yellow(withSuggestion(err.Error(), err.Suggestion)))
+			listed++
+		}
 	}
 
-	return true
+	if listed < total {
+		fmt.Printf("%s  %s\n", prefix, dim(fmt.Sprintf("…and %d more", total-listed)))
+	}
 }
 
 func containsCodeBlocks(content string) bool {
@@ -552,6 +1224,20 @@ func printHelp() {
 
 Usage:
   mermaid-check [flags] [file...]
+  mermaid-check [flags] baseline create <file or directory>...
+  mermaid-check hook install [--force] [--pre-commit-config]
+  mermaid-check mcp
+  mermaid-check [flags] report [--format pr-comment] [--base REF] <file or directory>...
+  mermaid-check [flags] fix [--interactive] <file>...
+  mermaid-check new [--into FILE [--marker TEXT]] <type>
+  mermaid-check [flags] export sql [--dialect postgres|mysql|sqlite] <file>...
+  mermaid-check [flags] export graphml|gexf [--output DIR] <file>...
+  mermaid-check [flags] render [--ascii | --svg] [-o FILE] <file>...
+  mermaid-check capabilities [--json]
+  mermaid-check gen er --from-sql <file|->
+  mermaid-check gen sequence --from-openapi <file|-> --operation <id>
+  mermaid-check gen flowchart|C4Container --from-k8s <file|directory|->
+  mermaid-check gen flowchart --from-terraform-graph <file|->
 
 Flags:
   --help             Show this help message
@@ -559,6 +1245,35 @@ Flags:
   --strict           Use strict validation rules (includes style checks)
   --error-on-empty   Treat files with no Mermaid diagrams as errors
   --format FORMAT    Force input format: 'mermaid' or 'markdown'
+  --output FORMAT    Output format: 'text' (default) or 'html', a single report
+                     page for all given files and directories
+  --config PATH      Configuration file (default: nearest .mermaid-check.yaml)
+  --baseline FILE    Only report errors not recorded in FILE (see 'baseline create')
+  --staged           Validate the Markdown and Mermaid files staged in git
+  --fence-languages LANGS
+                     Comma-separated code fence languages treated as Mermaid
+                     (default: mermaid,mmd,mermaidjs,{mermaid})
+  --renderer NAME    Report constructs the renderer diagrams are published with
+                     can't display: confluence, github, gitlab or notion
+  --source-comments  Also validate the diagrams in the comments of Go, JavaScript,
+                     TypeScript and Python files found in directories (source
+                     files given directly are always checked)
+  --detect-duplicates
+                     Report identical or near-identical diagrams across all files
+  --max-errors-per-diagram N
+                     List at most N errors for each diagram
+  --profile          Report parse and validation times per diagram and per rule,
+                     listing the slowest
+  --check-references Check links between diagrams ('#id' hrefs) across all files
+  --check-class-consistency
+                     Check class diagrams in the same file against each other:
+                     conflicting members and classes defined only elsewhere
+  --check-sequence-consistency
+                     Check sequence diagram participants against the nearest
+                     flowchart or C4 diagram in the same file, both ways
+  --require-annotations KEYS
+                     Comma-separated annotations every diagram must define,
+                     e.g. 'owner,id' requires '%% @owner: ...' and '%% @id: ...'
 
 Examples:
   # Validate a Mermaid file
@@ -567,6 +1282,9 @@ Examples:
   # Validate markdown with Mermaid blocks
   mermaid-check README.md
 
+  # Validate the diagrams in a Go file's doc comments
+  mermaid-check server.go
+
   # Validate from stdin
   cat diagram.mmd | mermaid-check
 
@@ -576,9 +1294,61 @@ Examples:
   # Use strict rules
   mermaid-check --strict diagram.mmd
 
+  # Require every diagram to declare an owner
+  mermaid-check --require-annotations owner docs/*.md
+
   # Treat empty files as errors
   mermaid-check --error-on-empty *.md
 
+  # Record today's errors, then only fail on new ones
+  mermaid-check baseline create docs/
+  mermaid-check --baseline .mermaid-check-baseline.json docs/*.md
+
+  # Validate staged files before every commit
+  mermaid-check hook install
+
+  # Serve the validate_diagram, extract_diagrams and fix_diagram MCP tools over stdio
+  mermaid-check mcp
+
+  # Write an HTML report for a documentation tree
+  mermaid-check --output html docs/ > report.html
+
+  # Apply automatic fixes, reviewing each one first
+  mermaid-check --strict fix --interactive docs/guide.md
+
+  # Generate a PostgreSQL schema from the ER diagrams in a design document
+  mermaid-check export sql --dialect postgres docs/schema.md > schema.sql
+
+  # Open a flowchart in yEd or Gephi
+  mermaid-check export graphml docs/pipeline.mmd > pipeline.graphml
+
+  # Take a quick look at the flowcharts and sequence diagrams in a document
+  mermaid-check render docs/guide.md
+
+  # Draw a flowchart as SVG, without Node.js
+  mermaid-check render -o pipeline.svg docs/pipeline.mmd
+
+  # List the diagram types and rules this version supports, for CI templates
+  mermaid-check capabilities --json
+
+  # Start an ER diagram from an existing database schema
+  pg_dump --schema-only mydb | mermaid-check gen er --from-sql - > schema.mmd
+
+  # Sketch the sequence diagram for an API operation
+  mermaid-check gen sequence --from-openapi openapi.yaml --operation createOrder
+
+  # Draw the Deployments, Services and Ingresses in a directory of manifests
+  mermaid-check gen C4Container --from-k8s deploy/ > docs/architecture.mmd
+
+  # Draw a Terraform configuration's resources, grouped by module
+  terraform graph | mermaid-check gen flowchart --from-terraform-graph - > docs/infra.mmd
+
+  # Start a C4 container diagram where docs/design.md has <!-- mermaid-check:new -->
+  mermaid-check new --into docs/design.md C4Container
+
+  # Write a pull request comment comparing findings with the main branch
+  mermaid-check report --format pr-comment --base origin/main docs/ > comment.md
+
 Exit codes:
   0 - All diagrams are valid (or no diagrams found unless --error-on-empty is set)
   1 - Validation errors found or processing failed
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// defaultNewMarker is the line `new --into` replaces with the diagram.
+const defaultNewMarker = "<!-- mermaid-check:new -->"
+
+// runNew handles the `new` subcommand, printing a diagram skeleton or
+// inserting it into a markdown file.
+func runNew(args []string) int {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	into := fs.String("into", "", "insert the diagram into this markdown file at the marker instead of printing it")
+	marker := fs.String("marker", defaultNewMarker, "text marking the line --into replaces")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: mermaid-check new [--into FILE [--marker TEXT]] <type>\n")
+		fmt.Fprintf(os.Stderr, "Types: %s\n", strings.Join(mermaid.TemplateNames(), ", "))
+		return 1
+	}
+
+	template, err := mermaid.Template(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if *into == "" {
+		fmt.Print(template)
+		return 0
+	}
+
+	if err := insertAtMarker(*into, *marker, "```mermaid\n"+template+"```"); err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), *into, err)
+		return 1
+	}
+	fmt.Printf("%s %s: inserted %s diagram\n", green("✓"), *into, fs.Arg(0))
+	return 0
+}
+
+// insertAtMarker replaces the first line of the file at path containing
+// marker with block, preserving CRLF line endings.
+func insertAtMarker(path, marker, block string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided file path is intentional
+	if err != nil {
+		return err
+	}
+	crlf := strings.Contains(string(data), "\r\n")
+	lines := strings.Split(inpututil.NormaliseNewlines(string(data)), "\n")
+
+	found := false
+	for i, line := range lines {
+		if strings.Contains(line, marker) {
+			lines[i] = block
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("marker %q not found", marker)
+	}
+
+	content := strings.Join(lines, "\n")
+	if crlf {
+		content = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	return os.WriteFile(path, []byte(content), info.Mode().Perm())
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+// maxProfileEntries caps the diagrams and rules listed by --profile.
+const maxProfileEntries = 10
+
+// ruleProfile is the total time a rule spent across every diagram.
+type ruleProfile struct {
+	rule     string
+	total    time.Duration
+	diagrams int
+}
+
+// printProfile reports the slowest diagrams to parse and validate and the
+// slowest rules across all of them. The profile is informational and doesn't
+// affect the exit code.
+func printProfile(results []mermaid.Result) {
+	if len(results) == 0 {
+		return
+	}
+
+	var parse, validate time.Duration
+	rules := make(map[string]*ruleProfile)
+	for _, result := range results {
+		if result.Metrics == nil {
+			continue
+		}
+		parse += result.Metrics.Parse
+		validate += result.Metrics.Validate
+		for _, timing := range result.Metrics.Rules {
+			profile, ok := rules[timing.Rule]
+			if !ok {
+				profile = &ruleProfile{rule: timing.Rule}
+				rules[timing.Rule] = profile
+			}
+			profile.total += timing.Duration
+			profile.diagrams++
+		}
+	}
+
+	fmt.Printf("\n%s %s\n", bold("Profile:"), dim(fmt.Sprintf("%d diagram(s), parse %s, validate %s", len(results), formatDuration(parse), formatDuration(validate))))
+
+	slowest := slices.Clone(results)
+	slices.SortStableFunc(slowest, func(a, b mermaid.Result) int {
+		return cmp.Compare(diagramTime(b), diagramTime(a))
+	})
+	fmt.Printf("  %s\n", cyan("Slowest diagrams:"))
+	for _, result := range slowest[:min(len(slowest), maxProfileEntries)] {
+		fmt.Printf("    %8s  %s (L%d-L%d) %s\n", formatDuration(diagramTime(result)), result.File, result.LineOffset, result.EndLine,
+			dim(fmt.Sprintf("%s, parse %s, validate %s", diagramTypeDisplayName(result.DiagramType), formatDuration(result.Metrics.Parse), formatDuration(result.Metrics.Validate))))
+	}
+
+	ranked := make([]*ruleProfile, 0, len(rules))
+	for _, profile := range rules {
+		ranked = append(ranked, profile)
+	}
+	slices.SortFunc(ranked, func(a, b *ruleProfile) int {
+		return cmp.Or(cmp.Compare(b.total, a.total), cmp.Compare(a.rule, b.rule))
+	})
+	if len(ranked) > 0 {
+		fmt.Printf("  %s\n", cyan("Slowest rules:"))
+	}
+	for _, profile := range ranked[:min(len(ranked), maxProfileEntries)] {
+		fmt.Printf("    %8s  %s %s\n", formatDuration(profile.total), profile.rule, dim(fmt.Sprintf("(%d diagram(s))", profile.diagrams)))
+	}
+}
+
+// diagramTime is the total time spent parsing and validating a diagram.
+func diagramTime(result mermaid.Result) time.Duration {
+	if result.Metrics == nil {
+		return 0
+	}
+	return result.Metrics.Parse + result.Metrics.Validate
+}
+
+// formatDuration rounds d for display.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
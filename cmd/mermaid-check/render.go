@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/render"
+)
+
+const renderUsage = "Usage: mermaid-check render [--ascii | --svg] [-o FILE] <file>...\n"
+
+// runRender handles the `render` subcommand, which draws every flowchart and
+// sequence diagram in the given files as text art, or a single diagram as SVG
+// when --svg is given or the -o file ends in .svg.
+func runRender(args []string, opts mermaid.ValidateOptions) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	ascii := fs.Bool("ascii", false, "draw with plain ASCII rather than Unicode box-drawing characters")
+	svg := fs.Bool("svg", false, "draw an SVG document rather than text")
+	outputPath := fs.String("o", "", "file to write the drawing to rather than standard output")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprint(os.Stderr, renderUsage)
+		return 1
+	}
+	*svg = *svg || strings.EqualFold(filepath.Ext(*outputPath), ".svg")
+	charset := render.Unicode
+	if *ascii {
+		charset = render.ASCII
+	}
+
+	drawings, exitCode := renderableDiagrams(fs.Args(), opts)
+	if len(drawings) == 0 {
+		if exitCode == 0 {
+			fmt.Fprintf(os.Stderr, "No flowcharts or sequence diagrams found\n")
+		}
+		return 1
+	}
+
+	var out strings.Builder
+	if *svg {
+		if len(drawings) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: found %d diagrams; an SVG document holds one, so render them one file at a time\n", len(drawings))
+			return 1
+		}
+		document, err := render.SVG(drawings[0].result.Diagram)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		out.WriteString(document)
+	} else {
+		for i, d := range drawings {
+			text, err := render.Text(d.result.Diagram, charset)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s (L%d-L%d): %v\n", red("✗"), d.path, d.result.LineOffset, d.result.EndLine, err)
+				exitCode = 1
+				continue
+			}
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			fmt.Fprintf(&out, "%s (L%d-L%d)\n\n%s", d.path, d.result.LineOffset, d.result.EndLine, text)
+		}
+	}
+
+	if *outputPath == "" {
+		fmt.Print(out.String())
+		return exitCode
+	}
+	if err := os.WriteFile(*outputPath, []byte(out.String()), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return exitCode
+}
+
+// drawing is a diagram to render, and where it came from.
+type drawing struct {
+	path   string
+	result mermaid.Result
+}
+
+// renderableDiagrams returns the flowcharts and sequence diagrams in the files
+// at paths, reporting files that can't be read and diagrams that don't parse.
+func renderableDiagrams(paths []string, opts mermaid.ValidateOptions) ([]drawing, int) {
+	var drawings []drawing
+	exitCode := 0
+	for _, path := range paths {
+		results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{FenceLanguages: opts.FenceLanguages})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s: %v\n", red("✗"), path, err)
+			exitCode = 1
+			continue
+		}
+		for _, result := range results {
+			if result.ParseError != nil {
+				fmt.Fprintf(os.Stderr, "%s %s (L%d-L%d): %v\n", red("✗"), path, result.LineOffset, result.EndLine, result.ParseError)
+				exitCode = 1
+				continue
+			}
+			switch result.Diagram.(type) {
+			case *ast.Flowchart, *ast.SequenceDiagram:
+				drawings = append(drawings, drawing{path: path, result: result})
+			}
+		}
+	}
+	return drawings, exitCode
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+// reportMarker is a hidden comment at the start of every PR comment, so that
+// CI can find and update its previous comment instead of adding another.
+const reportMarker = "<!-- mermaid-check-report -->"
+
+// maxReportFindings caps the findings listed in a PR comment, keeping it well
+// within the size limits of code review tools.
+const maxReportFindings = 200
+
+// diagramKey identifies a diagram by its file and position within the file.
+type diagramKey struct {
+	file  string
+	block int
+}
+
+// runReport handles the `report` subcommand.
+func runReport(args []string, opts mermaid.ValidateOptions) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	format := fs.String("format", "pr-comment", "output format (pr-comment)")
+	base := fs.String("base", "", "git ref to compare findings with, such as origin/main")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *format != "pr-comment" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported report format %q\n", *format)
+		return 1
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: mermaid-check [flags] report [--format pr-comment] [--base REF] <file or directory>...\n")
+		return 1
+	}
+
+	var results []mermaid.Result
+	for _, path := range fs.Args() {
+		pathResults, err := mermaid.ValidatePath(path, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		results = append(results, pathResults...)
+	}
+
+	var before map[diagramKey]int
+	if *base != "" {
+		var err error
+		if before, err = baseFindings(*base, results, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	fmt.Print(renderPRComment(results, before, *base))
+	for _, result := range results {
+		if findingCount(result) > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// baseFindings validates each file in results as it was at the git ref base,
+// returning the number of findings per diagram. Files that did not exist at
+// base have no entries.
+func baseFindings(base string, results []mermaid.Result, opts mermaid.ValidateOptions) (map[diagramKey]int, error) {
+	if _, err := git("rev-parse", "--verify", "--quiet", base+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("unknown git ref %q", base)
+	}
+
+	counts := make(map[diagramKey]int)
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if seen[result.File] {
+			continue
+		}
+		seen[result.File] = true
+
+		content, err := gitOutput("show", base+":"+gitRelativePath(result.File))
+		if err != nil {
+			// The file was added after base
+			continue
+		}
+		baseResults, err := mermaid.ValidateSource(result.File, string(content), opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s at %s: %w", result.File, base, err)
+		}
+		for _, baseResult := range baseResults {
+			counts[diagramKey{baseResult.File, baseResult.BlockIndex}] = findingCount(baseResult)
+		}
+	}
+	return counts, nil
+}
+
+// gitRelativePath returns path in the form git resolves relative to the
+// current directory in a <ref>:<path> expression.
+func gitRelativePath(path string) string {
+	if filepath.IsAbs(path) {
+		if cwd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(cwd, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return "./" + filepath.ToSlash(filepath.Clean(path))
+}
+
+// findingCount returns the number of problems reported for a diagram, counting
+// a parse error as one.
+func findingCount(result mermaid.Result) int {
+	if result.ParseError != nil {
+		return 1
+	}
+	return len(result.Errors)
+}
+
+// renderPRComment renders results as a markdown pull request comment: a
+// summary line, a table of the diagrams with findings and the findings
+// themselves. When before is set, the table compares each diagram's findings
+// with those at the git ref base.
+func renderPRComment(results []mermaid.Result, before map[diagramKey]int, base string) string {
+	var b strings.Builder
+	b.WriteString(reportMarker + "\n## Mermaid diagram check\n\n")
+
+	files := make(map[string]bool)
+	var total, failing, beforeTotal int
+	for _, result := range results {
+		files[result.File] = true
+		if count := findingCount(result); count > 0 {
+			total += count
+			failing++
+		}
+	}
+	for _, count := range before {
+		beforeTotal += count
+	}
+
+	if total == 0 {
+		fmt.Fprintf(&b, "✅ All %d diagram(s) in %d file(s) are valid.", len(results), len(files))
+	} else {
+		fmt.Fprintf(&b, "❌ Found %d problem(s) in %d of %d diagram(s) across %d file(s).", total, failing, len(results), len(files))
+	}
+	if before != nil {
+		fmt.Fprintf(&b, " `%s` had %d (%+d).", base, beforeTotal, total-beforeTotal)
+	}
+	b.WriteString("\n")
+
+	// Summarise the diagrams with findings, or whose findings changed
+	var rows []string
+	for _, result := range results {
+		count := findingCount(result)
+		diagram := fmt.Sprintf("| `%s` | %s | L%d-L%d |", result.File, diagramTypeDisplayName(result.DiagramType), result.LineOffset, result.EndLine)
+		if before == nil {
+			if count > 0 {
+				rows = append(rows, fmt.Sprintf("%s %d |", diagram, count))
+			}
+			continue
+		}
+		previous, existed := before[diagramKey{result.File, result.BlockIndex}]
+		if count > 0 || previous != count {
+			previousText := fmt.Sprint(previous)
+			if !existed {
+				previousText = "new"
+			}
+			rows = append(rows, fmt.Sprintf("%s %s | %d |", diagram, previousText, count))
+		}
+	}
+	if len(rows) > 0 {
+		if before == nil {
+			b.WriteString("\n| File | Diagram | Lines | Problems |\n| --- | --- | --- | --- |\n")
+		} else {
+			b.WriteString("\n| File | Diagram | Lines | Before | After |\n| --- | --- | --- | --- | --- |\n")
+		}
+		b.WriteString(strings.Join(rows, "\n") + "\n")
+	}
+
+	if total == 0 {
+		return b.String()
+	}
+
+	// List the findings, with line numbers in the containing file
+	b.WriteString("\n<details>\n<summary>Findings</summary>\n\n")
+	listed := 0
+	for _, result := range results {
+		if findingCount(result) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "**`%s`** %s (L%d-L%d)\n\n", result.File, diagramTypeDisplayName(result.DiagramType), result.LineOffset, result.EndLine)
+		if result.ParseError != nil {
+			fmt.Fprintf(&b, "- parse error: %s\n", escapeMarkdown(withSuggestion(result.ParseError.Error(), result.Suggestion)))
+			listed++
+		}
+		for _, err := range result.Errors {
+			if listed == maxReportFindings {
+				break
+			}
+			line := result.LineOffset + err.Line - 1
+			rule := ""
+			if err.Rule != "" {
+				rule = fmt.Sprintf(" `%s`", err.Rule)
+			}
+			fmt.Fprintf(&b, "- L%d %s%s: %s\n", line, err.Severity, rule, escapeMarkdown(withSuggestion(err.Message, err.Suggestion)))
+			listed++
+		}
+		b.WriteString("\n")
+		if listed == maxReportFindings {
+			break
+		}
+	}
+	if listed < total {
+		fmt.Fprintf(&b, "…and %d more.\n\n", total-listed)
+	}
+	b.WriteString("</details>\n")
+
+	return b.String()
+}
+
+// markdownEscaper escapes characters that markdown or HTML would interpret in
+// finding messages, which quote diagram text.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"|", `\|`, "<", "&lt;", ">", "&gt;",
+)
+
+// escapeMarkdown escapes text for inclusion in a markdown comment.
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,261 @@
+// Package config loads mermaid-check project configuration from a
+// .mermaid-check.yaml file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/sammcj/mermaid-check/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file searched for by Find.
+const FileName = ".mermaid-check.yaml"
+
+// Config is the contents of a configuration file. Paths inside it are resolved
+// relative to the directory containing the file.
+type Config struct {
+	// Strict enables the strict rule set for every diagram type.
+	Strict bool `yaml:"strict"`
+	// RequiredAnnotations lists metadata annotation keys every diagram must define.
+	RequiredAnnotations []string `yaml:"required-annotations"`
+	// SpellCheck configures the opt-in spell-check rule.
+	SpellCheck SpellCheck `yaml:"spell-check"`
+	// Terminology lists terms to avoid in diagram text and their preferred
+	// replacements.
+	Terminology []Term `yaml:"terminology"`
+	// Naming maps a diagram type (flowchart, sequence, class or state) to the
+	// naming convention its identifiers must follow: a preset such as
+	// camelCase or PascalCase, or a regular expression.
+	Naming map[string]string `yaml:"naming"`
+	// LabelLength maps a diagram type (flowchart or sequence) to the longest
+	// label or message allowed, enabling the label-length rule.
+	LabelLength map[string]int `yaml:"label-length"`
+	// SelfLoops maps a diagram type (flowchart, state, class or c4) to the
+	// severity self-loops are reported with (error, warning or info), or off
+	// to skip that type, enabling the self-loops rule.
+	SelfLoops map[string]string `yaml:"self-loops"`
+	// Icons configures the catalogue of icon identifiers the known-icons rule
+	// checks C4 sprites against, enabling the rule.
+	Icons *Icons `yaml:"icons"`
+	// Indentation is the indentation per nesting level, a number of spaces or
+	// "tab", enabling the indent-style rule.
+	Indentation string `yaml:"indentation"`
+	// ParticipantsDeclaredFirst requires sequence diagrams to declare every
+	// participant before the first message.
+	ParticipantsDeclaredFirst bool `yaml:"participants-declared-first"`
+	// SQLDialect is the SQL dialect (postgres, mysql or sqlite) ER attribute
+	// types must map to, enabling the er-sql-types rule.
+	SQLDialect string `yaml:"sql-dialect"`
+	// Renderer is the renderer diagrams are published with, such as
+	// confluence, enabling the renderer-compatibility rule.
+	Renderer string `yaml:"renderer"`
+	// FenceLanguages are the markdown code fence languages treated as
+	// Mermaid, replacing the defaults (mermaid, mmd, mermaidjs and {mermaid}).
+	FenceLanguages []string `yaml:"fence-languages"`
+	// SourceComments also validates the diagrams in the comments of source
+	// files found when walking directories.
+	SourceComments bool `yaml:"source-comments"`
+	// Rules gives options to configurable rules (see
+	// validator.ConfigurableRules), keyed by rule name, enabling each rule
+	// given options.
+	Rules map[string]validator.RuleOptions `yaml:"rules"`
+	// Overrides adjust the rules applied to files matching path globs.
+	Overrides []Override `yaml:"overrides"`
+	// Types holds the settings of the sections named after a diagram kind
+	// (see validator.DiagramKinds), such as flowchart: or sequence:, merged
+	// with the global settings when the file is loaded.
+	Types map[string]TypeConfig `yaml:"-"`
+
+	// dir is the directory the configuration was loaded from.
+	dir string
+}
+
+// TypeConfig is the settings for one kind of diagram. After loading it holds
+// the global settings with the section's applied: Strict when the section
+// sets it, RequiredAnnotations added to the global ones, and Rules options
+// set over the global options of the same rule.
+type TypeConfig struct {
+	// Strict enables or disables the strict rule set for the diagram kind.
+	Strict *bool `yaml:"strict"`
+	// RequiredAnnotations lists metadata annotation keys diagrams of the kind
+	// must define.
+	RequiredAnnotations []string `yaml:"required-annotations"`
+	// Rules gives options to configurable rules for the diagram kind.
+	Rules map[string]validator.RuleOptions `yaml:"rules"`
+}
+
+// SpellCheck configures the spell-check rule.
+type SpellCheck struct {
+	// Enabled turns the rule on.
+	Enabled bool `yaml:"enabled"`
+	// Dictionaries are wordlist files (one word per line, # for comments),
+	// for example /usr/share/dict/words plus a project-specific list.
+	Dictionaries []string `yaml:"dictionaries"`
+	// Words are extra accepted words, such as product names.
+	Words []string `yaml:"words"`
+}
+
+// Icons configures the known-icons rule's catalogue.
+type Icons struct {
+	// Known are icon identifiers to accept, such as "logos:aws-lambda"; an
+	// entry such as "mdi:*" accepts every icon in a pack.
+	Known []string `yaml:"known"`
+	// Replace makes Known the whole catalogue, rather than additions to the
+	// built-in one.
+	Replace bool `yaml:"replace"`
+}
+
+// Term is a word or phrase to avoid, with an optional preferred replacement.
+type Term struct {
+	Avoid  string `yaml:"avoid"`
+	Prefer string `yaml:"prefer"`
+}
+
+// Override disables rules for the files matching any of Paths, which are
+// globs relative to the configuration file ("**" matches any number of
+// directories).
+type Override struct {
+	Paths   []string `yaml:"paths"`
+	Disable []string `yaml:"disable"`
+}
+
+// Load reads and parses the configuration file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided config path is intentional
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.dir = filepath.Dir(path)
+
+	if err := cfg.loadTypes(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, err := cfg.ConfiguredRules(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, kind := range slices.Sorted(maps.Keys(cfg.Types)) {
+		if _, err := cfg.Types[kind].ConfiguredRules(); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, kind, err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// loadTypes decodes the diagram kind sections of data into Types, merging
+// each with the global settings.
+func (c *Config) loadTypes(data []byte) error {
+	var sections map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+	for _, kind := range validator.DiagramKinds {
+		section, ok := sections[kind]
+		if !ok {
+			continue
+		}
+		var typeConfig TypeConfig
+		if err := section.Decode(&typeConfig); err != nil {
+			return fmt.Errorf("%s: %w", kind, err)
+		}
+
+		if typeConfig.Strict == nil {
+			strict := c.Strict
+			typeConfig.Strict = &strict
+		}
+		annotations := slices.Clone(c.RequiredAnnotations)
+		for _, key := range typeConfig.RequiredAnnotations {
+			if !slices.Contains(annotations, key) {
+				annotations = append(annotations, key)
+			}
+		}
+		typeConfig.RequiredAnnotations = annotations
+		rules := make(map[string]validator.RuleOptions, len(c.Rules)+len(typeConfig.Rules))
+		for name, options := range c.Rules {
+			rules[name] = maps.Clone(options)
+		}
+		for name, options := range typeConfig.Rules {
+			if rules[name] == nil {
+				rules[name] = make(validator.RuleOptions, len(options))
+			}
+			maps.Copy(rules[name], options)
+		}
+		typeConfig.Rules = rules
+
+		if c.Types == nil {
+			c.Types = make(map[string]TypeConfig)
+		}
+		c.Types[kind] = typeConfig
+	}
+	return nil
+}
+
+// ConfiguredRules returns the rules Rules gives options to, in name order,
+// reporting rules that take no options, unknown options and values of the
+// wrong type.
+func (c *Config) ConfiguredRules() ([]validator.ConfigurableRule, error) {
+	return configureRules(c.Rules)
+}
+
+// ConfiguredRules returns the rules Rules gives options to, as
+// Config.ConfiguredRules does.
+func (t TypeConfig) ConfiguredRules() ([]validator.ConfigurableRule, error) {
+	return configureRules(t.Rules)
+}
+
+func configureRules(options map[string]validator.RuleOptions) ([]validator.ConfigurableRule, error) {
+	names := slices.Sorted(maps.Keys(options))
+	rules := make([]validator.ConfigurableRule, 0, len(names))
+	for _, name := range names {
+		rule, err := validator.ConfigureRule(name, options[name])
+		if err != nil {
+			return nil, fmt.Errorf("rules: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Find looks for FileName in dir and each of its parents, returning the path of
+// the first one found, or "" if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Resolve returns path relative to the directory the configuration was loaded
+// from, leaving absolute paths unchanged.
+func (c *Config) Resolve(path string) string {
+	if filepath.IsAbs(path) || c.dir == "" {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
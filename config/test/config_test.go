@@ -0,0 +1,282 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/config"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, config.FileName)
+	content := `strict: true
+required-annotations: [owner, id]
+spell-check:
+  enabled: true
+  dictionaries:
+    - words.txt
+    - /usr/share/dict/words
+  words: [Kubernetes]
+terminology:
+  - avoid: DB
+    prefer: Database
+naming:
+  class: PascalCase
+self-loops:
+  state: off
+  c4: error
+icons:
+  known: ["mdi:*", acme-billing]
+  replace: true
+indentation: 2
+participants-declared-first: true
+sql-dialect: postgres
+renderer: confluence
+fence-languages: [mermaid, mmd]
+source-comments: true
+overrides:
+  - paths: ["docs/legacy/**"]
+    disable: [no-parentheses-in-labels]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !cfg.Strict {
+		t.Error("Strict = false, want true")
+	}
+	if !reflect.DeepEqual(cfg.RequiredAnnotations, []string{"owner", "id"}) {
+		t.Errorf("RequiredAnnotations = %v", cfg.RequiredAnnotations)
+	}
+	if !cfg.SpellCheck.Enabled || !reflect.DeepEqual(cfg.SpellCheck.Words, []string{"Kubernetes"}) {
+		t.Errorf("SpellCheck = %+v", cfg.SpellCheck)
+	}
+	if want := []config.Term{{Avoid: "DB", Prefer: "Database"}}; !reflect.DeepEqual(cfg.Terminology, want) {
+		t.Errorf("Terminology = %+v, want %+v", cfg.Terminology, want)
+	}
+	if cfg.Naming["class"] != "PascalCase" {
+		t.Errorf("Naming = %v", cfg.Naming)
+	}
+	if want := map[string]string{"state": "off", "c4": "error"}; !reflect.DeepEqual(cfg.SelfLoops, want) {
+		t.Errorf("SelfLoops = %v, want %v", cfg.SelfLoops, want)
+	}
+	if want := (&config.Icons{Known: []string{"mdi:*", "acme-billing"}, Replace: true}); !reflect.DeepEqual(cfg.Icons, want) {
+		t.Errorf("Icons = %+v, want %+v", cfg.Icons, want)
+	}
+	if !cfg.ParticipantsDeclaredFirst {
+		t.Error("ParticipantsDeclaredFirst = false, want true")
+	}
+	if cfg.SQLDialect != "postgres" {
+		t.Errorf("SQLDialect = %q, want \"postgres\"", cfg.SQLDialect)
+	}
+	if cfg.Renderer != "confluence" {
+		t.Errorf("Renderer = %q, want \"confluence\"", cfg.Renderer)
+	}
+	if cfg.Indentation != "2" {
+		t.Errorf("Indentation = %q, want \"2\"", cfg.Indentation)
+	}
+	if want := []string{"mermaid", "mmd"}; !reflect.DeepEqual(cfg.FenceLanguages, want) {
+		t.Errorf("FenceLanguages = %v, want %v", cfg.FenceLanguages, want)
+	}
+	if !cfg.SourceComments {
+		t.Error("SourceComments = false, want true")
+	}
+	if want := []config.Override{{Paths: []string{"docs/legacy/**"}, Disable: []string{"no-parentheses-in-labels"}}}; !reflect.DeepEqual(cfg.Overrides, want) {
+		t.Errorf("Overrides = %+v, want %+v", cfg.Overrides, want)
+	}
+	if got, want := cfg.Resolve(cfg.SpellCheck.Dictionaries[0]), filepath.Join(dir, "words.txt"); got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+	if got := cfg.Resolve(cfg.SpellCheck.Dictionaries[1]); got != "/usr/share/dict/words" {
+		t.Errorf("Resolve() of absolute path = %q", got)
+	}
+}
+
+func TestLoad_InvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.FileName)
+	if err := os.WriteFile(path, []byte("strict: [unterminated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := config.Load(path); err == nil {
+		t.Error("Load() expected error for invalid YAML")
+	}
+}
+
+func TestLoad_RuleOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid options",
+			content: "rules:\n  max-link-length:\n    max: 5\n  naming-conventions:\n    flowchart: camelCase\n  terminology:\n    avoid: [DB]\n",
+		},
+		{
+			name:    "rule without options",
+			content: "rules:\n  no-duplicate-nodes:\n    max: 1\n",
+			wantErr: `rule "no-duplicate-nodes" takes no options`,
+		},
+		{
+			name:    "unknown option",
+			content: "rules:\n  max-link-length:\n    maximum: 5\n",
+			wantErr: `rule max-link-length has no option "maximum" (options: max)`,
+		},
+		{
+			name:    "wrong type",
+			content: "rules:\n  label-length:\n    flowchart: long\n",
+			wantErr: `rule label-length: option flowchart: want integer, got string "long"`,
+		},
+		{
+			name:    "wrong list item type",
+			content: "rules:\n  terminology:\n    avoid: [DB, 42]\n",
+			wantErr: "want list of strings, got 42 at item 2",
+		},
+		{
+			name:    "invalid pattern",
+			content: "rules:\n  naming-conventions:\n    class: \"[\"\n",
+			wantErr: "rule naming-conventions: option class: error parsing regexp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), config.FileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := config.Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			rules, err := cfg.ConfiguredRules()
+			if err != nil {
+				t.Fatalf("ConfiguredRules() error = %v", err)
+			}
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name())
+			}
+			if want := []string{"max-link-length", "naming-conventions", "terminology"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("ConfiguredRules() = %v, want %v", names, want)
+			}
+		})
+	}
+}
+
+func TestLoad_TypeSections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.FileName)
+	content := `required-annotations: [owner]
+rules:
+  label-length:
+    flowchart: 30
+    sequence: 50
+flowchart:
+  strict: true
+  rules:
+    label-length:
+      flowchart: 20
+    allowed-directions:
+      directions: [TD, LR]
+sequence:
+  required-annotations: [owner, id]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Types) != 2 {
+		t.Fatalf("Types = %v, want flowchart and sequence", cfg.Types)
+	}
+
+	flowchart := cfg.Types["flowchart"]
+	if flowchart.Strict == nil || !*flowchart.Strict {
+		t.Error("flowchart Strict = false, want true")
+	}
+	if !reflect.DeepEqual(flowchart.RequiredAnnotations, []string{"owner"}) {
+		t.Errorf("flowchart RequiredAnnotations = %v, want the global ones", flowchart.RequiredAnnotations)
+	}
+	if want := (validator.RuleOptions{"flowchart": 20, "sequence": 50}); !reflect.DeepEqual(flowchart.Rules["label-length"], want) {
+		t.Errorf("flowchart label-length options = %v, want %v", flowchart.Rules["label-length"], want)
+	}
+	rules, err := flowchart.ConfiguredRules()
+	if err != nil || len(rules) != 2 || rules[0].Name() != "allowed-directions" {
+		t.Errorf("flowchart ConfiguredRules() = %v, %v", rules, err)
+	}
+
+	sequence := cfg.Types["sequence"]
+	if sequence.Strict == nil || *sequence.Strict {
+		t.Error("sequence Strict should default to the global setting")
+	}
+	if !reflect.DeepEqual(sequence.RequiredAnnotations, []string{"owner", "id"}) {
+		t.Errorf("sequence RequiredAnnotations = %v", sequence.RequiredAnnotations)
+	}
+	if cfg.Rules["label-length"]["flowchart"] != 30 {
+		t.Errorf("global label-length options changed: %v", cfg.Rules["label-length"])
+	}
+}
+
+func TestLoad_TypeSectionErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.FileName)
+	content := "sequence:\n  rules:\n    max-participants:\n      max: many\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := config.Load(path)
+	if want := `sequence: rules: rule max-participants: option max: want integer, got string "many"`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Load() error = %v, want one containing %q", err, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "docs", "architecture")
+	if err := os.MkdirAll(nested, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := config.Find(nested)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if got != "" {
+		// A config file above the temp dir would make this test meaningless
+		t.Skipf("found unrelated config %s", got)
+	}
+
+	path := filepath.Join(root, config.FileName)
+	if err := os.WriteFile(path, []byte("strict: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = config.Find(nested)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if got != path {
+		t.Errorf("Find() = %q, want %q", got, path)
+	}
+}
@@ -0,0 +1,121 @@
+package mermaid
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// ParseContext is like Parse but returns early with ctx.Err() if the context is
+// cancelled or its deadline passes before parsing completes.
+//
+// Parsers do not observe the context themselves, so an abandoned parse keeps running
+// in the background until it finishes; the caller is simply no longer blocked on it.
+// Use ParseContextWithLimits for untrusted input, so that the work left running
+// is bounded.
+func ParseContext(ctx context.Context, source string) (ast.Diagram, error) {
+	return runWithContext(ctx, func() (ast.Diagram, error) {
+		return Parse(source)
+	})
+}
+
+// ParseContextWithLimits is like ParseContext but parses with ParseWithLimits.
+// Input exceeding the size, line or depth limits is rejected before parsing
+// starts, so a parse abandoned when ctx is done only ever has a bounded amount
+// of work left to finish in the background.
+func ParseContextWithLimits(ctx context.Context, source string, limits parser.Limits) (ast.Diagram, error) {
+	return runWithContext(ctx, func() (ast.Diagram, error) {
+		return ParseWithLimits(source, limits)
+	})
+}
+
+// ValidateContext is like Validate but returns early with ctx.Err() if the context
+// is cancelled or its deadline passes before validation completes. As with
+// ParseContext, abandoned validation runs on in the background until it
+// finishes.
+func ValidateContext(ctx context.Context, diagram ast.Diagram, strict bool) ([]validator.ValidationError, error) {
+	return runWithContext(ctx, func() ([]validator.ValidationError, error) {
+		return Validate(diagram, strict), nil
+	})
+}
+
+// ParseFileContext is like ParseFile but checks the context before each diagram
+// and abandons the file as soon as the context is done.
+func ParseFileContext(ctx context.Context, path string) ([]ast.Diagram, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided file path is intentional
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := fileBlocks(path, string(data), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	diagrams := make([]ast.Diagram, 0, len(blocks))
+	for _, block := range blocks {
+		diagram, err := ParseContext(ctx, block.Source)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("error parsing Mermaid block at line %d: %w", block.LineOffset, err)
+		}
+		diagrams = append(diagrams, diagram)
+	}
+
+	return diagrams, nil
+}
+
+// ValidateSourceContext is like ValidateSource but checks the context before
+// each diagram and abandons the content as soon as the context is done. Set
+// opts.Limits to bound the work done on each diagram once it has started.
+func ValidateSourceContext(ctx context.Context, path, content string, opts ValidateOptions) ([]Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	blocks, err := fileBlocks(path, content, opts.FenceLanguages)
+	if err != nil {
+		return nil, err
+	}
+	return validateBlocksContext(ctx, path, blocks, opts)
+}
+
+// runWithContext runs fn in its own goroutine and waits for either its result or
+// the context to finish, whichever happens first. fn cannot be interrupted, so
+// when the context finishes first it keeps running until it returns, and its
+// result is discarded.
+func runWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+	done := make(chan result, 1) // Buffered so an abandoned goroutine can still exit
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case r := <-done:
+		return r.value, r.err
+	}
+}
@@ -0,0 +1,211 @@
+package mermaid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// ignoredFingerprintFields are AST fields that don't affect what a diagram
+// shows, so diagrams differing only in these are treated as identical.
+var ignoredFingerprintFields = map[string]bool{
+	"Pos":         true,
+	"Source":      true,
+	"Lines":       true,
+	"Annotations": true,
+}
+
+// DuplicateGroup is a set of diagrams found by FindDuplicates to be identical
+// or near-identical.
+type DuplicateGroup struct {
+	Results    []Result // The matching diagrams, in input order
+	Identical  bool     // True when every diagram has the same fingerprint
+	Similarity float64  // Lowest pairwise similarity within the group (1 when Identical)
+}
+
+// Fingerprint returns a hash of diagram's normalised AST. Source positions,
+// comments, whitespace and metadata annotations are ignored, so two diagrams
+// that differ only in layout or commentary share a fingerprint.
+func Fingerprint(diagram ast.Diagram) string {
+	sum := sha256.Sum256([]byte(diagram.GetType() + "\n" + strings.Join(diagramFeatures(diagram), "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Similarity returns how alike two diagrams are, from 0 (nothing in common) to
+// 1 (identical fingerprints). It compares the diagrams' top-level elements
+// (statements, entries, tasks and so on) as multisets. Diagrams of different
+// types have a similarity of 0.
+func Similarity(a, b ast.Diagram) float64 {
+	if a.GetType() != b.GetType() {
+		return 0
+	}
+	if Fingerprint(a) == Fingerprint(b) {
+		return 1
+	}
+
+	featuresA, featuresB := diagramFeatures(a), diagramFeatures(b)
+	if len(featuresA) == 0 && len(featuresB) == 0 {
+		return 1
+	}
+
+	counts := make(map[string]int, len(featuresA))
+	for _, f := range featuresA {
+		counts[f]++
+	}
+	shared := 0
+	for _, f := range featuresB {
+		if counts[f] > 0 {
+			counts[f]--
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(featuresA)+len(featuresB)-shared)
+}
+
+// FindDuplicates groups parsed diagrams whose similarity is at least threshold
+// (use 1 to report exact duplicates only). Results that failed to parse are
+// ignored. Groups are returned in the order their first diagram appears.
+func FindDuplicates(results []Result, threshold float64) []DuplicateGroup {
+	var parsed []int
+	for i := range results {
+		if results[i].Diagram != nil {
+			parsed = append(parsed, i)
+		}
+	}
+
+	// Single-linkage clustering: a diagram joins the first group that already
+	// contains a sufficiently similar diagram.
+	groupOf := make(map[int]int)
+	var groups [][]int
+	for n, i := range parsed {
+		for _, j := range parsed[:n] {
+			if Similarity(results[i].Diagram, results[j].Diagram) >= threshold {
+				g := groupOf[j]
+				groupOf[i] = g
+				groups[g] = append(groups[g], i)
+				break
+			}
+		}
+		if _, grouped := groupOf[i]; !grouped {
+			groupOf[i] = len(groups)
+			groups = append(groups, []int{i})
+		}
+	}
+
+	var duplicates []DuplicateGroup
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		group := DuplicateGroup{Identical: true, Similarity: 1}
+		for n, i := range members {
+			group.Results = append(group.Results, results[i])
+			for _, j := range members[:n] {
+				s := Similarity(results[i].Diagram, results[j].Diagram)
+				group.Similarity = min(group.Similarity, s)
+				if s < 1 {
+					group.Identical = false
+				}
+			}
+		}
+		duplicates = append(duplicates, group)
+	}
+
+	return duplicates
+}
+
+// diagramFeatures returns a canonical encoding of each top-level element of
+// diagram, such as its statements or data entries.
+func diagramFeatures(diagram ast.Diagram) []string {
+	if generic, ok := diagram.(*ast.GenericDiagram); ok {
+		var features []string
+		for _, line := range generic.Lines {
+			trimmed := strings.Join(strings.Fields(line), " ")
+			if trimmed != "" && !strings.HasPrefix(trimmed, "%%") {
+				features = append(features, trimmed)
+			}
+		}
+		return features
+	}
+
+	var features []string
+	v := reflect.Indirect(reflect.ValueOf(diagram))
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if ignoredFingerprintFields[field.Name] || !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+		if value.Kind() == reflect.Slice {
+			for j := range value.Len() {
+				if encoded := encodeValue(value.Index(j)); encoded != "" {
+					features = append(features, field.Name+":"+encoded)
+				}
+			}
+			continue
+		}
+		features = append(features, field.Name+"="+encodeValue(value))
+	}
+	return features
+}
+
+// encodeValue writes a canonical, position-independent representation of v.
+// Comment nodes encode to the empty string and are dropped.
+func encodeValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		if strings.HasSuffix(t.Name(), "Comment") {
+			return ""
+		}
+		var b strings.Builder
+		b.WriteString(t.Name() + "{")
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if ignoredFingerprintFields[field.Name] || !field.IsExported() {
+				continue
+			}
+			fmt.Fprintf(&b, "%s:%s;", field.Name, encodeValue(v.Field(i)))
+		}
+		b.WriteString("}")
+		return b.String()
+	case reflect.Slice, reflect.Array:
+		var parts []string
+		for i := range v.Len() {
+			if encoded := encodeValue(v.Index(i)); encoded != "" {
+				parts = append(parts, encoded)
+			}
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		entries := make(map[string]string, v.Len())
+		for _, key := range v.MapKeys() {
+			k := fmt.Sprint(key.Interface())
+			keys = append(keys, k)
+			entries[k] = encodeValue(v.MapIndex(key))
+		}
+		slices.Sort(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = fmt.Sprintf("%q:%s", k, entries[k])
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	case reflect.String:
+		return fmt.Sprintf("%q", strings.Join(strings.Fields(v.String()), " "))
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,54 @@
+// Package examples provides a corpus of valid Mermaid diagrams, at least one
+// for every diagram type mermaid-check supports, for tools that need
+// known-good sources: editor previews, scaffolding and tests.
+//
+// Examples are named by the diagram type parser.DetectType reports for them,
+// such as "flowchart", "sequence" or "c4Container".
+package examples
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+//go:embed diagrams/*.mmd
+var diagrams embed.FS
+
+// Names returns the names of the examples in lexical order.
+func Names() []string {
+	entries, _ := diagrams.ReadDir("diagrams") // Embedded, so it can't fail
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".mmd"))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Get returns the source of the named example.
+func Get(name string) (string, error) {
+	data, err := diagrams.ReadFile(path.Join("diagrams", name+".mmd"))
+	if err != nil {
+		return "", fmt.Errorf("no example named %q", name)
+	}
+	return string(data), nil
+}
+
+// All returns the source of every example, keyed by name.
+func All() map[string]string {
+	all := make(map[string]string)
+	for _, name := range Names() {
+		all[name], _ = Get(name)
+	}
+	return all
+}
+
+// FS returns the examples as a file system of NAME.mmd files.
+func FS() fs.FS {
+	sub, _ := fs.Sub(diagrams, "diagrams") // Embedded, so it can't fail
+	return sub
+}
@@ -0,0 +1,80 @@
+package examples_test
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/examples"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+// supportedTypes are the diagram types parser.DetectType recognises.
+var supportedTypes = []string{
+	"flowchart", "graph", "sequence", "class", "state", "stateDiagram-v2", "er",
+	"gantt", "pie", "journey", "gitGraph", "mindmap", "timeline", "quadrantChart",
+	"xyChart", "sankey", "c4Context", "c4Container", "c4Component", "c4Dynamic", "c4Deployment",
+}
+
+func TestExamples_CoverEveryType(t *testing.T) {
+	names := examples.Names()
+	for _, diagramType := range supportedTypes {
+		if !slices.Contains(names, diagramType) {
+			t.Errorf("no example for %s", diagramType)
+		}
+	}
+}
+
+func TestExamples_Valid(t *testing.T) {
+	for name, source := range examples.All() {
+		t.Run(name, func(t *testing.T) {
+			if got := parser.DetectType(source); got != name {
+				t.Errorf("example detected as %s", got)
+			}
+			diagram, err := mermaid.Parse(source)
+			if err != nil {
+				t.Fatalf("example does not parse: %v", err)
+			}
+			// Strict too, as mermaid.Template offers the examples as templates
+			for _, strict := range []bool{false, true} {
+				if errors := mermaid.Validate(diagram, strict); len(errors) > 0 {
+					t.Errorf("example has errors (strict=%v): %v", strict, errors)
+				}
+			}
+			// As the CLI validates it, with the rules for every diagram type
+			results, err := mermaid.ValidateSource(name+".mmd", source, mermaid.ValidateOptions{})
+			if err != nil {
+				t.Fatalf("ValidateSource() error = %v", err)
+			}
+			for _, result := range results {
+				if len(result.Errors) > 0 {
+					t.Errorf("example has errors when validated as a file: %v", result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	source, err := examples.Get("sequence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parser.DetectType(source) != "sequence" {
+		t.Errorf("Get(sequence) = %q", source)
+	}
+	if _, err := examples.Get("venn"); err == nil {
+		t.Error("Get(venn) returned no error")
+	}
+}
+
+func TestFS(t *testing.T) {
+	data, err := fs.ReadFile(examples.FS(), "pie.mmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := examples.Get("pie"); string(data) != want {
+		t.Errorf("FS pie.mmd = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,200 @@
+package mermaid
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+// Explanation is a structured account of why a diagram failed to parse,
+// designed to be handed back to whatever produced the diagram, such as a
+// language model, so that it can correct it. It encodes to JSON with stable
+// field names and ordering, so explanations can be compared and diffed.
+type Explanation struct {
+	// Valid is true when the diagram parsed; the other fields are then empty
+	// apart from DiagramType.
+	Valid bool `json:"valid"`
+	// DiagramType is the type detected from the header, or "unknown".
+	DiagramType string `json:"diagramType"`
+	// Candidates are the headers an unrecognised header was most likely meant
+	// to be, closest first.
+	Candidates []string `json:"candidates,omitempty"`
+	// Line is the first failing line (1-indexed), or 0 when the failure is not
+	// tied to a line, and LineText its text.
+	Line     int    `json:"line,omitempty"`
+	LineText string `json:"lineText,omitempty"`
+	// Message describes the first failure.
+	Message string `json:"message,omitempty"`
+	// Expected lists the constructs the diagram type accepts, with examples.
+	Expected []string `json:"expected,omitempty"`
+	// Suggestions are hints for correcting the failures, in the order found.
+	Suggestions []string `json:"suggestions,omitempty"`
+	// Errors lists every syntax error found, the first of which is described
+	// by Line and Message.
+	Errors []ExplainedError `json:"errors,omitempty"`
+}
+
+// ExplainedError is one syntax error in an Explanation.
+type ExplainedError struct {
+	Line       int    `json:"line,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// headerConstruct is what Explanation.Expected holds when the header is at fault.
+const headerConstruct = "a header naming the diagram type on the first line: flowchart TD, graph LR, sequenceDiagram, classDiagram, stateDiagram-v2, erDiagram, gantt, pie, journey, timeline, gitGraph, mindmap, sankey-beta, quadrantChart, xychart-beta or C4Context (also C4Container, C4Component, C4Dynamic, C4Deployment)"
+
+// expectedConstructs lists the statements each diagram type accepts, keyed
+// by the type parser.DetectType returns.
+var expectedConstructs = map[string][]string{
+	"flowchart": {
+		"node: A[Label], with shapes A(Round), A{Decision}, A((Circle)), A[[Subroutine]], A[(Database)], A{{Hexagon}}, A>Flag]",
+		"link: A --> B, A --- B, A -.-> B, A ==> B, A ~~~ B, labelled A -->|text| B or A -- text --> B",
+		"subgraph: subgraph ID [Title] ... end, optionally with direction TB|TD|BT|RL|LR inside",
+		"styling: classDef name fill:#f9f,stroke:#333; class A,B name; style A fill:#f9f",
+		"interaction: click A \"https://example.com\" or click A callback",
+		"comment: %% text",
+	},
+	"sequence": {
+		"participant: participant A, actor A, optionally with an alias: participant A as Alice",
+		"message: A->>B: text, with arrows " + strings.Join(ast.MessageArrows, ", "),
+		"note: Note left of A: text, Note right of A: text, Note over A,B: text",
+		"activation: activate A, deactivate A, or A->>+B: text / B-->>-A: text",
+		"block: loop|alt|opt|par|critical|break|rect text ... end, with else (alt), and (par) or option (critical) inside",
+		"box: box [colour] [label] ... end around participant declarations",
+		"autonumber",
+		"comment: %% text",
+	},
+	"class": {
+		"class: class Name, or class Name { +attribute type; +method() returnType }",
+		"member: Name : +attribute type, Name : +method() returnType",
+		"relationship: A <|-- B, A *-- B, A o-- B, A --> B, A ..> B, A ..|> B, A -- B, optionally with cardinality and a label: A \"1\" --> \"*\" B : label",
+		"annotation: <<interface>> Name",
+		"namespace: namespace Name { ... }",
+		"note: note for Name \"text\"",
+		"comment: %% text",
+	},
+	"state": {
+		"transition: A --> B, A --> B : label, [*] --> A, A --> [*]",
+		"state: state \"Description\" as A, A : description",
+		"composite state: state A { ... }, with -- separating concurrent regions",
+		"pseudo-state: state A <<fork>>, <<join>> or <<choice>>",
+		"note: note left of A : text, or note right of A ... end note",
+		"comment: %% text",
+	},
+	"er": {
+		"relationship: A ||--o{ B : label, with cardinalities |o, ||, }o, }| and -- (identifying) or .. (non-identifying)",
+		"entity: NAME { type name PK|FK|UK \"comment\" }",
+		"comment: %% text",
+	},
+	"gantt": {
+		"settings: title text, dateFormat YYYY-MM-DD, axisFormat %Y-%m-%d, excludes weekends",
+		"section: section Name",
+		"task: Name : [crit, active, done, milestone,] [id,] start date or after id, end date or duration such as 3d",
+		"comment: %% text",
+	},
+	"pie": {
+		"settings: pie showData, title text",
+		"entry: \"Label\" : 42",
+		"comment: %% text",
+	},
+	"journey": {
+		"title: title text",
+		"section: section Name",
+		"task: Name: score from 1 to 5: Actor, Other actor",
+		"comment: %% text",
+	},
+	"timeline": {
+		"title: title text",
+		"section: section Name",
+		"period: 2024 : event : another event, with further events on lines starting with :",
+		"comment: %% text",
+	},
+	"gitGraph": {
+		"commit: commit, commit id: \"id\" tag: \"v1\" type: NORMAL|REVERSE|HIGHLIGHT",
+		"branch: branch name, checkout name (or switch name), merge name",
+		"cherry-pick: cherry-pick id: \"id\"",
+		"comment: %% text",
+	},
+	"mindmap": {
+		"root: the first node, e.g. root((Topic))",
+		"node: text indented further than its parent, with optional shapes [Square], (Rounded), ((Circle)), {{Hexagon}}",
+		"icon and class: ::icon(fa fa-book), :::className",
+	},
+	"sankey": {
+		"link: source,target,value, with values as numbers and commas inside fields quoted",
+	},
+	"quadrantChart": {
+		"settings: title text, x-axis Low --> High, y-axis Low --> High",
+		"quadrant label: quadrant-1 text to quadrant-4 text",
+		"point: Name: [0.3, 0.6], with coordinates between 0 and 1",
+	},
+	"xyChart": {
+		"settings: title \"text\", x-axis [a, b, c] or x-axis \"label\" 0 --> 100, y-axis \"label\" 0 --> 100",
+		"series: bar [1, 2, 3], line [1, 2, 3]",
+	},
+	"c4": {
+		"element: Person(alias, \"Label\", \"Description\"), System(...), System_Ext(...), Container(...), Component(...), ContainerDb(...)",
+		"boundary: Boundary(alias, \"Label\") { ... }, Enterprise_Boundary, System_Boundary, Container_Boundary",
+		"relationship: Rel(from, to, \"Label\"), BiRel(...), Rel_U/Rel_D/Rel_L/Rel_R(...)",
+		"deployment: Deployment_Node(alias, \"Label\") { ... }",
+		"style: UpdateElementStyle(alias, $bgColor=\"grey\"), UpdateRelStyle(from, to, ...)",
+	},
+}
+
+// expectedFor returns the constructs accepted by diagType.
+func expectedFor(diagType string) []string {
+	switch {
+	case diagType == "graph":
+		diagType = "flowchart"
+	case diagType == "stateDiagram-v2":
+		diagType = "state"
+	case strings.HasPrefix(diagType, "c4"):
+		diagType = "c4"
+	}
+	return expectedConstructs[diagType]
+}
+
+// Explain parses source, a raw Mermaid diagram, and explains any failure: the
+// detected diagram type or likely intended headers, the first failing line,
+// the constructs the diagram type accepts and suggestions for fixing it. It
+// reports every syntax error it can find, as ParseRecovering does.
+func Explain(source string) Explanation {
+	source = inpututil.NormaliseNewlines(source)
+	explanation := Explanation{DiagramType: parser.DetectType(source)}
+
+	diagram, errs := parser.ParseRecovering(source)
+	if len(errs) == 0 && diagram != nil {
+		explanation.Valid = true
+		explanation.DiagramType = diagram.GetType()
+		return explanation
+	}
+
+	explanation.Candidates = parser.HeaderCandidates(source)
+	for _, err := range errs {
+		explanation.Errors = append(explanation.Errors, ExplainedError{Line: err.Line, Message: err.Message, Suggestion: err.Suggestion})
+		if err.Suggestion != "" && !slices.Contains(explanation.Suggestions, err.Suggestion) {
+			explanation.Suggestions = append(explanation.Suggestions, err.Suggestion)
+		}
+	}
+
+	if len(errs) > 0 {
+		first := errs[0]
+		explanation.Message = first.Message
+		lines := strings.Split(source, "\n")
+		if first.Line >= 1 && first.Line <= len(lines) {
+			explanation.Line = first.Line
+			explanation.LineText = strings.TrimSpace(lines[first.Line-1])
+		}
+	}
+
+	if explanation.DiagramType == "unknown" || strings.Contains(explanation.Message, "header") {
+		explanation.Expected = []string{headerConstruct}
+	} else {
+		explanation.Expected = expectedFor(explanation.DiagramType)
+	}
+	return explanation
+}
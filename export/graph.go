@@ -0,0 +1,329 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// graph is a flowchart or state diagram reduced to labelled nodes and edges.
+type graph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+type graphNode struct {
+	id     string
+	label  string
+	parent string // Innermost subgraph or composite state, if any
+}
+
+type graphEdge struct {
+	source, target string
+	label          string
+	arrow          string // Arrow as written, such as "-->" or "-.->"
+	undirected     bool
+}
+
+// Start and end pseudo-states of a state diagram, named so they can't clash
+// with state IDs.
+const (
+	stateStartID = "[*]start"
+	stateEndID   = "[*]end"
+)
+
+// graphOf reduces diagram to a graph, for GraphML and GEXF.
+func graphOf(diagram ast.Diagram) (*graph, error) {
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		return flowchartGraph(d), nil
+	case *ast.StateDiagram:
+		g := &graph{}
+		seen := make(map[string]bool)
+		stateGraph(g, d.Statements, "", seen)
+		return g, nil
+	}
+	return nil, fmt.Errorf("cannot export %s diagrams as a graph: only flowcharts and state diagrams are supported", diagram.GetType())
+}
+
+// addNode adds the node id unless seen already has it.
+func (g *graph) addNode(id, label, parent string, seen map[string]bool) {
+	if seen[id] {
+		return
+	}
+	seen[id] = true
+	g.nodes = append(g.nodes, graphNode{id: id, label: label, parent: parent})
+}
+
+// flowchartGraph lists every node at its first mention, labelled from its
+// definition and placed in the subgraph Mermaid draws it in.
+func flowchartGraph(flowchart *ast.Flowchart) *graph {
+	g := &graph{}
+	labels := make(map[string]string)
+	var links []*ast.Link
+	collectFlowchart(flowchart.Statements, labels, &links)
+
+	membership := analysis.SubgraphOf(flowchart)
+	seen := make(map[string]bool)
+	for _, mention := range analysis.Mentions(flowchart) {
+		label := labels[mention.Node]
+		if label == "" {
+			label = mention.Node
+		}
+		parent := ""
+		if path := membership[mention.Node]; len(path) > 0 {
+			parent = path[len(path)-1]
+		}
+		g.addNode(mention.Node, label, parent, seen)
+	}
+	for _, link := range links {
+		// Links to or from a subgraph have no node to attach to
+		if !seen[link.From] || !seen[link.To] {
+			continue
+		}
+		g.edges = append(g.edges, graphEdge{
+			source:     link.From,
+			target:     link.To,
+			label:      link.Label,
+			arrow:      link.Arrow,
+			undirected: link.BiDir,
+		})
+	}
+	return g
+}
+
+func collectFlowchart(statements []ast.Statement, labels map[string]string, links *[]*ast.Link) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			if s.Label != "" {
+				labels[s.ID] = s.Label
+			}
+		case *ast.Link:
+			*links = append(*links, s)
+		case *ast.Subgraph:
+			collectFlowchart(s.Statements, labels, links)
+		}
+	}
+}
+
+// stateGraph adds the states and transitions in statements, which belong to
+// the composite state parent, to g. Start and end states are shared by every
+// level of the diagram.
+func stateGraph(g *graph, statements []ast.StateStmt, parent string, seen map[string]bool) {
+	// state adds the state id, or pseudo when id is "[*]", and returns its node
+	state := func(id, pseudo, label string) string {
+		if id == "[*]" {
+			g.addNode(pseudo, label, "", seen)
+			return pseudo
+		}
+		g.addNode(id, id, parent, seen)
+		return id
+	}
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.State:
+			label := s.Description
+			if label == "" {
+				label = s.ID
+			}
+			g.addNode(s.ID, label, parent, seen)
+			if s.IsComposite {
+				stateGraph(g, s.Nested, s.ID, seen)
+			}
+		case *ast.Transition:
+			source := state(s.From, stateStartID, "start")
+			target := state(s.To, stateEndID, "end")
+			g.edges = append(g.edges, graphEdge{source: source, target: target, label: s.Label, arrow: "-->"})
+		case *ast.StartState:
+			source := state("[*]", stateStartID, "start")
+			g.edges = append(g.edges, graphEdge{source: source, target: state(s.To, stateStartID, "start"), arrow: "-->"})
+		case *ast.EndState:
+			source := state(s.From, stateEndID, "end")
+			g.edges = append(g.edges, graphEdge{source: source, target: state("[*]", stateEndID, "end"), arrow: "-->"})
+		}
+	}
+}
+
+// GraphML XML elements.
+type (
+	graphMLDocument struct {
+		XMLName xml.Name     `xml:"graphml"`
+		Xmlns   string       `xml:"xmlns,attr"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+	graphMLEdge struct {
+		ID       string        `xml:"id,attr"`
+		Source   string        `xml:"source,attr"`
+		Target   string        `xml:"target,attr"`
+		Directed *bool         `xml:"directed,attr,omitempty"`
+		Data     []graphMLData `xml:"data"`
+	}
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// GraphML converts a flowchart or state diagram into a GraphML document, for
+// layout and analysis in tools such as yEd and Gephi. Nodes carry their label
+// and the subgraph or composite state they are drawn in as "label" and
+// "parent" data; edges carry their label and arrow as "label" and "arrow"
+// data. Bidirectional flowchart links are undirected edges. Other diagram
+// types return an error.
+func GraphML(diagram ast.Diagram) (string, error) {
+	g, err := graphOf(diagram)
+	if err != nil {
+		return "", err
+	}
+
+	doc := graphMLDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "parent", For: "node", Name: "parent", Type: "string"},
+			{ID: "edge_label", For: "edge", Name: "label", Type: "string"},
+			{ID: "arrow", For: "edge", Name: "arrow", Type: "string"},
+		},
+		Graph: graphMLGraph{ID: diagram.GetType(), EdgeDefault: "directed"},
+	}
+	for _, node := range g.nodes {
+		element := graphMLNode{ID: node.id, Data: []graphMLData{{Key: "label", Value: node.label}}}
+		if node.parent != "" {
+			element.Data = append(element.Data, graphMLData{Key: "parent", Value: node.parent})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, element)
+	}
+	for i, edge := range g.edges {
+		element := graphMLEdge{ID: "e" + strconv.Itoa(i), Source: edge.source, Target: edge.target}
+		if edge.undirected {
+			directed := false
+			element.Directed = &directed
+		}
+		if edge.label != "" {
+			element.Data = append(element.Data, graphMLData{Key: "edge_label", Value: edge.label})
+		}
+		element.Data = append(element.Data, graphMLData{Key: "arrow", Value: edge.arrow})
+		doc.Graph.Edges = append(doc.Graph.Edges, element)
+	}
+	return marshalXML(doc)
+}
+
+// GEXF XML elements.
+type (
+	gexfDocument struct {
+		XMLName xml.Name  `xml:"gexf"`
+		Xmlns   string    `xml:"xmlns,attr"`
+		Version string    `xml:"version,attr"`
+		Graph   gexfGraph `xml:"graph"`
+	}
+	gexfGraph struct {
+		DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+		Attributes      []gexfAttributes `xml:"attributes"`
+		Nodes           []gexfNode       `xml:"nodes>node"`
+		Edges           []gexfEdge       `xml:"edges>edge"`
+	}
+	gexfAttributes struct {
+		Class      string          `xml:"class,attr"`
+		Attributes []gexfAttribute `xml:"attribute"`
+	}
+	gexfAttribute struct {
+		ID    string `xml:"id,attr"`
+		Title string `xml:"title,attr"`
+		Type  string `xml:"type,attr"`
+	}
+	gexfNode struct {
+		ID        string         `xml:"id,attr"`
+		Label     string         `xml:"label,attr"`
+		AttValues *gexfAttValues `xml:"attvalues,omitempty"`
+	}
+	gexfEdge struct {
+		ID        string         `xml:"id,attr"`
+		Source    string         `xml:"source,attr"`
+		Target    string         `xml:"target,attr"`
+		Type      string         `xml:"type,attr,omitempty"`
+		Label     string         `xml:"label,attr,omitempty"`
+		AttValues *gexfAttValues `xml:"attvalues"`
+	}
+	gexfAttValues struct {
+		Values []gexfAttValue `xml:"attvalue"`
+	}
+	gexfAttValue struct {
+		For   string `xml:"for,attr"`
+		Value string `xml:"value,attr"`
+	}
+)
+
+// GEXF converts a flowchart or state diagram into a GEXF 1.3 document, for
+// Gephi. Nodes and edges carry their labels as GEXF labels, nodes the
+// subgraph or composite state they are drawn in as a "parent" attribute, and
+// edges their arrow as an "arrow" attribute. Bidirectional flowchart links are
+// undirected edges. Other diagram types return an error.
+func GEXF(diagram ast.Diagram) (string, error) {
+	g, err := graphOf(diagram)
+	if err != nil {
+		return "", err
+	}
+
+	doc := gexfDocument{
+		Xmlns:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: []gexfAttribute{{ID: "parent", Title: "parent", Type: "string"}}},
+				{Class: "edge", Attributes: []gexfAttribute{{ID: "arrow", Title: "arrow", Type: "string"}}},
+			},
+		},
+	}
+	for _, node := range g.nodes {
+		element := gexfNode{ID: node.id, Label: node.label}
+		if node.parent != "" {
+			element.AttValues = &gexfAttValues{[]gexfAttValue{{For: "parent", Value: node.parent}}}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, element)
+	}
+	for i, edge := range g.edges {
+		element := gexfEdge{
+			ID:        strconv.Itoa(i),
+			Source:    edge.source,
+			Target:    edge.target,
+			Label:     edge.label,
+			AttValues: &gexfAttValues{[]gexfAttValue{{For: "arrow", Value: edge.arrow}}},
+		}
+		if edge.undirected {
+			element.Type = "undirected"
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, element)
+	}
+	return marshalXML(doc)
+}
+
+// marshalXML renders doc as an indented XML document with a declaration.
+func marshalXML(doc any) (string, error) {
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
@@ -0,0 +1,294 @@
+// Package export converts parsed diagrams into other formats, for workflows
+// where the diagram in the documentation is the source of truth.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// Dialect is an SQL dialect SQL can generate.
+type Dialect string
+
+// Supported SQL dialects.
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+// Dialects lists the supported SQL dialects.
+var Dialects = []Dialect{Postgres, MySQL, SQLite}
+
+// ParseDialect returns the dialect named name, accepting "postgresql" for
+// Postgres and ignoring case.
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "postgres", "postgresql":
+		return Postgres, nil
+	case "mysql":
+		return MySQL, nil
+	case "sqlite":
+		return SQLite, nil
+	}
+	return "", fmt.Errorf("unknown SQL dialect %q (supported: postgres, mysql, sqlite)", name)
+}
+
+// sqlType is a column type in each dialect. Types taking a length or
+// precision, such as varchar(255), keep it where the dialect uses one.
+type sqlType struct {
+	postgres, mysql, sqlite string
+	sized                   bool // Keeps a (length) or (precision, scale) suffix
+}
+
+// sqlTypes maps ER attribute types, lower-cased and without any size suffix,
+// to SQL column types.
+var sqlTypes = map[string]sqlType{
+	"string":    {"TEXT", "VARCHAR(255)", "TEXT", false},
+	"text":      {"TEXT", "TEXT", "TEXT", false},
+	"varchar":   {"VARCHAR", "VARCHAR", "TEXT", true},
+	"char":      {"CHAR", "CHAR", "TEXT", true},
+	"int":       {"INTEGER", "INT", "INTEGER", false},
+	"integer":   {"INTEGER", "INT", "INTEGER", false},
+	"smallint":  {"SMALLINT", "SMALLINT", "INTEGER", false},
+	"short":     {"SMALLINT", "SMALLINT", "INTEGER", false},
+	"bigint":    {"BIGINT", "BIGINT", "INTEGER", false},
+	"long":      {"BIGINT", "BIGINT", "INTEGER", false},
+	"float":     {"REAL", "FLOAT", "REAL", false},
+	"real":      {"REAL", "FLOAT", "REAL", false},
+	"double":    {"DOUBLE PRECISION", "DOUBLE", "REAL", false},
+	"decimal":   {"NUMERIC", "DECIMAL", "NUMERIC", true},
+	"numeric":   {"NUMERIC", "DECIMAL", "NUMERIC", true},
+	"bool":      {"BOOLEAN", "BOOLEAN", "INTEGER", false},
+	"boolean":   {"BOOLEAN", "BOOLEAN", "INTEGER", false},
+	"date":      {"DATE", "DATE", "TEXT", false},
+	"datetime":  {"TIMESTAMP", "DATETIME", "TEXT", false},
+	"timestamp": {"TIMESTAMP", "TIMESTAMP", "TEXT", false},
+	"time":      {"TIME", "TIME", "TEXT", false},
+	"uuid":      {"UUID", "CHAR(36)", "TEXT", false},
+	"guid":      {"UUID", "CHAR(36)", "TEXT", false},
+	"json":      {"JSON", "JSON", "TEXT", false},
+	"jsonb":     {"JSONB", "JSON", "TEXT", false},
+	"blob":      {"BYTEA", "BLOB", "BLOB", false},
+	"binary":    {"BYTEA", "BLOB", "BLOB", false},
+	"bytes":     {"BYTEA", "BLOB", "BLOB", false},
+	"bytea":     {"BYTEA", "BLOB", "BLOB", false},
+}
+
+// sizedTypePattern splits an attribute type such as varchar(255) into its base
+// type and size.
+var sizedTypePattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)(\(\s*\d+\s*(?:,\s*\d+\s*)?\))?$`)
+
+// SQLType returns the column type for an ER attribute type in dialect, and
+// false if the type has no equivalent. A size such as the 64 of varchar(64) is
+// kept for types that take one in the dialect and dropped otherwise.
+func SQLType(attributeType string, dialect Dialect) (string, bool) {
+	match := sizedTypePattern.FindStringSubmatch(attributeType)
+	if match == nil {
+		return "", false
+	}
+	mapped, ok := sqlTypes[strings.ToLower(match[1])]
+	if !ok {
+		return "", false
+	}
+
+	var column string
+	switch dialect {
+	case Postgres:
+		column = mapped.postgres
+	case MySQL:
+		column = mapped.mysql
+	case SQLite:
+		column = mapped.sqlite
+	default:
+		return "", false
+	}
+	if mapped.sized && match[2] != "" && dialect != SQLite {
+		column += strings.ReplaceAll(match[2], " ", "")
+	}
+	return column, true
+}
+
+// SQL converts an ER diagram into CREATE TABLE statements in dialect. Each
+// entity with attributes becomes a table, its PK attributes the primary key
+// and its UK attributes unique. An FK attribute references the primary key of
+// a related entity: the one whose name it starts or ends with (as customer_id
+// or customerId for CUSTOMER), or the only related entity with a single-column
+// primary key. Attribute comments become SQL comments. Tables are ordered so
+// that each follows the tables it references, where possible.
+//
+// SQL returns an error naming every attribute whose type can't be mapped (see
+// SQLType), and generates nothing in that case.
+func SQL(diagram *ast.ERDiagram, dialect Dialect) (string, error) {
+	if !slices.Contains(Dialects, dialect) {
+		return "", fmt.Errorf("unknown SQL dialect %q (supported: postgres, mysql, sqlite)", dialect)
+	}
+
+	var unmapped []error
+	for _, entity := range diagram.Entities {
+		for _, attr := range entity.Attributes {
+			if _, ok := SQLType(attr.Type, dialect); !ok {
+				unmapped = append(unmapped, fmt.Errorf("line %d: attribute '%s' of '%s' has type '%s', which has no %s equivalent",
+					attr.Pos.Line, attr.Name, entity.Name, attr.Type, dialect))
+			}
+		}
+	}
+	if len(unmapped) > 0 {
+		return "", errors.Join(unmapped...)
+	}
+
+	entities := make(map[string]*ast.EREntity)
+	for i := range diagram.Entities {
+		entities[diagram.Entities[i].Name] = &diagram.Entities[i]
+	}
+
+	var b strings.Builder
+	for _, entity := range tableOrder(diagram, entities) {
+		if len(entity.Attributes) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		writeTable(&b, diagram, entities, entity, dialect)
+	}
+	return b.String(), nil
+}
+
+// writeTable writes the CREATE TABLE statement for entity.
+func writeTable(b *strings.Builder, diagram *ast.ERDiagram, entities map[string]*ast.EREntity, entity *ast.EREntity, dialect Dialect) {
+	type line struct{ text, comment string }
+	var lines []line
+	var primary []string
+	for _, attr := range entity.Attributes {
+		column, _ := SQLType(attr.Type, dialect)
+		text := quoteIdentifier(attr.Name, dialect) + " " + column
+		if slices.Contains(attr.Keys, "PK") {
+			primary = append(primary, quoteIdentifier(attr.Name, dialect))
+			text += " NOT NULL"
+		}
+		if slices.Contains(attr.Keys, "UK") {
+			text += " UNIQUE"
+		}
+		if slices.Contains(attr.Keys, "FK") {
+			if target, key := foreignKey(diagram, entities, entity, attr.Name); target != nil {
+				text += fmt.Sprintf(" REFERENCES %s (%s)", quoteIdentifier(target.Name, dialect), quoteIdentifier(key, dialect))
+			}
+		}
+		lines = append(lines, line{text, attr.Comment})
+	}
+	if len(primary) > 0 {
+		lines = append(lines, line{text: "PRIMARY KEY (" + strings.Join(primary, ", ") + ")"})
+	}
+
+	fmt.Fprintf(b, "CREATE TABLE %s (\n", quoteIdentifier(entity.Name, dialect))
+	for i, l := range lines {
+		b.WriteString("    " + l.text)
+		if i < len(lines)-1 {
+			b.WriteString(",")
+		}
+		if l.comment != "" {
+			b.WriteString(" -- " + strings.ReplaceAll(l.comment, "\n", " "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+}
+
+// foreignKey returns the entity an FK attribute of entity references and the
+// column it references, or nil if it can't be determined.
+func foreignKey(diagram *ast.ERDiagram, entities map[string]*ast.EREntity, entity *ast.EREntity, attribute string) (*ast.EREntity, string) {
+	var related []*ast.EREntity
+	for _, rel := range diagram.Relationships {
+		var other string
+		switch entity.Name {
+		case rel.From:
+			other = rel.To
+		case rel.To:
+			other = rel.From
+		default:
+			continue
+		}
+		if target, ok := entities[other]; ok && target != entity && !slices.Contains(related, target) {
+			if singlePrimaryKey(target) != "" {
+				related = append(related, target)
+			}
+		}
+	}
+
+	name := normaliseIdentifier(attribute)
+	for _, target := range related {
+		prefix := normaliseIdentifier(target.Name)
+		if strings.HasPrefix(name, prefix) || strings.HasSuffix(name, prefix) {
+			return target, singlePrimaryKey(target)
+		}
+	}
+	if len(related) == 1 {
+		return related[0], singlePrimaryKey(related[0])
+	}
+	return nil, ""
+}
+
+// singlePrimaryKey returns the name of entity's primary key attribute, or ""
+// unless it has exactly one.
+func singlePrimaryKey(entity *ast.EREntity) string {
+	var key string
+	for _, attr := range entity.Attributes {
+		if slices.Contains(attr.Keys, "PK") {
+			if key != "" {
+				return ""
+			}
+			key = attr.Name
+		}
+	}
+	return key
+}
+
+// tableOrder returns the entities of diagram ordered so that each follows the
+// entities its foreign keys reference, keeping diagram order otherwise. Cycles
+// are broken by diagram order.
+func tableOrder(diagram *ast.ERDiagram, entities map[string]*ast.EREntity) []*ast.EREntity {
+	var order []*ast.EREntity
+	state := make(map[*ast.EREntity]int) // 1 while visiting, 2 once ordered
+	var visit func(entity *ast.EREntity)
+	visit = func(entity *ast.EREntity) {
+		if state[entity] != 0 {
+			return
+		}
+		state[entity] = 1
+		for _, attr := range entity.Attributes {
+			if !slices.Contains(attr.Keys, "FK") {
+				continue
+			}
+			if target, _ := foreignKey(diagram, entities, entity, attr.Name); target != nil {
+				visit(target)
+			}
+		}
+		state[entity] = 2
+		order = append(order, entity)
+	}
+	for i := range diagram.Entities {
+		visit(&diagram.Entities[i])
+	}
+	return order
+}
+
+// quoteIdentifier quotes a table or column name for dialect, since ER entity
+// names are often reserved words (ORDER) or contain hyphens (LINE-ITEM).
+func quoteIdentifier(name string, dialect Dialect) string {
+	if dialect == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// normaliseIdentifier lower-cases name and drops underscores and hyphens, so
+// that customer_id, customerId and CUSTOMER-ID compare equal.
+func normaliseIdentifier(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,212 @@
+package export_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/export"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+const pipeline = `flowchart LR
+    A[Start] -->|go| B{Check}
+    B <--> C
+    subgraph S [Stage]
+        C
+    end`
+
+func parseDiagram(t *testing.T, source string) ast.Diagram {
+	t.Helper()
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return diagram
+}
+
+// graphML is the part of a GraphML document the tests look at.
+type graphML struct {
+	Graph struct {
+		EdgeDefault string `xml:"edgedefault,attr"`
+		Nodes       []struct {
+			ID   string        `xml:"id,attr"`
+			Data []graphMLData `xml:"data"`
+		} `xml:"node"`
+		Edges []struct {
+			Source   string        `xml:"source,attr"`
+			Target   string        `xml:"target,attr"`
+			Directed string        `xml:"directed,attr"`
+			Data     []graphMLData `xml:"data"`
+		} `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func dataString(data []graphMLData) string {
+	var parts []string
+	for _, d := range data {
+		parts = append(parts, d.Key+"="+d.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestGraphML_Flowchart(t *testing.T) {
+	out, err := export.GraphML(parseDiagram(t, pipeline))
+	if err != nil {
+		t.Fatalf("GraphML() error = %v", err)
+	}
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Errorf("GraphML() missing XML declaration:\n%s", out)
+	}
+	var doc graphML
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GraphML() is not valid XML: %v\n%s", err, out)
+	}
+
+	var nodes []string
+	for _, node := range doc.Graph.Nodes {
+		nodes = append(nodes, node.ID+": "+dataString(node.Data))
+	}
+	wantNodes := []string{"A: label=Start", "B: label=Check", "C: label=C parent=S"}
+	if strings.Join(nodes, "\n") != strings.Join(wantNodes, "\n") {
+		t.Errorf("nodes = %q, want %q", nodes, wantNodes)
+	}
+
+	var edges []string
+	for _, edge := range doc.Graph.Edges {
+		edges = append(edges, edge.Source+"->"+edge.Target+" directed="+edge.Directed+": "+dataString(edge.Data))
+	}
+	wantEdges := []string{"A->B directed=: edge_label=go arrow=-->", "B->C directed=false: arrow=<-->"}
+	if strings.Join(edges, "\n") != strings.Join(wantEdges, "\n") {
+		t.Errorf("edges = %q, want %q", edges, wantEdges)
+	}
+}
+
+func TestGraphML_StateDiagram(t *testing.T) {
+	diagram := &ast.StateDiagram{
+		Type: "stateDiagram-v2",
+		Statements: []ast.StateStmt{
+			&ast.Transition{From: "[*]", To: "Idle"},
+			&ast.State{ID: "Busy", Description: "Working hard", IsComposite: true, Nested: []ast.StateStmt{
+				&ast.Transition{From: "[*]", To: "Work"},
+				&ast.Transition{From: "Work", To: "[*]"},
+			}},
+			&ast.Transition{From: "Idle", To: "Busy", Label: "start"},
+		},
+	}
+	out, err := export.GraphML(diagram)
+	if err != nil {
+		t.Fatalf("GraphML() error = %v", err)
+	}
+	var doc graphML
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GraphML() is not valid XML: %v\n%s", err, out)
+	}
+
+	var nodes []string
+	for _, node := range doc.Graph.Nodes {
+		nodes = append(nodes, node.ID+": "+dataString(node.Data))
+	}
+	wantNodes := []string{
+		"[*]start: label=start",
+		"Idle: label=Idle",
+		"Busy: label=Working hard",
+		"Work: label=Work parent=Busy",
+		"[*]end: label=end",
+	}
+	if strings.Join(nodes, "\n") != strings.Join(wantNodes, "\n") {
+		t.Errorf("nodes = %q, want %q", nodes, wantNodes)
+	}
+
+	var edges []string
+	for _, edge := range doc.Graph.Edges {
+		edges = append(edges, edge.Source+"->"+edge.Target+": "+dataString(edge.Data))
+	}
+	wantEdges := []string{
+		"[*]start->Idle: arrow=-->",
+		"[*]start->Work: arrow=-->",
+		"Work->[*]end: arrow=-->",
+		"Idle->Busy: edge_label=start arrow=-->",
+	}
+	if strings.Join(edges, "\n") != strings.Join(wantEdges, "\n") {
+		t.Errorf("edges = %q, want %q", edges, wantEdges)
+	}
+}
+
+func TestGEXF_Flowchart(t *testing.T) {
+	out, err := export.GEXF(parseDiagram(t, pipeline))
+	if err != nil {
+		t.Fatalf("GEXF() error = %v", err)
+	}
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Graph   struct {
+			Nodes []struct {
+				ID        string `xml:"id,attr"`
+				Label     string `xml:"label,attr"`
+				AttValues []struct {
+					For   string `xml:"for,attr"`
+					Value string `xml:"value,attr"`
+				} `xml:"attvalues>attvalue"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Source    string `xml:"source,attr"`
+				Target    string `xml:"target,attr"`
+				Type      string `xml:"type,attr"`
+				Label     string `xml:"label,attr"`
+				AttValues []struct {
+					For   string `xml:"for,attr"`
+					Value string `xml:"value,attr"`
+				} `xml:"attvalues>attvalue"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GEXF() is not valid XML: %v\n%s", err, out)
+	}
+	if doc.Version != "1.3" {
+		t.Errorf("version = %q, want 1.3", doc.Version)
+	}
+
+	var nodes []string
+	for _, node := range doc.Graph.Nodes {
+		entry := node.ID + ": " + node.Label
+		for _, value := range node.AttValues {
+			entry += " " + value.For + "=" + value.Value
+		}
+		nodes = append(nodes, entry)
+	}
+	wantNodes := []string{"A: Start", "B: Check", "C: C parent=S"}
+	if strings.Join(nodes, "\n") != strings.Join(wantNodes, "\n") {
+		t.Errorf("nodes = %q, want %q", nodes, wantNodes)
+	}
+
+	var edges []string
+	for _, edge := range doc.Graph.Edges {
+		entry := edge.Source + "->" + edge.Target + " " + edge.Type + ": " + edge.Label
+		for _, value := range edge.AttValues {
+			entry += " " + value.For + "=" + value.Value
+		}
+		edges = append(edges, entry)
+	}
+	wantEdges := []string{"A->B : go arrow=-->", "B->C undirected:  arrow=<-->"}
+	if strings.Join(edges, "\n") != strings.Join(wantEdges, "\n") {
+		t.Errorf("edges = %q, want %q", edges, wantEdges)
+	}
+}
+
+func TestGraphExport_UnsupportedType(t *testing.T) {
+	diagram := parseDiagram(t, shopSchema)
+	if _, err := export.GraphML(diagram); err == nil {
+		t.Error("GraphML() error = nil, want error for an ER diagram")
+	}
+	if _, err := export.GEXF(diagram); err == nil {
+		t.Error("GEXF() error = nil, want error for an ER diagram")
+	}
+}
@@ -0,0 +1,144 @@
+package export_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/export"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+const shopSchema = `erDiagram
+    ORDER ||--|{ LINE-ITEM : contains
+    CUSTOMER ||--o{ ORDER : places
+    LINE-ITEM {
+        int order_id PK, FK
+        int line PK
+        varchar(64) sku
+    }
+    ORDER {
+        int id PK
+        int customerId FK
+        datetime placed
+    }
+    CUSTOMER {
+        int id PK
+        string email UK "login address"
+    }`
+
+func parseER(t *testing.T, source string) *ast.ERDiagram {
+	t.Helper()
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return diagram.(*ast.ERDiagram)
+}
+
+func TestSQL_Postgres(t *testing.T) {
+	got, err := export.SQL(parseER(t, shopSchema), export.Postgres)
+	if err != nil {
+		t.Fatalf("SQL() error = %v", err)
+	}
+	want := `CREATE TABLE "CUSTOMER" (
+    "id" INTEGER NOT NULL,
+    "email" TEXT UNIQUE, -- login address
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "ORDER" (
+    "id" INTEGER NOT NULL,
+    "customerId" INTEGER REFERENCES "CUSTOMER" ("id"),
+    "placed" TIMESTAMP,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE "LINE-ITEM" (
+    "order_id" INTEGER NOT NULL REFERENCES "ORDER" ("id"),
+    "line" INTEGER NOT NULL,
+    "sku" VARCHAR(64),
+    PRIMARY KEY ("order_id", "line")
+);
+`
+	if got != want {
+		t.Errorf("SQL() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSQL_Dialects(t *testing.T) {
+	diagram := parseER(t, shopSchema)
+
+	mysql, err := export.SQL(diagram, export.MySQL)
+	if err != nil {
+		t.Fatalf("SQL(mysql) error = %v", err)
+	}
+	for _, want := range []string{"CREATE TABLE `ORDER` (", "`placed` DATETIME", "`email` VARCHAR(255) UNIQUE"} {
+		if !strings.Contains(mysql, want) {
+			t.Errorf("SQL(mysql) does not contain %q:\n%s", want, mysql)
+		}
+	}
+
+	sqlite, err := export.SQL(diagram, export.SQLite)
+	if err != nil {
+		t.Fatalf("SQL(sqlite) error = %v", err)
+	}
+	for _, want := range []string{`"placed" TEXT`, `"sku" TEXT`} {
+		if !strings.Contains(sqlite, want) {
+			t.Errorf("SQL(sqlite) does not contain %q:\n%s", want, sqlite)
+		}
+	}
+}
+
+func TestSQL_UnmappedTypes(t *testing.T) {
+	diagram := parseER(t, "erDiagram\n    PRODUCT {\n        int id PK\n        money price\n        Colour shade\n    }")
+	_, err := export.SQL(diagram, export.Postgres)
+	if err == nil {
+		t.Fatal("SQL() returned no error for unmapped types")
+	}
+	for _, want := range []string{"line 4: attribute 'price' of 'PRODUCT' has type 'money'", "line 5: attribute 'shade'"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	if _, err := export.SQL(diagram, export.Dialect("oracle")); err == nil {
+		t.Error("SQL() returned no error for an unknown dialect")
+	}
+}
+
+func TestSQLType(t *testing.T) {
+	tests := []struct {
+		attributeType string
+		dialect       export.Dialect
+		want          string
+		ok            bool
+	}{
+		{"string", export.Postgres, "TEXT", true},
+		{"String", export.MySQL, "VARCHAR(255)", true},
+		{"varchar(32)", export.Postgres, "VARCHAR(32)", true},
+		{"varchar(32)", export.SQLite, "TEXT", true},
+		{"bool", export.SQLite, "INTEGER", true},
+		{"uuid", export.MySQL, "CHAR(36)", true},
+		{"int(11)", export.Postgres, "INTEGER", true},
+		{"money", export.Postgres, "", false},
+		{"string[]", export.Postgres, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := export.SQLType(tt.attributeType, tt.dialect)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("SQLType(%q, %s) = %q, %v, want %q, %v", tt.attributeType, tt.dialect, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	for name, want := range map[string]export.Dialect{"postgres": export.Postgres, "PostgreSQL": export.Postgres, "mysql": export.MySQL, "SQLite": export.SQLite} {
+		if got, err := export.ParseDialect(name); err != nil || got != want {
+			t.Errorf("ParseDialect(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := export.ParseDialect("oracle"); err == nil {
+		t.Error("ParseDialect(oracle) returned no error")
+	}
+}
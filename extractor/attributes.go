@@ -0,0 +1,93 @@
+package extractor
+
+import "strings"
+
+// ParseFenceAttributes parses the attributes in the info string of a code
+// fence, the text after "mermaid" in a line such as
+//
+//	```mermaid {caption="Request flow" id=fig-1 .wide}
+//
+// Attributes are key=value pairs, with values optionally in single or double
+// quotes (a backslash escapes the quote character). Following the Pandoc
+// convention, "#name" sets "id" and ".name" adds to the space-separated
+// "class". A bare word such as showLineNumbers is recorded with an empty
+// value. Surrounding braces are optional. It returns nil when info holds no
+// attributes.
+func ParseFenceAttributes(info string) map[string]string {
+	info = strings.TrimSpace(info)
+	info = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(info, "{"), "}"))
+
+	var attrs map[string]string
+	set := func(key, value string) {
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		attrs[key] = value
+	}
+
+	for i := 0; i < len(info); {
+		if info[i] == ' ' || info[i] == '\t' || info[i] == ',' {
+			i++
+			continue
+		}
+
+		// Read the key, up to whitespace or "="
+		start := i
+		for i < len(info) && info[i] != ' ' && info[i] != '\t' && info[i] != ',' && info[i] != '=' {
+			i++
+		}
+		key := info[start:i]
+
+		var value string
+		if i < len(info) && info[i] == '=' {
+			i++
+			value, i = readAttributeValue(info, i)
+		}
+
+		switch {
+		case strings.HasPrefix(key, "#") && len(key) > 1:
+			set("id", key[1:])
+		case strings.HasPrefix(key, ".") && len(key) > 1:
+			if class := attrs["class"]; class != "" {
+				set("class", class+" "+key[1:])
+			} else {
+				set("class", key[1:])
+			}
+		case key != "":
+			set(key, value)
+		}
+	}
+	return attrs
+}
+
+// readAttributeValue reads the attribute value starting at info[i], which may
+// be quoted, returning it and the index after it.
+func readAttributeValue(info string, i int) (string, int) {
+	if i >= len(info) {
+		return "", i
+	}
+
+	quote := info[i]
+	if quote != '"' && quote != '\'' {
+		start := i
+		for i < len(info) && info[i] != ' ' && info[i] != '\t' && info[i] != ',' {
+			i++
+		}
+		return info[start:i], i
+	}
+
+	var value strings.Builder
+	for i++; i < len(info); i++ {
+		switch {
+		case info[i] == '\\' && i+1 < len(info) && (info[i+1] == quote || info[i+1] == '\\'):
+			i++
+			value.WriteByte(info[i])
+		case info[i] == quote:
+			return value.String(), i + 1
+		default:
+			value.WriteByte(info[i])
+		}
+	}
+	// Unterminated quote: keep what was read
+	return value.String(), i
+}
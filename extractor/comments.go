@@ -0,0 +1,321 @@
+package extractor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// CommentSyntax is the comment syntax of a programming language, which decides
+// where ExtractFromComments looks for diagrams.
+type CommentSyntax int
+
+const (
+	// SlashComments are the // line comments and /* */ block comments
+	// (including /** */ doc comments) of Go, JavaScript and TypeScript.
+	SlashComments CommentSyntax = iota + 1
+	// HashComments are the # line comments and docstrings of Python.
+	HashComments
+)
+
+// commentSyntaxes maps source file extensions to their comment syntax.
+var commentSyntaxes = map[string]CommentSyntax{
+	".go":  SlashComments,
+	".js":  SlashComments,
+	".jsx": SlashComments,
+	".mjs": SlashComments,
+	".cjs": SlashComments,
+	".ts":  SlashComments,
+	".tsx": SlashComments,
+	".mts": SlashComments,
+	".cts": SlashComments,
+	".py":  HashComments,
+	".pyi": HashComments,
+}
+
+// CommentSyntaxFor returns the comment syntax of the source file at path, going
+// by its extension, and whether the extension is that of a supported language.
+func CommentSyntaxFor(path string) (CommentSyntax, bool) {
+	syntax, ok := commentSyntaxes[strings.ToLower(filepath.Ext(path))]
+	return syntax, ok
+}
+
+// comment is the text of one comment: a block comment, a docstring, or a run of
+// line comments on consecutive lines.
+type comment struct {
+	start    int      // Line of the source the comment starts on (1-indexed)
+	lines    []string // Text of each line, without comment markers
+	prefixes []string // What was removed from the start of each source line
+}
+
+// add appends a line of the comment, given the source line and the offset at
+// which its text starts.
+func (c *comment) add(line string, start int, text string) {
+	c.lines = append(c.lines, text)
+	c.prefixes = append(c.prefixes, line[:start])
+}
+
+// ExtractFromComments extracts the Mermaid code blocks written inside the
+// comments of source code, fenced as in markdown with one of languages
+// (DefaultFenceLanguages when none are given), as in a Go doc comment
+//
+//	// ```mermaid
+//	// flowchart LR
+//	//     A --> B
+//	// ```
+//
+// a JSDoc /** */ comment or a Python docstring. A fence must open and close
+// within one comment: a block comment, a docstring, or line comments on
+// consecutive lines. Comment markers, the leading * of JSDoc lines and the
+// indentation the comment's lines share are removed from each diagram line,
+// and recorded in DiagramBlock.LinePrefixes. Fences in string literals are
+// ignored, as are those in Python strings that aren't docstrings.
+func ExtractFromComments(source string, syntax CommentSyntax, languages ...string) ([]DiagramBlock, error) {
+	lines := strings.Split(inpututil.NormaliseNewlines(source), "\n")
+	var comments []comment
+	switch syntax {
+	case SlashComments:
+		comments = slashComments(lines)
+	case HashComments:
+		comments = hashComments(lines)
+	default:
+		return nil, fmt.Errorf("unknown comment syntax %d", syntax)
+	}
+
+	var blocks []DiagramBlock
+	for _, c := range comments {
+		dedent(&c)
+		found, err := ExtractFromMarkdownLanguages(strings.Join(c.lines, "\n"), languages...)
+		if err != nil {
+			return nil, fmt.Errorf("comment at line %d: %w", c.start, err)
+		}
+		for _, block := range found {
+			first := block.LineOffset - 1
+			block.LinePrefixes = c.prefixes[first:block.EndLine]
+			block.LineOffset += c.start - 1
+			block.EndLine += c.start - 1
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// slashComments returns the // and /* */ comments of lines, skipping string,
+// rune and template literals.
+func slashComments(lines []string) []comment {
+	var (
+		comments    []comment
+		current     *comment // Block comment, or run of line comments, being collected
+		inBlock     bool
+		inBacktick  bool
+		lastLineCom int
+	)
+	for i, line := range lines {
+		lineNum := i + 1
+		pos := 0
+		switch {
+		case inBlock:
+			end := strings.Index(line, "*/")
+			text := line
+			if end >= 0 {
+				text = line[:end]
+			}
+			start := 0
+			if lead := len(text) - len(strings.TrimLeft(text, " \t")); strings.HasPrefix(text[lead:], "*") {
+				start = lead + 1
+			}
+			current.add(line, start, text[start:])
+			if end < 0 {
+				continue
+			}
+			comments = append(comments, *current)
+			current, inBlock, pos = nil, false, end+2
+		case inBacktick:
+			end := strings.IndexByte(line, '`')
+			if end < 0 {
+				continue
+			}
+			inBacktick, pos = false, end+1
+		}
+
+	scan:
+		for j := pos; j < len(line); j++ {
+			switch line[j] {
+			case '"', '\'':
+				j = skipQuoted(line, j)
+			case '`':
+				end := strings.IndexByte(line[j+1:], '`')
+				if end < 0 {
+					inBacktick = true
+					break scan
+				}
+				j += end + 1
+			case '/':
+				if !strings.HasPrefix(line[j:], "//") && !strings.HasPrefix(line[j:], "/*") {
+					continue
+				}
+				if line[j+1] == '/' {
+					if current == nil || lastLineCom != lineNum-1 {
+						if current != nil {
+							comments = append(comments, *current)
+						}
+						current = &comment{start: lineNum}
+					}
+					current.add(line, j+2, line[j+2:])
+					lastLineCom = lineNum
+					break scan
+				}
+				if current != nil {
+					comments = append(comments, *current)
+				}
+				start := j + 2
+				if strings.HasPrefix(line[start:], "*") && !strings.HasPrefix(line[start:], "*/") {
+					start++
+				}
+				current = &comment{start: lineNum}
+				end := strings.Index(line[start:], "*/")
+				if end < 0 {
+					current.add(line, start, line[start:])
+					inBlock = true
+					break scan
+				}
+				current.add(line, start, line[start:start+end])
+				comments = append(comments, *current)
+				current = nil
+				j = start + end + 1
+			}
+		}
+	}
+	if current != nil {
+		comments = append(comments, *current)
+	}
+	return comments
+}
+
+// hashComments returns the # comments and docstrings of lines, skipping other
+// string literals. A docstring is taken to be a triple-quoted string that
+// starts a line, as strings used as statements do.
+func hashComments(lines []string) []comment {
+	var (
+		comments    []comment
+		current     *comment // Docstring, or run of line comments, being collected
+		quote       string   // Closing quotes of the triple-quoted string being read
+		docstring   bool
+		lastLineCom int
+	)
+	for i, line := range lines {
+		lineNum := i + 1
+		pos := 0
+		if quote != "" {
+			end := strings.Index(line, quote)
+			text := line
+			if end >= 0 {
+				text = line[:end]
+			}
+			if docstring {
+				current.add(line, 0, text)
+			}
+			if end < 0 {
+				continue
+			}
+			if docstring {
+				comments = append(comments, *current)
+				current = nil
+			}
+			quote, docstring, pos = "", false, end+3
+		}
+
+	scan:
+		for j := pos; j < len(line); j++ {
+			switch line[j] {
+			case '#':
+				if current == nil || lastLineCom != lineNum-1 {
+					if current != nil {
+						comments = append(comments, *current)
+					}
+					current = &comment{start: lineNum}
+				}
+				current.add(line, j+1, line[j+1:])
+				lastLineCom = lineNum
+				break scan
+			case '"', '\'':
+				triple := strings.Repeat(line[j:j+1], 3)
+				if !strings.HasPrefix(line[j:], triple) {
+					j = skipQuoted(line, j)
+					continue
+				}
+				before := strings.TrimSpace(line[:j])
+				isDocstring := len(before) <= 2 && strings.Trim(before, "rRuU") == ""
+				start := j + 3
+				end := strings.Index(line[start:], triple)
+				if isDocstring {
+					if current != nil {
+						comments = append(comments, *current)
+					}
+					current = &comment{start: lineNum}
+					if end >= 0 {
+						current.add(line, start, line[start:start+end])
+						comments = append(comments, *current)
+						current = nil
+					} else {
+						current.add(line, start, line[start:])
+					}
+				}
+				if end < 0 {
+					quote, docstring = triple, isDocstring
+					break scan
+				}
+				j = start + end + 2
+			}
+		}
+	}
+	if current != nil {
+		comments = append(comments, *current)
+	}
+	return comments
+}
+
+// skipQuoted returns the offset of the quote closing the single-line string
+// literal opened at line[open], or the end of the line if it isn't closed.
+func skipQuoted(line string, open int) int {
+	for j := open + 1; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case line[open]:
+			return j
+		}
+	}
+	return len(line)
+}
+
+// dedent removes the indentation shared by the comment's lines, apart from the
+// first, which follows the opening marker and has its own removed, moving it
+// to the line prefixes.
+func dedent(c *comment) {
+	indent := ""
+	found := false
+	for _, line := range c.lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found {
+			indent, found = lead, true
+			continue
+		}
+		for !strings.HasPrefix(lead, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+	for i, line := range c.lines {
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if i > 0 {
+			n = min(n, len(indent))
+		}
+		c.prefixes[i] += line[:n]
+		c.lines[i] = line[n:]
+	}
+}
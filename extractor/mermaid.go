@@ -0,0 +1,110 @@
+package extractor
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// headerKeywords are the words that start a Mermaid diagram.
+var headerKeywords = []string{
+	"flowchart", "flowchart-elk", "graph", "sequenceDiagram", "classDiagram",
+	"classDiagram-v2", "stateDiagram",
+	"stateDiagram-v2", "erDiagram", "gantt", "pie", "journey", "gitGraph",
+	"mindmap", "timeline", "sankey-beta", "quadrantChart", "xychart-beta",
+	"C4Context", "C4Container", "C4Component", "C4Dynamic", "C4Deployment",
+}
+
+// frontMatterKeyPattern matches the first line of YAML front matter.
+var frontMatterKeyPattern = regexp.MustCompile(`^[A-Za-z_][\w-]*:`)
+
+// SplitMermaid splits raw Mermaid content, such as a .mmd file, into its
+// diagrams. A diagram ends at a "---" separator line, or at a blank line
+// followed by another diagram header at column 0; an indented header, such as
+// a mindmap node named "flowchart", stays in the diagram. A "---" that opens
+// YAML front matter starts the next diagram instead. Content holding a single diagram is
+// returned as one block covering all of it, without a diagram type.
+func SplitMermaid(content string) []DiagramBlock {
+	content = inpututil.NormaliseNewlines(content)
+	lines := strings.Split(content, "\n")
+
+	var blocks []DiagramBlock
+	start, hasHeader := 0, false
+	flush := func(end int) {
+		// Drop surrounding blank lines
+		for start < end && strings.TrimSpace(lines[start]) == "" {
+			start++
+		}
+		for end > start && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		if end > start {
+			source := strings.Join(lines[start:end], "\n")
+			blocks = append(blocks, DiagramBlock{
+				Source:      source,
+				LineOffset:  start + 1,
+				EndLine:     end,
+				DiagramType: detectDiagramType(source),
+			})
+		}
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !hasHeader:
+			hasHeader = isHeaderLine(trimmed)
+		case trimmed == "---":
+			flush(i)
+			start, hasHeader = i+1, false
+			if opensFrontMatter(lines[i+1:]) {
+				start = i
+			}
+		case startsNewDiagram(line) && strings.TrimSpace(lines[i-1]) == "":
+			flush(i)
+			start = i
+		}
+	}
+
+	flush(len(lines))
+
+	if len(blocks) <= 1 {
+		return []DiagramBlock{{
+			Source:     content,
+			LineOffset: 1,
+			EndLine:    len(lines),
+		}}
+	}
+	return blocks
+}
+
+// isHeaderLine reports whether the trimmed line is a diagram header.
+func isHeaderLine(trimmed string) bool {
+	fields := strings.Fields(trimmed)
+	if len(fields) == 0 || !slices.Contains(headerKeywords, fields[0]) {
+		return false
+	}
+	// Flowchart nodes can share a name with a diagram type, as in "pie --> chart"
+	return !strings.Contains(trimmed, "--") && !strings.Contains(trimmed, "->")
+}
+
+// startsNewDiagram reports whether line is a diagram header at column 0, which
+// after a blank line starts the next diagram.
+func startsNewDiagram(line string) bool {
+	return line != "" && line[0] != ' ' && line[0] != '\t' && isHeaderLine(strings.TrimSpace(line))
+}
+
+// opensFrontMatter reports whether lines, which follow a "---" line, are YAML
+// front matter for the next diagram.
+func opensFrontMatter(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		return frontMatterKeyPattern.MatchString(trimmed)
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package extractor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// maxLineSize is the longest single line the Scanner will accept. Markdown files
+// occasionally contain very long lines (embedded data URIs, minified tables), so
+// this is well above bufio's 64KiB default.
+const maxLineSize = 1024 * 1024
+
+// Scanner reads Mermaid code blocks from markdown incrementally.
+// Only the block currently being collected is held in memory, which makes it
+// suitable for very large documents. Its API mirrors bufio.Scanner:
+//
+//	s := extractor.NewScanner(r)
+//	for s.Scan() {
+//	    block := s.Block()
+//	    // ...
+//	}
+//	if err := s.Err(); err != nil {
+//	    // handle error
+//	}
+type Scanner struct {
+	lines *bufio.Scanner
+	block DiagramBlock
+	err   error
+	done  bool
+
+	lineNum        int
+	inMermaidBlock bool
+	currentBlock   strings.Builder
+	blockStartLine int
+	blockAttrs     map[string]string
+	languages      []string
+}
+
+// NewScanner returns a new Scanner reading markdown from r. It recognises code
+// fences labelled with DefaultFenceLanguages; use SetLanguages to change them.
+func NewScanner(r io.Reader) *Scanner {
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &Scanner{lines: lines, languages: DefaultFenceLanguages()}
+}
+
+// SetLanguages sets the code fence languages whose blocks the Scanner returns,
+// such as "mermaid" or "mmd"; with none it goes back to DefaultFenceLanguages.
+// It must be called before Scan.
+func (s *Scanner) SetLanguages(languages ...string) {
+	if len(languages) == 0 {
+		languages = DefaultFenceLanguages()
+	}
+	s.languages = languages
+}
+
+// Scan advances to the next Mermaid block, which is then available through Block.
+// It returns false when the input is exhausted or an error occurs.
+func (s *Scanner) Scan() bool {
+	if s.done {
+		return false
+	}
+
+	for s.lines.Scan() {
+		s.lineNum++
+		line := s.lines.Text()
+		if s.lineNum == 1 {
+			line = inpututil.TrimByteOrderMark(line)
+		}
+		if s.processLine(line) {
+			return true
+		}
+		if s.err != nil {
+			s.done = true
+			return false
+		}
+	}
+
+	s.done = true
+	if err := s.lines.Err(); err != nil {
+		s.err = err
+		return false
+	}
+
+	// Handle unclosed block at end of file
+	if s.inMermaidBlock {
+		s.inMermaidBlock = false
+		return s.emit(s.lineNum) // Content ends at last line
+	}
+
+	return false
+}
+
+// Block returns the most recent block found by Scan.
+func (s *Scanner) Block() DiagramBlock {
+	return s.block
+}
+
+// Err returns the first error encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// processLine handles a single markdown line, returning true when it completes a block.
+func (s *Scanner) processLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+
+	// Check for accidentally escaped backticks that look like code fence attempts
+	// Only flag if the escaped backticks appear at the start of the line (after whitespace)
+	// and aren't embedded within other text (like inline code or examples)
+	if (strings.HasPrefix(trimmed, "\\`\\`\\`mermaid") || strings.HasPrefix(trimmed, "\\`\\`\\`")) &&
+		!strings.Contains(line, "`\\`\\`\\`") { // Ignore if it's in inline code like `\`\`\``
+		s.err = fmt.Errorf("line %d: escaped backticks found (\\`\\`\\`). Remove backslashes to use proper markdown code fences: ```", s.lineNum)
+		return false
+	}
+
+	// Check for start of Mermaid code block
+	if !s.inMermaidBlock {
+		if info, ok := s.openingFence(trimmed); ok {
+			s.inMermaidBlock = true
+			s.blockStartLine = s.lineNum + 1 // Content starts on next line
+			s.blockAttrs = ParseFenceAttributes(info)
+			s.currentBlock.Reset()
+			return false
+		}
+	}
+
+	// Check for end of code block
+	if s.inMermaidBlock && trimmed == "```" {
+		s.inMermaidBlock = false
+		return s.emit(s.lineNum - 1) // Content ends on previous line before closing fence
+	}
+
+	// Collect lines within Mermaid block
+	if s.inMermaidBlock {
+		if s.currentBlock.Len() > 0 {
+			s.currentBlock.WriteByte('\n')
+		}
+		s.currentBlock.WriteString(line)
+	}
+
+	return false
+}
+
+// openingFence reports whether the trimmed line opens a code fence labelled
+// with one of the Scanner's languages, returning the rest of its info string.
+func (s *Scanner) openingFence(trimmed string) (string, bool) {
+	rest, ok := strings.CutPrefix(trimmed, "```")
+	if !ok {
+		return "", false
+	}
+	language, info := rest, ""
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		language, info = rest[:i], rest[i:]
+	}
+	return info, language != "" && slices.Contains(s.languages, language)
+}
+
+// emit publishes the collected block, skipping blocks with no content.
+func (s *Scanner) emit(endLine int) bool {
+	source := s.currentBlock.String()
+	s.currentBlock.Reset()
+	if strings.TrimSpace(source) == "" {
+		return false
+	}
+
+	s.block = DiagramBlock{
+		Source:      source,
+		LineOffset:  s.blockStartLine,
+		EndLine:     endLine,
+		DiagramType: detectDiagramType(source),
+		Attributes:  s.blockAttrs,
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package extractor_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/extractor"
+)
+
+func TestParseFenceAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		want map[string]string
+	}{
+		{
+			name: "empty",
+			info: "",
+		},
+		{
+			name: "empty braces",
+			info: " {} ",
+		},
+		{
+			name: "quoted and bare values",
+			info: `{caption="Request flow" id=fig-1}`,
+			want: map[string]string{"caption": "Request flow", "id": "fig-1"},
+		},
+		{
+			name: "without braces",
+			info: `caption='Login' width=80`,
+			want: map[string]string{"caption": "Login", "width": "80"},
+		},
+		{
+			name: "escaped quote",
+			info: `{caption="The \"main\" flow"}`,
+			want: map[string]string{"caption": `The "main" flow`},
+		},
+		{
+			name: "pandoc id and classes",
+			info: `{#fig-2 .wide .centred caption="Deploy"}`,
+			want: map[string]string{"id": "fig-2", "class": "wide centred", "caption": "Deploy"},
+		},
+		{
+			name: "bare word",
+			info: "showLineNumbers",
+			want: map[string]string{"showLineNumbers": ""},
+		},
+		{
+			name: "comma separated",
+			info: `{id=a, caption="b, c"}`,
+			want: map[string]string{"id": "a", "caption": "b, c"},
+		},
+		{
+			name: "unterminated quote",
+			info: `{caption="Open}`,
+			want: map[string]string{"caption": "Open"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractor.ParseFenceAttributes(tt.info); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFenceAttributes(%q) = %v, want %v", tt.info, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractFromMarkdown_FenceAttributes(t *testing.T) {
+	markdown := "```mermaid {caption=\"Login flow\" id=fig-1}\nflowchart TD\n    A --> B\n```\n\n```mermaid\npie\n    \"A\" : 1\n```"
+
+	blocks, err := extractor.ExtractFromMarkdown(markdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+	if want := map[string]string{"caption": "Login flow", "id": "fig-1"}; !reflect.DeepEqual(blocks[0].Attributes, want) {
+		t.Errorf("first block attributes = %v, want %v", blocks[0].Attributes, want)
+	}
+	if blocks[1].Attributes != nil {
+		t.Errorf("second block attributes = %v, want nil", blocks[1].Attributes)
+	}
+}
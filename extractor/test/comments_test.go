@@ -0,0 +1,133 @@
+package extractor_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/extractor"
+)
+
+func TestCommentSyntaxFor(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   extractor.CommentSyntax
+		wantOK bool
+	}{
+		{"server.go", extractor.SlashComments, true},
+		{"src/app.TS", extractor.SlashComments, true},
+		{"component.jsx", extractor.SlashComments, true},
+		{"module.mjs", extractor.SlashComments, true},
+		{"pkg/models.py", extractor.HashComments, true},
+		{"README.md", 0, false},
+		{"diagram.mmd", 0, false},
+		{"Makefile", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := extractor.CommentSyntaxFor(tt.path)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("CommentSyntaxFor(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestExtractFromComments(t *testing.T) {
+	fence := "```"
+	tests := []struct {
+		name     string
+		syntax   extractor.CommentSyntax
+		source   string
+		want     []string   // Source of each block
+		offsets  []int      // LineOffset of each block
+		prefixes [][]string // LinePrefixes of each block
+	}{
+		{
+			name:     "go line comments",
+			syntax:   extractor.SlashComments,
+			source:   "package server\n\n// Server handles requests:\n//\n// " + fence + "mermaid\n// flowchart LR\n//     A --> B\n// " + fence + "\ntype Server struct{}\n",
+			want:     []string{"flowchart LR\n    A --> B"},
+			offsets:  []int{6},
+			prefixes: [][]string{{"// ", "// "}},
+		},
+		{
+			name:     "jsdoc block comment",
+			syntax:   extractor.SlashComments,
+			source:   "/**\n * Checkout flow.\n *\n * " + fence + "mermaid\n * sequenceDiagram\n *     Client->>API: POST /orders\n * " + fence + "\n */\nexport function checkout() {}\n",
+			want:     []string{"sequenceDiagram\n    Client->>API: POST /orders"},
+			offsets:  []int{5},
+			prefixes: [][]string{{" * ", " * "}},
+		},
+		{
+			name:     "indented block comment",
+			syntax:   extractor.SlashComments,
+			source:   "func f() {\n\t/*\n\t\t" + fence + "mermaid\n\t\tpie\n\t\t    \"A\" : 1\n\t\t" + fence + "\n\t*/\n}\n",
+			want:     []string{"pie\n    \"A\" : 1"},
+			offsets:  []int{4},
+			prefixes: [][]string{{"\t\t", "\t\t"}},
+		},
+		{
+			name:   "fences in strings are ignored",
+			syntax: extractor.SlashComments,
+			source: "const a = \"// " + fence + "mermaid\";\nconst b = `\n" + fence + "mermaid\ngraph TD\n" + fence + "\n`; // trailing\n",
+		},
+		{
+			name:     "separate line comments don't join",
+			syntax:   extractor.SlashComments,
+			source:   "// " + fence + "mermaid\n// graph TD\nx := 1\n// " + fence + "\n",
+			want:     []string{"graph TD"},
+			offsets:  []int{2},
+			prefixes: [][]string{{"// "}},
+		},
+		{
+			name:     "python docstring",
+			syntax:   extractor.HashComments,
+			source:   "def checkout():\n    \"\"\"Place an order.\n\n    " + fence + "mermaid\n    flowchart TD\n        A --> B\n    " + fence + "\n    \"\"\"\n",
+			want:     []string{"flowchart TD\n    A --> B"},
+			offsets:  []int{5},
+			prefixes: [][]string{{"    ", "    "}},
+		},
+		{
+			name:     "python hash comments",
+			syntax:   extractor.HashComments,
+			source:   "# " + fence + "mermaid\n# pie\n#     \"A\" : 1\n# " + fence + "\nx = 1\n",
+			want:     []string{"pie\n    \"A\" : 1"},
+			offsets:  []int{2},
+			prefixes: [][]string{{"# ", "# "}},
+		},
+		{
+			name:   "python strings that aren't docstrings are ignored",
+			syntax: extractor.HashComments,
+			source: "source = \"\"\"\n" + fence + "mermaid\ngraph TD\n" + fence + "\n\"\"\"\nlabel = \"# " + fence + "mermaid\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks, err := extractor.ExtractFromComments(tt.source, tt.syntax)
+			if err != nil {
+				t.Fatalf("ExtractFromComments() error = %v", err)
+			}
+			var got []string
+			var offsets []int
+			var prefixes [][]string
+			for _, block := range blocks {
+				got = append(got, block.Source)
+				offsets = append(offsets, block.LineOffset)
+				prefixes = append(prefixes, block.LinePrefixes)
+				if lines := strings.Count(block.Source, "\n") + 1; block.EndLine-block.LineOffset+1 != lines {
+					t.Errorf("block at line %d ends at %d, want %d lines", block.LineOffset, block.EndLine, lines)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sources = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(offsets, tt.offsets) {
+				t.Errorf("line offsets = %v, want %v", offsets, tt.offsets)
+			}
+			if !reflect.DeepEqual(prefixes, tt.prefixes) {
+				t.Errorf("line prefixes = %q, want %q", prefixes, tt.prefixes)
+			}
+		})
+	}
+}
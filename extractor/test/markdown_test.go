@@ -594,3 +594,50 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestExtractFromMarkdown_WindowsLineEndingsAndBOM(t *testing.T) {
+	markdown := "\uFEFF```mermaid\r\nflowchart TD\r\n    A --> B\r\n```\r\nText\r\n```mermaid\r\nsequenceDiagram\r\n    A->>B: hi\r\n```\r\n"
+
+	blocks, err := extractor.ExtractFromMarkdown(markdown)
+	if err != nil {
+		t.Fatalf("ExtractFromMarkdown() error = %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+
+	if blocks[0].Source != "flowchart TD\n    A --> B" {
+		t.Errorf("block 0 source = %q", blocks[0].Source)
+	}
+	if blocks[0].DiagramType != "flowchart" || blocks[0].LineOffset != 2 {
+		t.Errorf("block 0 = %+v, want flowchart at line 2", blocks[0])
+	}
+	if blocks[1].DiagramType != "sequence" || blocks[1].LineOffset != 7 {
+		t.Errorf("block 1 = %+v, want sequence at line 7", blocks[1])
+	}
+}
+
+func TestExtractFromMarkdown_FenceAliases(t *testing.T) {
+	markdown := "```mmd\nflowchart TD\n    A --> B\n```\n\n```mermaidjs\npie\n    \"A\" : 1\n```\n\n```{mermaid}\nsequenceDiagram\n    A->>B: hi\n```\n\n```go\nfmt.Println()\n```"
+
+	blocks, err := extractor.ExtractFromMarkdown(markdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var types []string
+	for _, block := range blocks {
+		types = append(types, block.DiagramType)
+	}
+	if want := []string{"flowchart", "pie", "sequence"}; fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Errorf("got diagram types %v, want %v", types, want)
+	}
+
+	// A custom list replaces the defaults
+	blocks, err = extractor.ExtractFromMarkdownLanguages(markdown, "mermaid", "mmd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].DiagramType != "flowchart" {
+		t.Errorf("expected only the mmd block, got %+v", blocks)
+	}
+}
@@ -0,0 +1,87 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/extractor"
+)
+
+func TestSplitMermaid(t *testing.T) {
+	type block struct {
+		source      string
+		lineOffset  int
+		endLine     int
+		diagramType string
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []block
+	}{
+		{
+			name:    "single diagram",
+			content: "flowchart TD\n    A --> B\n\n    B --> C\n",
+			want:    []block{{"flowchart TD\n    A --> B\n\n    B --> C\n", 1, 5, ""}},
+		},
+		{
+			name:    "blank line and new header",
+			content: "flowchart TD\n    A --> B\n\nsequenceDiagram\n    A->>B: hi\n",
+			want: []block{
+				{"flowchart TD\n    A --> B", 1, 2, "flowchart"},
+				{"sequenceDiagram\n    A->>B: hi", 4, 5, "sequence"},
+			},
+		},
+		{
+			name:    "indented mindmap node named after a diagram type",
+			content: "mindmap\n  root((Plans))\n    sequenceDiagram\n\n    flowchart ideas\n      boxes\n",
+			want:    []block{{"mindmap\n  root((Plans))\n    sequenceDiagram\n\n    flowchart ideas\n      boxes\n", 1, 7, ""}},
+		},
+		{
+			name:    "separator lines",
+			content: "%% first\npie\n    \"a\" : 1\n---\ngantt\n    title Plan\n---\n\nclassDiagram\n    class A",
+			want: []block{
+				{"%% first\npie\n    \"a\" : 1", 1, 3, "pie"},
+				{"gantt\n    title Plan", 5, 6, "gantt"},
+				{"classDiagram\n    class A", 9, 10, "class"},
+			},
+		},
+		{
+			name:    "front matter for the next diagram",
+			content: "---\ntitle: One\n---\nflowchart TD\n    A --> B\n---\ntitle: Two\n---\nflowchart LR\n    C --> D",
+			want: []block{
+				{"---\ntitle: One\n---\nflowchart TD\n    A --> B", 1, 5, "flowchart"},
+				{"---\ntitle: Two\n---\nflowchart LR\n    C --> D", 6, 10, "flowchart"},
+			},
+		},
+		{
+			name:    "node named like a diagram type",
+			content: "flowchart TD\n    A --> B\n\npie --> chart\n",
+			want:    []block{{"flowchart TD\n    A --> B\n\npie --> chart\n", 1, 5, ""}},
+		},
+		{
+			name:    "header without a blank line before it",
+			content: "flowchart TD\n    A --> B\ngraph LR\n",
+			want:    []block{{"flowchart TD\n    A --> B\ngraph LR\n", 1, 4, ""}},
+		},
+		{
+			name:    "trailing separator",
+			content: "flowchart TD\n    A --> B\n---\n",
+			want:    []block{{"flowchart TD\n    A --> B\n---\n", 1, 4, ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := extractor.SplitMermaid(tt.content)
+			if len(blocks) != len(tt.want) {
+				t.Fatalf("got %d blocks, want %d: %+v", len(blocks), len(tt.want), blocks)
+			}
+			for i, want := range tt.want {
+				got := block{blocks[i].Source, blocks[i].LineOffset, blocks[i].EndLine, blocks[i].DiagramType}
+				if got != want {
+					t.Errorf("block %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
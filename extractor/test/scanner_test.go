@@ -0,0 +1,83 @@
+package extractor_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/extractor"
+)
+
+func TestScanner_MatchesExtractFromMarkdown(t *testing.T) {
+	markdown := "# Title\n\n```mermaid\nflowchart TD\n    A --> B\n```\n\ntext\n\n```mermaid\nsequenceDiagram\n    Alice->>Bob: Hi\n```\n\n```mermaid\npie\n    \"A\" : 1"
+
+	want, err := extractor.ExtractFromMarkdown(markdown)
+	if err != nil {
+		t.Fatalf("ExtractFromMarkdown() error = %v", err)
+	}
+
+	var got []extractor.DiagramBlock
+	s := extractor.NewScanner(strings.NewReader(markdown))
+	for s.Scan() {
+		got = append(got, s.Block())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scanner.Err() = %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d blocks, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("block %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	last := got[len(got)-1]
+	if last.DiagramType != "pie" || last.EndLine != 17 {
+		t.Errorf("unclosed trailing block: got type %q end line %d", last.DiagramType, last.EndLine)
+	}
+}
+
+func TestScanner_EscapedBackticks(t *testing.T) {
+	s := extractor.NewScanner(strings.NewReader("intro\n\\`\\`\\`mermaid\nflowchart TD\n"))
+	if s.Scan() {
+		t.Fatal("expected Scan() to return false")
+	}
+	if s.Err() == nil || !strings.Contains(s.Err().Error(), "line 2") {
+		t.Errorf("expected escaped backtick error on line 2, got %v", s.Err())
+	}
+	if s.Scan() {
+		t.Error("expected Scan() to keep returning false after an error")
+	}
+}
+
+func TestScanner_SkipsEmptyBlocks(t *testing.T) {
+	s := extractor.NewScanner(strings.NewReader("```mermaid\n\n```\n```mermaid\ngraph LR\n    A --> B\n```\n"))
+	count := 0
+	for s.Scan() {
+		count++
+		if s.Block().LineOffset != 5 {
+			t.Errorf("expected line offset 5, got %d", s.Block().LineOffset)
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected 1 block, got %d", count)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read failed") }
+
+func TestScanner_ReaderError(t *testing.T) {
+	s := extractor.NewScanner(failingReader{})
+	if s.Scan() {
+		t.Fatal("expected Scan() to return false")
+	}
+	if s.Err() == nil {
+		t.Error("expected reader error to be reported")
+	}
+}
@@ -0,0 +1,42 @@
+package mermaid
+
+import (
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// maxFixPasses bounds the fix and revalidate passes made by FixSource, in case
+// fixes keep producing new errors.
+const maxFixPasses = 10
+
+// FixResult is the outcome of FixSource.
+type FixResult struct {
+	Source    string                      // Corrected diagram source
+	Applied   int                         // Number of fixes applied
+	Remaining []validator.ValidationError // Errors left after fixing
+}
+
+// FixSource applies the automatic fixes offered by the rules selected by opts
+// (see validator.Fix) to a raw Mermaid diagram, validating again after each
+// pass until no more fixes apply. An error is returned if the diagram cannot
+// be parsed or opts names an unknown renderer.
+func FixSource(source string, opts ValidateOptions) (FixResult, error) {
+	result := FixResult{Source: source}
+	if err := opts.check(); err != nil {
+		return result, err
+	}
+	for range maxFixPasses {
+		diagram, err := Parse(result.Source)
+		if err != nil {
+			return result, err
+		}
+		result.Remaining = validateWithOptions(diagram, opts)
+
+		fixed, applied := validator.ApplyFixes(result.Source, result.Remaining)
+		if applied == 0 {
+			break
+		}
+		result.Source = fixed
+		result.Applied += applied
+	}
+	return result, nil
+}
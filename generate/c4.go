@@ -0,0 +1,106 @@
+package generate
+
+import (
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// FormatC4 renders a C4 diagram as Mermaid source: the title, then elements,
+// boundaries with their contents indented four spaces per level, and
+// relationships.
+func FormatC4(diagram *ast.C4Diagram) string {
+	var b strings.Builder
+	b.WriteString(c4Header(diagram.DiagramType) + "\n")
+	if diagram.Title != "" {
+		b.WriteString("    title " + diagram.Title + "\n")
+	}
+	for _, element := range diagram.Elements {
+		b.WriteString("    " + formatC4Element(element) + "\n")
+	}
+	for _, boundary := range diagram.Boundaries {
+		writeC4Boundary(&b, boundary, 1)
+	}
+	for _, rel := range diagram.Relationships {
+		args := []string{rel.From, rel.To, c4Quote(rel.Label)}
+		if rel.RelType == "RelIndex" {
+			args = append([]string{rel.Index}, args...)
+		}
+		args = appendOptional(args, rel.Technology, rel.Description)
+		relType := rel.RelType
+		if relType == "" {
+			relType = "Rel"
+		}
+		b.WriteString("    " + relType + "(" + strings.Join(args, ", ") + ")\n")
+	}
+	return b.String()
+}
+
+// c4Header returns the header for a C4 diagram type such as "c4Container".
+func c4Header(diagramType string) string {
+	if strings.HasPrefix(diagramType, "c4") {
+		return "C4" + diagramType[2:]
+	}
+	return diagramType
+}
+
+// writeC4Boundary writes a boundary and its contents at the given depth.
+func writeC4Boundary(b *strings.Builder, boundary ast.C4Boundary, depth int) {
+	indent := strings.Repeat("    ", depth)
+	args := []string{boundary.ID, c4Quote(boundary.Label)}
+	if boundary.Type != "" {
+		args = append(args, c4Quote(boundary.Type))
+	}
+	b.WriteString(indent + boundary.BoundaryType + "(" + strings.Join(args, ", ") + ") {\n")
+	for _, element := range boundary.Elements {
+		b.WriteString(indent + "    " + formatC4Element(element) + "\n")
+	}
+	for _, nested := range boundary.Boundaries {
+		writeC4Boundary(b, nested, depth+1)
+	}
+	b.WriteString(indent + "}\n")
+}
+
+// formatC4Element renders an element macro call, such as
+// ContainerDb(db, "Database", "PostgreSQL").
+func formatC4Element(element ast.C4Element) string {
+	macro := element.ElementType
+	switch {
+	case element.Database:
+		macro += "Db"
+	case element.Queue:
+		macro += "Queue"
+	}
+	if element.External {
+		macro += "_Ext"
+	}
+	args := []string{element.ID, c4Quote(element.Label)}
+	switch element.ElementType {
+	case "Person", "System":
+		args = appendOptional(args, element.Description)
+	default:
+		args = appendOptional(args, element.Technology, element.Description)
+	}
+	return macro + "(" + strings.Join(args, ", ") + ")"
+}
+
+// appendOptional appends the quoted optional arguments up to the last one
+// given, leaving earlier missing ones empty.
+func appendOptional(args []string, optional ...string) []string {
+	last := -1
+	for i, value := range optional {
+		if value != "" {
+			last = i
+		}
+	}
+	for _, value := range optional[:last+1] {
+		args = append(args, c4Quote(value))
+	}
+	return args
+}
+
+// c4Quote quotes a macro argument, replacing double quotes, which can't be
+// escaped, with single ones.
+func c4Quote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
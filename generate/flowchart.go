@@ -0,0 +1,108 @@
+package generate
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// FormatFlowchart renders a flowchart as Mermaid source, indenting each
+// statement four spaces per level of subgraph nesting.
+func FormatFlowchart(diagram *ast.Flowchart) string {
+	var b strings.Builder
+	header := diagram.Type
+	if header == "" {
+		header = "flowchart"
+	}
+	b.WriteString(header)
+	if diagram.Direction != "" {
+		b.WriteString(" " + diagram.Direction)
+	}
+	b.WriteString("\n")
+	writeFlowchartStatements(&b, diagram.Statements, 1)
+	return b.String()
+}
+
+// writeFlowchartStatements writes statements at the given nesting depth.
+func writeFlowchartStatements(b *strings.Builder, statements []ast.Statement, depth int) {
+	indent := strings.Repeat("    ", depth)
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			b.WriteString(indent + formatNode(*s) + "\n")
+		case *ast.Link:
+			b.WriteString(indent + s.From + " " + s.Arrow)
+			if s.Label != "" {
+				b.WriteString("|" + s.Label + "|")
+			}
+			b.WriteString(" " + s.To + "\n")
+		case *ast.Subgraph:
+			switch {
+			case s.ID == "":
+				b.WriteString(indent + `subgraph "` + s.Title + `"` + "\n")
+			case s.Title != "" && s.Title != s.ID:
+				b.WriteString(indent + "subgraph " + s.ID + " [" + s.Title + "]\n")
+			default:
+				b.WriteString(indent + "subgraph " + s.ID + "\n")
+			}
+			if s.Direction != "" {
+				b.WriteString(indent + "    direction " + s.Direction + "\n")
+			}
+			writeFlowchartStatements(b, s.Statements, depth+1)
+			b.WriteString(indent + "end\n")
+		case *ast.ClassDef:
+			b.WriteString(indent + "classDef " + s.Name + " " + formatStyles(s.Styles) + "\n")
+		case *ast.Style:
+			b.WriteString(indent + "style " + s.NodeID + " " + formatStyles(s.Styles) + "\n")
+		case *ast.ClassAssignment:
+			b.WriteString(indent + "class " + strings.Join(s.NodeIDs, ",") + " " + s.ClassName + "\n")
+		case *ast.Comment:
+			b.WriteString(indent + "%%" + s.Text + "\n")
+		}
+	}
+}
+
+// formatNode renders a node definition, splitting its shape (such as "[]" or
+// "([])") into the brackets either side of the label.
+func formatNode(node ast.NodeDef) string {
+	if node.Shape == "" {
+		return node.ID
+	}
+	half := len(node.Shape) / 2
+	return node.ID + node.Shape[:half] + node.Label + node.Shape[half:]
+}
+
+// formatStyles renders CSS properties as Mermaid writes them, sorted by name.
+func formatStyles(styles map[string]string) string {
+	properties := make([]string, 0, len(styles))
+	for _, name := range slices.Sorted(maps.Keys(styles)) {
+		properties = append(properties, name+":"+styles[name])
+	}
+	return strings.Join(properties, ",")
+}
+
+// labelText makes text from another source safe for a flowchart or C4 label,
+// dropping the characters that end a label or a quoted argument.
+func labelText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '"', '|', '[', ']', '{', '}', '(', ')', '<', '>':
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// identifier converts a name from another source, such as my-app.v2, into an
+// identifier Mermaid accepts by replacing other characters with underscores.
+func identifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
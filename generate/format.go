@@ -0,0 +1,67 @@
+// Package generate creates Mermaid diagrams from other sources, such as
+// database schemas, API descriptions, Kubernetes manifests and Terraform
+// graphs, so that documentation can start from what already exists.
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// plainLabelPattern matches relationship labels that need no quotes.
+var plainLabelPattern = regexp.MustCompile(`^[\pL\pN_-]+$`)
+
+// FormatER renders an ER diagram as Mermaid source: relationships first, then
+// entities with their attributes, indented by four spaces.
+func FormatER(diagram *ast.ERDiagram) string {
+	var b strings.Builder
+	b.WriteString("erDiagram")
+	if diagram.Direction != "" {
+		b.WriteString(" " + diagram.Direction)
+	}
+	b.WriteString("\n")
+
+	for _, rel := range diagram.Relationships {
+		fmt.Fprintf(&b, "    %s %s%s%s %s", rel.From, rel.FromCard, rel.Type, rel.ToCard, rel.To)
+		if rel.Label != "" {
+			b.WriteString(" : " + formatLabel(rel.Label))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, entity := range diagram.Entities {
+		b.WriteString("    " + entity.Name)
+		if entity.Alias != "" {
+			b.WriteString("[" + entity.Alias + "]")
+		}
+		if len(entity.Attributes) == 0 {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(" {\n")
+		for _, attr := range entity.Attributes {
+			fmt.Fprintf(&b, "        %s %s", attr.Type, attr.Name)
+			if len(attr.Keys) > 0 {
+				b.WriteString(" " + strings.Join(attr.Keys, ", "))
+			}
+			if attr.Comment != "" {
+				b.WriteString(` "` + strings.ReplaceAll(attr.Comment, `"`, "'") + `"`)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	return b.String()
+}
+
+// formatLabel quotes a relationship label unless it is a single word or
+// already quoted.
+func formatLabel(label string) string {
+	if plainLabelPattern.MatchString(label) || (len(label) >= 2 && strings.HasPrefix(label, `"`) && strings.HasSuffix(label, `"`)) {
+		return label
+	}
+	return `"` + strings.ReplaceAll(label, `"`, "'") + `"`
+}
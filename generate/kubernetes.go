@@ -0,0 +1,429 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// k8sWorkloadKinds are the kinds of Kubernetes object whose pods Services
+// select, with the prefix of their diagram IDs.
+var k8sWorkloadKinds = map[string]string{
+	"Deployment":  "deploy",
+	"StatefulSet": "sts",
+	"DaemonSet":   "ds",
+}
+
+// k8sObject is the part of a Kubernetes object the generators read.
+type k8sObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec struct {
+		// Deployments, StatefulSets and DaemonSets
+		Template struct {
+			Metadata struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Containers []struct {
+					Image string `yaml:"image"`
+				} `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+
+		// Services
+		Type     string    `yaml:"type"`
+		Selector yaml.Node `yaml:"selector"`
+		Ports    []struct {
+			Port k8sIntOrString `yaml:"port"`
+		} `yaml:"ports"`
+
+		// Ingresses
+		TLS   []yaml.Node `yaml:"tls"`
+		Rules []struct {
+			Host string `yaml:"host"`
+			HTTP struct {
+				Paths []struct {
+					Path    string     `yaml:"path"`
+					Backend k8sBackend `yaml:"backend"`
+				} `yaml:"paths"`
+			} `yaml:"http"`
+		} `yaml:"rules"`
+		DefaultBackend *k8sBackend `yaml:"defaultBackend"`
+		Backend        *k8sBackend `yaml:"backend"` // extensions/v1beta1
+	} `yaml:"spec"`
+	Items []yaml.Node `yaml:"items"` // List
+}
+
+// k8sBackend is an Ingress backend, in the networking.k8s.io/v1 form or the
+// older serviceName form.
+type k8sBackend struct {
+	Service struct {
+		Name string `yaml:"name"`
+	} `yaml:"service"`
+	ServiceName string `yaml:"serviceName"`
+}
+
+// name returns the name of the Service b routes to.
+func (b k8sBackend) name() string {
+	if b.Service.Name != "" {
+		return b.Service.Name
+	}
+	return b.ServiceName
+}
+
+// k8sIntOrString is a Kubernetes field that holds a number or a name.
+type k8sIntOrString string
+
+// UnmarshalYAML accepts any scalar.
+func (v *k8sIntOrString) UnmarshalYAML(node *yaml.Node) error {
+	*v = k8sIntOrString(node.Value)
+	return nil
+}
+
+// k8sResource is a Deployment, StatefulSet, DaemonSet, Service or Ingress to
+// draw.
+type k8sResource struct {
+	kind, name, namespace string
+	id                    string
+	labels                map[string]string // Pod labels, for workloads
+	images                []string          // Container images, for workloads
+	selector              map[string]string // For Services
+	serviceType           string            // For Services
+	ports                 []string          // For Services
+	hosts                 []string          // For Ingresses
+	tls                   bool              // For Ingresses
+	routes                []k8sRoute        // For Ingresses
+	defined               bool              // False for Services only referenced by Ingresses
+}
+
+// k8sLink is a connection between two resources.
+type k8sLink struct {
+	from, to *k8sResource
+	labels   []string
+}
+
+// k8sModel is the resources read from Kubernetes manifests and their links:
+// from Ingresses to the Services they route to, and from Services to the
+// workloads whose pods they select.
+type k8sModel struct {
+	resources []*k8sResource
+	links     []*k8sLink
+}
+
+// KubernetesFlowchart generates a left-to-right flowchart of the Deployments,
+// StatefulSets, DaemonSets, Services and Ingresses in Kubernetes manifests:
+// one or more YAML documents, which may be Lists. Resources are grouped in a
+// subgraph per namespace. Each Ingress links to the Services it routes to,
+// labelled with the hosts and paths, and each Service links to the workloads
+// whose pod labels match its selector, labelled with its ports. Services an
+// Ingress routes to that the manifests don't define are drawn too.
+//
+// KubernetesFlowchart returns an error if the manifests can't be read or have
+// none of those kinds of object.
+func KubernetesFlowchart(manifests []byte) (*ast.Flowchart, error) {
+	model, err := readKubernetes(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	diagram := &ast.Flowchart{Type: "flowchart", Direction: "LR"}
+	for _, namespace := range model.namespaces() {
+		subgraph := &ast.Subgraph{ID: "ns_" + identifier(namespace), Title: namespace}
+		for _, resource := range model.resources {
+			if resource.namespace != namespace {
+				continue
+			}
+			shape := "[]"
+			switch resource.kind {
+			case "Ingress":
+				shape = "{{}}"
+			case "Service":
+				shape = "([])"
+			case "StatefulSet":
+				shape = "[()]"
+			}
+			subgraph.Statements = append(subgraph.Statements, &ast.NodeDef{
+				ID:    resource.id,
+				Shape: shape,
+				Label: labelText(resource.kind + " " + resource.name),
+			})
+		}
+		diagram.Statements = append(diagram.Statements, subgraph)
+	}
+	for _, link := range model.links {
+		diagram.Statements = append(diagram.Statements, &ast.Link{
+			From:   link.from.id,
+			To:     link.to.id,
+			Arrow:  "-->",
+			Label:  labelText(strings.Join(link.labels, ", ")),
+			Length: 1,
+		})
+	}
+	return diagram, nil
+}
+
+// KubernetesC4 generates a C4 container diagram of the same resources as
+// KubernetesFlowchart, with a boundary per namespace. Workloads show their
+// kind and container images, Services their type and ports, and Ingresses
+// their hosts; Services the manifests don't define are external. When there
+// are Ingresses, a user reaches them over HTTP, or HTTPS where TLS is set up.
+//
+// KubernetesC4 returns an error if the manifests can't be read or have none
+// of those kinds of object.
+func KubernetesC4(manifests []byte) (*ast.C4Diagram, error) {
+	model, err := readKubernetes(manifests)
+	if err != nil {
+		return nil, err
+	}
+
+	diagram := &ast.C4Diagram{DiagramType: "c4Container", Title: "Kubernetes resources"}
+	for _, namespace := range model.namespaces() {
+		boundary := ast.C4Boundary{BoundaryType: "System_Boundary", ID: "ns_" + identifier(namespace), Label: namespace}
+		for _, resource := range model.resources {
+			if resource.namespace != namespace {
+				continue
+			}
+			element := ast.C4Element{ElementType: "Container", ID: resource.id, Label: labelText(resource.name), Technology: resource.kind}
+			switch resource.kind {
+			case "Ingress":
+				element.Description = strings.Join(resource.hosts, " ")
+			case "Service":
+				element.External = !resource.defined
+				element.Technology = strings.TrimSpace(resource.serviceType + " Service")
+				if len(resource.ports) > 0 {
+					element.Description = portsText(resource.ports)
+				}
+			default:
+				element.Description = strings.Join(resource.images, " ")
+			}
+			element.Description = labelText(element.Description)
+			boundary.Elements = append(boundary.Elements, element)
+		}
+		diagram.Boundaries = append(diagram.Boundaries, boundary)
+	}
+
+	for _, resource := range model.resources {
+		if resource.kind != "Ingress" {
+			continue
+		}
+		if len(diagram.Elements) == 0 {
+			diagram.Elements = append(diagram.Elements, ast.C4Element{ElementType: "Person", ID: "user", Label: "User"})
+		}
+		protocol := "HTTP"
+		if resource.tls {
+			protocol = "HTTPS"
+		}
+		diagram.Relationships = append(diagram.Relationships, ast.C4Relationship{RelType: "Rel", From: "user", To: resource.id, Label: "Uses", Technology: protocol})
+	}
+	for _, link := range model.links {
+		rel := ast.C4Relationship{RelType: "Rel", From: link.from.id, To: link.to.id}
+		if link.from.kind == "Ingress" {
+			rel.Label = labelText("Routes " + strings.Join(link.labels, ", "))
+		} else {
+			rel.Label = "Forwards to"
+			rel.Technology = portsText(link.from.ports)
+		}
+		diagram.Relationships = append(diagram.Relationships, rel)
+	}
+	return diagram, nil
+}
+
+// readKubernetes reads the resources in manifests and links them.
+func readKubernetes(manifests []byte) (*k8sModel, error) {
+	model := &k8sModel{}
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for document := 1; ; document++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", document, err)
+		}
+		if err := model.add(&node); err != nil {
+			return nil, fmt.Errorf("document %d: %w", document, err)
+		}
+	}
+	if len(model.resources) == 0 {
+		return nil, errors.New("no Deployments, StatefulSets, DaemonSets, Services or Ingresses found")
+	}
+
+	for _, ingress := range model.resources {
+		for _, route := range ingress.routes {
+			service := model.find("Service", route.service, ingress.namespace)
+			if service == nil {
+				service = model.addResource(&k8sResource{kind: "Service", name: route.service, namespace: ingress.namespace})
+			}
+			model.link(ingress, service, route.label)
+		}
+	}
+	for _, service := range model.resources {
+		if service.kind != "Service" || len(service.selector) == 0 {
+			continue
+		}
+		for _, workload := range model.resources {
+			if _, ok := k8sWorkloadKinds[workload.kind]; ok && workload.namespace == service.namespace && selects(service.selector, workload.labels) {
+				model.link(service, workload, strings.Join(service.ports, ", "))
+			}
+		}
+	}
+	return model, nil
+}
+
+// k8sRoute is an Ingress route to a Service.
+type k8sRoute struct {
+	service, label string
+}
+
+// add adds the resources in a YAML document, recursing into Lists.
+func (m *k8sModel) add(node *yaml.Node) error {
+	if node.Kind == 0 {
+		return nil // Empty document
+	}
+	var object k8sObject
+	if err := node.Decode(&object); err != nil {
+		return err
+	}
+	if object.Kind == "List" || strings.HasSuffix(object.Kind, "List") {
+		for i := range object.Items {
+			if err := m.add(&object.Items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	namespace := object.Metadata.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	resource := &k8sResource{kind: object.Kind, name: object.Metadata.Name, namespace: namespace, defined: true}
+	spec := object.Spec
+	switch {
+	case k8sWorkloadKinds[object.Kind] != "":
+		resource.labels = spec.Template.Metadata.Labels
+		for _, container := range spec.Template.Spec.Containers {
+			resource.images = append(resource.images, container.Image)
+		}
+	case object.Kind == "Service":
+		// Unlike workloads' matchLabels, a Service's selector is a plain map
+		_ = spec.Selector.Decode(&resource.selector)
+		resource.serviceType = spec.Type
+		for _, port := range spec.Ports {
+			resource.ports = append(resource.ports, string(port.Port))
+		}
+	case object.Kind == "Ingress":
+		resource.tls = len(spec.TLS) > 0
+		for _, backend := range []*k8sBackend{spec.DefaultBackend, spec.Backend} {
+			if backend != nil && backend.name() != "" {
+				resource.routes = append(resource.routes, k8sRoute{service: backend.name(), label: "default"})
+			}
+		}
+		for _, rule := range spec.Rules {
+			if rule.Host != "" && !slices.Contains(resource.hosts, rule.Host) {
+				resource.hosts = append(resource.hosts, rule.Host)
+			}
+			for _, path := range rule.HTTP.Paths {
+				if name := path.Backend.name(); name != "" {
+					resource.routes = append(resource.routes, k8sRoute{service: name, label: rule.Host + path.Path})
+				}
+			}
+		}
+	default:
+		return nil
+	}
+	m.addResource(resource)
+	return nil
+}
+
+// addResource adds a resource, giving it a diagram ID from its kind,
+// namespace and name that is unique in the model.
+func (m *k8sModel) addResource(resource *k8sResource) *k8sResource {
+	prefix := k8sWorkloadKinds[resource.kind]
+	switch resource.kind {
+	case "Service":
+		prefix = "svc"
+	case "Ingress":
+		prefix = "ing"
+	}
+	id := prefix + "_" + identifier(resource.namespace) + "_" + identifier(resource.name)
+	resource.id = id
+	for n := 2; slices.ContainsFunc(m.resources, func(r *k8sResource) bool { return r.id == resource.id }); n++ {
+		resource.id = id + "_" + strconv.Itoa(n)
+	}
+	m.resources = append(m.resources, resource)
+	return resource
+}
+
+// find returns the resource of the given kind, name and namespace, or nil.
+func (m *k8sModel) find(kind, name, namespace string) *k8sResource {
+	for _, resource := range m.resources {
+		if resource.kind == kind && resource.name == name && resource.namespace == namespace {
+			return resource
+		}
+	}
+	return nil
+}
+
+// link connects two resources, adding label to an existing link between them.
+func (m *k8sModel) link(from, to *k8sResource, label string) {
+	for _, link := range m.links {
+		if link.from == from && link.to == to {
+			if label != "" && !slices.Contains(link.labels, label) {
+				link.labels = append(link.labels, label)
+			}
+			return
+		}
+	}
+	link := &k8sLink{from: from, to: to}
+	if label != "" {
+		link.labels = []string{label}
+	}
+	m.links = append(m.links, link)
+}
+
+// namespaces returns the namespaces of the model's resources in the order
+// they first appear.
+func (m *k8sModel) namespaces() []string {
+	var namespaces []string
+	for _, resource := range m.resources {
+		if !slices.Contains(namespaces, resource.namespace) {
+			namespaces = append(namespaces, resource.namespace)
+		}
+	}
+	return namespaces
+}
+
+// portsText describes a Service's ports, as in "Port 80" or "Ports 80, 443".
+func portsText(ports []string) string {
+	switch len(ports) {
+	case 0:
+		return ""
+	case 1:
+		return "Port " + ports[0]
+	}
+	return "Ports " + strings.Join(ports, ", ")
+}
+
+// selects reports whether a Service selector matches a pod's labels.
+func selects(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,222 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// openAPIMethods are the path item keys naming operations, in the order they
+// are searched.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPISpec is the part of an OpenAPI document sequence generation reads.
+type openAPISpec struct {
+	Info struct {
+		Title string `yaml:"title"`
+	} `yaml:"info"`
+	Paths map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+// openAPIOperation is the part of an OpenAPI operation sequence generation
+// reads.
+type openAPIOperation struct {
+	OperationID string `yaml:"operationId"`
+	Summary     string `yaml:"summary"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema openAPISchema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Description string `yaml:"description"`
+		Ref         string `yaml:"$ref"`
+	} `yaml:"responses"`
+}
+
+// openAPISchema is a schema, of which only a reference to a named one is used.
+type openAPISchema struct {
+	Ref string `yaml:"$ref"`
+}
+
+// SequenceFromOpenAPI generates a skeletal sequence diagram for one operation
+// of an OpenAPI 3 (or Swagger 2) document in YAML or JSON. The operation is
+// named by its operationId, or as a method and path such as "GET /pets/{id}".
+// The diagram has the client calling the API with the operation's method and
+// path, a note with its summary, a placeholder downstream call to fill in,
+// and the API's responses, in an alt block when there are several.
+//
+// SequenceFromOpenAPI returns an error if the document can't be read or has
+// no such operation; the error lists the operation IDs it does have.
+func SequenceFromOpenAPI(spec []byte, operation string) (*ast.SequenceDiagram, error) {
+	var doc openAPISpec
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("reading OpenAPI document: %w", err)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("OpenAPI document has no paths")
+	}
+
+	method, path, op, err := findOperation(doc, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	api := ast.Participant{ID: "API", Type: "participant"}
+	if title := sequenceText(doc.Info.Title); title != "" && title != api.ID {
+		api.Alias = title
+	}
+	request := strings.ToUpper(method) + " " + path
+	if op.RequestBody != nil {
+		if schema := requestSchema(op); schema != "" {
+			request += " (" + schema + ")"
+		}
+	}
+
+	statements := []ast.SeqStmt{
+		&ast.Participant{ID: "Client", Type: "actor"},
+		&api,
+		&ast.Participant{ID: "Downstream", Type: "participant"},
+		&ast.Message{From: "Client", To: "API", Arrow: "->>", Text: sequenceText(request)},
+		&ast.Activation{Participant: "API", Active: true},
+	}
+	if summary := sequenceText(op.Summary); summary != "" {
+		statements = append(statements, &ast.Note{Position: "over", Participants: []string{"API"}, Text: summary})
+	}
+	statements = append(statements,
+		&ast.Note{Position: "right of", Participants: []string{"Downstream"}, Text: "Placeholder: replace with the services " + operationName(op, request) + " calls"},
+		&ast.Message{From: "API", To: "Downstream", Arrow: "->>", Text: "Request"},
+		&ast.Message{From: "Downstream", To: "API", Arrow: "-->>", Text: "Result"},
+	)
+	statements = append(statements, responseStatements(op)...)
+	statements = append(statements, &ast.Activation{Participant: "API", Active: false})
+
+	return &ast.SequenceDiagram{Type: "sequence", Statements: statements}, nil
+}
+
+// findOperation returns the method, path and definition of the operation
+// named by operationId or as "METHOD /path".
+func findOperation(doc openAPISpec, operation string) (string, string, openAPIOperation, error) {
+	wantMethod, wantPath, byPath := strings.Cut(strings.TrimSpace(operation), " ")
+	wantPath = strings.TrimSpace(wantPath)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	var ids []string
+	for _, path := range paths {
+		for _, method := range openAPIMethods {
+			node, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				return "", "", op, fmt.Errorf("reading %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			if op.OperationID == operation || (byPath && strings.EqualFold(wantMethod, method) && wantPath == path) {
+				return method, path, op, nil
+			}
+			if op.OperationID != "" {
+				ids = append(ids, op.OperationID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return "", "", openAPIOperation{}, fmt.Errorf("operation %q not found", operation)
+	}
+	return "", "", openAPIOperation{}, fmt.Errorf("operation %q not found (available: %s)", operation, strings.Join(ids, ", "))
+}
+
+// requestSchema returns the name of the schema an operation's request body
+// references, preferring JSON content, or "" if it doesn't reference one.
+func requestSchema(op openAPIOperation) string {
+	types := make([]string, 0, len(op.RequestBody.Content))
+	for contentType := range op.RequestBody.Content {
+		types = append(types, contentType)
+	}
+	slices.SortFunc(types, func(a, b string) int {
+		if (a == "application/json") != (b == "application/json") {
+			if a == "application/json" {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	for _, contentType := range types {
+		if ref := op.RequestBody.Content[contentType].Schema.Ref; ref != "" {
+			return ref[strings.LastIndex(ref, "/")+1:]
+		}
+	}
+	return ""
+}
+
+// responseStatements returns the API's replies to the client, one per
+// documented response status, in an alt block when there are several.
+// Numbered statuses come first, in order, then ranges such as 4XX and default.
+func responseStatements(op openAPIOperation) []ast.SeqStmt {
+	statuses := make([]string, 0, len(op.Responses))
+	for status := range op.Responses {
+		statuses = append(statuses, status)
+	}
+	slices.SortFunc(statuses, func(a, b string) int {
+		if (a == "default") != (b == "default") {
+			if a == "default" {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(strings.ToUpper(a), strings.ToUpper(b))
+	})
+
+	reply := func(status string) *ast.Message {
+		text := status
+		if description := sequenceText(op.Responses[status].Description); description != "" {
+			text += " " + description
+		}
+		return &ast.Message{From: "API", To: "Client", Arrow: "-->>", Text: text}
+	}
+	switch len(statuses) {
+	case 0:
+		return []ast.SeqStmt{&ast.Message{From: "API", To: "Client", Arrow: "-->>", Text: "Response"}}
+	case 1:
+		return []ast.SeqStmt{reply(statuses[0])}
+	}
+	alt := &ast.Alt{}
+	for i, status := range statuses {
+		alt.Conditions = append(alt.Conditions, ast.AltCondition{
+			Label:      status,
+			Statements: []ast.SeqStmt{reply(status)},
+			IsElse:     i > 0,
+		})
+	}
+	return []ast.SeqStmt{alt}
+}
+
+// operationName names an operation in the placeholder note: its operationId,
+// or its method and path.
+func operationName(op openAPIOperation, request string) string {
+	if op.OperationID != "" {
+		return sequenceText(op.OperationID)
+	}
+	method, path, _ := strings.Cut(request, " ")
+	return method + " " + strings.Fields(path)[0]
+}
+
+// sequenceText makes text from the document safe for a single line of a
+// sequence diagram: the first line only, without the semicolons and hashes
+// Mermaid treats specially.
+func sequenceText(text string) string {
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	text = strings.NewReplacer(";", ",", "#", "").Replace(text)
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,120 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// FormatSequence renders a sequence diagram as Mermaid source, indenting each
+// statement four spaces per level of nesting.
+func FormatSequence(diagram *ast.SequenceDiagram) string {
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n")
+	writeSeqStatements(&b, diagram.Statements, 1)
+	return b.String()
+}
+
+// writeSeqStatements writes statements at the given nesting depth.
+func writeSeqStatements(b *strings.Builder, statements []ast.SeqStmt, depth int) {
+	indent := strings.Repeat("    ", depth)
+	line := func(format string, args ...any) {
+		b.WriteString(indent)
+		fmt.Fprintf(b, format, args...)
+		b.WriteString("\n")
+	}
+	block := func(keyword, label string, body []ast.SeqStmt) {
+		line("%s", strings.TrimSpace(keyword+" "+label))
+		writeSeqStatements(b, body, depth+1)
+	}
+
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			line("%s", formatParticipant(*s))
+		case *ast.Message:
+			to := s.To
+			if s.Activate {
+				to = "+" + to
+			} else if s.Deactivate {
+				to = "-" + to
+			}
+			if s.Text == "" {
+				line("%s%s%s", s.From, s.Arrow, to)
+			} else {
+				line("%s%s%s: %s", s.From, s.Arrow, to, s.Text)
+			}
+		case *ast.Activation:
+			if s.Active {
+				line("activate %s", s.Participant)
+			} else {
+				line("deactivate %s", s.Participant)
+			}
+		case *ast.Note:
+			line("Note %s %s: %s", s.Position, strings.Join(s.Participants, ","), s.Text)
+		case *ast.Loop:
+			block("loop", s.Label, s.Statements)
+			line("end")
+		case *ast.Opt:
+			block("opt", s.Label, s.Statements)
+			line("end")
+		case *ast.Break:
+			block("break", s.Label, s.Statements)
+			line("end")
+		case *ast.Alt:
+			for i, condition := range s.Conditions {
+				keyword := "else"
+				if i == 0 {
+					keyword = "alt"
+				}
+				block(keyword, condition.Label, condition.Statements)
+			}
+			line("end")
+		case *ast.Par:
+			for i, branch := range s.Branches {
+				keyword := "and"
+				if i == 0 {
+					keyword = "par"
+				}
+				block(keyword, branch.Label, branch.Statements)
+			}
+			line("end")
+		case *ast.Critical:
+			block("critical", s.Label, s.Statements)
+			for _, option := range s.Options {
+				block("option", option.Label, option.Statements)
+			}
+			line("end")
+		case *ast.Box:
+			line("%s", strings.TrimSpace("box "+strings.TrimSpace(s.Colour+" "+s.Label)))
+			for _, participant := range s.Participants {
+				b.WriteString(indent + "    " + formatParticipant(participant) + "\n")
+			}
+			line("end")
+		case *ast.Autonumber:
+			switch {
+			case !s.Enabled:
+				line("autonumber off")
+			case len(s.Args) > 0:
+				line("autonumber %s", strings.Join(s.Args, " "))
+			default:
+				line("autonumber")
+			}
+		case *ast.SeqComment:
+			line("%%%%%s", s.Text)
+		}
+	}
+}
+
+// formatParticipant renders a participant or actor declaration.
+func formatParticipant(p ast.Participant) string {
+	kind := p.Type
+	if kind == "" {
+		kind = "participant"
+	}
+	if p.Alias != "" {
+		return kind + " " + p.ID + " as " + p.Alias
+	}
+	return kind + " " + p.ID
+}
@@ -0,0 +1,577 @@
+package generate
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// createTablePattern matches the start of a CREATE TABLE statement up to the
+// table name.
+var createTablePattern = regexp.MustCompile(`(?is)^CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?`)
+
+// alterTablePattern matches the start of an ALTER TABLE statement up to the
+// table name, as pg_dump writes them to add keys after creating tables.
+var alterTablePattern = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?`)
+
+// multiWordTypes maps SQL types written as several words to a single ER
+// attribute type.
+var multiWordTypes = map[string]string{
+	"double precision":            "double",
+	"character varying":           "varchar",
+	"national character varying":  "varchar",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+	"time with time zone":         "timetz",
+	"time without time zone":      "time",
+}
+
+// columnKeywords end a column's type: the words that start a column constraint
+// or option.
+var columnKeywords = []string{
+	"NOT", "NULL", "PRIMARY", "UNIQUE", "REFERENCES", "DEFAULT", "CHECK", "CONSTRAINT",
+	"COLLATE", "GENERATED", "AUTO_INCREMENT", "AUTOINCREMENT", "IDENTITY", "COMMENT",
+	"UNSIGNED", "SIGNED", "ZEROFILL", "CHARACTER", "CHARSET", "ON", "AS",
+}
+
+// sqlColumn is a column of a table being converted.
+type sqlColumn struct {
+	attr    ast.ERAttribute
+	notNull bool
+}
+
+// sqlTable is a table being converted, with its foreign keys.
+type sqlTable struct {
+	name        string // Name as written
+	columns     []*sqlColumn
+	foreignKeys []sqlForeignKey
+}
+
+// sqlForeignKey is a foreign key from columns of one table to another table.
+type sqlForeignKey struct {
+	columns []string
+	target  string
+}
+
+// ERFromSQL generates an ER diagram from the CREATE TABLE statements in ddl,
+// so that a diagram can be started from an existing schema and then linted.
+// Each table becomes an entity named in upper case (the parser requires it),
+// aliased to the table's name when that differs. Columns become attributes,
+// with PK, UK and FK markers from primary key, unique and foreign key
+// constraints and comments from MySQL COMMENT clauses. Each foreign key
+// becomes a relationship from the referenced table, labelled with the
+// referencing columns: exactly one referenced row when the columns are NOT
+// NULL (otherwise zero or one), and zero or one referencing rows when the
+// columns are unique (otherwise zero or more). Keys added by ALTER TABLE ...
+// ADD, as pg_dump writes them, are applied too; other statements are ignored.
+//
+// ERFromSQL returns an error if ddl has no CREATE TABLE statements.
+func ERFromSQL(ddl string) (*ast.ERDiagram, error) {
+	var tables []*sqlTable
+	for _, statement := range splitTopLevel(stripSQLComments(ddl), ';') {
+		statement = strings.TrimSpace(statement)
+		if prefix := createTablePattern.FindString(statement); prefix != "" {
+			if table := parseCreateTable(statement[len(prefix):]); table != nil {
+				tables = append(tables, table)
+			}
+		} else if prefix := alterTablePattern.FindString(statement); prefix != "" {
+			parseAlterTable(tables, statement[len(prefix):])
+		}
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("no CREATE TABLE statements found")
+	}
+
+	diagram := &ast.ERDiagram{Type: "er"}
+	defined := make(map[string]bool)
+	for _, table := range tables {
+		name := entityName(table.name)
+		defined[name] = true
+		entity := ast.EREntity{Name: name}
+		if name != table.name {
+			entity.Alias = table.name
+		}
+		for _, column := range table.columns {
+			entity.Attributes = append(entity.Attributes, column.attr)
+		}
+		diagram.Entities = append(diagram.Entities, entity)
+	}
+
+	for _, table := range tables {
+		for _, fk := range table.foreignKeys {
+			target := entityName(fk.target)
+			if !defined[target] {
+				defined[target] = true
+				diagram.Entities = append(diagram.Entities, ast.EREntity{Name: target, Alias: aliasFor(target, fk.target)})
+			}
+			diagram.Relationships = append(diagram.Relationships, table.relationship(fk, target))
+		}
+	}
+	return diagram, nil
+}
+
+// relationship returns the ER relationship for a foreign key of t to target.
+func (t *sqlTable) relationship(fk sqlForeignKey, target string) ast.ERRelationship {
+	notNull, inPrimaryKey := true, true
+	for _, name := range fk.columns {
+		column := t.column(name)
+		if column == nil {
+			notNull, inPrimaryKey = false, false
+			continue
+		}
+		notNull = notNull && column.notNull
+		inPrimaryKey = inPrimaryKey && slices.Contains(column.attr.Keys, "PK")
+	}
+
+	rel := ast.ERRelationship{
+		From:     target,
+		To:       entityName(t.name),
+		FromCard: "|o",
+		ToCard:   "o{",
+		Type:     "..",
+		Label:    strings.Join(fk.columns, ", "),
+	}
+	if notNull {
+		rel.FromCard = "||"
+	}
+	if inPrimaryKey {
+		rel.Type = "--"
+	}
+	if t.unique(fk.columns) {
+		rel.ToCard = "o|"
+	}
+	return rel
+}
+
+// column returns the column of t with the given name, ignoring case.
+func (t *sqlTable) column(name string) *sqlColumn {
+	for _, column := range t.columns {
+		if strings.EqualFold(column.attr.Name, attributeName(name)) {
+			return column
+		}
+	}
+	return nil
+}
+
+// unique reports whether columns identify at most one row of t: a single
+// unique column, or exactly the primary key.
+func (t *sqlTable) unique(columns []string) bool {
+	if len(columns) == 1 {
+		if column := t.column(columns[0]); column != nil && slices.Contains(column.attr.Keys, "UK") {
+			return true
+		}
+	}
+	var primary []string
+	for _, column := range t.columns {
+		if slices.Contains(column.attr.Keys, "PK") {
+			primary = append(primary, strings.ToLower(column.attr.Name))
+		}
+	}
+	if len(primary) == 0 || len(primary) != len(columns) {
+		return false
+	}
+	for _, name := range columns {
+		if !slices.Contains(primary, strings.ToLower(attributeName(name))) {
+			return false
+		}
+	}
+	return true
+}
+
+// addKey adds an attribute key marker to the named column.
+func (t *sqlTable) addKey(name, key string) {
+	if column := t.column(name); column != nil && !slices.Contains(column.attr.Keys, key) {
+		column.attr.Keys = append(column.attr.Keys, key)
+		if key == "PK" {
+			column.notNull = true
+		}
+	}
+}
+
+// parseCreateTable parses a CREATE TABLE statement after the TABLE keyword:
+// the table name and its parenthesised definitions. It returns nil for
+// statements without a column list, such as CREATE TABLE ... AS SELECT.
+func parseCreateTable(statement string) *sqlTable {
+	open := strings.IndexByte(statement, '(')
+	if open < 0 {
+		return nil
+	}
+	body, ok := parenthesised(statement[open:])
+	if !ok {
+		return nil
+	}
+	table := &sqlTable{name: unquoteIdentifier(strings.TrimSpace(statement[:open]))}
+
+	var constraints [][]string
+	for _, definition := range splitTopLevel(body, ',') {
+		tokens := sqlTokens(definition)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch strings.ToUpper(tokens[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "KEY", "INDEX", "CHECK", "EXCLUDE", "FULLTEXT", "SPATIAL":
+			constraints = append(constraints, tokens)
+			continue
+		}
+		table.parseColumn(tokens)
+	}
+	for _, tokens := range constraints {
+		table.parseConstraint(tokens)
+	}
+	return table
+}
+
+// parseAlterTable applies the constraints an ALTER TABLE ... ADD statement
+// adds to one of tables, ignoring other alterations and unknown tables.
+func parseAlterTable(tables []*sqlTable, statement string) {
+	tokens := sqlTokens(statement)
+	if len(tokens) < 3 || !strings.EqualFold(tokens[1], "ADD") {
+		return
+	}
+	name := unquoteIdentifier(tokens[0])
+	for _, table := range tables {
+		if strings.EqualFold(table.name, name) {
+			table.parseConstraint(tokens[2:])
+			return
+		}
+	}
+}
+
+// parseColumn adds the column defined by tokens to t.
+func (t *sqlTable) parseColumn(tokens []string) {
+	column := &sqlColumn{attr: ast.ERAttribute{Name: attributeName(unquoteIdentifier(tokens[0]))}}
+	i := 1
+	var words []string
+	size := ""
+	for ; i < len(tokens); i++ {
+		token := tokens[i]
+		if strings.HasPrefix(token, "(") {
+			size = token
+			continue
+		}
+		if slices.Contains(columnKeywords, strings.ToUpper(token)) && !isTypeContinuation(words, token) {
+			break
+		}
+		words = append(words, strings.ToLower(token))
+	}
+	column.attr.Type = attributeType(words, size)
+
+	for ; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "PRIMARY":
+			column.attr.Keys = appendKey(column.attr.Keys, "PK")
+			column.notNull = true
+		case "UNIQUE":
+			column.attr.Keys = appendKey(column.attr.Keys, "UK")
+		case "NOT":
+			if i+1 < len(tokens) && strings.EqualFold(tokens[i+1], "NULL") {
+				column.notNull = true
+			}
+		case "REFERENCES":
+			if i+1 < len(tokens) {
+				column.attr.Keys = appendKey(column.attr.Keys, "FK")
+				t.foreignKeys = append(t.foreignKeys, sqlForeignKey{columns: []string{column.attr.Name}, target: unquoteIdentifier(tokens[i+1])})
+			}
+		case "COMMENT":
+			if i+1 < len(tokens) {
+				column.attr.Comment = unquoteString(tokens[i+1])
+			}
+		}
+	}
+	t.columns = append(t.columns, column)
+}
+
+// parseConstraint applies a table constraint to t's columns.
+func (t *sqlTable) parseConstraint(tokens []string) {
+	if strings.EqualFold(tokens[0], "CONSTRAINT") && len(tokens) > 2 {
+		tokens = tokens[2:]
+	}
+	keyword := strings.ToUpper(tokens[0])
+	var columns []string
+	next := 1
+	for ; next < len(tokens); next++ {
+		if strings.HasPrefix(tokens[next], "(") {
+			columns = columnList(tokens[next])
+			next++
+			break
+		}
+	}
+
+	switch keyword {
+	case "PRIMARY":
+		for _, name := range columns {
+			t.addKey(name, "PK")
+		}
+	case "UNIQUE":
+		if len(columns) == 1 {
+			t.addKey(columns[0], "UK")
+		}
+	case "FOREIGN":
+		for i := next; i+1 < len(tokens); i++ {
+			if strings.EqualFold(tokens[i], "REFERENCES") {
+				for _, name := range columns {
+					t.addKey(name, "FK")
+				}
+				t.foreignKeys = append(t.foreignKeys, sqlForeignKey{columns: columns, target: unquoteIdentifier(tokens[i+1])})
+				break
+			}
+		}
+	}
+}
+
+// isTypeContinuation reports whether keyword continues a multi-word type
+// rather than starting a constraint, as "character" does in "national
+// character varying".
+func isTypeContinuation(words []string, keyword string) bool {
+	candidate := strings.Join(append(slices.Clone(words), strings.ToLower(keyword)), " ")
+	for multiWord := range multiWordTypes {
+		if strings.HasPrefix(multiWord, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeType converts the words of an SQL column type and its size (such
+// as "(255)") into an ER attribute type, which must be a single word.
+func attributeType(words []string, size string) string {
+	if len(words) == 0 {
+		return "any" // SQLite allows columns without a type
+	}
+	joined := strings.Join(words, " ")
+	if mapped, ok := multiWordTypes[joined]; ok {
+		joined = mapped
+	}
+	name := attributeName(strings.ReplaceAll(joined, " ", "_"))
+	if !unicode.IsLetter([]rune(name)[0]) {
+		name = "t" + name
+	}
+	size = strings.Join(strings.Fields(size), "")
+	if size != "" && !strings.Contains(size, ",") {
+		name += size
+	}
+	return name
+}
+
+// entityName converts a table name to an ER entity name, which the parser
+// requires to be upper case.
+func entityName(table string) string {
+	var b strings.Builder
+	for i, r := range strings.ToUpper(table) {
+		switch {
+		case unicode.IsUpper(r) || unicode.Is(unicode.Lo, r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '-')):
+			b.WriteRune(r)
+		case i == 0 && unicode.IsDigit(r):
+			b.WriteString("_")
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// aliasFor returns the alias showing a table's original name, or "" if the
+// entity name is the same.
+func aliasFor(entity, table string) string {
+	if entity == table {
+		return ""
+	}
+	return table
+}
+
+// attributeName converts a column name to an ER attribute name.
+func attributeName(column string) string {
+	var b strings.Builder
+	for i, r := range column {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || unicode.IsMark(r) || r == '-')):
+			b.WriteRune(r)
+		case i == 0 && unicode.IsDigit(r):
+			b.WriteString("_")
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// appendKey adds key to keys unless already present.
+func appendKey(keys []string, key string) []string {
+	if slices.Contains(keys, key) {
+		return keys
+	}
+	return append(keys, key)
+}
+
+// columnList splits a parenthesised column list such as ("a", b) into names.
+func columnList(group string) []string {
+	inner, _ := parenthesised(group)
+	var columns []string
+	for _, part := range splitTopLevel(inner, ',') {
+		if tokens := sqlTokens(part); len(tokens) > 0 {
+			columns = append(columns, unquoteIdentifier(tokens[0]))
+		}
+	}
+	return columns
+}
+
+// unquoteIdentifier strips the quotes from an identifier quoted with double
+// quotes, backticks or brackets, keeping only the last part of a qualified
+// name such as public.orders.
+func unquoteIdentifier(name string) string {
+	parts := splitTopLevel(name, '.')
+	name = strings.TrimSpace(parts[len(parts)-1])
+	if len(name) >= 2 {
+		switch {
+		case name[0] == '"' && name[len(name)-1] == '"',
+			name[0] == '`' && name[len(name)-1] == '`',
+			name[0] == '[' && name[len(name)-1] == ']':
+			return name[1 : len(name)-1]
+		}
+	}
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = strings.TrimSpace(name[:i]) // REFERENCES orders(id)
+	}
+	return name
+}
+
+// unquoteString strips the quotes from an SQL string literal.
+func unquoteString(literal string) string {
+	if len(literal) >= 2 && literal[0] == '\'' && literal[len(literal)-1] == '\'' {
+		return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")
+	}
+	return literal
+}
+
+// parenthesised returns the text inside the parenthesised group s starts with.
+func parenthesised(s string) (string, bool) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitTopLevel splits s at each sep outside quotes and parentheses.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[' && sep == '.':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// sqlTokens splits a definition into words, quoted identifiers and strings,
+// and parenthesised groups, which are kept whole.
+func sqlTokens(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			group, _ := parenthesised(s[i:])
+			tokens = append(tokens, "("+group+")")
+			i += len(group) + 2
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(s[i+1:], closing)
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+			token := s[i:min(i+end+2, len(s))]
+			// A qualified name continues after the quotes, as in "public"."orders"
+			for j := i + len(token); j < len(s) && s[j] != ' ' && s[j] != '(' && s[j] != ','; j++ {
+				token += string(s[j])
+			}
+			tokens = append(tokens, token)
+			i += len(token)
+		default:
+			j := i
+			for j < len(s) && !unicode.IsSpace(rune(s[j])) && s[j] != '(' && s[j] != '\'' {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// stripSQLComments removes -- and /* */ comments outside string literals.
+func stripSQLComments(sql string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+			continue
+		}
+		if i < len(sql) {
+			b.WriteByte(sql[i])
+		}
+	}
+	return b.String()
+}
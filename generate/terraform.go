@@ -0,0 +1,366 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// terraformGraph is a dependency graph read from `terraform graph`: the node
+// names in the order they appear, and edges from each node to the nodes it
+// depends on.
+type terraformGraph struct {
+	nodes []string
+	edges map[string][]string
+}
+
+// addNode adds a node unless already present.
+func (g *terraformGraph) addNode(name string) {
+	if _, ok := g.edges[name]; !ok {
+		g.nodes = append(g.nodes, name)
+		g.edges[name] = nil
+	}
+}
+
+// addEdge adds an edge, and its nodes unless already present.
+func (g *terraformGraph) addEdge(from, to string) {
+	g.addNode(from)
+	g.addNode(to)
+	if !slices.Contains(g.edges[from], to) {
+		g.edges[from] = append(g.edges[from], to)
+	}
+}
+
+// terraformResource is a resource or data source in the graph.
+type terraformResource struct {
+	id      string
+	address string   // Address within its module, such as aws_subnet.a
+	modules []string // Module path, such as [vpc subnets] for module.vpc.module.subnets
+	data    bool
+}
+
+// TerraformFlowchart generates a flowchart of the resources and data sources
+// in the output of `terraform graph`, as the DOT it writes or as Graphviz JSON
+// (`terraform graph | dot -Tjson`). Resources in modules are grouped in a
+// subgraph per module, nested for nested modules, and labelled with their
+// address in the module; data sources have rounded ends. Links run from each
+// resource to the resources it depends on, right to left as Terraform draws
+// them. Providers, variables, locals, outputs and Terraform's own nodes are
+// left out, with dependencies through them kept.
+//
+// TerraformFlowchart returns an error if the graph can't be read or has no
+// resources.
+func TerraformFlowchart(graph []byte) (*ast.Flowchart, error) {
+	var g *terraformGraph
+	var err error
+	if trimmed := bytes.TrimSpace(graph); len(trimmed) > 0 && trimmed[0] == '{' {
+		g, err = readGraphvizJSON(trimmed)
+	} else {
+		g, err = readDOT(string(graph))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string]*terraformResource)
+	for _, name := range g.nodes {
+		if resource := parseTerraformNode(name); resource != nil {
+			resources[name] = resource
+		}
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("no resources found in the Terraform graph")
+	}
+
+	// Give each distinct address one node, as older versions of Terraform
+	// draw several (such as "aws_vpc.main (expand)") for some resources.
+	byAddress := make(map[string]*terraformResource)
+	var ordered []*terraformResource
+	for _, name := range g.nodes {
+		resource, ok := resources[name]
+		if !ok {
+			continue
+		}
+		key := strings.Join(resource.modules, ".") + "/" + resource.address
+		if existing, ok := byAddress[key]; ok {
+			resources[name] = existing
+			continue
+		}
+		byAddress[key] = resource
+		resource.id = uniqueID(ordered, identifier(strings.Join(append(slices.Clone(resource.modules), resource.address), "_")))
+		ordered = append(ordered, resource)
+	}
+
+	diagram := &ast.Flowchart{Type: "flowchart", Direction: "RL"}
+	subgraphs := make(map[string]*ast.Subgraph)
+	for _, resource := range ordered {
+		statements := &diagram.Statements
+		for depth := range resource.modules {
+			path := "module." + strings.Join(resource.modules[:depth+1], ".module.")
+			subgraph, ok := subgraphs[path]
+			if !ok {
+				subgraph = &ast.Subgraph{ID: identifier(path), Title: "module." + resource.modules[depth]}
+				subgraphs[path] = subgraph
+				*statements = append(*statements, subgraph)
+			}
+			statements = &subgraph.Statements
+		}
+		shape := "[]"
+		if resource.data {
+			shape = "([])"
+		}
+		*statements = append(*statements, &ast.NodeDef{ID: resource.id, Shape: shape, Label: labelText(resource.address)})
+	}
+
+	type edge struct{ from, to string }
+	var drawn []edge
+	for _, name := range g.nodes {
+		from, ok := resources[name]
+		if !ok {
+			continue
+		}
+		for _, target := range dependencies(g, resources, name) {
+			e := edge{from.id, resources[target].id}
+			if e.from != e.to && !slices.Contains(drawn, e) {
+				drawn = append(drawn, e)
+				diagram.Statements = append(diagram.Statements, &ast.Link{From: e.from, To: e.to, Arrow: "-->", Length: 1})
+			}
+		}
+	}
+	return diagram, nil
+}
+
+// dependencies returns the resource nodes name depends on, directly or
+// through nodes that aren't resources.
+func dependencies(g *terraformGraph, resources map[string]*terraformResource, name string) []string {
+	var found []string
+	visited := map[string]bool{name: true}
+	stack := slices.Clone(g.edges[name])
+	for len(stack) > 0 {
+		next := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[next] {
+			continue
+		}
+		visited[next] = true
+		if _, ok := resources[next]; ok {
+			found = append(found, next)
+			continue
+		}
+		stack = append(stack, g.edges[next]...)
+	}
+	slices.SortStableFunc(found, func(a, b string) int {
+		return slices.Index(g.nodes, a) - slices.Index(g.nodes, b)
+	})
+	return found
+}
+
+// parseTerraformNode returns the resource or data source a graph node names,
+// or nil for other nodes. Names such as `[root] module.vpc.aws_vpc.main
+// (expand)` from older versions of Terraform are reduced to the address.
+func parseTerraformNode(name string) *terraformResource {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "[root] ")
+	if i := strings.Index(name, " ("); i >= 0 && strings.HasSuffix(name, ")") {
+		name = name[:i]
+	}
+
+	resource := &terraformResource{}
+	for strings.HasPrefix(name, "module.") {
+		module, rest, ok := strings.Cut(strings.TrimPrefix(name, "module."), ".")
+		if !ok {
+			return nil // The module itself
+		}
+		resource.modules = append(resource.modules, module)
+		name = rest
+	}
+	if rest, ok := strings.CutPrefix(name, "data."); ok {
+		resource.data = true
+		name = rest
+	}
+
+	kind, _, ok := strings.Cut(name, ".")
+	if !ok || kind == "" || strings.ContainsAny(kind, `[]" `) {
+		return nil
+	}
+	switch kind {
+	case "var", "local", "output", "meta", "provider", "module", "root", "terraform", "path", "count", "each", "self":
+		return nil
+	}
+	resource.address = name
+	if resource.data {
+		resource.address = "data." + name
+	}
+	return resource
+}
+
+// uniqueID returns id, with a number added if a resource already has it.
+func uniqueID(resources []*terraformResource, id string) string {
+	candidate := id
+	for n := 2; slices.ContainsFunc(resources, func(r *terraformResource) bool { return r.id == candidate }); n++ {
+		candidate = fmt.Sprintf("%s_%d", id, n)
+	}
+	return candidate
+}
+
+// readGraphvizJSON reads a graph in the JSON Graphviz writes with -Tjson:
+// subgraphs then nodes in "objects", and edges between node IDs.
+func readGraphvizJSON(data []byte) (*terraformGraph, error) {
+	var doc struct {
+		SubgraphCount int `json:"_subgraph_cnt"`
+		Objects       []struct {
+			ID   int    `json:"_gvid"`
+			Name string `json:"name"`
+		} `json:"objects"`
+		Edges []struct {
+			Tail int `json:"tail"`
+			Head int `json:"head"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("reading Graphviz JSON: %w", err)
+	}
+
+	g := &terraformGraph{edges: make(map[string][]string)}
+	names := make(map[int]string)
+	for i, object := range doc.Objects {
+		if i >= doc.SubgraphCount {
+			names[object.ID] = object.Name
+			g.addNode(object.Name)
+		}
+	}
+	for _, edge := range doc.Edges {
+		from, okFrom := names[edge.Tail]
+		to, okTo := names[edge.Head]
+		if !okFrom || !okTo {
+			return nil, fmt.Errorf("reading Graphviz JSON: edge between unknown nodes %d and %d", edge.Tail, edge.Head)
+		}
+		g.addEdge(from, to)
+	}
+	return g, nil
+}
+
+// readDOT reads the nodes and edges of a graph in the DOT language, ignoring
+// attributes and subgraphs.
+func readDOT(source string) (*terraformGraph, error) {
+	tokens, err := dotTokens(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 || !slices.Contains([]string{"digraph", "graph", "strict"}, strings.ToLower(tokens[0].text)) {
+		return nil, errors.New("not a DOT graph: expected digraph")
+	}
+
+	g := &terraformGraph{edges: make(map[string][]string)}
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.text == "[":
+			// Skip an attribute list
+			for i < len(tokens) && tokens[i].text != "]" {
+				i++
+			}
+		case token.quoted || isDOTID(token.text):
+			next := ""
+			if i+1 < len(tokens) {
+				next = tokens[i+1].text
+			}
+			switch {
+			case !token.quoted && slices.Contains([]string{"digraph", "graph", "strict", "subgraph", "node", "edge"}, strings.ToLower(token.text)):
+				continue
+			case next == "=":
+				i += 2 // An attribute such as rankdir = "RL"
+				continue
+			case i > 0 && tokens[i-1].text == "subgraph" && !tokens[i-1].quoted:
+				continue // A subgraph's name
+			case i > 0 && (tokens[i-1].text == "digraph" || tokens[i-1].text == "graph") && !tokens[i-1].quoted:
+				continue // The graph's name
+			}
+			g.addNode(token.text)
+			for i+2 < len(tokens) && (tokens[i+1].text == "->" || tokens[i+1].text == "--") {
+				g.addEdge(tokens[i].text, tokens[i+2].text)
+				i += 2
+			}
+		}
+	}
+	return g, nil
+}
+
+// dotToken is a token of the DOT language. Quoted strings are unquoted.
+type dotToken struct {
+	text   string
+	quoted bool
+}
+
+// isDOTID reports whether text is an unquoted DOT identifier or number.
+func isDOTID(text string) bool {
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != '-' {
+			return false
+		}
+	}
+	return text != "" && text != "-"
+}
+
+// dotTokens splits DOT source into tokens, skipping comments.
+func dotTokens(source string) ([]dotToken, error) {
+	var tokens []dotToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ';' || r == ',':
+			i++
+		case r == '#' || (r == '/' && i+1 < len(runes) && runes[i+1] == '/'):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			j := i + 2
+			for j+1 < len(runes) && (runes[j] != '*' || runes[j+1] != '/') {
+				j++
+			}
+			if j+1 >= len(runes) {
+				return nil, errors.New("unterminated comment in DOT graph")
+			}
+			i = j + 2
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) && (runes[j+1] == '"' || runes[j+1] == '\\') {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, errors.New("unterminated string in DOT graph")
+			}
+			tokens = append(tokens, dotToken{text: b.String(), quoted: true})
+			i = j + 1
+		case r == '-' && i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '-'):
+			tokens = append(tokens, dotToken{text: string(runes[i : i+2])})
+			i += 2
+		case strings.ContainsRune("{}[]=", r):
+			tokens = append(tokens, dotToken{text: string(r)})
+			i++
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune(`{}[]=;,"`, runes[j]) &&
+				!(runes[j] == '-' && j+1 < len(runes) && (runes[j+1] == '>' || runes[j+1] == '-')) {
+				j++
+			}
+			if j == i {
+				j++
+			}
+			tokens = append(tokens, dotToken{text: string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,230 @@
+package generate_test
+
+import (
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/generate"
+)
+
+const shopManifests = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  selector:
+    matchLabels: {app: web}
+  template:
+    metadata:
+      labels: {app: web, tier: frontend}
+    spec:
+      containers:
+        - image: nginx:1.27
+---
+apiVersion: v1
+kind: Service
+metadata: {name: web, namespace: shop}
+spec:
+  selector: {app: web}
+  ports: [{port: 80, targetPort: http}, {port: 443}]
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata: {name: shop, namespace: shop}
+spec:
+  tls: [{hosts: [shop.example.com]}]
+  rules:
+    - host: shop.example.com
+      http:
+        paths:
+          - path: /
+            backend: {service: {name: web, port: {number: 80}}}
+          - path: /api
+            backend: {service: {name: api, port: {number: 8080}}}
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: StatefulSet
+    metadata: {name: db}
+    spec:
+      template:
+        metadata:
+          labels: {app: db}
+        spec:
+          containers: [{image: postgres:16}]
+  - apiVersion: v1
+    kind: Service
+    metadata: {name: db}
+    spec:
+      selector: {app: db}
+      ports: [{port: 5432}]
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata: {name: settings}
+`
+
+// validateSource parses generated source and fails the test if the default
+// or strict rules report anything.
+func validateSource(t *testing.T, source string) {
+	t.Helper()
+	diagram, err := mermaid.Parse(source)
+	if err != nil {
+		t.Fatalf("generated diagram does not parse: %v\n%s", err, source)
+	}
+	for _, strict := range []bool{false, true} {
+		if errors := mermaid.Validate(diagram, strict); len(errors) > 0 {
+			t.Errorf("generated diagram has errors (strict=%v): %v\n%s", strict, errors, source)
+		}
+	}
+}
+
+func TestKubernetesFlowchart(t *testing.T) {
+	diagram, err := generate.KubernetesFlowchart([]byte(shopManifests))
+	if err != nil {
+		t.Fatalf("KubernetesFlowchart() error = %v", err)
+	}
+	got := generate.FormatFlowchart(diagram)
+	validateSource(t, got)
+
+	want := `flowchart LR
+    subgraph ns_shop [shop]
+        deploy_shop_web[Deployment web]
+        svc_shop_web([Service web])
+        ing_shop_shop{{Ingress shop}}
+        svc_shop_api([Service api])
+    end
+    subgraph ns_default [default]
+        sts_default_db[(StatefulSet db)]
+        svc_default_db([Service db])
+    end
+    ing_shop_shop -->|shop.example.com/| svc_shop_web
+    ing_shop_shop -->|shop.example.com/api| svc_shop_api
+    svc_shop_web -->|80, 443| deploy_shop_web
+    svc_default_db -->|5432| sts_default_db
+`
+	if got != want {
+		t.Errorf("flowchart =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestKubernetesC4(t *testing.T) {
+	diagram, err := generate.KubernetesC4([]byte(shopManifests))
+	if err != nil {
+		t.Fatalf("KubernetesC4() error = %v", err)
+	}
+	got := generate.FormatC4(diagram)
+	validateSource(t, got)
+
+	for _, want := range []string{
+		"C4Container\n",
+		`Person(user, "User")`,
+		`System_Boundary(ns_shop, "shop") {`,
+		`Container(deploy_shop_web, "web", "Deployment", "nginx:1.27")`,
+		`Container(svc_shop_web, "web", "Service", "Ports 80, 443")`,
+		`Container_Ext(svc_shop_api, "api", "Service")`,
+		`Container(sts_default_db, "db", "StatefulSet", "postgres:16")`,
+		`Rel(user, ing_shop_shop, "Uses", "HTTPS")`,
+		`Rel(ing_shop_shop, svc_shop_api, "Routes shop.example.com/api")`,
+		`Rel(svc_default_db, sts_default_db, "Forwards to", "Port 5432")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diagram does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestKubernetes_SelectorMismatch(t *testing.T) {
+	diagram, err := generate.KubernetesFlowchart([]byte(`kind: Deployment
+metadata: {name: api, namespace: a}
+spec: {template: {metadata: {labels: {app: api}}}}
+---
+kind: Service
+metadata: {name: api, namespace: b}
+spec: {selector: {app: api}}
+---
+kind: Service
+metadata: {name: other, namespace: a}
+spec: {selector: {app: api, tier: backend}}
+`))
+	if err != nil {
+		t.Fatalf("KubernetesFlowchart() error = %v", err)
+	}
+	for _, stmt := range diagram.Statements {
+		if link, ok := stmt.(*ast.Link); ok {
+			t.Errorf("unexpected link %s --> %s: selectors match only labels in the same namespace", link.From, link.To)
+		}
+	}
+}
+
+func TestKubernetes_Errors(t *testing.T) {
+	for name, manifests := range map[string]string{
+		"no resources": "kind: ConfigMap\nmetadata: {name: settings}\n",
+		"invalid YAML": "kind: Service\n  metadata: [\n",
+	} {
+		if _, err := generate.KubernetesFlowchart([]byte(manifests)); err == nil {
+			t.Errorf("%s: KubernetesFlowchart() error = nil", name)
+		}
+		if _, err := generate.KubernetesC4([]byte(manifests)); err == nil {
+			t.Errorf("%s: KubernetesC4() error = nil", name)
+		}
+	}
+}
+
+func TestFormatFlowchart(t *testing.T) {
+	diagram := &ast.Flowchart{Type: "graph", Direction: "TD", Statements: []ast.Statement{
+		&ast.ClassDef{Name: "warn", Styles: map[string]string{"stroke": "#f00", "fill": "#ff0"}},
+		&ast.Subgraph{ID: "s1", Title: "Stage one", Direction: "LR", Statements: []ast.Statement{
+			&ast.NodeDef{ID: "A", Shape: "[]", Label: "Start"},
+			&ast.NodeDef{ID: "B", Shape: ">]", Label: "Flag"},
+		}},
+		&ast.Link{From: "A", To: "B", Arrow: "-.->", Label: "maybe"},
+		&ast.Link{From: "B", To: "C", Arrow: "==>"},
+		&ast.ClassAssignment{NodeIDs: []string{"A", "B"}, ClassName: "warn"},
+		&ast.Style{NodeID: "C", Styles: map[string]string{"fill": "#0f0"}},
+	}}
+
+	want := `graph TD
+    classDef warn fill:#ff0,stroke:#f00
+    subgraph s1 [Stage one]
+        direction LR
+        A[Start]
+        B>Flag]
+    end
+    A -.->|maybe| B
+    B ==> C
+    class A,B warn
+    style C fill:#0f0
+`
+	if got := generate.FormatFlowchart(diagram); got != want {
+		t.Errorf("FormatFlowchart() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatC4(t *testing.T) {
+	diagram := &ast.C4Diagram{
+		DiagramType: "c4Dynamic",
+		Title:       "Checkout",
+		Elements:    []ast.C4Element{{ElementType: "Person", ID: "user", Label: "User", Description: `Says "hi"`}},
+		Boundaries: []ast.C4Boundary{{BoundaryType: "Boundary", ID: "b", Label: "Backend", Type: "Cluster",
+			Elements: []ast.C4Element{{ElementType: "Container", ID: "db", Label: "DB", Description: "Stores", Database: true}},
+		}},
+		Relationships: []ast.C4Relationship{{RelType: "RelIndex", Index: "1", From: "user", To: "db", Label: "Reads"}},
+	}
+
+	want := `C4Dynamic
+    title Checkout
+    Person(user, "User", "Says 'hi'")
+    Boundary(b, "Backend", "Cluster") {
+        ContainerDb(db, "DB", "", "Stores")
+    }
+    RelIndex(1, user, db, "Reads")
+`
+	if got := generate.FormatC4(diagram); got != want {
+		t.Errorf("FormatC4() =\n%s\nwant\n%s", got, want)
+	}
+}
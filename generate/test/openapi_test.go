@@ -0,0 +1,164 @@
+package generate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/generate"
+)
+
+const petstore = `openapi: 3.0.0
+info:
+  title: Pet Store
+  version: 1.0.0
+paths:
+  /pets:
+    parameters:
+      - name: tenant
+        in: header
+    get:
+      operationId: listPets
+      summary: List all pets
+      responses:
+        200:
+          description: A page of pets
+        default:
+          description: Unexpected error
+    post:
+      operationId: createPet
+      summary: "Create a pet; or two\nSecond line"
+      requestBody:
+        content:
+          application/xml:
+            schema:
+              $ref: '#/components/schemas/PetXML'
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        '201':
+          description: Created
+  /pets/{petId}:
+    delete:
+      responses: {}
+`
+
+func generateSequence(t *testing.T, spec, operation string) string {
+	t.Helper()
+	diagram, err := generate.SequenceFromOpenAPI([]byte(spec), operation)
+	if err != nil {
+		t.Fatalf("SequenceFromOpenAPI() error = %v", err)
+	}
+	source := generate.FormatSequence(diagram)
+	validateSource(t, source)
+	return source
+}
+
+func TestSequenceFromOpenAPI(t *testing.T) {
+	got := generateSequence(t, petstore, "listPets")
+	want := `sequenceDiagram
+    actor Client
+    participant API as Pet Store
+    participant Downstream
+    Client->>API: GET /pets
+    activate API
+    Note over API: List all pets
+    Note right of Downstream: Placeholder: replace with the services listPets calls
+    API->>Downstream: Request
+    Downstream-->>API: Result
+    alt 200
+        API-->>Client: 200 A page of pets
+    else default
+        API-->>Client: default Unexpected error
+    end
+    deactivate API
+`
+	if got != want {
+		t.Errorf("diagram =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSequenceFromOpenAPI_RequestBody(t *testing.T) {
+	got := generateSequence(t, petstore, "createPet")
+	for _, want := range []string{
+		"Client->>API: POST /pets (NewPet)",
+		"Note over API: Create a pet, or two\n",
+		"API-->>Client: 201 Created\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diagram does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "alt") {
+		t.Errorf("diagram with one response has an alt block:\n%s", got)
+	}
+}
+
+func TestSequenceFromOpenAPI_MethodAndPath(t *testing.T) {
+	got := generateSequence(t, `{"paths": {"/pets/{petId}": {"delete": {"responses": {}}}}}`, "DELETE /pets/{petId}")
+	for _, want := range []string{
+		"    participant API\n",
+		"Client->>API: DELETE /pets/{petId}",
+		"the services DELETE /pets/{petId} calls",
+		"API-->>Client: Response",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diagram does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestSequenceFromOpenAPI_UnknownOperation(t *testing.T) {
+	_, err := generate.SequenceFromOpenAPI([]byte(petstore), "deletePet")
+	if err == nil || !strings.Contains(err.Error(), "available: listPets, createPet") {
+		t.Errorf("SequenceFromOpenAPI() error = %v, want the available operations", err)
+	}
+	if _, err := generate.SequenceFromOpenAPI([]byte("info: {}"), "listPets"); err == nil {
+		t.Error("SequenceFromOpenAPI() error = nil for a document without paths")
+	}
+}
+
+func TestFormatSequence(t *testing.T) {
+	diagram := &ast.SequenceDiagram{Statements: []ast.SeqStmt{
+		&ast.Autonumber{Enabled: true},
+		&ast.Box{Label: "Backend", Participants: []ast.Participant{{ID: "A", Type: "participant", Alias: "Service A"}}},
+		&ast.Participant{ID: "B"},
+		&ast.Message{From: "A", To: "B", Arrow: "->>", Text: "call", Activate: true},
+		&ast.Loop{Label: "retry", Statements: []ast.SeqStmt{
+			&ast.Opt{Label: "on failure", Statements: []ast.SeqStmt{&ast.Message{From: "B", To: "A", Arrow: "--x"}}},
+		}},
+		&ast.Par{Branches: []ast.ParBranch{
+			{Label: "first", Statements: []ast.SeqStmt{&ast.Note{Position: "over", Participants: []string{"A", "B"}, Text: "both"}}},
+			{Label: "second", Statements: []ast.SeqStmt{&ast.Activation{Participant: "A", Active: true}}},
+		}},
+		&ast.Critical{Label: "commit", Statements: []ast.SeqStmt{&ast.Break{Label: "stop"}}, Options: []ast.CriticalOption{{Label: "timeout"}}},
+	}}
+
+	want := `sequenceDiagram
+    autonumber
+    box Backend
+        participant A as Service A
+    end
+    participant B
+    A->>+B: call
+    loop retry
+        opt on failure
+            B--xA
+        end
+    end
+    par first
+        Note over A,B: both
+    and second
+        activate A
+    end
+    critical commit
+        break stop
+        end
+    option timeout
+    end
+`
+	if got := generate.FormatSequence(diagram); got != want {
+		t.Errorf("FormatSequence() =\n%s\nwant\n%s", got, want)
+	}
+}
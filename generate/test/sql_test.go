@@ -0,0 +1,219 @@
+package generate_test
+
+import (
+	"slices"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/generate"
+)
+
+const shopDDL = `-- Shop schema
+CREATE TABLE IF NOT EXISTS public.customers (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    balance NUMERIC(10, 2) DEFAULT 0 /* cents would be better */
+);
+
+CREATE TABLE "orders" (
+    id BIGINT NOT NULL,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    placed TIMESTAMP WITH TIME ZONE,
+    note TEXT COMMENT 'free text; "optional"',
+    CONSTRAINT orders_pk PRIMARY KEY (id)
+);
+
+CREATE TABLE line_items (
+    order_id BIGINT,
+    line INT,
+    sku CHARACTER VARYING(64),
+    price DOUBLE PRECISION,
+    voucher_id INT,
+    PRIMARY KEY (order_id, line),
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (voucher_id) REFERENCES vouchers (id)
+);
+
+CREATE INDEX orders_customer ON orders (customer_id);
+`
+
+func generateER(t *testing.T, ddl string) (*ast.ERDiagram, *ast.ERDiagram) {
+	t.Helper()
+	generated, err := generate.ERFromSQL(ddl)
+	if err != nil {
+		t.Fatalf("ERFromSQL() error = %v", err)
+	}
+	source := generate.FormatER(generated)
+	parsed, err := mermaid.Parse(source)
+	if err != nil {
+		t.Fatalf("generated diagram does not parse: %v\n%s", err, source)
+	}
+	for _, strict := range []bool{false, true} {
+		if errors := mermaid.Validate(parsed, strict); len(errors) > 0 {
+			t.Errorf("generated diagram has errors (strict=%v): %v\n%s", strict, errors, source)
+		}
+	}
+	return generated, parsed.(*ast.ERDiagram)
+}
+
+func findEntity(t *testing.T, diagram *ast.ERDiagram, name string) ast.EREntity {
+	t.Helper()
+	for _, entity := range diagram.Entities {
+		if entity.Name == name {
+			return entity
+		}
+	}
+	t.Fatalf("entity %s not found in %+v", name, diagram.Entities)
+	return ast.EREntity{}
+}
+
+func findAttribute(t *testing.T, entity ast.EREntity, name string) ast.ERAttribute {
+	t.Helper()
+	for _, attr := range entity.Attributes {
+		if attr.Name == name {
+			return attr
+		}
+	}
+	t.Fatalf("attribute %s not found in %s", name, entity.Name)
+	return ast.ERAttribute{}
+}
+
+func TestERFromSQL_Entities(t *testing.T) {
+	_, diagram := generateER(t, shopDDL)
+
+	customers := findEntity(t, diagram, "CUSTOMERS")
+	if customers.Alias != "customers" {
+		t.Errorf("CUSTOMERS alias = %q, want customers", customers.Alias)
+	}
+	tests := []struct {
+		entity, attribute, wantType string
+		wantKeys                    []string
+		wantComment                 string
+	}{
+		{"CUSTOMERS", "id", "serial", []string{"PK"}, ""},
+		{"CUSTOMERS", "email", "varchar(255)", []string{"UK"}, ""},
+		{"CUSTOMERS", "balance", "numeric", nil, ""},
+		{"ORDERS", "id", "bigint", []string{"PK"}, ""},
+		{"ORDERS", "customer_id", "integer", []string{"FK"}, ""},
+		{"ORDERS", "placed", "timestamptz", nil, ""},
+		{"ORDERS", "note", "text", nil, `free text; 'optional'`},
+		{"LINE_ITEMS", "order_id", "bigint", []string{"PK", "FK"}, ""},
+		{"LINE_ITEMS", "line", "int", []string{"PK"}, ""},
+		{"LINE_ITEMS", "sku", "varchar(64)", nil, ""},
+		{"LINE_ITEMS", "price", "double", nil, ""},
+	}
+	for _, tt := range tests {
+		attr := findAttribute(t, findEntity(t, diagram, tt.entity), tt.attribute)
+		if attr.Type != tt.wantType {
+			t.Errorf("%s.%s type = %q, want %q", tt.entity, tt.attribute, attr.Type, tt.wantType)
+		}
+		if !slices.Equal(attr.Keys, tt.wantKeys) {
+			t.Errorf("%s.%s keys = %v, want %v", tt.entity, tt.attribute, attr.Keys, tt.wantKeys)
+		}
+		if attr.Comment != tt.wantComment {
+			t.Errorf("%s.%s comment = %q, want %q", tt.entity, tt.attribute, attr.Comment, tt.wantComment)
+		}
+	}
+
+	// Referenced tables missing from the schema are declared without attributes
+	vouchers := findEntity(t, diagram, "VOUCHERS")
+	if len(vouchers.Attributes) != 0 {
+		t.Errorf("VOUCHERS attributes = %v, want none", vouchers.Attributes)
+	}
+}
+
+func TestERFromSQL_Relationships(t *testing.T) {
+	_, diagram := generateER(t, shopDDL)
+
+	var got []string
+	for _, rel := range diagram.Relationships {
+		got = append(got, rel.From+" "+rel.FromCard+rel.Type+rel.ToCard+" "+rel.To+" : "+rel.Label)
+	}
+	want := []string{
+		"CUSTOMERS ||..o{ ORDERS : customer_id",
+		"ORDERS ||--o{ LINE_ITEMS : order_id",
+		"VOUCHERS |o..o{ LINE_ITEMS : voucher_id",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("relationships = %v, want %v", got, want)
+	}
+}
+
+func TestERFromSQL_OneToOne(t *testing.T) {
+	_, diagram := generateER(t, "CREATE TABLE `users` (`id` INT PRIMARY KEY);\n"+
+		"CREATE TABLE `profiles` (`user_id` INT NOT NULL, bio TEXT, PRIMARY KEY (`user_id`), "+
+		"CONSTRAINT fk_user FOREIGN KEY (`user_id`) REFERENCES `users` (`id`));")
+
+	if len(diagram.Relationships) != 1 {
+		t.Fatalf("relationships = %+v, want 1", diagram.Relationships)
+	}
+	rel := diagram.Relationships[0]
+	if rel.FromCard != "||" || rel.Type != "--" || rel.ToCard != "o|" {
+		t.Errorf("relationship = %s%s%s, want ||--o|", rel.FromCard, rel.Type, rel.ToCard)
+	}
+}
+
+func TestERFromSQL_CompositeForeignKey(t *testing.T) {
+	_, diagram := generateER(t, `CREATE TABLE a (x INT, y INT, PRIMARY KEY (x, y));
+CREATE TABLE b (id INT PRIMARY KEY, ax INT, ay INT, FOREIGN KEY (ax, ay) REFERENCES a (x, y));`)
+
+	if len(diagram.Relationships) != 1 {
+		t.Fatalf("relationships = %+v, want 1", diagram.Relationships)
+	}
+	if got := diagram.Relationships[0].Label; got != `"ax, ay"` {
+		t.Errorf("label = %q, want %q", got, `"ax, ay"`)
+	}
+}
+
+func TestERFromSQL_AlterTable(t *testing.T) {
+	_, diagram := generateER(t, `CREATE TABLE public.teams (id integer NOT NULL);
+CREATE TABLE public.members (id integer NOT NULL, team_id integer);
+ALTER TABLE ONLY public.teams ADD CONSTRAINT teams_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.members
+    ADD CONSTRAINT members_team_id_fkey FOREIGN KEY (team_id) REFERENCES public.teams(id);
+ALTER TABLE public.members OWNER TO admin;`)
+
+	if keys := findAttribute(t, findEntity(t, diagram, "TEAMS"), "id").Keys; !slices.Equal(keys, []string{"PK"}) {
+		t.Errorf("TEAMS.id keys = %v, want [PK]", keys)
+	}
+	if len(diagram.Relationships) != 1 || diagram.Relationships[0].From != "TEAMS" || diagram.Relationships[0].To != "MEMBERS" {
+		t.Errorf("relationships = %+v, want TEAMS to MEMBERS", diagram.Relationships)
+	}
+}
+
+func TestERFromSQL_NoTables(t *testing.T) {
+	if _, err := generate.ERFromSQL("CREATE INDEX i ON t (c);\n-- CREATE TABLE commented (id INT);"); err == nil {
+		t.Error("ERFromSQL() error = nil, want error for a schema without tables")
+	}
+}
+
+func TestFormatER(t *testing.T) {
+	diagram := &ast.ERDiagram{
+		Direction: "LR",
+		Relationships: []ast.ERRelationship{
+			{From: "CUSTOMER", To: "ORDER", FromCard: "||", ToCard: "o{", Type: "--", Label: "places"},
+			{From: "ORDER", To: "LINE-ITEM", FromCard: "||", ToCard: "|{", Type: "..", Label: "contains many"},
+		},
+		Entities: []ast.EREntity{
+			{Name: "CUSTOMER", Alias: "Customer", Attributes: []ast.ERAttribute{
+				{Type: "int", Name: "id", Keys: []string{"PK"}},
+				{Type: "string", Name: "email", Keys: []string{"UK"}, Comment: "login"},
+			}},
+			{Name: "ORDER"},
+		},
+	}
+
+	want := `erDiagram LR
+    CUSTOMER ||--o{ ORDER : places
+    ORDER ||..|{ LINE-ITEM : "contains many"
+    CUSTOMER[Customer] {
+        int id PK
+        string email UK "login"
+    }
+    ORDER
+`
+	if got := generate.FormatER(diagram); got != want {
+		t.Errorf("FormatER() =\n%s\nwant\n%s", got, want)
+	}
+}
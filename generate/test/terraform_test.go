@@ -0,0 +1,134 @@
+package generate_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/generate"
+)
+
+const terraformDOT = `digraph G {
+  rankdir = "RL";
+  node [shape = rect, fontname = "sans-serif"];
+  "aws_instance.web" [label="aws_instance.web"];
+  "data.aws_ami.ubuntu" [label="data.aws_ami.ubuntu"];
+  subgraph "cluster_module.vpc" {
+    label = "module.vpc"
+    fontname = "sans-serif"
+    "module.vpc.aws_subnet.a" [label="aws_subnet.a"];
+    "module.vpc.aws_vpc.this" [label="aws_vpc.this"];
+    "module.vpc.module.nat.aws_nat_gateway.gw" [label="aws_nat_gateway.gw"];
+  }
+  "aws_instance.web" -> "data.aws_ami.ubuntu";
+  "aws_instance.web" -> "module.vpc.aws_subnet.a";
+  "module.vpc.aws_subnet.a" -> "module.vpc.aws_vpc.this";
+  "module.vpc.module.nat.aws_nat_gateway.gw" -> "module.vpc.aws_subnet.a";
+}
+`
+
+func terraformFlowchart(t *testing.T, graph string) string {
+	t.Helper()
+	diagram, err := generate.TerraformFlowchart([]byte(graph))
+	if err != nil {
+		t.Fatalf("TerraformFlowchart() error = %v", err)
+	}
+	source := generate.FormatFlowchart(diagram)
+	validateSource(t, source)
+	return source
+}
+
+func TestTerraformFlowchart(t *testing.T) {
+	got := terraformFlowchart(t, terraformDOT)
+	want := `flowchart RL
+    aws_instance_web[aws_instance.web]
+    data_aws_ami_ubuntu([data.aws_ami.ubuntu])
+    subgraph module_vpc [module.vpc]
+        vpc_aws_subnet_a[aws_subnet.a]
+        vpc_aws_vpc_this[aws_vpc.this]
+        subgraph module_vpc_module_nat [module.nat]
+            vpc_nat_aws_nat_gateway_gw[aws_nat_gateway.gw]
+        end
+    end
+    aws_instance_web --> data_aws_ami_ubuntu
+    aws_instance_web --> vpc_aws_subnet_a
+    vpc_aws_subnet_a --> vpc_aws_vpc_this
+    vpc_nat_aws_nat_gateway_gw --> vpc_aws_subnet_a
+`
+	if got != want {
+		t.Errorf("flowchart =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTerraformFlowchart_LegacyFormat(t *testing.T) {
+	// Terraform before 1.7 names nodes "[root] ..." and includes providers,
+	// variables and its own bookkeeping nodes.
+	got := terraformFlowchart(t, `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+		"[root] aws_security_group.web (expand)" [label = "aws_security_group.web", shape = "box"]
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"]" [label = "provider[\"registry.terraform.io/hashicorp/aws\"]", shape = "diamond"]
+		"[root] var.name" [label = "var.name", shape = "note"]
+		"[root] aws_instance.web (expand)" -> "[root] local.tags (expand)"
+		"[root] local.tags (expand)" -> "[root] aws_security_group.web (expand)"
+		"[root] aws_security_group.web (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"]"
+		"[root] aws_security_group.web (expand)" -> "[root] var.name"
+		"[root] meta.count-boundary (EachMode fixup)" -> "[root] aws_instance.web (expand)"
+		"[root] root" -> "[root] meta.count-boundary (EachMode fixup)"
+	}
+}`)
+
+	for _, want := range []string{
+		"aws_instance_web[aws_instance.web]",
+		"aws_security_group_web[aws_security_group.web]",
+		"aws_instance_web --> aws_security_group_web",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("flowchart does not contain %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"provider", "var", "meta", "root", "local"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("flowchart contains %q:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestTerraformFlowchart_GraphvizJSON(t *testing.T) {
+	got := terraformFlowchart(t, `{
+  "name": "G",
+  "directed": true,
+  "_subgraph_cnt": 1,
+  "objects": [
+    {"_gvid": 0, "name": "cluster_module.db", "nodes": [1]},
+    {"_gvid": 0, "name": "aws_instance.app", "label": "aws_instance.app"},
+    {"_gvid": 1, "name": "module.db.aws_db_instance.main", "label": "aws_db_instance.main"}
+  ],
+  "edges": [{"_gvid": 0, "tail": 0, "head": 1}]
+}`)
+
+	for _, want := range []string{
+		"subgraph module_db [module.db]",
+		"db_aws_db_instance_main[aws_db_instance.main]",
+		"aws_instance_app --> db_aws_db_instance_main",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("flowchart does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestTerraformFlowchart_Errors(t *testing.T) {
+	for name, graph := range map[string]string{
+		"no resources":       `digraph { "var.a" -> "provider[\"aws\"]" }`,
+		"not DOT":            "resource aws_instance web {}",
+		"unterminated":       `digraph { "aws_instance.web`,
+		"invalid JSON":       `{"objects": [}`,
+		"unknown JSON nodes": `{"_subgraph_cnt": 0, "objects": [{"_gvid": 0, "name": "aws_instance.a"}], "edges": [{"tail": 0, "head": 5}]}`,
+	} {
+		if _, err := generate.TerraformFlowchart([]byte(graph)); err == nil {
+			t.Errorf("%s: TerraformFlowchart() error = nil", name)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package inpututil
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchPath reports whether the file at name matches the glob pattern. Both
+// are resolved against the working directory, so relative patterns such as
+// "docs/legacy/**" match relative or absolute file paths alike. Within a path
+// segment the syntax is that of path.Match; a "**" segment matches any number
+// of directories, including none.
+func MatchPath(pattern, name string) bool {
+	absPattern, err := filepath.Abs(pattern)
+	if err != nil {
+		return false
+	}
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+	return matchSegments(
+		strings.Split(filepath.ToSlash(absPattern), "/"),
+		strings.Split(filepath.ToSlash(absName), "/"),
+	)
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
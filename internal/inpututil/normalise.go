@@ -0,0 +1,26 @@
+package inpututil
+
+import "strings"
+
+// byteOrderMark is the UTF-8 encoding of U+FEFF, which some Windows editors
+// prepend to text files.
+const byteOrderMark = "\uFEFF"
+
+// newlineReplacer converts Windows (\r\n) and classic Mac (\r) line endings to \n.
+var newlineReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// NormaliseNewlines strips a leading UTF-8 byte order mark and converts all line
+// endings to \n, so that line-anchored patterns behave the same for files
+// authored on any platform.
+func NormaliseNewlines(content string) string {
+	content = strings.TrimPrefix(content, byteOrderMark)
+	if !strings.Contains(content, "\r") {
+		return content
+	}
+	return newlineReplacer.Replace(content)
+}
+
+// TrimByteOrderMark removes a leading UTF-8 byte order mark from s, if present.
+func TrimByteOrderMark(s string) string {
+	return strings.TrimPrefix(s, byteOrderMark)
+}
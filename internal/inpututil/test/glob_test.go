@@ -0,0 +1,42 @@
+package inpututil_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+func TestMatchPath(t *testing.T) {
+	abs, err := filepath.Abs("docs/legacy/old.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"docs/legacy/**", "docs/legacy/old.md", true},
+		{"docs/legacy/**", "docs/legacy/deep/nested/old.md", true},
+		{"docs/legacy/**", abs, true},
+		{"docs/legacy/**", "./docs/legacy/old.md", true},
+		{"docs/legacy/**", "docs/current/new.md", false},
+		{"docs/*.md", "docs/readme.md", true},
+		{"docs/*.md", "docs/legacy/old.md", false},
+		{"**/*.mmd", "diagrams/flow.mmd", true},
+		{"**/*.mmd", "diagrams/flow.md", false},
+		{"docs/**/arch-*.md", "docs/arch-overview.md", true},
+		{"docs/**/arch-*.md", "docs/a/b/arch-overview.md", true},
+		{"docs/[", "docs/[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := inpututil.MatchPath(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package inpututil_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+func TestNormaliseNewlines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unix line endings", "a\nb\n", "a\nb\n"},
+		{"windows line endings", "a\r\nb\r\n", "a\nb\n"},
+		{"classic mac line endings", "a\rb", "a\nb"},
+		{"mixed line endings", "a\r\nb\nc\rd", "a\nb\nc\nd"},
+		{"byte order mark", "\uFEFFa\nb", "a\nb"},
+		{"byte order mark and windows line endings", "\uFEFFa\r\nb", "a\nb"},
+		{"byte order mark not at start is kept", "a\uFEFFb", "a\uFEFFb"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inpututil.NormaliseNewlines(tt.content); got != tt.want {
+				t.Errorf("NormaliseNewlines(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
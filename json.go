@@ -0,0 +1,213 @@
+package mermaid
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// JSONOptions are the options accepted by ValidateJSON, as a JSON object.
+type JSONOptions struct {
+	// Strict enables the strict rule set for every diagram type.
+	Strict bool `json:"strict"`
+	// Format is "mermaid" or "markdown"; when empty, source containing code
+	// fences is treated as markdown.
+	Format string `json:"format"`
+	// RequiredAnnotations lists metadata annotation keys every diagram must define.
+	RequiredAnnotations []string `json:"requiredAnnotations"`
+	// Disable lists the names of rules (see validator.RuleName) not to apply.
+	Disable []string `json:"disable"`
+	// Words are accepted words that enable the spell-check rule.
+	Words []string `json:"words"`
+	// FenceLanguages are the markdown code fence languages treated as Mermaid.
+	FenceLanguages []string `json:"fenceLanguages"`
+	// Renderer names the renderer diagrams are published with (see
+	// validator.RendererNames), reporting constructs it can't display.
+	Renderer string `json:"renderer"`
+	// Profile adds parse and validation times to each result.
+	Profile bool `json:"profile"`
+	// AST adds each parsed diagram's syntax tree to its result.
+	AST bool `json:"ast"`
+}
+
+// JSONReport is the result of ValidateJSON.
+type JSONReport struct {
+	// Valid is true when every diagram parsed with no findings of any
+	// severity, as Result.Valid reports for a single diagram; a warning or
+	// info finding makes it false too.
+	Valid bool `json:"valid"`
+	// Results has one entry per diagram found in the source.
+	Results []JSONResult `json:"results"`
+	// Error is set when the options or source could not be processed at all.
+	Error string `json:"error,omitempty"`
+}
+
+// JSONResult is the validation result for one diagram.
+type JSONResult struct {
+	BlockIndex  int         `json:"blockIndex"`
+	DiagramType string      `json:"diagramType"`
+	LineOffset  int         `json:"lineOffset"`
+	EndLine     int         `json:"endLine"`
+	ParseError  string      `json:"parseError,omitempty"`
+	Suggestion  string      `json:"suggestion,omitempty"`
+	Errors      []JSONError `json:"errors"`
+	// Metrics are set when JSONOptions.Profile is.
+	Metrics *JSONMetrics `json:"metrics,omitempty"`
+	// AST is the diagram's syntax tree, set when JSONOptions.AST is and the
+	// diagram parsed.
+	AST *ast.Document `json:"ast,omitempty"`
+}
+
+// JSONMetrics are a diagram's parse and validation times in milliseconds.
+type JSONMetrics struct {
+	ParseMs    float64          `json:"parseMs"`
+	ValidateMs float64          `json:"validateMs"`
+	Rules      []JSONRuleTiming `json:"rules,omitempty"`
+}
+
+// JSONRuleTiming is the time in milliseconds a rule spent on a diagram.
+type JSONRuleTiming struct {
+	Rule string  `json:"rule"`
+	Ms   float64 `json:"ms"`
+}
+
+// JSONError is a validation error. Line is relative to the diagram; add
+// LineOffset - 1 for the line in the source.
+type JSONError struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	// Fixable is true when FixSource can correct the error automatically.
+	Fixable bool `json:"fixable,omitempty"`
+	// Suggestion is a "did you mean" hint for correcting the error by hand.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// ValidateJSON validates source, a Mermaid diagram or markdown document, with
+// options given as a JSONOptions object (or ""), and returns a JSONReport as
+// JSON. It only deals in strings and never touches the file system, making it
+// suitable for calling from JavaScript when compiled to WebAssembly.
+func ValidateJSON(source, options string) string {
+	report := validateJSON(source, options)
+	data, err := json.Marshal(report)
+	if err != nil {
+		// Only strings, numbers and maps of them are marshalled, so this cannot happen
+		return fmt.Sprintf(`{"valid":false,"results":[],"error":%q}`, err.Error())
+	}
+	return string(data)
+}
+
+// validateJSON builds the report returned by ValidateJSON.
+func validateJSON(source, options string) JSONReport {
+	var opts JSONOptions
+	if options != "" {
+		if err := json.Unmarshal([]byte(options), &opts); err != nil {
+			return JSONReport{Results: []JSONResult{}, Error: fmt.Sprintf("invalid options: %v", err)}
+		}
+	}
+	return ValidateReport(source, opts)
+}
+
+// ValidateReport validates source, a Mermaid diagram or markdown document, and
+// returns the report ValidateJSON encodes.
+func ValidateReport(source string, opts JSONOptions) JSONReport {
+	report := JSONReport{Results: []JSONResult{}}
+
+	source = inpututil.NormaliseNewlines(source)
+	var markdown bool
+	switch opts.Format {
+	case "markdown":
+		markdown = true
+	case "mermaid":
+	case "":
+		markdown = containsMarkdownFences(source)
+	default:
+		report.Error = fmt.Sprintf("invalid format %q: expected mermaid or markdown", opts.Format)
+		return report
+	}
+
+	if opts.Renderer != "" {
+		if _, err := validator.ForRenderer(opts.Renderer); err != nil {
+			report.Error = err.Error()
+			return report
+		}
+	}
+
+	blocks, err := sourceBlocks(source, markdown, opts.FenceLanguages)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	validateOpts := ValidateOptions{
+		Strict:              opts.Strict,
+		RequiredAnnotations: opts.RequiredAnnotations,
+		Profile:             opts.Profile,
+		Renderer:            opts.Renderer,
+	}
+	if len(opts.Words) > 0 {
+		validateOpts.SpellChecker = validator.NewWordlistChecker(opts.Words...)
+	}
+
+	report.Valid = true
+	for _, result := range validateBlocks("", blocks, validateOpts) {
+		jsonResult := JSONResult{
+			BlockIndex:  result.BlockIndex,
+			DiagramType: result.DiagramType,
+			LineOffset:  result.LineOffset,
+			EndLine:     result.EndLine,
+			Errors:      []JSONError{},
+		}
+		if result.ParseError != nil {
+			jsonResult.ParseError = result.ParseError.Error()
+			jsonResult.Suggestion = result.Suggestion
+		}
+		for _, err := range validator.WithoutRules(result.Errors, opts.Disable...) {
+			jsonResult.Errors = append(jsonResult.Errors, JSONError{
+				Line:       err.Line,
+				Column:     err.Column,
+				Message:    err.Message,
+				Severity:   err.Severity.String(),
+				Rule:       err.Rule,
+				Fixable:    err.Fix != nil,
+				Suggestion: err.Suggestion,
+			})
+		}
+		if opts.AST && result.Diagram != nil {
+			document := ast.NewDocument(result.Diagram)
+			jsonResult.AST = &document
+		}
+		if result.Metrics != nil {
+			jsonResult.Metrics = jsonMetrics(*result.Metrics)
+		}
+		if jsonResult.ParseError != "" || len(jsonResult.Errors) > 0 {
+			report.Valid = false
+		}
+		report.Results = append(report.Results, jsonResult)
+	}
+
+	return report
+}
+
+// jsonMetrics converts metrics to milliseconds.
+func jsonMetrics(metrics Metrics) *JSONMetrics {
+	converted := &JSONMetrics{
+		ParseMs:    milliseconds(metrics.Parse),
+		ValidateMs: milliseconds(metrics.Validate),
+	}
+	for _, timing := range metrics.Rules {
+		converted.Rules = append(converted.Rules, JSONRuleTiming{Rule: timing.Rule, Ms: milliseconds(timing.Duration)})
+	}
+	return converted
+}
+
+// milliseconds returns d in milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,115 @@
+// Package mcpserver exposes diagram validation, extraction and fixing as
+// Model Context Protocol tools, so that agents generating Mermaid can check
+// their own output.
+package mcpserver
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/extractor"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// New creates an MCP server offering the validate_diagram, extract_diagrams
+// and fix_diagram tools. Run it with a transport such as mcp.StdioTransport.
+func New(version string) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "mermaid-check", Version: version}, nil)
+
+	readOnly := &mcp.ToolAnnotations{ReadOnlyHint: true}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "validate_diagram",
+		Description: "Validate a Mermaid diagram, or every Mermaid code block in a markdown document. Returns each diagram's parse error or validation errors (line numbers are relative to the diagram); errors marked fixable can be corrected with fix_diagram.",
+		Annotations: readOnly,
+	}, validateDiagram)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "extract_diagrams",
+		Description: "Extract the Mermaid code blocks from a markdown document, with their diagram types and line ranges.",
+		Annotations: readOnly,
+	}, extractDiagrams)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "fix_diagram",
+		Description: "Apply automatic fixes to a Mermaid diagram, returning the corrected source and a validation report for it. Only errors marked fixable are changed.",
+		Annotations: readOnly,
+	}, fixDiagram)
+
+	return server
+}
+
+// ValidateInput is the input of the validate_diagram tool.
+type ValidateInput struct {
+	Source string `json:"source" jsonschema:"Mermaid diagram source, or markdown containing mermaid code blocks"`
+	Strict bool   `json:"strict,omitempty" jsonschema:"apply the strict rule set, including style checks"`
+	Format string `json:"format,omitempty" jsonschema:"mermaid or markdown; detected from code fences when omitted"`
+}
+
+func validateDiagram(_ context.Context, _ *mcp.CallToolRequest, input ValidateInput) (*mcp.CallToolResult, mermaid.JSONReport, error) {
+	report := mermaid.ValidateReport(input.Source, mermaid.JSONOptions{Strict: input.Strict, Format: input.Format})
+	return nil, report, nil
+}
+
+// ExtractInput is the input of the extract_diagrams tool.
+type ExtractInput struct {
+	Markdown string `json:"markdown" jsonschema:"markdown document containing mermaid code blocks"`
+}
+
+// ExtractOutput is the output of the extract_diagrams tool.
+type ExtractOutput struct {
+	Diagrams []Diagram `json:"diagrams"`
+}
+
+// Diagram is a Mermaid code block extracted from markdown.
+type Diagram struct {
+	DiagramType string `json:"diagramType"`
+	LineOffset  int    `json:"lineOffset" jsonschema:"line of the markdown where the diagram source starts"`
+	EndLine     int    `json:"endLine" jsonschema:"line of the markdown where the diagram source ends"`
+	Source      string `json:"source"`
+	// Attributes are parsed from the code fence, as in ```mermaid {caption="..." id=fig-1}
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+func extractDiagrams(_ context.Context, _ *mcp.CallToolRequest, input ExtractInput) (*mcp.CallToolResult, ExtractOutput, error) {
+	blocks, err := extractor.ExtractFromMarkdown(inpututil.NormaliseNewlines(input.Markdown))
+	if err != nil {
+		return nil, ExtractOutput{}, err
+	}
+
+	output := ExtractOutput{Diagrams: make([]Diagram, 0, len(blocks))}
+	for _, block := range blocks {
+		output.Diagrams = append(output.Diagrams, Diagram{
+			DiagramType: block.DiagramType,
+			LineOffset:  block.LineOffset,
+			EndLine:     block.EndLine,
+			Source:      block.Source,
+			Attributes:  block.Attributes,
+		})
+	}
+	return nil, output, nil
+}
+
+// FixInput is the input of the fix_diagram tool.
+type FixInput struct {
+	Source string `json:"source" jsonschema:"Mermaid diagram source"`
+	Strict bool   `json:"strict,omitempty" jsonschema:"also apply fixes offered by the strict rule set"`
+}
+
+// FixOutput is the output of the fix_diagram tool.
+type FixOutput struct {
+	Source  string             `json:"source" jsonschema:"corrected diagram source"`
+	Applied int                `json:"applied" jsonschema:"number of fixes applied"`
+	Report  mermaid.JSONReport `json:"report" jsonschema:"validation report for the corrected source"`
+}
+
+func fixDiagram(_ context.Context, _ *mcp.CallToolRequest, input FixInput) (*mcp.CallToolResult, FixOutput, error) {
+	source := inpututil.NormaliseNewlines(input.Source)
+	result, err := mermaid.FixSource(source, mermaid.ValidateOptions{Strict: input.Strict})
+	if err != nil {
+		return nil, FixOutput{}, err
+	}
+	return nil, FixOutput{
+		Source:  result.Source,
+		Applied: result.Applied,
+		Report:  mermaid.ValidateReport(result.Source, mermaid.JSONOptions{Strict: input.Strict, Format: "mermaid"}),
+	}, nil
+}
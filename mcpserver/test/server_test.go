@@ -0,0 +1,95 @@
+package mcpserver_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sammcj/mermaid-check/mcpserver"
+)
+
+// callTool connects a client to a new server, calls a tool and decodes its
+// structured output into out.
+func callTool(t *testing.T, name string, arguments map[string]any, out any) *mcp.CallToolResult {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpserver.New("test").Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "test"}, nil)
+	session, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: name, Arguments: arguments})
+	if err != nil {
+		t.Fatalf("CallTool(%s) error = %v", name, err)
+	}
+	if out != nil && !result.IsError {
+		data, err := json.Marshal(result.StructuredContent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return result
+}
+
+func TestValidateDiagramTool(t *testing.T) {
+	var report struct {
+		Valid   bool `json:"valid"`
+		Results []struct {
+			DiagramType string `json:"diagramType"`
+			Errors      []struct {
+				Rule    string `json:"rule"`
+				Fixable bool   `json:"fixable"`
+			} `json:"errors"`
+		} `json:"results"`
+	}
+	callTool(t, "validate_diagram", map[string]any{"source": "classDiagram\n    class A\n    class A\n"}, &report)
+
+	if report.Valid || len(report.Results) != 1 || len(report.Results[0].Errors) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if e := report.Results[0].Errors[0]; e.Rule != "no-duplicate-classes" || !e.Fixable {
+		t.Errorf("unexpected error: %+v", e)
+	}
+}
+
+func TestExtractDiagramsTool(t *testing.T) {
+	var output struct {
+		Diagrams []struct {
+			DiagramType string `json:"diagramType"`
+			LineOffset  int    `json:"lineOffset"`
+			Source      string `json:"source"`
+		} `json:"diagrams"`
+	}
+	callTool(t, "extract_diagrams", map[string]any{"markdown": "# Doc\n\n```mermaid\nflowchart TD\n    A --> B\n```\n"}, &output)
+
+	if len(output.Diagrams) != 1 || output.Diagrams[0].DiagramType != "flowchart" || output.Diagrams[0].LineOffset != 4 {
+		t.Errorf("unexpected diagrams: %+v", output.Diagrams)
+	}
+}
+
+func TestFixDiagramTool(t *testing.T) {
+	var output struct {
+		Source  string `json:"source"`
+		Applied int    `json:"applied"`
+		Report  struct {
+			Valid bool `json:"valid"`
+		} `json:"report"`
+	}
+	callTool(t, "fix_diagram", map[string]any{"source": "classDiagram\n    class A\n    class A\n"}, &output)
+
+	if output.Source != "classDiagram\n    class A\n" || output.Applied != 1 || !output.Report.Valid {
+		t.Errorf("unexpected output: %+v", output)
+	}
+
+	if result := callTool(t, "fix_diagram", map[string]any{"source": "notADiagram"}, nil); !result.IsError {
+		t.Error("expected an error result for unparseable source")
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 
 	"github.com/sammcj/mermaid-check/ast"
 	"github.com/sammcj/mermaid-check/extractor"
-	"github.com/sammcj/mermaid-check/internal/inpututil"
 	"github.com/sammcj/mermaid-check/parser"
 	"github.com/sammcj/mermaid-check/validator"
 )
@@ -18,6 +18,20 @@ func Parse(source string) (ast.Diagram, error) {
 	return parser.Parse(source)
 }
 
+// ParseWithLimits parses a raw Mermaid diagram, rejecting input that exceeds the
+// given resource limits with an error wrapping parser.ErrLimitExceeded.
+// Use parser.DefaultLimits() as a starting point for untrusted input.
+func ParseWithLimits(source string, limits parser.Limits) (ast.Diagram, error) {
+	return parser.ParseWithLimits(source, limits)
+}
+
+// ParseRecovering parses a raw Mermaid diagram, collecting every syntax error
+// rather than stopping at the first. It returns a best-effort partial AST (nil if
+// none could be built) along with the errors found.
+func ParseRecovering(source string) (ast.Diagram, []parser.SyntaxError) {
+	return parser.ParseRecovering(source)
+}
+
 // ParseReader parses a raw Mermaid diagram from an io.Reader.
 // Returns a Diagram interface that can be a Flowchart or GenericDiagram depending on type.
 func ParseReader(r io.Reader) (ast.Diagram, error) {
@@ -45,9 +59,13 @@ func ParseFlowchart(source string) (*ast.Flowchart, error) {
 
 // ParseFile parses a file containing Mermaid diagram(s).
 // It auto-detects the file type based on extension and content:
-// - .mmd files are parsed as raw Mermaid (unless they contain markdown code fences)
-// - .md, .markdown, .mdx files are parsed as markdown and all Mermaid blocks are extracted
-// - If a .mmd file contains markdown code fences, it's treated as markdown
+//   - .mmd files are parsed as raw Mermaid (unless they contain markdown code fences),
+//     and may hold several diagrams separated by "---" lines or blank lines
+//     before a new header (see extractor.SplitMermaid)
+//   - .md, .markdown, .mdx files are parsed as markdown and all Mermaid blocks are extracted
+//   - If a .mmd file contains markdown code fences, it's treated as markdown
+//   - Go, JavaScript, TypeScript and Python files have the Mermaid blocks in their
+//     comments extracted (see extractor.ExtractFromComments)
 //
 // Returns a slice of diagrams (potentially multiple for markdown files).
 func ParseFile(path string) ([]ast.Diagram, error) {
@@ -56,43 +74,20 @@ func ParseFile(path string) ([]ast.Diagram, error) {
 		return nil, err
 	}
 
-	content := string(data)
-	fileType := inpututil.DetectFileType(path)
-
-	// Check if .mmd file contains markdown code fences
-	if fileType == inpututil.FileTypeMermaid && containsMarkdownFences(content) {
-		fileType = inpututil.FileTypeMarkdown
+	blocks, err := fileBlocks(path, string(data), nil)
+	if err != nil {
+		return nil, err
 	}
 
-	switch fileType {
-	case inpututil.FileTypeMermaid:
-		// Parse as raw Mermaid
-		diagram, err := Parse(content)
-		if err != nil {
-			return nil, err
-		}
-		return []ast.Diagram{diagram}, nil
-
-	case inpututil.FileTypeMarkdown:
-		// Extract and parse Mermaid blocks from markdown
-		blocks, err := extractor.ExtractFromMarkdown(content)
+	diagrams := make([]ast.Diagram, 0, len(blocks))
+	for _, block := range blocks {
+		diagram, err := Parse(block.Source)
 		if err != nil {
-			return nil, err
-		}
-
-		var diagrams []ast.Diagram
-		for _, block := range blocks {
-			diagram, err := Parse(block.Source)
-			if err != nil {
-				return nil, fmt.Errorf("error parsing Mermaid block at line %d: %w", block.LineOffset, err)
-			}
-			diagrams = append(diagrams, diagram)
+			return nil, fmt.Errorf("error parsing Mermaid block at line %d: %w", block.LineOffset, err)
 		}
-		return diagrams, nil
-
-	default:
-		return nil, fmt.Errorf("unsupported file type for %s", path)
+		diagrams = append(diagrams, diagram)
 	}
+	return diagrams, nil
 }
 
 // containsMarkdownFences checks if the content contains markdown code fences.
@@ -119,8 +114,48 @@ func ExtractFromMarkdown(markdown string) ([]extractor.DiagramBlock, error) {
 }
 
 // Validate validates any diagram using the appropriate validator.
-// Automatically detects diagram type and applies corresponding rules.
+// Automatically detects diagram type and applies corresponding rules; strict
+// runs the same rules as ValidateWith with LevelStrict, including those that
+// apply to every diagram type, such as self-loops and confusable characters.
+// Repeated errors are removed (see validator.Deduplicate) and the rest sorted
+// by line, column and rule name (see validator.SortErrors). ValidateWith
+// selects the rules more finely.
 func Validate(diagram ast.Diagram, strict bool) []validator.ValidationError {
+	level := LevelDefault
+	if strict {
+		level = LevelStrict
+	}
+	return validateWithOptions(diagram, ValidateOptions{Level: level})
+}
+
+// ValidateWith validates diagram with the rules opts selects: the rule set of
+// opts.Level, plus the rules named in opts.Rules and those other options
+// enable, less opts.Disable, with the severities of opts.Severities. The
+// options for diagram's kind in opts.Types apply. Errors are deduplicated
+// and sorted as Validate returns them; unlike ValidateFile, opts.PathRules and
+// opts.Baseline are not applied. An unknown opts.Renderer is reported as a
+// single renderer-compatibility error instead.
+func ValidateWith(diagram ast.Diagram, opts ValidateOptions) []validator.ValidationError {
+	if err := opts.check(); err != nil {
+		return optionsError(err)
+	}
+	return validateWithOptions(diagram, opts)
+}
+
+// optionsError reports an error from ValidateOptions.check as the single
+// error of a diagram.
+func optionsError(err error) []validator.ValidationError {
+	return []validator.ValidationError{{
+		Line:     1,
+		Column:   1,
+		Message:  err.Error(),
+		Severity: validator.SeverityError,
+		Rule:     "renderer-compatibility",
+	}}
+}
+
+// validateByType applies the default or strict rule set for the diagram's type.
+func validateByType(diagram ast.Diagram, strict bool) []validator.ValidationError {
 	switch d := diagram.(type) {
 	case *ast.Flowchart:
 		var rules []validator.Rule
@@ -262,6 +297,10 @@ func Validate(diagram ast.Diagram, strict bool) []validator.ValidationError {
 		return v.ValidateDiagram(diagram)
 
 	default:
+		// Diagrams from parsers added with parser.Register have no rules of their own
+		if slices.Contains(parser.Registered(), diagram.GetType()) {
+			return nil
+		}
 		return []validator.ValidationError{{
 			Line:     1,
 			Column:   1,
@@ -278,7 +317,9 @@ func ValidateFlowchart(diagram *ast.Flowchart, rules ...validator.Rule) []valida
 		rules = validator.DefaultRules()
 	}
 	v := validator.New(rules...)
-	return v.Validate(diagram)
+	errors := validator.Deduplicate(v.Validate(diagram))
+	validator.SortErrors(errors)
+	return errors
 }
 
 // Exported validation rules for convenience
@@ -287,10 +328,30 @@ var (
 	NoParenthesesInLabels = &validator.NoParenthesesInLabels{}
 	// ValidDirection checks if the flowchart direction is valid.
 	ValidDirection = &validator.ValidDirection{}
+	// ExplicitDirection warns when a flowchart header leaves out the direction.
+	ExplicitDirection = &validator.ExplicitDirection{}
+	// MaxLinkLength warns about links extended to span more than three ranks.
+	MaxLinkLength = &validator.MaxLinkLength{}
 	// NoUndefinedNodes checks that all referenced nodes are defined.
 	NoUndefinedNodes = &validator.NoUndefinedNodes{}
 	// NoDuplicateNodeIDs checks that node IDs are unique.
 	NoDuplicateNodeIDs = &validator.NoDuplicateNodeIDs{}
+	// ValidSubgraphReferences checks that links and class assignments target subgraph IDs, not titles.
+	ValidSubgraphReferences = &validator.ValidSubgraphReferences{}
+	// SubgraphMembership warns when a node is used in two subgraphs that don't nest, since Mermaid draws it in only one.
+	SubgraphMembership = &validator.SubgraphMembership{}
+	// ValidClassDefinitions checks that class assignments use defined classDefs.
+	ValidClassDefinitions = &validator.ValidClassDefinitions{}
+	// UnusedClassDefinitions warns about classDefs that are never assigned.
+	UnusedClassDefinitions = &validator.UnusedClassDefinitions{}
+	// SecureInteractions checks click statements for javascript: URLs, http links and callbacks needing securityLevel 'loose'.
+	SecureInteractions = &validator.SecureInteractions{}
+	// NoDuplicateLinks checks that no link is repeated with the same ends, arrow and label.
+	NoDuplicateLinks = &validator.NoDuplicateLinks{}
+	// UniqueNodeLabels warns when different node IDs share the same label text.
+	UniqueNodeLabels = &validator.UniqueNodeLabels{}
+	// ColourContrast checks classDef and style colours meet the WCAG AA text contrast ratio.
+	ColourContrast = &validator.ColourContrast{}
 )
 
 // DefaultRules returns the default set of validation rules.
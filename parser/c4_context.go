@@ -12,16 +12,18 @@ import (
 var (
 	c4TitlePattern         = regexp.MustCompile(`^\s*title\s+(.+)$`)
 	c4CommentPattern       = regexp.MustCompile(`^\s*%%.*$`)
-	c4PersonPattern        = regexp.MustCompile(`^\s*Person(?:_Ext)?\s*\(([^)]+)\)\s*$`)
-	c4SystemPattern        = regexp.MustCompile(`^\s*System(?:_Ext)?\s*\(([^)]+)\)\s*$`)
-	c4ContainerPattern     = regexp.MustCompile(`^\s*Container(?:Db|Queue)?\s*\(([^)]+)\)\s*$`)
-	c4ComponentPattern     = regexp.MustCompile(`^\s*Component(?:Db|Queue)?\s*\(([^)]+)\)\s*$`)
-	c4NodePattern          = regexp.MustCompile(`^\s*(?:Deployment_)?Node\s*\(([^)]+)\)\s*$`)
-	c4RelPattern           = regexp.MustCompile(`^\s*(Rel|Rel_Back|Rel_Neighbor|Rel_Down|Rel_Up|Rel_Left|Rel_Right|BiRel)\s*\(([^)]+)\)\s*$`)
-	c4BoundaryStartPattern = regexp.MustCompile(`^\s*(Boundary|Enterprise_Boundary|System_Boundary|Container_Boundary|Deployment_Node|Node)\s*\(([^)]+)\)\s*\{\s*$`)
+	c4PersonPattern        = regexp.MustCompile(`^\s*Person(_Ext)?\s*\((.+)\)\s*$`)
+	c4SystemPattern        = regexp.MustCompile(`^\s*System(Db|Queue)?(_Ext)?\s*\((.+)\)\s*$`)
+	c4ContainerPattern     = regexp.MustCompile(`^\s*Container(Db|Queue)?(_Ext)?\s*\((.+)\)\s*$`)
+	c4ComponentPattern     = regexp.MustCompile(`^\s*Component(Db|Queue)?(_Ext)?\s*\((.+)\)\s*$`)
+	c4NodePattern          = regexp.MustCompile(`^\s*(?:Deployment_)?Node(?:_L|_R)?\s*\((.+)\)\s*$`)
+	c4RelPattern           = regexp.MustCompile(`^\s*(Rel|RelIndex|Rel_Back|Rel_Neighbor|Rel_Down|Rel_D|Rel_Up|Rel_U|Rel_Left|Rel_L|Rel_Right|Rel_R|BiRel)\s*\((.+)\)\s*$`)
+	c4BoundaryStartPattern = regexp.MustCompile(`^\s*(Boundary|Enterprise_Boundary|System_Boundary|Container_Boundary|Deployment_Node|Node|Node_L|Node_R)\s*\((.+)\)\s*\{\s*$`)
 	c4BoundaryEndPattern   = regexp.MustCompile(`^\s*\}\s*$`)
-	c4ElementStylePattern  = regexp.MustCompile(`^\s*UpdateElementStyle\s*\(([^)]+)\)\s*$`)
-	c4RelStylePattern      = regexp.MustCompile(`^\s*UpdateRelStyle\s*\(([^)]+)\)\s*$`)
+	c4ElementStylePattern  = regexp.MustCompile(`^\s*UpdateElementStyle\s*\((.+)\)\s*$`)
+	c4RelStylePattern      = regexp.MustCompile(`^\s*UpdateRelStyle\s*\((.+)\)\s*$`)
+	c4LayoutConfigPattern  = regexp.MustCompile(`^\s*UpdateLayoutConfig\s*\((.*)\)\s*$`)
+	c4LayoutMacroPattern   = regexp.MustCompile(`^\s*(LAYOUT_TOP_DOWN|LAYOUT_LEFT_RIGHT|LAYOUT_LANDSCAPE|LAYOUT_WITH_LEGEND|LAYOUT_AS_SKETCH|SHOW_LEGEND|HIDE_STEREOTYPE)\s*\(\s*\)\s*$`)
 )
 
 // C4ContextParser parses C4 Context diagrams.
@@ -49,8 +51,9 @@ func parseC4Diagram(source, diagramType, expectedHeader string) (*ast.C4Diagram,
 		return nil, fmt.Errorf("empty diagram source")
 	}
 
-	// Check header
-	firstLine := strings.TrimSpace(lines[0])
+	// Check header, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if firstLine != expectedHeader {
 		return nil, fmt.Errorf("expected %s header, got: %s", expectedHeader, firstLine)
 	}
@@ -61,13 +64,14 @@ func parseC4Diagram(source, diagramType, expectedHeader string) (*ast.C4Diagram,
 		Boundaries:    []ast.C4Boundary{},
 		Relationships: []ast.C4Relationship{},
 		Styles:        []ast.C4Style{},
+		Layouts:       []ast.C4Layout{},
 		Source:        source,
 		Pos:           ast.Position{Line: 1, Column: 1},
 	}
 
 	// Parse body (skip header)
 	var err error
-	diagram.Boundaries, err = parseC4Body(lines[1:], 2, diagram)
+	diagram.Boundaries, err = parseC4Body(lines[header+1:], header+2, diagram)
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +177,13 @@ func parseC4Body(lines []string, startLine int, diagram *ast.C4Diagram) ([]ast.C
 			continue
 		}
 
+		// Parse layout directives
+		if layout, ok := parseC4Layout(trimmed, lineNum); ok {
+			diagram.Layouts = append(diagram.Layouts, layout)
+			i++
+			continue
+		}
+
 		// Unknown line
 		return nil, fmt.Errorf("line %d: unrecognised C4 syntax: %s", lineNum, trimmed)
 	}
@@ -274,80 +285,75 @@ func parseC4BoundaryContents(lines []string, startLine int, diagram *ast.C4Diagr
 func parseC4Element(line string, lineNum int) (ast.C4Element, bool) {
 	// Try Person
 	if matches := c4PersonPattern.FindStringSubmatch(line); matches != nil {
-		params := parseC4Parameters(matches[1])
+		params := parseC4Parameters(matches[2])
 		if len(params) < 2 {
 			return ast.C4Element{}, false
 		}
+		values := namedParams(params[2:], "descr", "sprite", "tags", "link")
 		return ast.C4Element{
 			ElementType: "Person",
 			ID:          params[0],
 			Label:       params[1],
-			Description: getParam(params, 2),
-			Sprite:      getParam(params, 3),
-			Tags:        getParam(params, 4),
-			Link:        getParam(params, 5),
-			External:    strings.Contains(line, "Person_Ext"),
+			Description: values["descr"],
+			Sprite:      values["sprite"],
+			Tags:        values["tags"],
+			Link:        values["link"],
+			External:    matches[1] != "",
 			Pos:         ast.Position{Line: lineNum, Column: 1},
 		}, true
 	}
 
 	// Try System
 	if matches := c4SystemPattern.FindStringSubmatch(line); matches != nil {
-		params := parseC4Parameters(matches[1])
+		params := parseC4Parameters(matches[3])
 		if len(params) < 2 {
 			return ast.C4Element{}, false
 		}
+		values := namedParams(params[2:], "descr", "sprite", "tags", "link")
 		return ast.C4Element{
 			ElementType: "System",
 			ID:          params[0],
 			Label:       params[1],
-			Description: getParam(params, 2),
-			Sprite:      getParam(params, 3),
-			Tags:        getParam(params, 4),
-			Link:        getParam(params, 5),
-			External:    strings.Contains(line, "System_Ext"),
+			Description: values["descr"],
+			Sprite:      values["sprite"],
+			Tags:        values["tags"],
+			Link:        values["link"],
+			External:    matches[2] != "",
+			Database:    matches[1] == "Db",
+			Queue:       matches[1] == "Queue",
 			Pos:         ast.Position{Line: lineNum, Column: 1},
 		}, true
 	}
 
-	// Try Container
-	if matches := c4ContainerPattern.FindStringSubmatch(line); matches != nil {
-		params := parseC4Parameters(matches[1])
-		if len(params) < 2 {
-			return ast.C4Element{}, false
+	// Try Container and Component, which share a signature
+	for _, candidate := range []struct {
+		elementType string
+		pattern     *regexp.Regexp
+	}{
+		{"Container", c4ContainerPattern},
+		{"Component", c4ComponentPattern},
+	} {
+		matches := candidate.pattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
 		}
-		return ast.C4Element{
-			ElementType: "Container",
-			ID:          params[0],
-			Label:       params[1],
-			Technology:  getParam(params, 2),
-			Description: getParam(params, 3),
-			Sprite:      getParam(params, 4),
-			Tags:        getParam(params, 5),
-			Link:        getParam(params, 6),
-			Database:    strings.Contains(line, "ContainerDb"),
-			Queue:       strings.Contains(line, "ContainerQueue"),
-			Pos:         ast.Position{Line: lineNum, Column: 1},
-		}, true
-	}
-
-	// Try Component
-	if matches := c4ComponentPattern.FindStringSubmatch(line); matches != nil {
-		params := parseC4Parameters(matches[1])
+		params := parseC4Parameters(matches[3])
 		if len(params) < 2 {
 			return ast.C4Element{}, false
 		}
+		values := namedParams(params[2:], "techn", "descr", "sprite", "tags", "link")
 		return ast.C4Element{
-			ElementType: "Component",
+			ElementType: candidate.elementType,
 			ID:          params[0],
 			Label:       params[1],
-			Technology:  getParam(params, 2),
-			Description: getParam(params, 3),
-			Sprite:      getParam(params, 4),
-			Tags:        getParam(params, 5),
-			Link:        getParam(params, 6),
-			Database:    strings.Contains(line, "ComponentDb"),
-			Queue:       strings.Contains(line, "ComponentQueue"),
+			Technology:  values["techn"],
+			Description: values["descr"],
+			Sprite:      values["sprite"],
+			Tags:        values["tags"],
+			Link:        values["link"],
+			External:    matches[2] != "",
+			Database:    matches[1] == "Db",
+			Queue:       matches[1] == "Queue",
 			Pos:         ast.Position{Line: lineNum, Column: 1},
 		}, true
 	}
@@ -358,15 +364,16 @@ func parseC4Element(line string, lineNum int) (ast.C4Element, bool) {
 		if len(params) < 2 {
 			return ast.C4Element{}, false
 		}
+		values := namedParams(params[2:], "type", "descr", "sprite", "tags", "link")
 		return ast.C4Element{
 			ElementType: "Node",
 			ID:          params[0],
 			Label:       params[1],
-			Technology:  getParam(params, 2),
-			Description: getParam(params, 3),
-			Sprite:      getParam(params, 4),
-			Tags:        getParam(params, 5),
-			Link:        getParam(params, 6),
+			Technology:  values["type"],
+			Description: values["descr"],
+			Sprite:      values["sprite"],
+			Tags:        values["tags"],
+			Link:        values["link"],
 			Pos:         ast.Position{Line: lineNum, Column: 1},
 		}, true
 	}
@@ -384,20 +391,31 @@ func parseC4Relationship(line string, lineNum int) (ast.C4Relationship, bool) {
 	relType := matches[1]
 	params := parseC4Parameters(matches[2])
 
+	// RelIndex takes the sequence index before the usual parameters
+	var index string
+	if relType == "RelIndex" {
+		if len(params) == 0 {
+			return ast.C4Relationship{}, false
+		}
+		index, params = params[0], params[1:]
+	}
+
 	if len(params) < 3 {
 		return ast.C4Relationship{}, false
 	}
 
+	values := namedParams(params[3:], "techn", "descr", "sprite", "tags", "link")
 	return ast.C4Relationship{
 		RelType:     relType,
+		Index:       index,
 		From:        params[0],
 		To:          params[1],
 		Label:       params[2],
-		Technology:  getParam(params, 3),
-		Description: getParam(params, 4),
-		Sprite:      getParam(params, 5),
-		Tags:        getParam(params, 6),
-		Link:        getParam(params, 7),
+		Technology:  values["techn"],
+		Description: values["descr"],
+		Sprite:      values["sprite"],
+		Tags:        values["tags"],
+		Link:        values["link"],
 		Pos:         ast.Position{Line: lineNum, Column: 1},
 	}, true
 }
@@ -410,14 +428,15 @@ func parseC4Style(line string, lineNum int) (ast.C4Style, bool) {
 		if len(params) < 1 {
 			return ast.C4Style{}, false
 		}
+		values := namedParams(params[1:], "bgColor", "fontColor", "borderColor", "shadowing", "shape")
 		return ast.C4Style{
 			StyleType:   "UpdateElementStyle",
 			ElementID:   params[0],
-			BgColor:     getParam(params, 1),
-			FontColor:   getParam(params, 2),
-			BorderColor: getParam(params, 3),
-			Shadowing:   getParam(params, 4),
-			Shape:       getParam(params, 5),
+			BgColor:     values["bgColor"],
+			FontColor:   values["fontColor"],
+			BorderColor: values["borderColor"],
+			Shadowing:   values["shadowing"],
+			Shape:       values["shape"],
 			Pos:         ast.Position{Line: lineNum, Column: 1},
 		}, true
 	}
@@ -428,14 +447,15 @@ func parseC4Style(line string, lineNum int) (ast.C4Style, bool) {
 		if len(params) < 2 {
 			return ast.C4Style{}, false
 		}
+		values := namedParams(params[2:], "textColor", "lineColor", "offsetX", "offsetY")
 		return ast.C4Style{
 			StyleType: "UpdateRelStyle",
 			From:      params[0],
 			To:        params[1],
-			TextColor: getParam(params, 2),
-			LineColor: getParam(params, 3),
-			OffsetX:   getParam(params, 4),
-			OffsetY:   getParam(params, 5),
+			TextColor: values["textColor"],
+			LineColor: values["lineColor"],
+			OffsetX:   values["offsetX"],
+			OffsetY:   values["offsetY"],
 			Pos:       ast.Position{Line: lineNum, Column: 1},
 		}, true
 	}
@@ -443,6 +463,44 @@ func parseC4Style(line string, lineNum int) (ast.C4Style, bool) {
 	return ast.C4Style{}, false
 }
 
+// parseC4Layout parses a layout directive.
+func parseC4Layout(line string, lineNum int) (ast.C4Layout, bool) {
+	if matches := c4LayoutConfigPattern.FindStringSubmatch(line); matches != nil {
+		values := namedParams(parseC4Parameters(matches[1]), "c4ShapeInRow", "c4BoundaryInRow")
+		return ast.C4Layout{
+			Directive:     "UpdateLayoutConfig",
+			ShapeInRow:    values["c4ShapeInRow"],
+			BoundaryInRow: values["c4BoundaryInRow"],
+			Pos:           ast.Position{Line: lineNum, Column: 1},
+		}, true
+	}
+
+	if matches := c4LayoutMacroPattern.FindStringSubmatch(line); matches != nil {
+		return ast.C4Layout{
+			Directive: matches[1],
+			Pos:       ast.Position{Line: lineNum, Column: 1},
+		}, true
+	}
+
+	return ast.C4Layout{}, false
+}
+
+// styleParams maps style parameters to their names. Parameters are either
+// positional, in the order of names, or named as in `$fontColor="red"`.
+func namedParams(params []string, names ...string) map[string]string {
+	values := make(map[string]string, len(names))
+	for i, param := range params {
+		if name, value, ok := strings.Cut(param, "="); ok && strings.HasPrefix(name, "$") {
+			values[strings.TrimSpace(name[1:])] = strings.Trim(strings.TrimSpace(value), `"`)
+			continue
+		}
+		if i < len(names) {
+			values[names[i]] = param
+		}
+	}
+	return values
+}
+
 // parseC4Parameters parses comma-separated parameters, handling quoted strings.
 func parseC4Parameters(params string) []string {
 	var result []string
@@ -481,11 +539,3 @@ func parseC4Parameters(params string) []string {
 
 	return result
 }
-
-// getParam safely gets a parameter at index, returning empty string if out of bounds.
-func getParam(params []string, index int) string {
-	if index < len(params) {
-		return params[index]
-	}
-	return ""
-}
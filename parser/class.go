@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"cmp"
 	"fmt"
 	"regexp"
 	"strings"
@@ -10,16 +11,19 @@ import (
 
 var (
 	// Class diagram patterns
-	classHeaderPattern = regexp.MustCompile(`^classDiagram\s*$`)
+	classHeaderPattern = regexp.MustCompile(`^classDiagram(?:-v2)?\s*$`)
 	classCommentPattern = regexp.MustCompile(`^%%(.*)$`)
 
 	// Class declaration patterns
-	classDeclPattern = regexp.MustCompile(`^class\s+(\w+)(?:\s*<<(.+)>>)?\s*$`)
-	classBodyStartPattern = regexp.MustCompile(`^class\s+(\w+)(?:\s*<<(.+)>>)?\s*\{\s*$`)
+	classDeclPattern = regexp.MustCompile(`^class\s+([\pL\pM\pN_]+)(?:\s*<<(.+)>>)?\s*$`)
+	classBodyStartPattern = regexp.MustCompile(`^class\s+([\pL\pM\pN_]+)(?:\s*<<(.+)>>)?\s*\{\s*$`)
 	classBodyEndPattern = regexp.MustCompile(`^\}\s*$`)
 
 	// Member patterns
-	memberPattern = regexp.MustCompile(`^([+\-#~])(\w+)(?:\(([^)]*)\))?(?:\s+(.+))?\s*$`)
+	memberPattern = regexp.MustCompile(`^([+\-#~])([\pL\pM\pN_]+)(?:\(([^)]*)\))?(?:\s+(.+))?\s*$`)
+	// memberStatementPattern matches a member declared outside the class body,
+	// as in "Animal : +int age"
+	memberStatementPattern = regexp.MustCompile(`^([\pL\pM\pN_]+)\s*:\s*(.+)$`)
 
 	// Relationship patterns
 	// Inheritance: --|>, <|--
@@ -28,12 +32,12 @@ var (
 	// Association: --, -->
 	// Dependency: .., ..>, <..
 	// Realization: ..|>, <|..
-	relationshipPattern = regexp.MustCompile(`^(\w+)\s+(?:"([^"]+)"\s+)?([<*o])?(-{2}|\.{2})([>|*o]?)\s+(?:"([^"]+)"\s+)?(\w+)(?:\s*:\s*(.+))?\s*$`)
+	relationshipPattern = regexp.MustCompile(`^([\pL\pM\pN_]+)\s+(?:"([^"]+)"\s+)?(<\||[<*o])?(-{2}|\.{2})(\|>|[>*o])?\s+(?:"([^"]+)"\s+)?([\pL\pM\pN_]+)(?:\s*:\s*(.+))?\s*$`)
 
 	// Note patterns. classNotePattern (targeted) is tried before
 	// classStandaloneNotePattern so a "note for X ..." line is never
 	// misread as a floating note.
-	classNotePattern           = regexp.MustCompile(`^note\s+for\s+(\w+)\s+"([^"]+)"\s*$`)
+	classNotePattern           = regexp.MustCompile(`^note\s+for\s+([\pL\pM\pN_]+)\s+"([^"]+)"\s*$`)
 	classStandaloneNotePattern = regexp.MustCompile(`^note\s+"([^"]+)"\s*$`)
 )
 
@@ -57,8 +61,9 @@ func (p *ClassParser) Parse(source string) (ast.Diagram, error) {
 		return nil, fmt.Errorf("empty diagram")
 	}
 
-	// Parse header
-	header := strings.TrimSpace(lines[0])
+	// Parse header, after any front matter, comments and directives
+	first := headerLine(lines)
+	header := strings.TrimSpace(lines[first])
 	if !classHeaderPattern.MatchString(header) {
 		return nil, fmt.Errorf("invalid class diagram header: expected 'classDiagram'")
 	}
@@ -70,18 +75,23 @@ func (p *ClassParser) Parse(source string) (ast.Diagram, error) {
 	}
 
 	// Parse statements
-	statements, err := p.parseStatements(lines[1:], 1)
+	statements, skipped, err := p.parseStatements(lines[first+1:], first+1)
 	if err != nil {
 		return nil, err
 	}
 	diagram.Statements = statements
+	diagram.SkippedLines = skipped
 
 	return diagram, nil
 }
 
-func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.ClassStmt, error) {
+func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.ClassStmt, []ast.SkippedLine, error) {
 	var statements []ast.ClassStmt
+	var skipped []ast.SkippedLine
 	lineNum := startLine
+	// Classes first declared by a member statement, which a later class
+	// statement completes rather than duplicates
+	implicit := make(map[string]*ast.Class)
 
 	for i := 0; i < len(lines); i++ {
 		lineNum++
@@ -113,9 +123,17 @@ func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.Clas
 			// Find closing brace
 			members, consumed, err := p.parseClassBody(lines[i+1:], lineNum+1)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
+			i += consumed
+			if class, ok := implicit[className]; ok {
+				delete(implicit, className)
+				class.Stereotype = cmp.Or(stereotype, class.Stereotype)
+				class.Members = append(class.Members, members...)
+				lineNum += consumed
+				continue
+			}
 			class := &ast.Class{
 				Name:       className,
 				Stereotype: stereotype,
@@ -124,7 +142,6 @@ func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.Clas
 			}
 			statements = append(statements, class)
 
-			i += consumed
 			lineNum += consumed
 			continue
 		}
@@ -137,6 +154,11 @@ func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.Clas
 				stereotype = matches[2]
 			}
 
+			if class, ok := implicit[className]; ok {
+				delete(implicit, className)
+				class.Stereotype = cmp.Or(stereotype, class.Stereotype)
+				continue
+			}
 			class := &ast.Class{
 				Name:       className,
 				Stereotype: stereotype,
@@ -176,6 +198,28 @@ func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.Clas
 			continue
 		}
 
+		// Handle members declared outside the class body, which declare the
+		// class too if needed
+		if matches := memberStatementPattern.FindStringSubmatch(trimmed); matches != nil {
+			member, ok := parseMember(strings.TrimSpace(matches[2]), lineNum)
+			class := findClass(statements, matches[1])
+			if class == nil {
+				class = &ast.Class{Name: matches[1], Members: []ast.ClassMember{}, Pos: ast.Position{Line: lineNum, Column: 1}}
+				implicit[class.Name] = class
+				statements = append(statements, class)
+			}
+			if ok {
+				class.Members = append(class.Members, member)
+			}
+			continue
+		}
+
+		// Handle click, link and callback interactions
+		if interaction := parseClassInteraction(trimmed, lineNum); interaction != nil {
+			statements = append(statements, interaction)
+			continue
+		}
+
 		// Handle notes
 		if matches := classNotePattern.FindStringSubmatch(trimmed); matches != nil {
 			note := &ast.ClassNote{
@@ -197,11 +241,11 @@ func (p *ClassParser) parseStatements(lines []string, startLine int) ([]ast.Clas
 			continue
 		}
 
-		// Skip lines we can't parse (for now)
-		continue
+		// Skip lines we can't parse, recording them so they can be reported
+		skipped = append(skipped, ast.SkippedLine{Line: lineNum, Content: trimmed, Reason: "not a recognised class diagram statement"})
 	}
 
-	return statements, nil
+	return statements, skipped, nil
 }
 
 func (p *ClassParser) parseClassBody(lines []string, startLine int) ([]ast.ClassMember, int, error) {
@@ -223,34 +267,7 @@ func (p *ClassParser) parseClassBody(lines []string, startLine int) ([]ast.Class
 		}
 
 		// Parse member
-		if matches := memberPattern.FindStringSubmatch(trimmed); matches != nil {
-			visibility := matches[1]
-			name := matches[2]
-			params := matches[3]
-			typ := ""
-			if len(matches) > 4 {
-				typ = matches[4]
-			}
-
-			member := ast.ClassMember{
-				Visibility: visibility,
-				Name:       name,
-				Type:       typ,
-				IsMethod:   params != "",
-				Pos:        ast.Position{Line: lineNum, Column: 1},
-			}
-
-			if params != "" {
-				// Parse parameters
-				if params != "" {
-					paramList := strings.Split(params, ",")
-					for i := range paramList {
-						paramList[i] = strings.TrimSpace(paramList[i])
-					}
-					member.Parameters = paramList
-				}
-			}
-
+		if member, ok := parseMember(trimmed, lineNum); ok {
 			members = append(members, member)
 		}
 	}
@@ -258,6 +275,49 @@ func (p *ClassParser) parseClassBody(lines []string, startLine int) ([]ast.Class
 	return nil, 0, fmt.Errorf("line %d: unclosed class body", startLine)
 }
 
+// findClass returns the class named name among statements, or nil.
+func findClass(statements []ast.ClassStmt, name string) *ast.Class {
+	for _, stmt := range statements {
+		if class, ok := stmt.(*ast.Class); ok && class.Name == name {
+			return class
+		}
+	}
+	return nil
+}
+
+// parseMember parses a class member, such as "+swim()", declared on line
+// lineNum, reporting false when text isn't one.
+func parseMember(text string, lineNum int) (ast.ClassMember, bool) {
+	matches := memberPattern.FindStringSubmatch(text)
+	if matches == nil {
+		return ast.ClassMember{}, false
+	}
+	visibility := matches[1]
+	name := matches[2]
+	params := matches[3]
+	typ := ""
+	if len(matches) > 4 {
+		typ = matches[4]
+	}
+
+	member := ast.ClassMember{
+		Visibility: visibility,
+		Name:       name,
+		Type:       typ,
+		IsMethod:   strings.HasPrefix(text[len(visibility)+len(name):], "("),
+		Pos:        ast.Position{Line: lineNum, Column: 1},
+	}
+
+	if params != "" {
+		paramList := strings.Split(params, ",")
+		for i := range paramList {
+			paramList[i] = strings.TrimSpace(paramList[i])
+		}
+		member.Parameters = paramList
+	}
+	return member, true
+}
+
 func (p *ClassParser) determineRelationshipType(left, link, right string) string {
 	// Inheritance: --|>, <|--
 	if link == "--" && (right == "|>" || left == "<|") {
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// initDirectivePattern matches a `%%{init: {...}}%%` directive line, capturing
+// the braced body.
+var initDirectivePattern = regexp.MustCompile(`^\s*%%(\{.*\})%%\s*$`)
+
+// parseInitDirective returns the configuration set by an init (or initialize)
+// directive line. Directive bodies are JSON-like and may use single quotes or
+// unquoted keys, so they are read as YAML flow mappings. It reports false for
+// lines that are not init directives or cannot be read.
+func parseInitDirective(line string) (map[string]any, bool) {
+	matches := initDirectivePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, false
+	}
+
+	var directive map[string]any
+	if err := yaml.Unmarshal([]byte(matches[1]), &directive); err != nil {
+		return nil, false
+	}
+
+	for _, key := range []string{"init", "initialize"} {
+		if config, ok := directive[key].(map[string]any); ok {
+			return config, true
+		}
+	}
+	return nil, false
+}
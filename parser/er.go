@@ -16,12 +16,14 @@ func NewERParser() *ERParser {
 	return &ERParser{}
 }
 
+// Entity names must start with an upper-case letter or underscore; letters from
+// uncased scripts (\p{Lo}, e.g. CJK) are also accepted.
 var (
 	erHeaderRegex     = regexp.MustCompile(`^erDiagram\s*(?:(TB|BT|LR|RL)\s*)?$`)
-	entityHeaderRegex = regexp.MustCompile(`^([A-Z_][A-Z0-9_-]*)\s*(?:\[([^\]]+)\])?\s*\{?\s*$`)
-	attributeRegex    = regexp.MustCompile(`^\s+([a-zA-Z][a-zA-Z0-9\-_\(\)\[\]]*)\s+([a-zA-Z_][a-zA-Z0-9_-]*|\*[a-zA-Z_][a-zA-Z0-9_-]*)\s*(?:([A-Z,]+))?\s*(?:"([^"]*)")?\s*$`)
-	relationshipRegex = regexp.MustCompile(`^([A-Z_][A-Z0-9_-]*)\s+(\|\||\|o|\}\||\}o)(--|\.\.)(\|\||\|o|o\||o\{|\|\{|\}\||\}o)\s+([A-Z_][A-Z0-9_-]*)\s*(?::\s*(.+))?$`)
-	simpleEntityRegex = regexp.MustCompile(`^([A-Z_][A-Z0-9_-]*)\s*(?:\[([^\]]+)\])?\s*$`)
+	entityHeaderRegex = regexp.MustCompile(`^([\p{Lu}\p{Lo}_][\p{Lu}\p{Lo}\pM\pN_-]*)\s*(?:\[([^\]]+)\])?\s*\{?\s*$`)
+	attributeRegex    = regexp.MustCompile(`^\s+(\pL[\pL\pM\pN\-_\(\)\[\]]*)\s+([\pL_][\pL\pM\pN_-]*|\*[\pL_][\pL\pM\pN_-]*)\s*(?:([A-Z]+(?:\s*,\s*[A-Z]+)*))?\s*(?:"([^"]*)")?\s*$`)
+	relationshipRegex = regexp.MustCompile(`^([\p{Lu}\p{Lo}_][\p{Lu}\p{Lo}\pM\pN_-]*)\s+(\|\||\|o|\}\||\}o)(--|\.\.)(\|\||\|o|o\||o\{|\|\{|\}\||\}o)\s+([\p{Lu}\p{Lo}_][\p{Lu}\p{Lo}\pM\pN_-]*)\s*(?::\s*(.+))?$`)
+	simpleEntityRegex = regexp.MustCompile(`^([\p{Lu}\p{Lo}_][\p{Lu}\p{Lo}\pM\pN_-]*)\s*(?:\[([^\]]+)\])?\s*$`)
 )
 
 // Parse parses an ER diagram source.
@@ -39,8 +41,9 @@ func (p *ERParser) Parse(source string) (ast.Diagram, error) {
 		Pos:           ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	matches := erHeaderRegex.FindStringSubmatch(firstLine)
 	if matches == nil {
 		return nil, fmt.Errorf("invalid ER diagram header: %s", firstLine)
@@ -55,7 +58,7 @@ func (p *ERParser) Parse(source string) (ast.Diagram, error) {
 	var currentEntity *ast.EREntity
 	inEntityBlock := false
 
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -5,31 +5,135 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
 
 var (
 	// Regex patterns for Mermaid syntax
-	headerPattern        = regexp.MustCompile(`^\s*(flowchart|graph)\s+(TB|TD|BT|RL|LR)\s*$`)
+	// Identifiers use [\pL\pM\pN_] rather than \w so that accented and CJK names are
+	// accepted, as they are by mermaid.js.
+	headerPattern        = regexp.MustCompile(`^\s*(flowchart-elk|flowchart|graph)(?:\s+(TB|TD|BT|RL|LR))?\s*$`)
 	commentPattern       = regexp.MustCompile(`^\s*%%(.*)$`)
-	subgraphStartPattern = regexp.MustCompile(`^\s*subgraph\s+(?:(\w+)\s*\[([^\]]+)\]|(\w+)|"([^"]+)")\s*$`)
+	subgraphStartPattern = regexp.MustCompile(`^\s*subgraph\s+(?:(` + nodeID + `)\s*\[([^\]]+)\]|(` + nodeID + `)|"([^"]+)")\s*$`)
 	subgraphEndPattern   = regexp.MustCompile(`^\s*end\s*$`)
-	classDefPattern      = regexp.MustCompile(`^\s*classDef\s+(\w+)\s+(.+)$`)
-	classAssignPattern   = regexp.MustCompile(`^\s*class\s+([\w,\s]+?)\s+(\w+)\s*$`)
+	directionPattern     = regexp.MustCompile(`^\s*direction\s+(\S+)\s*$`)
+	classDefPattern      = regexp.MustCompile(`^\s*classDef\s+([\pL\pM\pN_]+)\s+(.+)$`)
+	stylePattern         = regexp.MustCompile(`^\s*style\s+(` + nodeListPattern + `)\s+(.+)$`)
+	classAssignPattern   = regexp.MustCompile(`^\s*class\s+(` + nodeListPattern + `)\s+([\pL\pM\pN_]+)\s*$`)
+	linkStylePattern     = regexp.MustCompile(`^\s*linkStyle\s+(?:default|\d+(?:\s*,\s*\d+)*)\s+\S`)
+
+	// Node IDs may have dots, dashes and slashes between other characters, as
+	// in a.service, web-1 or src/main.go, so that diagrams generated from file
+	// paths and host names parse. A dash must be followed by a letter, digit
+	// or underscore, so A-.->B is still A, a dotted arrow and B.
+	nodeID        = `[\pL\pM\pN_]+(?:[-./][\pL\pM\pN_]+)*`
+	nodeIDPattern = regexp.MustCompile(nodeID)
+
+	// Node lists in style and class statements are separated by commas,
+	// ampersands or spaces, as in "A,B", "A & B" or "A B"
+	nodeListPattern = nodeID + `(?:\s*[,&]\s*` + nodeID + `|\s+` + nodeID + `)*`
 
 	// Node and link patterns
-	// NOTE: Order matters in alternation - longer patterns must come before shorter ones
-	nodeDefPattern = regexp.MustCompile(`^\s*(\w+)\s*(\{\{|\[\[|\(\(|\[\(|\(\[|\[|\(|\{|>)([^\])\}]*?)(\}\}|\]\]|\)\)|\)\]|\]\)|\]|\)|\})?\s*$`)
+	nodeDefPattern = regexp.MustCompile(`^\s*` + nodeWithOptDef + `\s*$`)
 
 	// Pattern to match a node reference with optional inline definition
-	// Captures: nodeID + optional (openBracket + label + closeBracket)
-	// NOTE: Order matters in alternation - longer patterns must come before shorter ones
-	nodeWithOptDef   = `(\w+)(?:\s*(\{\{|\[\[|\(\(|\[\(|\(\[|\[|\(|\{|>)([^\])\}]*?)(\}\}|\]\]|\)\)|\)\]|\]\)|\]|\)|\}))?`
-	linkPattern      = regexp.MustCompile(`^` + nodeWithOptDef + `\s*(<)?(-{2,3}|-\.{1,2}-|={2,3})(>)?\s*(\|([^|]+)\|)?\s*` + nodeWithOptDef + `$`)
-	biDirLinkPattern = regexp.MustCompile(`^` + nodeWithOptDef + `\s*(<)(--|==|-\.-)(>)\s*(\|([^|]+)\|)?\s*` + nodeWithOptDef + `$`)
+	// Captures: nodeID + optional shape (opening bracket, label and closing bracket)
+	nodeWithOptDef = `(` + nodeID + `)(?:\s*(` + nodeShapeAlternatives() + `))?`
+	// Arrows may be extended (----->, ====>, -..->) to make a link span more ranks
+	linkPattern      = regexp.MustCompile(`^` + nodeWithOptDef + `\s*(<)?(-{2,}|-\.+-|={2,}|~{3,})(>)?\s*(\|([^|]+)\|)?\s*` + nodeWithOptDef + `$`)
+	biDirLinkPattern = regexp.MustCompile(`^` + nodeWithOptDef + `\s*(<)(-{2,}|={2,}|-\.+-)(>)\s*(\|([^|]+)\|)?\s*` + nodeWithOptDef + `$`)
+	// Links with the label inside the arrow, as in A -- text --> B, A -. text .-> B or A == text ==> B
+	textLinkPattern = regexp.MustCompile(`^` + nodeWithOptDef + `\s*(--|-\.|==)\s*([^-.=>\s].*?)\s*(-{2,}>|-{3,}|\.+->?|={2,}>|={3,})\s*` + nodeWithOptDef + `$`)
 )
 
+var (
+	// chainNodePattern matches the next node of a chained or grouped
+	// statement: its ID, optional shape, optional shape data (@{ ... }) and
+	// optional class (:::name).
+	chainNodePattern = regexp.MustCompile(`^\s*(` + nodeID + `)(?:\s*(` + nodeShapeAlternatives() + `))?(?:\s*@\{[^}]*\})?(?::::([\pL\pM\pN_-]+))?`)
+	// chainAmpersandPattern matches the & between the nodes of a group.
+	chainAmpersandPattern = regexp.MustCompile(`^\s*&`)
+	// chainArrowPattern matches a link between node groups: an optional link
+	// ID (e1@), an arrow whose heads may be circles (o) or crosses (x), and
+	// an optional |label|.
+	// An arrow without a head needs three dashes or equals signs, as -- and
+	// == open a link with its label inside the arrow.
+	chainArrowPattern = regexp.MustCompile(`^\s*(?:` + nodeID + `@)?([<ox]?(?:-{2,}[>ox]|-{3,}|-\.+-[>ox]?|={2,}[>ox]|={3,}|~{3,}))\s*(?:\|([^|]*)\|)?`)
+	// chainTextArrowPattern matches a link between node groups with its label
+	// inside the arrow, as in -- text --o.
+	chainTextArrowPattern = regexp.MustCompile(`^\s*(--|-\.|==)\s+([^-.=>|\s][^|]*?)\s*(-{2,}[>ox]|-{3,}|\.+-[>ox]?|={2,}[>ox]|={3,})`)
+)
+
+// textLinkClosers matches the closing part of a link with its label inside the
+// arrow against the opening part it must be paired with, as in "-- text -->".
+var textLinkClosers = map[string]*regexp.Regexp{
+	"--": regexp.MustCompile(`^(?:-{2,}>|-{3,})$`),
+	"-.": regexp.MustCompile(`^\.+->?$`),
+	"==": regexp.MustCompile(`^(?:={2,}>|={3,})$`),
+}
+
+// linkLength returns the number of ranks a link drawn with arrow spans. Each
+// extra dash, equals sign, dot or tilde beyond the shortest arrow of its kind
+// (-->, ---, ==>, ===, -.->, ~~~) adds one.
+func linkLength(arrow string) int {
+	head := strings.HasSuffix(arrow, ">")
+	body := strings.TrimPrefix(strings.TrimSuffix(arrow, ">"), "<")
+
+	var length int
+	switch {
+	case strings.Contains(body, "."):
+		length = strings.Count(body, ".")
+	case strings.HasPrefix(body, "~"):
+		length = len(body) - 2
+	case head:
+		length = len(body) - 1
+	default:
+		length = len(body) - 2
+	}
+	return max(1, length)
+}
+
+// nodeShapes lists the brackets of each node shape.
+// NOTE: Order matters - longer brackets must come before their prefixes
+var nodeShapes = []struct{ open, close string }{
+	{"{{", "}}"},
+	{"[[", "]]"},
+	{"((", "))"},
+	{"[(", ")]"},
+	{"([", "])"},
+	{"[", "]"},
+	{"(", ")"},
+	{"{", "}"},
+	{">", "]"},
+}
+
+// nodeShapeAlternatives returns a regular expression alternation matching any
+// node shape. A label runs up to the shape's closing bracket, so it may hold
+// other brackets, as in A[Start (now)], and quoted parts may hold anything,
+// as in A["Array [0]"].
+func nodeShapeAlternatives() string {
+	alternatives := make([]string, len(nodeShapes))
+	for i, shape := range nodeShapes {
+		alternatives[i] = regexp.QuoteMeta(shape.open) +
+			`(?:"[^"]*"|[^` + regexp.QuoteMeta(shape.close[:1]) + `])*?` +
+			regexp.QuoteMeta(shape.close)
+	}
+	return strings.Join(alternatives, "|")
+}
+
+// splitNodeShape splits a node shape matched by nodeShapeAlternatives, such as
+// "((Label))", into its opening bracket, label and closing bracket.
+func splitNodeShape(shape string) (string, string, string) {
+	for _, s := range nodeShapes {
+		if len(shape) >= len(s.open)+len(s.close) && strings.HasPrefix(shape, s.open) && strings.HasSuffix(shape, s.close) {
+			return s.open, shape[len(s.open) : len(shape)-len(s.close)], s.close
+		}
+	}
+	return "", shape, ""
+}
+
 // FlowchartParser parses Mermaid flowchart and graph diagrams.
 type FlowchartParser struct {
 	// Pending NodeDefs from link parsing (from and to nodes)
@@ -37,6 +141,10 @@ type FlowchartParser struct {
 	pendingToNode   *ast.NodeDef
 	// Track which nodes have been defined to avoid duplicates
 	definedNodes map[string]bool
+	// Direction set by a `direction` statement in the subgraph currently being parsed
+	subgraphDirection string
+	// Lines not recognised as any statement
+	skipped []ast.SkippedLine
 }
 
 // SupportedTypes returns the diagram types this parser handles.
@@ -81,29 +189,57 @@ func (p *FlowchartParser) parseLines(lines []string) (*ast.Flowchart, error) {
 		return nil, fmt.Errorf("empty diagram")
 	}
 
-	// Parse header
-	header := strings.TrimSpace(lines[0])
+	// Parse header, after any front matter, comments and directives
+	first := headerLine(lines)
+	header := strings.TrimSpace(lines[first])
 	matches := headerPattern.FindStringSubmatch(header)
 	if matches == nil {
-		return nil, fmt.Errorf("invalid diagram header: expected 'flowchart' or 'graph' followed by direction")
+		return nil, fmt.Errorf("invalid diagram header: expected 'flowchart' or 'graph', optionally followed by a direction")
 	}
 
 	flowchart := &ast.Flowchart{
-		Type:      matches[1],
+		Type:      strings.TrimSuffix(matches[1], "-elk"),
 		Direction: matches[2],
 		Pos:       ast.Position{Line: 1, Column: 1},
 	}
 
 	// Parse statements
-	statements, err := p.parseStatements(lines[1:], 1, false)
+	p.skipped = nil
+	statements, err := p.parseStatements(lines[first+1:], first+1, false)
 	if err != nil {
 		return nil, err
 	}
 	flowchart.Statements = statements
+	flowchart.SkippedLines = p.skipped
 
 	return flowchart, nil
 }
 
+// linkLikePattern matches the arrows of links, to explain why a line that
+// looks like a link was skipped.
+var linkLikePattern = regexp.MustCompile(`--|==|-\.|->|~~~`)
+
+// flowchartSkippedLine records trimmed, line lineNum, as skipped.
+func flowchartSkippedLine(trimmed string, lineNum int) ast.SkippedLine {
+	reason := "not a recognised flowchart statement"
+	if linkLikePattern.MatchString(trimmed) {
+		reason = "looks like a link, but its node IDs, shapes or arrow aren't recognised"
+	}
+	return ast.SkippedLine{Line: lineNum, Content: trimmed, Reason: reason}
+}
+
+// nodeList returns the node IDs in line[start:end], a node list of a style or
+// class statement on line lineNum, with their positions.
+func nodeList(line string, start, end, lineNum int) ([]string, []ast.Position) {
+	var nodeIDs []string
+	var positions []ast.Position
+	for _, loc := range nodeIDPattern.FindAllStringIndex(line[start:end], -1) {
+		nodeIDs = append(nodeIDs, line[start+loc[0]:start+loc[1]])
+		positions = append(positions, ast.Position{Line: lineNum, Column: utf8.RuneCountInString(line[:start+loc[0]]) + 1})
+	}
+	return nodeIDs, positions
+}
+
 func (p *FlowchartParser) parseStatements(lines []string, startLine int, inSubgraph bool) ([]ast.Statement, error) {
 	var statements []ast.Statement
 	lineNum := startLine
@@ -139,12 +275,16 @@ func (p *FlowchartParser) parseStatements(lines []string, startLine int, inSubgr
 		// Handle subgraph start
 		if matches := subgraphStartPattern.FindStringSubmatch(trimmed); matches != nil {
 			// Find the matching 'end'
-			nestedLines, consumed, err := p.extractSubgraphLines(lines[i+1:], lineNum+1)
+			nestedLines, consumed, err := p.extractSubgraphLines(lines[i+1:], lineNum)
 			if err != nil {
 				return nil, err
 			}
 
+			outerDirection := p.subgraphDirection
+			p.subgraphDirection = ""
 			nestedStatements, err := p.parseStatements(nestedLines, lineNum, true)
+			direction := p.subgraphDirection
+			p.subgraphDirection = outerDirection
 			if err != nil {
 				return nil, err
 			}
@@ -168,6 +308,7 @@ func (p *FlowchartParser) parseStatements(lines []string, startLine int, inSubgr
 			statements = append(statements, &ast.Subgraph{
 				ID:         id,
 				Title:      title,
+				Direction:  direction,
 				Statements: nestedStatements,
 				Pos:        ast.Position{Line: lineNum, Column: 1},
 			})
@@ -177,6 +318,20 @@ func (p *FlowchartParser) parseStatements(lines []string, startLine int, inSubgr
 			continue
 		}
 
+		// Handle subgraph direction (only meaningful inside a subgraph)
+		if matches := directionPattern.FindStringSubmatch(trimmed); matches != nil {
+			if inSubgraph {
+				p.subgraphDirection = matches[1]
+			}
+			continue
+		}
+
+		// Handle click interactions
+		if interaction := parseClick(trimmed, lineNum); interaction != nil {
+			statements = append(statements, interaction)
+			continue
+		}
+
 		// Handle classDef
 		if matches := classDefPattern.FindStringSubmatch(trimmed); matches != nil {
 			styles := p.parseStyles(matches[2])
@@ -188,16 +343,27 @@ func (p *FlowchartParser) parseStatements(lines []string, startLine int, inSubgr
 			continue
 		}
 
-		// Handle class assignment
-		if matches := classAssignPattern.FindStringSubmatch(trimmed); matches != nil {
-			nodeIDs := strings.Split(matches[1], ",")
+		// Handle style, with one statement per node styled
+		if matches := stylePattern.FindStringSubmatchIndex(line); matches != nil {
+			nodeIDs, positions := nodeList(line, matches[2], matches[3], lineNum)
 			for i, id := range nodeIDs {
-				nodeIDs[i] = strings.TrimSpace(id)
+				statements = append(statements, &ast.Style{
+					NodeID: id,
+					Styles: p.parseStyles(line[matches[4]:matches[5]]),
+					Pos:    positions[i],
+				})
 			}
+			continue
+		}
+
+		// Handle class assignment
+		if matches := classAssignPattern.FindStringSubmatchIndex(line); matches != nil {
+			nodeIDs, positions := nodeList(line, matches[2], matches[3], lineNum)
 			statements = append(statements, &ast.ClassAssignment{
-				NodeIDs:   nodeIDs,
-				ClassName: matches[2],
-				Pos:       ast.Position{Line: lineNum, Column: 1},
+				NodeIDs:       nodeIDs,
+				NodePositions: positions,
+				ClassName:     line[matches[4]:matches[5]],
+				Pos:           ast.Position{Line: lineNum, Column: 1},
 			})
 			continue
 		}
@@ -230,8 +396,20 @@ func (p *FlowchartParser) parseStatements(lines []string, startLine int, inSubgr
 			continue
 		}
 
-		// If we can't parse the line, skip it (for now - could return error in strict mode)
-		continue
+		// Try to parse as links between several nodes, or as a node with a
+		// class or shape data
+		if chain, ok := p.parseChain(trimmed, lineNum); ok {
+			statements = append(statements, chain...)
+			continue
+		}
+
+		// Link styles only change how links are drawn
+		if linkStylePattern.MatchString(trimmed) {
+			continue
+		}
+
+		// Skip lines we can't parse, recording them so they can be reported
+		p.skipped = append(p.skipped, flowchartSkippedLine(trimmed, lineNum))
 	}
 
 	if inSubgraph {
@@ -269,19 +447,22 @@ func (p *FlowchartParser) extractSubgraphLines(lines []string, startLine int) ([
 }
 
 // extractNodeDef extracts a NodeDef from a node reference that may include an inline definition
-// e.g., "B[Label]" -> NodeDef{ID: "B", Label: "Label", Shape: "[]"}
+// e.g., "B" and "[Label]" -> NodeDef{ID: "B", Label: "Label", Shape: "[]"}
 // Returns nil if the node reference is just an ID without a definition
-func (p *FlowchartParser) extractNodeDef(nodeID, openBracket, label, closeBracket string, lineNum int) *ast.NodeDef {
+func (p *FlowchartParser) extractNodeDef(nodeID, shape string, lineNum int) *ast.NodeDef {
 	// If no brackets, it's just a node reference, not a definition
-	if openBracket == "" || closeBracket == "" {
+	if shape == "" {
 		return nil
 	}
 
+	openBracket, label, closeBracket := splitNodeShape(shape)
+	label = strings.TrimSpace(label)
 	return &ast.NodeDef{
-		ID:    nodeID,
-		Shape: openBracket + closeBracket,
-		Label: strings.TrimSpace(label),
-		Pos:   ast.Position{Line: lineNum, Column: 1},
+		ID:           nodeID,
+		Shape:        openBracket + closeBracket,
+		Label:        label,
+		DisplayLabel: ast.DecodeText(label),
+		Pos:          ast.Position{Line: lineNum, Column: 1},
 	}
 }
 
@@ -292,139 +473,252 @@ func (p *FlowchartParser) parseLink(line string, lineNum int) ast.Statement {
 
 	// Try bidirectional link first
 	if matches := biDirLinkPattern.FindStringSubmatch(line); matches != nil {
-		// Updated match groups with inline node definitions:
+		// Match groups with inline node definitions:
 		// 1: from ID
-		// 2: from open bracket (optional)
-		// 3: from label (optional)
-		// 4: from close bracket (optional)
-		// 5: left arrow part <
-		// 6: arrow middle (-->, ===, ---)
-		// 7: right arrow part >
-		// 8: link label with pipes (optional)
-		// 9: link label content (optional)
-		// 10: to ID
-		// 11: to open bracket (optional)
-		// 12: to label (optional)
-		// 13: to close bracket (optional)
+		// 2: from shape (optional)
+		// 3: left arrow part <
+		// 4: arrow middle (-->, ===, ---)
+		// 5: right arrow part >
+		// 6: link label with pipes (optional)
+		// 7: link label content (optional)
+		// 8: to ID
+		// 9: to shape (optional)
 
 		fromID := matches[1]
-		toID := matches[10]
-
-		// Extract inline NodeDefs if present and not already defined
-		if !p.definedNodes[fromID] {
-			p.pendingFromNode = p.extractNodeDef(matches[1], matches[2], matches[3], matches[4], lineNum)
-			if p.pendingFromNode != nil {
-				p.definedNodes[fromID] = true
-			}
-		}
-		if !p.definedNodes[toID] {
-			p.pendingToNode = p.extractNodeDef(matches[10], matches[11], matches[12], matches[13], lineNum)
-			if p.pendingToNode != nil {
-				p.definedNodes[toID] = true
-			}
-		}
-
-		label := ""
-		if len(matches) > 9 && matches[9] != "" {
-			label = strings.TrimSpace(matches[9])
-		}
+		toID := matches[8]
+		p.setPendingNodes(fromID, matches[2], toID, matches[9], lineNum)
 
 		return &ast.Link{
-			From:  fromID,
-			To:    toID,
-			Arrow: matches[5] + matches[6] + matches[7], // <-->
-			Label: label,
-			BiDir: true,
-			Pos:   ast.Position{Line: lineNum, Column: 1},
+			From:   fromID,
+			To:     toID,
+			Arrow:  matches[3] + matches[4] + matches[5], // <-->
+			Label:  strings.TrimSpace(matches[7]),
+			BiDir:  true,
+			Length: linkLength(matches[4] + matches[5]),
+			Pos:    ast.Position{Line: lineNum, Column: 1},
 		}
 	}
 
 	// Try unidirectional link
 	if matches := linkPattern.FindStringSubmatch(line); matches != nil {
-		// Updated match groups with inline node definitions:
+		// Match groups with inline node definitions:
 		// 1: from ID
-		// 2: from open bracket (optional)
-		// 3: from label (optional)
-		// 4: from close bracket (optional)
-		// 5: left arrow part < (optional)
-		// 6: arrow middle (--, ---, -.-, etc.)
-		// 7: right arrow part > (optional)
-		// 8: link label with pipes (optional)
-		// 9: link label content (optional)
-		// 10: to ID
-		// 11: to open bracket (optional)
-		// 12: to label (optional)
-		// 13: to close bracket (optional)
+		// 2: from shape (optional)
+		// 3: left arrow part < (optional)
+		// 4: arrow middle (--, ---, -.-, ~~~, etc.)
+		// 5: right arrow part > (optional)
+		// 6: link label with pipes (optional)
+		// 7: link label content (optional)
+		// 8: to ID
+		// 9: to shape (optional)
 
 		fromID := matches[1]
-		toID := matches[10]
-
-		// Extract inline NodeDefs if present and not already defined
-		if !p.definedNodes[fromID] {
-			p.pendingFromNode = p.extractNodeDef(matches[1], matches[2], matches[3], matches[4], lineNum)
-			if p.pendingFromNode != nil {
-				p.definedNodes[fromID] = true
-			}
-		}
-		if !p.definedNodes[toID] {
-			p.pendingToNode = p.extractNodeDef(matches[10], matches[11], matches[12], matches[13], lineNum)
-			if p.pendingToNode != nil {
-				p.definedNodes[toID] = true
-			}
-		}
+		toID := matches[8]
+		p.setPendingNodes(fromID, matches[2], toID, matches[9], lineNum)
 
-		arrow := matches[6]
-		if matches[5] == "<" {
+		arrow := matches[4]
+		if matches[3] == "<" {
 			arrow = "<" + arrow
 		}
-		if matches[7] == ">" {
+		if matches[5] == ">" {
 			arrow += ">"
 		}
 
-		label := ""
-		if len(matches) > 9 && matches[9] != "" {
-			label = strings.TrimSpace(matches[9])
+		return &ast.Link{
+			From:   fromID,
+			To:     toID,
+			Arrow:  arrow,
+			Label:  strings.TrimSpace(matches[7]),
+			BiDir:  false,
+			Length: linkLength(arrow),
+			Pos:    ast.Position{Line: lineNum, Column: 1},
 		}
+	}
+
+	// Try a link with its label inside the arrow
+	if matches := textLinkPattern.FindStringSubmatch(line); matches != nil {
+		// Match groups:
+		// 1: from ID
+		// 2: from shape (optional)
+		// 3: opening part of the arrow (--, -. or ==)
+		// 4: link label
+		// 5: closing part of the arrow (-->, ---, .->, .-, ==>, === or longer)
+		// 6: to ID
+		// 7: to shape (optional)
+		closer, ok := textLinkClosers[matches[3]]
+		if !ok || !closer.MatchString(matches[5]) {
+			return nil
+		}
+		arrow := matches[5]
+		if matches[3] == "-." {
+			arrow = "-" + arrow // -. text .-> is written -.-> with a |label|
+		}
+
+		fromID := matches[1]
+		toID := matches[6]
+		p.setPendingNodes(fromID, matches[2], toID, matches[7], lineNum)
 
 		return &ast.Link{
-			From:  fromID,
-			To:    toID,
-			Arrow: arrow,
-			Label: label,
-			BiDir: false,
-			Pos:   ast.Position{Line: lineNum, Column: 1},
+			From:   fromID,
+			To:     toID,
+			Arrow:  arrow,
+			Label:  strings.TrimSpace(matches[4]),
+			Length: linkLength(arrow),
+			Pos:    ast.Position{Line: lineNum, Column: 1},
 		}
 	}
 
 	return nil
 }
 
-func (p *FlowchartParser) parseNodeDef(line string, lineNum int) ast.Statement {
-	matches := nodeDefPattern.FindStringSubmatch(line)
-	if matches == nil {
-		return nil
+// setPendingNodes records the inline definitions of a link's endpoints, for
+// nodes not already defined, to be added around the Link statement.
+func (p *FlowchartParser) setPendingNodes(fromID, fromShape, toID, toShape string, lineNum int) {
+	if !p.definedNodes[fromID] {
+		p.pendingFromNode = p.extractNodeDef(fromID, fromShape, lineNum)
+		if p.pendingFromNode != nil {
+			p.definedNodes[fromID] = true
+		}
 	}
+	if !p.definedNodes[toID] {
+		p.pendingToNode = p.extractNodeDef(toID, toShape, lineNum)
+		if p.pendingToNode != nil {
+			p.definedNodes[toID] = true
+		}
+	}
+}
+
+// chainNode is a node of a chained or grouped statement.
+type chainNode struct {
+	id, shape, class string
+}
 
-	id := matches[1]
-	shape := ""
-	label := ""
+// parseChain parses a statement joining groups of nodes, separated by &, with
+// links, as in A --> B --> C, A & B --> C or A:::done --o B. Each link joins
+// every node of one group to every node of the next, and each :::class becomes
+// a class assignment. Shape data (@{ ... }) isn't kept. It reports false for
+// lines that aren't such a statement.
+func (p *FlowchartParser) parseChain(line string, lineNum int) ([]ast.Statement, bool) {
+	var groups [][]chainNode
+	var links []ast.Link
+	rest := line
+	for {
+		var group []chainNode
+		for {
+			matches := chainNodePattern.FindStringSubmatch(rest)
+			if matches == nil {
+				return nil, false
+			}
+			group = append(group, chainNode{id: matches[1], shape: matches[2], class: matches[3]})
+			rest = rest[len(matches[0]):]
+			ampersand := chainAmpersandPattern.FindString(rest)
+			if ampersand == "" {
+				break
+			}
+			rest = rest[len(ampersand):]
+		}
+		groups = append(groups, group)
+		if strings.TrimSpace(rest) == "" {
+			break
+		}
+		link, consumed, ok := parseChainArrow(rest)
+		if !ok {
+			return nil, false
+		}
+		links = append(links, link)
+		rest = rest[consumed:]
+	}
 
-	if len(matches) > 2 && matches[2] != "" {
-		// Shape is opening + closing brackets
-		shape = matches[2]
-		if len(matches) > 4 && matches[4] != "" {
-			shape += matches[4]
+	pos := ast.Position{Line: lineNum, Column: 1}
+	var statements []ast.Statement
+	define := func(group []chainNode) {
+		for _, node := range group {
+			if !p.definedNodes[node.id] {
+				if def := p.extractNodeDef(node.id, node.shape, lineNum); def != nil {
+					p.definedNodes[node.id] = true
+					statements = append(statements, def)
+				}
+			}
+			if node.class != "" {
+				statements = append(statements, &ast.ClassAssignment{NodeIDs: []string{node.id}, ClassName: node.class, Pos: pos})
+			}
 		}
-		if len(matches) > 3 {
-			label = strings.TrimSpace(matches[3])
+	}
+	define(groups[0])
+	for i, link := range links {
+		for _, from := range groups[i] {
+			for _, to := range groups[i+1] {
+				statements = append(statements, &ast.Link{
+					From:   from.id,
+					To:     to.id,
+					Arrow:  link.Arrow,
+					Label:  link.Label,
+					BiDir:  link.BiDir,
+					Length: link.Length,
+					Pos:    pos,
+				})
+			}
+		}
+		define(groups[i+1])
+	}
+	return statements, true
+}
+
+// parseChainArrow parses the link at the start of rest, returning it without
+// its endpoints, and the length of rest it takes up.
+func parseChainArrow(rest string) (ast.Link, int, bool) {
+	if matches := chainTextArrowPattern.FindStringSubmatch(rest); matches != nil {
+		closer, ok := textLinkClosers[matches[1]]
+		if ok && closer.MatchString(plainArrowHeads(matches[3])) {
+			arrow := matches[3]
+			if matches[1] == "-." {
+				arrow = "-" + arrow
+			}
+			return ast.Link{
+				Arrow:  arrow,
+				Label:  strings.TrimSpace(matches[2]),
+				Length: linkLength(plainArrowHeads(arrow)),
+			}, len(matches[0]), true
 		}
 	}
+	if matches := chainArrowPattern.FindStringSubmatch(rest); matches != nil {
+		arrow := plainArrowHeads(matches[1])
+		return ast.Link{
+			Arrow:  matches[1],
+			Label:  strings.TrimSpace(matches[2]),
+			BiDir:  strings.HasPrefix(arrow, "<") && strings.HasSuffix(arrow, ">"),
+			Length: linkLength(arrow),
+		}, len(matches[0]), true
+	}
+	return ast.Link{}, 0, false
+}
+
+// plainArrowHeads returns arrow with circle (o) and cross (x) heads written
+// as < and >.
+func plainArrowHeads(arrow string) string {
+	if strings.HasPrefix(arrow, "o") || strings.HasPrefix(arrow, "x") {
+		arrow = "<" + arrow[1:]
+	}
+	if strings.HasSuffix(arrow, "o") || strings.HasSuffix(arrow, "x") {
+		arrow = arrow[:len(arrow)-1] + ">"
+	}
+	return arrow
+}
+
+func (p *FlowchartParser) parseNodeDef(line string, lineNum int) ast.Statement {
+	matches := nodeDefPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
 
+	openBracket, label, closeBracket := splitNodeShape(matches[2])
+	label = strings.TrimSpace(label)
 	return &ast.NodeDef{
-		ID:    id,
-		Shape: shape,
-		Label: label,
-		Pos:   ast.Position{Line: lineNum, Column: 1},
+		ID:           matches[1],
+		Shape:        openBracket + closeBracket,
+		Label:        label,
+		DisplayLabel: ast.DecodeText(label),
+		Pos:          ast.Position{Line: lineNum, Column: 1},
 	}
 }
 
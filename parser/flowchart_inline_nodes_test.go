@@ -127,6 +127,44 @@ func TestInlineNodeDefinitions(t *testing.T) {
 				&ast.NodeDef{ID: "B", Label: "Important", Shape: "[]"},
 			},
 		},
+		{
+			name: "decision and database shapes",
+			source: `flowchart TD
+    A[Start] --> B{Decision}
+    B -->|yes| C[(Database)]
+    C --> D{{Prepare}}`,
+			expected: []ast.Statement{
+				&ast.NodeDef{ID: "A", Label: "Start", Shape: "[]"},
+				&ast.Link{From: "A", To: "B", Arrow: "-->"},
+				&ast.NodeDef{ID: "B", Label: "Decision", Shape: "{}"},
+				&ast.Link{From: "B", To: "C", Arrow: "-->", Label: "yes"},
+				&ast.NodeDef{ID: "C", Label: "Database", Shape: "[()]"},
+				&ast.Link{From: "C", To: "D", Arrow: "-->"},
+				&ast.NodeDef{ID: "D", Label: "Prepare", Shape: "{{}}"},
+			},
+		},
+		{
+			name: "labels containing other brackets",
+			source: `flowchart TD
+    A[Start (now)] --> B("Array [0]")
+    C{Ready?}`,
+			expected: []ast.Statement{
+				&ast.NodeDef{ID: "A", Label: "Start (now)", Shape: "[]"},
+				&ast.Link{From: "A", To: "B", Arrow: "-->"},
+				&ast.NodeDef{ID: "B", Label: `"Array [0]"`, Shape: "()"},
+				&ast.NodeDef{ID: "C", Label: "Ready?", Shape: "{}"},
+			},
+		},
+		{
+			name: "quoted label containing the closing bracket",
+			source: `flowchart LR
+    A["Has ] inside"] --> B>Flag]`,
+			expected: []ast.Statement{
+				&ast.NodeDef{ID: "A", Label: `"Has ] inside"`, Shape: "[]"},
+				&ast.Link{From: "A", To: "B", Arrow: "-->"},
+				&ast.NodeDef{ID: "B", Label: "Flag", Shape: ">]"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -43,8 +43,9 @@ func (p *GanttParser) Parse(source string) (ast.Diagram, error) {
 		Pos:        ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if !ganttHeaderRegex.MatchString(firstLine) {
 		return nil, fmt.Errorf("invalid gantt diagram header: %s", firstLine)
 	}
@@ -53,7 +54,7 @@ func (p *GanttParser) Parse(source string) (ast.Diagram, error) {
 	hasContent := false
 
 	// Parse subsequent lines
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -86,6 +87,7 @@ func (p *GanttParser) Parse(source string) (ast.Diagram, error) {
 		// Check for excludes
 		if matches := ganttExcludesRegex.FindStringSubmatch(trimmed); matches != nil {
 			diagram.Excludes = strings.TrimSpace(matches[1])
+			diagram.ExcludesPos = ast.Position{Line: i + 1, Column: 1}
 			hasContent = true
 			continue
 		}
@@ -21,9 +21,9 @@ var (
 	gitGraphHeaderRegex   = regexp.MustCompile(`^gitGraph\s*$`)
 	gitGraphThemeRegex    = regexp.MustCompile(`^\s*%%\{init:\s*\{\s*'theme'\s*:\s*'([^']+)'\s*\}\s*\}%%\s*$`)
 	gitGraphCommitRegex   = regexp.MustCompile(`^\s*commit(?:\s+id:\s*"([^"]+)")?(?:\s+tag:\s*"([^"]+)")?(?:\s+type:\s*(NORMAL|REVERSE|HIGHLIGHT))?\s*$`)
-	gitGraphBranchRegex   = regexp.MustCompile(`^\s*branch\s+([\w-]+)(?:\s+order:\s*(\d+))?\s*$`)
-	gitGraphCheckoutRegex = regexp.MustCompile(`^\s*checkout\s+([\w-]+)\s*$`)
-	gitGraphMergeRegex    = regexp.MustCompile(`^\s*merge\s+([\w-]+)(?:\s+id:\s*"([^"]+)")?(?:\s+tag:\s*"([^"]+)")?(?:\s+type:\s*(NORMAL|REVERSE|HIGHLIGHT))?\s*$`)
+	gitGraphBranchRegex   = regexp.MustCompile(`^\s*branch\s+([\pL\pM\pN_-]+)(?:\s+order:\s*(\d+))?\s*$`)
+	gitGraphCheckoutRegex = regexp.MustCompile(`^\s*checkout\s+([\pL\pM\pN_-]+)\s*$`)
+	gitGraphMergeRegex    = regexp.MustCompile(`^\s*merge\s+([\pL\pM\pN_-]+)(?:\s+id:\s*"([^"]+)")?(?:\s+tag:\s*"([^"]+)")?(?:\s+type:\s*(NORMAL|REVERSE|HIGHLIGHT))?\s*$`)
 	gitGraphCherryRegex   = regexp.MustCompile(`^\s*cherry-pick\s+id:\s*"([^"]+)"(?:\s+tag:\s*"([^"]+)")?\s*$`)
 	gitGraphOptionRegex   = regexp.MustCompile(`^\s*(mainBranchName|mainBranchOrder)\s*:\s*(.+)\s*$`)
 )
@@ -42,9 +42,9 @@ func (p *GitGraphParser) Parse(source string) (ast.Diagram, error) {
 		Pos:        ast.Position{Line: 1, Column: 1},
 	}
 
-	// Find header line, skipping config comments
+	// Find header line, skipping front matter and config comments
 	headerIdx := -1
-	for i := range lines {
+	for i := frontMatterEnd(lines); i < len(lines); i++ {
 		trimmed := strings.TrimSpace(lines[i])
 		// Skip empty lines
 		if trimmed == "" {
@@ -0,0 +1,311 @@
+package parser
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// Range is a span of diagram source from Start up to, but not including, End.
+// Lines and columns are 1-indexed and columns count characters, so
+// {Line: 3, Column: 1} is the start of the third line.
+type Range struct {
+	Start ast.Position
+	End   ast.Position
+}
+
+// Result is a parsed diagram together with its source, kept by editors between
+// calls to ParseIncremental.
+type Result struct {
+	Source      string      // Diagram source, with newlines normalised
+	Diagram     ast.Diagram // Parsed diagram, nil when Err is set
+	Err         error       // Parse error, if any
+	Incremental bool        // Whether statements of the previous AST were reused
+}
+
+// NewResult parses source from scratch, as the starting point for
+// ParseIncremental.
+func NewResult(source string) *Result {
+	source = inpututil.NormaliseNewlines(source)
+	diagram, err := Parse(source)
+	return &Result{Source: source, Diagram: diagram, Err: err}
+}
+
+// ParseIncremental replaces the text covered by edit in prev.Source with
+// newText and parses the result. When prev is a flowchart and the edit only
+// touches ordinary statement lines (not the header, subgraph boundaries,
+// direction statements or comments), only the edited lines are parsed again:
+// statements before the edit are reused as they are, and those after it are
+// reused or, when the edit adds or removes lines, copied with their lines
+// moved. Every other edit is parsed from scratch. Either way the diagram is
+// the one Parse would return for the new source.
+//
+// Parse errors are reported through Result.Err so that later edits can still
+// be applied to the returned Result; the error returned is for an edit that
+// lies outside prev.Source.
+func ParseIncremental(prev *Result, edit Range, newText string) (*Result, error) {
+	if prev == nil {
+		return nil, fmt.Errorf("no previous result to apply the edit to")
+	}
+	start, ok := sourceOffset(prev.Source, edit.Start)
+	if !ok {
+		return nil, fmt.Errorf("edit start %d:%d is outside the source", edit.Start.Line, edit.Start.Column)
+	}
+	end, ok := sourceOffset(prev.Source, edit.End)
+	if !ok || end < start {
+		return nil, fmt.Errorf("edit end %d:%d is outside the source or before its start", edit.End.Line, edit.End.Column)
+	}
+
+	newText = inpututil.NormaliseNewlines(newText)
+	source := prev.Source[:start] + newText + prev.Source[end:]
+
+	// Lines first to last of the previous source become lines first to newLast
+	first, last := edit.Start.Line, edit.End.Line
+	newLast := first + strings.Count(newText, "\n")
+	if edit.Start.Column == 1 && edit.End.Column == 1 && (newText == "" || strings.HasSuffix(newText, "\n")) {
+		// Whole lines were replaced, leaving the line the edit ends on as it was
+		last--
+		newLast--
+	}
+
+	if flowchart, ok := prev.Diagram.(*ast.Flowchart); ok && prev.Err == nil {
+		if updated, ok := reparseFlowchart(flowchart, prev.Source, source, first, last, newLast); ok {
+			return &Result{Source: source, Diagram: updated, Incremental: true}, nil
+		}
+	}
+	return NewResult(source), nil
+}
+
+// sourceOffset returns the byte offset of pos in source.
+func sourceOffset(source string, pos ast.Position) (int, bool) {
+	if pos.Line < 1 || pos.Column < 1 {
+		return 0, false
+	}
+	offset := 0
+	for line := 1; line < pos.Line; line++ {
+		newline := strings.IndexByte(source[offset:], '\n')
+		if newline < 0 {
+			return 0, false
+		}
+		offset += newline + 1
+	}
+
+	text := source[offset:]
+	if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+		text = text[:newline]
+	}
+	column := 1
+	for i := range text {
+		if column == pos.Column {
+			return offset + i, true
+		}
+		column++
+	}
+	if column == pos.Column {
+		return offset + len(text), true
+	}
+	return 0, false
+}
+
+// reparseFlowchart parses lines first to newLast of newSource, which replaced
+// lines first to last of oldSource, and splices the statements found into a
+// copy of flowchart. It reports false when the edit touches a line that
+// affects more than its own statements, or changes which nodes the edited
+// lines define when later lines mention them: later lines define a node
+// inline only when no earlier line has, so they might no longer parse as they
+// did.
+func reparseFlowchart(flowchart *ast.Flowchart, oldSource, newSource string, first, last, newLast int) (*ast.Flowchart, bool) {
+	oldLines := strings.Split(oldSource, "\n")
+	newLines := strings.Split(newSource, "\n")
+	if first < 2 || !headerPattern.MatchString(oldLines[0]) || last > len(oldLines) || newLast > len(newLines) {
+		return nil, false
+	}
+	if !plainStatementLines(oldLines[first-1:last]) || !plainStatementLines(newLines[first-1:newLast]) {
+		return nil, false
+	}
+
+	// Nodes defined before the edit aren't defined again by the edited lines
+	p := NewFlowchartParser()
+	nodeDefsBetween(flowchart.Statements, 1, first-1, p.definedNodes)
+	defined := maps.Clone(p.definedNodes)
+	replacement, err := p.parseStatements(newLines[first-1:newLast], first-1, false)
+	if err != nil {
+		return nil, false
+	}
+	oldDefined := make(map[string]bool)
+	nodeDefsBetween(flowchart.Statements, first, last, oldDefined)
+	newDefined := make(map[string]bool)
+	nodeDefsBetween(replacement, first, newLast, newDefined)
+	later := make(map[string]bool)
+	for _, line := range newLines[newLast:] {
+		for _, id := range nodeIDPattern.FindAllString(line, -1) {
+			later[id] = true
+		}
+	}
+	for id := range later {
+		if !defined[id] && oldDefined[id] != newDefined[id] {
+			return nil, false
+		}
+	}
+
+	updated := *flowchart
+	updated.Source = newSource
+	updated.Statements = spliceStatements(flowchart.Statements, enclosingSubgraphs(oldLines, first), first, last, newLast-last, replacement)
+	updated.SkippedLines = spliceSkippedLines(flowchart.SkippedLines, first, last, newLast-last, p.skipped)
+	return &updated, true
+}
+
+// spliceSkippedLines returns skipped with those on lines first to last
+// replaced by replacement, and those after them moved by delta lines.
+func spliceSkippedLines(skipped []ast.SkippedLine, first, last, delta int, replacement []ast.SkippedLine) []ast.SkippedLine {
+	var spliced []ast.SkippedLine
+	for _, line := range skipped {
+		switch {
+		case line.Line < first:
+			spliced = append(spliced, line)
+		case line.Line > last:
+			line.Line += delta
+			spliced = append(spliced, line)
+		}
+	}
+	spliced = append(spliced, replacement...)
+	slices.SortStableFunc(spliced, func(a, b ast.SkippedLine) int { return a.Line - b.Line })
+	return spliced
+}
+
+// nodeDefsBetween adds the IDs of the nodes defined on lines first to last of
+// statements, including those in subgraphs, to ids.
+func nodeDefsBetween(statements []ast.Statement, first, last int, ids map[string]bool) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			if s.Pos.Line >= first && s.Pos.Line <= last {
+				ids[s.ID] = true
+			}
+		case *ast.Subgraph:
+			nodeDefsBetween(s.Statements, first, last, ids)
+		}
+	}
+}
+
+// plainStatementLines reports whether each of lines holds only ordinary
+// statements, which don't open or close blocks or change the subgraph's
+// direction; they may still depend on the nodes defined before them.
+func plainStatementLines(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		word, _, _ := strings.Cut(trimmed, " ")
+		if word == "subgraph" || word == "end" || word == "direction" || strings.Contains(trimmed, "%%") {
+			return false
+		}
+	}
+	return true
+}
+
+// enclosingSubgraphs returns the lines opening the subgraphs that enclose the
+// given line, outermost first.
+func enclosingSubgraphs(lines []string, line int) []int {
+	var stack []int
+	for i := 1; i < line-1; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case subgraphStartPattern.MatchString(trimmed):
+			stack = append(stack, i+1)
+		case subgraphEndPattern.MatchString(trimmed) && len(stack) > 0:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return stack
+}
+
+// spliceStatements returns statements with those on lines first to last of
+// the subgraph opened on the lines in path (or of statements itself, when path
+// is empty) replaced by replacement, and the statements after them moved by
+// delta lines. Statements are copied only when they change.
+func spliceStatements(statements []ast.Statement, path []int, first, last, delta int, replacement []ast.Statement) []ast.Statement {
+	spliced := make([]ast.Statement, 0, len(statements)+len(replacement))
+	inserted := len(path) > 0
+	for _, stmt := range statements {
+		line := stmt.GetPosition().Line
+		if subgraph, ok := stmt.(*ast.Subgraph); ok && len(path) > 0 && line == path[0] {
+			copied := *subgraph
+			copied.Statements = spliceStatements(subgraph.Statements, path[1:], first, last, delta, replacement)
+			spliced = append(spliced, &copied)
+			continue
+		}
+		if len(path) == 0 && line >= first && line <= last {
+			continue
+		}
+		if line > last {
+			if !inserted {
+				spliced = append(spliced, replacement...)
+				inserted = true
+			}
+			stmt = moveStatement(stmt, delta)
+		}
+		spliced = append(spliced, stmt)
+	}
+	if !inserted {
+		spliced = append(spliced, replacement...)
+	}
+	if len(spliced) == 0 {
+		return nil // As parsing an empty block does
+	}
+	return spliced
+}
+
+// moveStatement returns stmt moved down by delta lines, copying it rather than
+// changing the previous AST.
+func moveStatement(stmt ast.Statement, delta int) ast.Statement {
+	if delta == 0 {
+		return stmt
+	}
+	switch s := stmt.(type) {
+	case *ast.NodeDef:
+		copied := *s
+		copied.Pos.Line += delta
+		return &copied
+	case *ast.Link:
+		copied := *s
+		copied.Pos.Line += delta
+		return &copied
+	case *ast.Subgraph:
+		copied := *s
+		copied.Pos.Line += delta
+		copied.Statements = make([]ast.Statement, len(s.Statements))
+		for i, nested := range s.Statements {
+			copied.Statements[i] = moveStatement(nested, delta)
+		}
+		return &copied
+	case *ast.ClassDef:
+		copied := *s
+		copied.Pos.Line += delta
+		return &copied
+	case *ast.Style:
+		copied := *s
+		copied.Pos.Line += delta
+		return &copied
+	case *ast.ClassAssignment:
+		copied := *s
+		copied.Pos.Line += delta
+		copied.NodePositions = slices.Clone(s.NodePositions)
+		for i := range copied.NodePositions {
+			copied.NodePositions[i].Line += delta
+		}
+		return &copied
+	case *ast.Comment:
+		copied := *s
+		copied.Pos.Line += delta
+		return &copied
+	case *ast.Interaction:
+		copied := *s
+		copied.Pos.Line += delta
+		return &copied
+	default:
+		return stmt
+	}
+}
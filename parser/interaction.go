@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"regexp"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+var (
+	// clickPattern splits a click statement into its node ID and the remaining arguments.
+	clickPattern = regexp.MustCompile(`^\s*click\s+(` + nodeID + `)\s+(.+?)\s*$`)
+
+	// Argument forms accepted after `click <id>`:
+	//   href "url" ["tooltip"] [_target]
+	//   "url" ["tooltip"] [_target]
+	//   call fn(args) ["tooltip"]
+	//   fn ["tooltip"]
+	clickHrefPattern     = regexp.MustCompile(`^(?:href\s+)?"([^"]*)"(?:\s+"([^"]*)")?(?:\s+(_[a-z]+))?$`)
+	clickCallPattern     = regexp.MustCompile(`^call\s+([\pL\pM\pN_$.]+)\s*\(([^)]*)\)(?:\s+"([^"]*)")?$`)
+	clickCallbackPattern = regexp.MustCompile(`^([\pL\pM\pN_$.]+)(?:\s+"([^"]*)")?$`)
+
+	// Class diagram shorthand forms.
+	classLinkPattern     = regexp.MustCompile(`^\s*link\s+([\pL\pM\pN_]+)\s+"([^"]*)"(?:\s+"([^"]*)")?(?:\s+(_[a-z]+))?\s*$`)
+	classCallbackPattern = regexp.MustCompile(`^\s*callback\s+([\pL\pM\pN_]+)\s+"([^"]*)"(?:\s+"([^"]*)")?\s*$`)
+)
+
+// parseClick parses a `click` statement, returning nil if line is not one.
+func parseClick(line string, lineNum int) *ast.Interaction {
+	matches := clickPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+
+	interaction := &ast.Interaction{
+		NodeID: matches[1],
+		Pos:    ast.Position{Line: lineNum, Column: 1},
+	}
+	args := matches[2]
+
+	switch {
+	case clickHrefPattern.MatchString(args):
+		m := clickHrefPattern.FindStringSubmatch(args)
+		interaction.Kind = "href"
+		interaction.URL = m[1]
+		interaction.Tooltip = m[2]
+		interaction.Target = m[3]
+	case clickCallPattern.MatchString(args):
+		m := clickCallPattern.FindStringSubmatch(args)
+		interaction.Kind = "callback"
+		interaction.Callback = m[1]
+		interaction.Args = m[2]
+		interaction.Tooltip = m[3]
+	case clickCallbackPattern.MatchString(args):
+		m := clickCallbackPattern.FindStringSubmatch(args)
+		interaction.Kind = "callback"
+		interaction.Callback = m[1]
+		interaction.Tooltip = m[2]
+	default:
+		return nil
+	}
+
+	return interaction
+}
+
+// parseClassInteraction parses the class diagram `click`, `link` and `callback`
+// statements, returning nil if line is none of them.
+func parseClassInteraction(line string, lineNum int) *ast.Interaction {
+	if interaction := parseClick(line, lineNum); interaction != nil {
+		return interaction
+	}
+
+	if m := classLinkPattern.FindStringSubmatch(line); m != nil {
+		return &ast.Interaction{
+			NodeID:  m[1],
+			Kind:    "href",
+			URL:     m[2],
+			Tooltip: m[3],
+			Target:  m[4],
+			Pos:     ast.Position{Line: lineNum, Column: 1},
+		}
+	}
+
+	if m := classCallbackPattern.FindStringSubmatch(line); m != nil {
+		return &ast.Interaction{
+			NodeID:   m[1],
+			Kind:     "callback",
+			Callback: m[2],
+			Tooltip:  m[3],
+			Pos:      ast.Position{Line: lineNum, Column: 1},
+		}
+	}
+
+	return nil
+}
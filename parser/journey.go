@@ -38,8 +38,9 @@ func (p *JourneyParser) Parse(source string) (ast.Diagram, error) {
 		Pos:      ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if !journeyHeaderRegex.MatchString(firstLine) {
 		return nil, fmt.Errorf("invalid journey diagram header: %s", firstLine)
 	}
@@ -48,7 +49,7 @@ func (p *JourneyParser) Parse(source string) (ast.Diagram, error) {
 	hasContent := false
 
 	// Parse subsequent lines
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -139,9 +140,29 @@ func (p *JourneyParser) Parse(source string) (ast.Diagram, error) {
 		return nil, fmt.Errorf("journey diagram must have at least a title or one section with tasks")
 	}
 
+	diagram.Actors = journeyActors(diagram.Sections)
+
 	return diagram, nil
 }
 
+// journeyActors returns the actors named by tasks, deduplicated in order of
+// first appearance.
+func journeyActors(sections []ast.Section) []string {
+	actors := []string{}
+	seen := make(map[string]bool)
+	for _, section := range sections {
+		for _, task := range section.Tasks {
+			for _, actor := range task.Actors {
+				if !seen[actor] {
+					seen[actor] = true
+					actors = append(actors, actor)
+				}
+			}
+		}
+	}
+	return actors
+}
+
 // SupportedTypes returns the diagram types this parser supports.
 func (p *JourneyParser) SupportedTypes() []string {
 	return []string{"journey"}
@@ -0,0 +1,262 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// ErrLimitExceeded is returned (wrapped) by ParseWithLimits when the source
+// exceeds one of the configured Limits. Use errors.Is to detect it.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bounds the resources a single parse may consume. It is intended for
+// services that validate untrusted, user-supplied diagrams. A zero value for any
+// field means that dimension is unlimited.
+type Limits struct {
+	MaxBytes int // Maximum size of the source in bytes
+	MaxLines int // Maximum number of source lines
+	MaxDepth int // Maximum nesting depth of blocks (subgraphs, loops, boundaries, mindmap levels)
+	MaxNodes int // Maximum number of AST nodes (statements, elements, entries) in the parsed diagram
+}
+
+// DefaultLimits returns conservative limits suitable for untrusted input.
+// They comfortably accommodate hand-written documentation diagrams.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBytes: 1 << 20, // 1 MiB
+		MaxLines: 10000,
+		MaxDepth: 50,
+		MaxNodes: 5000,
+	}
+}
+
+// ParseWithLimits parses source like Parse, but first rejects input that is too
+// large or too deeply nested, and afterwards rejects diagrams with too many nodes.
+// Size and depth are checked before any parser runs so pathological input never
+// reaches the recursive block parsers.
+func ParseWithLimits(source string, limits Limits) (ast.Diagram, error) {
+	if limits.MaxBytes > 0 && len(source) > limits.MaxBytes {
+		return nil, fmt.Errorf("%w: source is %d bytes, maximum is %d", ErrLimitExceeded, len(source), limits.MaxBytes)
+	}
+
+	if limits.MaxLines > 0 {
+		if lines := strings.Count(source, "\n") + 1; lines > limits.MaxLines {
+			return nil, fmt.Errorf("%w: source has %d lines, maximum is %d", ErrLimitExceeded, lines, limits.MaxLines)
+		}
+	}
+
+	if limits.MaxDepth > 0 {
+		if depth, line := blockDepth(detectDiagramType(source), source); depth > limits.MaxDepth {
+			return nil, fmt.Errorf("%w: line %d: nesting depth %d exceeds maximum of %d", ErrLimitExceeded, line, depth, limits.MaxDepth)
+		}
+	}
+
+	diagram, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if limits.MaxDepth > 0 {
+		if mm, ok := diagram.(*ast.MindmapDiagram); ok && mm.Root != nil {
+			if depth := mindmapDepth(mm.Root); depth > limits.MaxDepth {
+				return nil, fmt.Errorf("%w: mindmap depth %d exceeds maximum of %d", ErrLimitExceeded, depth, limits.MaxDepth)
+			}
+		}
+	}
+
+	if limits.MaxNodes > 0 {
+		if nodes := CountNodes(diagram); nodes > limits.MaxNodes {
+			return nil, fmt.Errorf("%w: diagram has %d nodes, maximum is %d", ErrLimitExceeded, nodes, limits.MaxNodes)
+		}
+	}
+
+	return diagram, nil
+}
+
+// blockDepth scans the source for block openers and closers and returns the
+// deepest nesting reached together with the line where it was first reached.
+func blockDepth(diagType, source string) (int, int) {
+	var isOpen, isClose func(trimmed string) bool
+
+	switch {
+	case diagType == "flowchart" || diagType == "graph":
+		isOpen = func(t string) bool { return subgraphStartPattern.MatchString(t) }
+		isClose = func(t string) bool { return subgraphEndPattern.MatchString(t) }
+	case diagType == "sequence":
+		isOpen = func(t string) bool {
+			return loopPattern.MatchString(t) || altPattern.MatchString(t) || optPattern.MatchString(t) ||
+				parPattern.MatchString(t) || criticalPattern.MatchString(t) || breakPattern.MatchString(t) ||
+				boxPattern.MatchString(t)
+		}
+		isClose = func(t string) bool { return endPattern.MatchString(t) }
+	case diagType == "class" || strings.HasPrefix(diagType, "state") || strings.HasPrefix(diagType, "c4"):
+		isOpen = func(t string) bool { return strings.HasSuffix(t, "{") }
+		isClose = func(t string) bool { return strings.HasPrefix(t, "}") }
+	default:
+		return 0, 0
+	}
+
+	depth, maxDepth, maxLine := 0, 0, 0
+	for i, line := range strings.Split(source, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "%%"):
+			continue
+		case isOpen(trimmed):
+			depth++
+			if depth > maxDepth {
+				maxDepth, maxLine = depth, i+1
+			}
+		case isClose(trimmed) && depth > 0:
+			depth--
+		}
+	}
+
+	return maxDepth, maxLine
+}
+
+// mindmapDepth returns the number of levels below and including node.
+func mindmapDepth(node *ast.MindmapNode) int {
+	deepest := 0
+	for _, child := range node.Children {
+		deepest = max(deepest, mindmapDepth(child))
+	}
+	return deepest + 1
+}
+
+// CountNodes returns the number of AST nodes in a diagram: statements for
+// statement-based diagrams (counted recursively through blocks), and elements,
+// entries, tasks, links or points for the structured diagram types.
+func CountNodes(diagram ast.Diagram) int {
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		return countFlowchartStatements(d.Statements)
+	case *ast.SequenceDiagram:
+		return countSequenceStatements(d.Statements)
+	case *ast.ClassDiagram:
+		count := len(d.Statements)
+		for _, stmt := range d.Statements {
+			if class, ok := stmt.(*ast.Class); ok {
+				count += len(class.Members)
+			}
+		}
+		return count
+	case *ast.StateDiagram:
+		return countStateStatements(d.Statements)
+	case *ast.C4Diagram:
+		return len(d.Elements) + len(d.Relationships) + len(d.Styles) + countC4Boundaries(d.Boundaries)
+	case *ast.MindmapDiagram:
+		if d.Root == nil {
+			return 0
+		}
+		return countMindmapNodes(d.Root)
+	default:
+		return countDataNodes(diagram)
+	}
+}
+
+// countDataNodes counts nodes for the flat, data-oriented diagram types.
+func countDataNodes(diagram ast.Diagram) int {
+	count := 0
+	switch d := diagram.(type) {
+	case *ast.ERDiagram:
+		count = len(d.Entities) + len(d.Relationships)
+		for _, entity := range d.Entities {
+			count += len(entity.Attributes)
+		}
+	case *ast.GanttDiagram:
+		for _, section := range d.Sections {
+			count += 1 + len(section.Tasks)
+		}
+	case *ast.JourneyDiagram:
+		for _, section := range d.Sections {
+			count += 1 + len(section.Tasks)
+		}
+	case *ast.TimelineDiagram:
+		for _, section := range d.Sections {
+			count += 1 + len(section.Periods)
+		}
+	case *ast.PieDiagram:
+		count = len(d.DataEntries)
+	case *ast.GitGraphDiagram:
+		count = len(d.Operations)
+	case *ast.SankeyDiagram:
+		count = len(d.Links)
+	case *ast.QuadrantDiagram:
+		count = len(d.Points)
+	case *ast.XYChartDiagram:
+		count = len(d.Series)
+	case *ast.GenericDiagram:
+		count = len(d.Lines)
+	}
+	return count
+}
+
+func countFlowchartStatements(statements []ast.Statement) int {
+	count := len(statements)
+	for _, stmt := range statements {
+		if sub, ok := stmt.(*ast.Subgraph); ok {
+			count += countFlowchartStatements(sub.Statements)
+		}
+	}
+	return count
+}
+
+func countSequenceStatements(statements []ast.SeqStmt) int {
+	count := len(statements)
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Loop:
+			count += countSequenceStatements(s.Statements)
+		case *ast.Opt:
+			count += countSequenceStatements(s.Statements)
+		case *ast.Break:
+			count += countSequenceStatements(s.Statements)
+		case *ast.Alt:
+			for _, cond := range s.Conditions {
+				count += countSequenceStatements(cond.Statements)
+			}
+		case *ast.Par:
+			for _, branch := range s.Branches {
+				count += countSequenceStatements(branch.Statements)
+			}
+		case *ast.Critical:
+			count += countSequenceStatements(s.Statements)
+			for _, opt := range s.Options {
+				count += countSequenceStatements(opt.Statements)
+			}
+		case *ast.Box:
+			count += len(s.Participants)
+		}
+	}
+	return count
+}
+
+func countStateStatements(statements []ast.StateStmt) int {
+	count := len(statements)
+	for _, stmt := range statements {
+		if state, ok := stmt.(*ast.State); ok {
+			count += countStateStatements(state.Nested)
+		}
+	}
+	return count
+}
+
+func countC4Boundaries(boundaries []ast.C4Boundary) int {
+	count := len(boundaries)
+	for _, boundary := range boundaries {
+		count += len(boundary.Elements) + countC4Boundaries(boundary.Boundaries)
+	}
+	return count
+}
+
+func countMindmapNodes(node *ast.MindmapNode) int {
+	count := 1
+	for _, child := range node.Children {
+		count += countMindmapNodes(child)
+	}
+	return count
+}
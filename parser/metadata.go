@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// frontmatterKeyPattern matches a top-level scalar `key: value` line in YAML frontmatter.
+var frontmatterKeyPattern = regexp.MustCompile(`^([\pL\pN_.-]+):\s*(.*?)\s*$`)
+
+// annotationPattern matches a structured comment annotation such as
+// `%% @owner: platform-team` or a bare flag such as `%% @deprecated`.
+var annotationPattern = regexp.MustCompile(`^%%\s*@([\pL\pN_.-]+)\s*(?::\s*(.*?))?\s*$`)
+
+// metadataSetter is implemented by every diagram type through ast.Annotations.
+type metadataSetter interface {
+	SetMetadata(metadata map[string]string)
+}
+
+// ExtractMetadata collects `%% @key: value` annotations from Mermaid source,
+// along with top-level scalar keys (such as title and id) from any leading
+// `---` frontmatter block. Keys are case-sensitive; when a key is repeated the
+// last value wins, and annotations take precedence over frontmatter. Bare
+// annotations (`%% @key`) are recorded with an empty value. It returns nil when
+// source has no metadata.
+func ExtractMetadata(source string) map[string]string {
+	metadata := frontmatterMetadata(source)
+	for line := range strings.SplitSeq(source, "\n") {
+		matches := annotationPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[matches[1]] = matches[2]
+	}
+	return metadata
+}
+
+// frontmatterMetadata returns the top-level scalar keys of a leading `---`
+// frontmatter block. Nested mappings (such as config:) and lists are skipped.
+func frontmatterMetadata(source string) map[string]string {
+	lines := strings.Split(strings.TrimLeft(source, "\n"), "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+
+	var metadata map[string]string
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			return metadata
+		}
+		matches := frontmatterKeyPattern.FindStringSubmatch(line)
+		if matches == nil || matches[2] == "" {
+			continue // indented, nested or list entries
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[matches[1]] = strings.Trim(matches[2], `"'`)
+	}
+
+	return nil // unterminated frontmatter
+}
+
+// withMetadata attaches the annotations found in source to diagram.
+func withMetadata(diagram ast.Diagram, source string) ast.Diagram {
+	if metadata := ExtractMetadata(source); metadata != nil {
+		if setter, ok := diagram.(metadataSetter); ok {
+			setter.SetMetadata(metadata)
+		}
+	}
+	return diagram
+}
@@ -18,7 +18,8 @@ func NewMindmapParser() *MindmapParser {
 
 var (
 	mindmapHeaderRegex = regexp.MustCompile(`^mindmap\s*$`)
-	mindmapIconRegex   = regexp.MustCompile(`^\s*::icon\(([^)]+)\)\s*$`)
+	mindmapIconRegex   = regexp.MustCompile(`^\s*::icon\(([^)]*)\)\s*$`)
+	mindmapClassRegex  = regexp.MustCompile(`^\s*:::(.*)$`)
 )
 
 // Parse parses a mindmap diagram source.
@@ -34,8 +35,9 @@ func (p *MindmapParser) Parse(source string) (ast.Diagram, error) {
 		Pos:    ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if !mindmapHeaderRegex.MatchString(firstLine) {
 		return nil, fmt.Errorf("invalid mindmap diagram header: %s", firstLine)
 	}
@@ -46,7 +48,7 @@ func (p *MindmapParser) Parse(source string) (ast.Diagram, error) {
 	indentSize := 0    // Will be detected as 2 or 4
 	rootIndent := -1   // Track root indentation
 
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 
 		// Skip empty lines and comments
@@ -55,6 +57,25 @@ func (p *MindmapParser) Parse(source string) (ast.Diagram, error) {
 			continue
 		}
 
+		// Icon and class lines decorate the last node, whatever their indentation
+		if iconMatches := mindmapIconRegex.FindStringSubmatch(trimmed); iconMatches != nil {
+			if len(nodeStack) == 0 {
+				return nil, fmt.Errorf("line %d: icon definition outside of node", i+1)
+			}
+			node := nodeStack[len(nodeStack)-1]
+			node.Icon = strings.TrimSpace(iconMatches[1])
+			node.Icons = append(node.Icons, node.Icon)
+			continue
+		}
+		if classMatches := mindmapClassRegex.FindStringSubmatch(trimmed); classMatches != nil {
+			if len(nodeStack) == 0 {
+				return nil, fmt.Errorf("line %d: class definition outside of node", i+1)
+			}
+			node := nodeStack[len(nodeStack)-1]
+			node.Classes = append(node.Classes, strings.Fields(classMatches[1])...)
+			continue
+		}
+
 		// Calculate indentation level
 		indent := len(line) - len(strings.TrimLeft(line, " \t"))
 
@@ -100,16 +121,6 @@ func (p *MindmapParser) Parse(source string) (ast.Diagram, error) {
 			return nil, fmt.Errorf("line %d: invalid indentation (less than root)", i+1)
 		}
 
-		// Check for icon line
-		if iconMatches := mindmapIconRegex.FindStringSubmatch(trimmed); iconMatches != nil {
-			if len(nodeStack) == 0 {
-				return nil, fmt.Errorf("line %d: icon definition outside of node", i+1)
-			}
-			// Add icon to last node
-			nodeStack[len(nodeStack)-1].Icon = strings.TrimSpace(iconMatches[1])
-			continue
-		}
-
 		// Parse node text and shape
 		text, shape := parseNodeText(trimmed)
 
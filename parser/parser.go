@@ -3,9 +3,11 @@ package parser
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
 )
 
 // DiagramParser defines the interface all diagram parsers must implement.
@@ -19,63 +21,83 @@ type DiagramParser interface {
 // Parse parses a Mermaid diagram from source and returns a Diagram.
 // It automatically detects the diagram type and uses the appropriate parser.
 func Parse(source string) (ast.Diagram, error) {
+	source = inpututil.NormaliseNewlines(source)
 	if strings.TrimSpace(source) == "" {
 		return nil, fmt.Errorf("empty diagram source")
 	}
 
 	diagType := detectDiagramType(source)
+	if written, intended := misspeltHeader(source, diagType); intended != "" {
+		return nil, fmt.Errorf("unknown diagram type %q: did you mean %q?", written, intended)
+	}
+
+	parser, registered := registeredParser(diagType)
+	if !registered {
+		parser = builtinParser(diagType)
+	}
+	if parser == nil {
+		// Fallback to GenericDiagram for known types without specific parsers
+		if isKnownDiagramType(diagType) {
+			return withMetadata(ast.NewGenericDiagram(diagType, source, ast.Position{Line: 1, Column: 1}), source), nil
+		}
+		supportedTypes := "flowchart, graph, sequence, class, state, stateDiagram-v2, er, gantt, pie, journey, gitGraph, mindmap, timeline, sankey, quadrantChart, xyChart, c4Context, c4Container, c4Component, c4Dynamic, c4Deployment"
+		if extra := Registered(); len(extra) > 0 {
+			supportedTypes += ", " + strings.Join(extra, ", ")
+		}
+		return nil, fmt.Errorf("unknown or unsupported diagram type %q: expected one of: %s", diagType, supportedTypes)
+	}
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return withMetadata(diagram, source), nil
+}
 
-	// Direct parser instantiation based on type
-	var parser DiagramParser
+// builtinParser returns a new parser for diagType, or nil if there is no
+// dedicated parser for it.
+func builtinParser(diagType string) DiagramParser {
 	switch diagType {
 	case "flowchart", "graph":
-		parser = NewFlowchartParser()
+		return NewFlowchartParser()
 	case "sequence":
-		parser = NewSequenceParser()
+		return NewSequenceParser()
 	case "class":
-		parser = NewClassParser()
+		return NewClassParser()
 	case "state", "stateDiagram-v2":
-		parser = NewStateParser()
+		return NewStateParser()
 	case "er":
-		parser = NewERParser()
+		return NewERParser()
 	case "gantt":
-		parser = NewGanttParser()
+		return NewGanttParser()
 	case "pie":
-		parser = NewPieParser()
+		return NewPieParser()
 	case "journey":
-		parser = NewJourneyParser()
+		return NewJourneyParser()
 	case "timeline":
-		parser = NewTimelineParser()
+		return NewTimelineParser()
 	case "gitGraph":
-		parser = NewGitGraphParser()
+		return NewGitGraphParser()
 	case "mindmap":
-		parser = NewMindmapParser()
+		return NewMindmapParser()
 	case "sankey":
-		parser = NewSankeyParser()
+		return NewSankeyParser()
 	case "quadrantChart":
-		parser = NewQuadrantParser()
+		return NewQuadrantParser()
 	case "xyChart":
-		parser = NewXYChartParser()
+		return NewXYChartParser()
 	case "c4Context":
-		parser = NewC4ContextParser()
+		return NewC4ContextParser()
 	case "c4Container":
-		parser = NewC4ContainerParser()
+		return NewC4ContainerParser()
 	case "c4Component":
-		parser = NewC4ComponentParser()
+		return NewC4ComponentParser()
 	case "c4Dynamic":
-		parser = NewC4DynamicParser()
+		return NewC4DynamicParser()
 	case "c4Deployment":
-		parser = NewC4DeploymentParser()
-	default:
-		// Fallback to GenericDiagram for known types without specific parsers
-		if isKnownDiagramType(diagType) {
-			return ast.NewGenericDiagram(diagType, source, ast.Position{Line: 1, Column: 1}), nil
-		}
-		supportedTypes := "flowchart, graph, sequence, class, state, stateDiagram-v2, er, gantt, pie, journey, gitGraph, mindmap, timeline, sankey, quadrantChart, xyChart, c4Context, c4Container, c4Component, c4Dynamic, c4Deployment"
-		return nil, fmt.Errorf("unknown or unsupported diagram type %q: expected one of: %s", diagType, supportedTypes)
+		return NewC4DeploymentParser()
 	}
-
-	return parser.Parse(source)
+	return nil
 }
 
 // diagramTypeMapping maps Mermaid diagram prefixes to normalized type names.
@@ -87,6 +109,7 @@ var diagramTypeMapping = []struct {
 	{"stateDiagram-v2", "stateDiagram-v2"},
 	{"stateDiagram", "state"},
 	{"sequenceDiagram", "sequence"},
+	{"classDiagram-v2", "class"},
 	{"classDiagram", "class"},
 	{"erDiagram", "er"},
 	{"C4Context", "c4Context"},
@@ -101,24 +124,67 @@ var diagramTypeMapping = []struct {
 	{"timeline", "timeline"},
 	{"mindmap", "mindmap"},
 	{"journey", "journey"},
+	{"flowchart-elk", "flowchart"},
 	{"flowchart", "flowchart"},
 	{"gantt", "gantt"},
 	{"graph", "graph"},
 	{"pie", "pie"},
 }
 
+// DetectType returns the type Parse would detect for source from its header,
+// such as "flowchart", "sequence", "c4Context" or a type added with Register,
+// or "unknown". Detect also suggests headers for sources it doesn't recognise.
+func DetectType(source string) string {
+	return detectDiagramType(inpututil.NormaliseNewlines(source))
+}
+
+// frontMatterEnd returns the index of the first line after a leading `---`
+// front matter block, or 0 when lines don't start with one.
+func frontMatterEnd(lines []string) int {
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i == len(lines) || strings.TrimSpace(lines[i]) != "---" {
+		return 0
+	}
+	for end := i + 1; end < len(lines); end++ {
+		if strings.TrimSpace(lines[end]) == "---" {
+			return end + 1
+		}
+	}
+	return 0 // unterminated front matter
+}
+
+// headerLine returns the index of the line holding the diagram header, after
+// any leading front matter, blank lines, comments and directives. It returns 0
+// when no line follows them, so the first line is reported as the header.
+func headerLine(lines []string) int {
+	for i := frontMatterEnd(lines); i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "%%") {
+			return i
+		}
+	}
+	return 0
+}
+
 // detectDiagramType detects the diagram type from the source.
 func detectDiagramType(source string) string {
-	lines := strings.SplitSeq(source, "\n")
-	for line := range lines {
+	lines := strings.Split(source, "\n")
+	for _, line := range lines[frontMatterEnd(lines):] {
 		trimmed := strings.TrimSpace(line)
 		if trimmed == "" || strings.HasPrefix(trimmed, "%%") {
 			continue // Skip empty lines and comments
 		}
 
+		if registered := registeredType(trimmed); registered != "" {
+			return registered
+		}
+
 		// Check for diagram type keywords in order of specificity
 		for _, mapping := range diagramTypeMapping {
-			if strings.HasPrefix(trimmed, mapping.prefix) {
+			if hasHeader(trimmed, mapping.prefix) {
 				return mapping.typeID
 			}
 		}
@@ -130,6 +196,51 @@ func detectDiagramType(source string) string {
 	return "unknown"
 }
 
+// hasHeader reports whether line starts with the header keyword, followed by
+// whitespace or nothing, so that "graphic" and "pie-chart" are not taken for
+// "graph" and "pie".
+func hasHeader(line, keyword string) bool {
+	rest, ok := strings.CutPrefix(line, keyword)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// headerNoiseWords are words people add after a header, as in "gantt chart".
+var headerNoiseWords = []string{"chart", "diagram"}
+
+// misspeltHeader returns the header written at the start of source and the one
+// it was probably meant to be, when it is a near miss such as "flowChart",
+// "sequence diagram" or "gantt chart". diagType is the type detected for
+// source; only headers that were not recognised, or that were followed by a
+// noise word, are considered.
+func misspeltHeader(source, diagType string) (written, intended string) {
+	fields := strings.Fields(firstStatement(source))
+	if len(fields) == 0 {
+		return "", ""
+	}
+
+	if diagType != "unknown" {
+		if len(fields) > 1 && slices.Contains(headerNoiseWords, strings.ToLower(fields[1])) {
+			for _, mapping := range diagramTypeMapping {
+				if fields[0] == mapping.prefix {
+					return fields[0] + " " + fields[1], mapping.prefix
+				}
+			}
+		}
+		return "", ""
+	}
+
+	if header := closestHeader(fields[0]); header != "" {
+		return fields[0], header
+	}
+	// Headers split into two words, such as "sequence diagram"
+	if len(fields) > 1 {
+		if header := closestHeader(fields[0] + fields[1]); header != "" {
+			return fields[0] + " " + fields[1], header
+		}
+	}
+	return "", ""
+}
+
 // isKnownDiagramType returns true if the type is a known Mermaid diagram type.
 func isKnownDiagramType(diagType string) bool {
 	for _, mapping := range diagramTypeMapping {
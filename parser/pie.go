@@ -19,7 +19,8 @@ func NewPieParser() *PieParser {
 
 var (
 	pieHeaderRegex = regexp.MustCompile(`^pie\s*(?:(showData)\s*)?(?:title\s+(.+))?$`)
-	pieEntryRegex  = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*([0-9]+(?:\.[0-9]{1,2})?)\s*$`)
+	pieTitleRegex  = regexp.MustCompile(`^\s*title\s+(.+)$`)
+	pieEntryRegex  = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*([0-9]+(?:\.[0-9]+)?)\s*$`)
 )
 
 // Parse parses a pie chart diagram source.
@@ -36,8 +37,22 @@ func (p *PieParser) Parse(source string) (ast.Diagram, error) {
 		Pos:         ast.Position{Line: 1, Column: 1},
 	}
 
+	// Skip front matter, blank lines, comments and init directives before
+	// the header, collecting pie settings from the directives
+	header := frontMatterEnd(lines)
+	for ; header < len(lines)-1; header++ {
+		trimmed := strings.TrimSpace(lines[header])
+		if config, ok := parseInitDirective(trimmed); ok {
+			applyPieConfig(diagram, config, header+1)
+			continue
+		}
+		if trimmed != "" && !strings.HasPrefix(trimmed, "%%") {
+			break
+		}
+	}
+
 	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	firstLine := strings.TrimSpace(lines[header])
 	matches := pieHeaderRegex.FindStringSubmatch(firstLine)
 	if matches == nil {
 		return nil, fmt.Errorf("invalid pie chart header: %s", firstLine)
@@ -54,7 +69,7 @@ func (p *PieParser) Parse(source string) (ast.Diagram, error) {
 	}
 
 	// Parse data entries
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -63,6 +78,12 @@ func (p *PieParser) Parse(source string) (ast.Diagram, error) {
 			continue
 		}
 
+		// A title may follow the header on its own line
+		if titleMatches := pieTitleRegex.FindStringSubmatch(trimmed); titleMatches != nil {
+			diagram.Title = strings.TrimSpace(titleMatches[1])
+			continue
+		}
+
 		// Parse data entry
 		entryMatches := pieEntryRegex.FindStringSubmatch(trimmed)
 		if entryMatches == nil {
@@ -84,6 +105,7 @@ func (p *PieParser) Parse(source string) (ast.Diagram, error) {
 		diagram.DataEntries = append(diagram.DataEntries, ast.PieEntry{
 			Label: label,
 			Value: value,
+			Raw:   valueStr,
 			Pos:   ast.Position{Line: i + 1, Column: 1},
 		})
 	}
@@ -95,6 +117,36 @@ func (p *PieParser) Parse(source string) (ast.Diagram, error) {
 	return diagram, nil
 }
 
+// applyPieConfig copies the pie settings of an init directive onto diagram:
+// the keys of its "pie" section and any pie* theme variables.
+func applyPieConfig(diagram *ast.PieDiagram, config map[string]any, line int) {
+	found := false
+	if pie, ok := config["pie"].(map[string]any); ok {
+		for key, value := range pie {
+			if diagram.Config == nil {
+				diagram.Config = make(map[string]string)
+			}
+			diagram.Config[key] = fmt.Sprint(value)
+			found = true
+		}
+	}
+	if theme, ok := config["themeVariables"].(map[string]any); ok {
+		for key, value := range theme {
+			if !strings.HasPrefix(key, "pie") {
+				continue
+			}
+			if diagram.Config == nil {
+				diagram.Config = make(map[string]string)
+			}
+			diagram.Config[key] = fmt.Sprint(value)
+			found = true
+		}
+	}
+	if found {
+		diagram.ConfigPos = ast.Position{Line: line, Column: 1}
+	}
+}
+
 // SupportedTypes returns the diagram types this parser supports.
 func (p *PieParser) SupportedTypes() []string {
 	return []string{"pie"}
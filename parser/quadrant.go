@@ -23,7 +23,8 @@ var (
 	quadrantXAxisRegex  = regexp.MustCompile(`^\s*x-axis\s+(.+?)\s+-->\s+(.+)$`)
 	quadrantYAxisRegex  = regexp.MustCompile(`^\s*y-axis\s+(.+?)\s+-->\s+(.+)$`)
 	quadrantLabelRegex  = regexp.MustCompile(`^\s*quadrant-([1-4])\s+(.+)$`)
-	quadrantPointRegex  = regexp.MustCompile(`^\s*(.+?):\s*\[\s*([0-9]+(?:\.[0-9]+)?)\s*,\s*([0-9]+(?:\.[0-9]+)?)\s*\]$`)
+	quadrantPointRegex  = regexp.MustCompile(`^\s*(.+?)(?::::([\w-]+))?:\s*\[\s*([0-9]+(?:\.[0-9]+)?)\s*,\s*([0-9]+(?:\.[0-9]+)?)\s*\]\s*(.*)$`)
+	quadrantClassRegex  = regexp.MustCompile(`^\s*classDef\s+([\w-]+)\s+(.+)$`)
 )
 
 // Parse parses a quadrant chart diagram source.
@@ -40,8 +41,9 @@ func (p *QuadrantParser) Parse(source string) (ast.Diagram, error) {
 		Pos:    ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if !quadrantHeaderRegex.MatchString(firstLine) {
 		return nil, fmt.Errorf("invalid quadrant chart header: %s", firstLine)
 	}
@@ -49,7 +51,7 @@ func (p *QuadrantParser) Parse(source string) (ast.Diagram, error) {
 	var xAxisDefined, yAxisDefined bool
 
 	// Parse remaining lines
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -91,11 +93,25 @@ func (p *QuadrantParser) Parse(source string) (ast.Diagram, error) {
 			continue
 		}
 
+		// Try to match class definition
+		if matches := quadrantClassRegex.FindStringSubmatch(trimmed); matches != nil {
+			styles, err := parseQuadrantStyles(matches[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			diagram.ClassDefs = append(diagram.ClassDefs, ast.ClassDef{
+				Name:   matches[1],
+				Styles: styles,
+				Pos:    ast.Position{Line: i + 1, Column: 1},
+			})
+			continue
+		}
+
 		// Try to match data point
 		if matches := quadrantPointRegex.FindStringSubmatch(trimmed); matches != nil {
 			name := strings.TrimSpace(matches[1])
-			xStr := matches[2]
-			yStr := matches[3]
+			xStr := matches[3]
+			yStr := matches[4]
 
 			x, err := strconv.ParseFloat(xStr, 64)
 			if err != nil {
@@ -107,11 +123,20 @@ func (p *QuadrantParser) Parse(source string) (ast.Diagram, error) {
 				return nil, fmt.Errorf("line %d: invalid Y coordinate: %s", i+1, yStr)
 			}
 
+			var styles map[string]string
+			if strings.TrimSpace(matches[5]) != "" {
+				if styles, err = parseQuadrantStyles(matches[5]); err != nil {
+					return nil, fmt.Errorf("line %d: %v", i+1, err)
+				}
+			}
+
 			diagram.Points = append(diagram.Points, ast.QuadrantPoint{
-				Name: name,
-				X:    x,
-				Y:    y,
-				Pos:  ast.Position{Line: i + 1, Column: 1},
+				Name:   name,
+				X:      x,
+				Y:      y,
+				Pos:    ast.Position{Line: i + 1, Column: 1},
+				Class:  matches[2],
+				Styles: styles,
 			})
 			continue
 		}
@@ -136,6 +161,25 @@ func (p *QuadrantParser) Parse(source string) (ast.Diagram, error) {
 	return diagram, nil
 }
 
+// parseQuadrantStyles parses comma-separated `key: value` point styles, as
+// used after a point's coordinates and in classDef lines.
+func parseQuadrantStyles(input string) (map[string]string, error) {
+	styles := make(map[string]string)
+	for part := range strings.SplitSeq(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid point style %q (expected key: value)", part)
+		}
+		styles[key] = value
+	}
+	return styles, nil
+}
+
 // SupportedTypes returns the diagram types this parser supports.
 func (p *QuadrantParser) SupportedTypes() []string {
 	return []string{"quadrantChart"}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// maxRecoveredErrors caps how many syntax errors ParseRecovering collects for a
+// single diagram before giving up.
+const maxRecoveredErrors = 100
+
+// lineErrorPattern extracts the line number from parser errors of the form "line N: message".
+var lineErrorPattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// SyntaxError describes a single parse failure found by ParseRecovering.
+type SyntaxError struct {
+	Line       int    // Line number (1-indexed), or 0 when the error is not tied to a line
+	Message    string // Error message without the line prefix
+	Suggestion string // "Did you mean" hint for fixing the error, if any (see Suggest)
+}
+
+func (e SyntaxError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseRecovering parses source like Parse, but instead of stopping at the first
+// syntax error it keeps going and reports every error it can find.
+//
+// Each time a parser rejects a line, that line is recorded as a SyntaxError and
+// blanked out, and parsing is retried. A block left open is instead closed
+// with an 'end' or '}' after the last line, keeping the lines in it. Line
+// numbers in later errors are therefore unaffected by earlier ones. The
+// returned diagram is the best-effort partial AST from the first attempt that
+// succeeded, with the offending lines omitted; it is nil if no attempt
+// succeeded (for example when the header itself is invalid).
+func ParseRecovering(source string) (ast.Diagram, []SyntaxError) {
+	lines := strings.Split(source, "\n")
+	var errors []SyntaxError
+
+	for len(errors) < maxRecoveredErrors {
+		diagram, err := Parse(strings.Join(lines, "\n"))
+		if err == nil {
+			if len(errors) > 0 {
+				setSource(diagram, source)
+			}
+			return diagram, errors
+		}
+
+		syntaxErr := toSyntaxError(err)
+
+		// Re-reporting an already blanked line means the parser made no progress;
+		// the error has been recorded already.
+		if syntaxErr.Line >= 1 && syntaxErr.Line <= len(lines) && strings.TrimSpace(lines[syntaxErr.Line-1]) == "" {
+			return nil, errors
+		}
+		// The same error again is for an enclosing block still left open
+		repeated := len(errors) > 0 && errors[len(errors)-1].Line == syntaxErr.Line && errors[len(errors)-1].Message == syntaxErr.Message
+		if !repeated {
+			syntaxErr.Suggestion = suggest(source, syntaxErr)
+			errors = append(errors, syntaxErr)
+		}
+
+		// Close blocks left open at the end, keeping the statements in them
+		if isUnclosedBlock(syntaxErr) {
+			if _, _, closer := unclosedBlock(lines); closer != "" {
+				lines = append(lines, closer)
+				continue
+			}
+		}
+		if repeated {
+			return nil, errors
+		}
+
+		// Errors without a usable line number cannot be recovered from.
+		if syntaxErr.Line < 1 || syntaxErr.Line > len(lines) {
+			return nil, errors
+		}
+		lines[syntaxErr.Line-1] = ""
+	}
+
+	return nil, errors
+}
+
+// isUnclosedBlock reports whether err is for a block without its 'end' or '}'.
+func isUnclosedBlock(err SyntaxError) bool {
+	return strings.Contains(err.Message, "unclosed") || strings.Contains(err.Message, "missing 'end'")
+}
+
+// toSyntaxError converts a parser error into a SyntaxError, splitting off any line prefix.
+func toSyntaxError(err error) SyntaxError {
+	if matches := lineErrorPattern.FindStringSubmatch(err.Error()); matches != nil {
+		if line, convErr := strconv.Atoi(matches[1]); convErr == nil {
+			return SyntaxError{Line: line, Message: matches[2]}
+		}
+	}
+	return SyntaxError{Message: err.Error()}
+}
+
+// setSource restores the original, unmodified source on a recovered diagram.
+func setSource(diagram ast.Diagram, source string) {
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		d.Source = source
+	case *ast.SequenceDiagram:
+		d.Source = source
+	case *ast.ClassDiagram:
+		d.Source = source
+	case *ast.StateDiagram:
+		d.Source = source
+	case *ast.ERDiagram:
+		d.Source = source
+	case *ast.GanttDiagram:
+		d.Source = source
+	case *ast.PieDiagram:
+		d.Source = source
+	case *ast.JourneyDiagram:
+		d.Source = source
+	case *ast.TimelineDiagram:
+		d.Source = source
+	case *ast.GitGraphDiagram:
+		d.Source = source
+	case *ast.MindmapDiagram:
+		d.Source = source
+	case *ast.SankeyDiagram:
+		d.Source = source
+	case *ast.QuadrantDiagram:
+		d.Source = source
+	case *ast.XYChartDiagram:
+		d.Source = source
+	case *ast.C4Diagram:
+		d.Source = source
+	case *ast.GenericDiagram:
+		d.Source = source
+	}
+}
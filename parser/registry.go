@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// registry holds the constructors of the parsers added with Register, keyed
+// by the header that introduces their diagrams.
+var registry = struct {
+	sync.RWMutex
+	parsers map[string]func() DiagramParser
+	headers []string // Registered headers, longest first
+}{parsers: map[string]func() DiagramParser{}}
+
+// Register makes newParser the constructor of the parser for diagrams whose
+// header is diagramType, alone or followed by whitespace, such as
+// "architecture-beta" or a private dialect's keyword. Parse, DetectType and
+// everything built on them, including ParseFile and the CLI, then route such
+// diagrams to a parser from newParser and report their type as diagramType.
+// Each parse gets a new parser, as the built-in ones do, so parsers may keep
+// state and are never shared between goroutines.
+// Registered headers are matched before the built-in ones, so a built-in type
+// can be replaced. Register panics if diagramType is empty or contains
+// spaces, if newParser is nil, or if diagramType is already registered.
+func Register(diagramType string, newParser func() DiagramParser) {
+	if diagramType == "" || strings.ContainsAny(diagramType, " \t") {
+		panic(fmt.Sprintf("parser: invalid diagram type %q", diagramType))
+	}
+	if newParser == nil {
+		panic("parser: Register constructor is nil")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, dup := registry.parsers[diagramType]; dup {
+		panic(fmt.Sprintf("parser: Register called twice for diagram type %q", diagramType))
+	}
+	registry.parsers[diagramType] = newParser
+	registry.headers = append(registry.headers, diagramType)
+	// Longer headers first, so "block-beta2" is matched before "block-beta"
+	slices.SortStableFunc(registry.headers, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+}
+
+// Registered returns the diagram types added with Register, sorted.
+func Registered() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	types := slices.Clone(registry.headers)
+	slices.Sort(types)
+	return types
+}
+
+// registeredParser returns a new parser for diagType, if one is registered.
+func registeredParser(diagType string) (DiagramParser, bool) {
+	registry.RLock()
+	newParser, ok := registry.parsers[diagType]
+	registry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return newParser(), true
+}
+
+// registeredType returns the registered diagram type whose header starts
+// line, or "" if there is none.
+func registeredType(line string) string {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, header := range registry.headers {
+		if hasHeader(line, header) {
+			return header
+		}
+	}
+	return ""
+}
@@ -30,14 +30,15 @@ func (p *SankeyParser) Parse(source string) (ast.Diagram, error) {
 		Pos:    ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if firstLine != "sankey-beta" {
 		return nil, fmt.Errorf("invalid Sankey diagram header: expected 'sankey-beta', got %q", firstLine)
 	}
 
 	// Parse link lines
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -14,11 +19,11 @@ var (
 	seqCommentPattern = regexp.MustCompile(`^%%(.*)$`)
 
 	// Participant patterns
-	participantPattern = regexp.MustCompile(`^(participant|actor)\s+(\w+)(?:\s+as\s+(.+))?$`)
+	participantPattern = regexp.MustCompile(`^(participant|actor)\s+([\pL\pM\pN_]+)(?:\s+as\s+(.+))?$`)
 
 	// Activation patterns
-	activatePattern   = regexp.MustCompile(`^activate\s+(\w+)$`)
-	deactivatePattern = regexp.MustCompile(`^deactivate\s+(\w+)$`)
+	activatePattern   = regexp.MustCompile(`^activate\s+([\pL\pM\pN_]+)$`)
+	deactivatePattern = regexp.MustCompile(`^deactivate\s+([\pL\pM\pN_]+)$`)
 
 	// Block patterns
 	loopPattern     = regexp.MustCompile(`^loop\s+(.+)$`)
@@ -33,15 +38,18 @@ var (
 	endPattern      = regexp.MustCompile(`^end\s*$`)
 
 	// Note patterns (case-insensitive to match Mermaid spec)
-	noteLeftPattern  = regexp.MustCompile(`(?i)^note\s+left\s+of\s+(\w+)\s*:\s*(.+)$`)
-	noteRightPattern = regexp.MustCompile(`(?i)^note\s+right\s+of\s+(\w+)\s*:\s*(.+)$`)
-	noteOverPattern  = regexp.MustCompile(`(?i)^note\s+over\s+([\w,\s]+)\s*:\s*(.+)$`)
+	noteLeftPattern  = regexp.MustCompile(`(?i)^note\s+left\s+of\s+([\pL\pM\pN_]+)\s*:\s*(.+)$`)
+	noteRightPattern = regexp.MustCompile(`(?i)^note\s+right\s+of\s+([\pL\pM\pN_]+)\s*:\s*(.+)$`)
+	noteOverPattern  = regexp.MustCompile(`(?i)^note\s+over\s+([\pL\pM\pN_,\s]+)\s*:\s*(.+)$`)
 
 	// Box pattern
-	boxPattern = regexp.MustCompile(`^box\s+(?:(\w+)\s+)?(.+)$`)
+	boxPattern = regexp.MustCompile(`^box(?:\s+(.*))?$`)
+	// boxColourPattern splits a colour from the start of a box header. Hex and
+	// functional colours may stand alone; a colour word must be followed by a label.
+	boxColourPattern = regexp.MustCompile(`^(?:(#[\pL\pN]*|(?:rgba?|hsla?)\s*\([^)]*\))|([\pL\pM\pN_]+)\s)\s*(.*)$`)
 
 	// Autonumber pattern
-	autonumberPattern = regexp.MustCompile(`^autonumber\s*$`)
+	autonumberPattern = regexp.MustCompile(`^autonumber(?:\s+(.*?))?\s*$`)
 )
 
 // SequenceParser parses Mermaid sequence diagrams.
@@ -61,10 +69,10 @@ func (p *SequenceParser) Parse(source string) (ast.Diagram, error) {
 		return nil, fmt.Errorf("empty sequence diagram")
 	}
 
-	// Find first non-comment, non-empty line
+	// Find first non-comment, non-empty line after any front matter
 	headerLine := -1
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
+	for i := frontMatterEnd(lines); i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
 		if trimmed != "" && !strings.HasPrefix(trimmed, "%%") {
 			headerLine = i
 			break
@@ -249,7 +257,11 @@ func (p *SequenceParser) parseStatement(lines []string, pos ast.Position, lineNu
 
 	// Box
 	if matches := boxPattern.FindStringSubmatch(trimmed); matches != nil {
-		return p.parseBoxBlock(lines, pos, lineNum, matches[1], matches[2])
+		colour, label := "", strings.TrimSpace(matches[1])
+		if parts := boxColourPattern.FindStringSubmatch(label); parts != nil {
+			colour, label = parts[1]+parts[2], parts[3]
+		}
+		return p.parseBoxBlock(lines, pos, lineNum, colour, label)
 	}
 
 	// Notes
@@ -282,11 +294,8 @@ func (p *SequenceParser) parseStatement(lines []string, pos ast.Position, lineNu
 	}
 
 	// Autonumber
-	if autonumberPattern.MatchString(trimmed) {
-		return &ast.Autonumber{
-			Enabled: true,
-			Pos:     pos,
-		}, 1, nil
+	if matches := autonumberPattern.FindStringSubmatch(trimmed); matches != nil {
+		return parseAutonumber(strings.Fields(matches[1]), pos), 1, nil
 	}
 
 	// Message (try this last as it's more permissive)
@@ -298,54 +307,79 @@ func (p *SequenceParser) parseStatement(lines []string, pos ast.Position, lineNu
 	return nil, 0, fmt.Errorf("line %d: unknown sequence diagram statement: %s", pos.Line, trimmed)
 }
 
-func (p *SequenceParser) parseMessage(line string, pos ast.Position) *ast.Message {
-	// Try different arrow patterns
-	arrows := []string{
-		"<<-->>", "<<->>", // Bidirectional
-		"-->>", "->>", "--x", "-x", "--)", "-)", "-->", "->", // Unidirectional
-	}
-
-	for _, arrow := range arrows {
-		if before, after, ok := strings.Cut(line, arrow); ok {
-			from := strings.TrimSpace(before)
-			rest := strings.TrimSpace(after)
-
-			// Check for activation/deactivation markers
-			activate := strings.HasSuffix(rest, "+")
-			deactivate := strings.HasSuffix(rest, "-")
-			if activate || deactivate {
-				rest = strings.TrimSuffix(strings.TrimSuffix(rest, "+"), "-")
-				rest = strings.TrimSpace(rest)
-			}
+// leftArrows maps the left-pointing arrows to the arrows that draw the same
+// message written left to right.
+var leftArrows = map[string]string{"<<--": "-->>", "<<-": "->>"}
+
+// messageArrows are ast.MessageArrows and the left-pointing <<-- and <<-, which
+// are the dotted and solid arrows with the sender on the right (see
+// leftArrows). They are sorted longest first so that -->> is not read as -->.
+var messageArrows = func() []string {
+	arrows := slices.Concat(ast.MessageArrows, slices.Collect(maps.Keys(leftArrows)))
+	slices.SortFunc(arrows, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+	return arrows
+}()
 
-			// Split on colon for message text
-			parts := strings.SplitN(rest, ":", 2)
-			to := strings.TrimSpace(parts[0])
-			text := ""
-			if len(parts) > 1 {
-				text = strings.TrimSpace(parts[1])
-			}
+func (p *SequenceParser) parseMessage(line string, pos ast.Position) *ast.Message {
+	// The participants and arrow come before the first colon; the text after it
+	// may contain anything, arrows included
+	head, text, _ := strings.Cut(line, ":")
+	for i := range len(head) {
+		arrow := messageArrowAt(head[i:])
+		if arrow == "" {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		msg := &ast.Message{
+			From:        strings.TrimSpace(head[:i]),
+			Arrow:       arrow,
+			Text:        text,
+			DisplayText: ast.DecodeText(text),
+			Pos:         pos,
+		}
 
-			// Validate participant IDs
-			if !isValidID(from) || !isValidID(to) {
-				continue
-			}
+		// Activation markers go between the arrow and the target, as in A->>+B
+		to := strings.TrimSpace(head[i+len(arrow):])
+		if marker, rest, ok := cutActivationMarker(to); ok {
+			msg.Activate, msg.Deactivate = marker == '+', marker == '-'
+			to = rest
+		}
+		msg.To = to
 
-			return &ast.Message{
-				From:       from,
-				To:         to,
-				Arrow:      arrow,
-				Text:       text,
-				Activate:   activate,
-				Deactivate: deactivate,
-				Pos:        pos,
-			}
+		if !isValidID(msg.From) || !isValidID(msg.To) {
+			return nil
+		}
+		if rightward, ok := leftArrows[arrow]; ok {
+			msg.From, msg.To = msg.To, msg.From
+			msg.Arrow = rightward
+			msg.Reversed = true
 		}
+		return msg
 	}
 
 	return nil
 }
 
+// messageArrowAt returns the message arrow s starts with, or "".
+func messageArrowAt(s string) string {
+	for _, arrow := range messageArrows {
+		if strings.HasPrefix(s, arrow) {
+			return arrow
+		}
+	}
+	return ""
+}
+
+// cutActivationMarker returns the + or - s starts with and the rest of s.
+func cutActivationMarker(s string) (byte, string, bool) {
+	if s == "" || (s[0] != '+' && s[0] != '-') {
+		return 0, s, false
+	}
+	return s[0], strings.TrimSpace(s[1:]), true
+}
+
 func (p *SequenceParser) extractBlock(lines []string, startLine int) ([]string, int, error) {
 	var blockLines []string //nolint:prealloc // Size cannot be determined beforehand
 	depth := 1
@@ -630,6 +664,34 @@ func (p *SequenceParser) parseCriticalBlock(lines []string, pos ast.Position, li
 	return nil, 0, fmt.Errorf("line %d: unclosed critical block, missing 'end'", lineNum)
 }
 
+// parseAutonumber builds an Autonumber from its arguments: none, "off", or a
+// start number optionally followed by a step. Arguments that aren't integers
+// leave the default in place; validator.ValidAutonumber reports them.
+func parseAutonumber(args []string, pos ast.Position) *ast.Autonumber {
+	autonumber := &ast.Autonumber{
+		Enabled: true,
+		Start:   1,
+		Step:    1,
+		Args:    args,
+		Pos:     pos,
+	}
+	if len(args) > 0 && strings.EqualFold(args[0], "off") {
+		autonumber.Enabled = false
+		return autonumber
+	}
+	if len(args) > 0 {
+		if start, err := strconv.Atoi(args[0]); err == nil {
+			autonumber.Start = start
+		}
+	}
+	if len(args) > 1 {
+		if step, err := strconv.Atoi(args[1]); err == nil {
+			autonumber.Step = step
+		}
+	}
+	return autonumber
+}
+
 func (p *SequenceParser) parseBoxBlock(lines []string, pos ast.Position, lineNum int, colour, label string) (ast.SeqStmt, int, error) {
 	var participants []ast.Participant
 	consumed := 1
@@ -671,10 +733,9 @@ func isValidID(id string) bool {
 	if id == "" {
 		return false
 	}
-	// Check if ID contains only alphanumeric and underscore
+	// Check if ID contains only Unicode letters, marks, digits and underscore
 	for _, ch := range id {
-		if (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') &&
-			(ch < '0' || ch > '9') && ch != '_' {
+		if !unicode.IsLetter(ch) && !unicode.IsMark(ch) && !unicode.IsDigit(ch) && ch != '_' {
 			return false
 		}
 	}
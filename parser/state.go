@@ -14,18 +14,23 @@ var (
 	stateCommentPattern = regexp.MustCompile(`^%%(.*)$`)
 
 	// State declaration patterns
-	stateDefPattern = regexp.MustCompile(`^state\s+"([^"]+)"\s+as\s+(\w+)\s*$`)
+	stateDefPattern = regexp.MustCompile(`^state\s+"([^"]+)"\s+as\s+([\pL\pM\pN_]+)\s*$`)
+	// stateDescriptionPattern matches a description given as "S1 : text"
+	stateDescriptionPattern = regexp.MustCompile(`^([\pL\pM\pN_]+)\s*:\s*(.+?)\s*$`)
 
 	// Transition patterns
-	transitionPattern = regexp.MustCompile(`^(\w+|\[\*\])\s+-->\s+(\w+|\[\*\])(?:\s*:\s*(.+))?\s*$`)
+	transitionPattern = regexp.MustCompile(`^([\pL\pM\pN_]+|\[\*\])\s+-->\s+([\pL\pM\pN_]+|\[\*\])(?:\s*(:)\s*(.*?))?\s*$`)
+
+	// Transition label pattern: event [guard] / action, each part optional
+	transitionLabelPattern = regexp.MustCompile(`^([^\[/]*?)\s*(?:\[([^\]]*)\])?\s*(?:/\s*(.*))?$`)
 
 	// Special state patterns
-	forkPattern   = regexp.MustCompile(`^state\s+(\w+)\s+<<fork>>\s*$`)
-	joinPattern   = regexp.MustCompile(`^state\s+(\w+)\s+<<join>>\s*$`)
-	choicePattern = regexp.MustCompile(`^state\s+(\w+)\s+<<choice>>\s*$`)
+	forkPattern   = regexp.MustCompile(`^state\s+([\pL\pM\pN_]+)\s+<<fork>>\s*$`)
+	joinPattern   = regexp.MustCompile(`^state\s+([\pL\pM\pN_]+)\s+<<join>>\s*$`)
+	choicePattern = regexp.MustCompile(`^state\s+([\pL\pM\pN_]+)\s+<<choice>>\s*$`)
 
 	// Note patterns
-	stateNotePattern = regexp.MustCompile(`^note\s+(left|right)\s+of\s+(\w+)\s*:\s*(.+)\s*$`)
+	stateNotePattern = regexp.MustCompile(`^note\s+(left|right)\s+of\s+([\pL\pM\pN_]+)\s*:\s*(.+)\s*$`)
 )
 
 // StateParser parses Mermaid state diagrams.
@@ -48,8 +53,9 @@ func (p *StateParser) Parse(source string) (ast.Diagram, error) {
 		return nil, fmt.Errorf("empty diagram")
 	}
 
-	// Parse header
-	header := strings.TrimSpace(lines[0])
+	// Parse header, after any front matter, comments and directives
+	first := headerLine(lines)
+	header := strings.TrimSpace(lines[first])
 	matches := stateHeaderPattern.FindStringSubmatch(header)
 	if matches == nil {
 		return nil, fmt.Errorf("invalid state diagram header: expected 'stateDiagram' or 'stateDiagram-v2'")
@@ -67,14 +73,14 @@ func (p *StateParser) Parse(source string) (ast.Diagram, error) {
 	}
 
 	// Parse statements
-	statements := p.parseStatements(lines[1:], 1)
-	diagram.Statements = statements
+	diagram.Statements, diagram.SkippedLines = p.parseStatements(lines[first+1:], first+1)
 
 	return diagram, nil
 }
 
-func (p *StateParser) parseStatements(lines []string, startLine int) []ast.StateStmt {
+func (p *StateParser) parseStatements(lines []string, startLine int) ([]ast.StateStmt, []ast.SkippedLine) {
 	var statements []ast.StateStmt
+	var skipped []ast.SkippedLine
 	lineNum := startLine
 
 	for i := range lines {
@@ -133,14 +139,26 @@ func (p *StateParser) parseStatements(lines []string, startLine int) []ast.State
 			continue
 		}
 
+		// Handle descriptions, which declare the state if needed; further
+		// descriptions of a state add lines to it
+		if matches := stateDescriptionPattern.FindStringSubmatch(trimmed); matches != nil {
+			if state := findState(statements, matches[1]); state != nil {
+				state.Description = strings.TrimPrefix(state.Description+"\n"+matches[2], "\n")
+				continue
+			}
+			statements = append(statements, &ast.State{
+				ID:          matches[1],
+				Description: matches[2],
+				Pos:         ast.Position{Line: lineNum, Column: 1},
+			})
+			continue
+		}
+
 		// Handle transitions
 		if matches := transitionPattern.FindStringSubmatch(trimmed); matches != nil {
 			from := matches[1]
 			to := matches[2]
-			label := ""
-			if len(matches) > 3 {
-				label = matches[3]
-			}
+			label := matches[4]
 
 			// Handle start state
 			if from == "[*]" {
@@ -161,12 +179,15 @@ func (p *StateParser) parseStatements(lines []string, startLine int) []ast.State
 			}
 
 			// Regular transition
-			statements = append(statements, &ast.Transition{
-				From:  from,
-				To:    to,
-				Label: label,
-				Pos:   ast.Position{Line: lineNum, Column: 1},
-			})
+			transition := &ast.Transition{
+				From:     from,
+				To:       to,
+				Label:    label,
+				HasLabel: matches[3] != "",
+				Pos:      ast.Position{Line: lineNum, Column: 1},
+			}
+			parseTransitionLabel(transition)
+			statements = append(statements, transition)
 			continue
 		}
 
@@ -181,9 +202,36 @@ func (p *StateParser) parseStatements(lines []string, startLine int) []ast.State
 			continue
 		}
 
-		// Skip lines we can't parse
-		continue
+		// Skip lines we can't parse, recording them so they can be reported
+		skipped = append(skipped, ast.SkippedLine{Line: lineNum, Content: trimmed, Reason: "not a recognised state diagram statement"})
 	}
 
-	return statements
+	return statements, skipped
+}
+
+// findState returns the state with the given ID among statements, or nil.
+func findState(statements []ast.StateStmt, id string) *ast.State {
+	for _, stmt := range statements {
+		if state, ok := stmt.(*ast.State); ok && state.ID == id {
+			return state
+		}
+	}
+	return nil
+}
+
+// parseTransitionLabel splits a transition label written as
+// "event [guard] / action" into its parts. Labels that do not follow this
+// form are kept whole as the event.
+func parseTransitionLabel(transition *ast.Transition) {
+	if transition.Label == "" {
+		return
+	}
+	matches := transitionLabelPattern.FindStringSubmatch(transition.Label)
+	if matches == nil {
+		transition.Event = transition.Label
+		return
+	}
+	transition.Event = matches[1]
+	transition.Guard = strings.TrimSpace(matches[2])
+	transition.Action = strings.TrimSpace(matches[3])
 }
@@ -0,0 +1,282 @@
+package parser
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+)
+
+// smartQuotes are typographic quotes that editors and chat tools substitute
+// for straight ones, which Mermaid does not accept as string delimiters.
+const smartQuotes = "“”‘’"
+
+// blockOpenerPattern matches lines that open a block closed by "end".
+var blockOpenerPattern = regexp.MustCompile(`^(subgraph|loop|alt|opt|par|critical|break|rect|box)\b`)
+
+// Arrow patterns used to spot arrows written for another diagram type.
+var (
+	sequenceArrowPattern = regexp.MustCompile(`->>`)
+	singleArrowPattern   = regexp.MustCompile(`(^|[^-])->([^>]|$)`)
+	fatArrowPattern      = regexp.MustCompile(`={1,2}>`)
+)
+
+// Suggest returns a "did you mean" hint for err, an error from parsing source,
+// or "" when it has none. It recognises misspelt diagram headers, blocks
+// missing their "end", arrows written for a different diagram type and smart
+// quotes.
+func Suggest(source string, err error) string {
+	if err == nil {
+		return ""
+	}
+	return suggest(source, toSyntaxError(err))
+}
+
+// suggest returns a hint for a syntax error in source, or "".
+func suggest(source string, err SyntaxError) string {
+	source = inpututil.NormaliseNewlines(source)
+	lines := strings.Split(source, "\n")
+	var line string
+	if err.Line >= 1 && err.Line <= len(lines) {
+		line = strings.TrimSpace(lines[err.Line-1])
+	}
+
+	diagType := detectDiagramType(source)
+	if (diagType == "unknown" || strings.Contains(err.Message, "header")) && !strings.Contains(err.Message, "did you mean") {
+		if header := closestHeader(firstStatement(source)); header != "" {
+			return fmt.Sprintf("did you mean '%s'?", header)
+		}
+	}
+
+	if isUnclosedBlock(err) {
+		if opener, openerLine, closer := unclosedBlock(lines); opener != "" {
+			return fmt.Sprintf("add '%s' to close the '%s' opened on line %d", closer, opener, openerLine)
+		}
+	}
+
+	if hint := arrowHint(diagType, line); hint != "" {
+		return hint
+	}
+
+	if strings.ContainsAny(line, smartQuotes) || (line == "" && strings.ContainsAny(source, smartQuotes)) {
+		return "replace smart quotes (“ ” ‘ ’) with straight quotes"
+	}
+	return ""
+}
+
+// firstStatement returns the first line of source that is neither blank nor a
+// comment, trimmed; detectDiagramType looks for the header there.
+func firstStatement(source string) string {
+	lines := strings.Split(source, "\n")
+	for _, line := range lines[frontMatterEnd(lines):] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "%%") {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// closestHeader returns the known header keyword nearest to the first word of
+// line, if it is close enough to be a plausible misspelling.
+func closestHeader(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	word := fields[0]
+
+	best, bestDistance := "", len(word)
+	for _, mapping := range diagramTypeMapping {
+		distance := editDistance(strings.ToLower(word), strings.ToLower(mapping.prefix))
+		if distance < bestDistance {
+			best, bestDistance = mapping.prefix, distance
+		}
+	}
+	// Allow roughly one edit per four characters; an exact match means the
+	// header is wrong for some other reason
+	if best == word || bestDistance > max(1, len(word)/4) {
+		return ""
+	}
+	return best
+}
+
+// maxHeaderCandidates is the most candidates Detect and HeaderCandidates
+// return.
+const maxHeaderCandidates = 3
+
+// DiagramType is a diagram type as detected from a header, such as
+// "flowchart", "sequence", "c4Context" or a type added with Register.
+type DiagramType string
+
+// UnknownType is the DiagramType of sources without a recognised header.
+const UnknownType DiagramType = "unknown"
+
+// Candidate is a diagram header the first statement of a source may have been
+// meant to be.
+type Candidate struct {
+	Header     string      // Header keyword, as in "sequenceDiagram"
+	Type       DiagramType // Type the header introduces
+	Distance   int         // Edits needed to turn what was written into Header
+	Confidence float64     // Between 0 and 1, higher for closer matches
+}
+
+// Detect returns the type Parse would detect for source from its header, as
+// DetectType does. When the header is not recognised it returns UnknownType
+// with the headers it may have been meant to be, most likely first, for
+// editors offering "did you mean" fixes; the candidates are nil otherwise.
+func Detect(source string) (DiagramType, []Candidate) {
+	source = inpututil.NormaliseNewlines(source)
+	if diagType := detectDiagramType(source); diagType != string(UnknownType) {
+		return DiagramType(diagType), nil
+	}
+	return UnknownType, headerCandidates(firstStatement(source))
+}
+
+// HeaderCandidates returns the known diagram headers the first statement of
+// source may have been meant to be, closest first, when it does not start with
+// one. It returns nil when the header is recognised or none is close.
+func HeaderCandidates(source string) []string {
+	_, candidates := Detect(source)
+	var headers []string
+	for _, c := range candidates {
+		headers = append(headers, c.Header)
+	}
+	return headers
+}
+
+// headerCandidates returns the headers, built-in or registered, close to the
+// first word of statement, or its first two words run together as in
+// "sequence diagram".
+func headerCandidates(statement string) []Candidate {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return nil
+	}
+	words := []string{fields[0]}
+	if len(fields) > 1 {
+		words = append(words, fields[0]+fields[1])
+	}
+
+	headers := make(map[string]DiagramType)
+	order := Registered()
+	for _, header := range order {
+		headers[header] = DiagramType(header)
+	}
+	for _, mapping := range diagramTypeMapping {
+		if _, ok := headers[mapping.prefix]; !ok {
+			headers[mapping.prefix] = DiagramType(mapping.typeID)
+			order = append(order, mapping.prefix)
+		}
+	}
+
+	var candidates []Candidate
+	for _, header := range order {
+		best, bestLength := -1, 0
+		for _, word := range words {
+			distance := editDistance(strings.ToLower(word), strings.ToLower(header))
+			if distance <= max(2, len(word)/3) && (best < 0 || distance < best) {
+				best, bestLength = distance, max(len(word), len(header))
+			}
+		}
+		if best >= 0 {
+			candidates = append(candidates, Candidate{
+				Header:     header,
+				Type:       headers[header],
+				Distance:   best,
+				Confidence: 1 - float64(best)/float64(bestLength),
+			})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b Candidate) int {
+		return cmp.Or(cmp.Compare(a.Distance, b.Distance), cmp.Compare(b.Confidence, a.Confidence))
+	})
+	return candidates[:min(len(candidates), maxHeaderCandidates)]
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+// unclosedBlock returns the opening line and line number (1-indexed) of the
+// innermost block left open at the end of lines, with the token that closes
+// it: "end", or "}" for a line ending in "{".
+func unclosedBlock(lines []string) (opener string, line int, closer string) {
+	type block struct {
+		opener string
+		line   int
+		brace  bool
+	}
+	var stack []block
+	for i, text := range lines {
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "end":
+			if len(stack) > 0 && !stack[len(stack)-1].brace {
+				stack = stack[:len(stack)-1]
+			}
+		case trimmed == "}":
+			if len(stack) > 0 && stack[len(stack)-1].brace {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.HasSuffix(trimmed, "{"):
+			stack = append(stack, block{trimmed, i + 1, true})
+		default:
+			if match := blockOpenerPattern.FindString(trimmed); match != "" {
+				stack = append(stack, block{match, i + 1, false})
+			}
+		}
+	}
+	if len(stack) == 0 {
+		return "", 0, ""
+	}
+	last := stack[len(stack)-1]
+	if last.brace {
+		return last.opener, last.line, "}"
+	}
+	return last.opener, last.line, "end"
+}
+
+// arrowHint suggests the right arrow when line uses one from another diagram
+// type.
+func arrowHint(diagType, line string) string {
+	switch diagType {
+	case "flowchart", "graph":
+		if sequenceArrowPattern.MatchString(line) {
+			return "flowcharts link nodes with '-->'; '->>' is a sequence diagram arrow"
+		}
+		if singleArrowPattern.MatchString(line) {
+			return "flowcharts link nodes with '-->', not '->'"
+		}
+	case "state", "stateDiagram-v2", "class":
+		if sequenceArrowPattern.MatchString(line) || singleArrowPattern.MatchString(line) {
+			return "use '-->' between states and classes"
+		}
+	case "sequence":
+		if fatArrowPattern.MatchString(line) {
+			return "sequence messages use '->>' (solid) or '-->>' (dotted), for example 'A->>B: text'"
+		}
+	}
+	return ""
+}
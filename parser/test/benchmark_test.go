@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/sammcj/mermaid-check/ast"
 	"github.com/sammcj/mermaid-check/parser"
 )
 
@@ -182,14 +183,22 @@ func BenchmarkParseLargeFlowchart(b *testing.B) {
 	}
 }
 
-// NOTE: BenchmarkDetectDiagramType is commented out because detectDiagramType is an unexported function
-// and this file uses black-box testing (package parser_test).
-// This benchmark should be moved to a white-box test file if needed.
-/*
-func BenchmarkDetectDiagramType(b *testing.B) {
+func BenchmarkParseIncrementalLargeFlowchart(b *testing.B) {
+	prev := parser.NewResult(benchmarkLargeFlowchart)
+	// Type a character at the end of the 50th link
+	edit := parser.Range{Start: ast.Position{Line: 51, Column: 20}, End: ast.Position{Line: 51, Column: 20}}
 	b.ReportAllocs()
 	for b.Loop() {
-		_ = detectDiagramType(benchmarkFlowchart)
+		result, err := parser.ParseIncremental(prev, edit, "x")
+		if err != nil || !result.Incremental {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDetect(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = parser.Detect(benchmarkFlowchart)
 	}
 }
-*/
@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -157,6 +159,54 @@ func TestC4ContextParser(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "context with named style parameters",
+			input: `C4Context
+    System(sys1, "System 1")
+    System(sys2, "System 2")
+    UpdateElementStyle(sys1, $fontColor="white", $bgColor="#1168bd")
+    UpdateRelStyle(sys1, sys2, $textColor="blue", $offsetY="-10")`,
+			wantErr: false,
+			check: func(t *testing.T, d *ast.C4Diagram) {
+				if len(d.Styles) != 2 {
+					t.Fatalf("expected 2 styles, got %d", len(d.Styles))
+				}
+				elemStyle := d.Styles[0]
+				if elemStyle.FontColor != "white" || elemStyle.BgColor != "#1168bd" {
+					t.Errorf("expected font white on #1168bd, got %q on %q", elemStyle.FontColor, elemStyle.BgColor)
+				}
+				relStyle := d.Styles[1]
+				if relStyle.TextColor != "blue" || relStyle.OffsetY != "-10" || relStyle.LineColor != "" {
+					t.Errorf("unexpected rel style %+v", relStyle)
+				}
+			},
+		},
+		{
+			name: "context with layout directives and short relationship forms",
+			input: `C4Context
+    System(sys1, "System 1")
+    System(sys2, "System 2")
+    Rel_D(sys1, sys2, "Calls")
+    Rel_L(sys2, sys1, "Replies")
+    UpdateLayoutConfig($c4ShapeInRow="3", $c4BoundaryInRow="1")
+    LAYOUT_WITH_LEGEND()`,
+			wantErr: false,
+			check: func(t *testing.T, d *ast.C4Diagram) {
+				if len(d.Relationships) != 2 || d.Relationships[0].RelType != "Rel_D" || d.Relationships[1].RelType != "Rel_L" {
+					t.Errorf("unexpected relationships %+v", d.Relationships)
+				}
+				if len(d.Layouts) != 2 {
+					t.Fatalf("expected 2 layout directives, got %d", len(d.Layouts))
+				}
+				config := d.Layouts[0]
+				if config.Directive != "UpdateLayoutConfig" || config.ShapeInRow != "3" || config.BoundaryInRow != "1" {
+					t.Errorf("unexpected layout config %+v", config)
+				}
+				if d.Layouts[1].Directive != "LAYOUT_WITH_LEGEND" || d.Layouts[1].Pos.Line != 7 {
+					t.Errorf("unexpected layout directive %+v", d.Layouts[1])
+				}
+			},
+		},
 		{
 			name: "context with comments",
 			input: `C4Context
@@ -435,6 +485,24 @@ func TestC4DynamicParser(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "dynamic diagram with explicit indexes",
+			input: `C4Dynamic
+    Person(user, "User")
+    System(auth, "Auth Service")
+    RelIndex(2, auth, user, "Login response")
+    RelIndex(1, user, auth, "Login request", "HTTPS")`,
+			wantErr: false,
+			check: func(t *testing.T, d *ast.C4Diagram) {
+				if len(d.Relationships) != 2 {
+					t.Fatalf("expected 2 relationships, got %d", len(d.Relationships))
+				}
+				rel := d.Relationships[1]
+				if rel.RelType != "RelIndex" || rel.Index != "1" || rel.From != "user" || rel.To != "auth" || rel.Technology != "HTTPS" {
+					t.Errorf("unexpected relationship %+v", rel)
+				}
+			},
+		},
 		{
 			name:    "invalid header",
 			input:   "C4Context\n    System(s1, \"System 1\")",
@@ -548,6 +616,81 @@ func TestC4DeploymentParser(t *testing.T) {
 // NOTE: TestParseC4Parameters is commented out because parseC4Parameters is an unexported function
 // and this file uses black-box testing (package parser_test).
 // This test should be moved to a white-box test file if needed.
+func TestC4Parser_ParseOfficialExamples(t *testing.T) {
+	parsers := map[string]parser.DiagramParser{
+		"context.mmd":    parser.NewC4ContextParser(),
+		"container.mmd":  parser.NewC4ContainerParser(),
+		"component.mmd":  parser.NewC4ComponentParser(),
+		"dynamic.mmd":    parser.NewC4DynamicParser(),
+		"deployment.mmd": parser.NewC4DeploymentParser(),
+	}
+
+	for name, p := range parsers {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("../../testdata/c4", name)) //nolint:gosec // Test file paths are safe
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+
+			diagram, err := p.Parse(string(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			c4Diagram, ok := diagram.(*ast.C4Diagram)
+			if !ok {
+				t.Fatalf("expected *ast.C4Diagram, got %T", diagram)
+			}
+			if len(c4Diagram.Relationships) == 0 {
+				t.Error("expected relationships to be parsed")
+			}
+		})
+	}
+}
+
+func TestC4Parser_ElementVariants(t *testing.T) {
+	input := `C4Container
+    SystemDb(sdb, "System DB")
+    SystemQueue_Ext(sq, "System Queue", "Queues things")
+    Container_Ext(c, "Container", "Go")
+    ContainerQueue_Ext(cq, "Queue", "Kafka")
+    Component_Ext(comp, "Component", "Go", $tags="v1.0")
+    ComponentDb(cdb, "Component DB", "SQL", "Calls isAuthenticated() on")`
+
+	diagram, err := parser.NewC4ContainerParser().Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := diagram.(*ast.C4Diagram)
+
+	want := []struct {
+		elementType               string
+		external, database, queue bool
+	}{
+		{"System", false, true, false},
+		{"System", true, false, true},
+		{"Container", true, false, false},
+		{"Container", true, false, true},
+		{"Component", true, false, false},
+		{"Component", false, true, false},
+	}
+	if len(d.Elements) != len(want) {
+		t.Fatalf("expected %d elements, got %d", len(want), len(d.Elements))
+	}
+	for i, w := range want {
+		e := d.Elements[i]
+		if e.ElementType != w.elementType || e.External != w.external || e.Database != w.database || e.Queue != w.queue {
+			t.Errorf("element %s: got %s external=%v database=%v queue=%v", e.ID, e.ElementType, e.External, e.Database, e.Queue)
+		}
+	}
+	if d.Elements[4].Tags != "v1.0" || d.Elements[4].Description != "" {
+		t.Errorf("expected named tags parameter, got %+v", d.Elements[4])
+	}
+	if d.Elements[5].Description != "Calls isAuthenticated() on" {
+		t.Errorf("expected description with parentheses, got %q", d.Elements[5].Description)
+	}
+}
+
 /*
 func TestParseC4Parameters(t *testing.T) {
 	tests := []struct {
@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -115,3 +117,99 @@ func TestClassParser_Notes(t *testing.T) {
 		}
 	}
 }
+
+func TestClassParser_Methods(t *testing.T) {
+	src := "classDiagram\n" +
+		"    class Animal {\n" +
+		"        +name String\n" +
+		"        +eat() void\n" +
+		"        +move(int speed) void\n" +
+		"    }"
+	d, err := parser.NewClassParser().Parse(src)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	class, ok := d.(*ast.ClassDiagram).Statements[0].(*ast.Class)
+	if !ok || len(class.Members) != 3 {
+		t.Fatalf("Parse() statements = %+v, want one class with 3 members", d.(*ast.ClassDiagram).Statements)
+	}
+	for i, want := range []bool{false, true, true} {
+		if got := class.Members[i].IsMethod; got != want {
+			t.Errorf("member %q IsMethod = %v, want %v", class.Members[i].Name, got, want)
+		}
+	}
+}
+
+func TestClassParser_RelationshipTypes(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"Animal <|-- Dog", "inheritance"},
+		{"Dog --|> Animal", "inheritance"},
+		{"Shape <|.. Circle", "realization"},
+		{"Car *-- Wheel", "composition"},
+		{"Pond o-- Duck", "aggregation"},
+		{"A --> B", "association"},
+		{"A ..> B", "dependency"},
+	}
+
+	p := parser.NewClassParser()
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			diagram, err := p.Parse("classDiagram\n    " + tt.line)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			classDiagram := diagram.(*ast.ClassDiagram)
+			if len(classDiagram.Statements) != 1 {
+				t.Fatalf("got %d statements, want 1 (skipped: %+v)", len(classDiagram.Statements), classDiagram.SkippedLines)
+			}
+			relationship, ok := classDiagram.Statements[0].(*ast.Relationship)
+			if !ok || relationship.Type != tt.want {
+				t.Errorf("statement = %+v, want a %s relationship", classDiagram.Statements[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestClassParser_SkippedLines(t *testing.T) {
+	diagram, err := parser.NewClassParser().Parse("classDiagram\n    class Animal\n    Animal ==> Duck\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	skipped := diagram.(*ast.ClassDiagram).SkippedLines
+	if len(skipped) != 1 || skipped[0].Line != 3 || skipped[0].Content != "Animal ==> Duck" {
+		t.Errorf("SkippedLines = %+v, want line 3", skipped)
+	}
+}
+
+func TestClassParser_MemberStatements(t *testing.T) {
+	source := "classDiagram\n    Animal : +int age\n    Animal: +isMammal()\n    Dog : +bark()\n    class Dog {\n        +String name\n    }\n    class Animal <<abstract>>\n"
+	diagram, err := parser.NewClassParser().Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	classDiagram := diagram.(*ast.ClassDiagram)
+	if len(classDiagram.SkippedLines) > 0 {
+		t.Errorf("SkippedLines = %+v", classDiagram.SkippedLines)
+	}
+
+	// Later class statements complete the classes member statements declared
+	var got []string
+	for _, stmt := range classDiagram.Statements {
+		class, ok := stmt.(*ast.Class)
+		if !ok {
+			continue
+		}
+		var members []string
+		for _, member := range class.Members {
+			members = append(members, member.Name)
+		}
+		got = append(got, fmt.Sprintf("%s<%s>%v", class.Name, class.Stereotype, members))
+	}
+	want := []string{"Animal<abstract>[int isMammal]", "Dog<>[bark String]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("classes = %q, want %q", got, want)
+	}
+}
@@ -125,6 +125,27 @@ func TestERParser_Parse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "entity with keys separated by a comma and space",
+			source: `erDiagram
+    LINE-ITEM {
+        int orderID PK, FK "Order it belongs to"
+    }`,
+			wantErr: false,
+			check: func(t *testing.T, d ast.Diagram) {
+				er, ok := d.(*ast.ERDiagram)
+				if !ok {
+					t.Fatalf("expected *ast.ERDiagram, got %T", d)
+				}
+				attr := er.Entities[0].Attributes[0]
+				if len(attr.Keys) != 2 || attr.Keys[0] != "PK" || attr.Keys[1] != "FK" {
+					t.Errorf("expected Keys [PK FK], got %v", attr.Keys)
+				}
+				if attr.Comment != "Order it belongs to" {
+					t.Errorf("expected comment 'Order it belongs to', got %q", attr.Comment)
+				}
+			},
+		},
 		{
 			name: "entity with attribute comments",
 			source: `erDiagram
@@ -3,6 +3,9 @@ package parser_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -45,6 +48,102 @@ func TestParseSimple(t *testing.T) {
 	}
 }
 
+func TestParseWithoutDirection(t *testing.T) {
+	for _, source := range []string{"flowchart\n    A --> B", "graph\n    A --> B"} {
+		d, err := parser.NewFlowchartParser().Parse(source)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", source, err)
+		}
+		if diagram := d.(*ast.Flowchart); diagram.Direction != "" {
+			t.Errorf("expected no direction for %q, got %q", source, diagram.Direction)
+		}
+	}
+}
+
+func TestParseLinkTextInArrow(t *testing.T) {
+	tests := []struct {
+		line  string
+		arrow string
+		label string
+	}{
+		{"A -- yes --> B", "-->", "yes"},
+		{"A-- open link ---B", "---", "open link"},
+		{"A -. maybe .-> B", "-.->", "maybe"},
+		{"A -. dotted .- B", "-.-", "dotted"},
+		{"A == must ==> B", "==>", "must"},
+		{"A == thick === B", "===", "thick"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			d, err := parser.NewFlowchartParser().Parse("flowchart LR\n    " + tt.line)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			statements := d.(*ast.Flowchart).Statements
+			if len(statements) != 1 {
+				t.Fatalf("expected 1 statement, got %+v", statements)
+			}
+			link, ok := statements[0].(*ast.Link)
+			if !ok {
+				t.Fatalf("expected *ast.Link, got %T", statements[0])
+			}
+			if link.From != "A" || link.To != "B" || link.Arrow != tt.arrow || link.Label != tt.label {
+				t.Errorf("got %+v, want A %s B labelled %q", link, tt.arrow, tt.label)
+			}
+		})
+	}
+
+	// Inline node definitions and mismatched arrow halves
+	d, err := parser.NewFlowchartParser().Parse("flowchart LR\n    A[Start] -- go --> B{Check}\n    C -- nope ==> D")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if statements := d.(*ast.Flowchart).Statements; len(statements) != 3 {
+		t.Errorf("expected node, link and node statements only, got %+v", statements)
+	}
+}
+
+func TestParseLinkLength(t *testing.T) {
+	tests := []struct {
+		line   string
+		arrow  string
+		length int
+	}{
+		{"A --> B", "-->", 1},
+		{"A ---> B", "--->", 2},
+		{"A -----> B", "----->", 4},
+		{"A --- B", "---", 1},
+		{"A ---- B", "----", 2},
+		{"A ===> B", "===>", 2},
+		{"A -..-> B", "-..->", 2},
+		{"A ~~~ B", "~~~", 1},
+		{"A ~~~~ B", "~~~~", 2},
+		{"A <---> B", "<--->", 2},
+		{"A -- text ---> B", "--->", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			d, err := parser.NewFlowchartParser().Parse("flowchart LR\n    " + tt.line)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			statements := d.(*ast.Flowchart).Statements
+			if len(statements) != 1 {
+				t.Fatalf("expected 1 statement, got %+v", statements)
+			}
+			link, ok := statements[0].(*ast.Link)
+			if !ok {
+				t.Fatalf("expected *ast.Link, got %T", statements[0])
+			}
+			if link.Arrow != tt.arrow || link.Length != tt.length {
+				t.Errorf("got arrow %q with length %d, want %q with length %d", link.Arrow, link.Length, tt.arrow, tt.length)
+			}
+		})
+	}
+}
+
 func TestParseTestDataFiles(t *testing.T) {
 	p := parser.NewFlowchartParser()
 
@@ -76,6 +175,43 @@ func TestParseTestDataFiles(t *testing.T) {
 	}
 }
 
+func TestParseSubgraphDirection(t *testing.T) {
+	src := "flowchart TD\n subgraph outer[Outer]\n  direction LR\n  subgraph inner\n   direction BT\n   a --> b\n  end\n  c --> d\n end\n subgraph plain\n  e --> f\n end"
+
+	d, err := parser.NewFlowchartParser().Parse(src)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	fc := d.(*ast.Flowchart)
+
+	var subgraphs []*ast.Subgraph
+	for _, s := range fc.Statements {
+		if g, ok := s.(*ast.Subgraph); ok {
+			subgraphs = append(subgraphs, g)
+		}
+	}
+	if len(subgraphs) != 2 {
+		t.Fatalf("expected 2 top-level subgraphs, got %d", len(subgraphs))
+	}
+
+	outer := subgraphs[0]
+	if outer.ID != "outer" || outer.Direction != "LR" {
+		t.Errorf("outer subgraph = %q direction %q, want outer direction LR", outer.ID, outer.Direction)
+	}
+	var inner *ast.Subgraph
+	for _, s := range outer.Statements {
+		if g, ok := s.(*ast.Subgraph); ok {
+			inner = g
+		}
+	}
+	if inner == nil || inner.ID != "inner" || inner.Direction != "BT" {
+		t.Errorf("inner subgraph = %+v, want inner direction BT", inner)
+	}
+	if subgraphs[1].Direction != "" {
+		t.Errorf("plain subgraph direction = %q, want empty", subgraphs[1].Direction)
+	}
+}
+
 func TestParseSubgraphTitle(t *testing.T) {
 	p := parser.NewFlowchartParser()
 	tests := []struct {
@@ -117,3 +253,270 @@ func TestParseSubgraphTitle(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStyle(t *testing.T) {
+	source := "flowchart LR\n    A --> B\n    style A fill:#f9f,stroke:#333,color:#fff"
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var style *ast.Style
+	for _, stmt := range diagram.(*ast.Flowchart).Statements {
+		if s, ok := stmt.(*ast.Style); ok {
+			style = s
+		}
+	}
+	if style == nil {
+		t.Fatal("expected a style statement")
+	}
+	if style.NodeID != "A" || style.Pos.Line != 3 {
+		t.Errorf("style = %+v, want node A on line 3", style)
+	}
+	for property, want := range map[string]string{"fill": "#f9f", "stroke": "#333", "color": "#fff"} {
+		if got := style.Styles[property]; got != want {
+			t.Errorf("Styles[%q] = %q, want %q", property, got, want)
+		}
+	}
+}
+
+func TestParseNodeLists(t *testing.T) {
+	tests := []struct {
+		name          string
+		line          string
+		wantIDs       []string
+		wantColumns   []int
+		wantClassName string
+	}{
+		{"comma-separated class", "class A,B,C important", []string{"A", "B", "C"}, []int{11, 13, 15}, "important"},
+		{"space-separated class", "class A B important", []string{"A", "B"}, []int{11, 13}, "important"},
+		{"ampersand-joined class", "class A & B important", []string{"A", "B"}, []int{11, 15}, "important"},
+		{"mixed separators class", "class A, B &C important", []string{"A", "B", "C"}, []int{11, 14, 17}, "important"},
+		{"comma-separated style", "style A,B fill:#f9f", []string{"A", "B"}, []int{11, 13}, ""},
+		{"ampersand-joined style", "style A & B fill:#f9f", []string{"A", "B"}, []int{11, 15}, ""},
+		{"space-separated style", "style A B fill:#f9f,color:#000", []string{"A", "B"}, []int{11, 13}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := "flowchart LR\n    A --> B --> C\n    classDef important fill:#f96\n    " + tt.line
+			diagram, err := parser.Parse(source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var ids []string
+			var columns []int
+			var className string
+			for _, stmt := range diagram.(*ast.Flowchart).Statements {
+				switch s := stmt.(type) {
+				case *ast.ClassAssignment:
+					ids, className = s.NodeIDs, s.ClassName
+					for i := range s.NodeIDs {
+						if pos := s.NodePosition(i); pos.Line == 4 {
+							columns = append(columns, pos.Column)
+						}
+					}
+				case *ast.Style:
+					if s.Styles["fill"] != "#f9f" {
+						t.Errorf("style for %s = %v, want fill #f9f", s.NodeID, s.Styles)
+					}
+					ids = append(ids, s.NodeID)
+					columns = append(columns, s.Pos.Column)
+				}
+			}
+			if !slices.Equal(ids, tt.wantIDs) || !slices.Equal(columns, tt.wantColumns) {
+				t.Errorf("node IDs %v at columns %v, want %v at %v", ids, columns, tt.wantIDs, tt.wantColumns)
+			}
+			if className != tt.wantClassName {
+				t.Errorf("class name = %q, want %q", className, tt.wantClassName)
+			}
+		})
+	}
+}
+
+func TestParseDisplayLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantLabel string
+		wantText  string
+	}{
+		{"plain", "flowchart LR\n    A[Start]", "Start", "Start"},
+		{"quoted", "flowchart LR\n    A[\"Start (here)\"]", `"Start (here)"`, "Start (here)"},
+		{"entity codes", "flowchart LR\n    A[\"say #quot;hi#quot; #35;1\"]", `"say #quot;hi#quot; #35;1"`, `say "hi" #1`},
+		{"inline on link", "flowchart LR\n    A[&lt;in&gt;] --> B", "&lt;in&gt;", "<in>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			for _, stmt := range diagram.(*ast.Flowchart).Statements {
+				if node, ok := stmt.(*ast.NodeDef); ok && node.ID == "A" {
+					if node.Label != tt.wantLabel || node.DisplayLabel != tt.wantText {
+						t.Errorf("Label = %q, DisplayLabel = %q, want %q and %q", node.Label, node.DisplayLabel, tt.wantLabel, tt.wantText)
+					}
+					return
+				}
+			}
+			t.Fatal("node A not found")
+		})
+	}
+}
+
+func TestParseNodeIDsWithSeparators(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantFrom  string
+		wantTo    string
+		wantArrow string
+	}{
+		{"dotted host names", "a.service --> b.service", "a.service", "b.service", "-->"},
+		{"dashed IDs", "web-1 --> web-2", "web-1", "web-2", "-->"},
+		{"file paths", "src/main.go --> pkg/util.go", "src/main.go", "pkg/util.go", "-->"},
+		{"inline definition", "api-gw[Gateway] --> svc.users(Users)", "api-gw", "svc.users", "-->"},
+		{"dotted arrow without spaces", "A-.->B", "A", "B", "-.->"},
+		{"open link without spaces", "a.b---c.d", "a.b", "c.d", "---"},
+		{"labelled link", "a.b -->|calls| c-d", "a.b", "c-d", "-->"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse("flowchart LR\n    " + tt.line)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			for _, stmt := range diagram.(*ast.Flowchart).Statements {
+				if link, ok := stmt.(*ast.Link); ok {
+					if link.From != tt.wantFrom || link.To != tt.wantTo || link.Arrow != tt.wantArrow {
+						t.Errorf("link = %s %s %s, want %s %s %s", link.From, link.Arrow, link.To, tt.wantFrom, tt.wantArrow, tt.wantTo)
+					}
+					return
+				}
+			}
+			t.Fatal("no link parsed")
+		})
+	}
+}
+
+func TestParseNodeIDsWithSeparators_Statements(t *testing.T) {
+	source := "flowchart LR\n    subgraph api.v1[API]\n        svc.users --> db-1\n    end\n    classDef db fill:#eee\n    class db-1,svc.users db\n    style db-1 fill:#f9f\n    click svc.users \"https://example.com\""
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var subgraphID string
+	var classIDs []string
+	var styled, clicked string
+	for _, stmt := range diagram.(*ast.Flowchart).Statements {
+		switch s := stmt.(type) {
+		case *ast.Subgraph:
+			subgraphID = s.ID
+		case *ast.ClassAssignment:
+			classIDs = s.NodeIDs
+		case *ast.Style:
+			styled = s.NodeID
+		case *ast.Interaction:
+			clicked = s.NodeID
+		}
+	}
+	if subgraphID != "api.v1" {
+		t.Errorf("subgraph ID = %q, want api.v1", subgraphID)
+	}
+	if !slices.Equal(classIDs, []string{"db-1", "svc.users"}) {
+		t.Errorf("class node IDs = %v, want [db-1 svc.users]", classIDs)
+	}
+	if styled != "db-1" || clicked != "svc.users" {
+		t.Errorf("styled %q and clicked %q, want db-1 and svc.users", styled, clicked)
+	}
+}
+
+func TestParseSkippedLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []ast.SkippedLine
+	}{
+		{
+			name:   "nothing skipped",
+			source: "flowchart LR\n    A --> B\n",
+		},
+		{
+			name:   "unrecognised arrow",
+			source: "flowchart LR\n    A --> B\n    B -> C\n",
+			want:   []ast.SkippedLine{{Line: 3, Content: "B -> C", Reason: "looks like a link, but its node IDs, shapes or arrow aren't recognised"}},
+		},
+		{
+			name:   "unrecognised statement in a subgraph",
+			source: "flowchart LR\n    subgraph one\n        A --> B\n        A ; B\n    end\n",
+			want:   []ast.SkippedLine{{Line: 4, Content: "A ; B", Reason: "not a recognised flowchart statement"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagram.(*ast.Flowchart).GetSkippedLines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SkippedLines = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChainedStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		links   []string // "from arrow to" of each link
+		classes []string // "node:class" of each class assignment
+	}{
+		{name: "chained links", line: "A[Start] --> B --> C", links: []string{"A --> B", "B --> C"}},
+		{name: "node groups", line: "A & B --> C & D", links: []string{"A --> C", "A --> D", "B --> C", "B --> D"}},
+		{name: "labelled chain", line: "A -->|yes| B -- no --> C", links: []string{"A --> B", "B --> C"}},
+		{name: "class on a link endpoint", line: "A:::done --> B", links: []string{"A --> B"}, classes: []string{"A:done"}},
+		{name: "class on a node", line: "A:::done", classes: []string{"A:done"}},
+		{name: "shape data", line: `A@{ shape: rect, label: "Start" }`},
+		{name: "shape data on a link endpoint", line: `A@{ shape: rect } --> B`, links: []string{"A --> B"}},
+		{name: "circle heads", line: "A o--o B", links: []string{"A o--o B"}},
+		{name: "cross heads", line: "A x--x B", links: []string{"A x--x B"}},
+		{name: "circle head", line: "A --o B & C", links: []string{"A --o B", "A --o C"}},
+		{name: "cross head", line: "A --x B", links: []string{"A --x B"}},
+		{name: "link style", line: "linkStyle 0,1 stroke:#f00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := parser.Parse("flowchart LR\n    " + tt.line + "\n")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			flowchart := d.(*ast.Flowchart)
+			if len(flowchart.SkippedLines) > 0 {
+				t.Fatalf("SkippedLines = %+v", flowchart.SkippedLines)
+			}
+			var links, classes []string
+			for _, stmt := range flowchart.Statements {
+				switch s := stmt.(type) {
+				case *ast.Link:
+					links = append(links, s.From+" "+s.Arrow+" "+s.To)
+				case *ast.ClassAssignment:
+					classes = append(classes, strings.Join(s.NodeIDs, ",")+":"+s.ClassName)
+				}
+			}
+			if !slices.Equal(links, tt.links) {
+				t.Errorf("links = %q, want %q", links, tt.links)
+			}
+			if !slices.Equal(classes, tt.classes) {
+				t.Errorf("class assignments = %q, want %q", classes, tt.classes)
+			}
+		})
+	}
+}
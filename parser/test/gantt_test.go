@@ -129,6 +129,9 @@ func TestGanttParser_Parse(t *testing.T) {
 				if gantt.Excludes != "weekends" {
 					t.Errorf("expected excludes 'weekends', got %q", gantt.Excludes)
 				}
+				if gantt.ExcludesPos.Line != 4 {
+					t.Errorf("expected excludes on line 4, got %d", gantt.ExcludesPos.Line)
+				}
 				if gantt.TodayMarker != "off" {
 					t.Errorf("expected todayMarker 'off', got %q", gantt.TodayMarker)
 				}
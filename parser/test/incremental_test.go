@@ -0,0 +1,239 @@
+package parser_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+const incrementalFlowchart = "flowchart TD\n" +
+	"    A[Start] --> B{Check}\n" +
+	"    subgraph backend[Backend]\n" +
+	"        B --> C[API]\n" +
+	"        subgraph storage\n" +
+	"            C --> D[(DB)]\n" +
+	"        end\n" +
+	"        C --> E\n" +
+	"    end\n" +
+	"    E --> F\n" +
+	"    classDef hot fill:#f00\n" +
+	"    class F hot\n"
+
+func pos(line, column int) ast.Position {
+	return ast.Position{Line: line, Column: column}
+}
+
+func TestParseIncremental(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          string
+		edit            parser.Range
+		newText         string
+		wantIncremental bool
+	}{
+		{
+			name:            "typing within a line",
+			edit:            parser.Range{Start: pos(2, 12), End: pos(2, 12)},
+			newText:         " here",
+			wantIncremental: true,
+		},
+		{
+			name:    "typing that stops a line defining a node used later",
+			edit:    parser.Range{Start: pos(2, 13), End: pos(2, 13)},
+			newText: " here",
+		},
+		{
+			name:            "redefining a node defined earlier",
+			source:          "flowchart TD\n    A[Start] --> B\n    C --> D\n",
+			edit:            parser.Range{Start: pos(3, 1), End: pos(4, 1)},
+			newText:         "    A[Start] --> D\n",
+			wantIncremental: true,
+		},
+		{
+			name:    "defining a node a later line defines inline",
+			source:  "flowchart TD\n    A --> B\n    C --> D\n    B[Check] --> D\n",
+			edit:    parser.Range{Start: pos(2, 12), End: pos(2, 12)},
+			newText: "[Stop]",
+		},
+		{
+			name:            "adding a line inside a nested subgraph",
+			edit:            parser.Range{Start: pos(6, 26), End: pos(6, 26)},
+			newText:         "\n            D --> G[Cache]",
+			wantIncremental: true,
+		},
+		{
+			name:            "deleting a line",
+			edit:            parser.Range{Start: pos(8, 1), End: pos(9, 1)},
+			wantIncremental: true,
+		},
+		{
+			name:            "replacing several lines",
+			edit:            parser.Range{Start: pos(10, 5), End: pos(11, 27)},
+			newText:         "E -.-> F\n    F --> G\n    click G \"https://example.com\"",
+			wantIncremental: true,
+		},
+		{
+			name:            "appending at the end",
+			edit:            parser.Range{Start: pos(13, 1), End: pos(13, 1)},
+			newText:         "    F --> Z\n",
+			wantIncremental: true,
+		},
+		{
+			name:            "adding an unrecognised line",
+			edit:            parser.Range{Start: pos(10, 1), End: pos(10, 1)},
+			newText:         "    E -> X\n",
+			wantIncremental: true,
+		},
+		{
+			name:            "moving an unrecognised line",
+			source:          "flowchart TD\n    A --> B\n    B -> C\n    C --> D\n",
+			edit:            parser.Range{Start: pos(2, 1), End: pos(2, 1)},
+			newText:         "    Z --> A\n",
+			wantIncremental: true,
+		},
+		{
+			name:            "correcting an unrecognised line",
+			source:          "flowchart TD\n    A --> B\n    B -> C\n    C --> D\n",
+			edit:            parser.Range{Start: pos(3, 7), End: pos(3, 7)},
+			newText:         "-",
+			wantIncremental: true,
+		},
+		{
+			name:    "changing the direction",
+			edit:    parser.Range{Start: pos(1, 11), End: pos(1, 13)},
+			newText: "LR",
+		},
+		{
+			name:    "closing a subgraph early",
+			edit:    parser.Range{Start: pos(4, 1), End: pos(4, 1)},
+			newText: "    end\n",
+		},
+		{
+			name:    "adding a comment",
+			edit:    parser.Range{Start: pos(2, 1), End: pos(2, 1)},
+			newText: "    %% @owner: team\n",
+		},
+		{
+			name:    "sequence diagram",
+			source:  "sequenceDiagram\n    A->>B: Hi\n",
+			edit:    parser.Range{Start: pos(2, 14), End: pos(2, 14)},
+			newText: " there",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			if source == "" {
+				source = incrementalFlowchart
+			}
+			prev := parser.NewResult(source)
+			if prev.Err != nil {
+				t.Fatalf("NewResult() error = %v", prev.Err)
+			}
+			before := parser.NewResult(source)
+
+			got, err := parser.ParseIncremental(prev, tt.edit, tt.newText)
+			if err != nil {
+				t.Fatalf("ParseIncremental() error = %v", err)
+			}
+			if got.Incremental != tt.wantIncremental {
+				t.Errorf("Incremental = %v, want %v", got.Incremental, tt.wantIncremental)
+			}
+
+			want := parser.NewResult(got.Source)
+			if !reflect.DeepEqual(got.Diagram, want.Diagram) || !reflect.DeepEqual(got.Err, want.Err) {
+				t.Errorf("ParseIncremental() diagram differs from a full parse of\n%s", got.Source)
+			}
+			if !reflect.DeepEqual(prev.Diagram, before.Diagram) {
+				t.Error("ParseIncremental() changed the previous diagram")
+			}
+		})
+	}
+}
+
+func TestParseIncrementalChained(t *testing.T) {
+	result := parser.NewResult(incrementalFlowchart)
+	// Type "X --> Y" a character at a time on a new line after line 2
+	result, err := parser.ParseIncremental(result, parser.Range{Start: pos(3, 1), End: pos(3, 1)}, "    \n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range "X --> Y" {
+		result, err = parser.ParseIncremental(result, parser.Range{Start: pos(3, 5+i), End: pos(3, 5+i)}, string(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Incremental {
+			t.Errorf("edit %d was parsed from scratch", i)
+		}
+	}
+
+	want := parser.NewResult(result.Source)
+	if !reflect.DeepEqual(result.Diagram, want.Diagram) {
+		t.Errorf("chained edits differ from a full parse of\n%s", result.Source)
+	}
+}
+
+func TestParseIncrementalInvalidEdit(t *testing.T) {
+	prev := parser.NewResult(incrementalFlowchart)
+	for _, edit := range []parser.Range{
+		{Start: pos(40, 1), End: pos(40, 1)},
+		{Start: pos(2, 1), End: pos(2, 200)},
+		{Start: pos(3, 1), End: pos(2, 1)},
+	} {
+		if _, err := parser.ParseIncremental(prev, edit, "x"); err == nil {
+			t.Errorf("ParseIncremental(%+v) succeeded, want an error", edit)
+		}
+	}
+	if _, err := parser.ParseIncremental(nil, parser.Range{}, ""); err == nil {
+		t.Error("ParseIncremental(nil) succeeded, want an error")
+	}
+}
+
+func TestParseIncrementalDeletingEveryStatement(t *testing.T) {
+	prev := parser.NewResult("flowchart LR\n    A --> B\n")
+	got, err := parser.ParseIncremental(prev, parser.Range{Start: pos(2, 1), End: pos(3, 1)}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := parser.NewResult(got.Source)
+	if !got.Incremental || !reflect.DeepEqual(got.Diagram, want.Diagram) {
+		t.Errorf("ParseIncremental() = %+v, want %+v", got.Diagram, want.Diagram)
+	}
+}
+
+// TestParseIncrementalMatchesParse replaces each line of some flowcharts with
+// each of a set of statements, checking the result against a full parse.
+func TestParseIncrementalMatchesParse(t *testing.T) {
+	sources := []string{
+		incrementalFlowchart,
+		"flowchart TD\n    A[Start] --> B\n    C --> D\n    B[Check] --> D{Done}\n    D --> A\n",
+		"flowchart LR\n    A & B --> C[Join]\n    C --> D:::hot\n    D[Out] --> A[In]\n",
+	}
+	replacements := []string{
+		"", "    A[Start] --> D", "    B[Other]", "    C[C] --> B{B}", "    A --> B --> C[Chain]",
+		"    X --> Y", "    D -> E", "    B & D[D] --> A", "    class A hot",
+	}
+
+	for _, source := range sources {
+		lines := strings.Count(source, "\n")
+		for line := 2; line <= lines; line++ {
+			for _, text := range replacements {
+				prev := parser.NewResult(source)
+				edit := parser.Range{Start: pos(line, 1), End: pos(line+1, 1)}
+				got, err := parser.ParseIncremental(prev, edit, text+"\n")
+				if err != nil {
+					t.Fatalf("ParseIncremental() error = %v", err)
+				}
+				want := parser.NewResult(got.Source)
+				if !reflect.DeepEqual(got.Diagram, want.Diagram) || !reflect.DeepEqual(got.Err, want.Err) {
+					t.Errorf("replacing line %d with %q: ParseIncremental() differs from a full parse of\n%s", line, text, got.Source)
+				}
+			}
+		}
+	}
+}
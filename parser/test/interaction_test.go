@@ -0,0 +1,94 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestParseFlowchartClick(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want ast.Interaction
+	}{
+		{
+			name: "href with tooltip and target",
+			line: `click A href "https://example.com" "Open docs" _blank`,
+			want: ast.Interaction{NodeID: "A", Kind: "href", URL: "https://example.com", Tooltip: "Open docs", Target: "_blank"},
+		},
+		{
+			name: "bare URL",
+			line: `click A "https://example.com"`,
+			want: ast.Interaction{NodeID: "A", Kind: "href", URL: "https://example.com"},
+		},
+		{
+			name: "call with arguments",
+			line: `click A call showDetails("A", 1) "Details"`,
+			want: ast.Interaction{NodeID: "A", Kind: "callback", Callback: "showDetails", Args: `"A", 1`, Tooltip: "Details"},
+		},
+		{
+			name: "bare callback",
+			line: `click A showDetails`,
+			want: ast.Interaction{NodeID: "A", Kind: "callback", Callback: "showDetails"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse("flowchart TD\n    A --> B\n    " + tt.line)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var got *ast.Interaction
+			for _, stmt := range diagram.(*ast.Flowchart).Statements {
+				if interaction, ok := stmt.(*ast.Interaction); ok {
+					got = interaction
+				}
+			}
+			if got == nil {
+				t.Fatal("no interaction statement found")
+			}
+
+			tt.want.Pos = ast.Position{Line: 3, Column: 1}
+			if *got != tt.want {
+				t.Errorf("interaction = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClassInteractions(t *testing.T) {
+	source := `classDiagram
+    class Shape
+    class Circle
+    link Shape "https://example.com/shape" "Shape docs"
+    callback Circle "onCircle" "Circle tooltip"
+    click Shape href "http://example.com" _self`
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var interactions []*ast.Interaction
+	for _, stmt := range diagram.(*ast.ClassDiagram).Statements {
+		if interaction, ok := stmt.(*ast.Interaction); ok {
+			interactions = append(interactions, interaction)
+		}
+	}
+	if len(interactions) != 3 {
+		t.Fatalf("expected 3 interactions, got %d", len(interactions))
+	}
+	if interactions[0].Kind != "href" || interactions[0].URL != "https://example.com/shape" || interactions[0].Tooltip != "Shape docs" {
+		t.Errorf("link = %+v", interactions[0])
+	}
+	if interactions[1].Kind != "callback" || interactions[1].Callback != "onCircle" || interactions[1].NodeID != "Circle" {
+		t.Errorf("callback = %+v", interactions[1])
+	}
+	if interactions[2].Kind != "href" || interactions[2].Target != "_self" {
+		t.Errorf("click = %+v", interactions[2])
+	}
+}
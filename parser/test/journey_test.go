@@ -1,6 +1,7 @@
 package parser_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -81,6 +82,9 @@ func TestJourneyParser_Parse(t *testing.T) {
 				if checkoutTask.Actors[0] != "Customer" || checkoutTask.Actors[1] != "System" {
 					t.Errorf("expected actors [Customer, System], got %v", checkoutTask.Actors)
 				}
+				if want := []string{"Customer", "System", "Warehouse", "Courier"}; !slices.Equal(journey.Actors, want) {
+					t.Errorf("expected actor registry %v, got %v", want, journey.Actors)
+				}
 			},
 		},
 		{
@@ -0,0 +1,140 @@
+package parser_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestParseWithLimits(t *testing.T) {
+	nestedFlowchart := "flowchart TD\n" +
+		strings.Repeat("subgraph S\n", 4) + "A --> B\n" + strings.Repeat("end\n", 4)
+	nestedSequence := "sequenceDiagram\n" +
+		strings.Repeat("loop again\n", 3) + "A->>B: hi\n" + strings.Repeat("end\n", 3)
+	nestedC4 := "C4Context\n" +
+		"Enterprise_Boundary(b0, \"Outer\") {\n  System_Boundary(b1, \"Inner\") {\n    System(s, \"S\")\n  }\n}"
+	deepMindmap := "mindmap\n  root\n    a\n      b\n        c"
+
+	tests := []struct {
+		name        string
+		source      string
+		limits      parser.Limits
+		wantLimit   bool
+		wantMessage string
+	}{
+		{
+			name:   "within default limits",
+			source: "flowchart TD\n    A --> B",
+			limits: parser.DefaultLimits(),
+		},
+		{
+			name:        "too many bytes",
+			source:      "flowchart TD\n    A --> B",
+			limits:      parser.Limits{MaxBytes: 10},
+			wantLimit:   true,
+			wantMessage: "bytes",
+		},
+		{
+			name:        "too many lines",
+			source:      "flowchart TD\n    A --> B\n    B --> C",
+			limits:      parser.Limits{MaxLines: 2},
+			wantLimit:   true,
+			wantMessage: "3 lines",
+		},
+		{
+			name:        "flowchart subgraphs too deep",
+			source:      nestedFlowchart,
+			limits:      parser.Limits{MaxDepth: 3},
+			wantLimit:   true,
+			wantMessage: "line 5: nesting depth 4",
+		},
+		{
+			name:   "flowchart subgraphs at limit",
+			source: nestedFlowchart,
+			limits: parser.Limits{MaxDepth: 4},
+		},
+		{
+			name:        "sequence blocks too deep",
+			source:      nestedSequence,
+			limits:      parser.Limits{MaxDepth: 2},
+			wantLimit:   true,
+			wantMessage: "nesting depth 3",
+		},
+		{
+			name:        "C4 boundaries too deep",
+			source:      nestedC4,
+			limits:      parser.Limits{MaxDepth: 1},
+			wantLimit:   true,
+			wantMessage: "nesting depth 2",
+		},
+		{
+			name:        "mindmap too deep",
+			source:      deepMindmap,
+			limits:      parser.Limits{MaxDepth: 3},
+			wantLimit:   true,
+			wantMessage: "mindmap depth 4",
+		},
+		{
+			name:        "too many nodes",
+			source:      "flowchart TD\n    A --> B\n    B --> C\n    C --> D",
+			limits:      parser.Limits{MaxNodes: 2},
+			wantLimit:   true,
+			wantMessage: "3 nodes",
+		},
+		{
+			name:   "zero limits are unlimited",
+			source: nestedFlowchart,
+			limits: parser.Limits{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.ParseWithLimits(tt.source, tt.limits)
+			if errors.Is(err, parser.ErrLimitExceeded) != tt.wantLimit {
+				t.Fatalf("ParseWithLimits() error = %v, wantLimit %v", err, tt.wantLimit)
+			}
+			if tt.wantLimit && !strings.Contains(err.Error(), tt.wantMessage) {
+				t.Errorf("expected error to mention %q, got %q", tt.wantMessage, err.Error())
+			}
+			if !tt.wantLimit && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseWithLimits_ParseError(t *testing.T) {
+	_, err := parser.ParseWithLimits("not a diagram", parser.DefaultLimits())
+	if err == nil || errors.Is(err, parser.ErrLimitExceeded) {
+		t.Errorf("expected ordinary parse error, got %v", err)
+	}
+}
+
+func TestCountNodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   int
+	}{
+		{"flowchart with subgraph", "flowchart TD\n    subgraph S\n        A --> B\n    end", 2},
+		{"sequence with loop", "sequenceDiagram\n    loop x\n        A->>B: hi\n    end", 2},
+		{"class with members", "classDiagram\n    class A {\n        +int x\n        +run()\n    }", 3},
+		{"pie", "pie\n    \"A\" : 1\n    \"B\" : 2", 2},
+		{"mindmap", "mindmap\n  root\n    a\n    b", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := parser.CountNodes(diagram); got != tt.want {
+				t.Errorf("CountNodes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package parser_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   map[string]string
+	}{
+		{
+			name:   "no annotations",
+			source: "flowchart TD\n    %% plain comment\n    A --> B",
+			want:   nil,
+		},
+		{
+			name:   "key value annotations",
+			source: "flowchart TD\n    %% @owner: platform-team\n    %%@id:ARCH-42\n    A --> B",
+			want:   map[string]string{"owner": "platform-team", "id": "ARCH-42"},
+		},
+		{
+			name:   "bare annotation and repeated key",
+			source: "%% @deprecated\nsequenceDiagram\n    %% @owner: a\n    %% @owner: b\n    A->>B: hi",
+			want:   map[string]string{"deprecated": "", "owner": "b"},
+		},
+		{
+			name:   "frontmatter scalars",
+			source: "---\ntitle: \"Checkout flow\"\nid: ARCH-7\nconfig:\n  theme: dark\n---\nflowchart TD\n    A --> B",
+			want:   map[string]string{"title": "Checkout flow", "id": "ARCH-7"},
+		},
+		{
+			name:   "annotations override frontmatter",
+			source: "---\nid: OLD\n---\nflowchart TD\n    %% @id: NEW",
+			want:   map[string]string{"id": "NEW"},
+		},
+		{
+			name:   "unterminated frontmatter is ignored",
+			source: "---\nid: X\nflowchart TD",
+			want:   nil,
+		},
+		{
+			name:   "annotation syntax outside a comment is ignored",
+			source: "flowchart TD\n    A[@owner: x] --> B",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parser.ExtractMetadata(tt.source)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("ExtractMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Metadata(t *testing.T) {
+	sources := map[string]string{
+		"flowchart": "flowchart TD\n    %% @owner: platform-team\n    A --> B",
+		"sequence":  "sequenceDiagram\n    %% @owner: platform-team\n    A->>B: hi",
+		"pie":       "pie\n    %% @owner: platform-team\n    \"A\" : 1",
+		"c4":        "C4Context\n    %% @owner: platform-team\n    Person(a, \"A\")",
+	}
+
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			diagram, err := parser.Parse(source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := diagram.GetMetadata()["owner"]; got != "platform-team" {
+				t.Errorf("GetMetadata()[owner] = %q, want %q", got, "platform-team")
+			}
+		})
+	}
+}
@@ -251,6 +251,39 @@ func TestMindmapParser_Parse(t *testing.T) {
 	}
 }
 
+func TestMindmapParser_IconsAndClasses(t *testing.T) {
+	source := `mindmap
+  Root
+    Tools
+          ::icon(fa fa-book)
+    ::icon(mdi mdi-hammer)
+    :::urgent large
+    Empty
+    ::icon()`
+
+	diagram, err := parser.NewMindmapParser().Parse(source)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mm := diagram.(*ast.MindmapDiagram)
+	if len(mm.Root.Children) != 2 {
+		t.Fatalf("expected 2 children, got %d", len(mm.Root.Children))
+	}
+
+	tools := mm.Root.Children[0]
+	if tools.Icon != "mdi mdi-hammer" || len(tools.Icons) != 2 {
+		t.Errorf("expected the last of 2 icons to win, got icon %q from %v", tools.Icon, tools.Icons)
+	}
+	if len(tools.Classes) != 2 || tools.Classes[0] != "urgent" || tools.Classes[1] != "large" {
+		t.Errorf("expected classes [urgent large], got %v", tools.Classes)
+	}
+
+	empty := mm.Root.Children[1]
+	if len(empty.Icons) != 1 || empty.Icons[0] != "" {
+		t.Errorf("expected one empty icon, got %v", empty.Icons)
+	}
+}
+
 func TestMindmapParser_SupportedTypes(t *testing.T) {
 	p := parser.NewMindmapParser()
 	types := p.SupportedTypes()
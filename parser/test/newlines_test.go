@@ -0,0 +1,44 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestParse_WindowsLineEndingsAndBOM(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "flowchart with CRLF and subgraph end",
+			source: "flowchart TD\r\n    subgraph S\r\n        A --> B\r\n    end\r\n",
+		},
+		{
+			name:   "sequence with CRLF and block end",
+			source: "sequenceDiagram\r\n    loop Every minute\r\n        A->>B: ping\r\n    end\r\n",
+		},
+		{
+			name:   "class diagram with BOM",
+			source: "\uFEFFclassDiagram\n    class Animal",
+		},
+		{
+			name:   "state diagram with BOM and CRLF",
+			source: "\uFEFFstateDiagram-v2\r\n    [*] --> Idle\r\n    Idle --> [*]\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if _, ok := diagram.(*ast.GenericDiagram); ok {
+				t.Errorf("expected a typed diagram, got %T", diagram)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package parser_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -251,11 +252,118 @@ sequenceDiagram
 	}
 }
 
-// NOTE: TestDetectDiagramType is commented out because detectDiagramType is an unexported function
-// and this file uses black-box testing (package parser_test).
-// This test should be moved to a white-box test file if needed.
-/*
-func TestDetectDiagramType(t *testing.T) {
+func TestParse_MisspeltHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{
+			name:    "lower case sequence header",
+			source:  "sequencediagram\n    A->>B: hi",
+			wantErr: `unknown diagram type "sequencediagram": did you mean "sequenceDiagram"?`,
+		},
+		{
+			name:    "camel case flowchart header",
+			source:  "flowChart TD\n    A --> B",
+			wantErr: `unknown diagram type "flowChart": did you mean "flowchart"?`,
+		},
+		{
+			name:    "lower case state header",
+			source:  "statediagram\n    [*] --> Idle",
+			wantErr: `unknown diagram type "statediagram": did you mean "stateDiagram"?`,
+		},
+		{
+			name:    "header split into two words",
+			source:  "%% comment\nsequence diagram\n    A->>B: hi",
+			wantErr: `unknown diagram type "sequence diagram": did you mean "sequenceDiagram"?`,
+		},
+		{
+			name:    "header followed by noise word",
+			source:  "gantt chart\n    title Plan",
+			wantErr: `unknown diagram type "gantt chart": did you mean "gantt"?`,
+		},
+		{
+			name:    "unrelated header",
+			source:  "unknownDiagram\n    something",
+			wantErr: `unknown or unsupported diagram type "unknown": expected one of: `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.source)
+			if err == nil {
+				t.Fatal("Parse() expected error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %q, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParse_Preamble(t *testing.T) {
+	headers := map[string]string{
+		"flowchart":       "flowchart LR\n    A --> B",
+		"sequence":        "sequenceDiagram\n    A->>B: hi",
+		"class":           "classDiagram\n    class Animal",
+		"stateDiagram-v2": "stateDiagram-v2\n    [*] --> Idle",
+		"er":              "erDiagram\n    CUSTOMER ||--o{ ORDER : places",
+		"gantt":           "gantt\n    title Plan\n    section Build\n    Design :a1, 2024-01-01, 1d",
+		"pie":             "pie\n    \"Dogs\" : 3",
+		"journey":         "journey\n    title Day\n    section Morning\n    Wake: 3: Me",
+		"gitGraph":        "gitGraph\n    commit",
+		"mindmap":         "mindmap\n  root",
+		"timeline":        "timeline\n    2024 : Launch",
+		"sankey":          "sankey-beta\nWeb,Card,10",
+		"quadrantChart":   "quadrantChart\n    x-axis Low --> High\n    y-axis Low --> High\n    Item: [0.3, 0.6]",
+		"xyChart":         "xychart-beta\n    x-axis [a, b]\n    y-axis \"Value\" 0 --> 10\n    bar [1, 2]",
+		"c4Context":       "C4Context\n    Person(user, \"User\")",
+	}
+	preambles := map[string]string{
+		"init directive": "%%{init: {'theme': 'dark'}}%%\n",
+		"front matter":   "---\ntitle: Example\nconfig:\n  theme: dark\n---\n",
+		"comments":       "%% Owned by the platform team\n\n",
+		"all of them":    "---\ntitle: Example\n---\n%%{init: {'theme': 'dark'}}%%\n%% comment\n",
+	}
+
+	for wantType, header := range headers {
+		for name, preamble := range preambles {
+			t.Run(wantType+"/"+name, func(t *testing.T) {
+				diagram, err := parser.Parse(preamble + header)
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if diagram.GetType() != wantType {
+					t.Errorf("GetType() = %q, want %q", diagram.GetType(), wantType)
+				}
+			})
+		}
+	}
+}
+
+func TestParse_PreambleLineNumbers(t *testing.T) {
+	source := "---\ntitle: Example\n---\n%%{init: {'theme': 'dark'}}%%\nflowchart LR\n    A --> B"
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	statements := diagram.(*ast.Flowchart).Statements
+	if len(statements) == 0 {
+		t.Fatal("Parse() returned no statements")
+	}
+	if line := statements[0].GetPosition().Line; line != 6 {
+		t.Errorf("first statement on line %d, want 6", line)
+	}
+
+	_, err = parser.Parse("%%{init: {'theme': 'dark'}}%%\nsankey-beta\nWeb,Web,10")
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("Parse() error = %v, want one on line 3", err)
+	}
+}
+
+func TestDetect(t *testing.T) {
 	tests := []struct {
 		name         string
 		source       string
@@ -271,6 +379,26 @@ func TestDetectDiagramType(t *testing.T) {
 			source:       "graph LR\n    A --> B",
 			expectedType: "graph",
 		},
+		{
+			name:         "flowchart-elk",
+			source:       "flowchart-elk TD\n    A --> B",
+			expectedType: "flowchart",
+		},
+		{
+			name:         "classDiagram-v2",
+			source:       "classDiagram-v2\n    class Animal",
+			expectedType: "class",
+		},
+		{
+			name:         "word starting with a header",
+			source:       "graphic LR\n    A --> B",
+			expectedType: "unknown",
+		},
+		{
+			name:         "header with a suffix",
+			source:       "pie-chart\n    \"A\" : 1",
+			expectedType: "unknown",
+		},
 		{
 			name:         "sequence",
 			source:       "sequenceDiagram\n    Alice->>Bob: Hi",
@@ -400,14 +528,40 @@ func TestDetectDiagramType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := detectDiagramType(tt.source)
-			if got != tt.expectedType {
+			got, _ := parser.Detect(tt.source)
+			if string(got) != tt.expectedType {
 				t.Errorf("expected %q, got %q", tt.expectedType, got)
 			}
 		})
 	}
 }
-*/
+
+func TestParse_HyphenatedHeaders(t *testing.T) {
+	tests := []struct {
+		source       string
+		expectedType string
+	}{
+		{"flowchart-elk TD\n    A --> B", "flowchart"},
+		{"flowchart-elk\n    A --> B", "flowchart"},
+		{"classDiagram-v2\n    class Animal", "class"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if diagram.GetType() != tt.expectedType {
+				t.Errorf("GetType() = %q, want %q", diagram.GetType(), tt.expectedType)
+			}
+		})
+	}
+
+	if _, err := parser.Parse("graph-elk TD\n    A --> B"); err == nil {
+		t.Error("Parse() accepted graph-elk, which Mermaid doesn't")
+	}
+}
 
 func TestParseWithRealFlowchart(t *testing.T) {
 	source := `flowchart TD
@@ -133,6 +133,39 @@ func TestPieParser_Parse(t *testing.T) {
     "Item" : 0`,
 			wantErr: true,
 		},
+		{
+			name: "init directive, title line and precise values",
+			source: `%%{init: {"pie": {"textPosition": 0.5}, "themeVariables": {"pieOuterStrokeWidth": "5px", "primaryColor": "#fff"}}}%%
+pie showData
+    title Key elements in Product X
+    "Calcium" : 42.96
+    "Potassium" : 50.055`,
+			wantErr: false,
+			check: func(t *testing.T, d ast.Diagram) {
+				pie := d.(*ast.PieDiagram)
+				if !pie.ShowData {
+					t.Error("expected ShowData to be true")
+				}
+				if pie.Title != "Key elements in Product X" {
+					t.Errorf("expected title from title line, got %q", pie.Title)
+				}
+				if pie.Config["textPosition"] != "0.5" || pie.Config["pieOuterStrokeWidth"] != "5px" {
+					t.Errorf("unexpected config %v", pie.Config)
+				}
+				if _, ok := pie.Config["primaryColor"]; ok {
+					t.Error("expected non-pie theme variables to be ignored")
+				}
+				if pie.ConfigPos.Line != 1 {
+					t.Errorf("expected config on line 1, got %d", pie.ConfigPos.Line)
+				}
+				if len(pie.DataEntries) != 2 || pie.DataEntries[1].Raw != "50.055" {
+					t.Errorf("unexpected entries %+v", pie.DataEntries)
+				}
+				if pie.DataEntries[0].Pos.Line != 4 {
+					t.Errorf("expected first entry on line 4, got %d", pie.DataEntries[0].Pos.Line)
+				}
+			},
+		},
 		{
 			name: "invalid entry format",
 			source: `pie
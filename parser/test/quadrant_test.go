@@ -265,6 +265,45 @@ func TestQuadrantParser_Parse(t *testing.T) {
     Point A: [0.5, 0.5]`,
 			wantErr: true,
 		},
+		{
+			name: "point styles and classes",
+			source: `quadrantChart
+    x-axis Left --> Right
+    y-axis Bottom --> Top
+    Point A: [0.9, 0.0] radius: 12
+    Point B: [0.6, 0.3] radius: 15, stroke-color: #00ff0f, stroke-width: 5px ,color: #ff33f0
+    Point C:::class1: [0.5, 0.5]
+    classDef class1 color: #109060, radius : 10`,
+			wantErr: false,
+			check: func(t *testing.T, d ast.Diagram) {
+				quad := d.(*ast.QuadrantDiagram)
+				if len(quad.Points) != 3 {
+					t.Fatalf("expected 3 points, got %d", len(quad.Points))
+				}
+				if quad.Points[0].Styles["radius"] != "12" {
+					t.Errorf("expected radius 12, got %v", quad.Points[0].Styles)
+				}
+				b := quad.Points[1].Styles
+				if len(b) != 4 || b["stroke-width"] != "5px" || b["color"] != "#ff33f0" || b["stroke-color"] != "#00ff0f" {
+					t.Errorf("unexpected styles %v", b)
+				}
+				c := quad.Points[2]
+				if c.Name != "Point C" || c.Class != "class1" || c.Styles != nil {
+					t.Errorf("unexpected point %+v", c)
+				}
+				if len(quad.ClassDefs) != 1 || quad.ClassDefs[0].Name != "class1" || quad.ClassDefs[0].Styles["radius"] != "10" {
+					t.Errorf("unexpected class definitions %+v", quad.ClassDefs)
+				}
+			},
+		},
+		{
+			name: "malformed point style",
+			source: `quadrantChart
+    x-axis Left --> Right
+    y-axis Bottom --> Top
+    Point A: [0.5, 0.5] big`,
+			wantErr: true,
+		},
 		{
 			name:    "empty source",
 			source:  "",
@@ -0,0 +1,152 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestParseRecovering(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		wantDiagram bool
+		wantErrors  []parser.SyntaxError
+	}{
+		{
+			name:        "valid diagram",
+			source:      "sequenceDiagram\n    A->>B: hi",
+			wantDiagram: true,
+		},
+		{
+			name:        "multiple errors in sequence diagram",
+			source:      "sequenceDiagram\n    participant A\n    !!bad\n    A->>B: ok\n    ??also bad",
+			wantDiagram: true,
+			wantErrors: []parser.SyntaxError{
+				{Line: 3, Message: "unknown sequence diagram statement: !!bad"},
+				{Line: 5, Message: "unknown sequence diagram statement: ??also bad"},
+			},
+		},
+		{
+			name:        "unclosed subgraph",
+			source:      "flowchart TD\n    A --> B\n    subgraph S\n    C --> D",
+			wantDiagram: true,
+			wantErrors: []parser.SyntaxError{
+				{Line: 3, Message: "unclosed subgraph", Suggestion: "add 'end' to close the 'subgraph' opened on line 3"},
+			},
+		},
+		{
+			name:   "invalid header",
+			source: "flowchart XY\n    A --> B",
+			wantErrors: []parser.SyntaxError{
+				{Message: "invalid diagram header: expected 'flowchart' or 'graph', optionally followed by a direction"},
+			},
+		},
+		{
+			name:   "unrecoverable error after line error",
+			source: "pie\n    bad",
+			wantErrors: []parser.SyntaxError{
+				{Line: 2, Message: "invalid pie entry format: bad"},
+				{Message: "pie chart must have at least one data entry"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, errs := parser.ParseRecovering(tt.source)
+
+			if (diagram != nil) != tt.wantDiagram {
+				t.Fatalf("ParseRecovering() diagram = %v, wantDiagram %v", diagram, tt.wantDiagram)
+			}
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("ParseRecovering() got %d errors %v, want %d", len(errs), errs, len(tt.wantErrors))
+			}
+			for i, want := range tt.wantErrors {
+				if errs[i] != want {
+					t.Errorf("error %d = %+v, want %+v", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRecovering_PartialAST(t *testing.T) {
+	source := "sequenceDiagram\n    participant A\n    !!bad\n    A->>B: ok"
+
+	diagram, errs := parser.ParseRecovering(source)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+
+	seq, ok := diagram.(*ast.SequenceDiagram)
+	if !ok {
+		t.Fatalf("expected *ast.SequenceDiagram, got %T", diagram)
+	}
+	if seq.Source != source {
+		t.Errorf("Source = %q, want original source", seq.Source)
+	}
+	if len(seq.Statements) != 2 {
+		t.Errorf("expected 2 statements in partial AST, got %d", len(seq.Statements))
+	}
+	for _, stmt := range seq.Statements {
+		if stmt.GetPosition().Line == 3 {
+			t.Errorf("partial AST should not contain the failing line")
+		}
+	}
+}
+
+func TestSyntaxError_Error(t *testing.T) {
+	withLine := parser.SyntaxError{Line: 4, Message: "oops"}
+	if got := withLine.Error(); got != "line 4: oops" {
+		t.Errorf("Error() = %q", got)
+	}
+	withoutLine := parser.SyntaxError{Message: "oops"}
+	if got := withoutLine.Error(); got != "oops" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestParseRecovering_UnclosedBlocks(t *testing.T) {
+	t.Run("sequence loop", func(t *testing.T) {
+		source := "sequenceDiagram\n    loop Every minute\n    A->>B: poll\n"
+		diagram, errs := parser.ParseRecovering(source)
+		if len(errs) != 1 || errs[0].Suggestion != "add 'end' to close the 'loop' opened on line 2" {
+			t.Errorf("errors = %+v, want one unclosed block error", errs)
+		}
+		seq, ok := diagram.(*ast.SequenceDiagram)
+		if !ok {
+			t.Fatalf("expected *ast.SequenceDiagram, got %T", diagram)
+		}
+		if seq.Source != source {
+			t.Errorf("Source = %q, want original source", seq.Source)
+		}
+		loop, ok := seq.Statements[0].(*ast.Loop)
+		if len(seq.Statements) != 1 || !ok || len(loop.Statements) != 1 {
+			t.Errorf("Statements = %+v, want the loop with its message", seq.Statements)
+		}
+	})
+
+	t.Run("nested sequence blocks", func(t *testing.T) {
+		diagram, errs := parser.ParseRecovering("sequenceDiagram\n    loop Outer\n    opt Inner\n    A->>B: poll\n")
+		if diagram == nil || len(errs) != 1 {
+			t.Errorf("ParseRecovering() = %v, %+v, want a diagram and one error", diagram, errs)
+		}
+	})
+
+	t.Run("class body", func(t *testing.T) {
+		diagram, errs := parser.ParseRecovering("classDiagram\n    class Animal {\n        +int age\n")
+		if len(errs) != 1 {
+			t.Errorf("errors = %+v, want one unclosed class body error", errs)
+		}
+		classDiagram, ok := diagram.(*ast.ClassDiagram)
+		if !ok {
+			t.Fatalf("expected *ast.ClassDiagram, got %T", diagram)
+		}
+		class, ok := classDiagram.Statements[0].(*ast.Class)
+		if !ok || class.Name != "Animal" || len(class.Members) != 1 {
+			t.Errorf("Statements = %+v, want Animal with its member", classDiagram.Statements)
+		}
+	})
+}
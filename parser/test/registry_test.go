@@ -0,0 +1,104 @@
+package parser_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+// wardleyParser is a stand-in for an application's own parser.
+type wardleyParser struct{}
+
+func (p *wardleyParser) Parse(source string) (ast.Diagram, error) {
+	return ast.NewGenericDiagram("wardley-test", source, ast.Position{Line: 1, Column: 1}), nil
+}
+
+func (p *wardleyParser) SupportedTypes() []string { return []string{"wardley-test"} }
+
+func newWardleyParser() parser.DiagramParser { return &wardleyParser{} }
+
+// countingParser records how many diagrams it has parsed, so that tests can
+// tell whether parsers are shared.
+type countingParser struct{ parsed int }
+
+func (p *countingParser) Parse(source string) (ast.Diagram, error) {
+	p.parsed++
+	if p.parsed > 1 {
+		return nil, fmt.Errorf("parser reused for diagram %d", p.parsed)
+	}
+	return ast.NewGenericDiagram("counting-test", source, ast.Position{Line: 1, Column: 1}), nil
+}
+
+func (p *countingParser) SupportedTypes() []string { return []string{"counting-test"} }
+
+func TestRegister(t *testing.T) {
+	parser.Register("wardley-test", newWardleyParser)
+
+	if !slices.Contains(parser.Registered(), "wardley-test") {
+		t.Errorf("Registered() = %v, want it to contain wardley-test", parser.Registered())
+	}
+
+	source := "%% map\nwardley-test\n    component Tea [0.8, 0.2]\n"
+	if got := parser.DetectType(source); got != "wardley-test" {
+		t.Errorf("DetectType() = %q, want wardley-test", got)
+	}
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if diagram.GetType() != "wardley-test" {
+		t.Errorf("GetType() = %q, want wardley-test", diagram.GetType())
+	}
+
+	// Built-in types are unaffected
+	if got := parser.DetectType("flowchart TD\n    A --> B"); got != "flowchart" {
+		t.Errorf("DetectType() = %q, want flowchart", got)
+	}
+
+	// The header must be the whole keyword
+	if got := parser.DetectType("wardley-testing\n    component Tea [0.8, 0.2]\n"); got != "unknown" {
+		t.Errorf("DetectType() = %q, want unknown", got)
+	}
+}
+
+func TestRegister_NewParserPerDiagram(t *testing.T) {
+	parser.Register("counting-test", func() parser.DiagramParser { return &countingParser{} })
+
+	for i := range 3 {
+		if _, err := parser.Parse("counting-test\n    thing"); err != nil {
+			t.Fatalf("Parse() %d error = %v", i+1, err)
+		}
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	parser.Register("panic-test", newWardleyParser)
+
+	tests := []struct {
+		name        string
+		diagramType string
+		parser      func() parser.DiagramParser
+		want        string
+	}{
+		{name: "empty type", diagramType: "", parser: newWardleyParser, want: "invalid diagram type"},
+		{name: "type with space", diagramType: "my type", parser: newWardleyParser, want: "invalid diagram type"},
+		{name: "nil constructor", diagramType: "nil-test", parser: nil, want: "nil"},
+		{name: "duplicate", diagramType: "panic-test", parser: newWardleyParser, want: "twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(r.(string), tt.want) {
+					t.Errorf("panic = %v, want one containing %q", r, tt.want)
+				}
+			}()
+			parser.Register(tt.diagramType, tt.parser)
+		})
+	}
+}
@@ -171,3 +171,164 @@ func TestSequenceParser_Messages(t *testing.T) {
 		})
 	}
 }
+
+func TestSequenceParser_MessageArrows(t *testing.T) {
+	p := parser.NewSequenceParser()
+	for _, arrow := range ast.MessageArrows {
+		t.Run(arrow, func(t *testing.T) {
+			diagram, err := p.Parse("sequenceDiagram\n    A" + arrow + "B: Hi")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			msg, ok := diagram.(*ast.SequenceDiagram).Statements[0].(*ast.Message)
+			if !ok {
+				t.Fatalf("first statement is not a message: %T", diagram.(*ast.SequenceDiagram).Statements[0])
+			}
+			if msg.Arrow != arrow || msg.From != "A" || msg.To != "B" {
+				t.Errorf("message = %s %s %s, want A %s B", msg.From, msg.Arrow, msg.To, arrow)
+			}
+		})
+	}
+}
+
+func TestSequenceParser_MessageParts(t *testing.T) {
+	p := parser.NewSequenceParser()
+
+	tests := []struct {
+		name string
+		line string
+		want ast.Message
+	}{
+		{"arrow in text", "A->>B: use --> in docs", ast.Message{From: "A", To: "B", Arrow: "->>", Text: "use --> in docs"}},
+		{"longer arrow in text", "A->B: see C-->>D", ast.Message{From: "A", To: "B", Arrow: "->", Text: "see C-->>D"}},
+		{"colon in text", "A-->>B: retry: 3", ast.Message{From: "A", To: "B", Arrow: "-->>", Text: "retry: 3"}},
+		{"no text", "A-xB", ast.Message{From: "A", To: "B", Arrow: "-x"}},
+		{"activation", "A->>+B: call", ast.Message{From: "A", To: "B", Arrow: "->>", Text: "call", Activate: true}},
+		{"deactivation", "B-->>-A: return", ast.Message{From: "B", To: "A", Arrow: "-->>", Text: "return", Deactivate: true}},
+		{"bidirectional", "A<<->>B: sync", ast.Message{From: "A", To: "B", Arrow: "<<->>", Text: "sync"}},
+		{"left-pointing", "B<<-A: request", ast.Message{From: "A", To: "B", Arrow: "->>", Text: "request", Reversed: true}},
+		{"left-pointing dotted", "B <<-- A: reply -> done", ast.Message{From: "A", To: "B", Arrow: "-->>", Text: "reply -> done", Reversed: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := p.Parse("sequenceDiagram\n    " + tt.line)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			msg, ok := diagram.(*ast.SequenceDiagram).Statements[0].(*ast.Message)
+			if !ok {
+				t.Fatalf("first statement is not a message: %T", diagram.(*ast.SequenceDiagram).Statements[0])
+			}
+			got := *msg
+			got.Pos, got.DisplayText = ast.Position{}, "" // Covered by TestSequenceParser_DisplayText
+			if got != tt.want {
+				t.Errorf("message = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSequenceParser_DisplayText(t *testing.T) {
+	diagram, err := parser.NewSequenceParser().Parse("sequenceDiagram\n    A->>B: #quot;hi#quot; &amp; #35;1")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	msg := diagram.(*ast.SequenceDiagram).Statements[0].(*ast.Message)
+	if want := "#quot;hi#quot; &amp; #35;1"; msg.Text != want {
+		t.Errorf("Text = %q, want %q", msg.Text, want)
+	}
+	if want := `"hi" & #1`; msg.DisplayText != want {
+		t.Errorf("DisplayText = %q, want %q", msg.DisplayText, want)
+	}
+}
+
+func TestSequenceParser_Autonumber(t *testing.T) {
+	p := parser.NewSequenceParser()
+
+	tests := []struct {
+		name        string
+		line        string
+		wantEnabled bool
+		wantStart   int
+		wantStep    int
+	}{
+		{"bare", "autonumber", true, 1, 1},
+		{"start", "autonumber 10", true, 10, 1},
+		{"start and step", "autonumber 10 5", true, 10, 5},
+		{"off", "autonumber off", false, 1, 1},
+		{"invalid arguments keep defaults", "autonumber ten", true, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := p.Parse("sequenceDiagram\n    " + tt.line + "\n    A->>B: Hi")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			autonumber, ok := diagram.(*ast.SequenceDiagram).Statements[0].(*ast.Autonumber)
+			if !ok {
+				t.Fatalf("expected *ast.Autonumber, got %T", diagram.(*ast.SequenceDiagram).Statements[0])
+			}
+			if autonumber.Enabled != tt.wantEnabled || autonumber.Start != tt.wantStart || autonumber.Step != tt.wantStep {
+				t.Errorf("got enabled=%v start=%d step=%d, want enabled=%v start=%d step=%d",
+					autonumber.Enabled, autonumber.Start, autonumber.Step, tt.wantEnabled, tt.wantStart, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestSequenceParser_AutonumberReenabled(t *testing.T) {
+	source := "sequenceDiagram\n    autonumber\n    A->>B: one\n    autonumber off\n    B->>A: two\n    autonumber 100 10\n    A->>B: three"
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var directives []*ast.Autonumber
+	for _, stmt := range diagram.(*ast.SequenceDiagram).Statements {
+		if autonumber, ok := stmt.(*ast.Autonumber); ok {
+			directives = append(directives, autonumber)
+		}
+	}
+	if len(directives) != 3 {
+		t.Fatalf("expected 3 autonumber directives, got %d", len(directives))
+	}
+	if directives[1].Enabled || !directives[2].Enabled || directives[2].Start != 100 {
+		t.Errorf("unexpected directives: %+v, %+v", directives[1], directives[2])
+	}
+}
+
+func TestSequenceParser_BoxColour(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantColour string
+		wantLabel  string
+	}{
+		{"word colour and label", "box Aqua Frontend team", "Aqua", "Frontend team"},
+		{"label only", "box Frontend", "", "Frontend"},
+		{"hex colour only", "box #e0f0ff", "#e0f0ff", ""},
+		{"rgb colour and label", "box rgb(33, 66, 99) Backend", "rgb(33, 66, 99)", "Backend"},
+		{"transparent", "box transparent Services", "transparent", "Services"},
+		{"bare box", "box", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse("sequenceDiagram\n    " + tt.header + "\n        participant A\n    end")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			box, ok := diagram.(*ast.SequenceDiagram).Statements[0].(*ast.Box)
+			if !ok {
+				t.Fatalf("expected *ast.Box, got %T", diagram.(*ast.SequenceDiagram).Statements[0])
+			}
+			if box.Colour != tt.wantColour || box.Label != tt.wantLabel {
+				t.Errorf("got colour %q label %q, want colour %q label %q", box.Colour, box.Label, tt.wantColour, tt.wantLabel)
+			}
+		})
+	}
+}
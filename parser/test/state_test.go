@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -65,6 +67,48 @@ func TestStateParser_Parse(t *testing.T) {
 	}
 }
 
+func TestStateParser_TransitionLabels(t *testing.T) {
+	source := `stateDiagram-v2
+    Idle --> Running : start [ready] / initialise()
+    Running --> Paused : pause
+    Paused --> Running : [resumable]
+    Running --> Idle : / reset
+    Idle --> Done :
+    Done --> Idle`
+
+	diagram, err := parser.NewStateParser().Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		event, guard, action string
+		hasLabel             bool
+	}{
+		{"start", "ready", "initialise()", true},
+		{"pause", "", "", true},
+		{"", "resumable", "", true},
+		{"", "", "reset", true},
+		{"", "", "", true},
+		{"", "", "", false},
+	}
+
+	statements := diagram.(*ast.StateDiagram).Statements
+	if len(statements) != len(tests) {
+		t.Fatalf("expected %d statements, got %d", len(tests), len(statements))
+	}
+	for i, tt := range tests {
+		trans, ok := statements[i].(*ast.Transition)
+		if !ok {
+			t.Fatalf("statement %d: expected *ast.Transition, got %T", i, statements[i])
+		}
+		if trans.Event != tt.event || trans.Guard != tt.guard || trans.Action != tt.action || trans.HasLabel != tt.hasLabel {
+			t.Errorf("statement %d: got event=%q guard=%q action=%q hasLabel=%v, want %q %q %q %v",
+				i, trans.Event, trans.Guard, trans.Action, trans.HasLabel, tt.event, tt.guard, tt.action, tt.hasLabel)
+		}
+	}
+}
+
 func TestStateParser_SupportedTypes(t *testing.T) {
 	p := parser.NewStateParser()
 	types := p.SupportedTypes()
@@ -72,3 +116,35 @@ func TestStateParser_SupportedTypes(t *testing.T) {
 		t.Errorf("SupportedTypes() = %v, want 2 types", types)
 	}
 }
+
+func TestStateParser_SkippedLines(t *testing.T) {
+	diagram, err := parser.NewStateParser().Parse("stateDiagram-v2\n    [*] --> Idle\n    Idle => Busy\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	skipped := diagram.(*ast.StateDiagram).SkippedLines
+	if len(skipped) != 1 || skipped[0].Line != 3 || skipped[0].Content != "Idle => Busy" {
+		t.Errorf("SkippedLines = %+v, want line 3", skipped)
+	}
+}
+
+func TestStateParser_Descriptions(t *testing.T) {
+	diagram, err := parser.Parse("stateDiagram-v2\n    S1 : Waiting\n    S1 : for input\n    [*] --> S1\n    S1 --> S2 : submit\n    S2 : Done\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	stateDiagram := diagram.(*ast.StateDiagram)
+	if len(stateDiagram.SkippedLines) > 0 {
+		t.Errorf("SkippedLines = %+v", stateDiagram.SkippedLines)
+	}
+	var got []string
+	for _, stmt := range stateDiagram.Statements {
+		if state, ok := stmt.(*ast.State); ok {
+			got = append(got, fmt.Sprintf("%s=%q@%d", state.ID, state.Description, state.Pos.Line))
+		}
+	}
+	want := []string{`S1="Waiting\nfor input"@2`, `S2="Done"@6`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("states = %v, want %v", got, want)
+	}
+}
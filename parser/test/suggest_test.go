@@ -0,0 +1,176 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "misspelt header named in error",
+			source: "flowchar TD\n    A --> B",
+		},
+		{
+			name:   "extra character in header named in error",
+			source: "sequenceDiagramm\n    A->>B: hi",
+		},
+		{
+			name:   "unrelated header",
+			source: "hello world\n    A --> B",
+		},
+		{
+			name:   "unclosed loop",
+			source: "sequenceDiagram\n    loop every minute\n        A->>B: ping",
+			want:   "add 'end' to close the 'loop' opened on line 2",
+		},
+		{
+			name:   "unclosed nested block",
+			source: "sequenceDiagram\n    alt ok\n        loop retry\n            A->>B: ping\n        end\n    else failed\n        A->>B: stop",
+			want:   "add 'end' to close the 'alt' opened on line 2",
+		},
+		{
+			name:   "unclosed subgraph",
+			source: "flowchart TD\n    subgraph one\n        A --> B\n    end\n    subgraph two\n        C --> D",
+			want:   "add 'end' to close the 'subgraph' opened on line 5",
+		},
+		{
+			name:   "unclosed class body",
+			source: "classDiagram\n    class Animal {\n        +int age",
+			want:   "add '}' to close the 'class Animal {' opened on line 2",
+		},
+		{
+			name:   "fat arrow in sequence diagram",
+			source: "sequenceDiagram\n    A => B: hi",
+			want:   "sequence messages use '->>' (solid) or '-->>' (dotted), for example 'A->>B: text'",
+		},
+		{
+			name:   "no suggestion",
+			source: "sequenceDiagram\n    !!bad",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.source)
+			if err == nil {
+				t.Fatal("Parse() succeeded, want an error")
+			}
+			if got := parser.Suggest(tt.source, err); got != tt.want {
+				t.Errorf("Suggest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest_ErrorLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+		want   string
+	}{
+		{
+			name:   "sequence arrow in flowchart",
+			source: "flowchart TD\n    A ->> B",
+			err:    "line 2: invalid link",
+			want:   "flowcharts link nodes with '-->'; '->>' is a sequence diagram arrow",
+		},
+		{
+			name:   "single dash arrow in flowchart",
+			source: "flowchart TD\n    A -> B",
+			err:    "line 2: invalid link",
+			want:   "flowcharts link nodes with '-->', not '->'",
+		},
+		{
+			name:   "single dash arrow in state diagram",
+			source: "stateDiagram-v2\n    Idle -> Running",
+			err:    "line 2: invalid transition",
+			want:   "use '-->' between states and classes",
+		},
+		{
+			name:   "flowchart arrow in flowchart",
+			source: "flowchart TD\n    A --> B",
+			err:    "line 2: invalid link",
+		},
+		{
+			name:   "smart quotes",
+			source: "flowchart TD\n    A[“Start”] --> B",
+			err:    "line 2: invalid node",
+			want:   "replace smart quotes (“ ” ‘ ’) with straight quotes",
+		},
+		{
+			name:   "smart quotes on another line",
+			source: "flowchart TD\n    A[“Start”] --> B\n    B --> C",
+			err:    "line 3: invalid node",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parser.Suggest(tt.source, errors.New(tt.err)); got != tt.want {
+				t.Errorf("Suggest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecovering_Suggestion(t *testing.T) {
+	_, errs := parser.ParseRecovering("sequenceDiagram\n    participant A\n    A => B: hi\n    A->>B: ok")
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if errs[0].Suggestion == "" {
+		t.Errorf("expected a suggestion for %v", errs[0])
+	}
+}
+
+func TestHeaderCandidates(t *testing.T) {
+	if got := parser.HeaderCandidates("flowchart TD\n    A --> B"); got != nil {
+		t.Errorf("HeaderCandidates() = %v for a known header, want nil", got)
+	}
+	got := parser.HeaderCandidates("clasDiagram\n    class A")
+	if len(got) == 0 || got[0] != "classDiagram" {
+		t.Errorf("HeaderCandidates() = %v, want classDiagram first", got)
+	}
+	if got := parser.DetectType("%% comment\nstateDiagram-v2\n    [*] --> A"); got != "stateDiagram-v2" {
+		t.Errorf("DetectType() = %q, want stateDiagram-v2", got)
+	}
+}
+
+func TestDetectCandidates(t *testing.T) {
+	diagType, candidates := parser.Detect("sequence diagram\n    A->>B: Hi")
+	if diagType != parser.UnknownType {
+		t.Errorf("Detect() type = %q, want %q", diagType, parser.UnknownType)
+	}
+	if len(candidates) == 0 {
+		t.Fatal("Detect() returned no candidates")
+	}
+	first := candidates[0]
+	if first.Header != "sequenceDiagram" || first.Type != "sequence" || first.Distance != 0 || first.Confidence != 1 {
+		t.Errorf("first candidate = %+v, want an exact sequenceDiagram match", first)
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Distance < candidates[i-1].Distance {
+			t.Errorf("candidates not ranked by distance: %+v", candidates)
+		}
+	}
+
+	diagType, candidates = parser.Detect("C4Contxt\n    title System")
+	if diagType != parser.UnknownType || len(candidates) == 0 || candidates[0].Type != "c4Context" {
+		t.Errorf("Detect() = %q, %+v, want C4Context first", diagType, candidates)
+	}
+	if c := candidates[0].Confidence; c <= 0 || c >= 1 {
+		t.Errorf("Confidence = %v, want between 0 and 1", c)
+	}
+
+	if diagType, candidates := parser.Detect("gantt\n    title Plan"); diagType != "gantt" || candidates != nil {
+		t.Errorf("Detect() = %q, %+v, want gantt without candidates", diagType, candidates)
+	}
+}
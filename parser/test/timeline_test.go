@@ -191,6 +191,42 @@ func TestTimelineParser_Parse(t *testing.T) {
 			name: "period without events",
 			source: `timeline
     2024 :`,
+			wantErr: false,
+			check: func(t *testing.T, d ast.Diagram) {
+				diagram := d.(*ast.TimelineDiagram)
+				if events := diagram.Sections[0].Periods[0].Events; len(events) != 0 {
+					t.Errorf("expected no events, got %v", events)
+				}
+			},
+		},
+		{
+			name: "events on continuation lines only",
+			source: `timeline
+    2024 :
+         : Planning : Hiring
+         : Launch
+    section Later
+    section Next year
+    2025 : Growth`,
+			wantErr: false,
+			check: func(t *testing.T, d ast.Diagram) {
+				diagram := d.(*ast.TimelineDiagram)
+				if len(diagram.Sections) != 3 {
+					t.Fatalf("expected 3 sections (including the empty one), got %d", len(diagram.Sections))
+				}
+				events := diagram.Sections[0].Periods[0].Events
+				if len(events) != 3 || events[0] != "Planning" || events[1] != "Hiring" || events[2] != "Launch" {
+					t.Errorf("expected [Planning Hiring Launch], got %v", events)
+				}
+				if diagram.Sections[1].Name != "Later" || len(diagram.Sections[1].Periods) != 0 {
+					t.Errorf("expected empty section 'Later', got %+v", diagram.Sections[1])
+				}
+			},
+		},
+		{
+			name: "only empty sections",
+			source: `timeline
+    section Empty`,
 			wantErr: true,
 		},
 		{
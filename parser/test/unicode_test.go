@@ -0,0 +1,117 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestParse_UnicodeFlowchart(t *testing.T) {
+	source := "flowchart LR\n    Café[Café crème] --> 用户\n    subgraph Ölkännchen\n        Ñandú --> 東京\n    end"
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	flowchart := diagram.(*ast.Flowchart)
+
+	var link *ast.Link
+	var nodeDef *ast.NodeDef
+	var subgraph *ast.Subgraph
+	for _, stmt := range flowchart.Statements {
+		switch s := stmt.(type) {
+		case *ast.Link:
+			link = s
+		case *ast.NodeDef:
+			if nodeDef == nil {
+				nodeDef = s
+			}
+		case *ast.Subgraph:
+			subgraph = s
+		}
+	}
+
+	if link == nil || link.From != "Café" || link.To != "用户" {
+		t.Errorf("link = %+v, want Café --> 用户", link)
+	}
+	if nodeDef == nil || nodeDef.ID != "Café" || nodeDef.Label != "Café crème" {
+		t.Errorf("node def = %+v, want Café[Café crème]", nodeDef)
+	}
+	if subgraph == nil || subgraph.ID != "Ölkännchen" {
+		t.Fatalf("subgraph = %+v, want ID Ölkännchen", subgraph)
+	}
+	if len(subgraph.Statements) != 1 {
+		t.Errorf("subgraph has %d statements, want 1", len(subgraph.Statements))
+	}
+}
+
+func TestParse_UnicodeSequence(t *testing.T) {
+	source := "sequenceDiagram\n    participant Zoë as Zoë Smith\n    actor 田中\n    Zoë->>田中: こんにちは\n    activate 田中\n    Note right of 田中: 確認\n    deactivate 田中"
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	seq := diagram.(*ast.SequenceDiagram)
+
+	var participants []string
+	var msg *ast.Message
+	for _, stmt := range seq.Statements {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			participants = append(participants, s.ID)
+		case *ast.Message:
+			msg = s
+		}
+	}
+	if len(participants) != 2 || participants[0] != "Zoë" || participants[1] != "田中" {
+		t.Errorf("participants = %v, want Zoë and 田中", participants)
+	}
+	if msg == nil || msg.From != "Zoë" || msg.To != "田中" || msg.Text != "こんにちは" {
+		t.Errorf("message = %+v, want Zoë->>田中: こんにちは", msg)
+	}
+}
+
+func TestParse_UnicodeOtherDiagrams(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "class diagram",
+			source: "classDiagram\n    class Élève\n    class 学生\n    Élève <|-- 学生",
+		},
+		{
+			name:   "state diagram",
+			source: "stateDiagram-v2\n    [*] --> Bereit\n    Bereit --> Geöffnet : öffnen\n    Geöffnet --> 完了",
+		},
+		{
+			name:   "ER diagram",
+			source: "erDiagram\n    KUNDE ||--o{ BESTELLUNG : gibt\n    顧客 {\n        string 名前\n    }",
+		},
+		{
+			name:   "git graph",
+			source: "gitGraph\n    commit\n    branch fonctionnalité\n    checkout fonctionnalité\n    commit\n    checkout main\n    merge fonctionnalité",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parser.Parse(tt.source); err != nil {
+				t.Errorf("Parse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParse_UnicodeERNames(t *testing.T) {
+	diagram, err := parser.Parse("erDiagram\n    ÉCOLE ||--o{ 学生 : has")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	er := diagram.(*ast.ERDiagram)
+	if len(er.Relationships) != 1 || er.Relationships[0].From != "ÉCOLE" || er.Relationships[0].To != "学生" {
+		t.Errorf("relationships = %+v, want ÉCOLE ||--o{ 学生", er.Relationships)
+	}
+}
@@ -154,6 +154,30 @@ func TestXYChartParser_Parse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "chart with named series",
+			source: `xychart-beta
+    x-axis [a, b]
+    y-axis "Y" 0 --> 10
+    bar "Revenue" [5, 8]
+    line "Target"[6, 7]
+    line [4, 4]`,
+			wantErr: false,
+			check: func(t *testing.T, d ast.Diagram) {
+				chart := d.(*ast.XYChartDiagram)
+				if len(chart.Series) != 3 {
+					t.Fatalf("expected 3 series, got %d", len(chart.Series))
+				}
+				for i, want := range []string{"Revenue", "Target", ""} {
+					if chart.Series[i].Name != want {
+						t.Errorf("series %d: expected name %q, got %q", i, want, chart.Series[i].Name)
+					}
+				}
+				if len(chart.Series[1].Values) != 2 || chart.Series[1].Values[0] != 6 {
+					t.Errorf("unexpected values %v", chart.Series[1].Values)
+				}
+			},
+		},
 		{
 			name: "chart without title",
 			source: `xychart-beta
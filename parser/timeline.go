@@ -19,7 +19,7 @@ func NewTimelineParser() *TimelineParser {
 var (
 	timelineTitleRegex   = regexp.MustCompile(`^\s*title\s+(.+)$`)
 	timelineSectionRegex = regexp.MustCompile(`^\s*section\s+(.+)$`)
-	timelinePeriodRegex  = regexp.MustCompile(`^\s*([^:]+?)\s*:\s*(.+)$`)
+	timelinePeriodRegex  = regexp.MustCompile(`^\s*([^:]+?)\s*:\s*(.*)$`)
 	timelineEventRegex   = regexp.MustCompile(`^\s*:\s*(.+)$`)
 )
 
@@ -30,8 +30,9 @@ func (p *TimelineParser) Parse(source string) (ast.Diagram, error) {
 		return nil, fmt.Errorf("empty diagram source")
 	}
 
-	// Verify header
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	if firstLine != "timeline" {
 		return nil, fmt.Errorf("invalid timeline header: expected 'timeline', got %q", firstLine)
 	}
@@ -52,7 +53,7 @@ func (p *TimelineParser) Parse(source string) (ast.Diagram, error) {
 
 	var currentPeriod *ast.TimelinePeriod
 
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -76,8 +77,8 @@ func (p *TimelineParser) Parse(source string) (ast.Diagram, error) {
 				currentSection.Periods = append(currentSection.Periods, *currentPeriod)
 			}
 
-			// Save current section if it has periods
-			if len(currentSection.Periods) > 0 {
+			// Save the current section if it is named or has periods
+			if currentSection.Name != "" || len(currentSection.Periods) > 0 {
 				diagram.Sections = append(diagram.Sections, *currentSection)
 			}
 
@@ -96,11 +97,11 @@ func (p *TimelineParser) Parse(source string) (ast.Diagram, error) {
 			if currentPeriod == nil {
 				return nil, fmt.Errorf("line %d: event continuation without time period", lineNum)
 			}
-			event := strings.TrimSpace(matches[1])
-			if event == "" {
+			events := splitTimelineEvents(matches[1])
+			if len(events) == 0 {
 				return nil, fmt.Errorf("line %d: empty event", lineNum)
 			}
-			currentPeriod.Events = append(currentPeriod.Events, event)
+			currentPeriod.Events = append(currentPeriod.Events, events...)
 			continue
 		}
 
@@ -116,25 +117,11 @@ func (p *TimelineParser) Parse(source string) (ast.Diagram, error) {
 				return nil, fmt.Errorf("line %d: empty time period", lineNum)
 			}
 
-			// Parse events (colon-separated)
-			eventsStr := strings.TrimSpace(matches[2])
-			events := []string{}
-			if eventsStr != "" {
-				for event := range strings.SplitSeq(eventsStr, ":") {
-					event = strings.TrimSpace(event)
-					if event != "" {
-						events = append(events, event)
-					}
-				}
-			}
-
-			if len(events) == 0 {
-				return nil, fmt.Errorf("line %d: time period %q has no events", lineNum, timePeriod)
-			}
-
+			// A period with no events yet may be followed by continuation
+			// lines; one that ends up empty is reported by the validator
 			currentPeriod = &ast.TimelinePeriod{
 				TimePeriod: timePeriod,
-				Events:     events,
+				Events:     splitTimelineEvents(matches[2]),
 				Pos:        ast.Position{Line: lineNum, Column: 1},
 			}
 			continue
@@ -148,19 +135,37 @@ func (p *TimelineParser) Parse(source string) (ast.Diagram, error) {
 		currentSection.Periods = append(currentSection.Periods, *currentPeriod)
 	}
 
-	// Save last section if it has periods
-	if len(currentSection.Periods) > 0 {
+	// Save the last section if it is named or has periods
+	if currentSection.Name != "" || len(currentSection.Periods) > 0 {
 		diagram.Sections = append(diagram.Sections, *currentSection)
 	}
 
 	// Validate we have at least one period
-	if len(diagram.Sections) == 0 {
+	hasPeriods := false
+	for _, section := range diagram.Sections {
+		if len(section.Periods) > 0 {
+			hasPeriods = true
+			break
+		}
+	}
+	if !hasPeriods {
 		return nil, fmt.Errorf("timeline must have at least one time period")
 	}
 
 	return diagram, nil
 }
 
+// splitTimelineEvents splits colon-separated events, dropping empty ones.
+func splitTimelineEvents(text string) []string {
+	events := []string{}
+	for event := range strings.SplitSeq(text, ":") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
 // SupportedTypes returns the diagram types this parser supports.
 func (p *TimelineParser) SupportedTypes() []string {
 	return []string{"timeline"}
@@ -24,8 +24,8 @@ var (
 	xyChartYAxisCatRegex    = regexp.MustCompile(`^\s*y-axis\s+\[(.+)\]\s*$`)
 	xyChartXAxisNumRegex    = regexp.MustCompile(`^\s*x-axis\s+"([^"]+)"\s+(-?[0-9]+(?:\.[0-9]+)?)\s+-->\s+(-?[0-9]+(?:\.[0-9]+)?)\s*$`)
 	xyChartYAxisNumRegex    = regexp.MustCompile(`^\s*y-axis\s+"([^"]+)"\s+(-?[0-9]+(?:\.[0-9]+)?)\s+-->\s+(-?[0-9]+(?:\.[0-9]+)?)\s*$`)
-	xyChartBarSeriesRegex   = regexp.MustCompile(`^\s*bar\s+\[(.+)\]\s*$`)
-	xyChartLineSeriesRegex  = regexp.MustCompile(`^\s*line\s+\[(.+)\]\s*$`)
+	xyChartBarSeriesRegex   = regexp.MustCompile(`^\s*bar\s+(?:"([^"]*)"\s*)?\[(.+)\]\s*$`)
+	xyChartLineSeriesRegex  = regexp.MustCompile(`^\s*line\s+(?:"([^"]*)"\s*)?\[(.+)\]\s*$`)
 )
 
 // Parse parses an XY chart diagram source.
@@ -43,8 +43,9 @@ func (p *XYChartParser) Parse(source string) (ast.Diagram, error) {
 		Pos:         ast.Position{Line: 1, Column: 1},
 	}
 
-	// Parse header line
-	firstLine := strings.TrimSpace(lines[0])
+	// Parse header line, after any front matter, comments and directives
+	header := headerLine(lines)
+	firstLine := strings.TrimSpace(lines[header])
 	matches := xyChartHeaderRegex.FindStringSubmatch(firstLine)
 	if matches == nil {
 		return nil, fmt.Errorf("invalid xychart header: %s", firstLine)
@@ -59,7 +60,7 @@ func (p *XYChartParser) Parse(source string) (ast.Diagram, error) {
 	yAxisDefined := false
 
 	// Parse remaining lines
-	for i := 1; i < len(lines); i++ {
+	for i := header + 1; i < len(lines); i++ {
 		line := lines[i]
 		trimmed := strings.TrimSpace(line)
 
@@ -156,12 +157,13 @@ func (p *XYChartParser) Parse(source string) (ast.Diagram, error) {
 
 		// Try to parse bar series
 		if matches := xyChartBarSeriesRegex.FindStringSubmatch(trimmed); matches != nil {
-			values, err := parseValues(matches[1])
+			values, err := parseValues(matches[2])
 			if err != nil {
 				return nil, fmt.Errorf("line %d: %v", lineNum, err)
 			}
 			diagram.Series = append(diagram.Series, ast.XYChartSeries{
 				Type:   "bar",
+				Name:   matches[1],
 				Values: values,
 				Pos:    ast.Position{Line: lineNum, Column: 1},
 			})
@@ -170,12 +172,13 @@ func (p *XYChartParser) Parse(source string) (ast.Diagram, error) {
 
 		// Try to parse line series
 		if matches := xyChartLineSeriesRegex.FindStringSubmatch(trimmed); matches != nil {
-			values, err := parseValues(matches[1])
+			values, err := parseValues(matches[2])
 			if err != nil {
 				return nil, fmt.Errorf("line %d: %v", lineNum, err)
 			}
 			diagram.Series = append(diagram.Series, ast.XYChartSeries{
 				Type:   "line",
+				Name:   matches[1],
 				Values: values,
 				Pos:    ast.Position{Line: lineNum, Column: 1},
 			})
@@ -0,0 +1,217 @@
+package mermaid
+
+import (
+	"time"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// Metrics records how long a diagram took to parse and validate, so that
+// pathological diagrams and slow rules can be found in large repositories.
+type Metrics struct {
+	// Parse is the time spent parsing the diagram.
+	Parse time.Duration
+	// Validate is the time spent validating the diagram, zero when it did
+	// not parse.
+	Validate time.Duration
+	// Rules are the times spent in each rule, in the order the rules ran.
+	Rules []RuleTiming
+}
+
+// RuleTiming is the time a rule spent validating one diagram.
+type RuleTiming struct {
+	Rule     string
+	Duration time.Duration
+}
+
+// ParseWithMetrics parses source like Parse, also reporting how long parsing
+// took.
+func ParseWithMetrics(source string) (ast.Diagram, Metrics, error) {
+	start := time.Now()
+	diagram, err := Parse(source)
+	return diagram, Metrics{Parse: time.Since(start)}, err
+}
+
+// ValidateWithMetrics validates diagram like Validate, also reporting how long
+// validation took in total and in each rule.
+func ValidateWithMetrics(diagram ast.Diagram, strict bool) ([]validator.ValidationError, Metrics) {
+	level := LevelDefault
+	if strict {
+		level = LevelStrict
+	}
+	return profileWithOptions(diagram, ValidateOptions{Level: level})
+}
+
+// ProfileWith validates diagram like ValidateWith, also reporting how long
+// validation took in total and in each rule.
+func ProfileWith(diagram ast.Diagram, opts ValidateOptions) ([]validator.ValidationError, Metrics) {
+	if err := opts.check(); err != nil {
+		return optionsError(err), Metrics{}
+	}
+	return profileWithOptions(diagram, opts)
+}
+
+// ruleRunner runs one rule against a diagram. An unnamed runner validates
+// a whole diagram and is not timed separately.
+type ruleRunner struct {
+	name string
+	run  func() []validator.ValidationError
+}
+
+// profileRules runs each of rules in turn, timing them.
+func profileRules(rules []ruleRunner) ([]validator.ValidationError, Metrics) {
+	var metrics Metrics
+	var errors []validator.ValidationError
+	start := time.Now()
+	for _, rule := range rules {
+		ruleStart := time.Now()
+		ruleErrors := rule.run()
+		if rule.name == "" {
+			errors = append(errors, ruleErrors...)
+			continue
+		}
+		metrics.Rules = append(metrics.Rules, RuleTiming{Rule: rule.name, Duration: time.Since(ruleStart)})
+		for i := range ruleErrors {
+			if ruleErrors[i].Rule == "" {
+				ruleErrors[i].Rule = rule.name
+			}
+		}
+		errors = append(errors, ruleErrors...)
+	}
+	metrics.Validate = time.Since(start)
+	return errors, metrics
+}
+
+// typeRules returns the rule set selected by opts for the diagram's type, as
+// validateRuleSet applies it.
+func typeRules(diagram ast.Diagram, opts ValidateOptions) []ruleRunner {
+	strict := opts.Strict
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		rules := validator.DefaultRules()
+		if strict {
+			rules = validator.StrictRules()
+		}
+		rules = withRuleOptions(rules, opts)
+		return runners(rules, func(r validator.Rule) []validator.ValidationError { return r.Validate(d) })
+	case *ast.SequenceDiagram:
+		rules := validator.SequenceDefaultRules()
+		if strict {
+			rules = validator.SequenceStrictRules()
+		}
+		rules = withRuleOptions(rules, opts)
+		return runners(rules, func(r validator.SequenceRule) []validator.ValidationError { return r.ValidateSequence(d) })
+	case *ast.ClassDiagram:
+		rules := validator.ClassDefaultRules()
+		if strict {
+			rules = validator.ClassStrictRules()
+		}
+		return runners(rules, func(r validator.ClassRule) []validator.ValidationError { return r.ValidateClass(d) })
+	case *ast.StateDiagram:
+		rules := validator.StateDefaultRules()
+		if strict {
+			rules = validator.StateStrictRules()
+		}
+		return runners(rules, func(r validator.StateRule) []validator.ValidationError { return r.ValidateState(d) })
+	case *ast.PieDiagram:
+		rules := validator.PieDefaultRules()
+		if strict {
+			rules = validator.PieStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.PieRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.ERDiagram:
+		rules := validator.ERDefaultRules()
+		if strict {
+			rules = validator.ERStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.ERRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.JourneyDiagram:
+		rules := validator.JourneyDefaultRules()
+		if strict {
+			rules = validator.JourneyStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.JourneyRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.TimelineDiagram:
+		rules := validator.TimelineDefaultRules()
+		if strict {
+			rules = validator.TimelineStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.TimelineRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.GanttDiagram:
+		rules := validator.GanttDefaultRules()
+		if strict {
+			rules = validator.GanttStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.GanttRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.GitGraphDiagram:
+		rules := validator.GitGraphDefaultRules()
+		if strict {
+			rules = validator.GitGraphStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.GitGraphRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.MindmapDiagram:
+		rules := validator.MindmapDefaultRules()
+		if strict {
+			rules = validator.MindmapStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.MindmapRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.SankeyDiagram:
+		rules := validator.SankeyDefaultRules()
+		if strict {
+			rules = validator.SankeyStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.SankeyRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.QuadrantDiagram:
+		rules := validator.QuadrantDefaultRules()
+		if strict {
+			rules = validator.QuadrantStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.QuadrantRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.XYChartDiagram:
+		rules := validator.XYChartDefaultRules()
+		if strict {
+			rules = validator.XYChartStrictRules()
+		}
+		return pointerRunners(rules, func(r validator.XYChartRule) []*validator.ValidationError { return r.Validate(d) })
+	case *ast.C4Diagram:
+		rules := validator.DefaultC4Rules()
+		if strict {
+			rules = validator.StrictC4Rules()
+		}
+		return runners(rules, func(r validator.C4Rule) []validator.ValidationError { return r.Validate(d) })
+	case *ast.GenericDiagram:
+		rules := validator.GenericDefaultRules()
+		if strict {
+			rules = validator.GenericStrictRules()
+		}
+		return runners(rules, func(r validator.GenericRule) []validator.ValidationError { return r.ValidateGeneric(d) })
+	default:
+		return []ruleRunner{{run: func() []validator.ValidationError { return validateByType(diagram, strict) }}}
+	}
+}
+
+// diagramRuleRunners wraps rules that apply to any diagram type.
+func diagramRuleRunners(diagram ast.Diagram, rules []validator.DiagramRule) []ruleRunner {
+	return runners(rules, func(r validator.DiagramRule) []validator.ValidationError { return r.ValidateDiagram(diagram) })
+}
+
+// runners wraps each of rules, run by validate, as a ruleRunner.
+func runners[R any](rules []R, validate func(R) []validator.ValidationError) []ruleRunner {
+	wrapped := make([]ruleRunner, len(rules))
+	for i, rule := range rules {
+		wrapped[i] = ruleRunner{name: validator.RuleName(rule), run: func() []validator.ValidationError { return validate(rule) }}
+	}
+	return wrapped
+}
+
+// pointerRunners is runners for rules returning []*validator.ValidationError.
+func pointerRunners[R any](rules []R, validate func(R) []*validator.ValidationError) []ruleRunner {
+	return runners(rules, func(rule R) []validator.ValidationError {
+		var errors []validator.ValidationError
+		for _, err := range validate(rule) {
+			errors = append(errors, *err)
+		}
+		return errors
+	})
+}
@@ -0,0 +1,732 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: mermaidcheck/v1/mermaidcheck.proto
+
+package mermaidcheckv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Severity is the severity of a validation error.
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_ERROR       Severity = 1
+	Severity_SEVERITY_WARNING     Severity = 2
+	Severity_SEVERITY_INFO        Severity = 3
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_ERROR",
+		2: "SEVERITY_WARNING",
+		3: "SEVERITY_INFO",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_ERROR":       1,
+		"SEVERITY_WARNING":     2,
+		"SEVERITY_INFO":        3,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_mermaidcheck_v1_mermaidcheck_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_mermaidcheck_v1_mermaidcheck_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{0}
+}
+
+// Options select the rules applied to each diagram.
+type Options struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Enable the strict rule set for every diagram type.
+	Strict bool `protobuf:"varint,1,opt,name=strict,proto3" json:"strict,omitempty"`
+	// Metadata annotation keys (`%% @key: value`) every diagram must define.
+	RequiredAnnotations []string `protobuf:"bytes,2,rep,name=required_annotations,json=requiredAnnotations,proto3" json:"required_annotations,omitempty"`
+	// Names of rules not to apply, such as "no-duplicate-links".
+	Disable       []string `protobuf:"bytes,3,rep,name=disable,proto3" json:"disable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Options) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+func (x *Options) GetRequiredAnnotations() []string {
+	if x != nil {
+		return x.RequiredAnnotations
+	}
+	return nil
+}
+
+func (x *Options) GetDisable() []string {
+	if x != nil {
+		return x.Disable
+	}
+	return nil
+}
+
+// ValidationError is a problem found in a diagram.
+type ValidationError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Line within the diagram (1-indexed); add line_offset - 1 for the line in
+	// the containing document.
+	Line     int32    `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Column   int32    `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	Message  string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Severity Severity `protobuf:"varint,4,opt,name=severity,proto3,enum=mermaidcheck.v1.Severity" json:"severity,omitempty"`
+	// Name of the rule that reported the error.
+	Rule          string `protobuf:"bytes,5,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationError) Reset() {
+	*x = ValidationError{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationError) ProtoMessage() {}
+
+func (x *ValidationError) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationError.ProtoReflect.Descriptor instead.
+func (*ValidationError) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidationError) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ValidationError) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *ValidationError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ValidationError) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *ValidationError) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+// DiagramResult is the outcome of validating one diagram.
+type DiagramResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Index of the diagram within its document (0-based).
+	BlockIndex  int32  `protobuf:"varint,1,opt,name=block_index,json=blockIndex,proto3" json:"block_index,omitempty"`
+	DiagramType string `protobuf:"bytes,2,opt,name=diagram_type,json=diagramType,proto3" json:"diagram_type,omitempty"`
+	// Lines of the containing document the diagram source spans (1-indexed).
+	LineOffset int32 `protobuf:"varint,3,opt,name=line_offset,json=lineOffset,proto3" json:"line_offset,omitempty"`
+	EndLine    int32 `protobuf:"varint,4,opt,name=end_line,json=endLine,proto3" json:"end_line,omitempty"`
+	// Set when the diagram could not be parsed.
+	ParseError    string             `protobuf:"bytes,5,opt,name=parse_error,json=parseError,proto3" json:"parse_error,omitempty"`
+	Errors        []*ValidationError `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagramResult) Reset() {
+	*x = DiagramResult{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagramResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagramResult) ProtoMessage() {}
+
+func (x *DiagramResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagramResult.ProtoReflect.Descriptor instead.
+func (*DiagramResult) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DiagramResult) GetBlockIndex() int32 {
+	if x != nil {
+		return x.BlockIndex
+	}
+	return 0
+}
+
+func (x *DiagramResult) GetDiagramType() string {
+	if x != nil {
+		return x.DiagramType
+	}
+	return ""
+}
+
+func (x *DiagramResult) GetLineOffset() int32 {
+	if x != nil {
+		return x.LineOffset
+	}
+	return 0
+}
+
+func (x *DiagramResult) GetEndLine() int32 {
+	if x != nil {
+		return x.EndLine
+	}
+	return 0
+}
+
+func (x *DiagramResult) GetParseError() string {
+	if x != nil {
+		return x.ParseError
+	}
+	return ""
+}
+
+func (x *DiagramResult) GetErrors() []*ValidationError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ValidateDiagramRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Options       *Options               `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateDiagramRequest) Reset() {
+	*x = ValidateDiagramRequest{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateDiagramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateDiagramRequest) ProtoMessage() {}
+
+func (x *ValidateDiagramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateDiagramRequest.ProtoReflect.Descriptor instead.
+func (*ValidateDiagramRequest) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateDiagramRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ValidateDiagramRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type ValidateDiagramResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *DiagramResult         `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateDiagramResponse) Reset() {
+	*x = ValidateDiagramResponse{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateDiagramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateDiagramResponse) ProtoMessage() {}
+
+func (x *ValidateDiagramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateDiagramResponse.ProtoReflect.Descriptor instead.
+func (*ValidateDiagramResponse) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ValidateDiagramResponse) GetResult() *DiagramResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ValidateMarkdownRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Markdown      string                 `protobuf:"bytes,1,opt,name=markdown,proto3" json:"markdown,omitempty"`
+	Options       *Options               `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateMarkdownRequest) Reset() {
+	*x = ValidateMarkdownRequest{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateMarkdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateMarkdownRequest) ProtoMessage() {}
+
+func (x *ValidateMarkdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateMarkdownRequest.ProtoReflect.Descriptor instead.
+func (*ValidateMarkdownRequest) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ValidateMarkdownRequest) GetMarkdown() string {
+	if x != nil {
+		return x.Markdown
+	}
+	return ""
+}
+
+func (x *ValidateMarkdownRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type ValidateMarkdownResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*DiagramResult       `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateMarkdownResponse) Reset() {
+	*x = ValidateMarkdownResponse{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateMarkdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateMarkdownResponse) ProtoMessage() {}
+
+func (x *ValidateMarkdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateMarkdownResponse.ProtoReflect.Descriptor instead.
+func (*ValidateMarkdownResponse) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ValidateMarkdownResponse) GetResults() []*DiagramResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type StreamValidateDirectoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Directory to validate, relative to the server's root directory.
+	Path          string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Options       *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamValidateDirectoryRequest) Reset() {
+	*x = StreamValidateDirectoryRequest{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamValidateDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamValidateDirectoryRequest) ProtoMessage() {}
+
+func (x *StreamValidateDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamValidateDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*StreamValidateDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamValidateDirectoryRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *StreamValidateDirectoryRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// FileResult is the outcome of validating one file.
+type FileResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Path of the file relative to the server's root directory, with forward
+	// slashes.
+	Path          string           `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Results       []*DiagramResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileResult) Reset() {
+	*x = FileResult{}
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileResult) ProtoMessage() {}
+
+func (x *FileResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mermaidcheck_v1_mermaidcheck_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileResult.ProtoReflect.Descriptor instead.
+func (*FileResult) Descriptor() ([]byte, []int) {
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FileResult) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileResult) GetResults() []*DiagramResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_mermaidcheck_v1_mermaidcheck_proto protoreflect.FileDescriptor
+
+const file_mermaidcheck_v1_mermaidcheck_proto_rawDesc = "" +
+	"\n" +
+	"\"mermaidcheck/v1/mermaidcheck.proto\x12\x0fmermaidcheck.v1\"n\n" +
+	"\aOptions\x12\x16\n" +
+	"\x06strict\x18\x01 \x01(\bR\x06strict\x121\n" +
+	"\x14required_annotations\x18\x02 \x03(\tR\x13requiredAnnotations\x12\x18\n" +
+	"\adisable\x18\x03 \x03(\tR\adisable\"\xa2\x01\n" +
+	"\x0fValidationError\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06column\x18\x02 \x01(\x05R\x06column\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x125\n" +
+	"\bseverity\x18\x04 \x01(\x0e2\x19.mermaidcheck.v1.SeverityR\bseverity\x12\x12\n" +
+	"\x04rule\x18\x05 \x01(\tR\x04rule\"\xea\x01\n" +
+	"\rDiagramResult\x12\x1f\n" +
+	"\vblock_index\x18\x01 \x01(\x05R\n" +
+	"blockIndex\x12!\n" +
+	"\fdiagram_type\x18\x02 \x01(\tR\vdiagramType\x12\x1f\n" +
+	"\vline_offset\x18\x03 \x01(\x05R\n" +
+	"lineOffset\x12\x19\n" +
+	"\bend_line\x18\x04 \x01(\x05R\aendLine\x12\x1f\n" +
+	"\vparse_error\x18\x05 \x01(\tR\n" +
+	"parseError\x128\n" +
+	"\x06errors\x18\x06 \x03(\v2 .mermaidcheck.v1.ValidationErrorR\x06errors\"d\n" +
+	"\x16ValidateDiagramRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x122\n" +
+	"\aoptions\x18\x02 \x01(\v2\x18.mermaidcheck.v1.OptionsR\aoptions\"Q\n" +
+	"\x17ValidateDiagramResponse\x126\n" +
+	"\x06result\x18\x01 \x01(\v2\x1e.mermaidcheck.v1.DiagramResultR\x06result\"i\n" +
+	"\x17ValidateMarkdownRequest\x12\x1a\n" +
+	"\bmarkdown\x18\x01 \x01(\tR\bmarkdown\x122\n" +
+	"\aoptions\x18\x02 \x01(\v2\x18.mermaidcheck.v1.OptionsR\aoptions\"T\n" +
+	"\x18ValidateMarkdownResponse\x128\n" +
+	"\aresults\x18\x01 \x03(\v2\x1e.mermaidcheck.v1.DiagramResultR\aresults\"h\n" +
+	"\x1eStreamValidateDirectoryRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x122\n" +
+	"\aoptions\x18\x02 \x01(\v2\x18.mermaidcheck.v1.OptionsR\aoptions\"Z\n" +
+	"\n" +
+	"FileResult\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x128\n" +
+	"\aresults\x18\x02 \x03(\v2\x1e.mermaidcheck.v1.DiagramResultR\aresults*a\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSEVERITY_ERROR\x10\x01\x12\x14\n" +
+	"\x10SEVERITY_WARNING\x10\x02\x12\x11\n" +
+	"\rSEVERITY_INFO\x10\x032\xcd\x02\n" +
+	"\x11ValidationService\x12d\n" +
+	"\x0fValidateDiagram\x12'.mermaidcheck.v1.ValidateDiagramRequest\x1a(.mermaidcheck.v1.ValidateDiagramResponse\x12g\n" +
+	"\x10ValidateMarkdown\x12(.mermaidcheck.v1.ValidateMarkdownRequest\x1a).mermaidcheck.v1.ValidateMarkdownResponse\x12i\n" +
+	"\x17StreamValidateDirectory\x12/.mermaidcheck.v1.StreamValidateDirectoryRequest\x1a\x1b.mermaidcheck.v1.FileResult0\x01BFZDgithub.com/sammcj/mermaid-check/proto/mermaidcheck/v1;mermaidcheckv1b\x06proto3"
+
+var (
+	file_mermaidcheck_v1_mermaidcheck_proto_rawDescOnce sync.Once
+	file_mermaidcheck_v1_mermaidcheck_proto_rawDescData []byte
+)
+
+func file_mermaidcheck_v1_mermaidcheck_proto_rawDescGZIP() []byte {
+	file_mermaidcheck_v1_mermaidcheck_proto_rawDescOnce.Do(func() {
+		file_mermaidcheck_v1_mermaidcheck_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_mermaidcheck_v1_mermaidcheck_proto_rawDesc), len(file_mermaidcheck_v1_mermaidcheck_proto_rawDesc)))
+	})
+	return file_mermaidcheck_v1_mermaidcheck_proto_rawDescData
+}
+
+var file_mermaidcheck_v1_mermaidcheck_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_mermaidcheck_v1_mermaidcheck_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_mermaidcheck_v1_mermaidcheck_proto_goTypes = []any{
+	(Severity)(0),                          // 0: mermaidcheck.v1.Severity
+	(*Options)(nil),                        // 1: mermaidcheck.v1.Options
+	(*ValidationError)(nil),                // 2: mermaidcheck.v1.ValidationError
+	(*DiagramResult)(nil),                  // 3: mermaidcheck.v1.DiagramResult
+	(*ValidateDiagramRequest)(nil),         // 4: mermaidcheck.v1.ValidateDiagramRequest
+	(*ValidateDiagramResponse)(nil),        // 5: mermaidcheck.v1.ValidateDiagramResponse
+	(*ValidateMarkdownRequest)(nil),        // 6: mermaidcheck.v1.ValidateMarkdownRequest
+	(*ValidateMarkdownResponse)(nil),       // 7: mermaidcheck.v1.ValidateMarkdownResponse
+	(*StreamValidateDirectoryRequest)(nil), // 8: mermaidcheck.v1.StreamValidateDirectoryRequest
+	(*FileResult)(nil),                     // 9: mermaidcheck.v1.FileResult
+}
+var file_mermaidcheck_v1_mermaidcheck_proto_depIdxs = []int32{
+	0,  // 0: mermaidcheck.v1.ValidationError.severity:type_name -> mermaidcheck.v1.Severity
+	2,  // 1: mermaidcheck.v1.DiagramResult.errors:type_name -> mermaidcheck.v1.ValidationError
+	1,  // 2: mermaidcheck.v1.ValidateDiagramRequest.options:type_name -> mermaidcheck.v1.Options
+	3,  // 3: mermaidcheck.v1.ValidateDiagramResponse.result:type_name -> mermaidcheck.v1.DiagramResult
+	1,  // 4: mermaidcheck.v1.ValidateMarkdownRequest.options:type_name -> mermaidcheck.v1.Options
+	3,  // 5: mermaidcheck.v1.ValidateMarkdownResponse.results:type_name -> mermaidcheck.v1.DiagramResult
+	1,  // 6: mermaidcheck.v1.StreamValidateDirectoryRequest.options:type_name -> mermaidcheck.v1.Options
+	3,  // 7: mermaidcheck.v1.FileResult.results:type_name -> mermaidcheck.v1.DiagramResult
+	4,  // 8: mermaidcheck.v1.ValidationService.ValidateDiagram:input_type -> mermaidcheck.v1.ValidateDiagramRequest
+	6,  // 9: mermaidcheck.v1.ValidationService.ValidateMarkdown:input_type -> mermaidcheck.v1.ValidateMarkdownRequest
+	8,  // 10: mermaidcheck.v1.ValidationService.StreamValidateDirectory:input_type -> mermaidcheck.v1.StreamValidateDirectoryRequest
+	5,  // 11: mermaidcheck.v1.ValidationService.ValidateDiagram:output_type -> mermaidcheck.v1.ValidateDiagramResponse
+	7,  // 12: mermaidcheck.v1.ValidationService.ValidateMarkdown:output_type -> mermaidcheck.v1.ValidateMarkdownResponse
+	9,  // 13: mermaidcheck.v1.ValidationService.StreamValidateDirectory:output_type -> mermaidcheck.v1.FileResult
+	11, // [11:14] is the sub-list for method output_type
+	8,  // [8:11] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_mermaidcheck_v1_mermaidcheck_proto_init() }
+func file_mermaidcheck_v1_mermaidcheck_proto_init() {
+	if File_mermaidcheck_v1_mermaidcheck_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mermaidcheck_v1_mermaidcheck_proto_rawDesc), len(file_mermaidcheck_v1_mermaidcheck_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_mermaidcheck_v1_mermaidcheck_proto_goTypes,
+		DependencyIndexes: file_mermaidcheck_v1_mermaidcheck_proto_depIdxs,
+		EnumInfos:         file_mermaidcheck_v1_mermaidcheck_proto_enumTypes,
+		MessageInfos:      file_mermaidcheck_v1_mermaidcheck_proto_msgTypes,
+	}.Build()
+	File_mermaidcheck_v1_mermaidcheck_proto = out.File
+	file_mermaidcheck_v1_mermaidcheck_proto_goTypes = nil
+	file_mermaidcheck_v1_mermaidcheck_proto_depIdxs = nil
+}
@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: mermaidcheck/v1/mermaidcheck.proto
+
+package mermaidcheckv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ValidationService_ValidateDiagram_FullMethodName         = "/mermaidcheck.v1.ValidationService/ValidateDiagram"
+	ValidationService_ValidateMarkdown_FullMethodName        = "/mermaidcheck.v1.ValidationService/ValidateMarkdown"
+	ValidationService_StreamValidateDirectory_FullMethodName = "/mermaidcheck.v1.ValidationService/StreamValidateDirectory"
+)
+
+// ValidationServiceClient is the client API for ValidationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ValidationService validates Mermaid diagrams, so mermaid-check can run as a
+// shared validation service with clients generated in any language.
+type ValidationServiceClient interface {
+	// ValidateDiagram validates a single raw Mermaid diagram.
+	ValidateDiagram(ctx context.Context, in *ValidateDiagramRequest, opts ...grpc.CallOption) (*ValidateDiagramResponse, error)
+	// ValidateMarkdown validates every Mermaid block in a markdown document.
+	ValidateMarkdown(ctx context.Context, in *ValidateMarkdownRequest, opts ...grpc.CallOption) (*ValidateMarkdownResponse, error)
+	// StreamValidateDirectory validates every Mermaid and markdown file under a
+	// directory on the server, sending one result per file as it is checked.
+	StreamValidateDirectory(ctx context.Context, in *StreamValidateDirectoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileResult], error)
+}
+
+type validationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewValidationServiceClient(cc grpc.ClientConnInterface) ValidationServiceClient {
+	return &validationServiceClient{cc}
+}
+
+func (c *validationServiceClient) ValidateDiagram(ctx context.Context, in *ValidateDiagramRequest, opts ...grpc.CallOption) (*ValidateDiagramResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateDiagramResponse)
+	err := c.cc.Invoke(ctx, ValidationService_ValidateDiagram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) ValidateMarkdown(ctx context.Context, in *ValidateMarkdownRequest, opts ...grpc.CallOption) (*ValidateMarkdownResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateMarkdownResponse)
+	err := c.cc.Invoke(ctx, ValidationService_ValidateMarkdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *validationServiceClient) StreamValidateDirectory(ctx context.Context, in *StreamValidateDirectoryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ValidationService_ServiceDesc.Streams[0], ValidationService_StreamValidateDirectory_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamValidateDirectoryRequest, FileResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ValidationService_StreamValidateDirectoryClient = grpc.ServerStreamingClient[FileResult]
+
+// ValidationServiceServer is the server API for ValidationService service.
+// All implementations must embed UnimplementedValidationServiceServer
+// for forward compatibility.
+//
+// ValidationService validates Mermaid diagrams, so mermaid-check can run as a
+// shared validation service with clients generated in any language.
+type ValidationServiceServer interface {
+	// ValidateDiagram validates a single raw Mermaid diagram.
+	ValidateDiagram(context.Context, *ValidateDiagramRequest) (*ValidateDiagramResponse, error)
+	// ValidateMarkdown validates every Mermaid block in a markdown document.
+	ValidateMarkdown(context.Context, *ValidateMarkdownRequest) (*ValidateMarkdownResponse, error)
+	// StreamValidateDirectory validates every Mermaid and markdown file under a
+	// directory on the server, sending one result per file as it is checked.
+	StreamValidateDirectory(*StreamValidateDirectoryRequest, grpc.ServerStreamingServer[FileResult]) error
+	mustEmbedUnimplementedValidationServiceServer()
+}
+
+// UnimplementedValidationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedValidationServiceServer struct{}
+
+func (UnimplementedValidationServiceServer) ValidateDiagram(context.Context, *ValidateDiagramRequest) (*ValidateDiagramResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateDiagram not implemented")
+}
+func (UnimplementedValidationServiceServer) ValidateMarkdown(context.Context, *ValidateMarkdownRequest) (*ValidateMarkdownResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateMarkdown not implemented")
+}
+func (UnimplementedValidationServiceServer) StreamValidateDirectory(*StreamValidateDirectoryRequest, grpc.ServerStreamingServer[FileResult]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamValidateDirectory not implemented")
+}
+func (UnimplementedValidationServiceServer) mustEmbedUnimplementedValidationServiceServer() {}
+func (UnimplementedValidationServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeValidationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ValidationServiceServer will
+// result in compilation errors.
+type UnsafeValidationServiceServer interface {
+	mustEmbedUnimplementedValidationServiceServer()
+}
+
+func RegisterValidationServiceServer(s grpc.ServiceRegistrar, srv ValidationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedValidationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ValidationService_ServiceDesc, srv)
+}
+
+func _ValidationService_ValidateDiagram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateDiagramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).ValidateDiagram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidationService_ValidateDiagram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).ValidateDiagram(ctx, req.(*ValidateDiagramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_ValidateMarkdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateMarkdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ValidationServiceServer).ValidateMarkdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ValidationService_ValidateMarkdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ValidationServiceServer).ValidateMarkdown(ctx, req.(*ValidateMarkdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ValidationService_StreamValidateDirectory_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamValidateDirectoryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ValidationServiceServer).StreamValidateDirectory(m, &grpc.GenericServerStream[StreamValidateDirectoryRequest, FileResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ValidationService_StreamValidateDirectoryServer = grpc.ServerStreamingServer[FileResult]
+
+// ValidationService_ServiceDesc is the grpc.ServiceDesc for ValidationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ValidationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mermaidcheck.v1.ValidationService",
+	HandlerType: (*ValidationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateDiagram",
+			Handler:    _ValidationService_ValidateDiagram_Handler,
+		},
+		{
+			MethodName: "ValidateMarkdown",
+			Handler:    _ValidationService_ValidateMarkdown_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamValidateDirectory",
+			Handler:       _ValidationService_StreamValidateDirectory_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "mermaidcheck/v1/mermaidcheck.proto",
+}
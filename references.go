@@ -0,0 +1,125 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// ReferenceError is a broken or conflicting cross-diagram reference found by CheckReferences.
+type ReferenceError struct {
+	File       string // File containing the problem
+	BlockIndex int    // Index of the diagram within File (0-based)
+	Line       int    // Line in File (1-indexed)
+	Message    string
+	Rule       string // Name of the check that reported the problem, if any
+}
+
+func (e ReferenceError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// CheckReferences checks links between diagrams across a set of results, which
+// should normally cover a whole documentation tree (see ValidatePath).
+//
+// Diagrams are identified by the `id` key of their metadata, taken from a
+// `%% @id: ...` annotation or from frontmatter. A click/link statement whose URL
+// is a bare fragment such as `#ARCH-42` is treated as a reference to the diagram
+// with that ID, and is reported if no such diagram exists. Diagrams sharing an
+// ID are also reported, since references to them would be ambiguous.
+func CheckReferences(results []Result) []ReferenceError {
+	var errors []ReferenceError
+	index := make(map[string]*Result)
+
+	for i := range results {
+		r := &results[i]
+		id := r.Metadata["id"]
+		if id == "" {
+			continue
+		}
+		if first, exists := index[id]; exists {
+			errors = append(errors, ReferenceError{
+				File:       r.File,
+				BlockIndex: r.BlockIndex,
+				Line:       r.LineOffset,
+				Message:    fmt.Sprintf("duplicate diagram id '%s' (first defined in %s at line %d)", id, first.File, first.LineOffset),
+			})
+			continue
+		}
+		index[id] = r
+	}
+
+	for i := range results {
+		r := &results[i]
+		if r.Diagram == nil {
+			continue
+		}
+		for _, interaction := range diagramInteractions(r.Diagram) {
+			target, ok := strings.CutPrefix(interaction.URL, "#")
+			if interaction.Kind != "href" || !ok || target == "" {
+				continue
+			}
+			if _, exists := index[target]; !exists {
+				errors = append(errors, ReferenceError{
+					File:       r.File,
+					BlockIndex: r.BlockIndex,
+					Line:       r.LineOffset + interaction.Pos.Line - 1,
+					Message:    fmt.Sprintf("link on '%s' references unknown diagram id '%s'", interaction.NodeID, target),
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+// addReferenceErrors adds each reference error to the Errors of the result it
+// belongs to, keeping them sorted.
+func addReferenceErrors(results []Result, errors []ReferenceError) {
+	for _, e := range errors {
+		for i := range results {
+			r := &results[i]
+			if r.File != e.File || r.BlockIndex != e.BlockIndex {
+				continue
+			}
+			r.Errors = append(r.Errors, validator.ValidationError{
+				Line:     e.Line - r.LineOffset + 1,
+				Column:   1,
+				Message:  e.Message,
+				Severity: validator.SeverityError,
+				Rule:     e.Rule,
+			})
+			validator.SortErrors(r.Errors)
+			break
+		}
+	}
+}
+
+// diagramInteractions returns the click/link statements of diagram types that support them.
+func diagramInteractions(diagram ast.Diagram) []*ast.Interaction {
+	var interactions []*ast.Interaction
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		collectInteractions(d.Statements, &interactions)
+	case *ast.ClassDiagram:
+		for _, stmt := range d.Statements {
+			if interaction, ok := stmt.(*ast.Interaction); ok {
+				interactions = append(interactions, interaction)
+			}
+		}
+	}
+	return interactions
+}
+
+func collectInteractions(statements []ast.Statement, interactions *[]*ast.Interaction) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Interaction:
+			*interactions = append(*interactions, s)
+		case *ast.Subgraph:
+			collectInteractions(s.Statements, interactions)
+		}
+	}
+}
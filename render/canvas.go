@@ -0,0 +1,231 @@
+package render
+
+import "strings"
+
+// Charset is the set of characters text is drawn with.
+type Charset int
+
+// Supported character sets.
+const (
+	Unicode Charset = iota // Box-drawing characters and arrows
+	ASCII                  // Plain ASCII, for terminals and logs without Unicode
+)
+
+// Directions a line leaves a cell in, combined into a cell's mask.
+const (
+	lineUp uint8 = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+// Box-drawing characters by mask.
+var unicodeLines = [16]rune{
+	' ', '│', '│', '│', '─', '┘', '┐', '┤',
+	'─', '└', '┌', '├', '─', '┴', '┬', '┼',
+}
+
+// Arrowheads, pointing up, down, left and right.
+var (
+	unicodeHeads = [4]rune{'▲', '▼', '◀', '▶'}
+	asciiHeads   = [4]rune{'^', 'v', '<', '>'}
+)
+
+const (
+	headUp = iota
+	headDown
+	headLeft
+	headRight
+)
+
+// canvas is a grid of cells that lines merge on: a horizontal line crossing a
+// vertical one is drawn as a junction. Text drawn on a cell replaces its line.
+type canvas struct {
+	charset Charset
+	masks   [][]uint8
+	text    [][]rune
+	dashed  [][]bool
+}
+
+func newCanvas(charset Charset) *canvas {
+	return &canvas{charset: charset}
+}
+
+// grow makes sure the cell at row, col exists.
+func (c *canvas) grow(row, col int) {
+	for len(c.masks) <= row {
+		c.masks = append(c.masks, nil)
+		c.text = append(c.text, nil)
+		c.dashed = append(c.dashed, nil)
+	}
+	for len(c.masks[row]) <= col {
+		c.masks[row] = append(c.masks[row], 0)
+		c.text[row] = append(c.text[row], 0)
+		c.dashed[row] = append(c.dashed[row], false)
+	}
+}
+
+// free reports whether nothing is drawn on the cells of row from col for n
+// cells.
+func (c *canvas) free(row, col, n int) bool {
+	for i := col; i < col+n; i++ {
+		if row < len(c.masks) && i < len(c.masks[row]) && (c.masks[row][i] != 0 || c.text[row][i] != 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// hline draws a horizontal line on row between columns from and to inclusive.
+func (c *canvas) hline(row, from, to int) {
+	if from > to {
+		from, to = to, from
+	}
+	for col := from; col <= to; col++ {
+		c.grow(row, col)
+		if col > from {
+			c.masks[row][col] |= lineLeft
+		}
+		if col < to {
+			c.masks[row][col] |= lineRight
+		}
+	}
+}
+
+// vline draws a vertical line on col between rows from and to inclusive.
+func (c *canvas) vline(col, from, to int) {
+	if from > to {
+		from, to = to, from
+	}
+	for row := from; row <= to; row++ {
+		c.grow(row, col)
+		if row > from {
+			c.masks[row][col] |= lineUp
+		}
+		if row < to {
+			c.masks[row][col] |= lineDown
+		}
+	}
+}
+
+// box draws a box with s inside it, its top left corner at row, col, and
+// returns its width.
+func (c *canvas) box(row, col int, s string) int {
+	width := textWidth(s) + 4
+	c.rect(row, col, row+2, col+width-1)
+	c.write(row+1, col+2, s)
+	return width
+}
+
+// rect draws the outline of a rectangle between two corners.
+func (c *canvas) rect(top, left, bottom, right int) {
+	c.hline(top, left, right)
+	c.hline(bottom, left, right)
+	c.vline(left, top, bottom)
+	c.vline(right, top, bottom)
+}
+
+// write draws s on row from col.
+func (c *canvas) write(row, col int, s string) {
+	for _, r := range s {
+		c.grow(row, col)
+		c.text[row][col] = r
+		col++
+	}
+}
+
+// head draws an arrowhead pointing in direction at row, col.
+func (c *canvas) head(row, col, direction int) {
+	heads := unicodeHeads
+	if c.charset == ASCII {
+		heads = asciiHeads
+	}
+	c.grow(row, col)
+	c.text[row][col] = heads[direction]
+}
+
+// dash marks the line through row, col as dashed. Junctions are drawn solid,
+// including those made by lines drawn later.
+func (c *canvas) dash(row, col int) {
+	c.grow(row, col)
+	c.dashed[row][col] = true
+}
+
+// Orientations of the first leg of a path drawn by dashPath.
+const (
+	vertical   = true
+	horizontal = false
+)
+
+// dashPath marks as dashed a path with three legs: along from between start
+// and turn, across turn from from to to, then along to between turn and end.
+// The first leg is vertical (from and to are columns) or horizontal (from and
+// to are rows).
+func (c *canvas) dashPath(firstVertical bool, from, start, turn, to, end int) {
+	cell := func(along, across int) {
+		if firstVertical {
+			c.dash(along, across)
+		} else {
+			c.dash(across, along)
+		}
+	}
+	for i := min(start, turn); i <= max(start, turn); i++ {
+		cell(i, from)
+	}
+	for i := min(from, to); i <= max(from, to); i++ {
+		cell(turn, i)
+	}
+	for i := min(turn, end); i <= max(turn, end); i++ {
+		cell(i, to)
+	}
+}
+
+// pick returns unicode or ascii, for the canvas's charset.
+func (c *canvas) pick(unicode, ascii rune) rune {
+	if c.charset == ASCII {
+		return ascii
+	}
+	return unicode
+}
+
+// String returns the canvas as lines of text without trailing spaces.
+func (c *canvas) String() string {
+	var b strings.Builder
+	for row := range c.masks {
+		var line strings.Builder
+		for col, mask := range c.masks[row] {
+			switch {
+			case c.text[row][col] != 0:
+				line.WriteRune(c.text[row][col])
+			case c.dashed[row][col] && (mask == lineLeft|lineRight || mask == lineLeft || mask == lineRight):
+				line.WriteRune(c.pick('╌', '.'))
+			case c.dashed[row][col] && (mask == lineUp|lineDown || mask == lineUp || mask == lineDown):
+				line.WriteRune(c.pick('╎', ':'))
+			case c.charset == ASCII:
+				line.WriteRune(asciiLine(mask))
+			default:
+				line.WriteRune(unicodeLines[mask])
+			}
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func asciiLine(mask uint8) rune {
+	switch mask {
+	case 0:
+		return ' '
+	case lineUp, lineDown, lineUp | lineDown:
+		return '|'
+	case lineLeft, lineRight, lineLeft | lineRight:
+		return '-'
+	}
+	return '+'
+}
+
+// textWidth returns the number of cells s takes up.
+func textWidth(s string) int {
+	return len([]rune(s))
+}
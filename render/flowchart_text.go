@@ -0,0 +1,310 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// segment is the part of an edge between a vertex and the next one on its
+// path, in the gap between their layers.
+type segment struct {
+	edge     *edge
+	from, to int  // Vertex indices
+	first    bool // Whether this is the first segment of the edge
+	last     bool // Whether this is the last segment of the edge
+}
+
+// segmentsFrom returns the segments of l's edges leaving the given layer.
+func (l *layout) segmentsFrom(layer int) []segment {
+	var segments []segment
+	for i := range l.edges {
+		e := &l.edges[i]
+		for j := 1; j < len(e.path); j++ {
+			if l.vertices[e.path[j-1]].layer == layer {
+				segments = append(segments, segment{
+					edge:  e,
+					from:  e.path[j-1],
+					to:    e.path[j],
+					first: j == 1,
+					last:  j == len(e.path)-1,
+				})
+			}
+		}
+	}
+	return segments
+}
+
+// tracks numbers the source vertices of the segments that don't run straight
+// from one port to the next, giving each its own track in the gap.
+func tracks(segments []segment, straight func(segment) bool) map[int]int {
+	track := make(map[int]int)
+	for _, s := range segments {
+		if _, ok := track[s.from]; !ok && !straight(s) {
+			track[s.from] = len(track)
+		}
+	}
+	return track
+}
+
+// flowchartText draws l's boxes and arrows on a canvas, with a note for each
+// link from a node to itself and each label there was no room for.
+func flowchartText(l *layout, charset Charset) string {
+	if len(l.layers) == 0 {
+		return ""
+	}
+	c := newCanvas(charset)
+	var notes []string
+	if l.vertical {
+		notes = drawLayersDown(c, l)
+	} else {
+		notes = drawLayersAcross(c, l)
+	}
+	for _, loop := range l.loops {
+		notes = append(notes, linkNote(c, loop.From, loop.To, plainLabel(loop.Label)))
+	}
+
+	out := c.String()
+	if len(notes) > 0 {
+		out += "\n" + strings.Join(notes, "\n") + "\n"
+	}
+	return out
+}
+
+func linkNote(c *canvas, from, to, label string) string {
+	note := fmt.Sprintf("%s %c %s", from, c.pick('→', '>'), to)
+	if label != "" {
+		note += ": " + label
+	}
+	return note
+}
+
+// vertexWidth returns the width of a vertex's box, or 1 for a dummy vertex.
+func vertexWidth(v vertex) int {
+	if v.dummy {
+		return 1
+	}
+	return textWidth(v.label) + 4
+}
+
+// drawLayersDown draws layers as rows of boxes from the top, with the links
+// between two layers routed in the rows between them.
+func drawLayersDown(c *canvas, l *layout) []string {
+	left := make([]int, len(l.vertices))
+	centre := func(v int) int { return left[v] + vertexWidth(l.vertices[v])/2 }
+
+	widths := make([]int, len(l.layers))
+	for i, layer := range l.layers {
+		for j, v := range layer {
+			if j > 0 {
+				widths[i] += 2
+			}
+			widths[i] += vertexWidth(l.vertices[v])
+		}
+	}
+	widest := slices.Max(widths)
+	for i, layer := range l.layers {
+		x := (widest - widths[i]) / 2
+		for _, v := range layer {
+			left[v] = x
+			x += vertexWidth(l.vertices[v]) + 2
+		}
+	}
+
+	var notes []string
+	top := 0
+	for i, layer := range l.layers {
+		for _, v := range layer {
+			if l.vertices[v].dummy {
+				c.vline(left[v], top, top+2)
+				if l.vertices[v].dashed {
+					c.dashPath(vertical, left[v], top, top+2, left[v], top+2)
+				}
+			} else {
+				c.box(top, left[v], l.vertices[v].label)
+			}
+		}
+		if i == len(l.layers)-1 {
+			break
+		}
+
+		segments := l.segmentsFrom(i)
+		straight := func(s segment) bool { return centre(s.from) == centre(s.to) }
+		track := tracks(segments, straight)
+		gap := top + 3
+		arrowRow := gap + len(track) + 1
+		next := arrowRow + 1
+
+		for _, s := range segments {
+			from, to := centre(s.from), centre(s.to)
+			start := top + 2
+			if s.first && s.edge.headStart {
+				start = gap
+			}
+			turn := arrowRow
+			if !straight(s) {
+				turn = gap + 1 + track[s.from]
+			}
+			end := next
+			if s.last && s.edge.headEnd {
+				end = arrowRow
+			}
+			c.vline(from, start, turn)
+			c.hline(turn, from, to)
+			c.vline(to, turn, end)
+			if s.edge.dashed {
+				c.dashPath(vertical, from, start, turn, to, end)
+			}
+		}
+		for _, s := range segments {
+			if s.first && s.edge.headStart {
+				c.head(gap, centre(s.from), headUp)
+			}
+			if s.last && s.edge.headEnd {
+				c.head(arrowRow, centre(s.to), headDown)
+			}
+		}
+		for _, s := range segments {
+			if !s.last || s.edge.label == "" {
+				continue
+			}
+			col := centre(s.to) + 2
+			if c.free(arrowRow, col-1, textWidth(s.edge.label)+2) {
+				c.write(arrowRow, col, s.edge.label)
+			} else {
+				notes = append(notes, l.edgeNote(c, s.edge))
+			}
+		}
+		top = next
+	}
+	return notes
+}
+
+// drawLayersAcross draws layers as columns of boxes from the left, with the
+// links between two layers routed in the columns between them.
+func drawLayersAcross(c *canvas, l *layout) []string {
+	row := make([]int, len(l.vertices)) // Top row of each vertex
+	middle := func(v int) int {
+		if l.vertices[v].dummy {
+			return row[v]
+		}
+		return row[v] + 1
+	}
+
+	heights := make([]int, len(l.layers))
+	for i, layer := range l.layers {
+		for j, v := range layer {
+			if j > 0 {
+				heights[i]++
+			}
+			if l.vertices[v].dummy {
+				heights[i]++
+			} else {
+				heights[i] += 3
+			}
+		}
+	}
+	tallest := slices.Max(heights)
+	for i, layer := range l.layers {
+		y := (tallest - heights[i]) / 2
+		for _, v := range layer {
+			row[v] = y
+			if l.vertices[v].dummy {
+				y += 2
+			} else {
+				y += 4
+			}
+		}
+	}
+
+	var notes []string
+	left := 0
+	for i, layer := range l.layers {
+		width := 0
+		for _, v := range layer {
+			width = max(width, vertexWidth(l.vertices[v]))
+		}
+		right := make(map[int]int) // Column each vertex's links leave from
+		for _, v := range layer {
+			if l.vertices[v].dummy {
+				c.hline(row[v], left, left+width-1)
+				if l.vertices[v].dashed {
+					c.dashPath(horizontal, row[v], left, left+width-1, row[v], left+width-1)
+				}
+				right[v] = left + width - 1
+			} else {
+				right[v] = left + c.box(row[v], left, l.vertices[v].label) - 1
+			}
+		}
+		if i == len(l.layers)-1 {
+			break
+		}
+
+		segments := l.segmentsFrom(i)
+		straight := func(s segment) bool { return middle(s.from) == middle(s.to) }
+		track := tracks(segments, straight)
+		labelWidth := 0
+		for _, s := range segments {
+			if s.last && s.edge.label != "" {
+				labelWidth = max(labelWidth, textWidth(s.edge.label)+2)
+			}
+		}
+		gap := left + width
+		labelCol := gap + len(track) + 1
+		arrowCol := labelCol + labelWidth + 1
+		next := arrowCol + 1
+
+		for _, s := range segments {
+			from, to := middle(s.from), middle(s.to)
+			start := right[s.from]
+			if s.first && s.edge.headStart {
+				start++
+			}
+			turn := gap
+			if !straight(s) {
+				turn = gap + 1 + track[s.from]
+			}
+			end := next
+			if s.last && s.edge.headEnd {
+				end = arrowCol
+			}
+			c.hline(from, start, turn)
+			c.vline(turn, from, to)
+			c.hline(to, turn, end)
+			if s.edge.dashed {
+				c.dashPath(horizontal, from, start, turn, to, end)
+			}
+		}
+		for _, s := range segments {
+			if s.first && s.edge.headStart {
+				c.head(middle(s.from), right[s.from]+1, headLeft)
+			}
+			if s.last && s.edge.headEnd {
+				c.head(middle(s.to), arrowCol, headRight)
+			}
+		}
+		labelled := make(map[int]bool) // Rows already carrying a label
+		for _, s := range segments {
+			if !s.last || s.edge.label == "" {
+				continue
+			}
+			if to := middle(s.to); !labelled[to] {
+				c.write(to, labelCol+1, s.edge.label)
+				labelled[to] = true
+			} else {
+				notes = append(notes, l.edgeNote(c, s.edge))
+			}
+		}
+		left = next
+	}
+	return notes
+}
+
+// edgeNote describes an edge whose label there was no room to draw.
+func (l *layout) edgeNote(c *canvas, e *edge) string {
+	from, to := l.vertices[e.path[0]].id, l.vertices[e.path[len(e.path)-1]].id
+	if e.headStart && !e.headEnd {
+		from, to = to, from
+	}
+	return linkNote(c, from, to, e.label)
+}
@@ -0,0 +1,272 @@
+// Package render draws parsed diagrams without a browser, as text for
+// terminals, for quick visual checks of what a diagram says.
+package render
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// layout is a flowchart arranged in layers, Sugiyama-style: every link runs
+// from one layer to a later one, through a dummy vertex in each layer it
+// crosses, and vertices are ordered within their layer to reduce crossings.
+type layout struct {
+	vertical bool        // Layers run top to bottom (TB, TD, BT) rather than left to right
+	vertices []vertex    // Nodes, then dummy vertices
+	layers   [][]int     // Vertex indices of each layer, in order
+	edges    []edge      // Drawn links
+	loops    []*ast.Link // Links from a node to itself, which are not drawn
+}
+
+type vertex struct {
+	id     string
+	label  string
+	shape  string // Brackets of the node's shape, such as "[]" or "{}"
+	dummy  bool
+	dashed bool // Whether the link through a dummy vertex is dashed
+	layer  int
+}
+
+// edge is a link drawn along path, the vertices it passes through in layer
+// order, with arrowheads at either end.
+type edge struct {
+	path      []int
+	headStart bool
+	headEnd   bool
+	label     string
+	dashed    bool
+}
+
+// layoutFlowchart arranges the nodes and links of flowchart in layers. The
+// direction decides which way layers run; subgraphs are not drawn.
+func layoutFlowchart(flowchart *ast.Flowchart) *layout {
+	l := &layout{}
+	index := make(map[string]int)
+	defs := make(map[string]*ast.NodeDef)
+	var links []*ast.Link
+	collectGraph(flowchart.Statements, defs, &links, func(id string) {
+		if _, ok := index[id]; !ok {
+			index[id] = len(l.vertices)
+			l.vertices = append(l.vertices, vertex{id: id, label: id})
+		}
+	})
+	for i := range l.vertices {
+		if def, ok := defs[l.vertices[i].id]; ok {
+			l.vertices[i].shape = def.Shape
+			if label := plainLabel(def.Label); label != "" {
+				l.vertices[i].label = label
+			}
+		}
+	}
+
+	var ranked []*ast.Link
+	for _, link := range links {
+		if link.From == link.To {
+			l.loops = append(l.loops, link)
+			continue
+		}
+		ranked = append(ranked, link)
+	}
+	reversed := backLinks(len(l.vertices), ranked, index)
+	assignLayers(l.vertices, ranked, index, reversed)
+
+	direction := strings.ToUpper(flowchart.Direction)
+	l.vertical = direction != "LR" && direction != "RL"
+	flip := direction == "BT" || direction == "RL"
+	if flip {
+		last := 0
+		for _, v := range l.vertices {
+			last = max(last, v.layer)
+		}
+		for i := range l.vertices {
+			l.vertices[i].layer = last - l.vertices[i].layer
+		}
+	}
+
+	for i, link := range ranked {
+		if link.Arrow == "~~~" {
+			continue // Invisible links only affect layers
+		}
+		from, to := index[link.From], index[link.To]
+		headStart, headEnd := link.BiDir, hasHead(link.Arrow)
+		if reversed[i] != flip {
+			from, to = to, from
+			headStart, headEnd = headEnd, headStart
+		}
+		dashed := strings.Contains(link.Arrow, ".")
+		l.edges = append(l.edges, edge{
+			path:      l.route(from, to, dashed),
+			headStart: headStart,
+			headEnd:   headEnd,
+			label:     plainLabel(link.Label),
+			dashed:    dashed,
+		})
+	}
+
+	l.layers = make([][]int, 0)
+	for i, v := range l.vertices {
+		for len(l.layers) <= v.layer {
+			l.layers = append(l.layers, nil)
+		}
+		l.layers[v.layer] = append(l.layers[v.layer], i)
+	}
+	l.orderLayers()
+	return l
+}
+
+// collectGraph records the node definitions giving a shape and the links in
+// statements, calling mention for every node in the order it is first
+// mentioned.
+func collectGraph(statements []ast.Statement, defs map[string]*ast.NodeDef, links *[]*ast.Link, mention func(string)) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			mention(s.ID)
+			if s.Shape != "" {
+				defs[s.ID] = s
+			}
+		case *ast.Link:
+			mention(s.From)
+			mention(s.To)
+			*links = append(*links, s)
+		case *ast.Subgraph:
+			collectGraph(s.Statements, defs, links, mention)
+		}
+	}
+}
+
+// plainLabel flattens line breaks and Markdown quoting in a label to one line.
+func plainLabel(label string) string {
+	for _, br := range []string{"<br/>", "<br />", "<br>", "\\n"} {
+		label = strings.ReplaceAll(label, br, " ")
+	}
+	label = strings.TrimPrefix(strings.TrimSuffix(label, "`"), "`")
+	return strings.Join(strings.Fields(label), " ")
+}
+
+// hasHead reports whether a flowchart arrow ends in an arrowhead, cross or
+// circle rather than being an open link.
+func hasHead(arrow string) bool {
+	return strings.HasSuffix(arrow, ">") || strings.HasSuffix(arrow, "x") || strings.HasSuffix(arrow, "o")
+}
+
+// backLinks marks the links that close a cycle, found by a depth-first search
+// from each node in the order they were first mentioned. Laying these out
+// backwards leaves the graph acyclic.
+func backLinks(n int, links []*ast.Link, index map[string]int) []bool {
+	out := make([][]int, n)
+	for i, link := range links {
+		out[index[link.From]] = append(out[index[link.From]], i)
+	}
+	reversed := make([]bool, len(links))
+	state := make([]int, n) // 0 unvisited, 1 on the stack, 2 done
+	var visit func(int)
+	visit = func(v int) {
+		state[v] = 1
+		for _, i := range out[v] {
+			switch to := index[links[i].To]; state[to] {
+			case 0:
+				visit(to)
+			case 1:
+				reversed[i] = true
+			}
+		}
+		state[v] = 2
+	}
+	for v := range n {
+		if state[v] == 0 {
+			visit(v)
+		}
+	}
+	return reversed
+}
+
+// assignLayers places every node at least as many layers after each node
+// linking to it as the link is long, with reversed links counted backwards.
+func assignLayers(vertices []vertex, links []*ast.Link, index map[string]int, reversed []bool) {
+	// Relax the constraints until they hold; the links form a DAG, so this
+	// settles within one pass per node
+	for range vertices {
+		changed := false
+		for i, link := range links {
+			from, to := index[link.From], index[link.To]
+			if reversed[i] {
+				from, to = to, from
+			}
+			if rank := vertices[from].layer + max(link.Length, 1); vertices[to].layer < rank {
+				vertices[to].layer = rank
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// route returns the path of a link from vertex from to a vertex in a later
+// layer, adding a dummy vertex in each layer between them.
+func (l *layout) route(from, to int, dashed bool) []int {
+	path := []int{from}
+	for layer := l.vertices[from].layer + 1; layer < l.vertices[to].layer; layer++ {
+		path = append(path, len(l.vertices))
+		l.vertices = append(l.vertices, vertex{dummy: true, dashed: dashed, layer: layer})
+	}
+	return append(path, to)
+}
+
+// orderLayers reorders each layer by the average position of its neighbours
+// in the layer before, then after, a few times over.
+func (l *layout) orderLayers() {
+	up := make([][]int, len(l.vertices))
+	down := make([][]int, len(l.vertices))
+	for _, e := range l.edges {
+		for i := 1; i < len(e.path); i++ {
+			down[e.path[i-1]] = append(down[e.path[i-1]], e.path[i])
+			up[e.path[i]] = append(up[e.path[i]], e.path[i-1])
+		}
+	}
+
+	position := make([]float64, len(l.vertices))
+	place := func(layer []int) {
+		for i, v := range layer {
+			position[v] = float64(i)
+		}
+	}
+	for _, layer := range l.layers {
+		place(layer)
+	}
+	sweep := func(layer []int, neighbours [][]int) {
+		centre := make(map[int]float64, len(layer))
+		for _, v := range layer {
+			centre[v] = position[v]
+			if len(neighbours[v]) > 0 {
+				sum := 0.0
+				for _, n := range neighbours[v] {
+					sum += position[n]
+				}
+				centre[v] = sum / float64(len(neighbours[v]))
+			}
+		}
+		slices.SortStableFunc(layer, func(a, b int) int {
+			switch {
+			case centre[a] < centre[b]:
+				return -1
+			case centre[a] > centre[b]:
+				return 1
+			}
+			return 0
+		})
+		place(layer)
+	}
+	for range 4 {
+		for i := 1; i < len(l.layers); i++ {
+			sweep(l.layers[i], up)
+		}
+		for i := len(l.layers) - 2; i >= 0; i-- {
+			sweep(l.layers[i], down)
+		}
+	}
+}
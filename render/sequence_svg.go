@@ -0,0 +1,169 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// Measurements of sequence diagram drawings, in pixels.
+const (
+	svgBoxHeight   = 36.0
+	svgMessageStep = 40.0 // Height of a message with its text
+	svgBlockInset  = 10.0 // Gap between a block frame and the frame around it
+)
+
+// sequenceSVG draws a sequence diagram with the lifeline of each participant
+// between its box at the top and bottom, and messages, notes and blocks drawn
+// between them from top to bottom. Columns are spaced as for Text.
+func sequenceSVG(diagram *ast.SequenceDiagram) string {
+	w := &svgWriter{}
+	s := participantsOf(diagram.Statements)
+	if len(s.ids) == 0 {
+		return w.String()
+	}
+	numbers := make(map[*ast.Message]int)
+	numberMessages(diagram.Statements, numbers)
+	numbered := func(m *ast.Message) string {
+		text := plainLabel(m.Text)
+		if number, ok := numbers[m]; ok {
+			return strings.TrimSpace(fmt.Sprintf("%d. %s", number, text))
+		}
+		return text
+	}
+	s.placeColumns(diagram.Statements, numbered)
+
+	// Statements are drawn first, to find where lifelines end, but are placed
+	// over the lifelines in the document
+	d := &sequenceSVGDrawing{w: &svgWriter{}, layout: s, numbered: numbered, y: svgMargin + svgBoxHeight + 20}
+	d.x = make([]float64, len(s.ids))
+	for i, centre := range s.centre {
+		d.x[i] = svgMargin + float64(centre)*svgCharWidth
+	}
+	last := len(s.ids) - 1
+	d.left = svgMargin
+	d.right = d.x[last] + (textPixels(s.labels[last])+32)/2 + float64(s.depth)*svgBlockInset
+	d.statements(diagram.Statements, 0)
+
+	bottom := d.y + 10
+	for i, x := range d.x {
+		w.line(point{x, svgMargin + svgBoxHeight}, point{x, bottom}, ` stroke-dasharray="3 3"`)
+		width := textPixels(s.labels[i]) + 32
+		for _, top := range []float64{svgMargin, bottom} {
+			w.element(`<rect x="%s" y="%s" width="%s" height="%s" fill="%s" stroke="%s" stroke-width="1.5"/>`,
+				num(x-width/2), num(top), num(width), num(svgBoxHeight), svgFillColour, svgEdgeColour)
+			w.text(x, top+svgBoxHeight/2, s.labels[i], "middle")
+			w.extend(x+width/2, top+svgBoxHeight)
+		}
+	}
+	w.merge(d.w)
+	return w.String()
+}
+
+// sequenceSVGDrawing is a sequence diagram being drawn, from top to bottom.
+type sequenceSVGDrawing struct {
+	w           *svgWriter
+	layout      *sequenceLayout
+	numbered    func(*ast.Message) string
+	x           []float64 // Lifeline of each participant
+	left, right float64   // Outermost block frame
+	y           float64   // Top of the next statement
+}
+
+func (d *sequenceSVGDrawing) statements(statements []ast.SeqStmt, depth int) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Message:
+			d.message(s)
+		case *ast.Note:
+			d.note(s)
+		default:
+			if sections := blockSections(stmt); sections != nil {
+				d.block(sections, depth)
+			}
+		}
+	}
+}
+
+func (d *sequenceSVGDrawing) message(m *ast.Message) {
+	from, to := d.x[d.layout.index[m.From]], d.x[d.layout.index[m.To]]
+	attrs := ""
+	if strings.Contains(m.Arrow, "--") {
+		attrs += ` stroke-dasharray="4 3"`
+	}
+	switch {
+	case strings.HasSuffix(m.Arrow, ">>"):
+		attrs += ` marker-end="url(#arrow)"`
+	case strings.HasSuffix(m.Arrow, "x"):
+		attrs += ` marker-end="url(#cross)"`
+	case strings.HasSuffix(m.Arrow, ")"):
+		attrs += ` marker-end="url(#open)"`
+	}
+	if strings.HasPrefix(m.Arrow, "<<") {
+		attrs += ` marker-start="url(#arrow)"`
+	}
+
+	text := d.numbered(m)
+	line := d.y + 24
+	if from == to {
+		d.w.element(`<path d="M%s,%s h30 v16 h-30" fill="none" stroke="%s" stroke-width="1.5"%s/>`, num(from), num(line), svgLineColour, attrs)
+		d.w.text(from+8, d.y+10, text, "start")
+		d.w.extend(from+38+textPixels(text), line+16)
+		d.y += svgMessageStep + 16
+		return
+	}
+	d.w.line(point{from, line}, point{to, line}, ` stroke-width="1.5"`+attrs)
+	d.w.text((from+to)/2, d.y+10, text, "middle")
+	d.w.extend(max(from, to), line)
+	d.y += svgMessageStep
+}
+
+func (d *sequenceSVGDrawing) note(n *ast.Note) {
+	first, last := d.layout.noteSpan(n)
+	if last < 0 {
+		return
+	}
+	text := plainLabel(n.Text)
+	width := textPixels(text) + 20
+	var left float64
+	switch n.Position {
+	case "right of":
+		left = d.x[first] + 10
+	case "left of":
+		left = d.x[first] - 10 - width
+	default:
+		width = max(width, d.x[last]-d.x[first]+40)
+		left = (d.x[first]+d.x[last])/2 - width/2
+	}
+	top := d.y + 6
+	d.w.element(`<rect x="%s" y="%s" width="%s" height="28" fill="%s" stroke="%s"/>`, num(left), num(top), num(width), svgNoteColour, svgLineColour)
+	d.w.text(left+width/2, top+14, text, "middle")
+	d.w.extend(left+width, top+28)
+	d.y += svgMessageStep
+}
+
+// block draws a frame around the sections of a block, with its keyword in a
+// tab in the corner and a dashed divider between sections.
+func (d *sequenceSVGDrawing) block(sections []blockSection, depth int) {
+	left := d.left + float64(depth)*svgBlockInset
+	right := d.right - float64(depth)*svgBlockInset
+	top := d.y
+	for i, section := range sections {
+		if i > 0 {
+			d.w.line(point{left, d.y}, point{right, d.y}, ` stroke-dasharray="4 3"`)
+		}
+		tab := textPixels(section.keyword) + 16
+		d.w.element(`<rect x="%s" y="%s" width="%s" height="20" fill="%s" stroke="%s"/>`, num(left), num(d.y), num(tab), svgFillColour, svgLineColour)
+		d.w.text(left+tab/2, d.y+10, section.keyword, "middle")
+		if section.label != "" {
+			d.w.text(left+tab+8, d.y+10, "["+plainLabel(section.label)+"]", "start")
+		}
+		d.y += 24
+		d.statements(section.statements, depth+1)
+	}
+	d.y += 6
+	d.w.element(`<rect x="%s" y="%s" width="%s" height="%s" fill="none" stroke="%s"/>`, num(left), num(top), num(right-left), num(d.y-top), svgLineColour)
+	d.w.extend(right, d.y)
+	d.y += 6
+}
@@ -0,0 +1,390 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// sequenceLayout places the participants of a sequence diagram in columns.
+type sequenceLayout struct {
+	ids    []string
+	labels []string
+	index  map[string]int
+	centre []int // Lifeline column of each participant
+	depth  int   // Deepest nesting of blocks
+}
+
+// participantsOf returns the participants of a sequence diagram in the order
+// Mermaid draws them: by declaration, or first mention in a message or note.
+func participantsOf(statements []ast.SeqStmt) *sequenceLayout {
+	s := &sequenceLayout{index: make(map[string]int)}
+	add := func(id, alias string) {
+		if _, ok := s.index[id]; ok || id == "" {
+			return
+		}
+		s.index[id] = len(s.ids)
+		s.ids = append(s.ids, id)
+		label := alias
+		if label == "" {
+			label = id
+		}
+		s.labels = append(s.labels, plainLabel(label))
+	}
+	walkSequence(statements, 0, func(stmt ast.SeqStmt, depth int) {
+		s.depth = max(s.depth, depth)
+		switch st := stmt.(type) {
+		case *ast.Participant:
+			add(st.ID, st.Alias)
+		case *ast.Box:
+			for _, p := range st.Participants {
+				add(p.ID, p.Alias)
+			}
+		case *ast.Message:
+			add(st.From, "")
+			add(st.To, "")
+		case *ast.Note:
+			for _, id := range st.Participants {
+				add(id, "")
+			}
+		}
+	})
+	return s
+}
+
+// walkSequence calls visit for every statement, including those nested in
+// blocks, in source order with the number of blocks around it.
+func walkSequence(statements []ast.SeqStmt, depth int, visit func(ast.SeqStmt, int)) {
+	for _, stmt := range statements {
+		visit(stmt, depth)
+		for _, nested := range blockSections(stmt) {
+			walkSequence(nested.statements, depth+1, visit)
+		}
+	}
+}
+
+// blockSection is one part of a block such as loop or alt: the block itself,
+// or one of its else, and or option branches.
+type blockSection struct {
+	keyword    string
+	label      string
+	statements []ast.SeqStmt
+}
+
+// blockSections returns the sections of stmt, or nil when it is not a block.
+func blockSections(stmt ast.SeqStmt) []blockSection {
+	switch s := stmt.(type) {
+	case *ast.Loop:
+		return []blockSection{{"loop", s.Label, s.Statements}}
+	case *ast.Opt:
+		return []blockSection{{"opt", s.Label, s.Statements}}
+	case *ast.Break:
+		return []blockSection{{"break", s.Label, s.Statements}}
+	case *ast.Alt:
+		var sections []blockSection
+		for i, condition := range s.Conditions {
+			keyword := "else"
+			if i == 0 {
+				keyword = "alt"
+			}
+			sections = append(sections, blockSection{keyword, condition.Label, condition.Statements})
+		}
+		return sections
+	case *ast.Par:
+		var sections []blockSection
+		for i, branch := range s.Branches {
+			keyword := "and"
+			if i == 0 {
+				keyword = "par"
+			}
+			sections = append(sections, blockSection{keyword, branch.Label, branch.Statements})
+		}
+		return sections
+	case *ast.Critical:
+		sections := []blockSection{{"critical", s.Label, s.Statements}}
+		for _, option := range s.Options {
+			sections = append(sections, blockSection{"option", option.Label, option.Statements})
+		}
+		return sections
+	}
+	return nil
+}
+
+// placeColumns spaces the lifelines so that participant boxes, message texts
+// and notes fit between them.
+func (s *sequenceLayout) placeColumns(statements []ast.SeqStmt, numbered func(*ast.Message) string) {
+	n := len(s.ids)
+	gaps := make([]int, n) // Distance from each lifeline to the next
+	for i := range n - 1 {
+		gaps[i] = (textWidth(s.labels[i])+3)/2 + (textWidth(s.labels[i+1])+4)/2 + 3
+	}
+	leftMargin := (textWidth(s.labels[0]) + 4) / 2
+	// need makes lifelines from and to, from before to, at least width apart
+	need := func(from, to, width int) {
+		if to >= n || to <= from {
+			return
+		}
+		have := 0
+		for i := from; i < to; i++ {
+			have += gaps[i]
+		}
+		if have < width {
+			gaps[to-1] += width - have
+		}
+	}
+	walkSequence(statements, 0, func(stmt ast.SeqStmt, _ int) {
+		switch st := stmt.(type) {
+		case *ast.Message:
+			from, to := s.index[st.From], s.index[st.To]
+			width := textWidth(numbered(st)) + 4
+			if from == to {
+				need(from, from+1, max(width, 6))
+			} else {
+				need(min(from, to), max(from, to), width)
+			}
+		case *ast.Note:
+			first, last := s.noteSpan(st)
+			width := textWidth(plainLabel(st.Text)) + 4
+			switch st.Position {
+			case "right of":
+				need(first, first+1, width+3)
+			case "left of":
+				if first == 0 {
+					leftMargin = max(leftMargin, width+2)
+				} else {
+					need(first-1, first, width+3)
+				}
+			default:
+				need(first, last, width-2)
+				need(last, last+1, width/2+3)
+				if first == 0 {
+					leftMargin = max(leftMargin, width/2+1)
+				}
+			}
+		}
+	})
+
+	s.centre = make([]int, n)
+	s.centre[0] = s.depth*2 + leftMargin
+	for i := 1; i < n; i++ {
+		s.centre[i] = s.centre[i-1] + gaps[i-1]
+	}
+}
+
+// noteSpan returns the first and last participants a note is attached to.
+func (s *sequenceLayout) noteSpan(note *ast.Note) (int, int) {
+	first, last := len(s.ids), -1
+	for _, id := range note.Participants {
+		first, last = min(first, s.index[id]), max(last, s.index[id])
+	}
+	return first, last
+}
+
+// sequenceText draws the participants of diagram with their lifelines, and
+// its messages, notes and blocks from top to bottom.
+func sequenceText(diagram *ast.SequenceDiagram, charset Charset) string {
+	s := participantsOf(diagram.Statements)
+	if len(s.ids) == 0 {
+		return ""
+	}
+	numbers := make(map[*ast.Message]int)
+	numberMessages(diagram.Statements, numbers)
+	numbered := func(m *ast.Message) string {
+		text := plainLabel(m.Text)
+		if number, ok := numbers[m]; ok {
+			return strings.TrimSpace(fmt.Sprintf("%d. %s", number, text))
+		}
+		return text
+	}
+	s.placeColumns(diagram.Statements, numbered)
+
+	last := len(s.ids) - 1
+	lastWidth := textWidth(s.labels[last]) + 4
+	d := &sequenceDrawing{
+		canvas:   newCanvas(charset),
+		layout:   s,
+		numbered: numbered,
+		left:     0,
+		right:    s.centre[last] + lastWidth - lastWidth/2 + s.depth*2,
+		row:      3,
+	}
+	for i, label := range s.labels {
+		d.canvas.box(0, s.centre[i]-(textWidth(label)+4)/2, label)
+	}
+	d.statements(diagram.Statements, 0)
+	for _, centre := range s.centre {
+		d.canvas.vline(centre, 2, d.row)
+	}
+	return d.canvas.String()
+}
+
+// numberMessages numbers the messages in statements as autonumber
+// directives turn numbering on and off.
+func numberMessages(statements []ast.SeqStmt, numbers map[*ast.Message]int) {
+	on, next, step := false, 1, 1
+	walkSequence(statements, 0, func(stmt ast.SeqStmt, _ int) {
+		switch st := stmt.(type) {
+		case *ast.Autonumber:
+			on = st.Enabled
+			if st.Start != 0 {
+				next = st.Start
+			}
+			if st.Step != 0 {
+				step = st.Step
+			}
+		case *ast.Message:
+			if on {
+				numbers[st] = next
+				next += step
+			}
+		}
+	})
+}
+
+// sequenceDrawing is a sequence diagram being drawn, row by row.
+type sequenceDrawing struct {
+	canvas      *canvas
+	layout      *sequenceLayout
+	numbered    func(*ast.Message) string
+	left, right int // Outermost block frame columns
+	row         int // Next row to draw on
+}
+
+func (d *sequenceDrawing) statements(statements []ast.SeqStmt, depth int) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Message:
+			d.message(s)
+		case *ast.Note:
+			d.note(s)
+		default:
+			if sections := blockSections(stmt); sections != nil {
+				d.block(sections, depth)
+			}
+		}
+	}
+}
+
+func (d *sequenceDrawing) message(m *ast.Message) {
+	c, s := d.canvas, d.layout
+	from, to := s.centre[s.index[m.From]], s.centre[s.index[m.To]]
+	text := d.numbered(m)
+	dashed := strings.Contains(m.Arrow, "--")
+	head := sequenceHead(c, m.Arrow)
+
+	if text != "" {
+		c.write(d.row, min(from, to)+2, text)
+		d.row++
+	}
+	if from == to {
+		c.hline(d.row, from, from+3)
+		c.vline(from+3, d.row, d.row+1)
+		c.hline(d.row+1, from+1, from+3)
+		c.write(d.row+1, from+1, string(mirrorHead(c, head)))
+		if dashed {
+			c.dashPath(horizontal, d.row, from+1, from+3, d.row+1, from+2)
+		}
+		d.row += 2
+		return
+	}
+
+	direction := 1
+	if to < from {
+		direction = -1
+	}
+	c.hline(d.row, from, to-direction)
+	if dashed {
+		c.dashPath(horizontal, d.row, from, to-direction, d.row, to-direction)
+	}
+	if direction == -1 {
+		head = mirrorHead(c, head)
+	}
+	c.write(d.row, to-direction, string(head))
+	if strings.HasPrefix(m.Arrow, "<<") {
+		back := c.pick('◀', '<')
+		if direction == -1 {
+			back = c.pick('▶', '>')
+		}
+		c.write(d.row, from+direction, string(back))
+	}
+	d.row++
+}
+
+// sequenceHead returns the character drawn at the end of a message arrow
+// pointing right.
+func sequenceHead(c *canvas, arrow string) rune {
+	switch {
+	case strings.HasSuffix(arrow, ">>"):
+		return c.pick('▶', '>')
+	case strings.HasSuffix(arrow, "x"):
+		return 'x'
+	case strings.HasSuffix(arrow, ")"):
+		return ')'
+	case strings.HasPrefix(arrow, "--"):
+		return c.pick('╌', '.')
+	}
+	return c.pick('─', '-')
+}
+
+// mirrorHead returns head pointing left.
+func mirrorHead(c *canvas, head rune) rune {
+	switch head {
+	case '▶', '>':
+		return c.pick('◀', '<')
+	case ')':
+		return '('
+	}
+	return head
+}
+
+func (d *sequenceDrawing) note(n *ast.Note) {
+	s := d.layout
+	first, last := s.noteSpan(n)
+	if last < 0 {
+		return
+	}
+	text := plainLabel(n.Text)
+	width := textWidth(text) + 4
+	var left int
+	switch n.Position {
+	case "right of":
+		left = s.centre[first] + 2
+	case "left of":
+		left = s.centre[first] - 1 - width
+	default:
+		mid := (s.centre[first] + s.centre[last]) / 2
+		width = max(width, s.centre[last]-s.centre[first]+4)
+		left = mid - width/2
+	}
+	right := left + width - 1
+	d.canvas.rect(d.row, left, d.row+2, right)
+	d.canvas.write(d.row+1, left+1, strings.Repeat(" ", width-2))
+	d.canvas.write(d.row+1, left+(width-textWidth(text))/2, text)
+	d.row += 3
+}
+
+// block draws a frame around the sections of a block, with a divider between
+// sections, inset from the frames around it.
+func (d *sequenceDrawing) block(sections []blockSection, depth int) {
+	c := d.canvas
+	left, right := d.left+depth*2, d.right-depth*2
+	top := d.row
+	for i, section := range sections {
+		c.hline(d.row, left, right)
+		if i > 0 {
+			c.dashPath(horizontal, d.row, left+1, left+1, d.row, right-1)
+		}
+		title := section.keyword
+		if section.label != "" {
+			title += " [" + plainLabel(section.label) + "]"
+		}
+		c.write(d.row, left+2, " "+title+" ")
+		d.row++
+		d.statements(section.statements, depth+1)
+	}
+	c.hline(d.row, left, right)
+	c.vline(left, top, d.row)
+	c.vline(right, top, d.row)
+	d.row++
+}
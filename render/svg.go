@@ -0,0 +1,297 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// SVG draws a flowchart or sequence diagram as a standalone SVG document,
+// without Mermaid or a browser. Fidelity is lower than Mermaid's own
+// renderer: flowcharts use the same layered layout as Text, with node shapes
+// but without subgraphs, styles or links from a node to itself, and text is
+// measured approximately. Other diagram types return an error.
+func SVG(diagram ast.Diagram) (string, error) {
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		return flowchartSVG(layoutFlowchart(d)), nil
+	case *ast.SequenceDiagram:
+		return sequenceSVG(d), nil
+	}
+	return "", fmt.Errorf("cannot render %s diagrams as SVG: only flowcharts and sequence diagrams are supported", diagram.GetType())
+}
+
+// Measurements of SVG drawings, in pixels.
+const (
+	svgMargin     = 20.0
+	svgCharWidth  = 8.0 // Approximate width of a character at svgFontSize
+	svgFontSize   = 14
+	svgLineColour = "#333"
+	svgFillColour = "#ECECFF"
+	svgEdgeColour = "#9370DB"
+	svgNoteColour = "#FFF5AD"
+)
+
+// svgWriter collects SVG elements and the extent of the drawing.
+type svgWriter struct {
+	body          strings.Builder
+	width, height float64
+}
+
+// extend grows the drawing to include x, y.
+func (w *svgWriter) extend(x, y float64) {
+	w.width = max(w.width, x)
+	w.height = max(w.height, y)
+}
+
+// merge adds the elements of other over those of w.
+func (w *svgWriter) merge(other *svgWriter) {
+	w.body.WriteString(other.body.String())
+	w.extend(other.width, other.height)
+}
+
+func (w *svgWriter) element(format string, args ...any) {
+	fmt.Fprintf(&w.body, "  "+format+"\n", args...)
+}
+
+// line draws a line from a to b, with extra attributes such as markers.
+func (w *svgWriter) line(a, b point, attrs string) {
+	w.element(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"%s/>`, num(a.x), num(a.y), num(b.x), num(b.y), svgLineColour, attrs)
+}
+
+// text draws s at x, y, aligned by anchor (start, middle or end) and centred
+// vertically on y.
+func (w *svgWriter) text(x, y float64, s, anchor string) {
+	w.element(`<text x="%s" y="%s" text-anchor="%s" dominant-baseline="middle">%s</text>`, num(x), num(y), anchor, html.EscapeString(s))
+}
+
+// String returns the SVG document, with a margin around the drawing.
+func (w *svgWriter) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s" font-family="sans-serif" font-size="%d">`+"\n",
+		num(w.width+svgMargin), num(w.height+svgMargin), svgFontSize)
+	b.WriteString(`  <defs>
+    <marker id="arrow" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="` + svgLineColour + `"/></marker>
+    <marker id="cross" viewBox="0 0 10 10" refX="5" refY="5" markerWidth="10" markerHeight="10" orient="auto"><path d="M1,1 L9,9 M9,1 L1,9" stroke="` + svgLineColour + `" stroke-width="1.5"/></marker>
+    <marker id="open" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10" fill="none" stroke="` + svgLineColour + `" stroke-width="1.5"/></marker>
+    <marker id="circle" viewBox="0 0 10 10" refX="5" refY="5" markerWidth="8" markerHeight="8" orient="auto"><circle cx="5" cy="5" r="4" fill="` + svgLineColour + `"/></marker>
+  </defs>
+  <rect width="100%" height="100%" fill="white"/>
+`)
+	b.WriteString(w.body.String())
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// num formats a coordinate without needless decimals.
+func num(f float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", f), "0"), ".")
+}
+
+// textPixels returns the approximate width of s in pixels.
+func textPixels(s string) float64 {
+	return float64(textWidth(s)) * svgCharWidth
+}
+
+// point is a position in a drawing.
+type point struct{ x, y float64 }
+
+// flowchartPlacement is where the vertices of a layout are drawn.
+type flowchartPlacement struct {
+	l      *layout
+	centre []point
+	size   []point // Width and height of each vertex
+}
+
+// vertexSize returns the width and height of a vertex's shape.
+func vertexSize(v vertex) point {
+	if v.dummy {
+		return point{}
+	}
+	width := max(textPixels(v.label)+32, 48)
+	switch v.shape {
+	case "{}":
+		return point{width + 32, 56}
+	case "(())":
+		d := max(width, 48)
+		return point{d, d}
+	case "{{}}":
+		return point{width + 24, 40}
+	}
+	return point{width, 40}
+}
+
+// placeVertices lays layers out one after the other, with each layer's
+// vertices centred across the drawing, leaving room between layers for link
+// labels.
+func placeVertices(l *layout) *flowchartPlacement {
+	p := &flowchartPlacement{l: l, centre: make([]point, len(l.vertices)), size: make([]point, len(l.vertices))}
+	for i, v := range l.vertices {
+		p.size[i] = vertexSize(v)
+	}
+	// along and across measure a vertex along the direction layers run in and
+	// across it
+	along := func(v int) float64 {
+		if l.vertical {
+			return p.size[v].y
+		}
+		return p.size[v].x
+	}
+	across := func(v int) float64 {
+		if l.vertical {
+			return p.size[v].x
+		}
+		return p.size[v].y
+	}
+
+	gap := 60.0
+	if !l.vertical {
+		for _, e := range l.edges {
+			gap = max(gap, textPixels(e.label)+40)
+		}
+	}
+	spans := make([]float64, len(l.layers))
+	for i, layer := range l.layers {
+		for j, v := range layer {
+			if j > 0 {
+				spans[i] += 30
+			}
+			spans[i] += across(v)
+		}
+	}
+	widest := slices.Max(spans)
+
+	start := svgMargin
+	for i, layer := range l.layers {
+		depth := 0.0
+		for _, v := range layer {
+			depth = max(depth, along(v))
+		}
+		offset := svgMargin + (widest-spans[i])/2
+		for _, v := range layer {
+			a, c := start+depth/2, offset+across(v)/2
+			if l.vertical {
+				p.centre[v] = point{c, a}
+			} else {
+				p.centre[v] = point{a, c}
+			}
+			offset += across(v) + 30
+		}
+		start += depth + gap
+	}
+	return p
+}
+
+// port returns where links leave (out) or enter a vertex, on the side facing
+// the next or previous layer.
+func (p *flowchartPlacement) port(v int, out bool) point {
+	c, size := p.centre[v], p.size[v]
+	sign := -1.0
+	if out {
+		sign = 1
+	}
+	if p.l.vertical {
+		return point{c.x, c.y + sign*size.y/2}
+	}
+	return point{c.x + sign*size.x/2, c.y}
+}
+
+func flowchartSVG(l *layout) string {
+	w := &svgWriter{}
+	if len(l.layers) == 0 {
+		return w.String()
+	}
+	p := placeVertices(l)
+	for i := range l.edges {
+		p.drawEdge(w, &l.edges[i])
+	}
+	for v, vertex := range l.vertices {
+		if !vertex.dummy {
+			drawShape(w, vertex, p.centre[v], p.size[v])
+		}
+	}
+	return w.String()
+}
+
+// drawEdge draws e as a line through the dummy vertices on its path, with its
+// label halfway along the last part.
+func (p *flowchartPlacement) drawEdge(w *svgWriter, e *edge) {
+	points := []point{p.port(e.path[0], true)}
+	for _, v := range e.path[1 : len(e.path)-1] {
+		points = append(points, p.centre[v])
+	}
+	points = append(points, p.port(e.path[len(e.path)-1], false))
+
+	coords := make([]string, len(points))
+	for i, pt := range points {
+		coords[i] = num(pt.x) + "," + num(pt.y)
+		w.extend(pt.x, pt.y)
+	}
+	attrs := ""
+	if e.headStart {
+		attrs += ` marker-start="url(#arrow)"`
+	}
+	if e.headEnd {
+		attrs += ` marker-end="url(#arrow)"`
+	}
+	if e.dashed {
+		attrs += ` stroke-dasharray="4 3"`
+	}
+	w.element(`<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"%s/>`, strings.Join(coords, " "), svgLineColour, attrs)
+
+	if e.label != "" {
+		a, b := points[len(points)-2], points[len(points)-1]
+		mid := point{(a.x + b.x) / 2, (a.y + b.y) / 2}
+		width := textPixels(e.label) + 8
+		w.element(`<rect x="%s" y="%s" width="%s" height="20" fill="#E8E8E8"/>`, num(mid.x-width/2), num(mid.y-10), num(width))
+		w.text(mid.x, mid.y, e.label, "middle")
+	}
+}
+
+// drawShape draws a node in the shape its brackets stand for, with its label.
+func drawShape(w *svgWriter, v vertex, c, size point) {
+	left, top := c.x-size.x/2, c.y-size.y/2
+	right, bottom := c.x+size.x/2, c.y+size.y/2
+	w.extend(right, bottom)
+	style := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="1.5"`, svgFillColour, svgEdgeColour)
+	polygon := func(points ...point) {
+		coords := make([]string, len(points))
+		for i, pt := range points {
+			coords[i] = num(pt.x) + "," + num(pt.y)
+		}
+		w.element(`<polygon points="%s" %s/>`, strings.Join(coords, " "), style)
+	}
+
+	switch v.shape {
+	case "{}":
+		polygon(point{c.x, top}, point{right, c.y}, point{c.x, bottom}, point{left, c.y})
+	case "{{}}":
+		polygon(point{left + 12, top}, point{right - 12, top}, point{right, c.y},
+			point{right - 12, bottom}, point{left + 12, bottom}, point{left, c.y})
+	case ">]":
+		polygon(point{left, top}, point{right, top}, point{right, bottom}, point{left, bottom}, point{left + 12, c.y})
+	case "(())":
+		w.element(`<circle cx="%s" cy="%s" r="%s" %s/>`, num(c.x), num(c.y), num(size.x/2), style)
+	default:
+		radius := 0.0
+		switch v.shape {
+		case "()":
+			radius = 8
+		case "([])":
+			radius = size.y / 2
+		case "[()]":
+			radius = math.Min(12, size.x/2)
+		}
+		w.element(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s" %s/>`,
+			num(left), num(top), num(size.x), num(size.y), num(radius), style)
+		if v.shape == "[[]]" {
+			w.element(`<path d="M%s,%s V%s M%s,%s V%s" stroke="%s" stroke-width="1.5"/>`,
+				num(left+8), num(top), num(bottom), num(right-8), num(top), num(bottom), svgEdgeColour)
+		}
+	}
+	w.text(c.x, c.y, v.label, "middle")
+}
@@ -0,0 +1,103 @@
+package render_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/render"
+)
+
+// svgDocument is the part of an SVG document the tests look at.
+type svgDocument struct {
+	Width    string   `xml:"width,attr"`
+	Height   string   `xml:"height,attr"`
+	Texts    []string `xml:"text"`
+	Polygons []struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polygon"`
+	Polylines []struct {
+		MarkerStart string `xml:"marker-start,attr"`
+		MarkerEnd   string `xml:"marker-end,attr"`
+		Dashes      string `xml:"stroke-dasharray,attr"`
+	} `xml:"polyline"`
+	Lines []struct {
+		MarkerEnd string `xml:"marker-end,attr"`
+	} `xml:"line"`
+}
+
+func parseSVG(t *testing.T, source string) svgDocument {
+	t.Helper()
+	out, err := render.SVG(parse(t, source))
+	if err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+	var doc svgDocument
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("SVG() is not valid XML: %v\n%s", err, out)
+	}
+	if doc.Width == "" || doc.Width == "20" || doc.Height == "" || doc.Height == "20" {
+		t.Errorf("SVG() size = %s x %s, want room for the drawing", doc.Width, doc.Height)
+	}
+	return doc
+}
+
+func TestSVG_Flowchart(t *testing.T) {
+	doc := parseSVG(t, `flowchart LR
+    A[Start & go] --> B{Ready?}
+    B -->|yes| C([Ship])
+    B -.-> D
+    D --> A`)
+
+	if got, want := strings.Join(doc.Texts, ","), "yes,Start & go,Ready?,Ship,D"; got != want {
+		t.Errorf("texts = %q, want %q", got, want)
+	}
+	if len(doc.Polygons) != 1 {
+		t.Errorf("polygons = %d, want 1 for the decision", len(doc.Polygons))
+	}
+	if len(doc.Polylines) != 4 {
+		t.Fatalf("polylines = %d, want 4", len(doc.Polylines))
+	}
+	var dashed, reversed int
+	for _, line := range doc.Polylines {
+		if line.Dashes != "" {
+			dashed++
+		}
+		if line.MarkerStart != "" && line.MarkerEnd == "" {
+			reversed++
+		}
+	}
+	if dashed != 1 || reversed != 1 {
+		t.Errorf("dashed links = %d, reversed links = %d, want 1 of each", dashed, reversed)
+	}
+}
+
+func TestSVG_Sequence(t *testing.T) {
+	doc := parseSVG(t, `sequenceDiagram
+    participant C as Client
+    C->>API: GET /items
+    loop retry
+        API-xDB: query
+    end
+    API-->>C: 200`)
+
+	want := []string{"Client", "Client", "API", "API", "DB", "DB", "GET /items", "loop", "[retry]", "query", "200"}
+	if got := strings.Join(doc.Texts, ","); got != strings.Join(want, ",") {
+		t.Errorf("texts = %q, want %q", got, want)
+	}
+	var markers []string
+	for _, line := range doc.Lines {
+		if line.MarkerEnd != "" {
+			markers = append(markers, line.MarkerEnd)
+		}
+	}
+	if got, want := strings.Join(markers, ","), "url(#arrow),url(#cross),url(#arrow)"; got != want {
+		t.Errorf("message markers = %q, want %q", got, want)
+	}
+}
+
+func TestSVG_UnsupportedType(t *testing.T) {
+	if _, err := render.SVG(parse(t, "pie\n    \"A\" : 1")); err == nil {
+		t.Error("SVG() error = nil, want error for a pie chart")
+	}
+}
@@ -0,0 +1,124 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/render"
+)
+
+func parse(t *testing.T, source string) ast.Diagram {
+	t.Helper()
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return diagram
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		charset render.Charset
+		want    string
+	}{
+		{
+			name: "flowchart top down",
+			source: `flowchart TD
+    A[Start] --> B{Ready?}
+    B -->|yes| C[Ship]
+    B -.-> D[Wait]`,
+			charset: render.Unicode,
+			want: `
+    ┌───────┐
+    │ Start │
+    └───┬───┘
+        │
+        └┐
+         ▼
+    ┌────────┐
+    │ Ready? │
+    └────┬───┘
+         ╎
+    ┌────┴╌╌╌╌┐
+    ▼ yes     ▼
+┌──────┐  ┌──────┐
+│ Ship │  │ Wait │
+└──────┘  └──────┘
+`,
+		},
+		{
+			name: "flowchart left to right with a cycle and a self link",
+			source: `graph LR
+    A --> B
+    B --> A
+    B --> B`,
+			charset: render.ASCII,
+			want: `
++---+   +---+
+| A +<->+ B |
++---+   +---+
+
+B > B
+`,
+		},
+		{
+			name: "sequence diagram",
+			source: `sequenceDiagram
+    autonumber
+    participant C as Client
+    C->>API: GET /items
+    loop retry
+        API-)DB: query
+    end
+    API-->>C: 200
+    Note over C: done`,
+			charset: render.ASCII,
+			want: `
+  +--------+         +-----+     +----+
+  | Client |         | API |     | DB |
+  +----+---+         +--+--+     +--+-+
+       | 1. GET /items  |           |
+       +--------------->|           |
++- loop [retry] --------+-----------+----+
+|      |                | 2. query  |    |
+|      |                +----------)|    |
++------+----------------+-----------+----+
+       | 3. 200         |           |
+       |<...............+           |
+   +---+--+             |           |
+   | done |             |           |
+   +---+--+             |           |
+       |                |           |
+`,
+		},
+		{
+			name:    "empty flowchart",
+			source:  "flowchart TD",
+			charset: render.Unicode,
+			want:    "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := render.Text(parse(t, tt.source), tt.charset)
+			if err != nil {
+				t.Fatalf("Text() error = %v", err)
+			}
+			if want := strings.TrimPrefix(tt.want, "\n"); got != want {
+				t.Errorf("Text() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestText_UnsupportedType(t *testing.T) {
+	diagram := parse(t, "pie\n    \"A\" : 1")
+	if _, err := render.Text(diagram, render.Unicode); err == nil {
+		t.Error("Text() error = nil, want error for a pie chart")
+	}
+}
@@ -0,0 +1,27 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// Text draws a flowchart or sequence diagram as box-and-arrow text art, for a
+// quick look at a diagram in a terminal. It is meant for small diagrams: the
+// drawing grows with the diagram and makes no attempt to fit a screen.
+//
+// Flowchart nodes are drawn as boxes in layers running in the diagram's
+// direction, with links routed between them; subgraphs and node shapes are not
+// drawn, and links from a node to itself, or whose label there was no room
+// for, are listed under the drawing. Sequence diagrams are drawn with a
+// lifeline for each participant and their messages, notes and blocks from top
+// to bottom. Other diagram types return an error.
+func Text(diagram ast.Diagram, charset Charset) (string, error) {
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		return flowchartText(layoutFlowchart(d), charset), nil
+	case *ast.SequenceDiagram:
+		return sequenceText(d, charset), nil
+	}
+	return "", fmt.Errorf("cannot render %s diagrams as text: only flowcharts and sequence diagrams are supported", diagram.GetType())
+}
@@ -0,0 +1,294 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// sequenceConsistencyRule names the errors reported by CheckSequenceConsistency.
+const sequenceConsistencyRule = "sequence-consistency"
+
+// architectureEntity is a participant of a sequence diagram, or a node or
+// element of a flowchart or C4 diagram, that CheckSequenceConsistency matches.
+type architectureEntity struct {
+	name  string   // Name as written, for messages
+	names []string // Normalised ID and display name
+	line  int      // Line in the file where the entity first appears
+}
+
+// CheckSequenceConsistency checks that the sequence diagrams of a file agree
+// with the architecture they describe, for documents that pair a flowchart or
+// C4 diagram of the components with sequence diagrams of their interactions.
+// Each sequence diagram is compared with the nearest flowchart or C4 diagram
+// in the same file, the preceding one when two are equally near. Participants
+// and nodes match when their IDs or display names are the same once case,
+// spaces and punctuation are ignored, so `participant API as Orders API`
+// matches `OrdersAPI[Orders API]`. A participant missing from the architecture
+// diagram is reported in the sequence diagram, and a node or element that none
+// of the sequence diagrams compared with it mentions is reported in the
+// architecture diagram. Results from different files are checked separately.
+func CheckSequenceConsistency(results []Result) []ReferenceError {
+	var errors []ReferenceError
+	byFile := make(map[string][]*Result)
+	var files []string
+	for i := range results {
+		r := &results[i]
+		if r.Diagram == nil {
+			continue
+		}
+		if _, seen := byFile[r.File]; !seen {
+			files = append(files, r.File)
+		}
+		byFile[r.File] = append(byFile[r.File], r)
+	}
+
+	for _, file := range files {
+		errors = append(errors, checkSequenceDiagrams(byFile[file])...)
+	}
+	return errors
+}
+
+// checkSequenceDiagrams checks the sequence diagrams of one file, in block
+// order, against the architecture diagrams of the file.
+func checkSequenceDiagrams(diagrams []*Result) []ReferenceError {
+	var errors []ReferenceError
+	var architectures []*Result
+	for _, r := range diagrams {
+		if len(architectureEntities(r)) > 0 {
+			architectures = append(architectures, r)
+		}
+	}
+	if len(architectures) == 0 {
+		return nil
+	}
+
+	sequences := make(map[*Result][]*Result) // Sequence diagrams compared with each architecture diagram
+	for _, r := range diagrams {
+		if _, ok := r.Diagram.(*ast.SequenceDiagram); !ok {
+			continue
+		}
+		architecture := nearestArchitecture(architectures, r)
+		sequences[architecture] = append(sequences[architecture], r)
+
+		known := architectureEntities(architecture)
+		for _, participant := range sequenceParticipants(r) {
+			if matchEntity(known, participant) {
+				continue
+			}
+			errors = append(errors, ReferenceError{
+				File:       r.File,
+				BlockIndex: r.BlockIndex,
+				Line:       participant.line,
+				Message: fmt.Sprintf("participant '%s' does not appear in the %s in diagram %d at line %d",
+					participant.name, architectureKind(architecture), architecture.BlockIndex+1, architecture.LineOffset),
+				Rule: sequenceConsistencyRule,
+			})
+		}
+	}
+
+	for _, architecture := range architectures {
+		compared := sequences[architecture]
+		if len(compared) == 0 {
+			continue
+		}
+		var participants []architectureEntity
+		var described []string
+		for _, r := range compared {
+			participants = append(participants, sequenceParticipants(r)...)
+			described = append(described, fmt.Sprint(r.BlockIndex+1))
+		}
+		for _, entity := range architectureEntities(architecture) {
+			if matchEntity(participants, entity) {
+				continue
+			}
+			errors = append(errors, ReferenceError{
+				File:       architecture.File,
+				BlockIndex: architecture.BlockIndex,
+				Line:       entity.line,
+				Message: fmt.Sprintf("%s '%s' does not appear in sequence diagram %s",
+					entityKind(architecture), entity.name, strings.Join(described, ", ")),
+				Rule: sequenceConsistencyRule,
+			})
+		}
+	}
+	return errors
+}
+
+// nearestArchitecture returns the architecture diagram closest to r in the
+// file, preferring the earlier of two equally near.
+func nearestArchitecture(architectures []*Result, r *Result) *Result {
+	nearest := architectures[0]
+	for _, candidate := range architectures[1:] {
+		if distance(candidate.BlockIndex, r.BlockIndex) < distance(nearest.BlockIndex, r.BlockIndex) {
+			nearest = candidate
+		}
+	}
+	return nearest
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// matchEntity reports whether any of entities shares a normalised name with
+// entity.
+func matchEntity(entities []architectureEntity, entity architectureEntity) bool {
+	for _, candidate := range entities {
+		for _, name := range candidate.names {
+			for _, other := range entity.names {
+				if name == other {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// architectureEntities returns the nodes of a flowchart or the elements of a
+// C4 diagram, in the order they first appear, or nil for other diagram types.
+func architectureEntities(r *Result) []architectureEntity {
+	collector := newEntityCollector(r)
+	switch d := r.Diagram.(type) {
+	case *ast.Flowchart:
+		collector.addFlowchart(d.Statements)
+	case *ast.C4Diagram:
+		collector.addC4(d.Elements, d.Boundaries)
+	}
+	return collector.entities
+}
+
+// sequenceParticipants returns the participants of a sequence diagram, whether
+// declared or introduced by a message, in the order they first appear.
+func sequenceParticipants(r *Result) []architectureEntity {
+	collector := newEntityCollector(r)
+	collector.addSequence(r.Diagram.(*ast.SequenceDiagram).Statements)
+	return collector.entities
+}
+
+// entityCollector gathers the entities of a diagram, merging repeated
+// mentions of an ID.
+type entityCollector struct {
+	result   *Result
+	entities []architectureEntity
+	index    map[string]int // Entity index by ID
+}
+
+func newEntityCollector(r *Result) *entityCollector {
+	return &entityCollector{result: r, index: make(map[string]int)}
+}
+
+// add records an entity with the given ID and display name (which may be
+// empty) at line of the diagram.
+func (c *entityCollector) add(id, label string, line int) {
+	if id == "" {
+		return
+	}
+	i, exists := c.index[id]
+	if !exists {
+		i = len(c.entities)
+		c.index[id] = i
+		c.entities = append(c.entities, architectureEntity{
+			name:  id,
+			names: []string{normaliseName(id)},
+			line:  c.result.LineOffset + line - 1,
+		})
+	}
+	if label = strings.TrimSpace(label); label != "" && label != id {
+		entity := &c.entities[i]
+		entity.name = label
+		entity.names = append(entity.names, normaliseName(label))
+	}
+}
+
+func (c *entityCollector) addFlowchart(statements []ast.Statement) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			c.add(s.ID, s.Label, s.Pos.Line)
+		case *ast.Link:
+			c.add(s.From, "", s.Pos.Line)
+			c.add(s.To, "", s.Pos.Line)
+		case *ast.Subgraph:
+			c.addFlowchart(s.Statements)
+		}
+	}
+}
+
+func (c *entityCollector) addC4(elements []ast.C4Element, boundaries []ast.C4Boundary) {
+	for _, element := range elements {
+		c.add(element.ID, element.Label, element.Pos.Line)
+	}
+	for _, boundary := range boundaries {
+		c.addC4(boundary.Elements, boundary.Boundaries)
+	}
+}
+
+func (c *entityCollector) addSequence(statements []ast.SeqStmt) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			c.add(s.ID, s.Alias, s.Pos.Line)
+		case *ast.Box:
+			for _, p := range s.Participants {
+				c.add(p.ID, p.Alias, p.Pos.Line)
+			}
+		case *ast.Message:
+			c.add(s.From, "", s.Pos.Line)
+			c.add(s.To, "", s.Pos.Line)
+		case *ast.Loop:
+			c.addSequence(s.Statements)
+		case *ast.Opt:
+			c.addSequence(s.Statements)
+		case *ast.Break:
+			c.addSequence(s.Statements)
+		case *ast.Alt:
+			for _, cond := range s.Conditions {
+				c.addSequence(cond.Statements)
+			}
+		case *ast.Par:
+			for _, branch := range s.Branches {
+				c.addSequence(branch.Statements)
+			}
+		case *ast.Critical:
+			c.addSequence(s.Statements)
+			for _, option := range s.Options {
+				c.addSequence(option.Statements)
+			}
+		}
+	}
+}
+
+// normaliseName reduces a name to its lower-case letters and digits, so that
+// "Orders API", "orders-api" and "OrdersAPI" compare equal.
+func normaliseName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// architectureKind describes an architecture diagram in messages.
+func architectureKind(r *Result) string {
+	if _, ok := r.Diagram.(*ast.C4Diagram); ok {
+		return "C4 diagram"
+	}
+	return "flowchart"
+}
+
+// entityKind describes the entities of an architecture diagram in messages.
+func entityKind(r *Result) string {
+	if _, ok := r.Diagram.(*ast.C4Diagram); ok {
+		return "C4 element"
+	}
+	return "flowchart node"
+}
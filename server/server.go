@@ -0,0 +1,206 @@
+// Package server implements the mermaidcheck.v1.ValidationService gRPC service
+// defined in proto/mermaidcheck/v1/mermaidcheck.proto.
+package server
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+	"github.com/sammcj/mermaid-check/parser"
+	mermaidcheckv1 "github.com/sammcj/mermaid-check/proto/mermaidcheck/v1"
+	"github.com/sammcj/mermaid-check/validator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements mermaidcheckv1.ValidationServiceServer.
+type Server struct {
+	mermaidcheckv1.UnimplementedValidationServiceServer
+
+	// root is the directory StreamValidateDirectory may read from.
+	root string
+	// limits bounds the size and complexity of each diagram, as requests
+	// come from untrusted clients.
+	limits parser.Limits
+}
+
+// New creates a Server. StreamValidateDirectory only validates directories
+// inside root; an empty root disables it. Diagrams are parsed within
+// parser.DefaultLimits.
+func New(root string) *Server {
+	return &Server{root: root, limits: parser.DefaultLimits()}
+}
+
+// ValidateDiagram validates a single raw Mermaid diagram.
+func (s *Server) ValidateDiagram(ctx context.Context, req *mermaidcheckv1.ValidateDiagramRequest) (*mermaidcheckv1.ValidateDiagramResponse, error) {
+	results, err := mermaid.ValidateSourceContext(ctx, "diagram.mmd", req.GetSource(), s.validateOptions(req.GetOptions()))
+	if err != nil {
+		return nil, requestError(ctx, err)
+	}
+	if len(results) != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "expected one diagram, found %d", len(results))
+	}
+	return &mermaidcheckv1.ValidateDiagramResponse{
+		Result: diagramResult(results[0], req.GetOptions().GetDisable()),
+	}, nil
+}
+
+// ValidateMarkdown validates every Mermaid block in a markdown document.
+func (s *Server) ValidateMarkdown(ctx context.Context, req *mermaidcheckv1.ValidateMarkdownRequest) (*mermaidcheckv1.ValidateMarkdownResponse, error) {
+	results, err := mermaid.ValidateSourceContext(ctx, "document.md", req.GetMarkdown(), s.validateOptions(req.GetOptions()))
+	if err != nil {
+		return nil, requestError(ctx, err)
+	}
+	return &mermaidcheckv1.ValidateMarkdownResponse{
+		Results: diagramResults(results, req.GetOptions().GetDisable()),
+	}, nil
+}
+
+// StreamValidateDirectory validates the Mermaid and markdown files under a
+// directory inside the server's root, sending a result for each file.
+// Hidden directories are skipped, as in mermaid.ValidatePath, and so are
+// symlinks, which could lead outside the root.
+func (s *Server) StreamValidateDirectory(req *mermaidcheckv1.StreamValidateDirectoryRequest, stream mermaidcheckv1.ValidationService_StreamValidateDirectoryServer) error {
+	if s.root == "" {
+		return status.Error(codes.FailedPrecondition, "directory validation is not enabled on this server")
+	}
+
+	root, err := filepath.EvalSymlinks(s.root)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	// Cleaning the path as if it were absolute stops it escaping the root,
+	// and resolving it stops a symlink inside the root leading out of it
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Clean(string(filepath.Separator)+req.GetPath())))
+	if errors.Is(err, os.ErrNotExist) {
+		return status.Errorf(codes.NotFound, "directory %q not found", req.GetPath())
+	} else if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if dir != root && !strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		return status.Errorf(codes.PermissionDenied, "%q is outside the server's root", req.GetPath())
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !info.IsDir() {
+		return status.Errorf(codes.InvalidArgument, "%q is not a directory", req.GetPath())
+	}
+
+	ctx := stream.Context()
+	opts := s.validateOptions(req.GetOptions())
+	disable := req.GetOptions().GetDisable()
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if inpututil.DetectFileType(path) == inpututil.FileTypeUnknown {
+			return nil
+		}
+
+		data, err := os.ReadFile(path) //nolint:gosec // Path is inside the server's root
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		results, err := mermaid.ValidateSourceContext(ctx, path, string(data), opts)
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		} else if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return stream.Send(&mermaidcheckv1.FileResult{
+			Path:    filepath.ToSlash(rel),
+			Results: diagramResults(results, disable),
+		})
+	})
+}
+
+// validateOptions converts request options to mermaid.ValidateOptions, with
+// the server's limits.
+func (s *Server) validateOptions(options *mermaidcheckv1.Options) mermaid.ValidateOptions {
+	return mermaid.ValidateOptions{
+		Strict:              options.GetStrict(),
+		RequiredAnnotations: options.GetRequiredAnnotations(),
+		Limits:              &s.limits,
+	}
+}
+
+// requestError converts an error validating a request to a status, reporting
+// cancelled and expired requests as such.
+func requestError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// diagramResults converts the results for a document, dropping errors from
+// the disabled rules.
+func diagramResults(results []mermaid.Result, disable []string) []*mermaidcheckv1.DiagramResult {
+	converted := make([]*mermaidcheckv1.DiagramResult, 0, len(results))
+	for _, result := range results {
+		converted = append(converted, diagramResult(result, disable))
+	}
+	return converted
+}
+
+// diagramResult converts the result for one diagram, dropping errors from the
+// disabled rules.
+func diagramResult(result mermaid.Result, disable []string) *mermaidcheckv1.DiagramResult {
+	converted := &mermaidcheckv1.DiagramResult{
+		BlockIndex:  int32(result.BlockIndex), //nolint:gosec // Diagram counts and line numbers fit in int32
+		DiagramType: result.DiagramType,
+		LineOffset:  int32(result.LineOffset), //nolint:gosec // Diagram counts and line numbers fit in int32
+		EndLine:     int32(result.EndLine),    //nolint:gosec // Diagram counts and line numbers fit in int32
+	}
+	if result.ParseError != nil {
+		converted.ParseError = result.ParseError.Error()
+	}
+	for _, err := range validator.WithoutRules(result.Errors, disable...) {
+		converted.Errors = append(converted.Errors, &mermaidcheckv1.ValidationError{
+			Line:     int32(err.Line),   //nolint:gosec // Diagram counts and line numbers fit in int32
+			Column:   int32(err.Column), //nolint:gosec // Diagram counts and line numbers fit in int32
+			Message:  err.Message,
+			Severity: severity(err.Severity),
+			Rule:     err.Rule,
+		})
+	}
+	return converted
+}
+
+// severity converts a validator.Severity to its protobuf equivalent.
+func severity(s validator.Severity) mermaidcheckv1.Severity {
+	switch s {
+	case validator.SeverityError:
+		return mermaidcheckv1.Severity_SEVERITY_ERROR
+	case validator.SeverityWarning:
+		return mermaidcheckv1.Severity_SEVERITY_WARNING
+	case validator.SeverityInfo:
+		return mermaidcheckv1.Severity_SEVERITY_INFO
+	default:
+		return mermaidcheckv1.Severity_SEVERITY_UNSPECIFIED
+	}
+}
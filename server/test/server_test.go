@@ -0,0 +1,245 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mermaidcheckv1 "github.com/sammcj/mermaid-check/proto/mermaidcheck/v1"
+	"github.com/sammcj/mermaid-check/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient starts a server with the given root on an in-memory listener and
+// returns a client connected to it.
+func newClient(t *testing.T, root string) mermaidcheckv1.ValidationServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	mermaidcheckv1.RegisterValidationServiceServer(grpcServer, server.New(root))
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return mermaidcheckv1.NewValidationServiceClient(conn)
+}
+
+func TestValidateDiagram(t *testing.T) {
+	client := newClient(t, "")
+
+	resp, err := client.ValidateDiagram(t.Context(), &mermaidcheckv1.ValidateDiagramRequest{
+		Source: "classDiagram\n    class Animal\n    class Animal\n",
+	})
+	if err != nil {
+		t.Fatalf("ValidateDiagram() error = %v", err)
+	}
+	result := resp.GetResult()
+	if result.GetDiagramType() != "class" || len(result.GetErrors()) != 1 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if e := result.GetErrors()[0]; e.GetLine() != 3 || e.GetSeverity() != mermaidcheckv1.Severity_SEVERITY_ERROR || e.GetRule() != "no-duplicate-classes" {
+		t.Errorf("unexpected error: %v", e)
+	}
+
+	resp, err = client.ValidateDiagram(t.Context(), &mermaidcheckv1.ValidateDiagramRequest{
+		Source:  "classDiagram\n    class Animal\n    class Animal\n",
+		Options: &mermaidcheckv1.Options{Disable: []string{"no-duplicate-classes"}},
+	})
+	if err != nil {
+		t.Fatalf("ValidateDiagram() error = %v", err)
+	}
+	if len(resp.GetResult().GetErrors()) != 0 {
+		t.Errorf("expected disabled rule to be skipped, got %v", resp.GetResult().GetErrors())
+	}
+
+	resp, err = client.ValidateDiagram(t.Context(), &mermaidcheckv1.ValidateDiagramRequest{Source: "notADiagram\n"})
+	if err != nil {
+		t.Fatalf("ValidateDiagram() error = %v", err)
+	}
+	if resp.GetResult().GetParseError() == "" {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestValidateDiagram_Limits(t *testing.T) {
+	client := newClient(t, "")
+
+	source := "flowchart TD\n" + strings.Repeat("    subgraph S\n", 60) + strings.Repeat("    end\n", 60)
+	resp, err := client.ValidateDiagram(t.Context(), &mermaidcheckv1.ValidateDiagramRequest{Source: source})
+	if err != nil {
+		t.Fatalf("ValidateDiagram() error = %v", err)
+	}
+	if !strings.Contains(resp.GetResult().GetParseError(), "limit exceeded") {
+		t.Errorf("expected a limit exceeded parse error, got %q", resp.GetResult().GetParseError())
+	}
+}
+
+func TestValidateMarkdown(t *testing.T) {
+	client := newClient(t, "")
+
+	resp, err := client.ValidateMarkdown(t.Context(), &mermaidcheckv1.ValidateMarkdownRequest{
+		Markdown: "# Doc\n\n```mermaid\nflowchart TD\n    A --> B\n```\n\n```mermaid\npie\n    \"A\" : 1\n```\n",
+		Options:  &mermaidcheckv1.Options{RequiredAnnotations: []string{"owner"}},
+	})
+	if err != nil {
+		t.Fatalf("ValidateMarkdown() error = %v", err)
+	}
+	if len(resp.GetResults()) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp.GetResults()))
+	}
+	for _, result := range resp.GetResults() {
+		if len(result.GetErrors()) != 1 {
+			t.Errorf("expected a missing annotation error, got %v", result.GetErrors())
+		}
+	}
+	if resp.GetResults()[1].GetLineOffset() != 9 {
+		t.Errorf("line offset = %d, want 9", resp.GetResults()[1].GetLineOffset())
+	}
+}
+
+func TestStreamValidateDirectory(t *testing.T) {
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		"docs/a.md":         "```mermaid\nflowchart TD\n    A --> B\n```\n",
+		"docs/sub/b.mmd":    "classDiagram\n    class Animal\n    class Animal\n",
+		"docs/notes.txt":    "not validated",
+		"docs/.hidden/c.md": "```mermaid\nnotADiagram\n```\n",
+		"outside.mmd":       "flowchart TD\n    A --> B\n",
+	} {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := newClient(t, root)
+
+	stream, err := client.StreamValidateDirectory(t.Context(), &mermaidcheckv1.StreamValidateDirectoryRequest{Path: "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errorCounts := map[string]int{}
+	for {
+		file, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		for _, result := range file.GetResults() {
+			errorCounts[file.GetPath()] += len(result.GetErrors())
+		}
+		if _, ok := errorCounts[file.GetPath()]; !ok {
+			errorCounts[file.GetPath()] = 0
+		}
+	}
+	if len(errorCounts) != 2 || errorCounts["docs/a.md"] != 0 || errorCounts["docs/sub/b.mmd"] != 1 {
+		t.Errorf("unexpected results: %v", errorCounts)
+	}
+
+	// Paths cannot escape the root
+	stream, err = client.StreamValidateDirectory(t.Context(), &mermaidcheckv1.StreamValidateDirectoryRequest{Path: "../../"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		file, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		if filepath.IsAbs(file.GetPath()) || file.GetPath()[:2] == ".." {
+			t.Errorf("file outside root reported: %s", file.GetPath())
+		}
+	}
+
+	stream, err = client.StreamValidateDirectory(t.Context(), &mermaidcheckv1.StreamValidateDirectoryRequest{Path: "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestStreamValidateDirectory_Symlinks(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.mmd"), []byte("flowchart TD\n    A --> B\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "docs", "a.mmd"), []byte("flowchart TD\n    A --> B\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{
+		"linked":              outside,
+		"docs/linked":         outside,
+		"docs/secret.mmd":     filepath.Join(outside, "secret.mmd"),
+		"docs/inside-too.mmd": filepath.Join(root, "docs", "a.mmd"),
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks unavailable: %v", err)
+		}
+	}
+	client := newClient(t, root)
+
+	// Directories resolving outside the root are rejected
+	stream, err := client.StreamValidateDirectory(t.Context(), &mermaidcheckv1.StreamValidateDirectoryRequest{Path: "linked"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+
+	// Symlinks within a directory are skipped
+	stream, err = client.StreamValidateDirectory(t.Context(), &mermaidcheckv1.StreamValidateDirectoryRequest{Path: "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for {
+		file, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		paths = append(paths, file.GetPath())
+	}
+	if len(paths) != 1 || paths[0] != "docs/a.mmd" {
+		t.Errorf("unexpected files: %v", paths)
+	}
+}
+
+func TestStreamValidateDirectory_Disabled(t *testing.T) {
+	client := newClient(t, "")
+
+	stream, err := client.StreamValidateDirectory(t.Context(), &mermaidcheckv1.StreamValidateDirectoryRequest{Path: "."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition, got %v", err)
+	}
+}
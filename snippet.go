@@ -0,0 +1,67 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// Snippet returns the line of source, a raw Mermaid diagram, that err refers
+// to with up to n lines of context either side, numbered and with the
+// offending line marked by ">" and a caret under err's column:
+//
+//	  2 | flowchart TD
+//	> 3 |     A --> B[Label
+//	    |     ^
+//	  4 |     B --> C
+//
+// Errors reported at column 1 (or with no column) have the caret under the
+// first non-blank character, since rules use column 1 for "the whole line".
+// Snippet returns "" when err has no line in source.
+func Snippet(source string, err validator.ValidationError, n int) string {
+	lines := strings.Split(strings.TrimSuffix(source, "\n"), "\n")
+	if err.Line < 1 || err.Line > len(lines) {
+		return ""
+	}
+	n = max(n, 0)
+	first, last := max(err.Line-n, 1), min(err.Line+n, len(lines))
+	width := len(fmt.Sprint(last))
+
+	var b strings.Builder
+	for number := first; number <= last; number++ {
+		line := lines[number-1]
+		marker := " "
+		if number == err.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, number, line)
+		if number == err.Line {
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", caretPadding(line, err.Column))
+		}
+	}
+	return b.String()
+}
+
+// caretPadding returns the whitespace that puts a caret under column (1-indexed,
+// in characters) of line, copying tabs so that it lines up however tabs are
+// displayed.
+func caretPadding(line string, column int) string {
+	runes := []rune(line)
+	offset := column - 1
+	if column <= 1 {
+		offset = len(runes) - len([]rune(strings.TrimLeftFunc(line, unicode.IsSpace)))
+	}
+	offset = min(offset, len(runes))
+
+	var padding strings.Builder
+	for _, r := range runes[:offset] {
+		if r == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+	return padding.String()
+}
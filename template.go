@@ -0,0 +1,47 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/examples"
+)
+
+// templateAliases maps other names for a diagram type to its template header.
+var templateAliases = map[string]string{
+	"state": "stateDiagram-v2",
+}
+
+// TemplateNames returns the headers of the diagram types Template has
+// skeletons for, such as "flowchart", "sequenceDiagram" and "C4Container",
+// in the order of the examples they come from.
+func TemplateNames() []string {
+	names := examples.Names()
+	for i, name := range names {
+		source, _ := examples.Get(name) // Listed by Names, so it exists
+		names[i] = templateHeader(source)
+	}
+	return names
+}
+
+// Template returns the example diagram of the named type (see package
+// examples), which passes validation, to start a new diagram from. The name
+// may be the diagram's header, as in "sequenceDiagram" or "C4Container", or
+// the type Parse reports for it, as in "sequence"; case is ignored.
+func Template(name string) (string, error) {
+	if header, ok := templateAliases[strings.ToLower(name)]; ok {
+		name = header
+	}
+	for _, example := range examples.Names() {
+		source, _ := examples.Get(example) // Listed by Names, so it exists
+		if strings.EqualFold(name, templateHeader(source)) || strings.EqualFold(name, example) {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("no template for diagram type %q (available: %s)", name, strings.Join(TemplateNames(), ", "))
+}
+
+// templateHeader returns the header keyword a template starts with.
+func templateHeader(template string) string {
+	return strings.Fields(template)[0]
+}
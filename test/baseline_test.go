@@ -0,0 +1,141 @@
+package mermaid_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docs", "doc.md")
+	writeFile(t, path, "```mermaid\nclassDiagram\n    class Animal\n    class Animal\n```\n")
+
+	results, err := mermaid.ValidatePath(dir, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := mermaid.NewBaseline(results, dir).Save(baselinePath); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline, err := mermaid.LoadBaseline(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(baseline.Findings) != 1 || baseline.Findings[0].File != "docs/doc.md" || baseline.Findings[0].Rule == "" {
+		t.Fatalf("unexpected findings: %+v", baseline.Findings)
+	}
+	opts := mermaid.ValidateOptions{Baseline: baseline}
+
+	t.Run("unchanged file is clean", func(t *testing.T) {
+		results, err := mermaid.ValidateFile(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !results[0].Valid() {
+			t.Errorf("expected baselined error to be hidden, got %+v", results[0].Errors)
+		}
+	})
+
+	t.Run("line shifts keep the finding", func(t *testing.T) {
+		writeFile(t, path, "# Title\n\nIntro.\n\n```mermaid\nclassDiagram\n    class Zoo\n\n    class Animal\n    class Animal\n```\n")
+		results, err := mermaid.ValidateFile(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !results[0].Valid() {
+			t.Errorf("expected baselined error to be hidden after a line shift, got %+v", results[0].Errors)
+		}
+	})
+
+	t.Run("new findings are reported", func(t *testing.T) {
+		writeFile(t, path, "```mermaid\nclassDiagram\n    class Animal\n    class Animal\n    class Animal\n    class Plant\n    class Plant\n```\n")
+		results, err := mermaid.ValidateFile(path, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results[0].Errors) != 2 {
+			t.Errorf("expected 2 new errors, got %+v", results[0].Errors)
+		}
+	})
+}
+
+func TestErrorFingerprint_IgnoresLineNumbers(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.mmd")
+	second := filepath.Join(dir, "second.mmd")
+	writeFile(t, first, "classDiagram\n    class Animal\n    class Animal\n")
+	writeFile(t, second, "classDiagram\n    class Zoo\n    class Animal\n    class Animal\n")
+
+	var fingerprints []string
+	for _, path := range []string{first, second} {
+		results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results[0].Errors) != 1 {
+			t.Fatalf("%s: expected 1 error, got %+v", path, results[0].Errors)
+		}
+		fingerprints = append(fingerprints, mermaid.ErrorFingerprint(results[0].Diagram, results[0].Errors[0]))
+	}
+	if fingerprints[0] != fingerprints[1] {
+		t.Errorf("fingerprints differ after a line shift: %v", fingerprints)
+	}
+}
+
+func TestBaseline_ParseErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "```mermaid\nsequenceDiagram\n    A => B: hi\n```\n")
+
+	results, err := mermaid.ValidatePath(dir, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline := mermaid.NewBaseline(results, dir)
+	if len(baseline.Findings) != 1 || baseline.Findings[0].Rule != mermaid.ParseErrorRule {
+		t.Fatalf("unexpected findings: %+v", baseline.Findings)
+	}
+	opts := mermaid.ValidateOptions{Baseline: baseline}
+
+	// Shifting the diagram down keeps the finding
+	writeFile(t, path, "# Title\n\n```mermaid\n%% note\nsequenceDiagram\n    A => B: hi\n```\n")
+	results, err = mermaid.ValidateFile(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Valid() {
+		t.Errorf("expected baselined parse error to be hidden, got %v", results[0].ParseError)
+	}
+
+	// A different parse error is reported
+	writeFile(t, path, "```mermaid\nsequenceDiagram\n    A => C: hi\n```\n")
+	results, err = mermaid.ValidateFile(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].ParseError == nil {
+		t.Error("expected a new parse error to be reported")
+	}
+}
+
+func TestErrorFingerprint_KeepsIdentifiers(t *testing.T) {
+	diagram, err := mermaid.Parse("flowchart TD\n    L1 --> L2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := func(message string) string {
+		return mermaid.ErrorFingerprint(diagram, validator.ValidationError{Line: 2, Message: message, Rule: "test"})
+	}
+
+	if fingerprint("node 'L1' links to 'L2'") == fingerprint("node 'L7' links to 'L9'") {
+		t.Error("identifiers such as L1 were treated as line numbers")
+	}
+	if fingerprint("first defined at line 3") != fingerprint("first defined at line 12") {
+		t.Error("line numbers were not ignored")
+	}
+}
@@ -0,0 +1,84 @@
+package mermaid_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestCapabilities(t *testing.T) {
+	manifest := mermaid.Capabilities()
+	if manifest.Version != mermaid.Version {
+		t.Errorf("Version = %q, want %q", manifest.Version, mermaid.Version)
+	}
+
+	var types []string
+	for _, diagramType := range manifest.DiagramTypes {
+		types = append(types, diagramType.Type)
+		if len(diagramType.Headers) == 0 || len(diagramType.Features) == 0 {
+			t.Errorf("%s: headers %v and features %v should not be empty", diagramType.Type, diagramType.Headers, diagramType.Features)
+		}
+		for _, header := range diagramType.Headers {
+			if detected := parser.DetectType(header); detected == "unknown" {
+				t.Errorf("%s: header %q is not recognised", diagramType.Type, header)
+			}
+		}
+	}
+	if !slices.Equal(types, validator.DiagramKinds) {
+		t.Errorf("diagram types = %v, want %v", types, validator.DiagramKinds)
+	}
+
+	rules := make(map[string]mermaid.RuleCapability)
+	var names []string
+	for _, rule := range manifest.Rules {
+		names = append(names, rule.Name)
+		rules[rule.Name] = rule
+		if rule.Version < 1 {
+			t.Errorf("%s: version %d, want at least 1", rule.Name, rule.Version)
+		}
+	}
+	if !slices.IsSorted(names) || len(rules) != len(names) {
+		t.Errorf("rules should be sorted and unique: %v", names)
+	}
+
+	tests := []struct {
+		name                string
+		types               []string
+		inDefault, inStrict bool
+		options             int
+	}{
+		{"no-duplicate-node-ids", []string{"flowchart"}, true, true, 0},
+		{"self-loops", []string{"flowchart", "state", "class", "c4"}, false, true, 0},
+		{"confusable-characters", nil, false, true, 0},
+		{"label-length", []string{"flowchart", "sequence"}, false, true, 2},
+		{"max-participants", []string{"sequence"}, false, false, 1},
+		{"renderer-compatibility", nil, false, false, 0},
+	}
+	for _, tt := range tests {
+		rule, ok := rules[tt.name]
+		if !ok {
+			t.Errorf("rule %s missing", tt.name)
+			continue
+		}
+		if !slices.Equal(rule.DiagramTypes, tt.types) || rule.Default != tt.inDefault || rule.Strict != tt.inStrict || len(rule.Options) != tt.options {
+			t.Errorf("%s = %+v, want types %v, default %v, strict %v and %d options", tt.name, rule, tt.types, tt.inDefault, tt.inStrict, tt.options)
+		}
+	}
+}
+
+func TestCapabilitiesJSON(t *testing.T) {
+	data, err := json.Marshal(mermaid.Capabilities())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{`"version":`, `"diagramTypes":`, `"headers":`, `"features":`, `"rules":`, `"default":`, `"strict":`, `"options":`} {
+		if !strings.Contains(string(data), field) {
+			t.Errorf("manifest JSON has no %s field", field)
+		}
+	}
+}
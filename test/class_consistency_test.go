@@ -0,0 +1,81 @@
+package mermaid_test
+
+import (
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+const classModelDoc = "# Model\n\n" +
+	"```mermaid\nclassDiagram\n    class Animal {\n        +name String\n        +eat() void\n    }\n    class Zoo\n    Zoo *-- Animal\n```\n\n" +
+	"```mermaid\nclassDiagram\n    class Animal {\n        +name String\n        +sleep() void\n    }\n    Keeper --> Zoo\n```\n"
+
+func TestCheckClassConsistency(t *testing.T) {
+	results, err := mermaid.ValidateSource("model.md", classModelDoc, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errors := mermaid.CheckClassConsistency(results)
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errors)
+	}
+
+	members := errors[0]
+	if members.BlockIndex != 1 || members.Line != 15 || members.Rule != "class-consistency" {
+		t.Errorf("members error = %+v, want diagram 2 line 15", members)
+	}
+	for _, want := range []string{"class 'Animal'", "diagram 1 at line 5", "missing +eat() void", "adds +sleep() void"} {
+		if !strings.Contains(members.Message, want) {
+			t.Errorf("message %q does not contain %q", members.Message, want)
+		}
+	}
+
+	relationship := errors[1]
+	if relationship.BlockIndex != 1 || relationship.Line != 19 || !strings.Contains(relationship.Message, "class 'Zoo', which is only defined in diagram 1 at line 9") {
+		t.Errorf("relationship error = %+v", relationship)
+	}
+}
+
+func TestCheckClassConsistency_SeparateFiles(t *testing.T) {
+	first, err := mermaid.ValidateSource("a.mmd", "classDiagram\n    class Zoo\n", mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := mermaid.ValidateSource("b.mmd", "classDiagram\n    Keeper --> Zoo\n", mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errors := mermaid.CheckClassConsistency(append(first, second...)); len(errors) != 0 {
+		t.Errorf("expected no errors across files, got %v", errors)
+	}
+}
+
+func TestValidateSource_CheckClassConsistency(t *testing.T) {
+	results, err := mermaid.ValidateSource("model.md", classModelDoc, mermaid.ValidateOptions{CheckClassConsistency: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found int
+	for _, err := range results[1].Errors {
+		if err.Rule == "class-consistency" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("expected 2 class-consistency errors on the second diagram, got %v", results[1].Errors)
+	}
+
+	results, err = mermaid.ValidateSource("model.md", classModelDoc, mermaid.ValidateOptions{
+		CheckClassConsistency: true,
+		PathRules:             []mermaid.PathRules{{Paths: []string{"*.md"}, Disable: []string{"class-consistency"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range results[1].Errors {
+		if err.Rule == "class-consistency" {
+			t.Errorf("disabled rule still reported: %v", err)
+		}
+	}
+}
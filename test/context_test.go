@@ -0,0 +1,137 @@
+package mermaid_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+func TestParseContext(t *testing.T) {
+	diagram, err := mermaid.ParseContext(context.Background(), "flowchart TD\n    A --> B")
+	if err != nil {
+		t.Fatalf("ParseContext() error = %v", err)
+	}
+	if diagram.GetType() != "flowchart" {
+		t.Errorf("expected flowchart, got %s", diagram.GetType())
+	}
+
+	if _, err := mermaid.ParseContext(context.Background(), "bogus"); err == nil {
+		t.Error("expected parse error to be returned")
+	}
+}
+
+func TestParseContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := mermaid.ParseContext(ctx, "flowchart TD\n    A --> B"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestValidateContext(t *testing.T) {
+	diagram, err := mermaid.Parse("classDiagram\n    class A\n    class A")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs, err := mermaid.ValidateContext(context.Background(), diagram, false)
+	if err != nil {
+		t.Fatalf("ValidateContext() error = %v", err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected 1 validation error, got %d", len(errs))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	if _, err := mermaid.ValidateContext(ctx, diagram, false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestParseFileContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.md")
+	writeFile(t, path, "```mermaid\nflowchart TD\n    A --> B\n```\n\n```mermaid\nsequenceDiagram\n    A->>B: Hi\n```\n")
+
+	diagrams, err := mermaid.ParseFileContext(context.Background(), path)
+	if err != nil {
+		t.Fatalf("ParseFileContext() error = %v", err)
+	}
+	if len(diagrams) != 2 {
+		t.Errorf("expected 2 diagrams, got %d", len(diagrams))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mermaid.ParseFileContext(ctx, path); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestValidateSourceContext(t *testing.T) {
+	content := "```mermaid\nflowchart TD\n    A --> B\n```\n\n```mermaid\nclassDiagram\n    class A\n    class A\n```\n"
+	limits := parser.DefaultLimits()
+	results, err := mermaid.ValidateSourceContext(context.Background(), "doc.md", content, mermaid.ValidateOptions{Limits: &limits})
+	if err != nil {
+		t.Fatalf("ValidateSourceContext() error = %v", err)
+	}
+	if len(results) != 2 || len(results[1].Errors) != 1 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mermaid.ValidateSourceContext(ctx, "doc.md", content, mermaid.ValidateOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestValidateSource_Limits(t *testing.T) {
+	source := "flowchart TD\n" + strings.Repeat("    A --> B\n", 20)
+	limits := parser.Limits{MaxLines: 10}
+	results, err := mermaid.ValidateSource("diagram.mmd", source, mermaid.ValidateOptions{Limits: &limits})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !errors.Is(results[0].ParseError, parser.ErrLimitExceeded) {
+		t.Errorf("expected a limit exceeded parse error, got %+v", results)
+	}
+
+	results, err = mermaid.ValidateSource("diagram.mmd", source, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ParseError != nil {
+		t.Errorf("expected no parse error without limits, got %v", results[0].ParseError)
+	}
+}
+
+func TestParseContextWithLimits(t *testing.T) {
+	limits := parser.DefaultLimits()
+	diagram, err := mermaid.ParseContextWithLimits(context.Background(), "flowchart TD\n    A --> B", limits)
+	if err != nil {
+		t.Fatalf("ParseContextWithLimits() error = %v", err)
+	}
+	if diagram.GetType() != "flowchart" {
+		t.Errorf("expected flowchart, got %s", diagram.GetType())
+	}
+
+	source := "flowchart TD\n" + strings.Repeat("    subgraph S\n", 60) + strings.Repeat("    end\n", 60)
+	if _, err := mermaid.ParseContextWithLimits(context.Background(), source, limits); !errors.Is(err, parser.ErrLimitExceeded) {
+		t.Errorf("expected parser.ErrLimitExceeded, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := mermaid.ParseContextWithLimits(ctx, "flowchart TD\n    A --> B", limits); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package mermaid_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{
+			name: "whitespace, comments and annotations are ignored",
+			a:    "flowchart TD\n    A[Start] --> B[End]",
+			b:    "flowchart TD\n  %% @owner: docs\n  %% the main flow\n\n  A[Start]   -->   B[End]",
+			same: true,
+		},
+		{
+			name: "different labels",
+			a:    "flowchart TD\n    A[Start] --> B[End]",
+			b:    "flowchart TD\n    A[Begin] --> B[End]",
+		},
+		{
+			name: "same content, different diagram type",
+			a:    "flowchart TD\n    A --> B",
+			b:    "graph TD\n    A --> B",
+		},
+		{
+			name: "identical pie charts",
+			a:    "pie\n    \"A\" : 1\n    \"B\" : 2",
+			b:    "pie\n  \"A\" : 1\n  \"B\" : 2",
+			same: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := mermaid.Parse(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := mermaid.Parse(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := mermaid.Fingerprint(a) == mermaid.Fingerprint(b); got != tt.same {
+				t.Errorf("fingerprints equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	a, _ := mermaid.Parse("flowchart TD\n    A --> B\n    B --> C\n    C --> D\n    D --> E")
+	b, _ := mermaid.Parse("flowchart TD\n    A --> B\n    B --> C\n    C --> D\n    D --> F")
+	c, _ := mermaid.Parse("sequenceDiagram\n    A->>B: hi")
+
+	if got := mermaid.Similarity(a, a); got != 1 {
+		t.Errorf("Similarity(a, a) = %v, want 1", got)
+	}
+	if got := mermaid.Similarity(a, b); got <= 0.5 || got >= 1 {
+		t.Errorf("Similarity(a, b) = %v, want between 0.5 and 1", got)
+	}
+	if got := mermaid.Similarity(a, c); got != 0 {
+		t.Errorf("Similarity(a, c) = %v, want 0", got)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	base := "flowchart TD\n    A --> B\n    B --> C\n    C --> D\n    D --> E\n    E --> F\n"
+	writeFile(t, filepath.Join(dir, "a.mmd"), base)
+	writeFile(t, filepath.Join(dir, "b.md"), "# Copy\n\n```mermaid\n"+base+"```\n")
+	writeFile(t, filepath.Join(dir, "c.mmd"), base+"    F --> G\n")
+	writeFile(t, filepath.Join(dir, "d.mmd"), "sequenceDiagram\n    A->>B: unrelated\n")
+
+	results, err := mermaid.ValidatePath(dir, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidatePath() error = %v", err)
+	}
+
+	exact := mermaid.FindDuplicates(results, 1)
+	if len(exact) != 1 || len(exact[0].Results) != 2 || !exact[0].Identical {
+		t.Fatalf("exact duplicates = %+v, want one identical pair", exact)
+	}
+
+	near := mermaid.FindDuplicates(results, 0.8)
+	if len(near) != 1 || len(near[0].Results) != 3 || near[0].Identical {
+		t.Fatalf("near duplicates = %+v, want one group of 3", near)
+	}
+	if near[0].Similarity >= 1 || near[0].Similarity < 0.8 {
+		t.Errorf("group similarity = %v", near[0].Similarity)
+	}
+}
@@ -0,0 +1,54 @@
+package mermaid_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+func TestExplain_Valid(t *testing.T) {
+	explanation := mermaid.Explain("flowchart TD\n    A --> B\n")
+	if !explanation.Valid || explanation.DiagramType != "flowchart" {
+		t.Errorf("Explain() = %+v, want a valid flowchart", explanation)
+	}
+	if len(explanation.Errors) != 0 || len(explanation.Expected) != 0 {
+		t.Errorf("Explain() reported errors or constructs for a valid diagram: %+v", explanation)
+	}
+}
+
+func TestExplain_InvalidLine(t *testing.T) {
+	explanation := mermaid.Explain("sequenceDiagram\n    Alice->>Bob: Hello\n    Alice=>Bob bad\n")
+	if explanation.Valid {
+		t.Fatal("Explain() reported an invalid diagram as valid")
+	}
+	if explanation.DiagramType != "sequence" {
+		t.Errorf("DiagramType = %q, want sequence", explanation.DiagramType)
+	}
+	if explanation.Line != 3 || explanation.LineText != "Alice=>Bob bad" {
+		t.Errorf("Line = %d %q, want 3 %q", explanation.Line, explanation.LineText, "Alice=>Bob bad")
+	}
+	if explanation.Message == "" || len(explanation.Errors) != 1 {
+		t.Errorf("Message = %q, Errors = %v", explanation.Message, explanation.Errors)
+	}
+	if !slices.ContainsFunc(explanation.Expected, func(s string) bool { return strings.HasPrefix(s, "message:") }) {
+		t.Errorf("Expected = %v, want the sequence message construct", explanation.Expected)
+	}
+	if len(explanation.Candidates) != 0 {
+		t.Errorf("Candidates = %v, want none for a known header", explanation.Candidates)
+	}
+}
+
+func TestExplain_UnknownHeader(t *testing.T) {
+	explanation := mermaid.Explain("sequenceDiagarm\n    A->>B: hi\n")
+	if explanation.Valid || explanation.DiagramType != "unknown" {
+		t.Fatalf("Explain() = %+v, want an unknown diagram", explanation)
+	}
+	if len(explanation.Candidates) == 0 || explanation.Candidates[0] != "sequenceDiagram" {
+		t.Errorf("Candidates = %v, want sequenceDiagram first", explanation.Candidates)
+	}
+	if len(explanation.Expected) != 1 || !strings.Contains(explanation.Expected[0], "header") {
+		t.Errorf("Expected = %v, want the header construct", explanation.Expected)
+	}
+}
@@ -0,0 +1,37 @@
+package mermaid_test
+
+import (
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+func TestFixSource(t *testing.T) {
+	result, err := mermaid.FixSource("classDiagram\n    class Animal\n    class Animal\n    class Animal\n    Animal <|-- Dog\n", mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("FixSource() error = %v", err)
+	}
+	if want := "classDiagram\n    class Animal\n    Animal <|-- Dog\n"; result.Source != want {
+		t.Errorf("Source = %q, want %q", result.Source, want)
+	}
+	if result.Applied != 2 || len(result.Remaining) != 0 {
+		t.Errorf("Applied = %d, Remaining = %v", result.Applied, result.Remaining)
+	}
+
+	if _, err := mermaid.FixSource("notADiagram\n", mermaid.ValidateOptions{}); err == nil {
+		t.Error("FixSource() expected parse error")
+	}
+}
+
+func TestFixSource_ConfusableCharacters(t *testing.T) {
+	result, err := mermaid.FixSource("flowchart TD\n    A\u00a0\u2014> B[\u201cEnd\u201d]\n", mermaid.ValidateOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("FixSource() error = %v", err)
+	}
+	if want := "flowchart TD\n    A --> B[\"End\"]\n"; result.Source != want {
+		t.Errorf("Source = %q, want %q", result.Source, want)
+	}
+	if result.Applied != 1 || len(result.Remaining) != 0 {
+		t.Errorf("Applied = %d, Remaining = %v", result.Applied, result.Remaining)
+	}
+}
@@ -0,0 +1,180 @@
+package mermaid_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		options    string
+		wantValid  bool
+		wantBlocks int
+		wantErrors int
+		wantError  bool
+	}{
+		{
+			name:       "valid mermaid",
+			source:     "flowchart TD\n    A --> B\n",
+			wantValid:  true,
+			wantBlocks: 1,
+		},
+		{
+			name:       "validation error",
+			source:     "classDiagram\n    class Animal\n    class Animal\n",
+			wantBlocks: 1,
+			wantErrors: 1,
+		},
+		{
+			name:       "disabled rule",
+			source:     "classDiagram\n    class Animal\n    class Animal\n",
+			options:    `{"disable": ["no-duplicate-classes"]}`,
+			wantValid:  true,
+			wantBlocks: 1,
+		},
+		{
+			name:       "markdown detected from fences",
+			source:     "# Doc\n\n```mermaid\npie\n    \"A\" : 1\n```\n\n```mermaid\nflowchart TD\n    A --> B\n```\n",
+			wantValid:  true,
+			wantBlocks: 2,
+		},
+		{
+			name:       "required annotations",
+			source:     "flowchart TD\n    A --> B\n",
+			options:    `{"requiredAnnotations": ["owner"]}`,
+			wantBlocks: 1,
+			wantErrors: 1,
+		},
+		{
+			name:       "fence language aliases",
+			source:     "```mmd\nflowchart TD\n    A --> B\n```\n\n```mermaid\npie\n    \"A\" : 1\n```\n",
+			wantValid:  true,
+			wantBlocks: 2,
+		},
+		{
+			name:       "custom fence languages",
+			source:     "```mmd\nflowchart TD\n    A --> B\n```\n\n```mermaid\npie\n    \"A\" : 1\n```\n",
+			options:    `{"format": "markdown", "fenceLanguages": ["mmd"]}`,
+			wantValid:  true,
+			wantBlocks: 1,
+		},
+		{
+			name:      "invalid options",
+			source:    "flowchart TD\n    A --> B\n",
+			options:   `{"strict": "yes"}`,
+			wantError: true,
+		},
+		{
+			name:       "renderer",
+			source:     "flowchart TD\n    A[\"`**Bold**`\"] --> B\n",
+			options:    `{"renderer": "notion"}`,
+			wantBlocks: 1,
+			wantErrors: 1,
+		},
+		{
+			name:       "warning makes the report invalid",
+			source:     "sequenceDiagram\n    Alice->>Bob: It’s done\n",
+			options:    `{"strict": true}`,
+			wantBlocks: 1,
+			wantErrors: 1,
+		},
+		{
+			name:      "unknown renderer",
+			source:    "flowchart TD\n    A --> B\n",
+			options:   `{"renderer": "wordpress"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid format",
+			source:    "flowchart TD\n    A --> B\n",
+			options:   `{"format": "html"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var report mermaid.JSONReport
+			if err := json.Unmarshal([]byte(mermaid.ValidateJSON(tt.source, tt.options)), &report); err != nil {
+				t.Fatalf("ValidateJSON() returned invalid JSON: %v", err)
+			}
+			if (report.Error != "") != tt.wantError {
+				t.Fatalf("error = %q, wantError %v", report.Error, tt.wantError)
+			}
+			if report.Valid != tt.wantValid {
+				t.Errorf("valid = %v, want %v", report.Valid, tt.wantValid)
+			}
+			if len(report.Results) != tt.wantBlocks {
+				t.Fatalf("got %d results, want %d", len(report.Results), tt.wantBlocks)
+			}
+			var errors int
+			for _, result := range report.Results {
+				errors += len(result.Errors)
+			}
+			if errors != tt.wantErrors {
+				t.Errorf("got %d errors, want %d: %+v", errors, tt.wantErrors, report.Results)
+			}
+		})
+	}
+}
+
+func TestValidateJSON_Suggestion(t *testing.T) {
+	var report mermaid.JSONReport
+	if err := json.Unmarshal([]byte(mermaid.ValidateJSON("sequenceDiagram\n    loop every minute\n        A->>B: ping\n", "")), &report); err != nil {
+		t.Fatalf("ValidateJSON() returned invalid JSON: %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].ParseError == "" {
+		t.Fatalf("expected a parse error: %+v", report)
+	}
+	if want := "add 'end' to close the 'loop' opened on line 2"; report.Results[0].Suggestion != want {
+		t.Errorf("suggestion = %q, want %q", report.Results[0].Suggestion, want)
+	}
+}
+
+func TestValidateSource(t *testing.T) {
+	results, err := mermaid.ValidateSource("doc.md", "```mermaid\nflowchart TD\n    A --> B\n```\n", mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateSource() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].Valid() || results[0].File != "doc.md" || results[0].LineOffset != 2 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	if _, err := mermaid.ValidateSource("doc.txt", "flowchart TD\n", mermaid.ValidateOptions{}); err == nil {
+		t.Error("ValidateSource() expected error for unsupported file type")
+	}
+}
+
+func TestValidateJSON_AST(t *testing.T) {
+	var report mermaid.JSONReport
+	source := "```mermaid\nflowchart TD\n    A --> B\n```\n\n```mermaid\nsequenceDiagramm\n    A->>B: hi\n```\n"
+	if err := json.Unmarshal([]byte(mermaid.ValidateJSON(source, `{"ast": true}`)), &report); err != nil {
+		t.Fatalf("ValidateJSON() returned invalid JSON: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(report.Results))
+	}
+	document := report.Results[0].AST
+	if document == nil {
+		t.Fatal("expected an AST for the parsed diagram")
+	}
+	if document.SchemaVersion != ast.SchemaVersion || document.Diagram["kind"] != "Flowchart" {
+		t.Errorf("AST = %+v, want a schema version %d flowchart", document, ast.SchemaVersion)
+	}
+	if report.Results[1].ParseError == "" || report.Results[1].AST != nil {
+		t.Errorf("expected a parse error and no AST: %+v", report.Results[1])
+	}
+
+	var plain mermaid.JSONReport
+	if err := json.Unmarshal([]byte(mermaid.ValidateJSON(source, "")), &plain); err != nil {
+		t.Fatalf("ValidateJSON() returned invalid JSON: %v", err)
+	}
+	if plain.Results[0].AST != nil {
+		t.Error("AST included without the ast option")
+	}
+}
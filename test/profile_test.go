@@ -0,0 +1,102 @@
+package mermaid_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+func TestParseWithMetrics(t *testing.T) {
+	diagram, metrics, err := mermaid.ParseWithMetrics("flowchart TD\n    A --> B")
+	if err != nil || diagram == nil {
+		t.Fatalf("ParseWithMetrics() error = %v", err)
+	}
+	if metrics.Parse <= 0 || metrics.Validate != 0 || len(metrics.Rules) != 0 {
+		t.Errorf("metrics = %+v, want only a parse time", metrics)
+	}
+}
+
+func TestValidateWithMetrics(t *testing.T) {
+	// A curly apostrophe, which only the strict rules for every diagram type report
+	diagram, err := mermaid.Parse("sequenceDiagram\n    participant A\n    A->>B: it’s me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, strict := range []bool{false, true} {
+		errors, metrics := mermaid.ValidateWithMetrics(diagram, strict)
+		if want := mermaid.Validate(diagram, strict); !reflect.DeepEqual(errors, want) {
+			t.Errorf("strict=%v: errors = %v, want %v", strict, errors, want)
+		}
+		if len(metrics.Rules) == 0 || metrics.Validate <= 0 {
+			t.Errorf("strict=%v: metrics = %+v, want rule timings", strict, metrics)
+		}
+	}
+}
+
+func TestProfileWith(t *testing.T) {
+	diagram, err := mermaid.Parse("flowchart TD\n    A[Same] --> A\n    B[Same] --> C\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []mermaid.ValidateOptions{
+		{},
+		{Level: mermaid.LevelStrict},
+		{Level: mermaid.LevelNone, Rules: []string{"self-loops"}},
+		{Strict: true, Disable: []string{"self-loops"}},
+	} {
+		errors, metrics := mermaid.ProfileWith(diagram, opts)
+		if want := mermaid.ValidateWith(diagram, opts); !reflect.DeepEqual(errors, want) {
+			t.Errorf("%+v: errors = %v, want %v", opts, errors, want)
+		}
+		if opts.Level != mermaid.LevelNone && (len(metrics.Rules) == 0 || metrics.Validate <= 0) {
+			t.Errorf("%+v: metrics = %+v, want rule timings", opts, metrics)
+		}
+	}
+}
+
+// Profiling times each rule separately but must report the same errors.
+func TestValidatePath_ProfileMatches(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		opts := mermaid.ValidateOptions{Strict: strict, LabelLengths: map[string]int{"flowchart": 10}}
+		want, err := mermaid.ValidatePath("../testdata", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts.Profile = true
+		got, err := mermaid.ValidatePath("../testdata", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("strict=%v: got %d results, want %d", strict, len(got), len(want))
+		}
+		for i := range got {
+			if !reflect.DeepEqual(got[i].Errors, want[i].Errors) {
+				t.Errorf("strict=%v: %s #%d errors = %v, want %v", strict, got[i].File, got[i].BlockIndex, got[i].Errors, want[i].Errors)
+			}
+			if want[i].Metrics != nil || got[i].Metrics == nil {
+				t.Errorf("strict=%v: %s #%d metrics = %v without and %v with profiling", strict, got[i].File, got[i].BlockIndex, want[i].Metrics, got[i].Metrics)
+			}
+		}
+	}
+}
+
+func TestValidateJSON_Profile(t *testing.T) {
+	var report mermaid.JSONReport
+	if err := json.Unmarshal([]byte(mermaid.ValidateJSON("pie\n    \"A\" : 1", `{"profile":true}`)), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 1 || report.Results[0].Metrics == nil || len(report.Results[0].Metrics.Rules) == 0 {
+		t.Fatalf("report = %+v, want rule timings", report)
+	}
+
+	var unprofiled mermaid.JSONReport
+	if err := json.Unmarshal([]byte(mermaid.ValidateJSON("pie\n    \"A\" : 1", "")), &unprofiled); err != nil {
+		t.Fatal(err)
+	}
+	if unprofiled.Results[0].Metrics != nil {
+		t.Errorf("metrics = %+v without profiling", unprofiled.Results[0].Metrics)
+	}
+}
@@ -0,0 +1,69 @@
+package mermaid_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+func TestValidatePath_CheckReferences(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "overview.md"), "# Overview\n\n```mermaid\nflowchart TD\n    %% @id: ARCH-1\n    A --> B\n    click A href \"#ARCH-42\"\n    click B href \"#ARCH-99\"\n```\n")
+	writeFile(t, filepath.Join(dir, "detail", "payments.mmd"), "flowchart LR\n    %% @id: ARCH-42\n    P --> Q\n    click Q \"https://example.com\"\n")
+	writeFile(t, filepath.Join(dir, "detail", "copy.mmd"), "flowchart LR\n    %% @id: ARCH-42\n    R --> S\n")
+
+	results, err := mermaid.ValidatePath(dir, mermaid.ValidateOptions{CheckReferences: true})
+	if err != nil {
+		t.Fatalf("ValidatePath() error = %v", err)
+	}
+
+	var messages []string
+	for _, r := range results {
+		for _, e := range r.Errors {
+			messages = append(messages, filepath.Base(r.File)+": "+e.Message)
+		}
+	}
+
+	want := []string{
+		"payments.mmd: duplicate diagram id 'ARCH-42'",
+		"overview.md: link on 'B' references unknown diagram id 'ARCH-99'",
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("got errors %v, want %d", messages, len(want))
+	}
+	for _, w := range want {
+		found := false
+		for _, m := range messages {
+			if strings.HasPrefix(m, w) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing error %q in %v", w, messages)
+		}
+	}
+}
+
+func TestCheckReferences_LineNumbers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "Intro\n\n```mermaid\nflowchart TD\n    A --> B\n    click A href \"#MISSING\"\n```\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+
+	refErrors := mermaid.CheckReferences(results)
+	if len(refErrors) != 1 {
+		t.Fatalf("expected 1 reference error, got %v", refErrors)
+	}
+	if refErrors[0].Line != 6 {
+		t.Errorf("Line = %d, want 6", refErrors[0].Line)
+	}
+	if got := refErrors[0].Error(); !strings.HasSuffix(got, "doc.md:6: link on 'A' references unknown diagram id 'MISSING'") {
+		t.Errorf("Error() = %q", got)
+	}
+}
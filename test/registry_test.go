@@ -0,0 +1,50 @@
+package mermaid_test
+
+import (
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/extractor"
+	"github.com/sammcj/mermaid-check/parser"
+)
+
+// dialectDiagram is a diagram type only a registered parser produces.
+type dialectDiagram struct {
+	source string
+	ast.Annotations
+}
+
+func (d *dialectDiagram) GetType() string           { return "dialect-test" }
+func (d *dialectDiagram) GetPosition() ast.Position { return ast.Position{Line: 1, Column: 1} }
+
+type dialectParser struct{}
+
+func (p *dialectParser) Parse(source string) (ast.Diagram, error) {
+	return &dialectDiagram{source: source}, nil
+}
+
+func (p *dialectParser) SupportedTypes() []string { return []string{"dialect-test"} }
+
+func TestRegisteredParserRouting(t *testing.T) {
+	parser.Register("dialect-test", func() parser.DiagramParser { return &dialectParser{} })
+
+	blocks, err := extractor.ExtractFromMarkdown("# Doc\n\n```mermaid\ndialect-test\n    thing\n```\n")
+	if err != nil {
+		t.Fatalf("ExtractFromMarkdown() error = %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].DiagramType != "dialect-test" {
+		t.Fatalf("blocks = %+v, want one dialect-test block", blocks)
+	}
+
+	diagram, err := mermaid.Parse(blocks[0].Source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, ok := diagram.(*dialectDiagram); !ok {
+		t.Fatalf("Parse() returned %T, want *dialectDiagram", diagram)
+	}
+	if errs := mermaid.Validate(diagram, true); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
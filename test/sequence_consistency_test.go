@@ -0,0 +1,76 @@
+package mermaid_test
+
+import (
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+)
+
+const architectureDoc = "# Orders\n\n" +
+	"```mermaid\nflowchart LR\n    Web[Web App] --> OrdersAPI[Orders API]\n    OrdersAPI --> DB[(Orders DB)]\n    OrdersAPI --> Audit\n```\n\n" +
+	"```mermaid\nsequenceDiagram\n    participant web_app\n    participant API as Orders API\n    web_app->>API: POST /orders\n    API->>Payments: charge\n    API->>DB: insert\n```\n"
+
+func TestCheckSequenceConsistency(t *testing.T) {
+	results, err := mermaid.ValidateSource("orders.md", architectureDoc, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errors := mermaid.CheckSequenceConsistency(results)
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errors)
+	}
+
+	participant := errors[0]
+	if participant.BlockIndex != 1 || participant.Line != 15 || participant.Rule != "sequence-consistency" ||
+		!strings.Contains(participant.Message, "participant 'Payments' does not appear in the flowchart in diagram 1") {
+		t.Errorf("participant error = %+v", participant)
+	}
+
+	node := errors[1]
+	if node.BlockIndex != 0 || node.Line != 7 || !strings.Contains(node.Message, "flowchart node 'Audit' does not appear in sequence diagram 2") {
+		t.Errorf("node error = %+v", node)
+	}
+}
+
+func TestCheckSequenceConsistency_NearestArchitecture(t *testing.T) {
+	doc := "```mermaid\nflowchart LR\n    A --> B\n```\n\n" +
+		"```mermaid\nsequenceDiagram\n    A->>B: hello\n```\n\n" +
+		"```mermaid\nC4Context\n    Person(user, \"User\")\n    System(shop, \"Shop\")\n    Rel(user, shop, \"Buys from\")\n```\n\n" +
+		"```mermaid\nsequenceDiagram\n    User->>Shop: buy\n```\n"
+	results, err := mermaid.ValidateSource("views.md", doc, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errors := mermaid.CheckSequenceConsistency(results); len(errors) != 0 {
+		t.Errorf("expected each sequence diagram to match its nearest architecture diagram, got %v", errors)
+	}
+}
+
+func TestCheckSequenceConsistency_NoArchitecture(t *testing.T) {
+	results, err := mermaid.ValidateSource("flow.mmd", "sequenceDiagram\n    A->>B: hello\n", mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errors := mermaid.CheckSequenceConsistency(results); len(errors) != 0 {
+		t.Errorf("expected no errors without an architecture diagram, got %v", errors)
+	}
+}
+
+func TestValidateSource_CheckSequenceConsistency(t *testing.T) {
+	results, err := mermaid.ValidateSource("orders.md", architectureDoc, mermaid.ValidateOptions{CheckSequenceConsistency: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{1, 1} {
+		var found int
+		for _, err := range results[i].Errors {
+			if err.Rule == "sequence-consistency" {
+				found++
+			}
+		}
+		if found != want {
+			t.Errorf("diagram %d: expected %d sequence-consistency error(s), got %v", i+1, want, results[i].Errors)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package mermaid_test
+
+import (
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestSnippet(t *testing.T) {
+	source := "flowchart TD\n    A --> B\n    B --> C\n    C --> D\n"
+	tests := []struct {
+		name string
+		err  validator.ValidationError
+		n    int
+		want string
+	}{
+		{
+			name: "context either side",
+			err:  validator.ValidationError{Line: 3, Column: 11},
+			n:    1,
+			want: "  2 |     A --> B\n> 3 |     B --> C\n    |           ^\n  4 |     C --> D\n",
+		},
+		{
+			name: "column 1 points at the first character",
+			err:  validator.ValidationError{Line: 2, Column: 1},
+			want: "> 2 |     A --> B\n    |     ^\n",
+		},
+		{
+			name: "context clipped at the start",
+			err:  validator.ValidationError{Line: 1, Column: 11},
+			n:    2,
+			want: "> 1 | flowchart TD\n    |           ^\n  2 |     A --> B\n  3 |     B --> C\n",
+		},
+		{
+			name: "no such line",
+			err:  validator.ValidationError{Line: 9},
+			n:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mermaid.Snippet(source, tt.err, tt.n); got != tt.want {
+				t.Errorf("Snippet() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet_Tabs(t *testing.T) {
+	got := mermaid.Snippet("flowchart TD\n\tA --> B", validator.ValidationError{Line: 2, Column: 3}, 0)
+	if want := "> 2 | \tA --> B\n    | \t ^\n"; got != want {
+		t.Errorf("Snippet() = %q, want %q", got, want)
+	}
+}
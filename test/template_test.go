@@ -0,0 +1,74 @@
+package mermaid_test
+
+import (
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/examples"
+)
+
+func TestTemplate_Valid(t *testing.T) {
+	for _, name := range mermaid.TemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			source, err := mermaid.Template(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(source, name) {
+				t.Errorf("template for %s starts %q", name, strings.SplitN(source, "\n", 2)[0])
+			}
+			for _, strict := range []bool{false, true} {
+				diagram, err := mermaid.Parse(source)
+				if err != nil {
+					t.Fatalf("template does not parse: %v\n%s", err, source)
+				}
+				if errors := mermaid.Validate(diagram, strict); len(errors) > 0 {
+					t.Errorf("template has errors (strict=%v): %v\n%s", strict, errors, source)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplate_Names(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"flowchart", "flowchart"},
+		{"sequence", "sequenceDiagram"},
+		{"SequenceDiagram", "sequenceDiagram"},
+		{"c4container", "C4Container"},
+		{"state", "stateDiagram-v2"},
+		{"graph", "graph"},
+	}
+	for _, tt := range tests {
+		source, err := mermaid.Template(tt.name)
+		if err != nil {
+			t.Errorf("Template(%q) error: %v", tt.name, err)
+			continue
+		}
+		if !strings.HasPrefix(source, tt.header) {
+			t.Errorf("Template(%q) = %q, want a %s diagram", tt.name, source, tt.header)
+		}
+	}
+
+	_, err := mermaid.Template("venn")
+	if err == nil || !strings.Contains(err.Error(), "available: ") || !strings.Contains(err.Error(), "flowchart, ") || !strings.Contains(err.Error(), "sequenceDiagram, ") {
+		t.Errorf("Template(venn) error = %v, want the available types", err)
+	}
+}
+
+func TestTemplate_FromExamples(t *testing.T) {
+	for name, source := range examples.All() {
+		if name == "state" {
+			// "state" names the stateDiagram-v2 template (see TestTemplate_Names)
+			continue
+		}
+		template, err := mermaid.Template(name)
+		if err != nil || template != source {
+			t.Errorf("Template(%q) = %q, %v, want the example", name, template, err)
+		}
+	}
+}
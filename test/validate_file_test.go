@@ -0,0 +1,421 @@
+package mermaid_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "# Doc\n\n```mermaid\nflowchart TD\n    A --> B\n```\n\n```mermaid\nclassDiagram\n    class Animal\n    class Animal\n```\n\n```mermaid\nnotADiagram\n```\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	if !results[0].Valid() || results[0].DiagramType != "flowchart" || results[0].LineOffset != 4 {
+		t.Errorf("unexpected first result: %+v", results[0])
+	}
+	if results[1].BlockIndex != 1 || results[1].ParseError != nil || len(results[1].Errors) != 1 {
+		t.Errorf("expected one validation error in second result, got %+v", results[1])
+	}
+	if results[2].ParseError == nil || results[2].Valid() {
+		t.Errorf("expected parse error in third result, got %+v", results[2])
+	}
+	for _, r := range results {
+		if r.File != path {
+			t.Errorf("expected file %q, got %q", path, r.File)
+		}
+	}
+}
+
+func TestValidateFile_Mermaid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "diagram.mmd")
+	writeFile(t, path, "sequenceDiagram\n    Alice->>Bob: Hi\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].Valid() || results[0].DiagramType != "sequence" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[0].LineOffset != 1 || results[0].EndLine != 3 {
+		t.Errorf("unexpected line range L%d-L%d", results[0].LineOffset, results[0].EndLine)
+	}
+}
+
+func TestValidateFile_MultipleMermaidDiagrams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "diagrams.mmd")
+	writeFile(t, path, "flowchart TD\n    A --> B\n\nclassDiagram\n    class Animal\n    class Animal\n---\npie\n    \"a\" : 1\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	want := []struct {
+		diagramType      string
+		lineOffset, end  int
+		validationErrors int
+	}{
+		{"flowchart", 1, 2, 0},
+		{"class", 4, 6, 1},
+		{"pie", 8, 9, 0},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.BlockIndex != i || r.DiagramType != w.diagramType || r.LineOffset != w.lineOffset || r.EndLine != w.end || len(r.Errors) != w.validationErrors {
+			t.Errorf("result %d = %+v, want %+v", i, r, w)
+		}
+	}
+
+	diagrams, err := mermaid.ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	if len(diagrams) != 3 || diagrams[2].GetType() != "pie" {
+		t.Errorf("unexpected diagrams: %v", diagrams)
+	}
+}
+
+func TestValidateFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := mermaid.ValidateFile(filepath.Join(dir, "missing.mmd"), mermaid.ValidateOptions{}); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	txt := filepath.Join(dir, "notes.txt")
+	writeFile(t, txt, "flowchart TD\n    A --> B")
+	if _, err := mermaid.ValidateFile(txt, mermaid.ValidateOptions{}); err == nil {
+		t.Error("expected error for unsupported file type")
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.mmd"), "flowchart LR\n    A --> B")
+	writeFile(t, filepath.Join(dir, "docs", "b.md"), "```mermaid\npie\n    \"A\" : 1\n```\n")
+	writeFile(t, filepath.Join(dir, "docs", "empty.md"), "# Nothing here\n")
+	writeFile(t, filepath.Join(dir, "docs", "ignored.txt"), "flowchart LR\n    A --> B")
+	writeFile(t, filepath.Join(dir, ".git", "c.mmd"), "not mermaid")
+
+	results, err := mermaid.ValidatePath(dir, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidatePath() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if filepath.Base(results[0].File) != "a.mmd" || filepath.Base(results[1].File) != "b.md" {
+		t.Errorf("unexpected file order: %s, %s", results[0].File, results[1].File)
+	}
+	for _, r := range results {
+		if !r.Valid() {
+			t.Errorf("%s: unexpected failure %+v", r.File, r)
+		}
+	}
+
+	single, err := mermaid.ValidatePath(filepath.Join(dir, "a.mmd"), mermaid.ValidateOptions{})
+	if err != nil || len(single) != 1 {
+		t.Errorf("ValidatePath() on a file: got %d results, err %v", len(single), err)
+	}
+
+	if _, err := mermaid.ValidatePath(filepath.Join(dir, "nope"), mermaid.ValidateOptions{}); err == nil {
+		t.Error("expected error for missing root")
+	}
+}
+
+func TestValidatePath_SourceComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.mmd"), "flowchart LR\n    A --> B")
+	writeFile(t, filepath.Join(dir, "server", "server.go"), "package server\n\n// Server routes requests:\n//\n// ```mermaid\n// flowchart LR\n//     A --> B\n// ```\ntype Server struct{}\n")
+	writeFile(t, filepath.Join(dir, "web", "checkout.js"), "/**\n * ```mermaid\n * sequenceDiagram\n *     Client->>API: POST /orders\n * ```\n */\nexport function checkout() {}\n")
+	writeFile(t, filepath.Join(dir, "jobs", "sync.py"), "def sync():\n    \"\"\"Sync orders.\n\n    ```mermaid\n    flowchart TD\n        end\n    ```\n    \"\"\"\n")
+
+	results, err := mermaid.ValidatePath(dir, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidatePath() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("without SourceComments: got %d results, want 1: %+v", len(results), results)
+	}
+
+	results, err = mermaid.ValidatePath(dir, mermaid.ValidateOptions{SourceComments: true})
+	if err != nil {
+		t.Fatalf("ValidatePath() error = %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, fmt.Sprintf("%s:%d %s valid=%v", filepath.Base(r.File), r.LineOffset, r.DiagramType, r.Valid()))
+	}
+	want := []string{
+		"a.mmd:1 flowchart valid=true",
+		"sync.py:5 flowchart valid=false",
+		"server.go:6 flowchart valid=true",
+		"checkout.js:3 sequence valid=true",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %q, want %q", got, want)
+	}
+
+	// Source files given directly are validated without SourceComments
+	direct, err := mermaid.ValidateFile(filepath.Join(dir, "server", "server.go"), mermaid.ValidateOptions{})
+	if err != nil || len(direct) != 1 {
+		t.Errorf("ValidateFile() on a Go file: got %d results, err %v", len(direct), err)
+	}
+	diagrams, err := mermaid.ParseFile(filepath.Join(dir, "web", "checkout.js"))
+	if err != nil || len(diagrams) != 1 || diagrams[0].GetType() != "sequence" {
+		t.Errorf("ParseFile() on a JavaScript file = %v, %v", diagrams, err)
+	}
+}
+
+func TestParseFile_WindowsLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "windows.mmd")
+	writeFile(t, path, "\uFEFFflowchart TD\r\n    subgraph S\r\n        A --> B\r\n    end\r\n")
+
+	diagrams, err := mermaid.ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	if len(diagrams) != 1 || diagrams[0].GetType() != "flowchart" {
+		t.Errorf("ParseFile() = %v, want one flowchart", diagrams)
+	}
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].Valid() {
+		t.Errorf("ValidateFile() = %+v, want one valid result", results)
+	}
+}
+
+func TestValidateFile_RequiredAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doc.md")
+	writeFile(t, path, "```mermaid\nflowchart TD\n    %% @owner: platform-team\n    A --> B\n```\n\n```mermaid\nflowchart TD\n    C --> D\n```\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{RequiredAnnotations: []string{"owner"}})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !results[0].Valid() {
+		t.Errorf("expected annotated diagram to be valid, got %+v", results[0])
+	}
+	if len(results[1].Errors) != 1 {
+		t.Errorf("expected 1 missing annotation error, got %+v", results[1])
+	}
+}
+
+func TestValidateFile_LabelLengths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flow.mmd")
+	writeFile(t, path, "flowchart TD\n    A[Receive the order] --> B[Done]\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if !results[0].Valid() {
+		t.Errorf("expected no label-length errors by default, got %+v", results[0].Errors)
+	}
+
+	// Configured limits enable the rule even outside strict mode
+	results, err = mermaid.ValidateFile(path, mermaid.ValidateOptions{LabelLengths: map[string]int{"flowchart": 10}})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results[0].Errors) != 1 {
+		t.Errorf("expected 1 label-length error, got %+v", results[0].Errors)
+	}
+}
+
+func TestValidateFile_SelfLoops(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flow.mmd")
+	writeFile(t, path, "flowchart TD\n    A --> A\n")
+
+	results, err := mermaid.ValidateFile(path, mermaid.ValidateOptions{})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if !results[0].Valid() {
+		t.Errorf("expected no self-loop errors by default, got %+v", results[0].Errors)
+	}
+
+	results, err = mermaid.ValidateFile(path, mermaid.ValidateOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results[0].Errors) != 1 || results[0].Errors[0].Severity != validator.SeverityWarning {
+		t.Errorf("expected 1 self-loop warning in strict mode, got %+v", results[0].Errors)
+	}
+
+	// Configuring the rule enables it outside strict mode
+	rule := &validator.SelfLoops{Severities: map[string]validator.Severity{"flowchart": validator.SeverityError}}
+	results, err = mermaid.ValidateFile(path, mermaid.ValidateOptions{SelfLoops: rule})
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(results[0].Errors) != 1 || results[0].Errors[0].Severity != validator.SeverityError {
+		t.Errorf("expected 1 self-loop error, got %+v", results[0].Errors)
+	}
+}
+
+func TestValidateFile_PathRules(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "docs", "legacy", "flow.mmd")
+	current := filepath.Join(dir, "docs", "current", "flow.mmd")
+	source := "flowchart TD\n    A --> B\n    A --> B\n"
+	writeFile(t, legacy, source)
+	writeFile(t, current, source)
+
+	opts := mermaid.ValidateOptions{PathRules: []mermaid.PathRules{{
+		Paths:   []string{filepath.Join(dir, "docs", "legacy", "**")},
+		Disable: []string{"no-duplicate-links"},
+	}}}
+
+	results, err := mermaid.ValidatePath(filepath.Join(dir, "docs"), opts)
+	if err != nil {
+		t.Fatalf("ValidatePath() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, result := range results {
+		wantErrors := 1
+		if result.File == legacy {
+			wantErrors = 0
+		}
+		if len(result.Errors) != wantErrors {
+			t.Errorf("%s: expected %d errors, got %+v", result.File, wantErrors, result.Errors)
+		}
+		for _, err := range result.Errors {
+			if err.Rule != "no-duplicate-links" {
+				t.Errorf("%s: expected errors from no-duplicate-links, got %q", result.File, err.Rule)
+			}
+		}
+	}
+}
+
+func TestValidateSource_SortedErrors(t *testing.T) {
+	source := "flowchart TD\n    classDef b fill:#fff\n    classDef a fill:#fff\n    A --> B\n    C --> C\n    A --> B\n"
+	results, err := mermaid.ValidateSource("diagram.mmd", source, mermaid.ValidateOptions{Strict: true})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("ValidateSource() = %v, %v", results, err)
+	}
+	errors := results[0].Errors
+	if len(errors) < 3 {
+		t.Fatalf("expected several errors, got %v", errors)
+	}
+	for i := 1; i < len(errors); i++ {
+		prev, cur := errors[i-1], errors[i]
+		if prev.Line > cur.Line || (prev.Line == cur.Line && (prev.Column > cur.Column || (prev.Column == cur.Column && prev.Rule > cur.Rule))) {
+			t.Errorf("errors out of order: %+v before %+v", prev, cur)
+		}
+	}
+}
+
+func TestValidateSource_TypeOptions(t *testing.T) {
+	directions, err := validator.ConfigureRule("allowed-directions", validator.RuleOptions{"directions": []string{"LR"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict := true
+	opts := mermaid.ValidateOptions{
+		RequiredAnnotations: []string{"owner"},
+		Types: map[string]mermaid.TypeOptions{
+			"flowchart": {Strict: &strict, RequiredAnnotations: []string{}, ConfiguredRules: []validator.ConfigurableRule{directions}},
+		},
+	}
+
+	source := "```mermaid\ngraph TD\n    A --> A\n```\n\n```mermaid\nsequenceDiagram\n    A->>B: Hi\n```\n"
+	results, err := mermaid.ValidateSource("doc.md", source, opts)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("ValidateSource() = %v, %v", results, err)
+	}
+
+	rules := func(errors []validator.ValidationError) []string {
+		var names []string
+		for _, err := range errors {
+			names = append(names, err.Rule)
+		}
+		return names
+	}
+	if got := rules(results[0].Errors); !slices.Contains(got, "allowed-directions") || !slices.Contains(got, "self-loops") || slices.Contains(got, "required-annotations") {
+		t.Errorf("flowchart errors = %v, want allowed-directions and strict self-loops without required-annotations", got)
+	}
+	if got := rules(results[1].Errors); !reflect.DeepEqual(got, []string{"required-annotations"}) {
+		t.Errorf("sequence errors = %v, want only required-annotations", got)
+	}
+}
+
+func TestValidate_UnknownRenderer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "diagram.mmd")
+	source := "flowchart TD\n    A --> B\n"
+	writeFile(t, path, source)
+	opts := mermaid.ValidateOptions{Renderer: "githbu"}
+
+	if _, err := mermaid.ValidateFile(path, opts); err == nil {
+		t.Error("ValidateFile() accepted an unknown renderer")
+	}
+	if _, err := mermaid.ValidateSource(path, source, opts); err == nil {
+		t.Error("ValidateSource() accepted an unknown renderer")
+	}
+	if _, err := mermaid.ValidateSourceContext(context.Background(), path, source, opts); err == nil {
+		t.Error("ValidateSourceContext() accepted an unknown renderer")
+	}
+	if _, err := mermaid.ValidatePath(dir, opts); err == nil {
+		t.Error("ValidatePath() accepted an unknown renderer")
+	}
+	if _, err := mermaid.FixSource(source, opts); err == nil {
+		t.Error("FixSource() accepted an unknown renderer")
+	}
+
+	diagram, err := mermaid.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	errors := mermaid.ValidateWith(diagram, opts)
+	if len(errors) != 1 || errors[0].Severity != validator.SeverityError || !strings.Contains(errors[0].Message, `unknown renderer "githbu"`) {
+		t.Errorf("ValidateWith() = %v, want one unknown renderer error", errors)
+	}
+
+	opts.Renderer = validator.RendererNames()[0]
+	if _, err := mermaid.ValidatePath(dir, opts); err != nil {
+		t.Errorf("ValidatePath() with renderer %q error = %v", opts.Renderer, err)
+	}
+}
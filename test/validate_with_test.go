@@ -0,0 +1,148 @@
+package mermaid_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	mermaid "github.com/sammcj/mermaid-check"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestValidateWith(t *testing.T) {
+	// A self-loop and a repeated label, which only strict rules report
+	diagram, err := mermaid.Parse("flowchart TD\n    A[Same] --> A\n    B[Same] --> C\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	ruleNames := func(errors []validator.ValidationError) []string {
+		var names []string
+		for _, err := range errors {
+			if !slices.Contains(names, err.Rule) {
+				names = append(names, err.Rule)
+			}
+		}
+		slices.Sort(names)
+		return names
+	}
+	strict := ruleNames(mermaid.ValidateWith(diagram, mermaid.ValidateOptions{Level: mermaid.LevelStrict}))
+	if !slices.Contains(strict, "self-loops") {
+		t.Fatalf("strict rules = %v, want self-loops among them", strict)
+	}
+	if !reflect.DeepEqual(strict, ruleNames(mermaid.ValidateWith(diagram, mermaid.ValidateOptions{Strict: true}))) {
+		t.Error("Level LevelStrict and Strict select different rules")
+	}
+
+	tests := []struct {
+		name string
+		opts mermaid.ValidateOptions
+		want []string
+	}{
+		{
+			name: "only named rules",
+			opts: mermaid.ValidateOptions{Level: mermaid.LevelNone, Rules: []string{"self-loops"}},
+			want: []string{"self-loops"},
+		},
+		{
+			name: "nothing selected",
+			opts: mermaid.ValidateOptions{Level: mermaid.LevelNone},
+		},
+		{
+			name: "default level with an extra rule",
+			opts: mermaid.ValidateOptions{Rules: []string{"self-loops"}},
+			want: append(ruleNames(mermaid.ValidateWith(diagram, mermaid.ValidateOptions{})), "self-loops"),
+		},
+		{
+			name: "strict level with a rule disabled",
+			opts: mermaid.ValidateOptions{Level: mermaid.LevelStrict, Disable: []string{"self-loops"}},
+			want: slices.DeleteFunc(slices.Clone(strict), func(name string) bool { return name == "self-loops" }),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ruleNames(mermaid.ValidateWith(diagram, tt.opts))
+			slices.Sort(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWith_Severities(t *testing.T) {
+	diagram, err := mermaid.Parse("flowchart TD\n    A --> A\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	errors := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{
+		Level:      mermaid.LevelNone,
+		Rules:      []string{"self-loops"},
+		Severities: map[string]validator.Severity{"self-loops": validator.SeverityError},
+	})
+	if len(errors) != 1 || errors[0].Severity != validator.SeverityError {
+		t.Errorf("ValidateWith() = %v, want one self-loops error raised to error", errors)
+	}
+}
+
+func TestValidateWith_SkippedLines(t *testing.T) {
+	diagram, err := mermaid.Parse("flowchart TD\n    A --> B\n    B -> C\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if errors := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{}); len(errors) != 0 {
+		t.Errorf("errors outside strict mode = %v", errors)
+	}
+	errors := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{Strict: true})
+	if len(errors) != 1 || errors[0].Rule != "skipped-lines" || errors[0].Line != 3 {
+		t.Fatalf("errors = %v, want one skipped-lines finding on line 3", errors)
+	}
+	if errors := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{Strict: true, Disable: []string{"skipped-lines"}}); len(errors) != 0 {
+		t.Errorf("errors with skipped-lines disabled = %v", errors)
+	}
+}
+
+func TestValidateWith_ConfusableCharactersStrictOnly(t *testing.T) {
+	diagram, err := mermaid.Parse("sequenceDiagram\n    Alice->>Bob: It’s done\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if errors := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{}); len(errors) != 0 {
+		t.Errorf("errors outside strict mode = %v", errors)
+	}
+	errors := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{Strict: true})
+	if len(errors) != 1 || errors[0].Rule != "confusable-characters" {
+		t.Errorf("errors in strict mode = %v, want one confusable-characters warning", errors)
+	}
+}
+
+func TestValidate_MatchesValidateWith(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		rule   string
+	}{
+		{"self-loop", "flowchart LR\n A --> A\n", "self-loops"},
+		{"confusable character", "sequenceDiagram\n    Alice->>Bob: It’s done\n", "confusable-characters"},
+		{"mixed indentation", "flowchart TD\n    A --> B\n\tB --> C\n", "mixed-indentation"},
+		{"skipped line", "flowchart TD\n    A --> B\n    B -> C\n", "skipped-lines"},
+		{"unknown icon", "C4Context\n    Person(user, \"User\", $sprite=\"aws-sqs\")\n", "known-icons"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := mermaid.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			strict := mermaid.Validate(diagram, true)
+			if !slices.ContainsFunc(strict, func(err validator.ValidationError) bool { return err.Rule == tt.rule }) {
+				t.Errorf("Validate(strict) = %v, want a %s finding", strict, tt.rule)
+			}
+			if want := mermaid.ValidateWith(diagram, mermaid.ValidateOptions{Level: mermaid.LevelStrict}); !reflect.DeepEqual(strict, want) {
+				t.Errorf("Validate(strict) = %v, ValidateWith(LevelStrict) = %v", strict, want)
+			}
+			if got, want := mermaid.Validate(diagram, false), mermaid.ValidateWith(diagram, mermaid.ValidateOptions{}); !reflect.DeepEqual(got, want) {
+				t.Errorf("Validate() = %v, ValidateWith() = %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,574 @@
+package mermaid
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/export"
+	"github.com/sammcj/mermaid-check/extractor"
+	"github.com/sammcj/mermaid-check/internal/inpututil"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// ValidateOptions configures ValidateWith, ValidateFile and ValidatePath.
+type ValidateOptions struct {
+	// Strict enables the strict rule set for every diagram type, as Level
+	// LevelStrict does.
+	Strict bool
+	// Level selects the rule set each diagram type starts from.
+	Level Level
+	// Rules names rules to run in addition to those Level selects, taken
+	// from the strict rule set of each diagram type. Rules that need
+	// settings, such as required-annotations, also need those set.
+	Rules []string
+	// Disable names rules not to run.
+	Disable []string
+	// Severities overrides the severity of the errors reported by the named
+	// rules.
+	Severities map[string]validator.Severity
+	// RequiredAnnotations lists metadata annotation keys (`%% @key: value`)
+	// that every diagram must define.
+	RequiredAnnotations []string
+	// CheckReferences makes ValidatePath check cross-diagram references (see
+	// CheckReferences) across every file it validates.
+	CheckReferences bool
+	// CheckClassConsistency checks the class diagrams within each file
+	// against each other (see CheckClassConsistency).
+	CheckClassConsistency bool
+	// CheckSequenceConsistency checks the sequence diagrams within each file
+	// against the flowchart or C4 diagram nearest them (see
+	// CheckSequenceConsistency).
+	CheckSequenceConsistency bool
+	// SpellChecker, when set, enables the spell-check rule for flowchart and
+	// sequence diagram text.
+	SpellChecker validator.SpellChecker
+	// Terminology lists terms to flag in the text of every diagram type.
+	Terminology []validator.Term
+	// NamingConventions maps a diagram type (flowchart, sequence, class or
+	// state) to the pattern its identifiers must match; see
+	// validator.NamingPattern.
+	NamingConventions map[string]*regexp.Regexp
+	// LabelLengths overrides the label-length rule's limits per diagram type
+	// ("flowchart" or "sequence") and enables the rule outside strict mode.
+	LabelLengths map[string]int
+	// SelfLoops configures the self-loops rule and enables it outside strict
+	// mode. Strict mode without it uses validator.DefaultSelfLoopSeverities.
+	SelfLoops *validator.SelfLoops
+	// Icons configures the known-icons rule's catalogue and enables it
+	// outside strict mode. Strict mode without it uses validator.DefaultIcons.
+	Icons *validator.KnownIcons
+	// Indentation, when set, enables the indent-style rule with the given
+	// indentation per level.
+	Indentation *validator.IndentStyle
+	// ParticipantsDeclaredFirst enables the participants-declared-first rule,
+	// which requires sequence diagrams to declare every participant before
+	// the first message.
+	ParticipantsDeclaredFirst bool
+	// SQLDialect, when set, enables the er-sql-types rule, which requires
+	// every ER attribute type to map to a column type in the dialect (see
+	// export.SQL).
+	SQLDialect export.Dialect
+	// Renderer, when set, names the renderer diagrams are published with
+	// (see validator.RendererNames), enabling the renderer-compatibility
+	// rule, which reports constructs that renderer can't display.
+	Renderer string
+	// ConfiguredRules are rules given options (see validator.ConfigureRule).
+	// Each replaces the rule of the same name in the rule sets it belongs to,
+	// or is added to them, so configuring a rule enables it.
+	ConfiguredRules []validator.ConfigurableRule
+	// Types overrides these options for diagrams of one kind, keyed by
+	// validator.DiagramKind (see ForDiagram).
+	Types map[string]TypeOptions
+	// PathRules disables rules for files matching path globs, so that
+	// existing documents can be brought into line gradually.
+	PathRules []PathRules
+	// Baseline, when set, hides the errors it records so that only new ones
+	// are reported. A hidden parse error leaves both Result.ParseError and
+	// Result.Diagram nil.
+	Baseline *Baseline
+	// FenceLanguages are the markdown code fence languages treated as
+	// Mermaid; extractor.DefaultFenceLanguages when empty.
+	FenceLanguages []string
+	// SourceComments makes ValidatePath also validate the diagrams in the
+	// comments of the Go, JavaScript, TypeScript and Python files it finds
+	// in directories (see extractor.ExtractFromComments). Source files given
+	// directly are always validated.
+	SourceComments bool
+	// Profile records parse and validation times for each diagram, and for
+	// each rule, in Result.Metrics.
+	Profile bool
+	// Limits, when set, bounds the size and complexity of each diagram (see
+	// parser.ParseWithLimits); diagrams exceeding them get a parse error.
+	// Set it to parser.DefaultLimits() when validating untrusted input.
+	Limits *parser.Limits
+}
+
+// TypeOptions override ValidateOptions for one kind of diagram.
+type TypeOptions struct {
+	// Strict, when set, replaces ValidateOptions.Strict.
+	Strict *bool
+	// RequiredAnnotations, when not nil, replaces
+	// ValidateOptions.RequiredAnnotations.
+	RequiredAnnotations []string
+	// ConfiguredRules replace the configured rules of the same name, or are
+	// added to them.
+	ConfiguredRules []validator.ConfigurableRule
+}
+
+// ForDiagram returns the options for diagram: o with the TypeOptions for the
+// diagram's kind applied.
+func (o ValidateOptions) ForDiagram(diagram ast.Diagram) ValidateOptions {
+	typeOptions, ok := o.Types[validator.DiagramKind(diagram)]
+	if !ok {
+		return o
+	}
+	if typeOptions.Strict != nil {
+		o.Strict = *typeOptions.Strict
+	}
+	if typeOptions.RequiredAnnotations != nil {
+		o.RequiredAnnotations = typeOptions.RequiredAnnotations
+	}
+	o.ConfiguredRules = validator.WithConfiguredRules(o.ConfiguredRules, typeOptions.ConfiguredRules)
+	return o
+}
+
+// Level is a preset rule set that ValidateOptions starts from.
+type Level int
+
+const (
+	// LevelDefault runs the default rules of each diagram type.
+	LevelDefault Level = iota
+	// LevelStrict runs the strict rules of each diagram type.
+	LevelStrict
+	// LevelNone runs only the rules named in ValidateOptions.Rules and the
+	// configured rules.
+	LevelNone
+)
+
+// PathRules disables rules for the files matching Paths.
+type PathRules struct {
+	// Paths are glob patterns resolved against the working directory, in
+	// which a "**" segment matches any number of directories.
+	Paths []string
+	// Disable lists the names of the rules (see validator.RuleName) that are
+	// not applied to matching files.
+	Disable []string
+}
+
+// check reports options that can't be applied: a Renderer that names no
+// known renderer.
+func (o ValidateOptions) check() error {
+	if o.Renderer != "" {
+		if _, err := validator.ForRenderer(o.Renderer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DisabledRules returns the names of the rules PathRules disables for the
+// file at path.
+func (o ValidateOptions) DisabledRules(path string) []string {
+	var disabled []string
+	for _, rules := range o.PathRules {
+		for _, pattern := range rules.Paths {
+			if inpututil.MatchPath(pattern, path) {
+				disabled = append(disabled, rules.Disable...)
+				break
+			}
+		}
+	}
+	return disabled
+}
+
+// Result is the outcome of validating a single diagram within a file.
+type Result struct {
+	File         string                      // Path of the file containing the diagram
+	BlockIndex   int                         // Index of the diagram within the file (0-based)
+	DiagramType  string                      // Detected diagram type
+	LineOffset   int                         // Line in File where the diagram source starts (1-indexed)
+	EndLine      int                         // Line in File where the diagram source ends (1-indexed)
+	Source       string                      // Diagram source, as parsed
+	ParseError   error                       // Set when the diagram could not be parsed, unless the baseline hides it
+	Suggestion   string                      // "Did you mean" hint for ParseError, if any
+	Errors       []validator.ValidationError // Validation errors (line numbers are relative to the diagram)
+	Diagram      ast.Diagram                 // Parsed diagram (nil when ParseError is set)
+	Metadata     map[string]string           // Frontmatter and annotation metadata, available even if parsing failed
+	Attributes   map[string]string           // Code fence attributes of a markdown block (see extractor.ParseFenceAttributes)
+	LinePrefixes []string                    // Comment markers removed from each line of a diagram in source code comments
+	Metrics      *Metrics                    // Parse and validation times, when ValidateOptions.Profile is set
+}
+
+// Valid reports whether the diagram parsed and produced no validation errors.
+func (r *Result) Valid() bool {
+	return r.ParseError == nil && len(r.Errors) == 0
+}
+
+// ValidateFile parses and validates every Mermaid diagram in the file at path.
+// File types are detected in the same way as ParseFile. A diagram that fails to
+// parse is reported through Result.ParseError rather than aborting the file, so
+// the remaining diagrams are still validated. An error is returned only when the
+// file cannot be read, has an unsupported type, or its markdown cannot be
+// scanned, or when opts names an unknown renderer.
+func ValidateFile(path string, opts ValidateOptions) ([]Result, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // User-provided file path is intentional
+	if err != nil {
+		return nil, err
+	}
+	return ValidateSource(path, string(data), opts)
+}
+
+// ValidateSource validates content as if it had been read from the file at
+// path, without touching the file system: path selects the file type and is
+// matched against opts.PathRules and opts.Baseline.
+func ValidateSource(path, content string, opts ValidateOptions) ([]Result, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	blocks, err := fileBlocks(path, content, opts.FenceLanguages)
+	if err != nil {
+		return nil, err
+	}
+	return validateBlocks(path, blocks, opts), nil
+}
+
+// validateBlocks parses and validates each block of the file at path.
+func validateBlocks(path string, blocks []extractor.DiagramBlock, opts ValidateOptions) []Result {
+	results, _ := validateBlocksContext(context.Background(), path, blocks, opts)
+	return results
+}
+
+// validateBlocksContext is validateBlocks, returning ctx.Err() as soon as ctx
+// is done between diagrams.
+func validateBlocksContext(ctx context.Context, path string, blocks []extractor.DiagramBlock, opts ValidateOptions) ([]Result, error) {
+	disabled := opts.DisabledRules(path)
+	var known *FileBaseline
+	if opts.Baseline != nil {
+		known = opts.Baseline.ForFile(path)
+	}
+	results := make([]Result, 0, len(blocks))
+	for i, block := range blocks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := Result{
+			File:         path,
+			BlockIndex:   i,
+			DiagramType:  block.DiagramType,
+			LineOffset:   block.LineOffset,
+			EndLine:      block.EndLine,
+			Source:       block.Source,
+			Metadata:     parser.ExtractMetadata(block.Source),
+			Attributes:   block.Attributes,
+			LinePrefixes: block.LinePrefixes,
+		}
+
+		diagram, metrics, err := parseBlock(block.Source, opts.Limits)
+		if opts.Profile {
+			result.Metrics = &metrics
+		}
+		if err != nil {
+			result.ParseError = err
+			result.Suggestion = parser.Suggest(block.Source, err)
+		} else {
+			result.Diagram = diagram
+			result.DiagramType = diagram.GetType()
+			if opts.Profile {
+				var validation Metrics
+				result.Errors, validation = profileWithOptions(diagram, opts)
+				result.Metrics.Validate, result.Metrics.Rules = validation.Validate, validation.Rules
+			} else {
+				result.Errors = validateWithOptions(diagram, opts)
+			}
+		}
+		results = append(results, result)
+	}
+
+	if opts.CheckClassConsistency {
+		addReferenceErrors(results, CheckClassConsistency(results))
+	}
+	if opts.CheckSequenceConsistency {
+		addReferenceErrors(results, CheckSequenceConsistency(results))
+	}
+
+	for i := range results {
+		r := &results[i]
+		if r.Diagram == nil {
+			if known != nil && r.ParseError != nil && known.FilterParseError(r.Source, r.ParseError) == nil {
+				r.ParseError, r.Suggestion = nil, ""
+			}
+			continue
+		}
+		r.Errors = validator.WithoutRules(r.Errors, disabled...)
+		if known != nil {
+			r.Errors = known.Filter(r.Diagram, r.Errors)
+		}
+	}
+
+	return results, nil
+}
+
+// parseBlock parses source like ParseWithMetrics, within limits when they
+// are set.
+func parseBlock(source string, limits *parser.Limits) (ast.Diagram, Metrics, error) {
+	if limits == nil {
+		return ParseWithMetrics(source)
+	}
+	start := time.Now()
+	diagram, err := ParseWithLimits(source, *limits)
+	return diagram, Metrics{Parse: time.Since(start)}, err
+}
+
+// ValidatePath validates root, which may be a single file or a directory.
+// Directories are walked recursively and every Mermaid or markdown file, and
+// with opts.SourceComments every source file whose comments can hold diagrams,
+// is validated with ValidateFile; hidden directories (such as .git) are
+// skipped.
+// Results are returned in lexical file order. When opts.CheckReferences is set,
+// cross-diagram reference errors are added to the Result they occur in.
+func ValidatePath(root string, opts ValidateOptions) ([]Result, error) {
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	if info.IsDir() {
+		results, err = validateDir(root, opts)
+	} else {
+		results, err = ValidateFile(root, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CheckReferences {
+		addReferenceErrors(results, CheckReferences(results))
+	}
+
+	return results, nil
+}
+
+// validateDir validates every Mermaid or markdown file under root, and source
+// files when opts.SourceComments is set.
+func validateDir(root string, opts ValidateOptions) ([]Result, error) {
+	var results []Result
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if inpututil.DetectFileType(path) == inpututil.FileTypeUnknown {
+			if _, ok := extractor.CommentSyntaxFor(path); !ok || !opts.SourceComments {
+				return nil
+			}
+		}
+
+		fileResults, err := ValidateFile(path, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		results = append(results, fileResults...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// validateWithOptions applies the rules selected by opts to diagram,
+// returning the errors deduplicated and sorted as Validate returns them.
+func validateWithOptions(diagram ast.Diagram, opts ValidateOptions) []validator.ValidationError {
+	errors, _ := selectRules(opts.ForDiagram(diagram), func(opts ValidateOptions) ([]validator.ValidationError, Metrics) {
+		errors := validateRuleSet(diagram, opts)
+		return append(errors, validator.ValidateDiagramRules(diagram, diagramRules(opts)...)...), Metrics{}
+	})
+	return errors
+}
+
+// profileWithOptions is validateWithOptions, timing each rule.
+func profileWithOptions(diagram ast.Diagram, opts ValidateOptions) ([]validator.ValidationError, Metrics) {
+	return selectRules(opts.ForDiagram(diagram), func(opts ValidateOptions) ([]validator.ValidationError, Metrics) {
+		return profileRules(append(typeRules(diagram, opts), diagramRuleRunners(diagram, diagramRules(opts))...))
+	})
+}
+
+// selectRules runs validate with the rule set of opts.Level, then with the
+// strict rule set for the rules named in opts.Rules, keeping only their
+// errors. It drops the errors of opts.Disable, applies opts.Severities and
+// returns the errors deduplicated and sorted, with the metrics of both runs.
+func selectRules(opts ValidateOptions, validate func(ValidateOptions) ([]validator.ValidationError, Metrics)) ([]validator.ValidationError, Metrics) {
+	opts.Strict = opts.Strict || opts.Level == LevelStrict
+	var errors []validator.ValidationError
+	var metrics Metrics
+	if opts.Level != LevelNone {
+		errors, metrics = validate(opts)
+	}
+
+	named := slices.Clone(opts.Rules)
+	if opts.Level == LevelNone {
+		for _, rule := range opts.ConfiguredRules {
+			named = append(named, rule.Name())
+		}
+	}
+	if len(named) > 0 && (!opts.Strict || opts.Level == LevelNone) {
+		strict := opts
+		strict.Strict = true
+		strictErrors, strictMetrics := validate(strict)
+		for _, err := range strictErrors {
+			if slices.Contains(named, err.Rule) {
+				errors = append(errors, err)
+			}
+		}
+		metrics.Validate += strictMetrics.Validate
+		metrics.Rules = append(metrics.Rules, strictMetrics.Rules...)
+	}
+
+	errors = validator.WithoutRules(validator.Deduplicate(errors), opts.Disable...)
+	for i := range errors {
+		if severity, ok := opts.Severities[errors[i].Rule]; ok {
+			errors[i].Severity = severity
+		}
+	}
+	validator.SortErrors(errors)
+	return errors, metrics
+}
+
+// diagramRules returns the rules opts enables for every diagram type.
+func diagramRules(opts ValidateOptions) []validator.DiagramRule {
+	var rules []validator.DiagramRule
+	if len(opts.RequiredAnnotations) > 0 {
+		rules = append(rules, &validator.RequiredAnnotations{Keys: opts.RequiredAnnotations})
+	}
+	if opts.SpellChecker != nil {
+		rules = append(rules, &validator.SpellCheck{Checker: opts.SpellChecker})
+	}
+	if len(opts.Terminology) > 0 {
+		rules = append(rules, &validator.Terminology{Terms: opts.Terminology})
+	}
+	if len(opts.NamingConventions) > 0 {
+		rules = append(rules, &validator.NamingConventions{Patterns: opts.NamingConventions})
+	}
+	if opts.SelfLoops != nil {
+		rules = append(rules, opts.SelfLoops)
+	} else if opts.Strict {
+		rules = append(rules, &validator.SelfLoops{})
+	}
+	if opts.Icons != nil {
+		rules = append(rules, opts.Icons)
+	} else if opts.Strict {
+		rules = append(rules, &validator.KnownIcons{})
+	}
+	if opts.Strict {
+		rules = append(rules, &validator.ConfusableCharacters{}, &validator.MixedIndentation{}, &validator.SkippedLines{})
+	}
+	if opts.Indentation != nil {
+		rules = append(rules, opts.Indentation)
+	}
+	if opts.ParticipantsDeclaredFirst {
+		rules = append(rules, &validator.ParticipantsDeclaredFirst{})
+	}
+	if opts.SQLDialect != "" {
+		rules = append(rules, &validator.ERSQLTypes{Dialect: opts.SQLDialect})
+	}
+	if opts.Renderer != "" {
+		// The entry points reject unknown renderers (see ValidateOptions.check)
+		rendererRules, _ := validator.ForRenderer(opts.Renderer)
+		rules = append(rules, rendererRules...)
+	}
+	return validator.WithConfiguredRules(rules, opts.ConfiguredRules)
+}
+
+// validateRuleSet applies the default or strict rule set to diagram, with any
+// configured label length limits (which enable the label-length rule outside
+// strict mode too) and configured rules.
+func validateRuleSet(diagram ast.Diagram, opts ValidateOptions) []validator.ValidationError {
+	if len(opts.LabelLengths) == 0 && len(opts.ConfiguredRules) == 0 {
+		return validateByType(diagram, opts.Strict)
+	}
+
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		rules := validator.DefaultRules()
+		if opts.Strict {
+			rules = validator.StrictRules()
+		}
+		return validator.New(withRuleOptions(rules, opts)...).Validate(d)
+	case *ast.SequenceDiagram:
+		rules := validator.SequenceDefaultRules()
+		if opts.Strict {
+			rules = validator.SequenceStrictRules()
+		}
+		return validator.NewSequence(withRuleOptions(rules, opts)...).ValidateDiagram(d)
+	default:
+		return validateByType(diagram, opts.Strict)
+	}
+}
+
+// withRuleOptions applies the label length limits and configured rules of
+// opts to a rule set.
+func withRuleOptions[R any](rules []R, opts ValidateOptions) []R {
+	if len(opts.LabelLengths) > 0 {
+		rules = validator.WithLabelLengths(rules, opts.LabelLengths)
+	}
+	return validator.WithConfiguredRules(rules, opts.ConfiguredRules)
+}
+
+// fileBlocks splits file content into diagram blocks according to its file type.
+// Raw Mermaid files produce a block per diagram (see extractor.SplitMermaid),
+// markdown a block per code fence labelled with one of languages, and source
+// code a block per such fence in its comments (see
+// extractor.ExtractFromComments).
+func fileBlocks(path, content string, languages []string) ([]extractor.DiagramBlock, error) {
+	content = inpututil.NormaliseNewlines(content)
+	fileType := inpututil.DetectFileType(path)
+
+	// Check if .mmd file contains markdown code fences
+	if fileType == inpututil.FileTypeMermaid && containsMarkdownFences(content) {
+		fileType = inpututil.FileTypeMarkdown
+	}
+
+	switch fileType {
+	case inpututil.FileTypeMermaid:
+		return sourceBlocks(content, false, nil)
+	case inpututil.FileTypeMarkdown:
+		return sourceBlocks(content, true, languages)
+	}
+	if syntax, ok := extractor.CommentSyntaxFor(path); ok {
+		return extractor.ExtractFromComments(content, syntax, languages...)
+	}
+	return nil, fmt.Errorf("unsupported file type for %s", path)
+}
+
+// sourceBlocks splits normalised content into diagram blocks, extracting them
+// from markdown code fences labelled with one of languages or splitting raw
+// Mermaid content into its diagrams.
+func sourceBlocks(content string, markdown bool, languages []string) ([]extractor.DiagramBlock, error) {
+	if markdown {
+		return extractor.ExtractFromMarkdownLanguages(content, languages...)
+	}
+	// The diagram type is filled in from the parsed diagram
+	return extractor.SplitMermaid(content), nil
+}
@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// Unicode directional isolates, which lay out the text between an opening
+// isolate and PDI on its own, in the given direction.
+const (
+	leftToRightIsolate    = '\u2066'
+	rightToLeftIsolate    = '\u2067'
+	firstStrongIsolate    = '\u2068'
+	popDirectionalIsolate = '\u2069'
+)
+
+// rightToLeftScripts are the scripts written right to left.
+var rightToLeftScripts = []*unicode.RangeTable{
+	unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko,
+	unicode.Samaritan, unicode.Mandaic, unicode.Adlam,
+}
+
+// textDirection is the direction of a character: that of its script for
+// letters, and none for digits, punctuation and spaces.
+type textDirection int
+
+const (
+	neutralDirection textDirection = iota
+	leftToRight
+	rightToLeft
+)
+
+func runeDirection(r rune) textDirection {
+	switch {
+	case unicode.In(r, rightToLeftScripts...):
+		return rightToLeft
+	case unicode.IsLetter(r):
+		return leftToRight
+	}
+	return neutralDirection
+}
+
+// MixedDirectionText warns about flowchart labels that mix right-to-left
+// scripts such as Arabic or Hebrew with left-to-right ones without Unicode
+// directional isolates, which Mermaid can display with the words out of
+// order. The fix wraps each run written against the label's direction (set by
+// its first letter) in isolates.
+type MixedDirectionText struct{}
+
+// Name returns the name of this validation rule.
+func (r *MixedDirectionText) Name() string { return "mixed-direction-text" }
+
+// Validate checks flowchart node, edge and subgraph labels.
+func (r *MixedDirectionText) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var fields []textField
+	collectFlowchartText(flowchart.Statements, &fields)
+	lines := strings.Split(flowchart.Source, "\n")
+
+	var errors []ValidationError
+	for _, field := range fields {
+		if strings.ContainsAny(field.text, string([]rune{leftToRightIsolate, rightToLeftIsolate, firstStrongIsolate})) {
+			continue
+		}
+		isolated, mixed := isolateDirectionRuns(field.text)
+		if !mixed {
+			continue
+		}
+		err := ValidationError{
+			Line:       field.line,
+			Column:     textColumn(lines, field.line, field.text),
+			Message:    "label mixes right-to-left and left-to-right text without directional isolates, so it may display out of order",
+			Severity:   SeverityWarning,
+			Suggestion: "wrap the text written in the other direction in U+2066 or U+2067 and U+2069 isolates",
+		}
+		if line := sourceLine(flowchart.Source, field.line); strings.Count(line, field.text) == 1 {
+			err.Fix = &Fix{Line: field.line, Replacement: strings.Replace(line, field.text, isolated, 1)}
+		}
+		errors = append(errors, err)
+	}
+	return errors
+}
+
+// isolateDirectionRuns returns text with each run of letters written against
+// its direction wrapped in isolates, and whether there were any. A run extends
+// from its first letter to its last before the next letter in the text's own
+// direction, taking in the spaces and punctuation between.
+func isolateDirectionRuns(text string) (string, bool) {
+	runes := []rune(text)
+	base := neutralDirection
+	for _, r := range runes {
+		if base = runeDirection(r); base != neutralDirection {
+			break
+		}
+	}
+
+	var b strings.Builder
+	mixed := false
+	for i := 0; i < len(runes); {
+		direction := runeDirection(runes[i])
+		if direction == neutralDirection || direction == base {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		end := i
+		for j := i; j < len(runes) && runeDirection(runes[j]) != base; j++ {
+			if runeDirection(runes[j]) != neutralDirection {
+				end = j
+			}
+		}
+		isolate := rune(leftToRightIsolate)
+		if direction == rightToLeft {
+			isolate = rightToLeftIsolate
+		}
+		b.WriteRune(isolate)
+		b.WriteString(string(runes[i : end+1]))
+		b.WriteRune(popDirectionalIsolate)
+		mixed = true
+		i = end + 1
+	}
+	return b.String(), mixed
+}
@@ -15,7 +15,7 @@ type C4Rule interface {
 func ValidateC4(d *ast.C4Diagram, rules []C4Rule) []ValidationError {
 	var errors []ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(d)...)
+		errors = append(errors, withRule(rule.Validate(d), rule)...)
 	}
 	return errors
 }
@@ -27,12 +27,14 @@ func DefaultC4Rules() []C4Rule {
 		&C4ValidRelationshipReferencesRule{},
 		&ValidBoundaryIDsRule{},
 		&ValidStyleReferencesRule{},
+		&C4NoDuplicateRelationshipsRule{},
 	}
 }
 
 // StrictC4Rules returns strict validation rules for C4 diagrams.
 func StrictC4Rules() []C4Rule {
-	return DefaultC4Rules()
+	rules := append(DefaultC4Rules(), &C4ColourContrastRule{}, &C4RelationshipOrderRule{}, &C4RelationshipDirectionRule{})
+	return append(rules, C4SemanticRules()...)
 }
 
 // NoDuplicateElementIDsRule checks that all element IDs are unique.
@@ -118,6 +120,91 @@ func (r *C4ValidRelationshipReferencesRule) Validate(d *ast.C4Diagram) []Validat
 	return errors
 }
 
+// C4RelationshipOrderRule checks that relationships are declared after both of
+// their endpoints. Mermaid lays elements out in declaration order, so a forward
+// reference is legal but usually a sign the diagram will read out of order.
+type C4RelationshipOrderRule struct{}
+
+// Validate reports relationships that reference an element declared later in
+// the source. Undefined elements are left to C4ValidRelationshipReferencesRule.
+func (r *C4RelationshipOrderRule) Validate(d *ast.C4Diagram) []ValidationError {
+	declared := make(map[string]ast.Position)
+	for _, elem := range d.Elements {
+		declared[elem.ID] = elem.Pos
+	}
+	collectBoundaryPositions(d.Boundaries, declared)
+
+	var errors []ValidationError
+	for _, rel := range d.Relationships {
+		for _, id := range []string{rel.From, rel.To} {
+			pos, ok := declared[id]
+			if !ok || pos.Line < rel.Pos.Line {
+				continue
+			}
+			errors = append(errors, ValidationError{
+				Line:     rel.Pos.Line,
+				Column:   rel.Pos.Column,
+				Message:  fmt.Sprintf("relationship references element '%s' before it is defined on line %d", id, pos.Line),
+				Severity: SeverityWarning,
+			})
+			if rel.From == rel.To {
+				break
+			}
+		}
+	}
+
+	return errors
+}
+
+// C4RelationshipDirectionRule checks for relationships between the same two
+// elements with the same label that run in opposite directions: Rel(a, b) and
+// Rel(b, a), or a BiRel repeated in reverse as another BiRel or a one-way
+// relationship. These usually come from copying a relationship and editing
+// only one end. Relationships repeated in the same direction, whatever their
+// type, are left to C4NoDuplicateRelationshipsRule.
+type C4RelationshipDirectionRule struct{}
+
+// Validate reports each relationship that contradicts or repeats an earlier
+// one between the same elements in another direction.
+func (r *C4RelationshipDirectionRule) Validate(d *ast.C4Diagram) []ValidationError {
+	var errors []ValidationError
+	for i, rel := range d.Relationships {
+		if rel.From == rel.To {
+			continue
+		}
+		for _, earlier := range d.Relationships[:i] {
+			if earlier.Label != rel.Label || earlier.From != rel.To || earlier.To != rel.From {
+				continue
+			}
+			message := fmt.Sprintf("relationship from '%s' to '%s' contradicts the one from '%s' to '%s' with the same label on line %d", rel.From, rel.To, earlier.From, earlier.To, earlier.Pos.Line)
+			if earlier.RelType == "BiRel" || rel.RelType == "BiRel" {
+				message = fmt.Sprintf("%s between '%s' and '%s' repeats the %s with the same label on line %d", rel.RelType, rel.From, rel.To, earlier.RelType, earlier.Pos.Line)
+			}
+			errors = append(errors, ValidationError{
+				Line:       rel.Pos.Line,
+				Column:     rel.Pos.Column,
+				Message:    message,
+				Severity:   SeverityWarning,
+				Suggestion: "keep one relationship, using BiRel if it runs both ways",
+			})
+			break
+		}
+	}
+	return errors
+}
+
+// collectBoundaryPositions recursively records where each boundary and
+// element ID is declared.
+func collectBoundaryPositions(boundaries []ast.C4Boundary, declared map[string]ast.Position) {
+	for _, boundary := range boundaries {
+		declared[boundary.ID] = boundary.Pos
+		for _, elem := range boundary.Elements {
+			declared[elem.ID] = elem.Pos
+		}
+		collectBoundaryPositions(boundary.Boundaries, declared)
+	}
+}
+
 // collectBoundaryIDs recursively collects all element IDs from boundaries.
 func collectBoundaryIDs(boundaries []ast.C4Boundary, validIDs map[string]bool) {
 	for _, boundary := range boundaries {
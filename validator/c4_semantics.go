@@ -0,0 +1,160 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// c4StepPattern matches a dynamic diagram label that starts with a step
+// number, such as "1. Login" or "2.1: Verify".
+var c4StepPattern = regexp.MustCompile(`^\s*\d+(?:\.\d+)*[.:)]?\s`)
+
+// C4SemanticRules returns rules checking that elements suit the kind of C4
+// diagram they appear in. They are included in StrictC4Rules.
+func C4SemanticRules() []C4Rule {
+	return []C4Rule{
+		&C4ContainerPlacementRule{},
+		&C4ComponentPlacementRule{},
+		&C4DeploymentNodePlacementRule{},
+		&C4DynamicNumberingRule{},
+	}
+}
+
+// C4ContainerPlacementRule checks that the containers of a C4Container diagram
+// sit inside the system they belong to. A System_Boundary or a
+// Container_Boundary (which the Mermaid documentation uses for the same
+// purpose) counts; external containers are exempt.
+type C4ContainerPlacementRule struct{}
+
+// Validate reports containers outside a system boundary in C4Container diagrams.
+func (r *C4ContainerPlacementRule) Validate(d *ast.C4Diagram) []ValidationError {
+	if d.DiagramType != "c4Container" {
+		return nil
+	}
+	return checkC4Placement(d, "Container", "system boundary", "System_Boundary", "Container_Boundary")
+}
+
+// C4ComponentPlacementRule checks that the components of a C4Component diagram
+// sit inside the Container_Boundary of the container they belong to. External
+// components are exempt.
+type C4ComponentPlacementRule struct{}
+
+// Validate reports components outside a container boundary in C4Component diagrams.
+func (r *C4ComponentPlacementRule) Validate(d *ast.C4Diagram) []ValidationError {
+	if d.DiagramType != "c4Component" {
+		return nil
+	}
+	return checkC4Placement(d, "Component", "Container_Boundary", "Container_Boundary")
+}
+
+// checkC4Placement reports non-external elements of elementType that have no
+// enclosing boundary of one of the allowed types.
+func checkC4Placement(d *ast.C4Diagram, elementType, want string, allowed ...string) []ValidationError {
+	var errors []ValidationError
+	report := func(elem ast.C4Element) {
+		if elem.ElementType != elementType || elem.External {
+			return
+		}
+		errors = append(errors, ValidationError{
+			Line:     elem.Pos.Line,
+			Column:   elem.Pos.Column,
+			Message:  fmt.Sprintf("%s '%s' should be inside a %s in a %s diagram", elementType, elem.ID, want, d.DiagramType),
+			Severity: SeverityWarning,
+		})
+	}
+
+	for _, elem := range d.Elements {
+		report(elem)
+	}
+
+	var walk func(boundaries []ast.C4Boundary, enclosed bool)
+	walk = func(boundaries []ast.C4Boundary, enclosed bool) {
+		for _, boundary := range boundaries {
+			inside := enclosed
+			for _, boundaryType := range allowed {
+				if boundary.BoundaryType == boundaryType {
+					inside = true
+				}
+			}
+			if !inside {
+				for _, elem := range boundary.Elements {
+					report(elem)
+				}
+			}
+			walk(boundary.Boundaries, inside)
+		}
+	}
+	walk(d.Boundaries, false)
+
+	return errors
+}
+
+// C4DeploymentNodePlacementRule checks that deployment nodes only appear in
+// C4Deployment diagrams.
+type C4DeploymentNodePlacementRule struct{}
+
+// Validate reports Deployment_Node and Node boundaries or elements outside
+// C4Deployment diagrams.
+func (r *C4DeploymentNodePlacementRule) Validate(d *ast.C4Diagram) []ValidationError {
+	if d.DiagramType == "c4Deployment" {
+		return nil
+	}
+
+	var errors []ValidationError
+	report := func(id string, pos ast.Position) {
+		errors = append(errors, ValidationError{
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Message:  fmt.Sprintf("deployment node '%s' is only meaningful in a c4Deployment diagram, not %s", id, d.DiagramType),
+			Severity: SeverityWarning,
+		})
+	}
+
+	var walk func(elements []ast.C4Element, boundaries []ast.C4Boundary)
+	walk = func(elements []ast.C4Element, boundaries []ast.C4Boundary) {
+		for _, elem := range elements {
+			if elem.ElementType == "Node" {
+				report(elem.ID, elem.Pos)
+			}
+		}
+		for _, boundary := range boundaries {
+			switch boundary.BoundaryType {
+			case "Deployment_Node", "Node", "Node_L", "Node_R":
+				report(boundary.ID, boundary.Pos)
+			}
+			walk(boundary.Elements, boundary.Boundaries)
+		}
+	}
+	walk(d.Elements, d.Boundaries)
+
+	return errors
+}
+
+// C4DynamicNumberingRule checks that every relationship in a C4Dynamic diagram
+// is numbered, either with RelIndex or a step number at the start of its label,
+// so the order of interactions is explicit.
+type C4DynamicNumberingRule struct{}
+
+// Validate reports unnumbered relationships in C4Dynamic diagrams.
+func (r *C4DynamicNumberingRule) Validate(d *ast.C4Diagram) []ValidationError {
+	if d.DiagramType != "c4Dynamic" {
+		return nil
+	}
+
+	var errors []ValidationError
+	for _, rel := range d.Relationships {
+		if rel.RelType == "RelIndex" || c4StepPattern.MatchString(rel.Label) {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:     rel.Pos.Line,
+			Column:   rel.Pos.Column,
+			Message:  fmt.Sprintf("dynamic diagram relationship '%s' is not numbered; start the label with a step number or use RelIndex", rel.Label),
+			Severity: SeverityWarning,
+		})
+	}
+
+	return errors
+}
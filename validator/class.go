@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -28,12 +29,17 @@ func (r *NoDuplicateClasses) ValidateClass(diagram *ast.ClassDiagram) []Validati
 	for _, stmt := range diagram.Statements {
 		if class, ok := stmt.(*ast.Class); ok {
 			if pos, exists := seen[class.Name]; exists {
-				errors = append(errors, ValidationError{
+				err := ValidationError{
 					Line:     class.Pos.Line,
 					Column:   class.Pos.Column,
 					Message:  fmt.Sprintf("duplicate class name %q (first defined at line %d)", class.Name, pos.Line),
 					Severity: SeverityError,
-				})
+				}
+				// A bare repeated declaration adds nothing and can be removed
+				if strings.TrimSpace(sourceLine(diagram.Source, class.Pos.Line)) == "class "+class.Name {
+					err.Fix = &Fix{Line: class.Pos.Line, Delete: true}
+				}
+				errors = append(errors, err)
 			} else {
 				seen[class.Name] = class.Pos
 			}
@@ -172,12 +178,13 @@ func ClassDefaultRules() []ClassRule {
 		&ValidClassReferences{},
 		&ValidMemberVisibility{},
 		&ValidRelationshipType{},
+		&NoDuplicateClassRelationships{},
 	}
 }
 
 // ClassStrictRules returns a strict set of validation rules for class diagrams.
 func ClassStrictRules() []ClassRule {
-	return ClassDefaultRules()
+	return append(ClassDefaultRules(), &SecureInteractions{})
 }
 
 // NewClass creates a new class diagram validator with the given rules.
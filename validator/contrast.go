@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+const (
+	// MinimumContrastRatio is the WCAG 2 AA minimum contrast ratio for normal text.
+	MinimumContrastRatio = 4.5
+
+	// defaultFill and defaultTextColour are the node fill and text colour of
+	// mermaid's default theme, assumed when a style sets only one of them.
+	defaultFill       = "#ECECFF"
+	defaultTextColour = "#333333"
+)
+
+// ColourContrast warns when the text colour and fill set by a flowchart
+// classDef or style statement have a contrast ratio below the WCAG AA minimum.
+// When only one of fill and color is set, the other is assumed to be the
+// default theme's. Colours that can't be parsed (such as CSS variables) are
+// skipped.
+type ColourContrast struct{}
+
+// Name returns the name of this validation rule.
+func (r *ColourContrast) Name() string { return "colour-contrast" }
+
+// Validate checks classDef and style statements in the flowchart.
+func (r *ColourContrast) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var errors []ValidationError
+	r.check(flowchart.Statements, &errors)
+	return errors
+}
+
+func (r *ColourContrast) check(statements []ast.Statement, errors *[]ValidationError) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.ClassDef:
+			if err := styleContrast(s.Styles["color"], s.Styles["fill"], fmt.Sprintf("classDef '%s'", s.Name), s.Pos); err != nil {
+				*errors = append(*errors, *err)
+			}
+		case *ast.Style:
+			if err := styleContrast(s.Styles["color"], s.Styles["fill"], fmt.Sprintf("style for '%s'", s.NodeID), s.Pos); err != nil {
+				*errors = append(*errors, *err)
+			}
+		case *ast.Subgraph:
+			r.check(s.Statements, errors)
+		}
+	}
+}
+
+// C4ColourContrastRule warns when an UpdateElementStyle statement sets a font
+// colour and background colour whose contrast ratio is below the WCAG AA
+// minimum. Both colours must be set, as C4 element defaults vary by type.
+type C4ColourContrastRule struct{}
+
+// Validate checks UpdateElementStyle statements in the C4 diagram.
+func (r *C4ColourContrastRule) Validate(d *ast.C4Diagram) []ValidationError {
+	var errors []ValidationError
+	for _, style := range d.Styles {
+		if style.StyleType != "UpdateElementStyle" || style.FontColor == "" || style.BgColor == "" {
+			continue
+		}
+		if err := styleContrast(style.FontColor, style.BgColor, fmt.Sprintf("style for '%s'", style.ElementID), style.Pos); err != nil {
+			errors = append(errors, *err)
+		}
+	}
+	return errors
+}
+
+// styleContrast returns a warning if text and fill contrast too little, filling
+// in the default theme colour for whichever is empty.
+func styleContrast(text, fill, subject string, pos ast.Position) *ValidationError {
+	if text == "" && fill == "" {
+		return nil
+	}
+	if text == "" {
+		text = defaultTextColour
+	}
+	if fill == "" {
+		fill = defaultFill
+	}
+
+	textRGB, ok := parseColour(text)
+	if !ok {
+		return nil
+	}
+	fillRGB, ok := parseColour(fill)
+	if !ok {
+		return nil
+	}
+
+	ratio := contrastRatio(textRGB, fillRGB)
+	if ratio >= MinimumContrastRatio {
+		return nil
+	}
+	return &ValidationError{
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Message:  fmt.Sprintf("%s has text colour %s on %s, a contrast ratio of %.2f:1 (WCAG AA requires %.1f:1)", subject, text, fill, ratio, MinimumContrastRatio),
+		Severity: SeverityWarning,
+	}
+}
+
+// contrastRatio returns the WCAG 2 contrast ratio, from 1 to 21, between two
+// sRGB colours.
+func contrastRatio(a, b [3]uint8) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// relativeLuminance returns the WCAG 2 relative luminance of an sRGB colour.
+func relativeLuminance(rgb [3]uint8) float64 {
+	var channels [3]float64
+	for i, c := range rgb {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			channels[i] = v / 12.92
+		} else {
+			channels[i] = math.Pow((v+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*channels[0] + 0.7152*channels[1] + 0.0722*channels[2]
+}
@@ -0,0 +1,49 @@
+package validator
+
+import "github.com/sammcj/mermaid-check/ast"
+
+// DiagramKinds are the names configuration uses for each kind of diagram,
+// whatever its header: "flowchart" covers graph diagrams, "state"
+// stateDiagram-v2 and "c4" every C4 diagram.
+var DiagramKinds = []string{
+	"flowchart", "sequence", "class", "state", "er", "gantt", "pie", "journey",
+	"c4", "gitgraph", "mindmap", "timeline", "quadrant", "xychart", "sankey",
+}
+
+// DiagramKind returns the name of diagram's kind in DiagramKinds, or its type
+// for diagram types without a parser.
+func DiagramKind(diagram ast.Diagram) string {
+	switch diagram.(type) {
+	case *ast.Flowchart:
+		return "flowchart"
+	case *ast.SequenceDiagram:
+		return "sequence"
+	case *ast.ClassDiagram:
+		return "class"
+	case *ast.StateDiagram:
+		return "state"
+	case *ast.ERDiagram:
+		return "er"
+	case *ast.GanttDiagram:
+		return "gantt"
+	case *ast.PieDiagram:
+		return "pie"
+	case *ast.JourneyDiagram:
+		return "journey"
+	case *ast.C4Diagram:
+		return "c4"
+	case *ast.GitGraphDiagram:
+		return "gitgraph"
+	case *ast.MindmapDiagram:
+		return "mindmap"
+	case *ast.TimelineDiagram:
+		return "timeline"
+	case *ast.QuadrantDiagram:
+		return "quadrant"
+	case *ast.XYChartDiagram:
+		return "xychart"
+	case *ast.SankeyDiagram:
+		return "sankey"
+	}
+	return diagram.GetType()
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// AllowedDirections warns about flowcharts and subgraphs laid out in a
+// direction other than Directions, so that a project's diagrams read the same
+// way. TD and TB are the same direction, and a flowchart without one runs top
+// to bottom. With no Directions every direction is allowed.
+type AllowedDirections struct {
+	Directions []string
+}
+
+// Name returns the name of this validation rule.
+func (r *AllowedDirections) Name() string { return "allowed-directions" }
+
+// Options returns the directions to allow, which can be configured.
+func (r *AllowedDirections) Options() []Option {
+	return []Option{{Name: "directions", Type: OptionStringList, Description: "Directions flowcharts and subgraphs may use (TB, TD, BT, LR or RL)"}}
+}
+
+// Configure sets Directions from options.
+func (r *AllowedDirections) Configure(options RuleOptions) error {
+	value, ok := options["directions"]
+	if !ok {
+		return nil
+	}
+	for _, direction := range value.([]string) {
+		if normaliseDirection(direction) == "" {
+			return fmt.Errorf("unknown direction %q (want TB, TD, BT, LR or RL)", direction)
+		}
+	}
+	r.Directions = value.([]string)
+	return nil
+}
+
+// normaliseDirection returns direction in upper case with TD written as TB,
+// or "" if it isn't a direction.
+func normaliseDirection(direction string) string {
+	switch direction = strings.ToUpper(direction); direction {
+	case "TD":
+		return "TB"
+	case "TB", "BT", "LR", "RL":
+		return direction
+	}
+	return ""
+}
+
+// Validate checks the direction of the flowchart and of its subgraphs.
+func (r *AllowedDirections) Validate(flowchart *ast.Flowchart) []ValidationError {
+	if len(r.Directions) == 0 {
+		return nil
+	}
+	allowed := make([]string, len(r.Directions))
+	for i, direction := range r.Directions {
+		allowed[i] = normaliseDirection(direction)
+	}
+
+	var errors []ValidationError
+	check := func(direction string, pos ast.Position, what string) {
+		if slices.Contains(allowed, normaliseDirection(direction)) {
+			return
+		}
+		errors = append(errors, ValidationError{
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Message:  fmt.Sprintf("%s direction %s is not allowed (allowed: %s)", what, direction, strings.Join(r.Directions, ", ")),
+			Severity: SeverityWarning,
+		})
+	}
+
+	direction := flowchart.Direction
+	if direction == "" {
+		direction = "TB"
+	}
+	check(direction, flowchart.Pos, "flowchart")
+	r.checkSubgraphs(flowchart.Statements, check)
+	return errors
+}
+
+func (r *AllowedDirections) checkSubgraphs(statements []ast.Statement, check func(string, ast.Position, string)) {
+	for _, stmt := range statements {
+		if subgraph, ok := stmt.(*ast.Subgraph); ok {
+			if subgraph.Direction != "" {
+				check(subgraph.Direction, subgraph.Pos, "subgraph")
+			}
+			r.checkSubgraphs(subgraph.Statements, check)
+		}
+	}
+}
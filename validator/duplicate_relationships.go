@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// NoDuplicateLinks checks for flowchart links repeated with the same ends,
+// arrow and label, which Mermaid draws as separate overlapping edges.
+type NoDuplicateLinks struct{}
+
+// Name returns the name of this validation rule.
+func (r *NoDuplicateLinks) Name() string { return "no-duplicate-links" }
+
+// Validate checks that no link is defined more than once.
+func (r *NoDuplicateLinks) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var errors []ValidationError
+	r.checkLinks(flowchart.Statements, make(map[[4]string]ast.Position), &errors)
+	return errors
+}
+
+func (r *NoDuplicateLinks) checkLinks(statements []ast.Statement, seen map[[4]string]ast.Position, errors *[]ValidationError) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Link:
+			key := [4]string{s.From, s.To, s.Arrow, s.Label}
+			if pos, exists := seen[key]; exists {
+				*errors = append(*errors, ValidationError{
+					Line:     s.Pos.Line,
+					Column:   s.Pos.Column,
+					Message:  fmt.Sprintf("duplicate link from '%s' to '%s' (first defined at line %d)", s.From, s.To, pos.Line),
+					Severity: SeverityWarning,
+				})
+			} else {
+				seen[key] = s.Pos
+			}
+		case *ast.Subgraph:
+			r.checkLinks(s.Statements, seen, errors)
+		}
+	}
+}
+
+// NoDuplicateClassRelationships checks for class relationships repeated with
+// the same classes, type, label and multiplicities.
+type NoDuplicateClassRelationships struct{}
+
+// Name returns the rule name.
+func (r *NoDuplicateClassRelationships) Name() string {
+	return "no-duplicate-class-relationships"
+}
+
+// ValidateClass validates the class diagram.
+func (r *NoDuplicateClassRelationships) ValidateClass(diagram *ast.ClassDiagram) []ValidationError {
+	var errors []ValidationError
+	seen := make(map[ast.Relationship]ast.Position)
+
+	for _, stmt := range diagram.Statements {
+		rel, ok := stmt.(*ast.Relationship)
+		if !ok {
+			continue
+		}
+		key := *rel
+		key.Pos = ast.Position{}
+		if pos, exists := seen[key]; exists {
+			errors = append(errors, ValidationError{
+				Line:     rel.Pos.Line,
+				Column:   rel.Pos.Column,
+				Message:  fmt.Sprintf("duplicate relationship from %q to %q (first defined at line %d)", rel.From, rel.To, pos.Line),
+				Severity: SeverityWarning,
+			})
+		} else {
+			seen[key] = rel.Pos
+		}
+	}
+
+	return errors
+}
+
+// NoDuplicateERRelationshipsRule checks for ER relationships repeated with the
+// same entities, cardinalities, type and label.
+type NoDuplicateERRelationshipsRule struct{}
+
+// Validate checks that no relationship is defined more than once.
+func (r *NoDuplicateERRelationshipsRule) Validate(diagram *ast.ERDiagram) []*ValidationError {
+	var errors []*ValidationError
+	seen := make(map[ast.ERRelationship]ast.Position)
+
+	for _, rel := range diagram.Relationships {
+		key := rel
+		key.Pos = ast.Position{}
+		if pos, exists := seen[key]; exists {
+			errors = append(errors, &ValidationError{
+				Line:     rel.Pos.Line,
+				Column:   rel.Pos.Column,
+				Message:  fmt.Sprintf("duplicate relationship from %q to %q (first defined at line %d)", rel.From, rel.To, pos.Line),
+				Severity: SeverityWarning,
+			})
+		} else {
+			seen[key] = rel.Pos
+		}
+	}
+
+	return errors
+}
+
+// C4NoDuplicateRelationshipsRule checks for relationships between the same
+// pair of elements with the same label.
+type C4NoDuplicateRelationshipsRule struct{}
+
+// Validate checks that no relationship is defined more than once.
+func (r *C4NoDuplicateRelationshipsRule) Validate(diagram *ast.C4Diagram) []ValidationError {
+	var errors []ValidationError
+	seen := make(map[[3]string]ast.Position)
+
+	for _, rel := range diagram.Relationships {
+		key := [3]string{rel.From, rel.To, rel.Label}
+		if pos, exists := seen[key]; exists {
+			errors = append(errors, ValidationError{
+				Line:     rel.Pos.Line,
+				Column:   rel.Pos.Column,
+				Message:  fmt.Sprintf("duplicate relationship from '%s' to '%s' labelled '%s' (first defined on line %d)", rel.From, rel.To, rel.Label, pos.Line),
+				Severity: SeverityWarning,
+			})
+		} else {
+			seen[key] = rel.Pos
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// entityPattern matches HTML entities (&quot;) and Mermaid entity codes
+// (#quot;), capturing the name.
+var entityPattern = regexp.MustCompile(`[&#](\w+);`)
+
+// UnescapedQuotes reports double quotes inside quoted flowchart labels, as in
+// A["say "hi""], which end the label early and break rendering. Such quotes
+// are written #quot; and the fix replaces them.
+type UnescapedQuotes struct{}
+
+// Name returns the name of this validation rule.
+func (r *UnescapedQuotes) Name() string { return "unescaped-quotes" }
+
+// Validate checks quoted node and link labels.
+func (r *UnescapedQuotes) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var fields []textField
+	collectFlowchartText(flowchart.Statements, &fields)
+	lines := strings.Split(flowchart.Source, "\n")
+
+	var errors []ValidationError
+	for _, field := range fields {
+		escaped, ok := escapeInnerQuotes(field.text)
+		if !ok {
+			continue
+		}
+		err := ValidationError{
+			Line:       field.line,
+			Column:     textColumn(lines, field.line, field.text),
+			Message:    fmt.Sprintf("label %s has an unescaped double quote, which ends it early", field.text),
+			Severity:   SeverityError,
+			Suggestion: "write double quotes inside a quoted label as #quot;",
+		}
+		if line := sourceLine(flowchart.Source, field.line); strings.Count(line, field.text) == 1 {
+			err.Fix = &Fix{Line: field.line, Replacement: strings.Replace(line, field.text, escaped, 1)}
+		}
+		errors = append(errors, err)
+	}
+	return errors
+}
+
+// escapeInnerQuotes returns a quoted label with the double quotes inside it
+// written as #quot;, and whether there were any. Quotes escaped with a
+// backslash are left alone.
+func escapeInnerQuotes(label string) (string, bool) {
+	if len(label) < 3 || !strings.HasPrefix(label, `"`) || !strings.HasSuffix(label, `"`) {
+		return label, false
+	}
+	inner := label[1 : len(label)-1]
+	var b strings.Builder
+	found := false
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case inner[i] == '\\' && i+1 < len(inner) && inner[i+1] == '"':
+			b.WriteString(`\"`)
+			i++
+		case inner[i] == '"':
+			b.WriteString("#quot;")
+			found = true
+		default:
+			b.WriteByte(inner[i])
+		}
+	}
+	return `"` + b.String() + `"`, found
+}
+
+// UnknownEntities warns about HTML entities and Mermaid entity codes in
+// labels and messages that don't name a character, such as #qout;, which
+// Mermaid displays as written.
+type UnknownEntities struct{}
+
+// Name returns the name of this validation rule.
+func (r *UnknownEntities) Name() string { return "unknown-entities" }
+
+// Validate checks flowchart node, edge and subgraph labels.
+func (r *UnknownEntities) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var fields []textField
+	collectFlowchartText(flowchart.Statements, &fields)
+	return r.check(fields, flowchart.Source)
+}
+
+// ValidateSequence checks sequence diagram message text.
+func (r *UnknownEntities) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	var messages []textField
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		if message, ok := stmt.(*ast.Message); ok {
+			messages = append(messages, textField{message.Text, message.Pos.Line})
+		}
+	})
+	return r.check(messages, diagram.Source)
+}
+
+func (r *UnknownEntities) check(fields []textField, source string) []ValidationError {
+	lines := strings.Split(source, "\n")
+	var errors []ValidationError
+	for _, field := range fields {
+		for _, match := range entityPattern.FindAllStringSubmatch(field.text, -1) {
+			if _, ok := ast.DecodeEntity(match[1]); ok {
+				continue
+			}
+			errors = append(errors, ValidationError{
+				Line:     field.line,
+				Column:   textColumn(lines, field.line, match[0]),
+				Message:  fmt.Sprintf("unknown entity '%s' is displayed as written", match[0]),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
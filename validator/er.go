@@ -20,7 +20,7 @@ func ValidateER(diagram *ast.ERDiagram, strict bool) []*ValidationError {
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -31,6 +31,7 @@ func ERDefaultRules() []ERRule {
 		&NoDuplicateEntitiesRule{},
 		&ValidRelationshipReferencesRule{},
 		&ValidAttributeKeysRule{},
+		&NoDuplicateERRelationshipsRule{},
 	}
 }
 
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/export"
+)
+
+// ERSQLTypes checks that every ER attribute type maps to a column type in an
+// SQL dialect, so that export.SQL can generate the schema from the diagram.
+type ERSQLTypes struct {
+	Dialect export.Dialect
+}
+
+// Name returns the name of this validation rule.
+func (r *ERSQLTypes) Name() string { return "er-sql-types" }
+
+// ValidateDiagram applies the rule to ER diagrams, ignoring other types.
+func (r *ERSQLTypes) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	d, ok := diagram.(*ast.ERDiagram)
+	if !ok {
+		return nil
+	}
+	var errors []ValidationError
+	for _, err := range r.Validate(d) {
+		errors = append(errors, *err)
+	}
+	return errors
+}
+
+// Validate checks the type of every attribute against the dialect.
+func (r *ERSQLTypes) Validate(diagram *ast.ERDiagram) []*ValidationError {
+	var errors []*ValidationError
+	for _, entity := range diagram.Entities {
+		for _, attr := range entity.Attributes {
+			if _, ok := export.SQLType(attr.Type, r.Dialect); ok {
+				continue
+			}
+			errors = append(errors, &ValidationError{
+				Line:     attr.Pos.Line,
+				Column:   attr.Pos.Column,
+				Message:  fmt.Sprintf("attribute '%s' of '%s' has type '%s', which has no %s equivalent", attr.Name, entity.Name, attr.Type, r.Dialect),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// Fix is an automatic correction attached to a ValidationError. It replaces
+// one line of the diagram source, or removes it when Delete is set.
+type Fix struct {
+	Line        int    // Line to change (1-indexed, relative to the diagram like ValidationError.Line)
+	Replacement string // New text for the line
+	Delete      bool   // Remove the line instead of replacing it
+}
+
+// ApplyFixes applies the fixes attached to errors to source, returning the
+// corrected source and the number of fixes applied. Only the first fix for
+// each line is applied; validating the result again picks up any others.
+func ApplyFixes(source string, errors []ValidationError) (string, int) {
+	lines := strings.Split(source, "\n")
+	fixes := make(map[int]*Fix)
+	for _, err := range errors {
+		if err.Fix == nil || err.Fix.Line < 1 || err.Fix.Line > len(lines) {
+			continue
+		}
+		if _, exists := fixes[err.Fix.Line]; !exists {
+			fixes[err.Fix.Line] = err.Fix
+		}
+	}
+	if len(fixes) == 0 {
+		return source, 0
+	}
+
+	fixed := make([]string, 0, len(lines))
+	for i, line := range lines {
+		fix, ok := fixes[i+1]
+		switch {
+		case !ok:
+			fixed = append(fixed, line)
+		case !fix.Delete:
+			fixed = append(fixed, fix.Replacement)
+		}
+	}
+	return strings.Join(fixed, "\n"), len(fixes)
+}
+
+// sourceLine returns line n (1-indexed) of source, or "" if there is none.
+func sourceLine(source string, n int) string {
+	lines := strings.Split(source, "\n")
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	return lines[n-1]
+}
+
+// diagramSource returns the source diagram was parsed from, which fixes and
+// line-based rules work on.
+func diagramSource(diagram ast.Diagram) string {
+	switch d := diagram.(type) {
+	case *ast.GenericDiagram:
+		return d.Source
+	case interface{ GetSource() string }:
+		return d.GetSource()
+	}
+	return ""
+}
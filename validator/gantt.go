@@ -21,7 +21,7 @@ func ValidateGantt(diagram *ast.GanttDiagram, strict bool) []*ValidationError {
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -33,6 +33,8 @@ func GanttDefaultRules() []GanttRule {
 		&ValidTaskReferencesRule{},
 		&ValidDateFormatRule{},
 		&ValidTaskStatusRule{},
+		&ValidExcludesRule{},
+		&ExcludedTaskDatesRule{},
 	}
 }
 
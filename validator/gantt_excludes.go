@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+var ganttExcludesSplitRegex = regexp.MustCompile(`[\s,]+`)
+
+// ganttDayNames maps the day names accepted by excludes to weekdays.
+var ganttDayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ganttExcludes is the parsed form of an excludes line.
+type ganttExcludes struct {
+	weekdays map[time.Weekday]bool
+	dates    map[string]bool // Excluded dates, formatted with the diagram's layout
+}
+
+// excluded reports whether the given day is excluded.
+func (e ganttExcludes) excluded(day time.Time, layout string) bool {
+	return e.weekdays[day.Weekday()] || e.dates[day.Format(layout)]
+}
+
+// parseGanttExcludes splits an excludes value the way Mermaid does, returning
+// the excluded days along with any values that are not weekends, a day name
+// or a date in layout.
+func parseGanttExcludes(value, layout string) (ganttExcludes, []string) {
+	excludes := ganttExcludes{weekdays: map[time.Weekday]bool{}, dates: map[string]bool{}}
+	var invalid []string
+
+	for _, item := range ganttExcludesSplitRegex.Split(strings.TrimSpace(value), -1) {
+		if item == "" {
+			continue
+		}
+		lower := strings.ToLower(item)
+		if lower == "weekends" {
+			excludes.weekdays[time.Saturday] = true
+			excludes.weekdays[time.Sunday] = true
+			continue
+		}
+		if day, ok := ganttDayNames[lower]; ok {
+			excludes.weekdays[day] = true
+			continue
+		}
+		if layout != "" {
+			if date, err := time.Parse(layout, item); err == nil {
+				excludes.dates[date.Format(layout)] = true
+				continue
+			}
+		}
+		invalid = append(invalid, item)
+	}
+
+	return excludes, invalid
+}
+
+// ValidExcludesRule checks that excludes lists only weekends, day names and
+// dates written in the diagram's dateFormat.
+type ValidExcludesRule struct{}
+
+// Validate checks each excludes value.
+func (r *ValidExcludesRule) Validate(diagram *ast.GanttDiagram) []*ValidationError {
+	if diagram.Excludes == "" {
+		return nil
+	}
+
+	layout, known := analysis.GanttDateLayout(diagram.DateFormat)
+	if !known {
+		layout = ""
+	}
+	_, invalid := parseGanttExcludes(diagram.Excludes, layout)
+
+	var errors []*ValidationError
+	for _, item := range invalid {
+		// Dates cannot be checked against a dateFormat we do not understand
+		if !known && strings.ContainsAny(item, "0123456789") {
+			continue
+		}
+		errors = append(errors, &ValidationError{
+			Line:     diagram.ExcludesPos.Line,
+			Column:   diagram.ExcludesPos.Column,
+			Message:  fmt.Sprintf("invalid excludes value %q: expected weekends, a day name or a date in format %q", item, diagram.DateFormat),
+			Severity: SeverityError,
+		})
+	}
+
+	return errors
+}
+
+// ExcludedTaskDatesRule checks for tasks with an explicit start and end date
+// where every day in between is excluded. Mermaid keeps the explicit dates but
+// skips the excluded days, so the task renders as a zero-width bar.
+type ExcludedTaskDatesRule struct{}
+
+// Validate checks that explicitly dated tasks include at least one working day.
+func (r *ExcludedTaskDatesRule) Validate(diagram *ast.GanttDiagram) []*ValidationError {
+	if diagram.Excludes == "" {
+		return nil
+	}
+	layout, ok := analysis.GanttDateLayout(diagram.DateFormat)
+	if !ok {
+		return nil
+	}
+	excludes, _ := parseGanttExcludes(diagram.Excludes, layout)
+
+	var errors []*ValidationError
+	for _, section := range diagram.Sections {
+		for _, task := range section.Tasks {
+			start, err := time.Parse(layout, task.StartDate)
+			if err != nil {
+				continue
+			}
+			end, err := time.Parse(layout, task.EndDate)
+			if err != nil || !end.After(start) {
+				continue
+			}
+
+			working := false
+			for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+				if !excludes.excluded(day, layout) {
+					working = true
+					break
+				}
+			}
+			if !working {
+				errors = append(errors, &ValidationError{
+					Line:     task.Pos.Line,
+					Column:   task.Pos.Column,
+					Message:  fmt.Sprintf("task %q falls entirely within excluded dates and will render as a zero-width bar", task.Name),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+
+	return errors
+}
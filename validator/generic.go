@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -30,7 +31,7 @@ func (r *ValidComments) ValidateGeneric(diagram *ast.GenericDiagram) []Validatio
 		if strings.HasPrefix(trimmed, "%") && !strings.HasPrefix(trimmed, "%%") {
 			errors = append(errors, ValidationError{
 				Line:     diagram.Pos.Line + i,
-				Column:   strings.Index(line, "%") + 1,
+				Column:   utf8.RuneCountInString(line[:strings.Index(line, "%")]) + 1,
 				Message:  "invalid comment syntax: use '%%' for comments, not '%'",
 				Severity: SeverityError,
 			})
@@ -52,9 +53,10 @@ func (r *NoTrailingWhitespace) ValidateGeneric(diagram *ast.GenericDiagram) []Va
 		if len(line) > 0 && (line[len(line)-1] == ' ' || line[len(line)-1] == '\t') {
 			errors = append(errors, ValidationError{
 				Line:     diagram.Pos.Line + i,
-				Column:   len(line),
+				Column:   utf8.RuneCountInString(line),
 				Message:  "trailing whitespace on line",
 				Severity: SeverityWarning,
+				Fix:      &Fix{Line: diagram.Pos.Line + i, Replacement: strings.TrimRight(line, " \t")},
 			})
 		}
 	}
@@ -18,7 +18,7 @@ func ValidateGitGraph(diagram *ast.GitGraphDiagram, strict bool) []*ValidationEr
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -35,9 +35,12 @@ func GitGraphDefaultRules() []GitGraphRule {
 
 // GitGraphStrictRules returns strict validation rules for git graph diagrams.
 func GitGraphStrictRules() []GitGraphRule {
-	rules := GitGraphDefaultRules()
-	// Add strict-only rules here if needed
-	return rules
+	return append(GitGraphDefaultRules(),
+		&NoSelfMergeRule{},
+		&CheckoutAfterBranchRule{},
+		&NoCherryPickFromCurrentBranchRule{},
+		&NoEmptyBranchMergeRule{},
+	)
 }
 
 // NoDuplicateBranchNamesRule checks for duplicate branch names.
@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// gitGraphState tracks the branches of a git graph as its operations are
+// replayed in order.
+type gitGraphState struct {
+	current     string            // Branch currently checked out
+	created     map[string]bool   // Branches that exist so far
+	ownCommits  map[string]int    // Commits made on each branch since it was created
+	commitOwner map[string]string // Branch each commit ID was made on
+}
+
+// replayGitGraph calls visit for each operation of diagram with the state
+// before the operation, then applies the operation to the state. Operations
+// that Mermaid would reject (such as checking out an unknown branch) leave
+// the state unchanged.
+func replayGitGraph(diagram *ast.GitGraphDiagram, visit func(op ast.GitOperation, state *gitGraphState)) {
+	main := diagram.MainBranchName
+	if main == "" {
+		main = "main"
+	}
+	state := &gitGraphState{
+		current:     main,
+		created:     map[string]bool{main: true},
+		ownCommits:  map[string]int{main: 0},
+		commitOwner: make(map[string]string),
+	}
+
+	for _, op := range diagram.Operations {
+		visit(op, state)
+
+		switch op.Type {
+		case "commit", "merge", "cherry-pick":
+			state.ownCommits[state.current]++
+			if op.ID != "" {
+				state.commitOwner[op.ID] = state.current
+			}
+		case "branch":
+			state.created[op.BranchName] = true
+			state.ownCommits[op.BranchName] = 0
+			state.current = op.BranchName
+		case "checkout":
+			if state.created[op.BranchName] {
+				state.current = op.BranchName
+			}
+		}
+	}
+}
+
+// NoSelfMergeRule is a strict rule warning about merges of the branch that is
+// checked out, which Mermaid refuses to render.
+type NoSelfMergeRule struct{}
+
+// Validate checks that no branch is merged into itself.
+func (r *NoSelfMergeRule) Validate(diagram *ast.GitGraphDiagram) []*ValidationError {
+	var errors []*ValidationError
+	replayGitGraph(diagram, func(op ast.GitOperation, state *gitGraphState) {
+		if op.Type != "merge" || op.BranchName != state.current {
+			return
+		}
+		errors = append(errors, &ValidationError{
+			Line:       op.Pos.Line,
+			Column:     op.Pos.Column,
+			Message:    fmt.Sprintf("branch '%s' is merged into itself", op.BranchName),
+			Severity:   SeverityWarning,
+			Suggestion: "checkout the branch to merge into first",
+		})
+	})
+	return errors
+}
+
+// CheckoutAfterBranchRule is a strict rule warning about checkouts of a branch
+// before the branch statement that creates it. Branches that are never created are reported by
+// ValidBranchReferencesRule instead.
+type CheckoutAfterBranchRule struct{}
+
+// Validate checks that every branch is created before it is checked out.
+func (r *CheckoutAfterBranchRule) Validate(diagram *ast.GitGraphDiagram) []*ValidationError {
+	declared := make(map[string]bool)
+	for _, op := range diagram.Operations {
+		if op.Type == "branch" {
+			declared[op.BranchName] = true
+		}
+	}
+
+	var errors []*ValidationError
+	replayGitGraph(diagram, func(op ast.GitOperation, state *gitGraphState) {
+		if op.Type != "checkout" || state.created[op.BranchName] || !declared[op.BranchName] {
+			return
+		}
+		errors = append(errors, &ValidationError{
+			Line:       op.Pos.Line,
+			Column:     op.Pos.Column,
+			Message:    fmt.Sprintf("branch '%s' is checked out before it is created", op.BranchName),
+			Severity:   SeverityWarning,
+			Suggestion: fmt.Sprintf("move 'branch %s' above this checkout", op.BranchName),
+		})
+	})
+	return errors
+}
+
+// NoCherryPickFromCurrentBranchRule is a strict rule warning about
+// cherry-picks of a commit made on the branch that is checked out, which
+// Mermaid refuses to render.
+type NoCherryPickFromCurrentBranchRule struct{}
+
+// Validate checks that cherry-picked commits come from another branch.
+func (r *NoCherryPickFromCurrentBranchRule) Validate(diagram *ast.GitGraphDiagram) []*ValidationError {
+	var errors []*ValidationError
+	replayGitGraph(diagram, func(op ast.GitOperation, state *gitGraphState) {
+		owner, ok := state.commitOwner[op.ParentID]
+		if op.Type != "cherry-pick" || !ok || owner != state.current {
+			return
+		}
+		errors = append(errors, &ValidationError{
+			Line:       op.Pos.Line,
+			Column:     op.Pos.Column,
+			Message:    fmt.Sprintf("commit '%s' is cherry-picked onto branch '%s', where it was made", op.ParentID, owner),
+			Severity:   SeverityWarning,
+			Suggestion: "checkout another branch before cherry-picking",
+		})
+	})
+	return errors
+}
+
+// NoEmptyBranchMergeRule is a strict rule reporting merges of a branch that
+// has had no commits since it was created, which add nothing to the graph.
+type NoEmptyBranchMergeRule struct{}
+
+// Validate checks that merged branches have commits of their own.
+func (r *NoEmptyBranchMergeRule) Validate(diagram *ast.GitGraphDiagram) []*ValidationError {
+	var errors []*ValidationError
+	replayGitGraph(diagram, func(op ast.GitOperation, state *gitGraphState) {
+		if op.Type != "merge" || op.BranchName == state.current || !state.created[op.BranchName] ||
+			state.ownCommits[op.BranchName] > 0 {
+			return
+		}
+		errors = append(errors, &ValidationError{
+			Line:     op.Pos.Line,
+			Column:   op.Pos.Column,
+			Message:  fmt.Sprintf("branch '%s' is merged without any commits of its own", op.BranchName),
+			Severity: SeverityWarning,
+		})
+	})
+	return errors
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// DefaultIcons is the catalogue of icon identifiers KnownIcons accepts unless
+// given its own: the icons Mermaid architecture diagrams build in, and the
+// Iconify logos for common AWS and Google Cloud services and infrastructure.
+// An entry ending in ":*", such as "mdi:*", accepts every icon in that pack.
+var DefaultIcons = []string{
+	// Mermaid's built-in icons
+	"cloud", "database", "disk", "internet", "server",
+
+	// AWS
+	"logos:aws", "logos:aws-api-gateway", "logos:aws-cloudfront", "logos:aws-cloudwatch",
+	"logos:aws-dynamodb", "logos:aws-ec2", "logos:aws-ecs", "logos:aws-eks",
+	"logos:aws-elb", "logos:aws-fargate", "logos:aws-lambda", "logos:aws-rds",
+	"logos:aws-route53", "logos:aws-s3", "logos:aws-sns", "logos:aws-sqs",
+
+	// Google Cloud
+	"logos:google-cloud", "logos:google-cloud-functions", "logos:google-cloud-run",
+
+	// Infrastructure
+	"logos:docker-icon", "logos:kubernetes", "logos:mongodb-icon", "logos:mysql-icon",
+	"logos:nginx", "logos:postgresql", "logos:redis",
+}
+
+// KnownIcons warns about C4 element and relationship sprites that aren't in a
+// catalogue of known icon identifiers, which usually means a typo or an icon
+// pack that was never registered. Sprites are compared ignoring case and a
+// leading "$". Icons replaces DefaultIcons as the catalogue when set.
+type KnownIcons struct {
+	Icons []string
+}
+
+// Name returns the name of this validation rule.
+func (r *KnownIcons) Name() string { return "known-icons" }
+
+// ValidateDiagram checks the sprites of a C4 diagram, ignoring other types.
+func (r *KnownIcons) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	d, ok := diagram.(*ast.C4Diagram)
+	if !ok {
+		return nil
+	}
+	catalogue := r.Icons
+	if catalogue == nil {
+		catalogue = DefaultIcons
+	}
+
+	var errors []ValidationError
+	check := func(kind, id, sprite string, pos ast.Position) {
+		icon := strings.TrimPrefix(strings.TrimSpace(sprite), "$")
+		if icon == "" || knownIcon(catalogue, icon) {
+			return
+		}
+		err := ValidationError{
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Message:  fmt.Sprintf("%s '%s' uses unknown icon '%s'", kind, id, icon),
+			Severity: SeverityWarning,
+		}
+		if suggestion := iconInOtherPack(catalogue, icon); suggestion != "" {
+			err.Suggestion = fmt.Sprintf("did you mean '%s'?", suggestion)
+		}
+		errors = append(errors, err)
+	}
+
+	var checkElements func(elements []ast.C4Element, boundaries []ast.C4Boundary)
+	checkElements = func(elements []ast.C4Element, boundaries []ast.C4Boundary) {
+		for _, element := range elements {
+			check(element.ElementType, element.ID, element.Sprite, element.Pos)
+		}
+		for _, boundary := range boundaries {
+			checkElements(boundary.Elements, boundary.Boundaries)
+		}
+	}
+	checkElements(d.Elements, d.Boundaries)
+	for _, rel := range d.Relationships {
+		check("relationship", rel.From+" -> "+rel.To, rel.Sprite, rel.Pos)
+	}
+	return errors
+}
+
+// knownIcon reports whether the catalogue has icon, directly or through a
+// "pack:*" entry for its pack.
+func knownIcon(catalogue []string, icon string) bool {
+	pack, _, hasPack := strings.Cut(icon, ":")
+	for _, known := range catalogue {
+		if strings.EqualFold(known, icon) || (hasPack && strings.EqualFold(known, pack+":*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// iconInOtherPack returns the catalogue entry with the same name as icon in a
+// different pack, or with a pack where icon has none, such as
+// "logos:aws-lambda" for "aws-lambda"; or "" if there isn't one.
+func iconInOtherPack(catalogue []string, icon string) string {
+	_, name, hasPack := strings.Cut(icon, ":")
+	if !hasPack {
+		name = icon
+	}
+	for _, known := range catalogue {
+		_, knownName, _ := strings.Cut(known, ":")
+		if knownName == "" {
+			knownName = known
+		}
+		if strings.EqualFold(knownName, name) && !strings.EqualFold(known, icon) {
+			return known
+		}
+	}
+	return ""
+}
@@ -0,0 +1,274 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// indentStyle is the kind of whitespace a line is indented with.
+type indentStyle int
+
+const (
+	noIndent indentStyle = iota
+	spaceIndent
+	tabIndent
+	mixedIndent
+)
+
+// defaultIndentWidth is the number of spaces a tab stands for when no line is
+// indented with spaces to go by.
+const defaultIndentWidth = 4
+
+// MixedIndentation reports lines whose indentation mixes tabs and spaces, or
+// uses a different one to the rest of the diagram, which breaks
+// indentation-sensitive syntax such as mindmaps. YAML front matter must be
+// indented with spaces. The fix re-indents the line in the dominant style.
+type MixedIndentation struct{}
+
+// Name returns the name of this validation rule.
+func (r *MixedIndentation) Name() string { return "mixed-indentation" }
+
+// ValidateDiagram reports inconsistently indented lines in diagram.
+func (r *MixedIndentation) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	lines := strings.Split(diagramSource(diagram), "\n")
+
+	var errors []ValidationError
+	bodyStart := frontMatterEnd(lines)
+	if bodyStart > 0 {
+		errors = checkIndentation(lines[:bodyStart], 0, spaceIndent, "front matter")
+	}
+	body := lines[bodyStart:]
+	return append(errors, checkIndentation(body, bodyStart, dominantIndent(body), "diagram")...)
+}
+
+// frontMatterEnd returns the index of the line after the YAML front matter at
+// the start of lines, or 0 if there is none.
+func frontMatterEnd(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// leadingIndent returns the indentation of line and its style. Lines holding
+// only whitespace are treated as unindented.
+func leadingIndent(line string) (string, indentStyle) {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	switch {
+	case indent == "" || indent == line:
+		return "", noIndent
+	case !strings.Contains(indent, "\t"):
+		return indent, spaceIndent
+	case !strings.Contains(indent, " "):
+		return indent, tabIndent
+	default:
+		return indent, mixedIndent
+	}
+}
+
+// dominantIndent returns the style most lines are indented with, preferring
+// spaces when there are as many lines indented with tabs.
+func dominantIndent(lines []string) indentStyle {
+	var spaces, tabs int
+	for _, line := range lines {
+		switch _, style := leadingIndent(line); style {
+		case spaceIndent:
+			spaces++
+		case tabIndent:
+			tabs++
+		}
+	}
+	if tabs > spaces {
+		return tabIndent
+	}
+	return spaceIndent
+}
+
+// indentWidth returns the number of spaces a tab stands for in lines: the
+// smallest indentation of the lines indented with spaces.
+func indentWidth(lines []string) int {
+	width := 0
+	for _, line := range lines {
+		if indent, style := leadingIndent(line); style == spaceIndent && (width == 0 || len(indent) < width) {
+			width = len(indent)
+		}
+	}
+	if width == 0 {
+		return defaultIndentWidth
+	}
+	return width
+}
+
+// checkIndentation reports the lines not indented in the dominant style, which
+// are lines[i] at line offset+i+1 of a diagram; part names them in messages.
+func checkIndentation(lines []string, offset int, dominant indentStyle, part string) []ValidationError {
+	width := indentWidth(lines)
+	dominantName, otherName := "spaces", "tabs"
+	if dominant == tabIndent {
+		dominantName, otherName = "tabs", "spaces"
+	}
+
+	var errors []ValidationError
+	for i, line := range lines {
+		indent, style := leadingIndent(line)
+		if style == noIndent || style == dominant {
+			continue
+		}
+
+		message := "indentation mixes tabs and spaces"
+		if style != mixedIndent {
+			message = fmt.Sprintf("line is indented with %s, but the %s is indented with %s", otherName, part, dominantName)
+		}
+		if part == "front matter" {
+			message += "; YAML front matter must be indented with spaces"
+		}
+		errors = append(errors, ValidationError{
+			Line:     offset + i + 1,
+			Column:   1,
+			Message:  message,
+			Severity: SeverityWarning,
+			Fix:      &Fix{Line: offset + i + 1, Replacement: reindent(indent, dominant, width) + line[len(indent):]},
+		})
+	}
+	return errors
+}
+
+// reindent converts indent to the given style, taking a tab to be width spaces.
+func reindent(indent string, style indentStyle, width int) string {
+	columns := strings.Count(indent, "\t")*width + strings.Count(indent, " ")
+	if style == spaceIndent {
+		return strings.Repeat(" ", columns)
+	}
+	return strings.Repeat("\t", max(1, (columns+width/2)/width))
+}
+
+// blockKeywords are the statements that open a block closed by "end", per
+// diagram type.
+var blockKeywords = map[string][]string{
+	"flowchart": {"subgraph"},
+	"graph":     {"subgraph"},
+	"sequence":  {"loop", "alt", "opt", "par", "par_over", "critical", "break", "rect", "box"},
+}
+
+// blockDividers are the statements that divide a block into sections, such as
+// "else" in an alt block. They are indented like the statement opening the
+// block.
+var blockDividers = map[string][]string{
+	"sequence": {"else", "and", "option"},
+}
+
+// IndentStyle reports statements that are not indented by one level below the
+// (unindented) diagram header plus one level for each subgraph, loop, alt or
+// other block, including blocks in braces, they are nested in. A level is
+// Width spaces, or a tab when Tabs is set. Mindmaps and sankey diagrams, whose
+// indentation is not block structure, are not checked. The fix re-indents the
+// statement.
+type IndentStyle struct {
+	Width int  // Spaces per level; defaultIndentWidth when zero
+	Tabs  bool // Indent with a tab per level rather than spaces
+}
+
+// ParseIndentStyle parses an indentation setting: a number of spaces per
+// level, or "tab" (or "tabs") to indent with tabs.
+func ParseIndentStyle(value string) (*IndentStyle, error) {
+	value = strings.TrimSpace(value)
+	if value == "tab" || value == "tabs" {
+		return &IndentStyle{Tabs: true}, nil
+	}
+	width, err := strconv.Atoi(value)
+	if err != nil || width < 1 {
+		return nil, fmt.Errorf("invalid indentation %q: expected a number of spaces or \"tab\"", value)
+	}
+	return &IndentStyle{Width: width}, nil
+}
+
+// Name returns the name of this validation rule.
+func (r *IndentStyle) Name() string { return "indent-style" }
+
+// ValidateDiagram reports statements in diagram at the wrong indentation.
+func (r *IndentStyle) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	diagramType := diagram.GetType()
+	if diagramType == "mindmap" || diagramType == "sankey" {
+		return nil
+	}
+
+	lines := strings.Split(diagramSource(diagram), "\n")
+	var errors []ValidationError
+	depth := 0 // Nesting level of the next statement; 0 until the header is seen
+	for i := frontMatterEnd(lines); i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "%%") {
+			continue
+		}
+
+		keyword := strings.Fields(trimmed)[0]
+		level := depth
+		switch {
+		case depth == 0:
+			depth = 1
+		case trimmed == "end" || strings.HasPrefix(trimmed, "}"):
+			depth = max(1, depth-1)
+			level = depth
+		case slices.Contains(blockDividers[diagramType], keyword):
+			level = max(1, depth-1)
+		case slices.Contains(blockKeywords[diagramType], keyword), strings.HasSuffix(trimmed, "{"):
+			depth++
+		}
+
+		indent, _ := leadingIndent(lines[i])
+		if want := r.indent(level); indent != want {
+			errors = append(errors, ValidationError{
+				Line:     i + 1,
+				Column:   1,
+				Message:  fmt.Sprintf("expected indentation of %s, found %s", describeIndent(want), describeIndent(indent)),
+				Severity: SeverityWarning,
+				Fix:      &Fix{Line: i + 1, Replacement: want + lines[i][len(indent):]},
+			})
+		}
+	}
+	return errors
+}
+
+// indent returns the indentation for the given nesting level.
+func (r *IndentStyle) indent(level int) string {
+	if r.Tabs {
+		return strings.Repeat("\t", level)
+	}
+	width := r.Width
+	if width <= 0 {
+		width = defaultIndentWidth
+	}
+	return strings.Repeat(" ", level*width)
+}
+
+// describeIndent describes indentation for messages, as in "4 spaces".
+func describeIndent(indent string) string {
+	var parts []string
+	if tabs := strings.Count(indent, "\t"); tabs > 0 {
+		parts = append(parts, countOf(tabs, "tab"))
+	}
+	if spaces := strings.Count(indent, " "); spaces > 0 {
+		parts = append(parts, countOf(spaces, "space"))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// countOf formats n of noun, as in "1 tab" or "4 spaces".
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// securityLevelPattern finds the securityLevel setting in an init directive or frontmatter config.
+var securityLevelPattern = regexp.MustCompile(`securityLevel['"]?\s*:\s*['"]?([a-zA-Z]+)`)
+
+// SecureInteractions checks click, link and callback statements for unsafe or
+// non-functional targets: javascript: URLs, plain http links, and callbacks in
+// diagrams that don't set securityLevel to 'loose' (mermaid ignores callbacks
+// under the default 'strict' level).
+type SecureInteractions struct{}
+
+// Name returns the name of this validation rule.
+func (r *SecureInteractions) Name() string { return "secure-interactions" }
+
+// Validate checks the interactions in a flowchart.
+func (r *SecureInteractions) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var interactions []*ast.Interaction
+	collectFlowchartInteractions(flowchart.Statements, &interactions)
+	return r.check(interactions, flowchart.Source)
+}
+
+// ValidateClass checks the interactions in a class diagram.
+func (r *SecureInteractions) ValidateClass(diagram *ast.ClassDiagram) []ValidationError {
+	var interactions []*ast.Interaction
+	for _, stmt := range diagram.Statements {
+		if interaction, ok := stmt.(*ast.Interaction); ok {
+			interactions = append(interactions, interaction)
+		}
+	}
+	return r.check(interactions, diagram.Source)
+}
+
+func (r *SecureInteractions) check(interactions []*ast.Interaction, source string) []ValidationError {
+	var errors []ValidationError
+	level := securityLevel(source)
+
+	for _, interaction := range interactions {
+		switch interaction.Kind {
+		case "href":
+			url := strings.ToLower(strings.TrimSpace(interaction.URL))
+			switch {
+			case strings.HasPrefix(url, "javascript:"):
+				errors = append(errors, ValidationError{
+					Line:     interaction.Pos.Line,
+					Column:   interaction.Pos.Column,
+					Message:  fmt.Sprintf("link on '%s' uses a javascript: URL; use a callback with securityLevel 'loose' instead", interaction.NodeID),
+					Severity: SeverityError,
+				})
+			case strings.HasPrefix(url, "http://"):
+				errors = append(errors, ValidationError{
+					Line:     interaction.Pos.Line,
+					Column:   interaction.Pos.Column,
+					Message:  fmt.Sprintf("link on '%s' uses insecure http://; prefer https://", interaction.NodeID),
+					Severity: SeverityWarning,
+				})
+			}
+		case "callback":
+			if level != "loose" {
+				current := level
+				if current == "" {
+					current = "strict (default)"
+				}
+				errors = append(errors, ValidationError{
+					Line:     interaction.Pos.Line,
+					Column:   interaction.Pos.Column,
+					Message:  fmt.Sprintf("callback '%s' on '%s' requires securityLevel 'loose', but securityLevel is %s", interaction.Callback, interaction.NodeID, current),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+func collectFlowchartInteractions(statements []ast.Statement, interactions *[]*ast.Interaction) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Interaction:
+			*interactions = append(*interactions, s)
+		case *ast.Subgraph:
+			collectFlowchartInteractions(s.Statements, interactions)
+		}
+	}
+}
+
+// securityLevel returns the securityLevel configured in source, or "" if none is set.
+func securityLevel(source string) string {
+	if matches := securityLevelPattern.FindStringSubmatch(source); matches != nil {
+		return strings.ToLower(matches[1])
+	}
+	return ""
+}
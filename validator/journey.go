@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 
+	"github.com/sammcj/mermaid-check/analysis"
 	"github.com/sammcj/mermaid-check/ast"
 )
 
@@ -20,7 +21,7 @@ func ValidateJourney(diagram *ast.JourneyDiagram, strict bool) []*ValidationErro
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -35,9 +36,7 @@ func JourneyDefaultRules() []JourneyRule {
 
 // JourneyStrictRules returns strict validation rules for journey diagrams.
 func JourneyStrictRules() []JourneyRule {
-	rules := JourneyDefaultRules()
-	// Add strict-only rules here if needed
-	return rules
+	return append(JourneyDefaultRules(), &ConsistentActorsRule{}, &LowSectionScoreRule{})
 }
 
 // ValidTaskScoresRule checks that all task scores are within valid range (1-5).
@@ -85,3 +84,84 @@ func (r *TasksHaveActorsRule) Validate(diagram *ast.JourneyDiagram) []*Validatio
 
 	return errors
 }
+
+// ConsistentActorsRule warns about actors that appear in only one task while
+// other actors recur, as a one-off name is often a typo of another actor.
+type ConsistentActorsRule struct{}
+
+// Validate reports actors used by a single task.
+func (r *ConsistentActorsRule) Validate(diagram *ast.JourneyDiagram) []*ValidationError {
+	counts := make(map[string]int)
+	first := make(map[string]ast.Position)
+	var order []string
+	recurring := false
+
+	for _, section := range diagram.Sections {
+		for _, task := range section.Tasks {
+			for _, actor := range task.Actors {
+				if counts[actor] == 0 {
+					first[actor] = task.Pos
+					order = append(order, actor)
+				}
+				counts[actor]++
+				if counts[actor] > 1 {
+					recurring = true
+				}
+			}
+		}
+	}
+
+	if !recurring {
+		return nil
+	}
+
+	var errors []*ValidationError
+	for _, actor := range order {
+		if counts[actor] != 1 {
+			continue
+		}
+		errors = append(errors, &ValidationError{
+			Line:     first[actor].Line,
+			Column:   first[actor].Column,
+			Message:  fmt.Sprintf("actor %q appears in only one task; check it is not a misspelling of another actor", actor),
+			Severity: SeverityWarning,
+		})
+	}
+
+	return errors
+}
+
+// DefaultJourneyScoreThreshold is the lowest average section score accepted
+// by LowSectionScoreRule: the middle of Mermaid's 1 to 5 scale.
+const DefaultJourneyScoreThreshold = 3.0
+
+// LowSectionScoreRule warns about journey sections whose tasks score below
+// Threshold on average, pointing at the parts of a journey most in need of
+// attention. Threshold defaults to DefaultJourneyScoreThreshold.
+type LowSectionScoreRule struct {
+	Threshold float64
+}
+
+// Validate checks the average score of every section with tasks.
+func (r *LowSectionScoreRule) Validate(diagram *ast.JourneyDiagram) []*ValidationError {
+	threshold := r.Threshold
+	if threshold <= 0 {
+		threshold = DefaultJourneyScoreThreshold
+	}
+
+	var errors []*ValidationError
+	for i, score := range analysis.JourneyScores(diagram).Sections {
+		if score.Tasks == 0 || score.Average >= threshold {
+			continue
+		}
+		section := diagram.Sections[i]
+		errors = append(errors, &ValidationError{
+			Line:     section.Pos.Line,
+			Column:   section.Pos.Column,
+			Message:  fmt.Sprintf("section %q has an average score of %.1f, below %.1f", section.Name, score.Average, threshold),
+			Severity: SeverityWarning,
+		})
+	}
+
+	return errors
+}
@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+const (
+	// DefaultFlowchartLabelLength is the default longest flowchart node or
+	// edge label, in characters, accepted by LabelLength.
+	DefaultFlowchartLabelLength = 40
+	// DefaultSequenceMessageLength is the default longest sequence message,
+	// in characters, accepted by LabelLength.
+	DefaultSequenceMessageLength = 60
+)
+
+// lineBreakPattern matches the <br> tags mermaid renders as line breaks.
+var lineBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// LabelLength warns about node labels, edge labels and sequence messages too
+// long to render well. Limits is keyed by diagram type ("flowchart" or
+// "sequence"); types without a limit use DefaultFlowchartLabelLength and
+// DefaultSequenceMessageLength. Text already split with <br/> is measured
+// line by line.
+type LabelLength struct {
+	Limits map[string]int
+}
+
+// Name returns the name of this validation rule.
+func (r *LabelLength) Name() string { return "label-length" }
+
+// Options returns the limits that can be configured, one per diagram type.
+func (r *LabelLength) Options() []Option {
+	return []Option{
+		{Name: "flowchart", Type: OptionInt, Description: "Longest flowchart label, in characters", Default: DefaultFlowchartLabelLength},
+		{Name: "sequence", Type: OptionInt, Description: "Longest sequence message, in characters", Default: DefaultSequenceMessageLength},
+	}
+}
+
+// Configure sets the limits given in options.
+func (r *LabelLength) Configure(options RuleOptions) error {
+	for diagramType, value := range options {
+		limit := value.(int)
+		if limit <= 0 {
+			return fmt.Errorf("%s limit must be positive, got %d", diagramType, limit)
+		}
+		if r.Limits == nil {
+			r.Limits = make(map[string]int)
+		}
+		r.Limits[diagramType] = limit
+	}
+	return nil
+}
+
+// Validate checks flowchart node, edge and subgraph labels.
+func (r *LabelLength) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var fields []textField
+	collectFlowchartText(flowchart.Statements, &fields)
+	return r.check("label", fields, flowchart.Source, r.limit("flowchart", DefaultFlowchartLabelLength))
+}
+
+// ValidateSequence checks sequence diagram message text.
+func (r *LabelLength) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	var messages []textField
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		if message, ok := stmt.(*ast.Message); ok {
+			messages = append(messages, textField{message.Text, message.Pos.Line})
+		}
+	})
+	return r.check("message", messages, diagram.Source, r.limit("sequence", DefaultSequenceMessageLength))
+}
+
+func (r *LabelLength) limit(diagramType string, fallback int) int {
+	if limit, ok := r.Limits[diagramType]; ok && limit > 0 {
+		return limit
+	}
+	return fallback
+}
+
+func (r *LabelLength) check(kind string, fields []textField, source string, limit int) []ValidationError {
+	lines := strings.Split(source, "\n")
+	var errors []ValidationError
+	for _, field := range fields {
+		longest := 0
+		for _, part := range lineBreakPattern.Split(field.text, -1) {
+			longest = max(longest, utf8.RuneCountInString(strings.TrimSpace(part)))
+		}
+		if longest <= limit {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:     field.line,
+			Column:   textColumn(lines, field.line, field.text),
+			Message:  fmt.Sprintf("%s is %d characters long (limit %d); consider splitting it with <br/>", kind, longest, limit),
+			Severity: SeverityWarning,
+		})
+	}
+	return errors
+}
+
+// WithLabelLengths returns rules with the limits of any LabelLength rule set to
+// limits, adding a LabelLength rule if rules has none. It works with both Rule
+// and SequenceRule sets.
+func WithLabelLengths[R any](rules []R, limits map[string]int) []R {
+	for _, rule := range rules {
+		if labelLength, ok := any(rule).(*LabelLength); ok {
+			labelLength.Limits = limits
+			return rules
+		}
+	}
+	if rule, ok := any(&LabelLength{Limits: limits}).(R); ok {
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// DefaultMaxLinkLength is the longest flowchart link, in ranks, accepted by
+// MaxLinkLength. It matches the longest arrows in Mermaid's documentation
+// (---->, ====>, -...->).
+const DefaultMaxLinkLength = 3
+
+// MaxLinkLength warns about flowchart links extended (as in ------>) to span
+// more than Max ranks, which stretch the layout rather than clarify it. Max
+// defaults to DefaultMaxLinkLength.
+type MaxLinkLength struct {
+	Max int
+}
+
+// Name returns the name of this validation rule.
+func (r *MaxLinkLength) Name() string { return "max-link-length" }
+
+// Options returns the longest link length, which can be configured.
+func (r *MaxLinkLength) Options() []Option {
+	return []Option{{Name: "max", Type: OptionInt, Description: "Longest link, in ranks", Default: DefaultMaxLinkLength}}
+}
+
+// Configure sets Max from options.
+func (r *MaxLinkLength) Configure(options RuleOptions) error {
+	if value, ok := options["max"]; ok {
+		if value.(int) <= 0 {
+			return fmt.Errorf("max must be positive, got %d", value)
+		}
+		r.Max = value.(int)
+	}
+	return nil
+}
+
+// Validate checks the length of every link in the flowchart.
+func (r *MaxLinkLength) Validate(flowchart *ast.Flowchart) []ValidationError {
+	limit := r.Max
+	if limit <= 0 {
+		limit = DefaultMaxLinkLength
+	}
+
+	var errors []ValidationError
+	r.checkLinks(flowchart.Statements, limit, &errors)
+	return errors
+}
+
+func (r *MaxLinkLength) checkLinks(statements []ast.Statement, limit int, errors *[]ValidationError) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Link:
+			if s.Length > limit {
+				*errors = append(*errors, ValidationError{
+					Line:     s.Pos.Line,
+					Column:   s.Pos.Column,
+					Message:  fmt.Sprintf("link '%s' from '%s' to '%s' spans %d ranks; shorten it to at most %d", s.Arrow, s.From, s.To, s.Length, limit),
+					Severity: SeverityWarning,
+				})
+			}
+		case *ast.Subgraph:
+			r.checkLinks(s.Statements, limit, errors)
+		}
+	}
+}
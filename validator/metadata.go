@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// RequiredAnnotations reports diagrams missing any of the configured metadata
+// annotations (`%% @key: value` comments). Unlike the type-specific rules it
+// applies to every diagram type, so a repository can require, for example, an
+// owner on all of its diagrams.
+type RequiredAnnotations struct {
+	Keys []string // Annotation keys every diagram must define with a non-empty value
+}
+
+// Name returns the name of this validation rule.
+func (r *RequiredAnnotations) Name() string { return "required-annotations" }
+
+// ValidateDiagram checks that diagram defines every required annotation.
+func (r *RequiredAnnotations) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	var errors []ValidationError
+	metadata := diagram.GetMetadata()
+	pos := diagram.GetPosition()
+
+	for _, key := range r.Keys {
+		value, ok := metadata[key]
+		var message string
+		switch {
+		case !ok:
+			message = fmt.Sprintf("missing required annotation '@%s' (add a '%%%% @%s: value' comment)", key, key)
+		case value == "":
+			message = fmt.Sprintf("required annotation '@%s' has no value", key)
+		default:
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Message:  message,
+			Severity: SeverityError,
+		})
+	}
+
+	return errors
+}
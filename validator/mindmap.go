@@ -2,6 +2,8 @@ package validator
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -20,7 +22,7 @@ func ValidateMindmap(diagram *ast.MindmapDiagram, strict bool) []*ValidationErro
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -31,6 +33,8 @@ func MindmapDefaultRules() []MindmapRule {
 		&RootNodeExistsRule{},
 		&NoEmptyNodesRule{},
 		&ValidShapeRule{},
+		&ValidIconsRule{},
+		&ValidNodeClassesRule{},
 	}
 }
 
@@ -135,3 +139,86 @@ func (r *ValidShapeRule) Validate(diagram *ast.MindmapDiagram) []*ValidationErro
 
 	return errors
 }
+
+// cssClassPattern matches a single CSS class name, as used in icon class lists
+// such as "fa fa-book" and in ::: class lines.
+var cssClassPattern = regexp.MustCompile(`^-?[_a-zA-Z][_a-zA-Z0-9-]*$`)
+
+// walkMindmap calls fn for every node in the tree rooted at node.
+func walkMindmap(node *ast.MindmapNode, fn func(*ast.MindmapNode)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	for _, child := range node.Children {
+		walkMindmap(child, fn)
+	}
+}
+
+// ValidIconsRule checks ::icon() directives: each must hold a non-empty list of
+// CSS classes, and a node should have only one, since Mermaid silently uses
+// the last.
+type ValidIconsRule struct{}
+
+// Validate checks the icon directives of every node.
+func (r *ValidIconsRule) Validate(diagram *ast.MindmapDiagram) []*ValidationError {
+	var errors []*ValidationError
+
+	walkMindmap(diagram.Root, func(node *ast.MindmapNode) {
+		for _, icon := range node.Icons {
+			if icon == "" {
+				errors = append(errors, &ValidationError{
+					Line:     node.Pos.Line,
+					Column:   node.Pos.Column,
+					Message:  fmt.Sprintf("node %q has an empty ::icon()", node.Text),
+					Severity: SeverityError,
+				})
+				continue
+			}
+			for _, class := range strings.Fields(icon) {
+				if !cssClassPattern.MatchString(class) {
+					errors = append(errors, &ValidationError{
+						Line:     node.Pos.Line,
+						Column:   node.Pos.Column,
+						Message:  fmt.Sprintf("node %q icon %q contains invalid class name %q", node.Text, icon, class),
+						Severity: SeverityWarning,
+					})
+				}
+			}
+		}
+
+		if len(node.Icons) > 1 {
+			errors = append(errors, &ValidationError{
+				Line:     node.Pos.Line,
+				Column:   node.Pos.Column,
+				Message:  fmt.Sprintf("node %q has %d ::icon() directives; only the last (%q) is used", node.Text, len(node.Icons), node.Icon),
+				Severity: SeverityWarning,
+			})
+		}
+	})
+
+	return errors
+}
+
+// ValidNodeClassesRule checks that ::: class lines hold valid CSS class names.
+type ValidNodeClassesRule struct{}
+
+// Validate checks the classes of every node.
+func (r *ValidNodeClassesRule) Validate(diagram *ast.MindmapDiagram) []*ValidationError {
+	var errors []*ValidationError
+
+	walkMindmap(diagram.Root, func(node *ast.MindmapNode) {
+		for _, class := range node.Classes {
+			if !cssClassPattern.MatchString(class) {
+				errors = append(errors, &ValidationError{
+					Line:     node.Pos.Line,
+					Column:   node.Pos.Column,
+					Message:  fmt.Sprintf("node %q has invalid class name %q", node.Text, class),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	})
+
+	return errors
+}
@@ -0,0 +1,173 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// namingPresets are the named conventions accepted by NamingPattern.
+var namingPresets = map[string]string{
+	"camelCase":            `^[a-z][a-zA-Z0-9]*$`,
+	"PascalCase":           `^[A-Z][a-zA-Z0-9]*$`,
+	"snake_case":           `^[a-z][a-z0-9]*(_[a-z0-9]+)*$`,
+	"SCREAMING_SNAKE_CASE": `^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`,
+	"kebab-case":           `^[a-z][a-z0-9]*(-[a-z0-9]+)*$`,
+}
+
+// NamingPattern compiles a naming convention, which is either the name of a
+// preset (camelCase, PascalCase, snake_case, SCREAMING_SNAKE_CASE or
+// kebab-case) or a regular expression.
+func NamingPattern(convention string) (*regexp.Regexp, error) {
+	if preset, ok := namingPresets[convention]; ok {
+		convention = preset
+	}
+	return regexp.Compile(convention)
+}
+
+// NamingConventions checks identifiers against a naming convention per
+// diagram type: flowchart node and subgraph IDs, sequence participants, class
+// names and state names. Patterns is keyed by "flowchart", "sequence", "class"
+// or "state"; diagram types without a pattern are not checked. Each identifier
+// is reported once, where it first appears.
+type NamingConventions struct {
+	Patterns map[string]*regexp.Regexp
+}
+
+// Name returns the name of this validation rule.
+func (r *NamingConventions) Name() string { return "naming-conventions" }
+
+// Options returns the conventions that can be configured, one per diagram
+// type.
+func (r *NamingConventions) Options() []Option {
+	var options []Option
+	for _, diagramType := range []string{"flowchart", "sequence", "class", "state"} {
+		options = append(options, Option{Name: diagramType, Type: OptionPattern, Description: "Naming convention for " + diagramType + " identifiers"})
+	}
+	return options
+}
+
+// Configure sets the patterns given in options.
+func (r *NamingConventions) Configure(options RuleOptions) error {
+	for diagramType, value := range options {
+		if r.Patterns == nil {
+			r.Patterns = make(map[string]*regexp.Regexp)
+		}
+		r.Patterns[diagramType] = value.(*regexp.Regexp)
+	}
+	return nil
+}
+
+// identifier is a name used in a diagram along with the line it appears on.
+type identifier struct {
+	name string
+	line int
+}
+
+// ValidateDiagram reports identifiers not matching the pattern for the
+// diagram's type.
+func (r *NamingConventions) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	var kind, source string
+	var ids []identifier
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		kind, source = "flowchart", d.Source
+		collectFlowchartIDs(d.Statements, &ids)
+	case *ast.SequenceDiagram:
+		kind, source = "sequence", d.Source
+		collectSequenceIDs(d.Statements, &ids)
+	case *ast.ClassDiagram:
+		kind, source = "class", d.Source
+		for _, stmt := range d.Statements {
+			switch s := stmt.(type) {
+			case *ast.Class:
+				ids = append(ids, identifier{s.Name, s.Pos.Line})
+			case *ast.Relationship:
+				ids = append(ids, identifier{s.From, s.Pos.Line}, identifier{s.To, s.Pos.Line})
+			}
+		}
+	case *ast.StateDiagram:
+		kind, source = "state", d.Source
+		collectStateIDs(d.Statements, &ids)
+	default:
+		return nil
+	}
+
+	pattern := r.Patterns[kind]
+	if pattern == nil {
+		return nil
+	}
+
+	lines := strings.Split(source, "\n")
+	seen := make(map[string]bool)
+	var errors []ValidationError
+	for _, id := range ids {
+		if id.name == "" || seen[id.name] {
+			continue
+		}
+		seen[id.name] = true
+		if pattern.MatchString(id.name) {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:     id.line,
+			Column:   wordColumn(lines, id.line, id.name),
+			Message:  fmt.Sprintf("%s identifier '%s' does not match naming convention %s", kind, id.name, pattern),
+			Severity: SeverityWarning,
+		})
+	}
+	return errors
+}
+
+func collectFlowchartIDs(statements []ast.Statement, ids *[]identifier) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+		case *ast.Link:
+			*ids = append(*ids, identifier{s.From, s.Pos.Line}, identifier{s.To, s.Pos.Line})
+		case *ast.Subgraph:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+			collectFlowchartIDs(s.Statements, ids)
+		}
+	}
+}
+
+func collectSequenceIDs(statements []ast.SeqStmt, ids *[]identifier) {
+	walkSequence(statements, func(stmt ast.SeqStmt) {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+		case *ast.Box:
+			for _, p := range s.Participants {
+				*ids = append(*ids, identifier{p.ID, p.Pos.Line})
+			}
+		case *ast.Message:
+			*ids = append(*ids, identifier{s.From, s.Pos.Line}, identifier{s.To, s.Pos.Line})
+		}
+	})
+}
+
+func collectStateIDs(statements []ast.StateStmt, ids *[]identifier) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.State:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+			collectStateIDs(s.Nested, ids)
+		case *ast.Transition:
+			*ids = append(*ids, identifier{s.From, s.Pos.Line}, identifier{s.To, s.Pos.Line})
+		case *ast.StartState:
+			*ids = append(*ids, identifier{s.To, s.Pos.Line})
+		case *ast.EndState:
+			*ids = append(*ids, identifier{s.From, s.Pos.Line})
+		case *ast.Fork:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+		case *ast.Join:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+		case *ast.Choice:
+			*ids = append(*ids, identifier{s.ID, s.Pos.Line})
+		}
+	}
+}
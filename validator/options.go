@@ -0,0 +1,201 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// OptionType is the type of value a rule option takes.
+type OptionType string
+
+const (
+	OptionInt        OptionType = "integer"
+	OptionNumber     OptionType = "number"
+	OptionString     OptionType = "string"
+	OptionStringList OptionType = "list of strings"
+	OptionBool       OptionType = "boolean"
+	// OptionPattern is a naming preset or regular expression (see
+	// NamingPattern), converted to a *regexp.Regexp.
+	OptionPattern OptionType = "pattern"
+)
+
+// Option describes an option a configurable rule takes.
+type Option struct {
+	Name        string     `json:"name"`
+	Type        OptionType `json:"type"`
+	Description string     `json:"description"`
+	Default     any        `json:"default,omitempty"` // Value used when the option is not given, or nil for none
+}
+
+// RuleOptions are the option values given to a rule, keyed by option name.
+type RuleOptions map[string]any
+
+// ConfigurableRule is a rule with typed options. Options declares them, and
+// Configure applies values that CheckOptions has checked and converted, so it
+// can rely on each value having its option's Go type: int, float64, string,
+// []string, bool or *regexp.Regexp. Options not given are left as they were.
+type ConfigurableRule interface {
+	Name() string
+	Options() []Option
+	Configure(options RuleOptions) error
+}
+
+// ConfigurableRules returns a new instance of every rule taking options, in
+// name order.
+func ConfigurableRules() []ConfigurableRule {
+	return []ConfigurableRule{
+		&AllowedDirections{},
+		&LabelLength{},
+		&MaxLinkLength{},
+		&MaxParticipants{},
+		&NamingConventions{},
+		&Terminology{},
+	}
+}
+
+// ConfigureRule returns a new instance of the configurable rule called name
+// with options applied, reporting rules that don't exist or take no options,
+// unknown options and values of the wrong type.
+func ConfigureRule(name string, options RuleOptions) (ConfigurableRule, error) {
+	var names []string
+	for _, rule := range ConfigurableRules() {
+		if rule.Name() != name {
+			names = append(names, rule.Name())
+			continue
+		}
+		checked, err := CheckOptions(rule, options)
+		if err != nil {
+			return nil, err
+		}
+		if err := rule.Configure(checked); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		return rule, nil
+	}
+	return nil, fmt.Errorf("rule %q takes no options (rules with options: %s)", name, strings.Join(names, ", "))
+}
+
+// CheckOptions checks options against the schema of rule, returning them
+// converted to the Go types Configure expects.
+func CheckOptions(rule ConfigurableRule, options RuleOptions) (RuleOptions, error) {
+	schema := rule.Options()
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	checked := make(RuleOptions, len(options))
+	for _, key := range keys {
+		i := slices.IndexFunc(schema, func(option Option) bool { return option.Name == key })
+		if i < 0 {
+			names := make([]string, len(schema))
+			for j, option := range schema {
+				names[j] = option.Name
+			}
+			return nil, fmt.Errorf("rule %s has no option %q (options: %s)", rule.Name(), key, strings.Join(names, ", "))
+		}
+		value, err := convertOption(schema[i].Type, options[key])
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: option %s: %w", rule.Name(), key, err)
+		}
+		checked[key] = value
+	}
+	return checked, nil
+}
+
+// convertOption converts a value decoded from YAML or JSON to the Go type of
+// an option of type t.
+func convertOption(t OptionType, value any) (any, error) {
+	wrongType := fmt.Errorf("want %s, got %s", t, describeValue(value))
+	switch t {
+	case OptionInt:
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		}
+	case OptionNumber:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case OptionString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case OptionBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case OptionStringList:
+		switch v := value.(type) {
+		case []string:
+			return v, nil
+		case []any:
+			list := make([]string, len(v))
+			for i, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("want %s, got %s at item %d", t, describeValue(item), i+1)
+				}
+				list[i] = s
+			}
+			return list, nil
+		}
+	case OptionPattern:
+		if v, ok := value.(string); ok {
+			pattern, err := NamingPattern(v)
+			if err != nil {
+				return nil, err
+			}
+			return pattern, nil
+		}
+	}
+	return nil, wrongType
+}
+
+// describeValue describes a decoded value for error messages.
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nothing"
+	case int, float64, bool:
+		return fmt.Sprint(v)
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case []any, []string:
+		return "a list"
+	case map[string]any:
+		return "a mapping"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// WithConfiguredRules returns rules with each rule of the same name as one of
+// configured replaced by it, and the other configured rules that apply to R
+// added, so that giving a rule options enables it. It works with every rule
+// set type.
+func WithConfiguredRules[R any](rules []R, configured []ConfigurableRule) []R {
+	rules = slices.Clone(rules)
+	for _, c := range configured {
+		rule, ok := any(c).(R)
+		if !ok {
+			continue
+		}
+		i := slices.IndexFunc(rules, func(r R) bool { return RuleName(r) == c.Name() })
+		if i < 0 {
+			rules = append(rules, rule)
+		} else {
+			rules[i] = rule
+		}
+	}
+	return rules
+}
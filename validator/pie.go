@@ -2,6 +2,8 @@ package validator
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -20,7 +22,7 @@ func ValidatePie(diagram *ast.PieDiagram, strict bool) []*ValidationError {
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -30,14 +32,13 @@ func PieDefaultRules() []PieRule {
 	return []PieRule{
 		&NoDuplicateLabelsRule{},
 		&PositiveValuesRule{},
+		&PieTextPositionRule{},
 	}
 }
 
 // PieStrictRules returns strict validation rules for pie diagrams.
 func PieStrictRules() []PieRule {
-	rules := PieDefaultRules()
-	// Add strict-only rules here if needed
-	return rules
+	return append(PieDefaultRules(), &PieValuePrecisionRule{})
 }
 
 // NoDuplicateLabelsRule checks for duplicate labels in pie chart.
@@ -77,3 +78,49 @@ func (r *PositiveValuesRule) Validate(diagram *ast.PieDiagram) []*ValidationErro
 
 	return errors
 }
+
+// PieTextPositionRule checks that a textPosition set in an init directive is a
+// number between 0 (the centre) and 1 (the outer edge).
+type PieTextPositionRule struct{}
+
+// Validate checks the textPosition setting.
+func (r *PieTextPositionRule) Validate(diagram *ast.PieDiagram) []*ValidationError {
+	raw, ok := diagram.Config["textPosition"]
+	if !ok {
+		return nil
+	}
+
+	position, err := strconv.ParseFloat(raw, 64)
+	if err != nil || position < 0 || position > 1 {
+		return []*ValidationError{{
+			Line:     diagram.ConfigPos.Line,
+			Column:   diagram.ConfigPos.Column,
+			Message:  fmt.Sprintf("pie textPosition must be a number between 0 and 1 (got %q)", raw),
+			Severity: SeverityError,
+		}}
+	}
+	return nil
+}
+
+// PieValuePrecisionRule checks that values have at most two decimal places,
+// the precision Mermaid keeps when rendering pie charts.
+type PieValuePrecisionRule struct{}
+
+// Validate checks the number of decimal places in each value.
+func (r *PieValuePrecisionRule) Validate(diagram *ast.PieDiagram) []*ValidationError {
+	var errors []*ValidationError
+
+	for _, entry := range diagram.DataEntries {
+		_, decimals, found := strings.Cut(entry.Raw, ".")
+		if found && len(decimals) > 2 {
+			errors = append(errors, &ValidationError{
+				Line:     entry.Pos.Line,
+				Column:   entry.Pos.Column,
+				Message:  fmt.Sprintf("pie chart value for %q has more than two decimal places (%s); Mermaid rounds it", entry.Label, entry.Raw),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+
+	return errors
+}
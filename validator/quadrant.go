@@ -2,6 +2,9 @@ package validator
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -20,7 +23,7 @@ func ValidateQuadrant(diagram *ast.QuadrantDiagram, strict bool) []*ValidationEr
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -33,6 +36,8 @@ func QuadrantDefaultRules() []QuadrantRule {
 		&QuadrantXAxisDefinedRule{},
 		&QuadrantYAxisDefinedRule{},
 		&MinimumPointsRule{},
+		&QuadrantValidStylesRule{},
+		&QuadrantValidClassesRule{},
 	}
 }
 
@@ -146,3 +151,83 @@ func (r *MinimumPointsRule) Validate(diagram *ast.QuadrantDiagram) []*Validation
 
 	return nil
 }
+
+// QuadrantValidStylesRule checks point styles and classDef properties: only
+// supported properties, valid colours, and numeric radius and stroke width.
+type QuadrantValidStylesRule struct{}
+
+// Validate checks the styles of every point and class definition.
+func (r *QuadrantValidStylesRule) Validate(diagram *ast.QuadrantDiagram) []*ValidationError {
+	var errors []*ValidationError
+
+	for _, class := range diagram.ClassDefs {
+		errors = append(errors, quadrantStyleErrors(fmt.Sprintf("classDef %q", class.Name), class.Styles, class.Pos)...)
+	}
+	for _, point := range diagram.Points {
+		errors = append(errors, quadrantStyleErrors(fmt.Sprintf("point %q", point.Name), point.Styles, point.Pos)...)
+	}
+
+	return errors
+}
+
+// quadrantStyleErrors validates one set of point styles, in a stable order.
+func quadrantStyleErrors(owner string, styles map[string]string, pos ast.Position) []*ValidationError {
+	keys := make([]string, 0, len(styles))
+	for key := range styles {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var errors []*ValidationError
+	report := func(message string) {
+		errors = append(errors, &ValidationError{
+			Line:     pos.Line,
+			Column:   pos.Column,
+			Message:  fmt.Sprintf("%s %s", owner, message),
+			Severity: SeverityWarning,
+		})
+	}
+
+	for _, key := range keys {
+		value := styles[key]
+		switch key {
+		case "color", "stroke-color":
+			if !isValidColour(value) {
+				report(fmt.Sprintf("has invalid %s %q", key, value))
+			}
+		case "radius", "stroke-width":
+			if _, err := strconv.ParseFloat(strings.TrimSuffix(value, "px"), 64); err != nil {
+				report(fmt.Sprintf("has non-numeric %s %q", key, value))
+			}
+		default:
+			report(fmt.Sprintf("has unsupported style %q (expected radius, color, stroke-color or stroke-width)", key))
+		}
+	}
+
+	return errors
+}
+
+// QuadrantValidClassesRule checks that points only use defined classes.
+type QuadrantValidClassesRule struct{}
+
+// Validate checks each point's class against the classDef lines.
+func (r *QuadrantValidClassesRule) Validate(diagram *ast.QuadrantDiagram) []*ValidationError {
+	defined := make(map[string]bool, len(diagram.ClassDefs))
+	for _, class := range diagram.ClassDefs {
+		defined[class.Name] = true
+	}
+
+	var errors []*ValidationError
+	for _, point := range diagram.Points {
+		if point.Class != "" && !defined[point.Class] {
+			errors = append(errors, &ValidationError{
+				Line:     point.Pos.Line,
+				Column:   point.Pos.Column,
+				Message:  fmt.Sprintf("point %q references undefined classDef %q", point.Name, point.Class),
+				Severity: SeverityError,
+			})
+		}
+	}
+
+	return errors
+}
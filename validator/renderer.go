@@ -0,0 +1,282 @@
+package validator
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// Renderer describes what a Mermaid renderer, such as a wiki's Mermaid macro,
+// can display. Publishing targets often bundle an older Mermaid or configure it
+// themselves, so diagrams that render in the Mermaid live editor can break or
+// lose their styling there.
+type Renderer struct {
+	Name  string `json:"-"`     // Name used to select the renderer, such as "confluence"
+	Title string `json:"title"` // Name used in messages, such as "Confluence's Mermaid macro"
+	// MermaidVersion is the Mermaid release it bundles, when known.
+	MermaidVersion string `json:"mermaid"`
+	// InitDirectives reports whether %%{init: ...}%% directives are honoured.
+	InitDirectives bool `json:"initDirectives"`
+	// MarkdownStrings reports whether markdown strings ("`**bold**`") in
+	// labels are rendered.
+	MarkdownStrings bool `json:"markdownStrings"`
+	// UnsupportedDiagrams are the headers of the diagram types it can't
+	// display, such as "sankey-beta".
+	UnsupportedDiagrams []string `json:"unsupportedDiagrams"`
+	// Interactions reports whether click, link and callback statements work;
+	// they don't where diagrams render in a sandbox.
+	Interactions bool `json:"interactions"`
+	// LockedConfig are the settings it fixes itself, whatever an init
+	// directive asks for.
+	LockedConfig []string `json:"lockedConfig"`
+}
+
+// mermaidRelease is what a Mermaid release added.
+type mermaidRelease struct {
+	MarkdownStrings bool     `json:"markdownStrings"`
+	Diagrams        []string `json:"diagrams"`
+}
+
+// rendererMatrix holds the capability matrix: the Mermaid releases that
+// added features renderers may lack, and each renderer's Mermaid version and
+// settings. A new publishing target needs only an entry here.
+//
+//go:embed renderers.json
+var rendererMatrix []byte
+
+// renderers are the publishing targets RendererCompatibility knows, sorted by
+// name.
+var renderers = loadRenderers(rendererMatrix)
+
+// loadRenderers reads a capability matrix. A renderer with a known Mermaid
+// version also lacks whatever later releases added.
+func loadRenderers(data []byte) []Renderer {
+	var matrix struct {
+		Mermaid   map[string]mermaidRelease `json:"mermaid"`
+		Renderers map[string]Renderer       `json:"renderers"`
+	}
+	if err := json.Unmarshal(data, &matrix); err != nil {
+		panic(fmt.Sprintf("reading the renderer capability matrix: %v", err)) // Embedded, so only a bad edit gets here
+	}
+
+	list := make([]Renderer, 0, len(matrix.Renderers))
+	for name, renderer := range matrix.Renderers {
+		renderer.Name = name
+		if renderer.MermaidVersion != "" {
+			for version, release := range matrix.Mermaid {
+				if compareVersions(version, renderer.MermaidVersion) <= 0 {
+					continue
+				}
+				if release.MarkdownStrings {
+					renderer.MarkdownStrings = false
+				}
+				renderer.UnsupportedDiagrams = append(renderer.UnsupportedDiagrams, release.Diagrams...)
+			}
+			slices.Sort(renderer.UnsupportedDiagrams)
+		}
+		list = append(list, renderer)
+	}
+	slices.SortFunc(list, func(a, b Renderer) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+// compareVersions compares two dotted version numbers, such as "10.9.1",
+// returning -1, 0 or 1.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// LookupRenderer returns the renderer with the given name, and whether there
+// is one.
+func LookupRenderer(name string) (Renderer, bool) {
+	i := slices.IndexFunc(renderers, func(r Renderer) bool { return r.Name == name })
+	if i < 0 {
+		return Renderer{}, false
+	}
+	return renderers[i], true
+}
+
+// RendererNames returns the names of the renderers RendererCompatibility
+// knows, sorted.
+func RendererNames() []string {
+	names := make([]string, len(renderers))
+	for i, r := range renderers {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// ForRenderer returns the rules that check diagrams against the named
+// renderer, or an error naming the known renderers when there is no such
+// renderer.
+func ForRenderer(name string) ([]DiagramRule, error) {
+	renderer, ok := LookupRenderer(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer %q: expected one of %s", name, strings.Join(RendererNames(), ", "))
+	}
+	return []DiagramRule{&RendererCompatibility{Renderer: renderer}}, nil
+}
+
+var (
+	// initDirectivePattern matches a %%{init: ...}%% (or initialize) directive.
+	initDirectivePattern = regexp.MustCompile(`^\s*%%\{\s*['"]?init(?:ialize)?['"]?\s*:`)
+	// markdownStringPattern matches a markdown string: a quoted label whose
+	// text is wrapped in backticks.
+	markdownStringPattern = regexp.MustCompile("\"`[^`\"]*`\"")
+	// directiveBodyPattern captures the braced body of a directive line.
+	directiveBodyPattern = regexp.MustCompile(`^\s*%%(\{.*\})%%\s*$`)
+)
+
+// interactionStatements are the statements that attach click handlers or
+// links, by diagram kind.
+var interactionStatements = map[string][]string{
+	"flowchart": {"click"},
+	"class":     {"click", "link", "callback"},
+}
+
+// RendererCompatibility reports constructs a renderer is known not to
+// display: diagram types it can't draw, which fail to render, and init
+// directives, settings and markdown strings it ignores, which leave the
+// diagram drawn with default settings or markup shown as written, and
+// interactions it disables. It applies to every diagram type.
+type RendererCompatibility struct {
+	Renderer Renderer
+}
+
+// Name returns the name of this validation rule.
+func (r *RendererCompatibility) Name() string { return "renderer-compatibility" }
+
+// ValidateDiagram checks the source of diagram against the renderer.
+func (r *RendererCompatibility) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	var errors []ValidationError
+	interactions := interactionStatements[DiagramKind(diagram)]
+	headerSeen := false
+	lines := strings.Split(diagramSource(diagram), "\n")
+	for i := frontMatterEnd(lines); i < len(lines); i++ {
+		line, lineNum := lines[i], i+1
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case initDirectivePattern.MatchString(line):
+			if !r.Renderer.InitDirectives {
+				errors = append(errors, ValidationError{
+					Line:       lineNum,
+					Column:     strings.Index(line, "%%") + 1,
+					Message:    fmt.Sprintf("%s ignores %%%%{init}%%%% directives, so the diagram is drawn with its default settings", r.Renderer.Title),
+					Severity:   SeverityWarning,
+					Suggestion: "style the diagram with classDef and style statements instead",
+				})
+				continue
+			}
+			errors = append(errors, r.lockedConfig(line, lineNum)...)
+			continue
+		case strings.HasPrefix(trimmed, "%%"):
+			continue
+		case !headerSeen:
+			headerSeen = true
+			header := strings.Fields(trimmed)[0]
+			if slices.Contains(r.Renderer.UnsupportedDiagrams, header) {
+				kind := "diagram type"
+				if strings.HasSuffix(header, "-beta") {
+					kind = "beta diagram type"
+				}
+				errors = append(errors, ValidationError{
+					Line:     lineNum,
+					Column:   strings.Index(line, header) + 1,
+					Message:  fmt.Sprintf("%s can't render the %s %s", r.rendererTitle(), kind, header),
+					Severity: SeverityError,
+				})
+			}
+			continue
+		}
+		if keyword := strings.Fields(trimmed)[0]; !r.Renderer.Interactions && slices.Contains(interactions, keyword) {
+			errors = append(errors, ValidationError{
+				Line:       lineNum,
+				Column:     strings.Index(line, keyword) + 1,
+				Message:    fmt.Sprintf("%s disables diagram interactions, so this %s statement does nothing", r.Renderer.Title, keyword),
+				Severity:   SeverityWarning,
+				Suggestion: "link to the target from the text around the diagram instead",
+			})
+		}
+		if r.Renderer.MarkdownStrings {
+			continue
+		}
+		for _, loc := range markdownStringPattern.FindAllStringIndex(line, -1) {
+			errors = append(errors, ValidationError{
+				Line:       lineNum,
+				Column:     len([]rune(line[:loc[0]])) + 1,
+				Message:    fmt.Sprintf("%s doesn't render markdown strings, so %s is displayed with its backticks and markup", r.Renderer.Title, line[loc[0]:loc[1]]),
+				Severity:   SeverityWarning,
+				Suggestion: "use a plain quoted label, with <b> or <i> for emphasis",
+			})
+		}
+	}
+	return errors
+}
+
+// rendererTitle returns the renderer's title with the Mermaid version it
+// bundles, when known.
+func (r *RendererCompatibility) rendererTitle() string {
+	if r.Renderer.MermaidVersion == "" {
+		return r.Renderer.Title
+	}
+	return fmt.Sprintf("%s (Mermaid %s)", r.Renderer.Title, r.Renderer.MermaidVersion)
+}
+
+// lockedConfig reports the settings in the init directive on line that the
+// renderer overrides. Directive bodies are JSON-like and may use single quotes
+// or unquoted keys, so they are read as YAML flow mappings.
+func (r *RendererCompatibility) lockedConfig(line string, lineNum int) []ValidationError {
+	if len(r.Renderer.LockedConfig) == 0 {
+		return nil
+	}
+	matches := directiveBodyPattern.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	var directive map[string]any
+	if err := yaml.Unmarshal([]byte(matches[1]), &directive); err != nil {
+		return nil
+	}
+	config, ok := directive["init"].(map[string]any)
+	if !ok {
+		config, _ = directive["initialize"].(map[string]any)
+	}
+
+	var errors []ValidationError
+	for _, key := range r.Renderer.LockedConfig {
+		if _, set := config[key]; !set {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:       lineNum,
+			Column:     strings.Index(line, key) + 1,
+			Message:    fmt.Sprintf("%s sets %s itself and ignores it in %%%%{init}%%%% directives", r.Renderer.Title, key),
+			Severity:   SeverityWarning,
+			Suggestion: fmt.Sprintf("remove %s from the directive", key),
+		})
+	}
+	return errors
+}
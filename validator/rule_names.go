@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// RuleName returns the name of rule: its Name method when it has one,
+// otherwise its type name in kebab-case without the "Rule" suffix (so
+// *PositiveValuesRule is "positive-values").
+func RuleName(rule any) string {
+	if named, ok := rule.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+
+	t := reflect.TypeOf(rule)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return kebabCase(strings.TrimSuffix(t.Name(), "Rule"))
+}
+
+// RuleVersion returns the version of rule: its Version method when it has
+// one, otherwise 1. A rule's version goes up when a change to it may report
+// different errors for the same diagram, so that tools depending on a rule's
+// behaviour can tell.
+func RuleVersion(rule any) int {
+	if versioned, ok := rule.(interface{ Version() int }); ok {
+		return versioned.Version()
+	}
+	return 1
+}
+
+// kebabCase converts a Go identifier such as "XYChartUniqueSeriesNames" to
+// "xy-chart-unique-series-names".
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// withRule records rule as the source of errors that don't already name one.
+func withRule(errors []ValidationError, rule any) []ValidationError {
+	name := RuleName(rule)
+	for i := range errors {
+		if errors[i].Rule == "" {
+			errors[i].Rule = name
+		}
+	}
+	return errors
+}
+
+// withRulePointers is withRule for rules returning []*ValidationError.
+func withRulePointers(errors []*ValidationError, rule any) []*ValidationError {
+	name := RuleName(rule)
+	for _, err := range errors {
+		if err.Rule == "" {
+			err.Rule = name
+		}
+	}
+	return errors
+}
+
+// DiagramRule is a rule that applies to any diagram type, such as the
+// configurable naming-conventions and terminology rules.
+type DiagramRule interface {
+	Name() string
+	ValidateDiagram(diagram ast.Diagram) []ValidationError
+}
+
+// ValidateDiagramRules runs rules against diagram.
+func ValidateDiagramRules(diagram ast.Diagram, rules ...DiagramRule) []ValidationError {
+	var errors []ValidationError
+	for _, rule := range rules {
+		errors = append(errors, withRule(rule.ValidateDiagram(diagram), rule)...)
+	}
+	return errors
+}
+
+// WithoutRules returns errors with those reported by the named rules removed.
+func WithoutRules(errors []ValidationError, names ...string) []ValidationError {
+	if len(names) == 0 {
+		return errors
+	}
+	kept := make([]ValidationError, 0, len(errors))
+	for _, err := range errors {
+		if !slices.Contains(names, err.Rule) {
+			kept = append(kept, err)
+		}
+	}
+	return kept
+}
@@ -20,7 +20,7 @@ func ValidateSankey(diagram *ast.SankeyDiagram, strict bool) []*ValidationError
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
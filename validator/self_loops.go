@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// DefaultSelfLoopSeverities returns the severity SelfLoops reports self-loops
+// with for each diagram type. Self-transitions are common in state machines so
+// are only informational, whereas a C4 element relating to itself is almost
+// always a mistake.
+func DefaultSelfLoopSeverities() map[string]Severity {
+	return map[string]Severity{
+		"flowchart": SeverityWarning,
+		"state":     SeverityInfo,
+		"class":     SeverityWarning,
+		"c4":        SeverityError,
+	}
+}
+
+// SelfLoops reports edges that start and end at the same node: flowchart
+// links, state transitions, class relationships and C4 relationships.
+// Severities overrides DefaultSelfLoopSeverities per diagram type ("flowchart",
+// "state", "class" or "c4"), and diagram types listed in Disabled are not
+// checked.
+type SelfLoops struct {
+	Severities map[string]Severity
+	Disabled   []string
+}
+
+// Name returns the name of this validation rule.
+func (r *SelfLoops) Name() string { return "self-loops" }
+
+// selfLoop is an edge from a node to itself.
+type selfLoop struct {
+	kind string // What the edge is called in this diagram type
+	node string
+	pos  ast.Position
+}
+
+// ValidateDiagram reports self-loops in diagram.
+func (r *SelfLoops) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	var kind string
+	var loops []selfLoop
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		kind = "flowchart"
+		collectFlowchartSelfLoops(d.Statements, &loops)
+	case *ast.StateDiagram:
+		kind = "state"
+		for _, trans := range collectTransitions(d.Statements, nil) {
+			if trans.From == trans.To {
+				loops = append(loops, selfLoop{"transition", trans.From, trans.Pos})
+			}
+		}
+	case *ast.ClassDiagram:
+		kind = "class"
+		for _, stmt := range d.Statements {
+			if rel, ok := stmt.(*ast.Relationship); ok && rel.From == rel.To {
+				loops = append(loops, selfLoop{"relationship", rel.From, rel.Pos})
+			}
+		}
+	case *ast.C4Diagram:
+		kind = "c4"
+		for _, rel := range d.Relationships {
+			if rel.From == rel.To {
+				loops = append(loops, selfLoop{"relationship", rel.From, rel.Pos})
+			}
+		}
+	default:
+		return nil
+	}
+
+	for _, disabled := range r.Disabled {
+		if disabled == kind {
+			return nil
+		}
+	}
+	severity, ok := r.Severities[kind]
+	if !ok {
+		severity = DefaultSelfLoopSeverities()[kind]
+	}
+
+	errors := make([]ValidationError, 0, len(loops))
+	for _, loop := range loops {
+		errors = append(errors, ValidationError{
+			Line:     loop.pos.Line,
+			Column:   loop.pos.Column,
+			Message:  fmt.Sprintf("%s from '%s' to itself", loop.kind, loop.node),
+			Severity: severity,
+		})
+	}
+	return errors
+}
+
+func collectFlowchartSelfLoops(statements []ast.Statement, loops *[]selfLoop) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Link:
+			if s.From == s.To {
+				*loops = append(*loops, selfLoop{"link", s.From, s.Pos})
+			}
+		case *ast.Subgraph:
+			collectFlowchartSelfLoops(s.Statements, loops)
+		}
+	}
+}
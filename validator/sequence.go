@@ -2,7 +2,10 @@ package validator
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/sammcj/mermaid-check/analysis"
 	"github.com/sammcj/mermaid-check/ast"
 )
 
@@ -183,17 +186,9 @@ func (r *ValidMessageArrows) Name() string { return "valid-message-arrows" }
 // ValidateSequence checks message arrow syntax.
 func (r *ValidMessageArrows) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
 	var errors []ValidationError
-	validArrows := map[string]bool{
-		"->":      true,
-		"-->":     true,
-		"->>":     true,
-		"-->>":    true,
-		"-x":      true,
-		"--x":     true,
-		"-)":      true,
-		"--)":     true,
-		"<<->>":   true,
-		"<<-->>":  true,
+	validArrows := make(map[string]bool, len(ast.MessageArrows))
+	for _, arrow := range ast.MessageArrows {
+		validArrows[arrow] = true
 	}
 
 	r.checkArrows(diagram.Statements, validArrows, &errors)
@@ -209,8 +204,9 @@ func (r *ValidMessageArrows) checkArrows(statements []ast.SeqStmt, validArrows m
 				*errors = append(*errors, ValidationError{
 					Line:     s.Pos.Line,
 					Column:   s.Pos.Column,
-					Message:  fmt.Sprintf("invalid message arrow '%s'", s.Arrow),
-					Severity: SeverityError,
+					Message:    fmt.Sprintf("invalid message arrow '%s'", s.Arrow),
+					Severity:   SeverityError,
+					Suggestion: "use one of " + strings.Join(ast.MessageArrows, ", "),
 				})
 			}
 
@@ -351,6 +347,336 @@ func (r *ValidNotePositions) checkNotes(statements []ast.SeqStmt, participants m
 	}
 }
 
+// ValidAutonumber checks autonumber arguments: none, "off", or an integer
+// start number optionally followed by a positive integer step.
+type ValidAutonumber struct{}
+
+// Name returns the name of this validation rule.
+func (r *ValidAutonumber) Name() string { return "valid-autonumber" }
+
+// ValidateSequence checks every autonumber directive, including those nested in blocks.
+func (r *ValidAutonumber) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	var errors []ValidationError
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		autonumber, ok := stmt.(*ast.Autonumber)
+		if !ok {
+			return
+		}
+		if message := autonumberArgsError(autonumber.Args); message != "" {
+			errors = append(errors, ValidationError{
+				Line:     autonumber.Pos.Line,
+				Column:   autonumber.Pos.Column,
+				Message:  message,
+				Severity: SeverityError,
+			})
+		}
+	})
+	return errors
+}
+
+// autonumberArgsError describes what is wrong with autonumber arguments, or
+// returns "" if they are valid.
+func autonumberArgsError(args []string) string {
+	if len(args) > 0 && strings.EqualFold(args[0], "off") {
+		if len(args) > 1 {
+			return "autonumber off takes no further arguments"
+		}
+		return ""
+	}
+	if len(args) > 2 {
+		return fmt.Sprintf("autonumber takes at most a start and a step, got %d arguments", len(args))
+	}
+	if len(args) > 0 {
+		if _, err := strconv.Atoi(args[0]); err != nil {
+			return fmt.Sprintf("autonumber start '%s' is not an integer", args[0])
+		}
+	}
+	if len(args) > 1 {
+		step, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Sprintf("autonumber step '%s' is not an integer", args[1])
+		}
+		if step <= 0 {
+			return fmt.Sprintf("autonumber step must be positive, got %d", step)
+		}
+	}
+	return ""
+}
+
+// ValidBoxes checks that box colours are CSS colours mermaid understands and
+// that every box groups at least one participant.
+type ValidBoxes struct{}
+
+// Name returns the name of this validation rule.
+func (r *ValidBoxes) Name() string { return "valid-boxes" }
+
+// ValidateSequence checks every box in the diagram.
+func (r *ValidBoxes) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	var errors []ValidationError
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		box, ok := stmt.(*ast.Box)
+		if !ok {
+			return
+		}
+		if box.Colour != "" && !isValidColour(box.Colour) {
+			errors = append(errors, ValidationError{
+				Line:     box.Pos.Line,
+				Column:   box.Pos.Column,
+				Message:  fmt.Sprintf("box colour '%s' is not a CSS colour name, hex code, colour function or 'transparent', so mermaid will show it as part of the label", box.Colour),
+				Severity: SeverityWarning,
+			})
+		}
+		if len(box.Participants) == 0 {
+			errors = append(errors, ValidationError{
+				Line:     box.Pos.Line,
+				Column:   box.Pos.Column,
+				Message:  "box contains no participants",
+				Severity: SeverityWarning,
+			})
+		}
+	})
+	return errors
+}
+
+// UnusedParticipants warns about participants that are declared but never
+// take part in a message, note or activation, which are usually leftovers
+// from commented-out messages.
+type UnusedParticipants struct{}
+
+// Name returns the name of this validation rule.
+func (r *UnusedParticipants) Name() string { return "unused-participants" }
+
+// ValidateSequence checks that every declared participant is used.
+func (r *UnusedParticipants) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	used := make(map[string]bool)
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		for _, id := range participantsUsedBy(stmt) {
+			used[id] = true
+		}
+	})
+
+	var errors []ValidationError
+	for _, participant := range declaredParticipants(diagram.Statements) {
+		if !used[participant.ID] {
+			errors = append(errors, ValidationError{
+				Line:     participant.Pos.Line,
+				Column:   participant.Pos.Column,
+				Message:  fmt.Sprintf("participant '%s' is declared but never used in a message, note or activation", participant.ID),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
+
+// ParticipantAliasShadowing warns about participants whose alias is another
+// participant's ID, as in `participant A as B` alongside participant B. The
+// diagram then shows two lifelines labelled B, and messages to B go to the one
+// that isn't aliased.
+type ParticipantAliasShadowing struct{}
+
+// Name returns the name of this validation rule.
+func (r *ParticipantAliasShadowing) Name() string { return "participant-alias-shadowing" }
+
+// ValidateSequence checks that no alias matches the ID of another
+// participant, whether declared or created by being used.
+func (r *ParticipantAliasShadowing) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	ids := make(map[string]bool)
+	for _, participant := range declaredParticipants(diagram.Statements) {
+		ids[participant.ID] = true
+	}
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		for _, id := range participantsUsedBy(stmt) {
+			ids[id] = true
+		}
+	})
+
+	var errors []ValidationError
+	for _, participant := range declaredParticipants(diagram.Statements) {
+		if participant.Alias == "" || participant.Alias == participant.ID || !ids[participant.Alias] {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:       participant.Pos.Line,
+			Column:     participant.Pos.Column,
+			Message:    fmt.Sprintf("participant '%s' is aliased as '%s', which is the ID of another participant", participant.ID, participant.Alias),
+			Severity:   SeverityWarning,
+			Suggestion: "choose an alias that is not a participant ID",
+		})
+	}
+	return errors
+}
+
+// ParticipantsDeclaredFirst is an opt-in style rule requiring every
+// participant to be declared before the first message, so the order of the
+// lifelines can be read from the top of the diagram. It reports declarations
+// after the first message and participants that are only created implicitly
+// by being used.
+type ParticipantsDeclaredFirst struct{}
+
+// Name returns the name of this validation rule.
+func (r *ParticipantsDeclaredFirst) Name() string { return "participants-declared-first" }
+
+// ValidateDiagram applies the rule to sequence diagrams, ignoring other types.
+func (r *ParticipantsDeclaredFirst) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	if d, ok := diagram.(*ast.SequenceDiagram); ok {
+		return r.ValidateSequence(d)
+	}
+	return nil
+}
+
+// ValidateSequence checks that participants are declared before any message.
+func (r *ParticipantsDeclaredFirst) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	declared := make(map[string]bool)
+	for _, participant := range declaredParticipants(diagram.Statements) {
+		declared[participant.ID] = true
+	}
+
+	var errors []ValidationError
+	var firstMessage *ast.Message
+	reported := make(map[string]bool)
+	walkSequence(diagram.Statements, func(stmt ast.SeqStmt) {
+		if message, ok := stmt.(*ast.Message); ok && firstMessage == nil {
+			firstMessage = message
+		}
+		for _, id := range participantsUsedBy(stmt) {
+			if !declared[id] && !reported[id] {
+				reported[id] = true
+				pos := stmt.GetPosition()
+				errors = append(errors, ValidationError{
+					Line:     pos.Line,
+					Column:   pos.Column,
+					Message:  fmt.Sprintf("participant '%s' is used without being declared; declare it before the first message", id),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	})
+	if firstMessage == nil {
+		return errors
+	}
+
+	for _, participant := range declaredParticipants(diagram.Statements) {
+		if participant.Pos.Line > firstMessage.Pos.Line {
+			errors = append(errors, ValidationError{
+				Line:     participant.Pos.Line,
+				Column:   participant.Pos.Column,
+				Message:  fmt.Sprintf("participant '%s' is declared after the first message (line %d); declare all participants before it", participant.ID, firstMessage.Pos.Line),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
+
+// declaredParticipants returns the participants declared in statements,
+// including those grouped in boxes, in source order.
+func declaredParticipants(statements []ast.SeqStmt) []*ast.Participant {
+	var participants []*ast.Participant
+	walkSequence(statements, func(stmt ast.SeqStmt) {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			participants = append(participants, s)
+		case *ast.Box:
+			for i := range s.Participants {
+				participants = append(participants, &s.Participants[i])
+			}
+		}
+	})
+	return participants
+}
+
+// participantsUsedBy returns the IDs of the participants a message, note or
+// activation refers to.
+func participantsUsedBy(stmt ast.SeqStmt) []string {
+	switch s := stmt.(type) {
+	case *ast.Message:
+		return []string{s.From, s.To}
+	case *ast.Note:
+		return s.Participants
+	case *ast.Activation:
+		return []string{s.Participant}
+	}
+	return nil
+}
+
+// ConditionalParticipants is a strict rule noting participants whose only
+// messages are inside alt or opt branches, which may never run. Such a
+// participant takes no part in the main scenario, which reviewers of API
+// interaction documents often want to know.
+type ConditionalParticipants struct{}
+
+// Name returns the name of this validation rule.
+func (r *ConditionalParticipants) Name() string { return "conditional-participants" }
+
+// ValidateSequence checks that every participant exchanges a message outside
+// alt and opt branches.
+func (r *ConditionalParticipants) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	var errors []ValidationError
+	for _, trace := range analysis.SequenceTrace(diagram) {
+		if !trace.ConditionalOnly() {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:     trace.Pos.Line,
+			Column:   trace.Pos.Column,
+			Message:  fmt.Sprintf("participant '%s' only takes part in messages inside alt or opt branches, which may never run", trace.Participant),
+			Severity: SeverityInfo,
+		})
+	}
+	return errors
+}
+
+// DefaultMaxParticipants is the largest number of participants accepted by
+// MaxParticipants.
+const DefaultMaxParticipants = 10
+
+// MaxParticipants warns about sequence diagrams with more than Max
+// participants, which grow too wide to read and are often better split into
+// several diagrams. Max defaults to DefaultMaxParticipants.
+type MaxParticipants struct {
+	Max int
+}
+
+// Name returns the name of this validation rule.
+func (r *MaxParticipants) Name() string { return "max-participants" }
+
+// Options returns the participant limit, which can be configured.
+func (r *MaxParticipants) Options() []Option {
+	return []Option{{Name: "max", Type: OptionInt, Description: "Most participants in a sequence diagram", Default: DefaultMaxParticipants}}
+}
+
+// Configure sets Max from options.
+func (r *MaxParticipants) Configure(options RuleOptions) error {
+	if value, ok := options["max"]; ok {
+		if value.(int) <= 0 {
+			return fmt.Errorf("max must be positive, got %d", value)
+		}
+		r.Max = value.(int)
+	}
+	return nil
+}
+
+// ValidateSequence counts the participants declared or used in diagram,
+// reporting the first one over the limit.
+func (r *MaxParticipants) ValidateSequence(diagram *ast.SequenceDiagram) []ValidationError {
+	limit := r.Max
+	if limit <= 0 {
+		limit = DefaultMaxParticipants
+	}
+	traces := analysis.SequenceTrace(diagram)
+	if len(traces) <= limit {
+		return nil
+	}
+	first := traces[limit]
+	return []ValidationError{{
+		Line:     first.Pos.Line,
+		Column:   first.Pos.Column,
+		Message:  fmt.Sprintf("diagram has %d participants (limit %d); consider splitting it", len(traces), limit),
+		Severity: SeverityWarning,
+	}}
+}
+
 // SequenceDefaultRules returns default validation rules for sequence diagrams.
 func SequenceDefaultRules() []SequenceRule {
 	return []SequenceRule{
@@ -358,10 +684,15 @@ func SequenceDefaultRules() []SequenceRule {
 		&NoDuplicateParticipants{},
 		&ValidMessageArrows{},
 		&ValidNotePositions{},
+		&ValidAutonumber{},
+		&ValidBoxes{},
+		&UnusedParticipants{},
+		&ParticipantAliasShadowing{},
+		&UnknownEntities{},
 	}
 }
 
 // SequenceStrictRules returns strict validation rules for sequence diagrams.
 func SequenceStrictRules() []SequenceRule {
-	return SequenceDefaultRules()
+	return append(SequenceDefaultRules(), &LabelLength{}, &ConditionalParticipants{})
 }
@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// SkippedLines reports, as information, the lines the parser didn't recognise
+// and left out of the AST, so that nothing on them was validated. Flowchart,
+// class and state diagram parsers skip such lines rather than failing.
+type SkippedLines struct{}
+
+// Name returns the name of this validation rule.
+func (r *SkippedLines) Name() string { return "skipped-lines" }
+
+// ValidateDiagram reports the lines skipped while parsing diagram.
+func (r *SkippedLines) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	skipper, ok := diagram.(interface{ GetSkippedLines() []ast.SkippedLine })
+	if !ok {
+		return nil
+	}
+	var errors []ValidationError
+	for _, skipped := range skipper.GetSkippedLines() {
+		errors = append(errors, ValidationError{
+			Line:     skipped.Line,
+			Column:   1,
+			Message:  fmt.Sprintf("line was skipped and not validated (%s): %s", skipped.Reason, skipped.Content),
+			Severity: SeverityInfo,
+		})
+	}
+	return errors
+}
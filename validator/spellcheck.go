@@ -0,0 +1,132 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// SpellChecker decides whether a word is spelt correctly. Implement it to plug
+// in an external checker such as hunspell; WordlistChecker covers the common
+// case of a plain dictionary file.
+type SpellChecker interface {
+	// Correct reports whether word is spelt correctly.
+	Correct(word string) bool
+}
+
+// WordlistChecker is a SpellChecker that accepts words from a fixed list,
+// ignoring case.
+type WordlistChecker struct {
+	words map[string]bool
+}
+
+// NewWordlistChecker returns a WordlistChecker accepting words.
+func NewWordlistChecker(words ...string) *WordlistChecker {
+	c := &WordlistChecker{words: make(map[string]bool, len(words))}
+	c.Add(words...)
+	return c
+}
+
+// Add adds words to the list of accepted words.
+func (c *WordlistChecker) Add(words ...string) {
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			c.words[strings.ToLower(word)] = true
+		}
+	}
+}
+
+// AddFile adds every word in the file at path, one per line. Blank lines and
+// lines starting with # are ignored.
+func (c *WordlistChecker) AddFile(path string) error {
+	f, err := os.Open(path) //nolint:gosec // User-provided dictionary path is intentional
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	return c.AddReader(f)
+}
+
+// AddReader adds every word read from r, in the same format as AddFile. It
+// suits environments without a file system, such as WebAssembly.
+func (c *WordlistChecker) AddReader(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		c.Add(line)
+	}
+	return scanner.Err()
+}
+
+// Correct reports whether word, or its lower-case form, is in the list.
+func (c *WordlistChecker) Correct(word string) bool {
+	return c.words[strings.ToLower(word)]
+}
+
+// SpellCheck reports misspelt words in human-readable diagram text: flowchart
+// node and edge labels, and sequence diagram participant names and message
+// text. Words containing digits, all-capitals acronyms and single letters are
+// skipped. It is opt-in and needs a SpellChecker.
+type SpellCheck struct {
+	Checker SpellChecker
+}
+
+// Name returns the name of this validation rule.
+func (r *SpellCheck) Name() string { return "spell-check" }
+
+// ValidateDiagram spell-checks the text of flowcharts and sequence diagrams.
+// Other diagram types are not checked.
+func (r *SpellCheck) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	if r.Checker == nil {
+		return nil
+	}
+
+	switch diagram.(type) {
+	case *ast.Flowchart, *ast.SequenceDiagram:
+	default:
+		return nil
+	}
+
+	source, fields := diagramText(diagram)
+	lines := strings.Split(source, "\n")
+	var errors []ValidationError
+	for _, field := range fields {
+		for _, word := range words(field.text) {
+			if r.Checker.Correct(word) {
+				continue
+			}
+			errors = append(errors, ValidationError{
+				Line:     field.line,
+				Column:   wordColumn(lines, field.line, word),
+				Message:  fmt.Sprintf("possible misspelling '%s'", word),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
+
+// words splits text into the words worth spell-checking.
+func words(text string) []string {
+	var result []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		word = strings.Trim(word, "'")
+		if utf8.RuneCountInString(word) < 2 || strings.IndexFunc(word, unicode.IsDigit) >= 0 || strings.ToUpper(word) == word {
+			continue
+		}
+		result = append(result, word)
+	}
+	return result
+}
@@ -83,6 +83,75 @@ func (r *ValidStateReferences) ValidateState(diagram *ast.StateDiagram) []Valida
 	return errors
 }
 
+// NoEmptyTransitionLabels checks that transitions with a ":" separator have a label.
+type NoEmptyTransitionLabels struct{}
+
+// Name returns the rule name.
+func (r *NoEmptyTransitionLabels) Name() string {
+	return "no-empty-transition-labels"
+}
+
+// ValidateState validates the state diagram.
+func (r *NoEmptyTransitionLabels) ValidateState(diagram *ast.StateDiagram) []ValidationError {
+	var errors []ValidationError
+
+	for _, trans := range collectTransitions(diagram.Statements, nil) {
+		if trans.HasLabel && trans.Label == "" {
+			errors = append(errors, ValidationError{
+				Line:     trans.Pos.Line,
+				Column:   trans.Pos.Column,
+				Message:  fmt.Sprintf("transition from %q to %q has an empty label", trans.From, trans.To),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+
+	return errors
+}
+
+// NoDuplicateTransitions checks for transitions repeated with the same states and label.
+type NoDuplicateTransitions struct{}
+
+// Name returns the rule name.
+func (r *NoDuplicateTransitions) Name() string {
+	return "no-duplicate-transitions"
+}
+
+// ValidateState validates the state diagram.
+func (r *NoDuplicateTransitions) ValidateState(diagram *ast.StateDiagram) []ValidationError {
+	var errors []ValidationError
+	seen := make(map[[3]string]ast.Position)
+
+	for _, trans := range collectTransitions(diagram.Statements, nil) {
+		key := [3]string{trans.From, trans.To, trans.Label}
+		if pos, exists := seen[key]; exists {
+			errors = append(errors, ValidationError{
+				Line:     trans.Pos.Line,
+				Column:   trans.Pos.Column,
+				Message:  fmt.Sprintf("duplicate transition from %q to %q (first defined at line %d)", trans.From, trans.To, pos.Line),
+				Severity: SeverityWarning,
+			})
+		} else {
+			seen[key] = trans.Pos
+		}
+	}
+
+	return errors
+}
+
+// collectTransitions returns every transition, including those inside composite states.
+func collectTransitions(statements []ast.StateStmt, transitions []*ast.Transition) []*ast.Transition {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Transition:
+			transitions = append(transitions, s)
+		case *ast.State:
+			transitions = collectTransitions(s.Nested, transitions)
+		}
+	}
+	return transitions
+}
+
 // StateDefaultRules returns the default set of validation rules for state diagrams.
 func StateDefaultRules() []StateRule {
 	return []StateRule{
@@ -93,7 +162,10 @@ func StateDefaultRules() []StateRule {
 
 // StateStrictRules returns a strict set of validation rules for state diagrams.
 func StateStrictRules() []StateRule {
-	return StateDefaultRules()
+	return append(StateDefaultRules(),
+		&NoEmptyTransitionLabels{},
+		&NoDuplicateTransitions{},
+	)
 }
 
 // NewState creates a new state diagram validator with the given rules.
@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/sammcj/mermaid-check/analysis"
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// SubgraphMembership warns about a flowchart node mentioned in two subgraphs
+// where neither lies within the other. Mermaid draws a node in only one
+// subgraph (see analysis.SubgraphOf), so the other loses it without warning.
+// Mentions outside every subgraph, such as links between subgraphs, are fine.
+type SubgraphMembership struct{}
+
+// Name returns the name of this validation rule.
+func (r *SubgraphMembership) Name() string { return "subgraph-membership" }
+
+// Validate checks every node mention against the subgraph the node is drawn in.
+func (r *SubgraphMembership) Validate(flowchart *ast.Flowchart) []ValidationError {
+	membership := analysis.SubgraphOf(flowchart)
+	reported := make(map[string]bool)
+	var errors []ValidationError
+	for _, mention := range analysis.Mentions(flowchart) {
+		drawn := membership[mention.Node]
+		if len(mention.Subgraphs) == 0 || analysis.Nested(mention.Subgraphs, drawn) {
+			continue
+		}
+		subgraph := mention.Subgraphs[len(mention.Subgraphs)-1]
+		if key := mention.Node + "\x00" + subgraph; !reported[key] {
+			reported[key] = true
+			errors = append(errors, ValidationError{
+				Line:     mention.Pos.Line,
+				Column:   mention.Pos.Column,
+				Message:  fmt.Sprintf("node '%s' is used in subgraph '%s' but Mermaid draws it in subgraph '%s'; define it in one subgraph and link to it from the other", mention.Node, subgraph, drawn[len(drawn)-1]),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// Term is a word or phrase to avoid in diagram text, with an optional
+// preferred replacement.
+type Term struct {
+	Avoid  string // Word or phrase to flag, matched as whole words ignoring case
+	Prefer string // Suggested replacement (optional)
+}
+
+// Terminology enforces a project's preferred terms (for example "Database"
+// rather than "DB", or British spellings) across the human-readable text of
+// every diagram type.
+type Terminology struct {
+	Terms []Term
+}
+
+// Name returns the name of this validation rule.
+func (r *Terminology) Name() string { return "terminology" }
+
+// Options returns the terms to avoid, which can be configured without
+// replacements.
+func (r *Terminology) Options() []Option {
+	return []Option{{Name: "avoid", Type: OptionStringList, Description: "Words or phrases to flag"}}
+}
+
+// Configure adds the terms given in options.
+func (r *Terminology) Configure(options RuleOptions) error {
+	if value, ok := options["avoid"]; ok {
+		for _, avoid := range value.([]string) {
+			r.Terms = append(r.Terms, Term{Avoid: avoid})
+		}
+	}
+	return nil
+}
+
+// ValidateDiagram reports every occurrence of an avoided term.
+func (r *Terminology) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	if len(r.Terms) == 0 {
+		return nil
+	}
+
+	patterns := make([]*regexp.Regexp, len(r.Terms))
+	for i, term := range r.Terms {
+		if strings.TrimSpace(term.Avoid) != "" {
+			patterns[i] = termPattern(term.Avoid)
+		}
+	}
+
+	source, fields := diagramText(diagram)
+	lines := strings.Split(source, "\n")
+	var errors []ValidationError
+	for _, field := range fields {
+		for i, term := range r.Terms {
+			if patterns[i] == nil {
+				continue
+			}
+			for _, match := range patterns[i].FindAllStringSubmatch(field.text, -1) {
+				found := match[1]
+				message := fmt.Sprintf("avoid '%s'", found)
+				if term.Prefer != "" {
+					message = fmt.Sprintf("use '%s' instead of '%s'", term.Prefer, found)
+				}
+				errors = append(errors, ValidationError{
+					Line:     field.line,
+					Column:   wordColumn(lines, field.line, found),
+					Message:  message,
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+	return errors
+}
+
+// termPattern matches phrase as whole words, ignoring case and treating any run
+// of whitespace in phrase as matching any run of whitespace in the text.
+func termPattern(phrase string) *regexp.Regexp {
+	parts := strings.Fields(phrase)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN_])(` + strings.Join(parts, `\s+`) + `)(?:$|[^\pL\pN_])`)
+}
@@ -0,0 +1,62 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestMixedDirectionText(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		errorCount int
+		fix        string
+	}{
+		{
+			name:   "left-to-right only",
+			source: "flowchart LR\n    A[Submit order] --> B",
+		},
+		{
+			name:   "right-to-left only",
+			source: "flowchart LR\n    A[إرسال الطلب] --> B",
+		},
+		{
+			name:   "already isolated",
+			source: "flowchart LR\n    A[Send \u2067إرسال\u2069 now] --> B",
+		},
+		{
+			name:       "right-to-left words in English label",
+			source:     "flowchart LR\n    A[Send إرسال الطلب now] --> B",
+			errorCount: 1,
+			fix:        "    A[Send \u2067إرسال الطلب\u2069 now] --> B",
+		},
+		{
+			name:       "English word in Hebrew link label",
+			source:     "flowchart LR\n    A -->|שלח API עכשיו| B",
+			errorCount: 1,
+			fix:        "    A -->|שלח \u2066API\u2069 עכשיו| B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := (&validator.MixedDirectionText{}).Validate(diagram.(*ast.Flowchart))
+			if len(errors) != tt.errorCount {
+				t.Fatalf("expected %d errors, got %d: %v", tt.errorCount, len(errors), errors)
+			}
+			if tt.errorCount == 0 {
+				return
+			}
+			if errors[0].Fix == nil || errors[0].Fix.Replacement != tt.fix {
+				t.Errorf("fix = %+v, want %q", errors[0].Fix, tt.fix)
+			}
+		})
+	}
+}
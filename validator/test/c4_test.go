@@ -1,6 +1,7 @@
 package validator_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -337,6 +338,279 @@ func TestValidStyleReferencesRule(t *testing.T) {
 	}
 }
 
+func TestC4RelationshipOrderRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		diagram   *ast.C4Diagram
+		wantCount int
+	}{
+		{
+			name: "relationships after their endpoints",
+			diagram: &ast.C4Diagram{
+				Elements: []ast.C4Element{
+					{ID: "elem1", Pos: ast.Position{Line: 2}},
+					{ID: "elem2", Pos: ast.Position{Line: 3}},
+				},
+				Relationships: []ast.C4Relationship{
+					{From: "elem1", To: "elem2", Pos: ast.Position{Line: 4}},
+				},
+			},
+			wantCount: 0,
+		},
+		{
+			name: "relationship before its target",
+			diagram: &ast.C4Diagram{
+				Elements: []ast.C4Element{
+					{ID: "elem1", Pos: ast.Position{Line: 2}},
+					{ID: "elem2", Pos: ast.Position{Line: 4}},
+				},
+				Relationships: []ast.C4Relationship{
+					{From: "elem1", To: "elem2", Pos: ast.Position{Line: 3}},
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "relationship before both endpoints in a boundary",
+			diagram: &ast.C4Diagram{
+				Boundaries: []ast.C4Boundary{
+					{
+						ID:  "b1",
+						Pos: ast.Position{Line: 3},
+						Elements: []ast.C4Element{
+							{ID: "elem1", Pos: ast.Position{Line: 4}},
+							{ID: "elem2", Pos: ast.Position{Line: 5}},
+						},
+					},
+				},
+				Relationships: []ast.C4Relationship{
+					{From: "elem1", To: "elem2", Pos: ast.Position{Line: 2}},
+				},
+			},
+			wantCount: 2,
+		},
+		{
+			name: "undefined endpoints are ignored",
+			diagram: &ast.C4Diagram{
+				Relationships: []ast.C4Relationship{
+					{From: "missing", To: "missing", Pos: ast.Position{Line: 2}},
+				},
+			},
+			wantCount: 0,
+		},
+	}
+
+	rule := &validator.C4RelationshipOrderRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := rule.Validate(tt.diagram)
+			if len(errors) != tt.wantCount {
+				t.Errorf("expected %d errors, got %d", tt.wantCount, len(errors))
+				for _, err := range errors {
+					t.Logf("  error at line %d: %s", err.Line, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestC4RelationshipDirectionRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		relationships []ast.C4Relationship
+		wantLines     []int
+	}{
+		{
+			name: "opposite directions with different labels",
+			relationships: []ast.C4Relationship{
+				{RelType: "Rel", From: "a", To: "b", Label: "Requests", Pos: ast.Position{Line: 2}},
+				{RelType: "Rel", From: "b", To: "a", Label: "Responds", Pos: ast.Position{Line: 3}},
+			},
+		},
+		{
+			name: "opposite directions with the same label",
+			relationships: []ast.C4Relationship{
+				{RelType: "Rel", From: "a", To: "b", Label: "Syncs", Pos: ast.Position{Line: 2}},
+				{RelType: "Rel_Back", From: "b", To: "a", Label: "Syncs", Pos: ast.Position{Line: 3}},
+			},
+			wantLines: []int{3},
+		},
+		{
+			name: "same direction is left to the duplicate rule",
+			relationships: []ast.C4Relationship{
+				{RelType: "Rel", From: "a", To: "b", Label: "Syncs", Pos: ast.Position{Line: 2}},
+				{RelType: "BiRel", From: "a", To: "b", Label: "Syncs", Pos: ast.Position{Line: 3}},
+			},
+		},
+		{
+			name: "BiRel repeated as a reversed Rel",
+			relationships: []ast.C4Relationship{
+				{RelType: "BiRel", From: "a", To: "b", Label: "Syncs", Pos: ast.Position{Line: 2}},
+				{RelType: "Rel", From: "b", To: "a", Label: "Syncs", Pos: ast.Position{Line: 3}},
+			},
+			wantLines: []int{3},
+		},
+		{
+			name: "BiRel repeated in reverse",
+			relationships: []ast.C4Relationship{
+				{RelType: "BiRel", From: "a", To: "b", Label: "Syncs", Pos: ast.Position{Line: 2}},
+				{RelType: "Rel", From: "c", To: "a", Label: "Syncs", Pos: ast.Position{Line: 3}},
+				{RelType: "BiRel", From: "b", To: "a", Label: "Syncs", Pos: ast.Position{Line: 4}},
+				{RelType: "Rel", From: "a", To: "b", Label: "Syncs", Pos: ast.Position{Line: 5}},
+			},
+			wantLines: []int{4, 5},
+		},
+		{
+			name: "self-loops are ignored",
+			relationships: []ast.C4Relationship{
+				{RelType: "Rel", From: "a", To: "a", Label: "Retries", Pos: ast.Position{Line: 2}},
+				{RelType: "Rel", From: "a", To: "a", Label: "Retries", Pos: ast.Position{Line: 3}},
+			},
+		},
+	}
+
+	rule := &validator.C4RelationshipDirectionRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := rule.Validate(&ast.C4Diagram{Relationships: tt.relationships})
+			var lines []int
+			for _, err := range errors {
+				lines = append(lines, err.Line)
+				if err.Severity != validator.SeverityWarning {
+					t.Errorf("line %d: severity = %v, want warning", err.Line, err.Severity)
+				}
+			}
+			if !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", lines, tt.wantLines)
+				for _, err := range errors {
+					t.Logf("  error at line %d: %s", err.Line, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestC4SemanticRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		diagram   *ast.C4Diagram
+		wantCount int
+	}{
+		{
+			name: "containers inside a system boundary",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Container",
+				Elements: []ast.C4Element{
+					{ElementType: "Person", ID: "user"},
+					{ElementType: "Container", ID: "ext", External: true},
+				},
+				Boundaries: []ast.C4Boundary{
+					{
+						BoundaryType: "System_Boundary",
+						ID:           "sys",
+						Boundaries: []ast.C4Boundary{
+							{BoundaryType: "Boundary", ID: "inner", Elements: []ast.C4Element{{ElementType: "Container", ID: "api"}}},
+						},
+					},
+					{BoundaryType: "Container_Boundary", ID: "c1", Elements: []ast.C4Element{{ElementType: "Container", ID: "web"}}},
+				},
+			},
+			wantCount: 0,
+		},
+		{
+			name: "containers outside a system boundary",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Container",
+				Elements:    []ast.C4Element{{ElementType: "Container", ID: "api", Pos: ast.Position{Line: 2}}},
+				Boundaries: []ast.C4Boundary{
+					{BoundaryType: "Enterprise_Boundary", ID: "ent", Elements: []ast.C4Element{{ElementType: "Container", ID: "web"}}},
+				},
+			},
+			wantCount: 2,
+		},
+		{
+			name: "components outside a container boundary",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Component",
+				Elements:    []ast.C4Element{{ElementType: "Container", ID: "spa"}},
+				Boundaries: []ast.C4Boundary{
+					{BoundaryType: "Container_Boundary", ID: "api", Elements: []ast.C4Element{{ElementType: "Component", ID: "sign"}}},
+					{BoundaryType: "System_Boundary", ID: "sys", Elements: []ast.C4Element{{ElementType: "Component", ID: "stray"}}},
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name: "deployment nodes outside a deployment diagram",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Container",
+				Boundaries: []ast.C4Boundary{
+					{
+						BoundaryType: "Deployment_Node",
+						ID:           "dn",
+						Boundaries: []ast.C4Boundary{
+							{BoundaryType: "System_Boundary", ID: "sys", Elements: []ast.C4Element{{ElementType: "Node", ID: "n"}}},
+						},
+					},
+				},
+			},
+			wantCount: 2,
+		},
+		{
+			name: "deployment nodes in a deployment diagram",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Deployment",
+				Elements:    []ast.C4Element{{ElementType: "Container", ID: "api"}},
+				Boundaries:  []ast.C4Boundary{{BoundaryType: "Deployment_Node", ID: "dn", Elements: []ast.C4Element{{ElementType: "Node", ID: "n"}}}},
+			},
+			wantCount: 0,
+		},
+		{
+			name: "dynamic relationships numbered",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Dynamic",
+				Relationships: []ast.C4Relationship{
+					{RelType: "Rel", Label: "1. Login request"},
+					{RelType: "Rel", Label: "2.1: Verify credentials"},
+					{RelType: "RelIndex", Index: "3", Label: "Return result"},
+				},
+			},
+			wantCount: 0,
+		},
+		{
+			name: "dynamic relationships unnumbered",
+			diagram: &ast.C4Diagram{
+				DiagramType: "c4Dynamic",
+				Relationships: []ast.C4Relationship{
+					{RelType: "Rel", Label: "Login request"},
+					{RelType: "Rel", Label: "2FA challenge"},
+				},
+			},
+			wantCount: 2,
+		},
+		{
+			name: "unnumbered relationships outside a dynamic diagram",
+			diagram: &ast.C4Diagram{
+				DiagramType:   "c4Context",
+				Relationships: []ast.C4Relationship{{RelType: "Rel", Label: "Uses"}},
+			},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateC4(tt.diagram, validator.C4SemanticRules())
+			if len(errors) != tt.wantCount {
+				t.Errorf("expected %d errors, got %d", tt.wantCount, len(errors))
+				for _, err := range errors {
+					t.Logf("  error at line %d: %s", err.Line, err.Message)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateC4(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -390,14 +664,14 @@ func TestValidateC4(t *testing.T) {
 
 func TestDefaultC4Rules(t *testing.T) {
 	rules := validator.DefaultC4Rules()
-	if len(rules) != 4 {
-		t.Errorf("expected 4 default rules, got %d", len(rules))
+	if len(rules) != 5 {
+		t.Errorf("expected 5 default rules, got %d", len(rules))
 	}
 }
 
 func TestStrictC4Rules(t *testing.T) {
 	rules := validator.StrictC4Rules()
-	if len(rules) != 4 {
-		t.Errorf("expected 4 strict rules, got %d", len(rules))
+	if len(rules) != 12 {
+		t.Errorf("expected 12 strict rules, got %d", len(rules))
 	}
 }
@@ -240,15 +240,15 @@ func TestValidRelationshipType(t *testing.T) {
 
 func TestClassDefaultRules(t *testing.T) {
 	rules := validator.ClassDefaultRules()
-	if len(rules) != 4 {
-		t.Errorf("ClassDefaultRules() returned %d rules, want 4", len(rules))
+	if len(rules) != 5 {
+		t.Errorf("ClassDefaultRules() returned %d rules, want 5", len(rules))
 	}
 }
 
 func TestClassStrictRules(t *testing.T) {
 	rules := validator.ClassStrictRules()
-	if len(rules) != 4 {
-		t.Errorf("ClassStrictRules() returned %d rules, want 4", len(rules))
+	if len(rules) != 6 {
+		t.Errorf("ClassStrictRules() returned %d rules, want 6", len(rules))
 	}
 }
 
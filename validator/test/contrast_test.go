@@ -0,0 +1,85 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestColourContrast(t *testing.T) {
+	rule := &validator.ColourContrast{}
+	if rule.Name() != "colour-contrast" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "colour-contrast")
+	}
+
+	tests := []struct {
+		name       string
+		styles     string
+		errorCount int
+	}{
+		{"black on white", "classDef ok fill:#fff,color:#000", 0},
+		{"light grey on white", "classDef faint fill:#ffffff,color:#aaaaaa", 1},
+		{"named colours", "classDef warn fill:yellow,color:white", 1},
+		{"rgb function", "classDef dark fill:rgb(20, 20, 20),color:rgba(255,255,255,0.9)", 0},
+		{"fill only against default text", "classDef dark fill:#222", 1},
+		{"text only against default fill", "classDef pale color:#ddd", 1},
+		{"style statement", "style A fill:#0000ff,color:#000080", 1},
+		{"unparseable colour skipped", "classDef themed fill:var(--bg),color:#eee", 0},
+		{"stroke only", "classDef outlined stroke:#f00", 0},
+		{"important suffix and short hex with alpha", "classDef x fill:#000f !important,color:#fff", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse("flowchart TD\n    A --> B\n    " + tt.styles)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != tt.errorCount {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), tt.errorCount, errors)
+			}
+			for _, err := range errors {
+				if err.Line != 3 || err.Severity != validator.SeverityWarning {
+					t.Errorf("unexpected error %+v", err)
+				}
+				if !strings.Contains(err.Message, "WCAG AA") {
+					t.Errorf("message %q should mention WCAG AA", err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestColourContrast_Subgraph(t *testing.T) {
+	diagram, err := parser.Parse("flowchart TD\n    subgraph s1\n    A --> B\n    classDef low fill:#777,color:#888\n    end")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	errors := (&validator.ColourContrast{}).Validate(diagram.(*ast.Flowchart))
+	if len(errors) != 1 || errors[0].Line != 4 {
+		t.Errorf("expected 1 error on line 4, got %v", errors)
+	}
+}
+
+func TestC4ColourContrastRule(t *testing.T) {
+	source := `C4Context
+    System(a, "A")
+    System(b, "B")
+    System(c, "C")
+    UpdateElementStyle(a, $fontColor="white", $bgColor="#1168bd")
+    UpdateElementStyle(b, $fontColor="#999999", $bgColor="#aaaaaa")
+    UpdateElementStyle(c, $bgColor="#aaaaaa")`
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	errors := (&validator.C4ColourContrastRule{}).Validate(diagram.(*ast.C4Diagram))
+	if len(errors) != 1 || errors[0].Line != 6 {
+		t.Errorf("expected 1 error on line 6, got %v", errors)
+	}
+}
@@ -0,0 +1,64 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestAllowedDirections(t *testing.T) {
+	tests := []struct {
+		name       string
+		directions []string
+		source     string
+		wantLines  []int
+	}{
+		{
+			name:       "allowed direction",
+			directions: []string{"LR"},
+			source:     "flowchart LR\n    A --> B",
+		},
+		{
+			name:       "TD and TB are the same",
+			directions: []string{"TD"},
+			source:     "graph TB\n    A --> B",
+		},
+		{
+			name:       "no direction runs top to bottom",
+			directions: []string{"LR"},
+			source:     "flowchart\n    A --> B",
+			wantLines:  []int{1},
+		},
+		{
+			name:       "subgraph direction",
+			directions: []string{"td", "lr"},
+			source:     "flowchart TD\n    subgraph one\n        direction RL\n        A --> B\n    end\n    subgraph two\n        direction LR\n        C\n    end",
+			wantLines:  []int{2},
+		},
+		{
+			name:   "no directions configured",
+			source: "flowchart RL\n    A --> B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			rule := &validator.AllowedDirections{Directions: tt.directions}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantLines), errors)
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] {
+					t.Errorf("error %d on line %d, want %d", i, err.Line, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
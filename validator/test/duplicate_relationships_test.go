@@ -0,0 +1,81 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestNoDuplicateLinks(t *testing.T) {
+	flowchart := &ast.Flowchart{
+		Direction: "TD",
+		Statements: []ast.Statement{
+			&ast.Link{From: "A", To: "B", Arrow: "-->", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.Link{From: "A", To: "B", Arrow: "-->", Label: "retry", Pos: ast.Position{Line: 3, Column: 1}},
+			&ast.Link{From: "A", To: "B", Arrow: "-.->", Pos: ast.Position{Line: 4, Column: 1}},
+			&ast.Subgraph{
+				ID:    "sub",
+				Title: "sub",
+				Statements: []ast.Statement{
+					&ast.Link{From: "A", To: "B", Arrow: "-->", Pos: ast.Position{Line: 6, Column: 1}},
+				},
+				Pos: ast.Position{Line: 5, Column: 1},
+			},
+		},
+	}
+
+	errors := (&validator.NoDuplicateLinks{}).Validate(flowchart)
+	if len(errors) != 1 || errors[0].Line != 6 {
+		t.Fatalf("expected one error on line 6, got %v", errors)
+	}
+	if errors[0].Severity != validator.SeverityWarning {
+		t.Errorf("expected warning severity, got %v", errors[0].Severity)
+	}
+}
+
+func TestNoDuplicateClassRelationships(t *testing.T) {
+	diagram := &ast.ClassDiagram{
+		Statements: []ast.ClassStmt{
+			&ast.Relationship{From: "Animal", To: "Dog", Type: "inheritance", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.Relationship{From: "Animal", To: "Dog", Type: "composition", Pos: ast.Position{Line: 3, Column: 1}},
+			&ast.Relationship{From: "Animal", To: "Dog", Type: "inheritance", Pos: ast.Position{Line: 4, Column: 1}},
+		},
+	}
+
+	errors := (&validator.NoDuplicateClassRelationships{}).ValidateClass(diagram)
+	if len(errors) != 1 || errors[0].Line != 4 {
+		t.Errorf("expected one error on line 4, got %v", errors)
+	}
+}
+
+func TestNoDuplicateERRelationshipsRule(t *testing.T) {
+	diagram := &ast.ERDiagram{
+		Relationships: []ast.ERRelationship{
+			{From: "CUSTOMER", To: "ORDER", FromCard: "||", ToCard: "o{", Type: "--", Label: "places", Pos: ast.Position{Line: 2, Column: 1}},
+			{From: "CUSTOMER", To: "ORDER", FromCard: "||", ToCard: "o{", Type: "--", Label: "cancels", Pos: ast.Position{Line: 3, Column: 1}},
+			{From: "CUSTOMER", To: "ORDER", FromCard: "||", ToCard: "o{", Type: "--", Label: "places", Pos: ast.Position{Line: 4, Column: 1}},
+		},
+	}
+
+	errors := (&validator.NoDuplicateERRelationshipsRule{}).Validate(diagram)
+	if len(errors) != 1 || errors[0].Line != 4 {
+		t.Errorf("expected one error on line 4, got %v", errors)
+	}
+}
+
+func TestC4NoDuplicateRelationshipsRule(t *testing.T) {
+	diagram := &ast.C4Diagram{
+		Relationships: []ast.C4Relationship{
+			{RelType: "Rel", From: "user", To: "web", Label: "Uses", Pos: ast.Position{Line: 5, Column: 1}},
+			{RelType: "Rel", From: "web", To: "user", Label: "Uses", Pos: ast.Position{Line: 6, Column: 1}},
+			{RelType: "Rel_D", From: "user", To: "web", Label: "Uses", Pos: ast.Position{Line: 7, Column: 1}},
+			{RelType: "Rel", From: "user", To: "web", Label: "Logs in", Pos: ast.Position{Line: 8, Column: 1}},
+		},
+	}
+
+	errors := (&validator.C4NoDuplicateRelationshipsRule{}).Validate(diagram)
+	if len(errors) != 1 || errors[0].Line != 7 {
+		t.Errorf("expected one error on line 7, got %v", errors)
+	}
+}
@@ -0,0 +1,124 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestUnescapedQuotes(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		errorCount int
+		fix        string
+	}{
+		{
+			name:   "quoted label",
+			source: "flowchart TD\n    A[\"Start (here)\"] --> B",
+		},
+		{
+			name:   "escaped quotes",
+			source: "flowchart TD\n    A[\"say #quot;hi#quot; or \\\"bye\\\"\"] --> B",
+		},
+		{
+			name:   "quote in unquoted label",
+			source: "flowchart TD\n    A[6\" pipe] --> B",
+		},
+		{
+			name:       "quote in quoted node label",
+			source:     "flowchart TD\n    A[\"say \"hi\"\"] --> B",
+			errorCount: 1,
+			fix:        "    A[\"say #quot;hi#quot;\"] --> B",
+		},
+		{
+			name:       "quote in quoted link label",
+			source:     "flowchart TD\n    A -->|\"the \"main\" path\"| B",
+			errorCount: 1,
+			fix:        "    A -->|\"the #quot;main#quot; path\"| B",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := (&validator.UnescapedQuotes{}).Validate(diagram.(*ast.Flowchart))
+			if len(errors) != tt.errorCount {
+				t.Fatalf("expected %d errors, got %d: %v", tt.errorCount, len(errors), errors)
+			}
+			if tt.errorCount == 0 {
+				return
+			}
+			if errors[0].Severity != validator.SeverityError || errors[0].Fix == nil {
+				t.Fatalf("expected a fixable error, got %+v", errors[0])
+			}
+			if errors[0].Fix.Replacement != tt.fix {
+				t.Errorf("fix = %q, want %q", errors[0].Fix.Replacement, tt.fix)
+			}
+		})
+	}
+}
+
+func TestUnknownEntities(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		errorCount int
+		column     int
+	}{
+		{
+			name:   "known entities",
+			source: "flowchart TD\n    A[\"#quot;A#quot; &amp; #35;1 &#9829;\"] --> B",
+		},
+		{
+			name:       "unknown entity code",
+			source:     "flowchart TD\n    A[\"say #qout;hi\"] --> B",
+			errorCount: 1,
+			column:     12,
+		},
+		{
+			name:       "unknown HTML entity in link label",
+			source:     "flowchart TD\n    A -->|R&D; team| B",
+			errorCount: 1,
+			column:     12,
+		},
+		{
+			name:   "known entity in message",
+			source: "sequenceDiagram\n    A->>B: total #35;3",
+		},
+		{
+			name:       "unknown entity in message",
+			source:     "sequenceDiagram\n    A->>B: total #hash;3",
+			errorCount: 1,
+			column:     18,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			rule := &validator.UnknownEntities{}
+			var errors []validator.ValidationError
+			switch d := diagram.(type) {
+			case *ast.Flowchart:
+				errors = rule.Validate(d)
+			case *ast.SequenceDiagram:
+				errors = rule.ValidateSequence(d)
+			}
+			if len(errors) != tt.errorCount {
+				t.Fatalf("expected %d errors, got %d: %v", tt.errorCount, len(errors), errors)
+			}
+			if tt.errorCount > 0 && errors[0].Column != tt.column {
+				t.Errorf("column = %d, want %d", errors[0].Column, tt.column)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/export"
 	"github.com/sammcj/mermaid-check/validator"
 )
 
@@ -177,13 +178,39 @@ func TestValidAttributeKeysRule(t *testing.T) {
 	}
 }
 
+func TestERSQLTypes(t *testing.T) {
+	rule := &validator.ERSQLTypes{Dialect: export.Postgres}
+	if rule.Name() != "er-sql-types" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "er-sql-types")
+	}
+
+	diagram := &ast.ERDiagram{
+		Type: "erDiagram",
+		Entities: []ast.EREntity{{
+			Name: "PRODUCT",
+			Attributes: []ast.ERAttribute{
+				{Type: "int", Name: "id", Keys: []string{"PK"}, Pos: ast.Position{Line: 3, Column: 1}},
+				{Type: "money", Name: "price", Pos: ast.Position{Line: 4, Column: 1}},
+			},
+		}},
+	}
+	errors := rule.ValidateDiagram(diagram)
+	if len(errors) != 1 || errors[0].Line != 4 || errors[0].Message != "attribute 'price' of 'PRODUCT' has type 'money', which has no postgres equivalent" {
+		t.Errorf("ValidateDiagram() = %v, want one error for price", errors)
+	}
+
+	if errors := rule.ValidateDiagram(&ast.PieDiagram{}); len(errors) != 0 {
+		t.Errorf("ValidateDiagram() on a pie chart = %v, want none", errors)
+	}
+}
+
 func TestERDefaultRules(t *testing.T) {
 	rules := validator.ERDefaultRules()
 	if len(rules) == 0 {
 		t.Error("ERDefaultRules() returned empty slice")
 	}
-	if len(rules) != 3 {
-		t.Errorf("expected 3 default rules, got %d", len(rules))
+	if len(rules) != 4 {
+		t.Errorf("expected 4 default rules, got %d", len(rules))
 	}
 }
 
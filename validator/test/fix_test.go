@@ -0,0 +1,52 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestApplyFixes(t *testing.T) {
+	source := "line one\nline two  \nline three\nline four"
+	errors := []validator.ValidationError{
+		{Line: 2, Fix: &validator.Fix{Line: 2, Replacement: "line two"}},
+		{Line: 2, Fix: &validator.Fix{Line: 2, Replacement: "ignored"}},
+		{Line: 3, Fix: &validator.Fix{Line: 3, Delete: true}},
+		{Line: 4},
+		{Line: 9, Fix: &validator.Fix{Line: 9, Delete: true}},
+	}
+
+	fixed, applied := validator.ApplyFixes(source, errors)
+	if want := "line one\nline two\nline four"; fixed != want {
+		t.Errorf("ApplyFixes() = %q, want %q", fixed, want)
+	}
+	if applied != 2 {
+		t.Errorf("applied = %d, want 2", applied)
+	}
+
+	if fixed, applied := validator.ApplyFixes(source, nil); fixed != source || applied != 0 {
+		t.Errorf("ApplyFixes() without fixes = %q, %d", fixed, applied)
+	}
+}
+
+func TestNoDuplicateClasses_Fix(t *testing.T) {
+	source := "classDiagram\n    class Animal\n    class Animal\n    class Animal {\n        +name\n    }\n"
+	diagram, err := parser.NewClassParser().Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errors := (&validator.NoDuplicateClasses{}).ValidateClass(diagram.(*ast.ClassDiagram))
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errors)
+	}
+	// Only the bare declaration can be removed safely
+	if errors[0].Fix == nil || !errors[0].Fix.Delete || errors[0].Fix.Line != 3 {
+		t.Errorf("expected a fix deleting line 3, got %+v", errors[0].Fix)
+	}
+	if errors[1].Fix != nil {
+		t.Errorf("expected no fix for a declaration with members, got %+v", errors[1].Fix)
+	}
+}
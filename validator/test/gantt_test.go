@@ -491,3 +491,74 @@ func TestValidDateFormatRule(t *testing.T) {
 		})
 	}
 }
+
+func TestValidExcludesRule(t *testing.T) {
+	rule := &validator.ValidExcludesRule{}
+
+	tests := []struct {
+		name       string
+		dateFormat string
+		excludes   string
+		wantErrors int
+	}{
+		{name: "no excludes", wantErrors: 0},
+		{name: "weekends and day names", excludes: "weekends, Friday monday", wantErrors: 0},
+		{name: "dates in default format", excludes: "2024-01-01,2024-12-25", wantErrors: 0},
+		{name: "dates in custom format", dateFormat: "DD/MM/YYYY", excludes: "25/12/2024", wantErrors: 0},
+		{name: "date in wrong format", dateFormat: "DD/MM/YYYY", excludes: "2024-12-25", wantErrors: 1},
+		{name: "impossible date", excludes: "2024-02-30", wantErrors: 1},
+		{name: "unknown keyword", excludes: "weekends holidays", wantErrors: 1},
+		{name: "unsupported date format skips dates", dateFormat: "X", excludes: "1704067200 weekdays", wantErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := &ast.GanttDiagram{
+				DateFormat:  tt.dateFormat,
+				Excludes:    tt.excludes,
+				ExcludesPos: ast.Position{Line: 3, Column: 1},
+			}
+			errors := rule.Validate(diagram)
+			if len(errors) != tt.wantErrors {
+				t.Errorf("Validate() returned %d errors, want %d", len(errors), tt.wantErrors)
+				for _, err := range errors {
+					t.Logf("  - %s", err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestExcludedTaskDatesRule(t *testing.T) {
+	rule := &validator.ExcludedTaskDatesRule{}
+
+	diagram := &ast.GanttDiagram{
+		DateFormat: "YYYY-MM-DD",
+		Excludes:   "weekends 2024-01-10",
+		Sections: []ast.GanttSection{
+			{
+				Name: "Work",
+				Tasks: []ast.GanttTask{
+					{Name: "Weekend", StartDate: "2024-01-06", EndDate: "2024-01-08", Pos: ast.Position{Line: 4, Column: 1}},
+					{Name: "Holiday", StartDate: "2024-01-10", EndDate: "2024-01-11", Pos: ast.Position{Line: 5, Column: 1}},
+					{Name: "Working days", StartDate: "2024-01-08", EndDate: "2024-01-10", Pos: ast.Position{Line: 6, Column: 1}},
+					{Name: "Duration", StartDate: "2024-01-06", EndDate: "2d", Pos: ast.Position{Line: 7, Column: 1}},
+					{Name: "Dependency", StartDate: "after a", EndDate: "2024-01-08", Pos: ast.Position{Line: 8, Column: 1}},
+				},
+			},
+		},
+	}
+
+	errors := rule.Validate(diagram)
+	if len(errors) != 2 {
+		t.Fatalf("Validate() returned %d errors, want 2: %v", len(errors), errors)
+	}
+	if errors[0].Line != 4 || errors[1].Line != 5 {
+		t.Errorf("expected warnings on lines 4 and 5, got %d and %d", errors[0].Line, errors[1].Line)
+	}
+	for _, err := range errors {
+		if err.Severity != validator.SeverityWarning {
+			t.Errorf("expected warning severity, got %v", err.Severity)
+		}
+	}
+}
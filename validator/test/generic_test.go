@@ -161,6 +161,16 @@ func TestNoTrailingWhitespace(t *testing.T) {
 	}
 }
 
+func TestGenericRules_MultiByteColumns(t *testing.T) {
+	source := "sequenceDiagram\n    Zoë->>田中: こんにちは "
+	diagram := ast.NewGenericDiagram("sequence", source, ast.Position{Line: 1, Column: 1})
+
+	whitespace := (&validator.NoTrailingWhitespace{}).ValidateGeneric(diagram)
+	if len(whitespace) != 1 || whitespace[0].Column != 20 {
+		t.Errorf("trailing whitespace errors = %v, want one at column 20", whitespace)
+	}
+}
+
 func TestNoParenthesesInText(t *testing.T) {
 	rule := &validator.NoParenthesesInText{}
 
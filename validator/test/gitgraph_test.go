@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
 
 	"github.com/sammcj/mermaid-check/validator"
 )
@@ -412,3 +413,122 @@ func TestValidCommitTypeRule(t *testing.T) {
 		})
 	}
 }
+
+func TestGitGraphLifecycleRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      validator.GitGraphRule
+		source    string
+		wantLines []int
+	}{
+		{
+			name:   "merge of another branch",
+			rule:   &validator.NoSelfMergeRule{},
+			source: "gitGraph\n    commit\n    branch develop\n    commit\n    checkout main\n    merge develop",
+		},
+		{
+			name:      "merge into itself",
+			rule:      &validator.NoSelfMergeRule{},
+			source:    "gitGraph\n    commit\n    branch develop\n    commit\n    merge develop",
+			wantLines: []int{5},
+		},
+		{
+			name:   "checkout after branch",
+			rule:   &validator.CheckoutAfterBranchRule{},
+			source: "gitGraph\n    commit\n    branch develop\n    checkout main\n    checkout develop\n    commit",
+		},
+		{
+			name:      "checkout before branch",
+			rule:      &validator.CheckoutAfterBranchRule{},
+			source:    "gitGraph\n    commit\n    checkout develop\n    commit\n    branch develop",
+			wantLines: []int{3},
+		},
+		{
+			name:   "checkout of undeclared branch left to branch references",
+			rule:   &validator.CheckoutAfterBranchRule{},
+			source: "gitGraph\n    commit\n    checkout missing",
+		},
+		{
+			name:   "cherry-pick from another branch",
+			rule:   &validator.NoCherryPickFromCurrentBranchRule{},
+			source: "gitGraph\n    commit\n    branch develop\n    commit id: \"A\"\n    checkout main\n    cherry-pick id: \"A\"",
+		},
+		{
+			name:      "cherry-pick from current branch",
+			rule:      &validator.NoCherryPickFromCurrentBranchRule{},
+			source:    "gitGraph\n    commit\n    branch develop\n    commit id: \"A\"\n    cherry-pick id: \"A\"",
+			wantLines: []int{5},
+		},
+		{
+			name:   "merge of branch with commits",
+			rule:   &validator.NoEmptyBranchMergeRule{},
+			source: "gitGraph\n    commit\n    branch develop\n    commit\n    checkout main\n    merge develop",
+		},
+		{
+			name:      "merge of branch without commits",
+			rule:      &validator.NoEmptyBranchMergeRule{},
+			source:    "gitGraph\n    commit\n    branch develop\n    checkout main\n    commit\n    merge develop",
+			wantLines: []int{6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			errs := tt.rule.Validate(diagram.(*ast.GitGraphDiagram))
+			if len(errs) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errs), len(tt.wantLines), errs)
+			}
+			for i, err := range errs {
+				if err.Line != tt.wantLines[i] {
+					t.Errorf("error %d on line %d, want %d", i, err.Line, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateGitGraphStrictEmptyBranchMerge(t *testing.T) {
+	source := "gitGraph\n    commit\n    branch develop\n    checkout main\n    commit\n    merge develop"
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	gitGraph := diagram.(*ast.GitGraphDiagram)
+
+	if errs := validator.ValidateGitGraph(gitGraph, false); len(errs) != 0 {
+		t.Errorf("default rules reported %d errors, want 0", len(errs))
+	}
+	errs := validator.ValidateGitGraph(gitGraph, true)
+	if len(errs) != 1 || errs[0].Severity != validator.SeverityWarning || errs[0].Rule != "no-empty-branch-merge" {
+		t.Errorf("strict rules reported %v, want one no-empty-branch-merge warning", errs)
+	}
+}
+
+func TestValidateGitGraphStrictLifecycle(t *testing.T) {
+	source := "gitGraph\n    commit\n    checkout develop\n    branch develop\n    commit id: \"A\"\n    cherry-pick id: \"A\"\n    merge develop"
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	gitGraph := diagram.(*ast.GitGraphDiagram)
+
+	if errs := validator.ValidateGitGraph(gitGraph, false); len(errs) != 0 {
+		t.Errorf("default rules reported %v, want none", errs)
+	}
+	rules := map[string]bool{}
+	for _, err := range validator.ValidateGitGraph(gitGraph, true) {
+		if err.Severity != validator.SeverityWarning {
+			t.Errorf("%s reported with severity %v, want a warning", err.Rule, err.Severity)
+		}
+		rules[err.Rule] = true
+	}
+	for _, rule := range []string{"checkout-after-branch", "no-cherry-pick-from-current-branch", "no-self-merge"} {
+		if !rules[rule] {
+			t.Errorf("strict rules did not report %s, got %v", rule, rules)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+const spriteDiagram = `C4Container
+    title Icons
+    Person(user, "User", $sprite="cloud")
+    System_Boundary(shop, "Shop") {
+        Container(api, "API", "Go", $sprite="logos:aws-lambda")
+        ContainerDb(db, "DB", "Postgres", $sprite="$logos:AWS-RDS")
+        Container(queue, "Queue", "SQS", $sprite="aws-sqs")
+        Container(icons, "Icons", "Go", $sprite="mdi:account")
+    }
+    Rel(user, api, "Uses", $sprite="logos:aws-lamda")
+    Rel(api, db, "Reads")
+`
+
+func TestKnownIcons(t *testing.T) {
+	diagram, err := parser.Parse(spriteDiagram)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    *validator.KnownIcons
+		want    []string
+		suggest map[string]string
+	}{
+		{
+			name: "default catalogue",
+			rule: &validator.KnownIcons{},
+			want: []string{
+				"line 7: warning: Container 'queue' uses unknown icon 'aws-sqs'",
+				"line 8: warning: Container 'icons' uses unknown icon 'mdi:account'",
+				"line 10: warning: relationship 'user -> api' uses unknown icon 'logos:aws-lamda'",
+			},
+			suggest: map[string]string{"aws-sqs": "did you mean 'logos:aws-sqs'?"},
+		},
+		{
+			name: "custom catalogue with a pack wildcard",
+			rule: &validator.KnownIcons{Icons: []string{"cloud", "logos:*", "aws-sqs"}},
+			want: []string{
+				"line 8: warning: Container 'icons' uses unknown icon 'mdi:account'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := tt.rule.ValidateDiagram(diagram)
+			var got []string
+			for _, err := range errors {
+				if err.Severity != validator.SeverityWarning {
+					t.Errorf("severity = %v, want warning", err.Severity)
+				}
+				got = append(got, err.Error())
+				for icon, suggestion := range tt.suggest {
+					if strings.Contains(err.Message, "'"+icon+"'") && err.Suggestion != suggestion {
+						t.Errorf("suggestion for %s = %q, want %q", icon, err.Suggestion, suggestion)
+					}
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestKnownIcons_OtherDiagramTypes(t *testing.T) {
+	diagram, err := parser.Parse("flowchart TD\n    A --> B\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errors := (&validator.KnownIcons{}).ValidateDiagram(diagram); len(errors) != 0 {
+		t.Errorf("ValidateDiagram() = %v, want nothing for a flowchart", errors)
+	}
+}
@@ -0,0 +1,167 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestMixedIndentation(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantLines []int
+		wantFixed []string
+	}{
+		{
+			name:   "spaces only",
+			source: "mindmap\n  root\n    A\n    B",
+		},
+		{
+			name:   "tabs only",
+			source: "mindmap\n\troot\n\t\tA\n\t\tB",
+		},
+		{
+			name:      "tab among spaces",
+			source:    "mindmap\n  root\n    A\n\t\tB",
+			wantLines: []int{4},
+			wantFixed: []string{"    B"},
+		},
+		{
+			name:      "spaces among tabs",
+			source:    "mindmap\n\troot\n\t\tA\n\t\tB\n    C",
+			wantLines: []int{5},
+			wantFixed: []string{"\tC"},
+		},
+		{
+			name:      "mixed on one line",
+			source:    "mindmap\n  root\n  \tA",
+			wantLines: []int{3},
+			wantFixed: []string{"    A"},
+		},
+		{
+			name:      "tabs in front matter",
+			source:    "---\nconfig:\n\ttheme: dark\n---\nmindmap\n\troot\n\t\tA",
+			wantLines: []int{3},
+			wantFixed: []string{"    theme: dark"},
+		},
+		{
+			name:   "whitespace-only line",
+			source: "mindmap\n  root\n\t\n    A",
+		},
+	}
+
+	rule := &validator.MixedIndentation{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := rule.ValidateDiagram(&ast.GenericDiagram{Source: tt.source})
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantLines), errors)
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] {
+					t.Errorf("error %d on line %d, want %d", i, err.Line, tt.wantLines[i])
+				}
+				if err.Fix == nil || err.Fix.Replacement != tt.wantFixed[i] {
+					t.Errorf("error %d fix = %+v, want replacement %q", i, err.Fix, tt.wantFixed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIndentStyle(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      validator.IndentStyle
+		source    string
+		wantLines []int
+		wantFixed []string
+	}{
+		{
+			name:   "consistent flowchart",
+			source: "flowchart TD\n    A --> B\n    subgraph S\n        C --> D\n    end",
+		},
+		{
+			name:      "two spaces",
+			source:    "flowchart TD\n  A --> B\n    C --> D",
+			wantLines: []int{2},
+			wantFixed: []string{"    A --> B"},
+		},
+		{
+			name:      "subgraph contents not indented",
+			source:    "flowchart TD\n    subgraph S\n    C --> D\n    end",
+			wantLines: []int{3},
+			wantFixed: []string{"        C --> D"},
+		},
+		{
+			name:   "sequence alt with else",
+			source: "sequenceDiagram\n    alt ok\n        A->>B: yes\n    else failed\n        A->>B: no\n    end",
+		},
+		{
+			name:      "sequence else indented like its messages",
+			source:    "sequenceDiagram\n    alt ok\n        A->>B: yes\n        else failed\n        A->>B: no\n    end",
+			wantLines: []int{4},
+			wantFixed: []string{"    else failed"},
+		},
+		{
+			name:   "braces and comments",
+			source: "classDiagram\n    class Animal {\n        +name\n    }\n%% comment",
+		},
+		{
+			name:      "tabs",
+			rule:      validator.IndentStyle{Tabs: true},
+			source:    "stateDiagram-v2\n\tstate A {\n    \tB --> C\n\t}",
+			wantLines: []int{3},
+			wantFixed: []string{"\t\tB --> C"},
+		},
+		{
+			name:      "custom width",
+			rule:      validator.IndentStyle{Width: 2},
+			source:    "flowchart TD\n    A --> B",
+			wantLines: []int{2},
+			wantFixed: []string{"  A --> B"},
+		},
+		{
+			name:   "mindmap not checked",
+			source: "mindmap\n  root\n      A",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			errors := tt.rule.ValidateDiagram(diagram)
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantLines), errors)
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] {
+					t.Errorf("error %d on line %d, want %d", i, err.Line, tt.wantLines[i])
+				}
+				if err.Fix == nil || err.Fix.Replacement != tt.wantFixed[i] {
+					t.Errorf("error %d fix = %+v, want replacement %q", i, err.Fix, tt.wantFixed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseIndentStyle(t *testing.T) {
+	if style, err := validator.ParseIndentStyle("2"); err != nil || style.Width != 2 || style.Tabs {
+		t.Errorf("ParseIndentStyle(\"2\") = %+v, %v", style, err)
+	}
+	if style, err := validator.ParseIndentStyle("tab"); err != nil || !style.Tabs {
+		t.Errorf("ParseIndentStyle(\"tab\") = %+v, %v", style, err)
+	}
+	for _, value := range []string{"", "0", "wide"} {
+		if _, err := validator.ParseIndentStyle(value); err == nil {
+			t.Errorf("ParseIndentStyle(%q) expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestSecureInteractions(t *testing.T) {
+	rule := &validator.SecureInteractions{}
+
+	if rule.Name() != "secure-interactions" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "secure-interactions")
+	}
+
+	tests := []struct {
+		name         string
+		source       string
+		wantErrors   int
+		wantSeverity validator.Severity
+	}{
+		{
+			name:   "https link",
+			source: "flowchart TD\n    A --> B\n    click A href \"https://example.com\"",
+		},
+		{
+			name:         "javascript URL",
+			source:       "flowchart TD\n    A --> B\n    click A href \"javascript:alert(1)\"",
+			wantErrors:   1,
+			wantSeverity: validator.SeverityError,
+		},
+		{
+			name:         "http link",
+			source:       "flowchart TD\n    A --> B\n    click A \"http://example.com\"",
+			wantErrors:   1,
+			wantSeverity: validator.SeverityWarning,
+		},
+		{
+			name:         "callback without securityLevel",
+			source:       "flowchart TD\n    A --> B\n    click A call show()",
+			wantErrors:   1,
+			wantSeverity: validator.SeverityWarning,
+		},
+		{
+			name:         "callback with strict securityLevel",
+			source:       "%%{init: {\"securityLevel\": \"strict\"}}%%\nflowchart TD\n    A --> B\n    click A show",
+			wantErrors:   1,
+			wantSeverity: validator.SeverityWarning,
+		},
+		{
+			name:   "callback with loose securityLevel",
+			source: "%%{init: {\"securityLevel\": \"loose\"}}%%\nflowchart TD\n    A --> B\n    click A show",
+		},
+		{
+			name:         "link inside subgraph",
+			source:       "flowchart TD\n    subgraph S\n        A --> B\n        click B \"http://example.com\"\n    end",
+			wantErrors:   1,
+			wantSeverity: validator.SeverityWarning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != tt.wantErrors {
+				t.Fatalf("expected %d errors, got %d: %v", tt.wantErrors, len(errors), errors)
+			}
+			for _, err := range errors {
+				if err.Severity != tt.wantSeverity {
+					t.Errorf("severity = %v, want %v", err.Severity, tt.wantSeverity)
+				}
+			}
+		})
+	}
+}
+
+func TestSecureInteractions_Class(t *testing.T) {
+	source := "classDiagram\n    class Shape\n    link Shape \"javascript:void(0)\"\n    callback Shape \"onShape\""
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	errors := (&validator.SecureInteractions{}).ValidateClass(diagram.(*ast.ClassDiagram))
+	if len(errors) != 2 {
+		t.Errorf("expected 2 errors, got %d: %v", len(errors), errors)
+	}
+}
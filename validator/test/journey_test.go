@@ -1,6 +1,8 @@
 package validator_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -344,3 +346,101 @@ func TestTasksHaveActorsRule(t *testing.T) {
 		})
 	}
 }
+
+func TestConsistentActorsRule(t *testing.T) {
+	rule := &validator.ConsistentActorsRule{}
+
+	tests := []struct {
+		name       string
+		actors     [][]string
+		wantActors []string
+	}{
+		{
+			name:   "all actors recur",
+			actors: [][]string{{"Customer"}, {"Customer", "System"}, {"System"}},
+		},
+		{
+			name:       "one-off actor alongside recurring ones",
+			actors:     [][]string{{"Customer"}, {"Custmer"}, {"Customer", "System"}, {"System"}},
+			wantActors: []string{"Custmer"},
+		},
+		{
+			name:   "no actor recurs",
+			actors: [][]string{{"Customer"}, {"System"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			section := ast.Section{Name: "Main"}
+			for i, actors := range tt.actors {
+				section.Tasks = append(section.Tasks, ast.Task{
+					Name:   "Task",
+					Score:  3,
+					Actors: actors,
+					Pos:    ast.Position{Line: i + 2, Column: 1},
+				})
+			}
+
+			errors := rule.Validate(&ast.JourneyDiagram{Sections: []ast.Section{section}})
+			if len(errors) != len(tt.wantActors) {
+				t.Fatalf("Validate() returned %d errors, want %d", len(errors), len(tt.wantActors))
+			}
+			for i, err := range errors {
+				if !strings.Contains(err.Message, fmt.Sprintf("%q", tt.wantActors[i])) || err.Severity != validator.SeverityWarning {
+					t.Errorf("unexpected error %+v", err)
+				}
+			}
+		})
+	}
+
+	found := false
+	for _, rule := range validator.JourneyStrictRules() {
+		if _, ok := rule.(*validator.ConsistentActorsRule); ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected the strict rules to add the consistent-actors rule")
+	}
+}
+
+func TestLowSectionScoreRule(t *testing.T) {
+	diagram := &ast.JourneyDiagram{
+		Sections: []ast.Section{
+			{Name: "Signup", Pos: ast.Position{Line: 2, Column: 1}, Tasks: []ast.Task{
+				{Name: "Fill form", Score: 2, Actors: []string{"User"}},
+				{Name: "Confirm email", Score: 3, Actors: []string{"User"}},
+			}},
+			{Name: "Checkout", Pos: ast.Position{Line: 5, Column: 1}, Tasks: []ast.Task{
+				{Name: "Pay", Score: 4, Actors: []string{"User"}},
+			}},
+			{Name: "Empty", Pos: ast.Position{Line: 7, Column: 1}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		wantLines []int
+	}{
+		{name: "default threshold", wantLines: []int{2}},
+		{name: "higher threshold", threshold: 4.5, wantLines: []int{2, 5}},
+		{name: "lower threshold", threshold: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &validator.LowSectionScoreRule{Threshold: tt.threshold}
+			errors := rule.Validate(diagram)
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("Validate() returned %d errors, want %d", len(errors), len(tt.wantLines))
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] || err.Severity != validator.SeverityWarning {
+					t.Errorf("unexpected error %+v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestLabelLength_Flowchart(t *testing.T) {
+	long := strings.Repeat("x", validator.DefaultFlowchartLabelLength+1)
+
+	tests := []struct {
+		name       string
+		source     string
+		limits     map[string]int
+		errorCount int
+		column     int
+	}{
+		{
+			name:   "short labels",
+			source: "flowchart TD\n    A[Start] -->|next| B[Done]",
+		},
+		{
+			name:       "long node label",
+			source:     "flowchart TD\n    A[" + long + "] --> B",
+			errorCount: 1,
+			column:     7,
+		},
+		{
+			name:       "long edge label",
+			source:     "flowchart TD\n    A -->|" + long + "| B",
+			errorCount: 1,
+			column:     11,
+		},
+		{
+			name:   "label split with line breaks",
+			source: "flowchart TD\n    A[" + long[:30] + "<br/>" + long[:30] + "<BR>" + long[:30] + "] --> B",
+		},
+		{
+			name:       "custom limit",
+			source:     "flowchart TD\n    A[Receive the order] --> B",
+			limits:     map[string]int{"flowchart": 10},
+			errorCount: 1,
+			column:     7,
+		},
+		{
+			name:   "limit for another diagram type",
+			source: "flowchart TD\n    A[Receive the order] --> B",
+			limits: map[string]int{"sequence": 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			rule := &validator.LabelLength{Limits: tt.limits}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != tt.errorCount {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), tt.errorCount, errors)
+			}
+			for _, err := range errors {
+				if err.Line != 2 || err.Column != tt.column {
+					t.Errorf("error at %d:%d, want 2:%d", err.Line, err.Column, tt.column)
+				}
+				if !strings.Contains(err.Message, "<br/>") {
+					t.Errorf("message %q should suggest <br/>", err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestLabelLength_Sequence(t *testing.T) {
+	long := strings.Repeat("word ", 13)
+	source := "sequenceDiagram\n    participant A as " + long + "\n    A->>B: " + long + "\n    Note over A: " + long + "\n    loop Retry\n        B-->>A: " + long + "\n    end"
+
+	diagram, err := parser.Parse(source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	rule := &validator.LabelLength{}
+	errors := rule.ValidateSequence(diagram.(*ast.SequenceDiagram))
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors for the long messages only, got %v", errors)
+	}
+	if errors[0].Line != 3 || errors[1].Line != 6 {
+		t.Errorf("errors on lines %d and %d, want 3 and 6", errors[0].Line, errors[1].Line)
+	}
+}
+
+func TestWithLabelLengths(t *testing.T) {
+	limits := map[string]int{"flowchart": 20, "sequence": 80}
+
+	strict := validator.WithLabelLengths(validator.StrictRules(), limits)
+	if len(strict) != len(validator.StrictRules()) {
+		t.Errorf("strict rules should be configured in place, got %d rules", len(strict))
+	}
+
+	defaults := validator.WithLabelLengths(validator.SequenceDefaultRules(), limits)
+	if len(defaults) != len(validator.SequenceDefaultRules())+1 {
+		t.Fatalf("default rules should gain a label-length rule, got %d rules", len(defaults))
+	}
+	rule, ok := defaults[len(defaults)-1].(*validator.LabelLength)
+	if !ok || rule.Limits["sequence"] != 80 {
+		t.Errorf("last rule = %#v, want configured LabelLength", defaults[len(defaults)-1])
+	}
+}
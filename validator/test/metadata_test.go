@@ -0,0 +1,60 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestRequiredAnnotations(t *testing.T) {
+	rule := &validator.RequiredAnnotations{Keys: []string{"owner", "id"}}
+
+	if rule.Name() != "required-annotations" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "required-annotations")
+	}
+
+	tests := []struct {
+		name       string
+		source     string
+		errorCount int
+	}{
+		{
+			name:   "all annotations present",
+			source: "flowchart TD\n    %% @owner: platform-team\n    %% @id: ARCH-42\n    A --> B",
+		},
+		{
+			name:       "one annotation missing",
+			source:     "sequenceDiagram\n    %% @owner: platform-team\n    A->>B: hi",
+			errorCount: 1,
+		},
+		{
+			name:       "annotation without value",
+			source:     "pie\n    %% @owner\n    %% @id: PIE-1\n    \"A\" : 1",
+			errorCount: 1,
+		},
+		{
+			name:       "no annotations",
+			source:     "erDiagram\n    A ||--o{ B : has",
+			errorCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.ValidateDiagram(diagram)
+			if len(errors) != tt.errorCount {
+				t.Errorf("expected %d errors, got %d: %v", tt.errorCount, len(errors), errors)
+			}
+			for _, err := range errors {
+				if err.Severity != validator.SeverityError || err.Line != 1 {
+					t.Errorf("unexpected error %+v", err)
+				}
+			}
+		})
+	}
+}
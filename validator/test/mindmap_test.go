@@ -337,16 +337,69 @@ func TestValidShapeRule(t *testing.T) {
 	}
 }
 
+func TestValidIconsRule(t *testing.T) {
+	root := &ast.MindmapNode{
+		Text: "Root",
+		Pos:  ast.Position{Line: 2},
+		Children: []*ast.MindmapNode{
+			{Text: "Good", Icon: "fa fa-book", Icons: []string{"fa fa-book"}, Pos: ast.Position{Line: 3}},
+			{Text: "Empty", Icons: []string{""}, Pos: ast.Position{Line: 5}},
+			{Text: "Bad", Icon: "fa fa-b@@k", Icons: []string{"fa fa-b@@k"}, Pos: ast.Position{Line: 7}},
+			{Text: "Twice", Icon: "mdi mdi-skull", Icons: []string{"fa fa-book", "mdi mdi-skull"}, Pos: ast.Position{Line: 9}},
+		},
+	}
+
+	rule := &validator.ValidIconsRule{}
+	errors := rule.Validate(&ast.MindmapDiagram{Root: root})
+
+	want := []struct {
+		line     int
+		severity validator.Severity
+	}{
+		{5, validator.SeverityError},
+		{7, validator.SeverityWarning},
+		{9, validator.SeverityWarning},
+	}
+	if len(errors) != len(want) {
+		for _, err := range errors {
+			t.Logf("  line %d: %s", err.Line, err.Message)
+		}
+		t.Fatalf("expected %d errors, got %d", len(want), len(errors))
+	}
+	for i, w := range want {
+		if errors[i].Line != w.line || errors[i].Severity != w.severity {
+			t.Errorf("error %d: expected %s on line %d, got %+v", i, w.severity, w.line, errors[i])
+		}
+	}
+}
+
+func TestValidNodeClassesRule(t *testing.T) {
+	root := &ast.MindmapNode{
+		Text:    "Root",
+		Classes: []string{"urgent", "large"},
+		Pos:     ast.Position{Line: 2},
+		Children: []*ast.MindmapNode{
+			{Text: "Child", Classes: []string{"9lives"}, Pos: ast.Position{Line: 4}},
+		},
+	}
+
+	rule := &validator.ValidNodeClassesRule{}
+	errors := rule.Validate(&ast.MindmapDiagram{Root: root})
+	if len(errors) != 1 || errors[0].Line != 4 {
+		t.Errorf("expected one error on line 4, got %+v", errors)
+	}
+}
+
 func TestMindmapDefaultRules(t *testing.T) {
 	rules := validator.MindmapDefaultRules()
-	if len(rules) != 3 {
-		t.Errorf("expected 3 default rules, got %d", len(rules))
+	if len(rules) != 5 {
+		t.Errorf("expected 5 default rules, got %d", len(rules))
 	}
 }
 
 func TestMindmapStrictRules(t *testing.T) {
 	rules := validator.MindmapStrictRules()
-	if len(rules) < 3 {
-		t.Errorf("expected at least 3 strict rules, got %d", len(rules))
+	if len(rules) < 5 {
+		t.Errorf("expected at least 5 strict rules, got %d", len(rules))
 	}
 }
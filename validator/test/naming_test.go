@@ -0,0 +1,134 @@
+package validator_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestNamingPattern(t *testing.T) {
+	tests := []struct {
+		convention string
+		valid      []string
+		invalid    []string
+	}{
+		{"camelCase", []string{"orderService", "a1"}, []string{"OrderService", "order_service"}},
+		{"PascalCase", []string{"OrderService"}, []string{"orderService", "Order_Service"}},
+		{"snake_case", []string{"order_service", "db"}, []string{"orderService", "order__service"}},
+		{"SCREAMING_SNAKE_CASE", []string{"AWAITING_PAYMENT", "IDLE"}, []string{"AwaitingPayment", "awaiting_payment"}},
+		{"kebab-case", []string{"order-service"}, []string{"order_service"}},
+		{`^n\d+$`, []string{"n1", "n42"}, []string{"node1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.convention, func(t *testing.T) {
+			pattern, err := validator.NamingPattern(tt.convention)
+			if err != nil {
+				t.Fatalf("NamingPattern() error = %v", err)
+			}
+			for _, name := range tt.valid {
+				if !pattern.MatchString(name) {
+					t.Errorf("%q should match %s", name, tt.convention)
+				}
+			}
+			for _, name := range tt.invalid {
+				if pattern.MatchString(name) {
+					t.Errorf("%q should not match %s", name, tt.convention)
+				}
+			}
+		})
+	}
+
+	if _, err := validator.NamingPattern("[unclosed"); err == nil {
+		t.Error("NamingPattern() expected error for invalid regex")
+	}
+}
+
+func TestNamingConventions(t *testing.T) {
+	mustPattern := func(convention string) *regexp.Regexp {
+		pattern, err := validator.NamingPattern(convention)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pattern
+	}
+	rule := &validator.NamingConventions{Patterns: map[string]*regexp.Regexp{
+		"flowchart": mustPattern("camelCase"),
+		"sequence":  mustPattern("PascalCase"),
+		"class":     mustPattern("PascalCase"),
+		"state":     mustPattern("SCREAMING_SNAKE_CASE"),
+	}}
+
+	if rule.Name() != "naming-conventions" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "naming-conventions")
+	}
+
+	type violation struct {
+		line, column int
+		name         string
+	}
+	tests := []struct {
+		name       string
+		source     string
+		violations []violation
+	}{
+		{
+			name:   "flowchart following convention",
+			source: "flowchart LR\n    startNode[Start] --> endNode[End]",
+		},
+		{
+			name:       "flowchart reported once per identifier",
+			source:     "flowchart LR\n    start --> Check_Order\n    Check_Order --> done",
+			violations: []violation{{2, 15, "Check_Order"}},
+		},
+		{
+			name:       "flowchart subgraph id",
+			source:     "flowchart LR\n    subgraph Payment_Flow\n    a --> b\n    end",
+			violations: []violation{{2, 14, "Payment_Flow"}},
+		},
+		{
+			name:       "sequence participants and implicit actors",
+			source:     "sequenceDiagram\n    participant api as API\n    Client->>api: GET\n    loop retry\n        api->>db_main: query\n    end",
+			violations: []violation{{2, 17, "api"}, {5, 15, "db_main"}},
+		},
+		{
+			name:       "class names",
+			source:     "classDiagram\n    class orderService\n    orderService <|-- PaymentService",
+			violations: []violation{{2, 11, "orderService"}},
+		},
+		{
+			name:       "state names skip start and end markers",
+			source:     "stateDiagram-v2\n    [*] --> IDLE\n    IDLE --> Running\n    Running --> [*]",
+			violations: []violation{{3, 14, "Running"}},
+		},
+		{
+			name:   "unconfigured diagram types are not checked",
+			source: "pie\n    \"some_label\" : 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.ValidateDiagram(diagram)
+			if len(errors) != len(tt.violations) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.violations), errors)
+			}
+			for i, err := range errors {
+				want := tt.violations[i]
+				if err.Line != want.line || err.Column != want.column {
+					t.Errorf("error %d at %d:%d, want %d:%d (%s)", i, err.Line, err.Column, want.line, want.column, err.Message)
+				}
+				if !strings.Contains(err.Message, "'"+want.name+"'") {
+					t.Errorf("error %d message %q does not name %q", i, err.Message, want.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestConfigurableRulesDefaults(t *testing.T) {
+	for _, rule := range validator.ConfigurableRules() {
+		for _, option := range rule.Options() {
+			if option.Default == nil {
+				continue
+			}
+			if _, err := validator.CheckOptions(rule, validator.RuleOptions{option.Name: option.Default}); err != nil {
+				t.Errorf("%s: default of option %s does not match its type: %v", rule.Name(), option.Name, err)
+			}
+		}
+	}
+}
+
+func TestConfigureRule(t *testing.T) {
+	rule, err := validator.ConfigureRule("max-link-length", validator.RuleOptions{"max": 1})
+	if err != nil {
+		t.Fatalf("ConfigureRule() error = %v", err)
+	}
+	if got := rule.(*validator.MaxLinkLength).Max; got != 1 {
+		t.Errorf("Max = %d, want 1", got)
+	}
+
+	rule, err = validator.ConfigureRule("label-length", validator.RuleOptions{"sequence": 20.0})
+	if err != nil {
+		t.Fatalf("ConfigureRule() error = %v", err)
+	}
+	if got := rule.(*validator.LabelLength).Limits; len(got) != 1 || got["sequence"] != 20 {
+		t.Errorf("Limits = %v, want sequence: 20", got)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		options validator.RuleOptions
+		wantErr string
+	}{
+		{"unknown rule", "no-such-rule", nil, `rule "no-such-rule" takes no options (rules with options: allowed-directions, label-length, max-link-length, max-participants, naming-conventions, terminology)`},
+		{"unknown option", "terminology", validator.RuleOptions{"prefer": "x"}, `rule terminology has no option "prefer" (options: avoid)`},
+		{"fractional integer", "max-link-length", validator.RuleOptions{"max": 2.5}, "option max: want integer, got 2.5"},
+		{"list for integer", "max-link-length", validator.RuleOptions{"max": []any{1}}, "option max: want integer, got a list"},
+		{"non-positive limit", "max-link-length", validator.RuleOptions{"max": 0}, "max must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ConfigureRule(tt.rule, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ConfigureRule() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithConfiguredRules(t *testing.T) {
+	maxLength, err := validator.ConfigureRule("max-link-length", validator.RuleOptions{"max": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	terminology, err := validator.ConfigureRule("terminology", validator.RuleOptions{"avoid": []any{"DB"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	configured := []validator.ConfigurableRule{maxLength, terminology}
+
+	strict := validator.StrictRules()
+	rules := validator.WithConfiguredRules(strict, configured)
+	if len(rules) != len(strict) {
+		t.Errorf("got %d strict rules, want %d: max-link-length replaced and terminology skipped", len(rules), len(strict))
+	}
+	defaults := validator.WithConfiguredRules(validator.DefaultRules(), configured)
+	if len(defaults) != len(validator.DefaultRules())+1 {
+		t.Errorf("got %d default rules, want max-link-length added", len(defaults))
+	}
+
+	diagram, err := parser.Parse("flowchart TD\n    A --> B\n    B ---> C")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	errors := validator.New(defaults...).Validate(diagram.(*ast.Flowchart))
+	if len(errors) != 1 || errors[0].Line != 3 || errors[0].Rule != "max-link-length" {
+		t.Errorf("expected the configured max-link-length rule to report line 3, got %v", errors)
+	}
+}
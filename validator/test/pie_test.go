@@ -109,3 +109,50 @@ func TestPieStrictRules(t *testing.T) {
 		t.Error("PieStrictRules() returned empty slice")
 	}
 }
+
+func TestPieTextPositionRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		wantErr  bool
+	}{
+		{name: "not set", wantErr: false},
+		{name: "within range", position: "0.75", wantErr: false},
+		{name: "edge", position: "1", wantErr: false},
+		{name: "too large", position: "1.5", wantErr: true},
+		{name: "negative", position: "-0.1", wantErr: true},
+		{name: "not a number", position: "middle", wantErr: true},
+	}
+
+	rule := &validator.PieTextPositionRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := &ast.PieDiagram{ConfigPos: ast.Position{Line: 1, Column: 1}}
+			if tt.position != "" {
+				diagram.Config = map[string]string{"textPosition": tt.position}
+			}
+			errors := rule.Validate(diagram)
+			if (len(errors) > 0) != tt.wantErr {
+				t.Errorf("PieTextPositionRule.Validate() errors = %v, wantErr %v", errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPieValuePrecisionRule(t *testing.T) {
+	diagram := &ast.PieDiagram{
+		DataEntries: []ast.PieEntry{
+			{Label: "A", Value: 10, Raw: "10", Pos: ast.Position{Line: 2, Column: 1}},
+			{Label: "B", Value: 20.25, Raw: "20.25", Pos: ast.Position{Line: 3, Column: 1}},
+			{Label: "C", Value: 30.125, Raw: "30.125", Pos: ast.Position{Line: 4, Column: 1}},
+		},
+	}
+
+	errors := (&validator.PieValuePrecisionRule{}).Validate(diagram)
+	if len(errors) != 1 || errors[0].Line != 4 {
+		t.Fatalf("expected one error on line 4, got %v", errors)
+	}
+	if errors[0].Severity != validator.SeverityWarning {
+		t.Errorf("expected warning severity, got %v", errors[0].Severity)
+	}
+}
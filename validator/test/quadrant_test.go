@@ -303,6 +303,56 @@ func TestValidateQuadrant(t *testing.T) {
 	}
 }
 
+func TestQuadrantValidStylesRule(t *testing.T) {
+	diagram := &ast.QuadrantDiagram{
+		ClassDefs: []ast.ClassDef{
+			{Name: "good", Styles: map[string]string{"color": "#109060", "radius": "10"}, Pos: ast.Position{Line: 2}},
+			{Name: "bad", Styles: map[string]string{"color": "#12345", "opacity": "0.5"}, Pos: ast.Position{Line: 3}},
+		},
+		Points: []ast.QuadrantPoint{
+			{Name: "A", Styles: map[string]string{"radius": "12", "stroke-width": "5px", "stroke-color": "rebeccapurple"}, Pos: ast.Position{Line: 4}},
+			{Name: "B", Styles: map[string]string{"radius": "big", "stroke-color": "nocolour"}, Pos: ast.Position{Line: 5}},
+			{Name: "C", Pos: ast.Position{Line: 6}},
+		},
+	}
+
+	rule := &validator.QuadrantValidStylesRule{}
+	errors := rule.Validate(diagram)
+
+	wantLines := []int{3, 3, 5, 5}
+	if len(errors) != len(wantLines) {
+		for _, err := range errors {
+			t.Logf("  line %d: %s", err.Line, err.Message)
+		}
+		t.Fatalf("expected %d errors, got %d", len(wantLines), len(errors))
+	}
+	for i, err := range errors {
+		if err.Line != wantLines[i] {
+			t.Errorf("error %d: expected line %d, got %d (%s)", i, wantLines[i], err.Line, err.Message)
+		}
+	}
+}
+
+func TestQuadrantValidClassesRule(t *testing.T) {
+	diagram := &ast.QuadrantDiagram{
+		ClassDefs: []ast.ClassDef{{Name: "class1"}},
+		Points: []ast.QuadrantPoint{
+			{Name: "A", Class: "class1", Pos: ast.Position{Line: 3}},
+			{Name: "B", Class: "class2", Pos: ast.Position{Line: 4}},
+			{Name: "C", Pos: ast.Position{Line: 5}},
+		},
+	}
+
+	rule := &validator.QuadrantValidClassesRule{}
+	errors := rule.Validate(diagram)
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errors))
+	}
+	if errors[0].Line != 4 || errors[0].Severity != validator.SeverityError {
+		t.Errorf("expected error on line 4, got %+v", errors[0])
+	}
+}
+
 func TestQuadrantDefaultRules(t *testing.T) {
 	rules := validator.QuadrantDefaultRules()
 	if len(rules) == 0 {
@@ -0,0 +1,180 @@
+package validator_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestLookupRenderer(t *testing.T) {
+	if names := validator.RendererNames(); !reflect.DeepEqual(names, []string{"confluence", "github", "gitlab", "notion"}) {
+		t.Errorf("RendererNames() = %v", names)
+	}
+	for _, name := range validator.RendererNames() {
+		renderer, ok := validator.LookupRenderer(name)
+		if !ok || renderer.Name != name || renderer.Title == "" {
+			t.Errorf("LookupRenderer(%q) = %+v, %v", name, renderer, ok)
+		}
+	}
+	if _, ok := validator.LookupRenderer("wordpress"); ok {
+		t.Error("LookupRenderer(\"wordpress\") found a renderer")
+	}
+}
+
+func TestRendererMermaidVersions(t *testing.T) {
+	// Renderers with a known Mermaid version lack the diagram types later
+	// releases added
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"github", []string{"architecture-beta", "packet-beta"}},
+		{"gitlab", []string{"architecture-beta", "block-beta", "packet-beta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, _ := validator.LookupRenderer(tt.name)
+			if renderer.MermaidVersion == "" {
+				t.Errorf("%s has no Mermaid version", tt.name)
+			}
+			if !reflect.DeepEqual(renderer.UnsupportedDiagrams, tt.want) {
+				t.Errorf("UnsupportedDiagrams = %v, want %v", renderer.UnsupportedDiagrams, tt.want)
+			}
+		})
+	}
+}
+
+func TestForRenderer(t *testing.T) {
+	rules, err := validator.ForRenderer("gitlab")
+	if err != nil {
+		t.Fatalf("ForRenderer(\"gitlab\") error = %v", err)
+	}
+	if len(rules) != 1 || rules[0].Name() != "renderer-compatibility" {
+		t.Fatalf("ForRenderer(\"gitlab\") = %v", rules)
+	}
+	diagram, err := parser.Parse("flowchart LR\n    A --> B\n    click A href \"https://example.com\"")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if errs := rules[0].ValidateDiagram(diagram); len(errs) != 1 || errs[0].Line != 3 {
+		t.Errorf("ValidateDiagram() = %v, want a warning on line 3", errs)
+	}
+
+	if _, err := validator.ForRenderer("wordpress"); err == nil || !strings.Contains(err.Error(), "confluence, github, gitlab, notion") {
+		t.Errorf("ForRenderer(\"wordpress\") error = %v", err)
+	}
+}
+
+func TestRendererCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer string
+		source   string
+		want     []string // "line:column severity" of each error
+	}{
+		{
+			name:     "plain flowchart",
+			renderer: "confluence",
+			source:   "flowchart LR\n    A --> B",
+		},
+		{
+			name:     "init directive ignored",
+			renderer: "notion",
+			source:   "%%{init: {\"theme\": \"dark\"}}%%\nflowchart LR\n    A --> B",
+			want:     []string{"1:1 warning"},
+		},
+		{
+			name:     "init directive honoured",
+			renderer: "github",
+			source:   "%%{init: {\"theme\": \"dark\"}}%%\nflowchart LR\n    A --> B",
+		},
+		{
+			name:     "init directive after front matter",
+			renderer: "notion",
+			source:   "---\ntitle: Checkout\n---\n%%{init: {\"theme\": \"dark\"}}%%\nflowchart LR\n    A --> B",
+			want:     []string{"4:1 warning"},
+		},
+		{
+			name:     "beta diagram type after front matter",
+			renderer: "notion",
+			source:   "---\ntitle: Payments\n---\nsankey-beta\nWeb,Card,10",
+			want:     []string{"4:1 error"},
+		},
+		{
+			name:     "markdown strings",
+			renderer: "confluence",
+			source:   "flowchart LR\n    A[\"`**Order** placed`\"] --> B[\"`Paid`\"]",
+			want:     []string{"2:7 warning", "2:35 warning"},
+		},
+		{
+			name:     "markdown strings rendered",
+			renderer: "github",
+			source:   "flowchart LR\n    A[\"`**Order** placed`\"] --> B",
+		},
+		{
+			name:     "beta diagram type",
+			renderer: "notion",
+			source:   "sankey-beta\n%% Payments by channel\nWeb,Card,10",
+			want:     []string{"1:1 error"},
+		},
+		{
+			name:     "beta diagram type rendered",
+			renderer: "github",
+			source:   "sankey-beta\nWeb,Card,10",
+		},
+		{
+			name:     "click handlers disabled",
+			renderer: "github",
+			source:   "flowchart LR\n    A --> B\n    click A href \"https://example.com\"\n    click B callback",
+			want:     []string{"3:5 warning", "4:5 warning"},
+		},
+		{
+			name:     "class diagram links disabled",
+			renderer: "github",
+			source:   "classDiagram\n    class Order\n    link Order \"https://example.com\"\n    callback Order \"showOrder\"",
+			want:     []string{"3:5 warning", "4:5 warning"},
+		},
+		{
+			name:     "click handlers allowed",
+			renderer: "confluence",
+			source:   "flowchart LR\n    A --> B\n    click A href \"https://example.com\"",
+		},
+		{
+			name:     "locked init settings",
+			renderer: "github",
+			source:   "%%{init: {'theme': 'dark', 'securityLevel': 'loose', maxEdges: 900}}%%\nflowchart LR\n    A --> B",
+			want:     []string{"1:29 warning", "1:54 warning"},
+		},
+		{
+			name:     "locked init settings after front matter",
+			renderer: "github",
+			source:   "---\ntitle: Checkout\n---\n%%{init: {\"securityLevel\": \"loose\"}}%%\nsequenceDiagram\n    A->>B: hi",
+			want:     []string{"4:12 warning"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			renderer, _ := validator.LookupRenderer(tt.renderer)
+			rule := &validator.RendererCompatibility{Renderer: renderer}
+			if rule.Name() != "renderer-compatibility" {
+				t.Errorf("Name() = %q", rule.Name())
+			}
+			var got []string
+			for _, err := range rule.ValidateDiagram(diagram) {
+				got = append(got, fmt.Sprintf("%d:%d %s", err.Line, err.Column, err.Severity))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package validator_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/sammcj/mermaid-check/ast"
 	"github.com/sammcj/mermaid-check/validator"
 )
 
@@ -21,10 +23,18 @@ func TestRuleNames(t *testing.T) {
 		// Class rules
 		{"NoDuplicateClasses", &validator.NoDuplicateClasses{}, "no-duplicate-classes"},
 		{"ValidClassReferences", &validator.ValidClassReferences{}, "valid-class-references"},
+		{"NoDuplicateClassRelationships", &validator.NoDuplicateClassRelationships{}, "no-duplicate-class-relationships"},
 
 		// State rules
 		{"NoDuplicateStates", &validator.NoDuplicateStates{}, "no-duplicate-states"},
 		{"ValidStateReferences", &validator.ValidStateReferences{}, "valid-state-references"},
+		{"NoEmptyTransitionLabels", &validator.NoEmptyTransitionLabels{}, "no-empty-transition-labels"},
+		{"NoDuplicateTransitions", &validator.NoDuplicateTransitions{}, "no-duplicate-transitions"},
+
+		// Flowchart rules
+		{"NoDuplicateLinks", &validator.NoDuplicateLinks{}, "no-duplicate-links"},
+		{"UnescapedQuotes", &validator.UnescapedQuotes{}, "unescaped-quotes"},
+		{"UnknownEntities", &validator.UnknownEntities{}, "unknown-entities"},
 	}
 
 	for _, tt := range tests {
@@ -35,3 +45,93 @@ func TestRuleNames(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleName(t *testing.T) {
+	tests := []struct {
+		rule any
+		want string
+	}{
+		{&validator.NoDuplicateLinks{}, "no-duplicate-links"},
+		{&validator.PositiveValuesRule{}, "positive-values"},
+		{&validator.XYChartUniqueSeriesNamesRule{}, "xy-chart-unique-series-names"},
+		{&validator.C4ContainerPlacementRule{}, "c4-container-placement"},
+		{&validator.NoDuplicateERRelationshipsRule{}, "no-duplicate-er-relationships"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := validator.RuleName(tt.rule); got != tt.want {
+				t.Errorf("RuleName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorRule(t *testing.T) {
+	pie := &ast.PieDiagram{
+		DataEntries: []ast.PieEntry{
+			{Label: "A", Value: 10, Pos: ast.Position{Line: 2, Column: 1}},
+			{Label: "A", Value: 20, Pos: ast.Position{Line: 3, Column: 1}},
+		},
+	}
+	errors := validator.ValidatePie(pie, false)
+	if len(errors) != 1 || errors[0].Rule != "no-duplicate-labels" {
+		t.Errorf("expected one no-duplicate-labels error, got %+v", errors)
+	}
+
+	flowchart := &ast.Flowchart{
+		Direction: "TD",
+		Statements: []ast.Statement{
+			&ast.Link{From: "A", To: "A", Arrow: "-->", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.Link{From: "A", To: "A", Arrow: "-->", Pos: ast.Position{Line: 3, Column: 1}},
+		},
+	}
+	all := append(validator.New(validator.DefaultRules()...).Validate(flowchart),
+		validator.ValidateDiagramRules(flowchart, &validator.SelfLoops{})...)
+	if len(all) != 3 {
+		t.Fatalf("expected 3 errors, got %+v", all)
+	}
+
+	kept := validator.WithoutRules(all, "self-loops")
+	if len(kept) != 1 || kept[0].Rule != "no-duplicate-links" {
+		t.Errorf("WithoutRules() = %+v, want only the no-duplicate-links error", kept)
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	errors := []validator.ValidationError{
+		{Line: 3, Column: 1, Rule: "b", Message: "x"},
+		{Line: 2, Column: 5, Rule: "a", Message: "x"},
+		{Line: 3, Column: 1, Rule: "a", Message: "y"},
+		{Line: 2, Column: 1, Rule: "z", Message: "x"},
+		{Line: 3, Column: 1, Rule: "a", Message: "x"},
+	}
+	validator.SortErrors(errors)
+
+	want := []string{"2:1 z x", "2:5 a x", "3:1 a x", "3:1 a y", "3:1 b x"}
+	for i, err := range errors {
+		if got := fmt.Sprintf("%d:%d %s %s", err.Line, err.Column, err.Rule, err.Message); got != want[i] {
+			t.Errorf("errors[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	errors := []validator.ValidationError{
+		{Line: 2, Column: 1, Rule: "a", Message: "x"},
+		{Line: 2, Column: 1, Rule: "b", Message: "x"},
+		{Line: 2, Column: 1, Rule: "a", Message: "x", Suggestion: "repeated"},
+		{Line: 2, Column: 2, Rule: "a", Message: "x"},
+		{Line: 2, Column: 1, Rule: "a", Message: "y"},
+	}
+	kept := validator.Deduplicate(errors)
+	if len(kept) != 4 {
+		t.Fatalf("Deduplicate() kept %d errors, want 4: %+v", len(kept), kept)
+	}
+	for _, err := range kept {
+		if err.Suggestion != "" {
+			t.Errorf("Deduplicate() kept the repeat rather than the first error: %+v", err)
+		}
+	}
+}
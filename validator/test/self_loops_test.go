@@ -0,0 +1,86 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestSelfLoops(t *testing.T) {
+	flowchart := &ast.Flowchart{
+		Direction: "TD",
+		Statements: []ast.Statement{
+			&ast.Link{From: "A", To: "B", Arrow: "-->", Pos: ast.Position{Line: 2, Column: 5}},
+			&ast.Subgraph{
+				ID: "sub",
+				Statements: []ast.Statement{
+					&ast.Link{From: "C", To: "C", Arrow: "-->", Pos: ast.Position{Line: 4, Column: 5}},
+				},
+			},
+		},
+	}
+	state := &ast.StateDiagram{
+		Statements: []ast.StateStmt{
+			&ast.Transition{From: "Idle", To: "Idle", Label: "tick", Pos: ast.Position{Line: 2, Column: 1}},
+		},
+	}
+	class := &ast.ClassDiagram{
+		Statements: []ast.ClassStmt{
+			&ast.Relationship{From: "Node", To: "Node", Type: "association", Pos: ast.Position{Line: 2, Column: 1}},
+		},
+	}
+	c4 := &ast.C4Diagram{
+		Relationships: []ast.C4Relationship{
+			{RelType: "Rel", From: "api", To: "api", Label: "Calls", Pos: ast.Position{Line: 3, Column: 1}},
+			{RelType: "Rel", From: "web", To: "api", Label: "Calls", Pos: ast.Position{Line: 4, Column: 1}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		rule         *validator.SelfLoops
+		diagram      ast.Diagram
+		wantLine     int
+		wantSeverity validator.Severity
+	}{
+		{"flowchart link in subgraph", &validator.SelfLoops{}, flowchart, 4, validator.SeverityWarning},
+		{"state transition is informational", &validator.SelfLoops{}, state, 2, validator.SeverityInfo},
+		{"class relationship", &validator.SelfLoops{}, class, 2, validator.SeverityWarning},
+		{"c4 relationship is an error", &validator.SelfLoops{}, c4, 3, validator.SeverityError},
+		{
+			"severity override",
+			&validator.SelfLoops{Severities: map[string]validator.Severity{"state": validator.SeverityError}},
+			state, 2, validator.SeverityError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := tt.rule.ValidateDiagram(tt.diagram)
+			if len(errors) != 1 {
+				t.Fatalf("expected 1 error, got %+v", errors)
+			}
+			if errors[0].Line != tt.wantLine || errors[0].Severity != tt.wantSeverity {
+				t.Errorf("got line %d severity %v, want line %d severity %v", errors[0].Line, errors[0].Severity, tt.wantLine, tt.wantSeverity)
+			}
+		})
+	}
+
+	rule := &validator.SelfLoops{Disabled: []string{"c4"}}
+	if errors := rule.ValidateDiagram(c4); len(errors) != 0 {
+		t.Errorf("expected disabled diagram type to be skipped, got %+v", errors)
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, want := range []validator.Severity{validator.SeverityError, validator.SeverityWarning, validator.SeverityInfo} {
+		got, err := validator.ParseSeverity(want.String())
+		if err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %v, %v", want.String(), got, err)
+		}
+	}
+	if _, err := validator.ParseSeverity("fatal"); err == nil {
+		t.Error("ParseSeverity(\"fatal\") expected error")
+	}
+}
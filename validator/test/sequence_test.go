@@ -1,9 +1,11 @@
 package validator_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
 	"github.com/sammcj/mermaid-check/validator"
 )
 
@@ -98,6 +100,11 @@ func TestValidMessageArrows(t *testing.T) {
 					t.Logf("  error: %s", err.Message)
 				}
 			}
+			for _, err := range errors {
+				if err.Suggestion == "" {
+					t.Errorf("expected a suggestion for %q", err.Message)
+				}
+			}
 		})
 	}
 }
@@ -336,6 +343,7 @@ func TestValidMessageArrowsExtended(t *testing.T) {
 		{"solid open arrow", "-)", 0},
 		{"dotted open arrow", "--)", 0},
 		{"bidirectional", "<<->>", 0},
+		{"dotted bidirectional", "<<-->>", 0},
 		{"invalid", ">>>", 1},
 		{"empty", "", 1},
 	}
@@ -357,6 +365,24 @@ func TestValidMessageArrowsExtended(t *testing.T) {
 	}
 }
 
+func TestValidMessageArrows_SuggestionListsArrows(t *testing.T) {
+	diagram := &ast.SequenceDiagram{
+		Type: "sequence",
+		Statements: []ast.SeqStmt{
+			&ast.Message{From: "A", To: "B", Arrow: ">>>", Pos: ast.Position{Line: 2, Column: 1}},
+		},
+	}
+	errors := (&validator.ValidMessageArrows{}).ValidateSequence(diagram)
+	if len(errors) != 1 {
+		t.Fatalf("ValidateSequence() errors = %d, want 1", len(errors))
+	}
+	for _, arrow := range ast.MessageArrows {
+		if !strings.Contains(errors[0].Suggestion, arrow) {
+			t.Errorf("suggestion %q does not list %q", errors[0].Suggestion, arrow)
+		}
+	}
+}
+
 func TestValidNotePositionsExtended(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -388,3 +414,258 @@ func TestValidNotePositionsExtended(t *testing.T) {
 		})
 	}
 }
+
+func TestValidAutonumber(t *testing.T) {
+	rule := &validator.ValidAutonumber{}
+	if rule.Name() != "valid-autonumber" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "valid-autonumber")
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErrors int
+	}{
+		{"bare", nil, 0},
+		{"start", []string{"10"}, 0},
+		{"start and step", []string{"10", "5"}, 0},
+		{"negative start", []string{"-5", "1"}, 0},
+		{"off", []string{"off"}, 0},
+		{"off with arguments", []string{"off", "10"}, 1},
+		{"non-integer start", []string{"ten"}, 1},
+		{"non-integer step", []string{"10", "1.5"}, 1},
+		{"zero step", []string{"10", "0"}, 1},
+		{"too many arguments", []string{"1", "2", "3"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := &ast.SequenceDiagram{
+				Type: "sequence",
+				Statements: []ast.SeqStmt{
+					&ast.Loop{Label: "retry", Statements: []ast.SeqStmt{
+						&ast.Autonumber{Enabled: true, Args: tt.args, Pos: ast.Position{Line: 3, Column: 1}},
+					}},
+				},
+			}
+			errors := rule.ValidateSequence(diagram)
+			if len(errors) != tt.wantErrors {
+				t.Errorf("ValidateSequence() errors = %v, want %d", errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidBoxes(t *testing.T) {
+	rule := &validator.ValidBoxes{}
+	if rule.Name() != "valid-boxes" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "valid-boxes")
+	}
+
+	participants := []ast.Participant{{ID: "Alice", Pos: ast.Position{Line: 3, Column: 1}}}
+	tests := []struct {
+		name         string
+		colour       string
+		participants []ast.Participant
+		wantErrors   int
+	}{
+		{"no colour", "", participants, 0},
+		{"named colour", "Aqua", participants, 0},
+		{"transparent", "transparent", participants, 0},
+		{"hex colour", "#e0f0ff", participants, 0},
+		{"rgb colour", "rgb(33, 66, 99)", participants, 0},
+		{"hsl colour", "hsl(200, 50%, 80%)", participants, 0},
+		{"unknown colour name", "Aqau", participants, 1},
+		{"invalid hex", "#ggg", participants, 1},
+		{"empty box", "Aqua", nil, 1},
+		{"empty box with invalid colour", "#12", nil, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := &ast.SequenceDiagram{
+				Type: "sequence",
+				Statements: []ast.SeqStmt{
+					&ast.Box{Colour: tt.colour, Label: "Group", Participants: tt.participants, Pos: ast.Position{Line: 2, Column: 1}},
+				},
+			}
+			errors := rule.ValidateSequence(diagram)
+			if len(errors) != tt.wantErrors {
+				t.Errorf("ValidateSequence() errors = %v, want %d", errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestUnusedParticipants(t *testing.T) {
+	diagram, err := parser.Parse(`sequenceDiagram
+    participant Alice
+    participant Bob
+    participant Carol
+    box Backend
+        participant Db
+    end
+    Alice->>Bob: Hi
+    loop Poll
+        Note over Db: waits
+    end
+    %% Bob->>Carol: Forward`)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	errors := (&validator.UnusedParticipants{}).ValidateSequence(diagram.(*ast.SequenceDiagram))
+	if len(errors) != 1 || errors[0].Line != 4 || !strings.Contains(errors[0].Message, "'Carol'") {
+		t.Errorf("expected Carol to be reported on line 4, got %v", errors)
+	}
+}
+
+func TestParticipantAliasShadowing(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantLines []int
+	}{
+		{
+			name:   "distinct aliases",
+			source: "sequenceDiagram\n    participant A as Alice\n    participant B as Bob\n    A->>B: Hi",
+		},
+		{
+			name:   "alias matching its own ID",
+			source: "sequenceDiagram\n    participant A as A\n    A->>A: Think",
+		},
+		{
+			name:      "alias is a declared participant",
+			source:    "sequenceDiagram\n    participant A as B\n    participant B\n    A->>B: Hi",
+			wantLines: []int{2},
+		},
+		{
+			name:      "alias is an implicit participant in a box",
+			source:    "sequenceDiagram\n    box Backend\n        participant Db as Cache\n    end\n    Cache->>Db: Miss",
+			wantLines: []int{3},
+		},
+	}
+
+	rule := &validator.ParticipantAliasShadowing{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			errors := rule.ValidateSequence(diagram.(*ast.SequenceDiagram))
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantLines), errors)
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] {
+					t.Errorf("error %d on line %d, want %d", i, err.Line, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParticipantsDeclaredFirst(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantLines []int
+	}{
+		{
+			name:   "declared first",
+			source: "sequenceDiagram\n    participant A\n    actor B\n    A->>B: Hi",
+		},
+		{
+			name:      "declared after first message",
+			source:    "sequenceDiagram\n    participant A\n    participant B\n    A->>B: Hi\n    participant C\n    B->>C: Forward",
+			wantLines: []int{5},
+		},
+		{
+			name:      "implicit participant",
+			source:    "sequenceDiagram\n    participant A\n    A->>B: Hi\n    B->>A: Hello",
+			wantLines: []int{3},
+		},
+	}
+
+	rule := &validator.ParticipantsDeclaredFirst{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			errors := rule.ValidateDiagram(diagram)
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantLines), errors)
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] {
+					t.Errorf("error %d on line %d, want %d", i, err.Line, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConditionalParticipants(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantLines []int
+	}{
+		{
+			name:   "participants in the main flow",
+			source: "sequenceDiagram\n    participant A\n    participant B\n    A->>B: Hi\n    opt retry\n        A->>B: Again\n    end",
+		},
+		{
+			name:      "participant only inside alt",
+			source:    "sequenceDiagram\n    participant A\n    participant B\n    participant C\n    A->>B: Hi\n    alt fails\n        B->>C: Report\n    else\n        B->>A: Done\n    end",
+			wantLines: []int{4},
+		},
+		{
+			name:      "implicit participant only inside opt",
+			source:    "sequenceDiagram\n    A->>B: Hi\n    opt cache\n        B->>Cache: Store\n    end",
+			wantLines: []int{4},
+		},
+		{
+			name:   "loop and par are not conditional",
+			source: "sequenceDiagram\n    loop poll\n        A->>B: Hi\n    end\n    par fan out\n        A->>C: Hi\n    end",
+		},
+	}
+
+	rule := &validator.ConditionalParticipants{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			errors := rule.ValidateSequence(diagram.(*ast.SequenceDiagram))
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantLines), errors)
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] || err.Severity != validator.SeverityInfo {
+					t.Errorf("error %d on line %d with severity %v, want line %d and info", i, err.Line, err.Severity, tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMaxParticipants(t *testing.T) {
+	diagram, err := parser.Parse("sequenceDiagram\n    participant A\n    participant B\n    A->>B: Hi\n    B->>C: Forward\n    C->>D: Forward")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	sequence := diagram.(*ast.SequenceDiagram)
+
+	if errors := (&validator.MaxParticipants{}).ValidateSequence(sequence); len(errors) != 0 {
+		t.Errorf("expected no errors with the default limit, got %v", errors)
+	}
+	errors := (&validator.MaxParticipants{Max: 2}).ValidateSequence(sequence)
+	if len(errors) != 1 || errors[0].Line != 5 || !strings.Contains(errors[0].Message, "4 participants (limit 2)") {
+		t.Errorf("expected the third participant to be reported on line 5, got %v", errors)
+	}
+}
@@ -0,0 +1,44 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestSkippedLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		wantLine int
+	}{
+		{"flowchart without skipped lines", "flowchart LR\n    A --> B", 0},
+		{"flowchart", "flowchart LR\n    A --> B\n    B -> C", 3},
+		{"class diagram", "classDiagram\n    class Animal\n    Animal ==> Duck", 3},
+		{"state diagram", "stateDiagram-v2\n    Idle => Busy", 2},
+		{"diagram type that never skips", "sequenceDiagram\n    A->>B: Hi", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := (&validator.SkippedLines{}).ValidateDiagram(diagram)
+			if tt.wantLine == 0 {
+				if len(errors) != 0 {
+					t.Errorf("unexpected errors: %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+			}
+			if errors[0].Line != tt.wantLine || errors[0].Severity != validator.SeverityInfo {
+				t.Errorf("error = %+v, want info on line %d", errors[0], tt.wantLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+// misspelling is an expected spell-check error.
+type misspelling struct {
+	line, column int
+	word         string
+}
+
+func TestSpellCheck(t *testing.T) {
+	checker := validator.NewWordlistChecker(
+		"start", "process", "the", "order", "done", "yes", "no", "valid",
+		"alice", "bob", "hello", "how", "are", "you", "fine", "thanks", "retry",
+	)
+	rule := &validator.SpellCheck{Checker: checker}
+
+	if rule.Name() != "spell-check" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "spell-check")
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		expected []misspelling
+	}{
+		{
+			name:   "flowchart spelt correctly",
+			source: "flowchart TD\n    A[Start] -->|Yes| B(Process the order)\n    B --> C{Valid?}",
+		},
+		{
+			name:     "flowchart node label",
+			source:   "flowchart TD\n    A[Start] --> B(Proccess the order)",
+			expected: []misspelling{{2, 20, "Proccess"}},
+		},
+		{
+			name:     "flowchart edge label and subgraph title",
+			source:   "flowchart TD\n    subgraph s1 [Chekout]\n    A[Start] -->|Yess| B[Done]\n    end",
+			expected: []misspelling{{2, 18, "Chekout"}, {3, 18, "Yess"}},
+		},
+		{
+			name:     "acronyms, numbers and single letters are skipped",
+			source:   "flowchart TD\n    A[HTTP v2 request x] --> B[Done]",
+			expected: []misspelling{{2, 15, "request"}},
+		},
+		{
+			name:     "sequence messages and participants",
+			source:   "sequenceDiagram\n    participant A as Alise\n    A->>Bob: Hello, how are you?\n    loop Retry\n        Bob-->>A: Fine thankss\n    end",
+			expected: []misspelling{{2, 22, "Alise"}, {5, 24, "thankss"}},
+		},
+		{
+			name:   "other diagram types are not checked",
+			source: "pie\n    \"Misspeled\" : 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.ValidateDiagram(diagram)
+			if len(errors) != len(tt.expected) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.expected), errors)
+			}
+			for i, err := range errors {
+				if err.Severity != validator.SeverityWarning {
+					t.Errorf("error %d severity = %v, want warning", i, err.Severity)
+				}
+				want := tt.expected[i]
+				if err.Line != want.line || err.Column != want.column {
+					t.Errorf("error %d at %d:%d, want %d:%d (%s)", i, err.Line, err.Column, want.line, want.column, err.Message)
+				}
+				if want := "possible misspelling '" + want.word + "'"; err.Message != want {
+					t.Errorf("error %d message = %q, want %q", i, err.Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpellCheck_NoChecker(t *testing.T) {
+	diagram, err := parser.Parse("flowchart TD\n    A[Anyting] --> B")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if errors := (&validator.SpellCheck{}).ValidateDiagram(diagram); len(errors) != 0 {
+		t.Errorf("expected no errors without a checker, got %v", errors)
+	}
+}
+
+func TestWordlistChecker_AddFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# project words\nKubernetes\n\n  Terraform  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := validator.NewWordlistChecker()
+	if err := checker.AddFile(path); err != nil {
+		t.Fatalf("AddFile() error = %v", err)
+	}
+
+	for _, word := range []string{"kubernetes", "Kubernetes", "TERRAFORM"} {
+		if !checker.Correct(word) {
+			t.Errorf("Correct(%q) = false, want true", word)
+		}
+	}
+	if checker.Correct("project") {
+		t.Error("comment lines should not add words")
+	}
+
+	if err := checker.AddFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("AddFile() expected error for missing file")
+	}
+}
+
+func TestWordlistChecker_AddReader(t *testing.T) {
+	checker := validator.NewWordlistChecker()
+	if err := checker.AddReader(strings.NewReader("# comment\nMermaid\n")); err != nil {
+		t.Fatalf("AddReader() error = %v", err)
+	}
+	if !checker.Correct("mermaid") || checker.Correct("comment") {
+		t.Error("AddReader() should add words and skip comments")
+	}
+}
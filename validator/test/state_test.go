@@ -111,6 +111,53 @@ func TestValidStateReferences(t *testing.T) {
 	}
 }
 
+func TestNoEmptyTransitionLabels(t *testing.T) {
+	diagram := &ast.StateDiagram{
+		Statements: []ast.StateStmt{
+			&ast.Transition{From: "A", To: "B", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.Transition{From: "B", To: "C", HasLabel: true, Pos: ast.Position{Line: 3, Column: 1}},
+			&ast.Transition{From: "C", To: "D", Label: "go", HasLabel: true, Pos: ast.Position{Line: 4, Column: 1}},
+		},
+	}
+
+	rule := &validator.NoEmptyTransitionLabels{}
+	if rule.Name() != "no-empty-transition-labels" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "no-empty-transition-labels")
+	}
+
+	errors := rule.ValidateState(diagram)
+	if len(errors) != 1 || errors[0].Line != 3 {
+		t.Errorf("ValidateState() errors = %v, want one error on line 3", errors)
+	}
+}
+
+func TestNoDuplicateTransitions(t *testing.T) {
+	diagram := &ast.StateDiagram{
+		Statements: []ast.StateStmt{
+			&ast.Transition{From: "A", To: "B", Label: "go", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.Transition{From: "A", To: "B", Label: "stop", Pos: ast.Position{Line: 3, Column: 1}},
+			&ast.State{
+				ID:          "Composite",
+				IsComposite: true,
+				Nested: []ast.StateStmt{
+					&ast.Transition{From: "A", To: "B", Label: "go", Pos: ast.Position{Line: 5, Column: 1}},
+				},
+				Pos: ast.Position{Line: 4, Column: 1},
+			},
+		},
+	}
+
+	rule := &validator.NoDuplicateTransitions{}
+	if rule.Name() != "no-duplicate-transitions" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "no-duplicate-transitions")
+	}
+
+	errors := rule.ValidateState(diagram)
+	if len(errors) != 1 || errors[0].Line != 5 {
+		t.Errorf("ValidateState() errors = %v, want one error on line 5", errors)
+	}
+}
+
 func TestStateDefaultRules(t *testing.T) {
 	rules := validator.StateDefaultRules()
 	if len(rules) != 2 {
@@ -120,8 +167,8 @@ func TestStateDefaultRules(t *testing.T) {
 
 func TestStateStrictRules(t *testing.T) {
 	rules := validator.StateStrictRules()
-	if len(rules) != 2 {
-		t.Errorf("StateStrictRules() returned %d rules, want 2", len(rules))
+	if len(rules) != 4 {
+		t.Errorf("StateStrictRules() returned %d rules, want 4", len(rules))
 	}
 }
 
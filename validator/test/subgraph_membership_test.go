@@ -0,0 +1,65 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestSubgraphMembership(t *testing.T) {
+	rule := &validator.SubgraphMembership{}
+
+	if rule.Name() != "subgraph-membership" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "subgraph-membership")
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		messages []string
+	}{
+		{
+			name:   "links between subgraphs outside them",
+			source: "flowchart TD\n    subgraph a\n        x\n    end\n    subgraph b\n        y\n    end\n    x --> y",
+		},
+		{
+			name:   "node in nested subgraphs",
+			source: "flowchart TD\n    subgraph outer\n        x --> y\n        subgraph inner\n            x\n        end\n    end",
+		},
+		{
+			name:     "node in sibling subgraphs",
+			source:   "flowchart TD\n    subgraph a\n        x --> y\n    end\n    subgraph b\n        x --> z\n        x --> w\n    end",
+			messages: []string{"node 'x' is used in subgraph 'b' but Mermaid draws it in subgraph 'a'"},
+		},
+		{
+			name:     "node in an inner subgraph and its outer subgraph's sibling",
+			source:   "flowchart TD\n    subgraph a\n        subgraph inner\n            x\n        end\n    end\n    subgraph b\n        x\n    end",
+			messages: []string{"node 'x' is used in subgraph 'b' but Mermaid draws it in subgraph 'inner'"},
+		},
+		{
+			name:   "link to a subgraph",
+			source: "flowchart TD\n    subgraph a\n        x --> b\n    end\n    subgraph b\n        y\n    end",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != len(tt.messages) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.messages), len(errors), errors)
+			}
+			for i, want := range tt.messages {
+				if !strings.Contains(errors[i].Message, want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errors[i].Message, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/parser"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestTerminology(t *testing.T) {
+	rule := &validator.Terminology{Terms: []validator.Term{
+		{Avoid: "DB", Prefer: "Database"},
+		{Avoid: "color", Prefer: "colour"},
+		{Avoid: "log in", Prefer: "sign in"},
+		{Avoid: "master"},
+	}}
+
+	if rule.Name() != "terminology" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "terminology")
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		messages []string
+		line     int
+		column   int
+	}{
+		{
+			name:   "preferred terms only",
+			source: "flowchart LR\n    A[Database] --> B[Colour picker]",
+		},
+		{
+			name:     "flowchart label",
+			source:   "flowchart LR\n    A[App] --> B[(Orders DB)]",
+			messages: []string{"use 'Database' instead of 'DB'"},
+			line:     2,
+			column:   26,
+		},
+		{
+			name:   "matches whole words only",
+			source: "flowchart LR\n    A[DBA team] --> B[Colorado]",
+		},
+		{
+			name:     "phrase ignoring case in a sequence message",
+			source:   "sequenceDiagram\n    User->>App: Log  in",
+			messages: []string{"use 'sign in' instead of 'Log  in'"},
+			line:     2,
+			column:   17,
+		},
+		{
+			name:     "pie title",
+			source:   "pie title Favourite color\n    \"Red\" : 1",
+			messages: []string{"use 'colour' instead of 'color'"},
+			line:     1,
+			column:   21,
+		},
+		{
+			name:     "class note",
+			source:   "classDiagram\n    class Repo\n    note for Repo \"Pushes to master\"",
+			messages: []string{"avoid 'master'"},
+			line:     3,
+			column:   30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.ValidateDiagram(diagram)
+			if len(errors) != len(tt.messages) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.messages), errors)
+			}
+			for i, err := range errors {
+				if err.Message != tt.messages[i] {
+					t.Errorf("message = %q, want %q", err.Message, tt.messages[i])
+				}
+				if err.Line != tt.line || err.Column != tt.column {
+					t.Errorf("position = %d:%d, want %d:%d", err.Line, err.Column, tt.line, tt.column)
+				}
+				if err.Severity != validator.SeverityWarning {
+					t.Errorf("severity = %v, want warning", err.Severity)
+				}
+			}
+		})
+	}
+}
@@ -337,10 +337,95 @@ func TestNoEmptyPeriodsRule(t *testing.T) {
 	}
 }
 
+func TestNoEmptySectionsRule(t *testing.T) {
+	diagram := &ast.TimelineDiagram{
+		Type: "timeline",
+		Sections: []ast.TimelineSection{
+			{Name: "Empty", Pos: ast.Position{Line: 2, Column: 1}},
+			{
+				Name:    "Full",
+				Periods: []ast.TimelinePeriod{{TimePeriod: "2024", Events: []string{"Event"}}},
+				Pos:     ast.Position{Line: 3, Column: 1},
+			},
+		},
+	}
+
+	rule := &validator.NoEmptySectionsRule{}
+	errors := rule.Validate(diagram)
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errors))
+	}
+	if errors[0].Line != 2 || errors[0].Severity != validator.SeverityWarning {
+		t.Errorf("expected warning on line 2, got %+v", errors[0])
+	}
+}
+
+func TestChronologicalPeriodsRule(t *testing.T) {
+	periods := func(names ...string) []ast.TimelineSection {
+		section := ast.TimelineSection{}
+		for i, name := range names {
+			section.Periods = append(section.Periods, ast.TimelinePeriod{
+				TimePeriod: name,
+				Events:     []string{"Event"},
+				Pos:        ast.Position{Line: i + 2, Column: 1},
+			})
+		}
+		return []ast.TimelineSection{section}
+	}
+
+	tests := []struct {
+		name      string
+		sections  []ast.TimelineSection
+		wantLines []int
+	}{
+		{
+			name:     "ascending years",
+			sections: periods("2002", "2004", "2004-03", "2004-03-15", "2010"),
+		},
+		{
+			name:     "non-date periods are ignored",
+			sections: periods("2020", "Early Stage", "Q1 2019", "2021"),
+		},
+		{
+			name:      "descending years",
+			sections:  periods("2004", "2002", "2005"),
+			wantLines: []int{3},
+		},
+		{
+			name:      "descending decades",
+			sections:  periods("1950s", "1940s"),
+			wantLines: []int{3},
+		},
+		{
+			name: "descending across sections",
+			sections: append(periods("2020"), ast.TimelineSection{
+				Name:    "Later",
+				Periods: []ast.TimelinePeriod{{TimePeriod: "2019-12", Pos: ast.Position{Line: 5, Column: 1}}},
+			}),
+			wantLines: []int{5},
+		},
+	}
+
+	rule := &validator.ChronologicalPeriodsRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := rule.Validate(&ast.TimelineDiagram{Type: "timeline", Sections: tt.sections})
+			if len(errors) != len(tt.wantLines) {
+				t.Fatalf("expected %d errors, got %d", len(tt.wantLines), len(errors))
+			}
+			for i, err := range errors {
+				if err.Line != tt.wantLines[i] || err.Severity != validator.SeverityWarning {
+					t.Errorf("expected warning on line %d, got %+v", tt.wantLines[i], err)
+				}
+			}
+		})
+	}
+}
+
 func TestTimelineDefaultRules(t *testing.T) {
 	rules := validator.TimelineDefaultRules()
 
-	expectedRules := 2
+	expectedRules := 3
 	if len(rules) != expectedRules {
 		t.Errorf("expected %d default rules, got %d", expectedRules, len(rules))
 	}
@@ -349,7 +434,7 @@ func TestTimelineDefaultRules(t *testing.T) {
 func TestTimelineStrictRules(t *testing.T) {
 	rules := validator.TimelineStrictRules()
 
-	minExpectedRules := 2
+	minExpectedRules := 4
 	if len(rules) < minExpectedRules {
 		t.Errorf("expected at least %d strict rules, got %d", minExpectedRules, len(rules))
 	}
@@ -0,0 +1,102 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/validator"
+)
+
+func TestConfusableCharacters(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		wantColumns []int
+		wantFixed   string
+	}{
+		{
+			name:   "plain ASCII",
+			source: "flowchart TD\n    A --> B",
+		},
+		{
+			name:        "non-breaking space",
+			source:      "flowchart TD\n    A\u00a0--> B",
+			wantColumns: []int{6},
+			wantFixed:   "    A --> B",
+		},
+		{
+			name:        "zero-width space",
+			source:      "flowchart TD\n    A --> B\u200b",
+			wantColumns: []int{12},
+			wantFixed:   "    A --> B",
+		},
+		{
+			name:        "smart quotes",
+			source:      "flowchart TD\n    A[\u201cStart\u201d] --> B",
+			wantColumns: []int{7, 13},
+			wantFixed:   "    A[\"Start\"] --> B",
+		},
+		{
+			name:        "em dash in arrow",
+			source:      "flowchart TD\n    A \u2014> B",
+			wantColumns: []int{7},
+			wantFixed:   "    A --> B",
+		},
+		{
+			name:   "em dash in text",
+			source: "flowchart TD\n    A[Start \u2014 now] --> B",
+		},
+		{
+			name:   "zero-width non-joiner in Persian",
+			source: "flowchart TD\n    A[\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645] --> B",
+		},
+		{
+			name:   "zero-width joiner in emoji",
+			source: "flowchart TD\n    A[\U0001F469\u200d\U0001F4BB] --> B",
+		},
+	}
+
+	rule := &validator.ConfusableCharacters{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := rule.ValidateDiagram(&ast.Flowchart{Source: tt.source})
+			if len(errors) != len(tt.wantColumns) {
+				t.Fatalf("got %d errors, want %d: %v", len(errors), len(tt.wantColumns), errors)
+			}
+			for i, err := range errors {
+				if err.Line != 2 || err.Column != tt.wantColumns[i] {
+					t.Errorf("error %d at %d:%d, want 2:%d", i, err.Line, err.Column, tt.wantColumns[i])
+				}
+				if err.Fix == nil || err.Fix.Replacement != tt.wantFixed {
+					t.Errorf("error %d fix = %+v, want replacement %q", i, err.Fix, tt.wantFixed)
+				}
+			}
+		})
+	}
+}
+
+func TestConfusableCharacters_Generic(t *testing.T) {
+	diagram := &ast.GenericDiagram{Source: "sankey-beta\nA,B,1\u00a0"}
+	errors := (&validator.ConfusableCharacters{}).ValidateDiagram(diagram)
+	if len(errors) != 1 || errors[0].Line != 2 {
+		t.Errorf("expected one error on line 2, got %v", errors)
+	}
+}
+
+func TestConfusableCharacters_Messages(t *testing.T) {
+	errors := (&validator.ConfusableCharacters{}).ValidateDiagram(&ast.Flowchart{Source: "flowchart TD\n    A[\u2018a\u201d] -->\u00a0B\u200b"})
+	want := []string{
+		"smart quote (U+2018) should be a straight single quote (')",
+		"smart quote (U+201D) should be a straight double quote (\")",
+		"non-breaking space (U+00A0) should be a plain space",
+		"zero-width space (U+200B) should be removed",
+	}
+	if len(errors) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(errors), len(want), errors)
+	}
+	for i, err := range errors {
+		if err.Message != want[i] {
+			t.Errorf("message %d = %q, want %q", i, err.Message, want[i])
+		}
+	}
+}
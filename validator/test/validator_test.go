@@ -1,9 +1,12 @@
 package validator_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sammcj/mermaid-check/ast"
+	"github.com/sammcj/mermaid-check/parser"
 	"github.com/sammcj/mermaid-check/validator"
 )
 
@@ -46,6 +49,278 @@ func TestValidDirection(t *testing.T) {
 	}
 }
 
+func TestValidDirection_Subgraphs(t *testing.T) {
+	rule := &validator.ValidDirection{}
+	flowchart := &ast.Flowchart{
+		Type:      "flowchart",
+		Direction: "TD",
+		Statements: []ast.Statement{
+			&ast.Subgraph{ID: "ok", Title: "ok", Direction: "LR", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.Subgraph{ID: "bad", Title: "bad", Direction: "SIDEWAYS", Pos: ast.Position{Line: 5, Column: 1}},
+		},
+	}
+
+	errors := rule.Validate(flowchart)
+	if len(errors) != 1 || errors[0].Line != 5 {
+		t.Errorf("expected 1 error on line 5, got %v", errors)
+	}
+}
+
+func TestExplicitDirection(t *testing.T) {
+	rule := &validator.ExplicitDirection{}
+
+	tests := []struct {
+		name      string
+		source    string
+		wantFixed string
+	}{
+		{"with direction", "flowchart LR\n    A --> B", ""},
+		{"flowchart without direction", "flowchart\n    A --> B", "flowchart TB"},
+		{"graph without direction", "graph  \n    A --> B", "graph TB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if tt.wantFixed == "" {
+				if len(errors) > 0 {
+					t.Errorf("unexpected errors: %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0].Line != 1 || errors[0].Severity != validator.SeverityWarning {
+				t.Fatalf("expected one warning on line 1, got %v", errors)
+			}
+			if errors[0].Fix == nil || errors[0].Fix.Replacement != tt.wantFixed {
+				t.Errorf("fix = %+v, want replacement %q", errors[0].Fix, tt.wantFixed)
+			}
+		})
+	}
+
+	if errors := (&validator.ValidDirection{}).Validate(&ast.Flowchart{Type: "flowchart"}); len(errors) > 0 {
+		t.Errorf("valid-direction reported a missing direction: %v", errors)
+	}
+}
+
+func TestValidSubgraphReferences(t *testing.T) {
+	rule := &validator.ValidSubgraphReferences{}
+
+	if rule.Name() != "valid-subgraph-references" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "valid-subgraph-references")
+	}
+
+	tests := []struct {
+		name       string
+		source     string
+		errorCount int
+	}{
+		{
+			name:   "link to subgraph ID",
+			source: "flowchart TD\n    subgraph backend[Backend]\n        api --> db\n    end\n    client --> backend",
+		},
+		{
+			name:       "link to subgraph title",
+			source:     "flowchart TD\n    subgraph backend[Backend]\n        api --> db\n    end\n    client --> Backend",
+			errorCount: 1,
+		},
+		{
+			name:   "class assignment to subgraph ID and node",
+			source: "flowchart TD\n    subgraph backend[Backend]\n        api --> db\n    end\n    classDef box fill:#eee\n    class backend,api box",
+		},
+		{
+			name:       "class assignment to subgraph title",
+			source:     "flowchart TD\n    subgraph backend[Backend]\n        api --> db\n    end\n    classDef box fill:#eee\n    class Backend box",
+			errorCount: 1,
+		},
+		{
+			name:       "class assignment to undefined ID",
+			source:     "flowchart TD\n    A --> B\n    classDef box fill:#eee\n    class missing box",
+			errorCount: 1,
+		},
+		{
+			name:   "link inside nested subgraph to outer ID",
+			source: "flowchart TD\n    subgraph outer\n        subgraph inner\n            x --> outer\n        end\n    end",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != tt.errorCount {
+				t.Errorf("expected %d errors, got %d: %v", tt.errorCount, len(errors), errors)
+			}
+		})
+	}
+}
+
+func TestValidSubgraphReferences_NodePosition(t *testing.T) {
+	diagram, err := parser.Parse("flowchart TD\n    A --> B\n    classDef box fill:#eee\n    class A & missing box")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	errors := (&validator.ValidSubgraphReferences{}).Validate(diagram.(*ast.Flowchart))
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+	}
+	if errors[0].Line != 4 || errors[0].Column != 15 {
+		t.Errorf("error at %d:%d, want 4:15 (the missing ID)", errors[0].Line, errors[0].Column)
+	}
+}
+
+func TestValidClassDefinitions(t *testing.T) {
+	rule := &validator.ValidClassDefinitions{}
+
+	if rule.Name() != "valid-class-definitions" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "valid-class-definitions")
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		messages []string
+	}{
+		{
+			name:   "assignment to defined classDef",
+			source: "flowchart TD\n    A --> B\n    classDef hot fill:#f96\n    class A,B hot",
+		},
+		{
+			name:   "classDef defined after use",
+			source: "flowchart TD\n    A --> B\n    class A hot\n    classDef hot fill:#f96",
+		},
+		{
+			name:     "assignment to undefined classDef",
+			source:   "flowchart TD\n    A --> B\n    classDef hot fill:#f96\n    class A hot\n    class B cold",
+			messages: []string{"class assignment references undefined classDef 'cold'"},
+		},
+		{
+			name:     "shorthand to undefined classDef",
+			source:   "flowchart TD\n    A:::cold --> B",
+			messages: []string{"class assignment references undefined classDef 'cold'"},
+		},
+		{
+			name:   "unused classDef",
+			source: "flowchart TD\n    A --> B\n    classDef hot fill:#f96",
+		},
+		{
+			name:   "classDef and assignment inside subgraphs",
+			source: "flowchart TD\n    subgraph s1\n        A --> B\n        classDef hot fill:#f96\n    end\n    class A hot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != len(tt.messages) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.messages), len(errors), errors)
+			}
+			for i, err := range errors {
+				if err.Message != tt.messages[i] {
+					t.Errorf("message = %q, want %q", err.Message, tt.messages[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUnusedClassDefinitions(t *testing.T) {
+	rule := &validator.UnusedClassDefinitions{}
+
+	if rule.Name() != "unused-class-definitions" {
+		t.Errorf("Name() = %q, want %q", rule.Name(), "unused-class-definitions")
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		messages []string
+	}{
+		{
+			name:   "classDef used by class statement",
+			source: "flowchart TD\n    A --> B\n    classDef hot fill:#f96\n    class A hot",
+		},
+		{
+			name:     "unused classDef",
+			source:   "flowchart TD\n    A --> B\n    classDef hot fill:#f96",
+			messages: []string{"classDef 'hot' is never used"},
+		},
+		{
+			name:   "classDef used by shorthand",
+			source: "flowchart TD\n    A:::hot --> B\n    classDef hot fill:#f96",
+		},
+		{
+			name:   "classDef used by shorthand inside subgraph",
+			source: "flowchart TD\n    subgraph s1\n        A:::hot\n    end\n    classDef hot fill:#f96",
+		},
+		{
+			name:     "shorthand only in a comment",
+			source:   "flowchart TD\n    %% A:::hot --> B\n    A --> B\n    classDef hot fill:#f96",
+			messages: []string{"classDef 'hot' is never used"},
+		},
+		{
+			name:     "shorthand only in label text",
+			source:   "flowchart TD\n    A[\"use :::hot\"] --> B\n    classDef hot fill:#f96",
+			messages: []string{"classDef 'hot' is never used"},
+		},
+		{
+			name:   "default classDef",
+			source: "flowchart TD\n    A --> B\n    classDef default fill:#eee",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram, err := parser.Parse(tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			errors := rule.Validate(diagram.(*ast.Flowchart))
+			if len(errors) != len(tt.messages) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.messages), len(errors), errors)
+			}
+			for i, err := range errors {
+				if err.Message != tt.messages[i] {
+					t.Errorf("message = %q, want %q", err.Message, tt.messages[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultRules_ValidFixtures(t *testing.T) {
+	files, err := filepath.Glob("../../testdata/flowchart/valid-*.mmd")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no flowchart fixtures found: %v", err)
+	}
+	v := validator.New(validator.DefaultRules()...)
+	for _, path := range files {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path) //nolint:gosec // Test file paths are safe
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			diagram, err := parser.Parse(string(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if errors := v.Validate(diagram.(*ast.Flowchart)); len(errors) != 0 {
+				t.Errorf("expected no findings, got %v", errors)
+			}
+		})
+	}
+}
+
 func TestNoUndefinedNodes(t *testing.T) {
 	rule := &validator.NoUndefinedNodes{}
 
@@ -216,6 +491,39 @@ func TestNoDuplicateNodeIDs(t *testing.T) {
 	})
 }
 
+func TestUniqueNodeLabels(t *testing.T) {
+	rule := &validator.UniqueNodeLabels{}
+	flowchart := &ast.Flowchart{
+		Direction: "TD",
+		Statements: []ast.Statement{
+			&ast.NodeDef{ID: "A", Label: "Save", Pos: ast.Position{Line: 2, Column: 1}},
+			&ast.NodeDef{ID: "A", Label: "Save", Pos: ast.Position{Line: 3, Column: 1}},
+			&ast.NodeDef{ID: "B", Label: "Load", Pos: ast.Position{Line: 4, Column: 1}},
+			&ast.NodeDef{ID: "C", Label: "Save", Pos: ast.Position{Line: 5, Column: 1}},
+			&ast.Subgraph{
+				ID: "sub",
+				Statements: []ast.Statement{
+					&ast.NodeDef{ID: "C", Label: "Save", Pos: ast.Position{Line: 7, Column: 1}},
+					&ast.NodeDef{ID: "D", Label: "Load", Pos: ast.Position{Line: 8, Column: 1}},
+					&ast.NodeDef{ID: "E", Pos: ast.Position{Line: 9, Column: 1}},
+					&ast.NodeDef{ID: "F", Pos: ast.Position{Line: 10, Column: 1}},
+				},
+			},
+		},
+	}
+
+	errors := rule.Validate(flowchart)
+	if len(errors) != 2 {
+		t.Fatalf("expected 2 errors, got %+v", errors)
+	}
+	if errors[0].Line != 5 || errors[1].Line != 8 {
+		t.Errorf("expected errors on lines 5 and 8, got %d and %d", errors[0].Line, errors[1].Line)
+	}
+	if !contains(errors[0].Message, "node 'A'") {
+		t.Errorf("expected message to name the first node, got %q", errors[0].Message)
+	}
+}
+
 func TestValidator(t *testing.T) {
 	t.Run("default rules", func(t *testing.T) {
 		v := validator.New(validator.DefaultRules()...)
@@ -379,3 +687,21 @@ func TestValidateDiagram(t *testing.T) {
 		}
 	})
 }
+
+func TestMaxLinkLength(t *testing.T) {
+	diagram, err := parser.Parse("flowchart LR\n    A ----> B\n    subgraph S\n        C ------> D\n    end\n    E ~~~~~~ F")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	flowchart := diagram.(*ast.Flowchart)
+
+	errors := (&validator.MaxLinkLength{}).Validate(flowchart)
+	if len(errors) != 2 || errors[0].Line != 4 || errors[1].Line != 6 {
+		t.Errorf("expected errors on lines 4 and 6, got %v", errors)
+	}
+
+	errors = (&validator.MaxLinkLength{Max: 2}).Validate(flowchart)
+	if len(errors) != 3 {
+		t.Errorf("expected 3 errors with a limit of 2, got %v", errors)
+	}
+}
@@ -332,12 +332,78 @@ func TestXYChartValidOrientationRule(t *testing.T) {
 	}
 }
 
+func TestXYChartUniqueSeriesNamesRule(t *testing.T) {
+	diagram := &ast.XYChartDiagram{
+		Series: []ast.XYChartSeries{
+			{Type: "bar", Name: "Revenue", Pos: ast.Position{Line: 4}},
+			{Type: "line", Name: "Revenue", Pos: ast.Position{Line: 5}},
+			{Type: "line", Name: "Costs", Pos: ast.Position{Line: 6}},
+			{Type: "bar", Pos: ast.Position{Line: 7}},
+			{Type: "bar", Pos: ast.Position{Line: 8}},
+		},
+	}
+
+	rule := &validator.XYChartUniqueSeriesNamesRule{}
+	errors := rule.Validate(diagram)
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errors))
+	}
+	if errors[0].Line != 5 || errors[0].Severity != validator.SeverityWarning {
+		t.Errorf("expected warning on line 5, got %+v", errors[0])
+	}
+}
+
+func TestXYChartValuesInRangeRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		yAxis      ast.XYChartAxis
+		values     []float64
+		wantErrors int
+	}{
+		{
+			name:   "values within range",
+			yAxis:  ast.XYChartAxis{IsNumeric: true, Min: 0, Max: 100},
+			values: []float64{0, 50, 100},
+		},
+		{
+			name:       "values outside range",
+			yAxis:      ast.XYChartAxis{IsNumeric: true, Min: 0, Max: 100},
+			values:     []float64{-1, 50, 101.5},
+			wantErrors: 2,
+		},
+		{
+			name:   "inverted range",
+			yAxis:  ast.XYChartAxis{IsNumeric: true, Min: 100, Max: 0},
+			values: []float64{0, 50, 100},
+		},
+		{
+			name:   "categorical y-axis is not checked",
+			yAxis:  ast.XYChartAxis{Categories: []string{"a", "b"}},
+			values: []float64{-1000, 1000},
+		},
+	}
+
+	rule := &validator.XYChartValuesInRangeRule{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := &ast.XYChartDiagram{
+				YAxis:  tt.yAxis,
+				Series: []ast.XYChartSeries{{Type: "bar", Name: "Revenue", Values: tt.values, Pos: ast.Position{Line: 4}}},
+			}
+			errors := rule.Validate(diagram)
+			if len(errors) != tt.wantErrors {
+				t.Errorf("expected %d errors, got %d", tt.wantErrors, len(errors))
+			}
+		})
+	}
+}
+
 func TestXYChartDefaultRules(t *testing.T) {
 	rules := validator.XYChartDefaultRules()
 	if len(rules) == 0 {
 		t.Error("validator.XYChartDefaultRules() returned empty slice")
 	}
-	expectedRuleCount := 5 // XAxisDefined, YAxisDefined, MinimumSeries, ValidSeriesLength, ValidOrientation
+	expectedRuleCount := 7 // XAxisDefined, YAxisDefined, MinimumSeries, ValidSeriesLength, ValidOrientation, UniqueSeriesNames, ValuesInRange
 	if len(rules) != expectedRuleCount {
 		t.Errorf("expected %d rules, got %d", expectedRuleCount, len(rules))
 	}
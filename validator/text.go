@@ -0,0 +1,304 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// textField is a piece of diagram text along with the line it appears on.
+type textField struct {
+	text string
+	line int
+}
+
+// diagramText returns the human-readable text of a diagram (labels, titles,
+// messages, notes, section and task names and so on) along with its source.
+// Identifiers, styling and numeric data are not included.
+func diagramText(diagram ast.Diagram) (string, []textField) {
+	var fields []textField
+	var source string
+	add := func(text string, line int) {
+		if text != "" {
+			fields = append(fields, textField{text, line})
+		}
+	}
+
+	switch d := diagram.(type) {
+	case *ast.Flowchart:
+		source = d.Source
+		collectFlowchartText(d.Statements, &fields)
+	case *ast.SequenceDiagram:
+		source = d.Source
+		collectSequenceText(d.Statements, &fields)
+	case *ast.ClassDiagram:
+		source = d.Source
+		for _, stmt := range d.Statements {
+			switch s := stmt.(type) {
+			case *ast.Relationship:
+				add(s.Label, s.Pos.Line)
+			case *ast.ClassNote:
+				add(s.Text, s.Pos.Line)
+			}
+		}
+	case *ast.StateDiagram:
+		source = d.Source
+		collectStateText(d.Statements, &fields)
+	case *ast.ERDiagram:
+		source = d.Source
+		for _, entity := range d.Entities {
+			add(entity.Alias, entity.Pos.Line)
+			for _, attr := range entity.Attributes {
+				add(attr.Comment, attr.Pos.Line)
+			}
+		}
+		for _, rel := range d.Relationships {
+			add(rel.Label, rel.Pos.Line)
+		}
+	case *ast.GanttDiagram:
+		source = d.Source
+		add(d.Title, 0)
+		for _, section := range d.Sections {
+			add(section.Name, section.Pos.Line)
+			for _, task := range section.Tasks {
+				add(task.Name, task.Pos.Line)
+			}
+		}
+	case *ast.PieDiagram:
+		source = d.Source
+		add(d.Title, 0)
+		for _, entry := range d.DataEntries {
+			add(entry.Label, entry.Pos.Line)
+		}
+	case *ast.JourneyDiagram:
+		source = d.Source
+		add(d.Title, 0)
+		for _, section := range d.Sections {
+			add(section.Name, section.Pos.Line)
+			for _, task := range section.Tasks {
+				add(task.Name, task.Pos.Line)
+			}
+		}
+	case *ast.TimelineDiagram:
+		source = d.Source
+		add(d.Title, 0)
+		for _, section := range d.Sections {
+			add(section.Name, section.Pos.Line)
+			for _, period := range section.Periods {
+				add(period.TimePeriod, period.Pos.Line)
+				for _, event := range period.Events {
+					add(event, 0)
+				}
+			}
+		}
+	case *ast.MindmapDiagram:
+		source = d.Source
+		collectMindmapText(d.Root, &fields)
+	case *ast.QuadrantDiagram:
+		source = d.Source
+		add(d.Title, 0)
+		for _, label := range []string{d.XAxis.Min, d.XAxis.Max, d.YAxis.Min, d.YAxis.Max} {
+			add(label, 0)
+		}
+		for _, label := range d.QuadrantLabels {
+			add(label, 0)
+		}
+		for _, point := range d.Points {
+			add(point.Name, point.Pos.Line)
+		}
+	case *ast.XYChartDiagram:
+		source = d.Source
+		add(d.Title, 0)
+		for _, axis := range []ast.XYChartAxis{d.XAxis, d.YAxis} {
+			add(axis.Label, axis.Pos.Line)
+			for _, category := range axis.Categories {
+				add(category, axis.Pos.Line)
+			}
+		}
+	case *ast.SankeyDiagram:
+		source = d.Source
+		for _, link := range d.Links {
+			add(link.Source, link.Pos.Line)
+			add(link.Target, link.Pos.Line)
+		}
+	case *ast.C4Diagram:
+		source = d.Source
+		add(d.Title, 0)
+		collectC4Text(d.Elements, d.Boundaries, &fields)
+		for _, rel := range d.Relationships {
+			add(rel.Label, rel.Pos.Line)
+			add(rel.Description, rel.Pos.Line)
+		}
+	}
+
+	// Fields without a recorded position are located by searching the source
+	lines := strings.Split(source, "\n")
+	for i := range fields {
+		if fields[i].line == 0 {
+			fields[i].line = lineContaining(lines, fields[i].text)
+		}
+	}
+
+	return source, fields
+}
+
+func collectFlowchartText(statements []ast.Statement, fields *[]textField) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			*fields = append(*fields, textField{s.Label, s.Pos.Line})
+		case *ast.Link:
+			*fields = append(*fields, textField{s.Label, s.Pos.Line})
+		case *ast.Subgraph:
+			*fields = append(*fields, textField{s.Title, s.Pos.Line})
+			collectFlowchartText(s.Statements, fields)
+		}
+	}
+}
+
+func collectSequenceText(statements []ast.SeqStmt, fields *[]textField) {
+	walkSequence(statements, func(stmt ast.SeqStmt) {
+		switch s := stmt.(type) {
+		case *ast.Participant:
+			*fields = append(*fields, textField{participantName(s), s.Pos.Line})
+		case *ast.Message:
+			*fields = append(*fields, textField{s.Text, s.Pos.Line})
+		case *ast.Note:
+			*fields = append(*fields, textField{s.Text, s.Pos.Line})
+		case *ast.Box:
+			for i := range s.Participants {
+				*fields = append(*fields, textField{participantName(&s.Participants[i]), s.Participants[i].Pos.Line})
+			}
+		}
+	})
+}
+
+// walkSequence calls fn for each statement, descending into loop, alt, opt,
+// par, critical and break blocks.
+func walkSequence(statements []ast.SeqStmt, fn func(ast.SeqStmt)) {
+	for _, stmt := range statements {
+		fn(stmt)
+		switch s := stmt.(type) {
+		case *ast.Loop:
+			walkSequence(s.Statements, fn)
+		case *ast.Opt:
+			walkSequence(s.Statements, fn)
+		case *ast.Break:
+			walkSequence(s.Statements, fn)
+		case *ast.Alt:
+			for _, cond := range s.Conditions {
+				walkSequence(cond.Statements, fn)
+			}
+		case *ast.Par:
+			for _, branch := range s.Branches {
+				walkSequence(branch.Statements, fn)
+			}
+		case *ast.Critical:
+			walkSequence(s.Statements, fn)
+			for _, option := range s.Options {
+				walkSequence(option.Statements, fn)
+			}
+		}
+	}
+}
+
+// participantName returns the displayed name of a participant: its alias if it
+// has one, otherwise its ID.
+func participantName(p *ast.Participant) string {
+	if p.Alias != "" {
+		return p.Alias
+	}
+	return p.ID
+}
+
+func collectStateText(statements []ast.StateStmt, fields *[]textField) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.State:
+			if s.Description != "" {
+				*fields = append(*fields, textField{s.Description, s.Pos.Line})
+			}
+			collectStateText(s.Nested, fields)
+		case *ast.Transition:
+			if s.Label != "" {
+				*fields = append(*fields, textField{s.Label, s.Pos.Line})
+			}
+		case *ast.StateNote:
+			*fields = append(*fields, textField{s.Text, s.Pos.Line})
+		}
+	}
+}
+
+func collectMindmapText(node *ast.MindmapNode, fields *[]textField) {
+	if node == nil {
+		return
+	}
+	*fields = append(*fields, textField{node.Text, node.Pos.Line})
+	for _, child := range node.Children {
+		collectMindmapText(child, fields)
+	}
+}
+
+func collectC4Text(elements []ast.C4Element, boundaries []ast.C4Boundary, fields *[]textField) {
+	for _, element := range elements {
+		for _, text := range []string{element.Label, element.Description} {
+			if text != "" {
+				*fields = append(*fields, textField{text, element.Pos.Line})
+			}
+		}
+	}
+	for _, boundary := range boundaries {
+		if boundary.Label != "" {
+			*fields = append(*fields, textField{boundary.Label, boundary.Pos.Line})
+		}
+		collectC4Text(boundary.Elements, boundary.Boundaries, fields)
+	}
+}
+
+// textColumn returns the 1-indexed column at which text starts on the given
+// source line, or 1 if it can't be found.
+func textColumn(lines []string, line int, text string) int {
+	if line < 1 || line > len(lines) {
+		return 1
+	}
+	i := strings.Index(lines[line-1], text)
+	if i < 0 {
+		return 1
+	}
+	return utf8.RuneCountInString(lines[line-1][:i]) + 1
+}
+
+// lineContaining returns the 1-indexed line of the first source line containing
+// text, or 1 if there is none.
+func lineContaining(lines []string, text string) int {
+	for i, line := range lines {
+		if strings.Contains(line, text) {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// wordColumn returns the 1-indexed column of the first whole-word occurrence
+// of word on the given source line, or 1 if it can't be found.
+func wordColumn(lines []string, line int, word string) int {
+	if line < 1 || line > len(lines) {
+		return 1
+	}
+	text := lines[line-1]
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return 1
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !unicode.IsLetter(before) && !unicode.IsLetter(after) {
+			return utf8.RuneCountInString(text[:start]) + 1
+		}
+		offset = end
+	}
+}
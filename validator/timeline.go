@@ -2,6 +2,8 @@ package validator
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/sammcj/mermaid-check/ast"
 )
@@ -20,7 +22,7 @@ func ValidateTimeline(diagram *ast.TimelineDiagram, strict bool) []*ValidationEr
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -30,14 +32,13 @@ func TimelineDefaultRules() []TimelineRule {
 	return []TimelineRule{
 		&PeriodsHaveEventsRule{},
 		&NoEmptyPeriodsRule{},
+		&NoEmptySectionsRule{},
 	}
 }
 
 // TimelineStrictRules returns strict validation rules for timeline diagrams.
 func TimelineStrictRules() []TimelineRule {
-	rules := TimelineDefaultRules()
-	// Add strict-only rules here if needed
-	return rules
+	return append(TimelineDefaultRules(), &ChronologicalPeriodsRule{})
 }
 
 // PeriodsHaveEventsRule checks that all periods have at least one event.
@@ -98,3 +99,76 @@ func (r *NoEmptyPeriodsRule) Validate(diagram *ast.TimelineDiagram) []*Validatio
 
 	return errors
 }
+
+// NoEmptySectionsRule checks that every section contains at least one period.
+type NoEmptySectionsRule struct{}
+
+// Validate checks that sections have periods.
+func (r *NoEmptySectionsRule) Validate(diagram *ast.TimelineDiagram) []*ValidationError {
+	var errors []*ValidationError
+
+	for _, section := range diagram.Sections {
+		if len(section.Periods) == 0 {
+			errors = append(errors, &ValidationError{
+				Line:     section.Pos.Line,
+				Column:   section.Pos.Column,
+				Message:  fmt.Sprintf("section %q has no time periods", section.Name),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+
+	return errors
+}
+
+// timelineDatePattern matches time periods that can be ordered: a number, a
+// decade such as "1940s", or an ISO-style date ("2024", "2024-03", "2024-03-15").
+var timelineDatePattern = regexp.MustCompile(`^(\d+)(?:s|-(\d{1,2})(?:-(\d{1,2}))?)?$`)
+
+// ChronologicalPeriodsRule checks that time periods which look like years or
+// dates are listed in ascending order. Periods that are not dates (such as
+// "Early Stage") are ignored.
+type ChronologicalPeriodsRule struct{}
+
+// Validate checks that dated periods do not go backwards in time.
+func (r *ChronologicalPeriodsRule) Validate(diagram *ast.TimelineDiagram) []*ValidationError {
+	var errors []*ValidationError
+	var previous *ast.TimelinePeriod
+	var previousValue int
+
+	for _, section := range diagram.Sections {
+		for i := range section.Periods {
+			period := &section.Periods[i]
+			value, ok := timelinePeriodValue(period.TimePeriod)
+			if !ok {
+				continue
+			}
+			if previous != nil && value < previousValue {
+				errors = append(errors, &ValidationError{
+					Line:     period.Pos.Line,
+					Column:   period.Pos.Column,
+					Message:  fmt.Sprintf("time period %q is earlier than the preceding period %q", period.TimePeriod, previous.TimePeriod),
+					Severity: SeverityWarning,
+				})
+			}
+			previous, previousValue = period, value
+		}
+	}
+
+	return errors
+}
+
+// timelinePeriodValue converts a dated time period into a sortable value.
+func timelinePeriodValue(period string) (int, bool) {
+	matches := timelineDatePattern.FindStringSubmatch(period)
+	if matches == nil {
+		return 0, false
+	}
+	value, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	month, _ := strconv.Atoi(matches[2])
+	day, _ := strconv.Atoi(matches[3])
+	return value*10000 + month*100 + day, true
+}
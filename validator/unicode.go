@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sammcj/mermaid-check/ast"
+)
+
+// confusable describes a character ConfusableCharacters reports, and what it
+// is replaced with when fixed ("" removes it).
+type confusable struct {
+	name        string
+	replacement string
+}
+
+// confusables are characters that are invisible or look like ASCII
+// punctuation, which chat tools and word processors substitute when diagrams
+// are pasted through them. Zero-width joiners and non-joiners are left alone:
+// joiners are part of many emoji, and non-joiners are needed to write Persian
+// and other scripts.
+var confusables = map[rune]confusable{
+	'\u00a0': {"non-breaking space", " "},
+	'\u2007': {"figure space", " "},
+	'\u202f': {"narrow non-breaking space", " "},
+	'\u00ad': {"soft hyphen", ""},
+	'\u200b': {"zero-width space", ""},
+	'\u2060': {"word joiner", ""},
+	'\ufeff': {"byte order mark", ""},
+	'\u201c': {"smart quote", `"`},
+	'\u201d': {"smart quote", `"`},
+	'\u2018': {"smart quote", "'"},
+	'\u2019': {"smart quote", "'"},
+}
+
+// arrowDashes are dashes that word processors substitute for "--", which
+// break arrows such as "-->" when they appear in one.
+var arrowDashes = map[rune]string{
+	'\u2013': "en dash",
+	'\u2014': "em dash",
+}
+
+// ConfusableCharacters reports non-breaking spaces, zero-width characters,
+// smart quotes and en or em dashes in arrows, with a fix that replaces them
+// with their ASCII equivalents. It applies to every diagram type. As these
+// characters are also legitimate typography in labels and messages, it is
+// only in the strict rule set.
+type ConfusableCharacters struct{}
+
+// Name returns the name of this validation rule.
+func (r *ConfusableCharacters) Name() string { return "confusable-characters" }
+
+// ValidateDiagram reports confusable characters in the source of diagram.
+func (r *ConfusableCharacters) ValidateDiagram(diagram ast.Diagram) []ValidationError {
+	var errors []ValidationError
+	for i, line := range strings.Split(diagramSource(diagram), "\n") {
+		errors = append(errors, confusablesInLine(line, i+1)...)
+	}
+	return errors
+}
+
+// confusablesInLine reports the confusable characters in line, which is line
+// number lineNum of the diagram. Every error carries the same fix, which
+// corrects the whole line.
+func confusablesInLine(line string, lineNum int) []ValidationError {
+	runes := []rune(line)
+	var errors []ValidationError
+	var fixed strings.Builder
+	for i, r := range runes {
+		if c, ok := confusables[r]; ok {
+			errors = append(errors, ValidationError{
+				Line:     lineNum,
+				Column:   i + 1,
+				Message:  fmt.Sprintf("%s (U+%04X) should be %s", c.name, r, describeReplacement(c.replacement)),
+				Severity: SeverityWarning,
+			})
+			fixed.WriteString(c.replacement)
+			continue
+		}
+		if name, ok := arrowDashes[r]; ok && inArrow(runes, i) {
+			errors = append(errors, ValidationError{
+				Line:     lineNum,
+				Column:   i + 1,
+				Message:  fmt.Sprintf("%s (U+%04X) in arrow should be '--'", name, r),
+				Severity: SeverityError,
+			})
+			fixed.WriteString("--")
+			continue
+		}
+		fixed.WriteRune(r)
+	}
+
+	if len(errors) > 0 {
+		fix := &Fix{Line: lineNum, Replacement: fixed.String()}
+		for i := range errors {
+			errors[i].Fix = fix
+		}
+	}
+	return errors
+}
+
+// describeReplacement describes the replacement for a confusable character.
+func describeReplacement(replacement string) string {
+	switch replacement {
+	case "":
+		return "removed"
+	case " ":
+		return "a plain space"
+	case `"`:
+		return `a straight double quote (")`
+	case "'":
+		return "a straight single quote (')"
+	default:
+		return fmt.Sprintf("'%s'", replacement)
+	}
+}
+
+// inArrow reports whether the dash at runes[i] is part of an arrow: next to a
+// hyphen or an arrowhead.
+func inArrow(runes []rune, i int) bool {
+	if i+1 < len(runes) && (runes[i+1] == '-' || runes[i+1] == '>') {
+		return true
+	}
+	return i > 0 && (runes[i-1] == '-' || runes[i-1] == '<')
+}
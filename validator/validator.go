@@ -2,7 +2,9 @@
 package validator
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/sammcj/mermaid-check/ast"
@@ -33,18 +35,67 @@ func (s Severity) String() string {
 	}
 }
 
+// ParseSeverity converts "error", "warning" or "info" to a Severity.
+func ParseSeverity(name string) (Severity, error) {
+	for _, severity := range []Severity{SeverityError, SeverityWarning, SeverityInfo} {
+		if severity.String() == name {
+			return severity, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q: expected error, warning or info", name)
+}
+
 // ValidationError represents a validation error with position and context.
 type ValidationError struct {
-	Line     int      // Line number (1-indexed)
-	Column   int      // Column number (1-indexed)
-	Message  string   // Error message
-	Severity Severity // Error severity
+	Line       int      // Line number (1-indexed)
+	Column     int      // Column number (1-indexed)
+	Message    string   // Error message
+	Severity   Severity // Error severity
+	Rule       string   // Name of the rule that reported the error (see RuleName)
+	Fix        *Fix     // Automatic correction, if the rule can offer one (see ApplyFixes)
+	Suggestion string   // "Did you mean" hint for correcting the error by hand, if any
 }
 
 func (v *ValidationError) Error() string {
 	return fmt.Sprintf("line %d: %s: %s", v.Line, v.Severity, v.Message)
 }
 
+// SortErrors sorts errors by line, column, rule name and then message, so
+// that rules that collect findings in maps still report them in the same
+// order on every run.
+func SortErrors(errors []ValidationError) {
+	slices.SortStableFunc(errors, func(a, b ValidationError) int {
+		return cmp.Or(
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column),
+			cmp.Compare(a.Rule, b.Rule),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+}
+
+// Deduplicate returns errors without those repeating an earlier error's rule,
+// position and message, as overlapping checks can report the same problem
+// more than once.
+func Deduplicate(errors []ValidationError) []ValidationError {
+	type key struct {
+		rule         string
+		line, column int
+		message      string
+	}
+	seen := make(map[key]bool, len(errors))
+	kept := errors[:0:0]
+	for _, err := range errors {
+		k := key{err.Rule, err.Line, err.Column, err.Message}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		kept = append(kept, err)
+	}
+	return kept
+}
+
 // Rule represents a validation rule that can be applied to a flowchart.
 type Rule interface {
 	// Name returns the name of the rule.
@@ -81,7 +132,7 @@ func NewSequence(rules ...SequenceRule) *Validator {
 func (v *Validator) Validate(flowchart *ast.Flowchart) []ValidationError {
 	var errors []ValidationError
 	for _, rule := range v.rules {
-		errors = append(errors, rule.Validate(flowchart)...)
+		errors = append(errors, withRule(rule.Validate(flowchart), rule)...)
 	}
 	return errors
 }
@@ -94,25 +145,25 @@ func (v *Validator) ValidateDiagram(diagram ast.Diagram) []ValidationError {
 	case *ast.SequenceDiagram:
 		var errors []ValidationError
 		for _, rule := range v.sequenceRules {
-			errors = append(errors, rule.ValidateSequence(d)...)
+			errors = append(errors, withRule(rule.ValidateSequence(d), rule)...)
 		}
 		return errors
 	case *ast.ClassDiagram:
 		var errors []ValidationError
 		for _, rule := range v.classRules {
-			errors = append(errors, rule.ValidateClass(d)...)
+			errors = append(errors, withRule(rule.ValidateClass(d), rule)...)
 		}
 		return errors
 	case *ast.StateDiagram:
 		var errors []ValidationError
 		for _, rule := range v.stateRules {
-			errors = append(errors, rule.ValidateState(d)...)
+			errors = append(errors, withRule(rule.ValidateState(d), rule)...)
 		}
 		return errors
 	case *ast.GenericDiagram:
 		var errors []ValidationError
 		for _, rule := range v.genericRules {
-			errors = append(errors, rule.ValidateGeneric(d)...)
+			errors = append(errors, withRule(rule.ValidateGeneric(d), rule)...)
 		}
 		return errors
 	default:
@@ -131,22 +182,70 @@ type ValidDirection struct{}
 // Name returns the name of this validation rule.
 func (r *ValidDirection) Name() string { return "valid-direction" }
 
-// Validate checks if the flowchart direction is one of the valid values.
+// validDirections lists the layout directions accepted by flowcharts and subgraphs.
+var validDirections = map[string]bool{
+	"TB": true, "TD": true, "BT": true, "RL": true, "LR": true,
+}
+
+// Validate checks if the flowchart direction, and any subgraph directions, are
+// valid values. A flowchart without a direction uses the default (TB).
 func (r *ValidDirection) Validate(flowchart *ast.Flowchart) []ValidationError {
-	validDirections := map[string]bool{
-		"TB": true, "TD": true, "BT": true, "RL": true, "LR": true,
-	}
+	var errors []ValidationError
 
-	if !validDirections[flowchart.Direction] {
-		return []ValidationError{{
+	if flowchart.Direction != "" && !validDirections[flowchart.Direction] {
+		errors = append(errors, ValidationError{
 			Line:     flowchart.Pos.Line,
 			Column:   flowchart.Pos.Column,
 			Message:  fmt.Sprintf("invalid direction '%s', must be one of: TB, TD, BT, RL, LR", flowchart.Direction),
 			Severity: SeverityError,
-		}}
+		})
+	}
+
+	r.checkSubgraphs(flowchart.Statements, &errors)
+
+	return errors
+}
+
+func (r *ValidDirection) checkSubgraphs(statements []ast.Statement, errors *[]ValidationError) {
+	for _, stmt := range statements {
+		if s, ok := stmt.(*ast.Subgraph); ok {
+			if s.Direction != "" && !validDirections[s.Direction] {
+				*errors = append(*errors, ValidationError{
+					Line:     s.Pos.Line,
+					Column:   s.Pos.Column,
+					Message:  fmt.Sprintf("invalid direction '%s' in subgraph '%s', must be one of: TB, TD, BT, RL, LR", s.Direction, s.Title),
+					Severity: SeverityError,
+				})
+			}
+			r.checkSubgraphs(s.Statements, errors)
+		}
+	}
+}
+
+// ExplicitDirection warns about flowcharts whose header leaves out the layout
+// direction, so readers need not know that it defaults to top to bottom. The
+// fix adds the default, TB, to the header.
+type ExplicitDirection struct{}
+
+// Name returns the name of this validation rule.
+func (r *ExplicitDirection) Name() string { return "explicit-direction" }
+
+// Validate checks that the flowchart header gives a direction.
+func (r *ExplicitDirection) Validate(flowchart *ast.Flowchart) []ValidationError {
+	if flowchart.Direction != "" {
+		return nil
 	}
 
-	return nil
+	err := ValidationError{
+		Line:     flowchart.Pos.Line,
+		Column:   flowchart.Pos.Column,
+		Message:  fmt.Sprintf("'%s' has no direction; add one such as '%s TB' (the default) or '%s LR'", flowchart.Type, flowchart.Type, flowchart.Type),
+		Severity: SeverityWarning,
+	}
+	if header := sourceLine(flowchart.Source, flowchart.Pos.Line); strings.TrimSpace(header) == flowchart.Type {
+		err.Fix = &Fix{Line: flowchart.Pos.Line, Replacement: strings.TrimRight(header, " \t") + " TB"}
+	}
+	return []ValidationError{err}
 }
 
 // NoUndefinedNodes checks that all referenced nodes are defined.
@@ -277,12 +376,223 @@ func (r *NoDuplicateNodeIDs) checkDuplicates(statements []ast.Statement, positio
 	}
 }
 
+// ValidSubgraphReferences checks that links and class assignments aimed at
+// subgraphs use the subgraph ID rather than its title, and that class
+// assignments only target nodes or subgraphs that exist.
+type ValidSubgraphReferences struct{}
+
+// Name returns the name of this validation rule.
+func (r *ValidSubgraphReferences) Name() string { return "valid-subgraph-references" }
+
+// Validate checks link endpoints and class assignment targets against known subgraph IDs.
+func (r *ValidSubgraphReferences) Validate(flowchart *ast.Flowchart) []ValidationError {
+	subgraphIDs := make(map[string]bool)
+	titleToID := make(map[string]string)
+	nodeIDs := make(map[string]bool)
+	var errors []ValidationError
+
+	r.collect(flowchart.Statements, subgraphIDs, titleToID, nodeIDs)
+	r.checkReferences(flowchart.Statements, subgraphIDs, titleToID, nodeIDs, &errors)
+
+	return errors
+}
+
+func (r *ValidSubgraphReferences) collect(statements []ast.Statement, subgraphIDs map[string]bool, titleToID map[string]string, nodeIDs map[string]bool) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			nodeIDs[s.ID] = true
+		case *ast.Link:
+			nodeIDs[s.From] = true
+			nodeIDs[s.To] = true
+		case *ast.Subgraph:
+			if s.ID != "" {
+				subgraphIDs[s.ID] = true
+				if s.Title != s.ID {
+					titleToID[s.Title] = s.ID
+				}
+			}
+			r.collect(s.Statements, subgraphIDs, titleToID, nodeIDs)
+		}
+	}
+}
+
+func (r *ValidSubgraphReferences) checkReferences(statements []ast.Statement, subgraphIDs map[string]bool, titleToID map[string]string, nodeIDs map[string]bool, errors *[]ValidationError) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.Link:
+			for _, endpoint := range []string{s.From, s.To} {
+				if id, ok := titleToID[endpoint]; ok && !subgraphIDs[endpoint] {
+					*errors = append(*errors, ValidationError{
+						Line:     s.Pos.Line,
+						Column:   s.Pos.Column,
+						Message:  fmt.Sprintf("link references subgraph title '%s', use the subgraph ID '%s' instead", endpoint, id),
+						Severity: SeverityWarning,
+					})
+				}
+			}
+		case *ast.ClassAssignment:
+			for i, id := range s.NodeIDs {
+				if nodeIDs[id] || subgraphIDs[id] {
+					continue
+				}
+				message := fmt.Sprintf("class assignment references undefined node or subgraph '%s'", id)
+				if subgraphID, ok := titleToID[id]; ok {
+					message = fmt.Sprintf("class assignment references subgraph title '%s', use the subgraph ID '%s' instead", id, subgraphID)
+				}
+				pos := s.NodePosition(i)
+				*errors = append(*errors, ValidationError{
+					Line:     pos.Line,
+					Column:   pos.Column,
+					Message:  message,
+					Severity: SeverityWarning,
+				})
+			}
+		case *ast.Subgraph:
+			r.checkReferences(s.Statements, subgraphIDs, titleToID, nodeIDs, errors)
+		}
+	}
+}
+
+// ValidClassDefinitions checks that class assignments, made by a class
+// statement or the `:::className` shorthand, use a classDef that exists.
+// Assignment targets are checked by ValidSubgraphReferences.
+type ValidClassDefinitions struct{}
+
+// Name returns the name of this validation rule.
+func (r *ValidClassDefinitions) Name() string { return "valid-class-definitions" }
+
+// Validate checks class assignments against classDefs.
+func (r *ValidClassDefinitions) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var classDefs []*ast.ClassDef
+	var assignments []*ast.ClassAssignment
+	collectClasses(flowchart.Statements, &classDefs, &assignments)
+
+	defined := make(map[string]bool, len(classDefs))
+	for _, def := range classDefs {
+		defined[def.Name] = true
+	}
+
+	var errors []ValidationError
+	for _, assignment := range assignments {
+		if !defined[assignment.ClassName] {
+			errors = append(errors, ValidationError{
+				Line:     assignment.Pos.Line,
+				Column:   assignment.Pos.Column,
+				Message:  fmt.Sprintf("class assignment references undefined classDef '%s'", assignment.ClassName),
+				Severity: SeverityWarning,
+			})
+		}
+	}
+	return errors
+}
+
+// UnusedClassDefinitions warns about classDefs that no class statement or
+// `:::className` shorthand uses. The special "default" classDef applies to all
+// nodes and is never reported.
+type UnusedClassDefinitions struct{}
+
+// Name returns the name of this validation rule.
+func (r *UnusedClassDefinitions) Name() string { return "unused-class-definitions" }
+
+// Validate reports classDefs that are never assigned.
+func (r *UnusedClassDefinitions) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var classDefs []*ast.ClassDef
+	var assignments []*ast.ClassAssignment
+	collectClasses(flowchart.Statements, &classDefs, &assignments)
+
+	used := make(map[string]bool, len(assignments))
+	for _, assignment := range assignments {
+		used[assignment.ClassName] = true
+	}
+
+	var errors []ValidationError
+	for _, def := range classDefs {
+		if def.Name == "default" || used[def.Name] {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			Line:     def.Pos.Line,
+			Column:   def.Pos.Column,
+			Message:  fmt.Sprintf("classDef '%s' is never used", def.Name),
+			Severity: SeverityWarning,
+		})
+	}
+	return errors
+}
+
+// collectClasses gathers the classDefs and class assignments in statements,
+// including those inside subgraphs. Assignments include the `:::className`
+// shorthand, which the parser records as class assignments.
+func collectClasses(statements []ast.Statement, classDefs *[]*ast.ClassDef, assignments *[]*ast.ClassAssignment) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.ClassDef:
+			*classDefs = append(*classDefs, s)
+		case *ast.ClassAssignment:
+			*assignments = append(*assignments, s)
+		case *ast.Subgraph:
+			collectClasses(s.Statements, classDefs, assignments)
+		}
+	}
+}
+
+// UniqueNodeLabels warns when different node IDs share the same label text.
+// Such nodes render identically, which usually means one of them should reuse
+// the other's ID.
+type UniqueNodeLabels struct{}
+
+// Name returns the name of this validation rule.
+func (r *UniqueNodeLabels) Name() string { return "unique-node-labels" }
+
+// Validate checks that each non-empty label belongs to a single node ID.
+func (r *UniqueNodeLabels) Validate(flowchart *ast.Flowchart) []ValidationError {
+	var errors []ValidationError
+	r.checkLabels(flowchart.Statements, make(map[string]*ast.NodeDef), make(map[string]bool), &errors)
+	return errors
+}
+
+func (r *UniqueNodeLabels) checkLabels(statements []ast.Statement, owners map[string]*ast.NodeDef, reported map[string]bool, errors *[]ValidationError) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *ast.NodeDef:
+			label := strings.TrimSpace(s.Label)
+			if label == "" {
+				continue
+			}
+			owner, exists := owners[label]
+			if !exists {
+				owners[label] = s
+				continue
+			}
+			if owner.ID == s.ID || reported[s.ID] {
+				continue
+			}
+			reported[s.ID] = true
+			*errors = append(*errors, ValidationError{
+				Line:     s.Pos.Line,
+				Column:   s.Pos.Column,
+				Message:  fmt.Sprintf("node '%s' has the same label '%s' as node '%s' (line %d); consider reusing '%s'", s.ID, label, owner.ID, owner.Pos.Line, owner.ID),
+				Severity: SeverityWarning,
+			})
+		case *ast.Subgraph:
+			r.checkLabels(s.Statements, owners, reported, errors)
+		}
+	}
+}
+
 // DefaultRules returns the default set of validation rules.
 func DefaultRules() []Rule {
 	return []Rule{
 		&ValidDirection{},
 		&NoUndefinedNodes{},
 		&NoDuplicateNodeIDs{},
+		&ValidSubgraphReferences{},
+		&SubgraphMembership{},
+		&ValidClassDefinitions{},
+		&NoDuplicateLinks{},
+		&UnescapedQuotes{},
+		&UnknownEntities{},
 	}
 }
 
@@ -292,6 +602,20 @@ func StrictRules() []Rule {
 		&ValidDirection{},
 		&NoUndefinedNodes{},
 		&NoDuplicateNodeIDs{},
+		&ValidSubgraphReferences{},
+		&SubgraphMembership{},
+		&ValidClassDefinitions{},
+		&NoDuplicateLinks{},
+		&UnescapedQuotes{},
+		&UnknownEntities{},
+		&ExplicitDirection{},
+		&MaxLinkLength{},
 		&NoParenthesesInLabels{},
+		&SecureInteractions{},
+		&LabelLength{},
+		&ColourContrast{},
+		&UniqueNodeLabels{},
+		&MixedDirectionText{},
+		&UnusedClassDefinitions{},
 	}
 }
@@ -20,7 +20,7 @@ func ValidateXYChart(diagram *ast.XYChartDiagram, strict bool) []*ValidationErro
 
 	var errors []*ValidationError
 	for _, rule := range rules {
-		errors = append(errors, rule.Validate(diagram)...)
+		errors = append(errors, withRulePointers(rule.Validate(diagram), rule)...)
 	}
 	return errors
 }
@@ -33,6 +33,8 @@ func XYChartDefaultRules() []XYChartRule {
 		&XYChartMinimumSeriesRule{},
 		&XYChartValidSeriesLengthRule{},
 		&XYChartValidOrientationRule{},
+		&XYChartUniqueSeriesNamesRule{},
+		&XYChartValuesInRangeRule{},
 	}
 }
 
@@ -163,3 +165,63 @@ func (r *XYChartValidOrientationRule) Validate(diagram *ast.XYChartDiagram) []*V
 	}
 	return nil
 }
+
+// XYChartUniqueSeriesNamesRule checks that named series have distinct names.
+type XYChartUniqueSeriesNamesRule struct{}
+
+// Validate checks that no two series share a name. Unnamed series are ignored.
+func (r *XYChartUniqueSeriesNamesRule) Validate(diagram *ast.XYChartDiagram) []*ValidationError {
+	var errors []*ValidationError
+	seen := make(map[string]int)
+
+	for _, series := range diagram.Series {
+		if series.Name == "" {
+			continue
+		}
+		if line, ok := seen[series.Name]; ok {
+			errors = append(errors, &ValidationError{
+				Line:     series.Pos.Line,
+				Column:   series.Pos.Column,
+				Message:  fmt.Sprintf("duplicate series name %q (first defined at line %d)", series.Name, line),
+				Severity: SeverityWarning,
+			})
+			continue
+		}
+		seen[series.Name] = series.Pos.Line
+	}
+
+	return errors
+}
+
+// XYChartValuesInRangeRule checks that series values fall within the declared
+// range of a numeric y-axis, as values outside it are clipped when rendered.
+type XYChartValuesInRangeRule struct{}
+
+// Validate checks each series value against the y-axis minimum and maximum.
+func (r *XYChartValuesInRangeRule) Validate(diagram *ast.XYChartDiagram) []*ValidationError {
+	axis := diagram.YAxis
+	if !axis.IsNumeric {
+		return nil
+	}
+
+	low, high := min(axis.Min, axis.Max), max(axis.Min, axis.Max)
+	var errors []*ValidationError
+	for i, series := range diagram.Series {
+		name := fmt.Sprintf("series %d", i+1)
+		if series.Name != "" {
+			name = fmt.Sprintf("series %q", series.Name)
+		}
+		for _, value := range series.Values {
+			if value < low || value > high {
+				errors = append(errors, &ValidationError{
+					Line:     series.Pos.Line,
+					Column:   series.Pos.Column,
+					Message:  fmt.Sprintf("%s value %g is outside the y-axis range %g to %g", name, value, axis.Min, axis.Max),
+					Severity: SeverityWarning,
+				})
+			}
+		}
+	}
+
+	return errors
+}